@@ -0,0 +1,272 @@
+// composer-cloud submits a compose request to osbuild-composer's cloud
+// API, waits for it to finish and prints where the images were uploaded to.
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+
+	"github.com/osbuild/osbuild-composer/pkg/cloudapi/client"
+)
+
+// loadComposeRequest reads a compose request in JSON or YAML format. YAML is
+// converted to JSON first, so that the field names are the same as in the
+// API. Unknown fields are rejected to catch typos before submitting.
+func loadComposeRequest(r io.Reader) (*client.ComposeRequest, error) {
+	raw, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// YAML is a superset of JSON, which makes this work for both
+	var value interface{}
+	err = yaml.Unmarshal(raw, &value)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing compose request: %v", err)
+	}
+	raw, err = json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("error converting compose request to JSON: %v", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	var request client.ComposeRequest
+	err = decoder.Decode(&request)
+	if err != nil {
+		return nil, fmt.Errorf("invalid compose request: %v", err)
+	}
+	return &request, nil
+}
+
+func createTLSConfig(caCertFile, clientCertFile, clientKeyFile string) (*tls.Config, error) {
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if caCertFile != "" {
+		caCertPEM, err := ioutil.ReadFile(caCertFile)
+		if err != nil {
+			return nil, err
+		}
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(caCertPEM) {
+			return nil, errors.New("failed to append root certificate")
+		}
+	}
+
+	if clientCertFile != "" || clientKeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(clientCertFile, clientKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
+
+func writeJSON(path string, value interface{}) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(data, '\n'), 0600)
+}
+
+// downloadArtifacts saves the logs and the manifests of a compose to
+// directory. Only koji composes have them, so their absence isn't an error.
+func downloadArtifacts(ctx context.Context, c *client.ClientWithResponses, id, directory string) error {
+	err := os.MkdirAll(directory, 0700)
+	if err != nil {
+		return err
+	}
+
+	logs, err := c.ComposeLogs(ctx, id)
+	if err != nil {
+		logrus.Warnf("Could not download the logs of compose %s: %v", id, err)
+	} else {
+		path := filepath.Join(directory, "logs.json")
+		err = writeJSON(path, logs)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("logs saved to %s\n", path)
+	}
+
+	manifests, err := c.ComposeManifests(ctx, id)
+	if err != nil {
+		logrus.Warnf("Could not download the manifests of compose %s: %v", id, err)
+		return nil
+	}
+	for i, manifest := range manifests.Manifests {
+		path := filepath.Join(directory, fmt.Sprintf("manifest-%d.json", i))
+		err = writeJSON(path, manifest)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("manifest saved to %s\n", path)
+	}
+
+	return nil
+}
+
+// printStatus prints the status of each image of a compose and, for those
+// which were uploaded, the upload target and its results.
+func printStatus(w io.Writer, status *client.ComposeStatus) {
+	fmt.Fprintf(w, "compose %s: %s\n", status.Id, status.Status)
+
+	if status.KojiStatus != nil {
+		if status.KojiStatus.BuildId != nil {
+			fmt.Fprintf(w, "koji build: %d\n", *status.KojiStatus.BuildId)
+		}
+		if status.KojiStatus.Failure != nil {
+			fmt.Fprintf(w, "koji failure: %s\n", *status.KojiStatus.Failure)
+		}
+	}
+
+	imageStatuses := []client.ImageStatus{status.ImageStatus}
+	if status.ImageStatuses != nil {
+		imageStatuses = *status.ImageStatuses
+	}
+	for i, imageStatus := range imageStatuses {
+		fmt.Fprintf(w, "image %d: %s\n", i, imageStatus.Status)
+		if imageStatus.Error != nil {
+			fmt.Fprintf(w, "  error: %s\n", imageStatus.Error.Reason)
+			if imageStatus.Error.Details != nil {
+				details, err := json.Marshal(imageStatus.Error.Details)
+				if err == nil {
+					fmt.Fprintf(w, "  details: %s\n", details)
+				}
+			}
+		}
+		if upload := imageStatus.UploadStatus; upload != nil {
+			fmt.Fprintf(w, "  upload to %s: %s\n", upload.Type, upload.Status)
+			options, err := json.Marshal(upload.Options)
+			if err == nil {
+				fmt.Fprintf(w, "  upload options: %s\n", options)
+			}
+		}
+	}
+}
+
+func main() {
+	var serverURL, offlineTokenPath, oAuthURL, clientID string
+	var caCert, clientCert, clientKey string
+	var outputDirectory string
+	var wait bool
+	var interval, timeout time.Duration
+	flag.StringVar(&serverURL, "url", "https://localhost/api/image-builder-composer/v2", "URL of the cloud API")
+	flag.StringVar(&offlineTokenPath, "offline-token", "", "file containing an offline token to authenticate with")
+	flag.StringVar(&oAuthURL, "oauth-url", "", "URL to refresh access tokens at, required with -offline-token")
+	flag.StringVar(&clientID, "client-id", client.DefaultOAuthClientID, "OAuth client id to refresh access tokens with")
+	flag.StringVar(&caCert, "ca", "", "CA certificate to verify the server with")
+	flag.StringVar(&clientCert, "cert", "", "client certificate to authenticate with")
+	flag.StringVar(&clientKey, "key", "", "key of the client certificate")
+	flag.BoolVar(&wait, "wait", true, "wait for the compose to finish")
+	flag.DurationVar(&interval, "interval", 30*time.Second, "how often to poll the status of the compose")
+	flag.DurationVar(&timeout, "timeout", 0, "how long to wait for the compose to finish, 0 to wait forever")
+	flag.StringVar(&outputDirectory, "output", "", "directory to download the logs and manifests of the compose to")
+
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [options] compose-request.(json|yaml)\n\n", os.Args[0])
+		fmt.Fprintf(flag.CommandLine.Output(), "Submits a compose request and prints its results. Use - to read the request from stdin.\n\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	input := os.Stdin
+	if path := flag.Arg(0); path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			logrus.Fatalf("Could not open compose request: %v", err)
+		}
+		defer file.Close()
+		input = file
+	}
+	request, err := loadComposeRequest(input)
+	if err != nil {
+		logrus.Fatal(err)
+	}
+
+	tlsConfig, err := createTLSConfig(caCert, clientCert, clientKey)
+	if err != nil {
+		logrus.Fatalf("Error creating TLS config: %v", err)
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	options := []client.ClientOption{
+		client.WithHTTPClient(&http.Client{Transport: transport}),
+	}
+
+	if offlineTokenPath != "" {
+		t, err := ioutil.ReadFile(offlineTokenPath)
+		if err != nil {
+			logrus.Fatalf("Could not read offline token: %v", err)
+		}
+		if oAuthURL == "" {
+			logrus.Fatal("OAuth URL should be specified together with the offline token")
+		}
+		options = append(options, client.WithOfflineToken(strings.TrimSpace(string(t)), oAuthURL, clientID))
+	}
+
+	c, err := client.NewClientWithResponses(serverURL, options...)
+	if err != nil {
+		logrus.Fatalf("Error creating cloud API client: %v", err)
+	}
+
+	ctx := context.Background()
+	id, err := c.Compose(ctx, *request)
+	if err != nil {
+		logrus.Fatalf("Error submitting compose request: %v", err)
+	}
+	fmt.Printf("compose %s submitted\n", id)
+
+	if !wait {
+		return
+	}
+
+	waitCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	status, err := c.WaitForCompose(waitCtx, id, interval)
+	if err != nil {
+		logrus.Fatalf("Error waiting for compose %s: %v", id, err)
+	}
+
+	if outputDirectory != "" {
+		err = downloadArtifacts(ctx, c, id, outputDirectory)
+		if err != nil {
+			logrus.Fatalf("Error downloading logs and manifests: %v", err)
+		}
+	}
+
+	printStatus(os.Stdout, status)
+	if status.Status != client.ComposeStatusValueSuccess {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/osbuild-composer/pkg/cloudapi/client"
+)
+
+func TestLoadComposeRequest(t *testing.T) {
+	requests := map[string]string{
+		"json": `{
+			"distribution": "rhel-86",
+			"image_request": {
+				"architecture": "x86_64",
+				"image_type": "aws",
+				"repositories": [{"baseurl": "https://example.com/repo", "rhsm": false}],
+				"upload_options": {"region": "eu-central-1"}
+			}
+		}`,
+		"yaml": `
+distribution: rhel-86
+image_request:
+  architecture: x86_64
+  image_type: aws
+  repositories:
+    - baseurl: https://example.com/repo
+      rhsm: false
+  upload_options:
+    region: eu-central-1
+`,
+	}
+
+	for format, input := range requests {
+		t.Run(format, func(t *testing.T) {
+			request, err := loadComposeRequest(strings.NewReader(input))
+			require.NoError(t, err)
+			require.Equal(t, "rhel-86", request.Distribution)
+			require.NotNil(t, request.ImageRequest)
+			require.Equal(t, client.ImageTypes("aws"), request.ImageRequest.ImageType)
+			require.Equal(t, "https://example.com/repo", *request.ImageRequest.Repositories[0].Baseurl)
+			require.Equal(t, map[string]interface{}{"region": "eu-central-1"}, *request.ImageRequest.UploadOptions)
+		})
+	}
+
+	_, err := loadComposeRequest(strings.NewReader(`distribution: rhel-86
+image_requests: []
+unknown: field`))
+	require.Error(t, err)
+
+	_, err = loadComposeRequest(strings.NewReader(`{"distribution": `))
+	require.Error(t, err)
+}
+
+func TestPrintStatus(t *testing.T) {
+	var buf bytes.Buffer
+	buildID := 42
+	printStatus(&buf, &client.ComposeStatus{
+		ObjectReference: client.ObjectReference{Id: "id"},
+		Status:          client.ComposeStatusValueSuccess,
+		ImageStatus: client.ImageStatus{
+			Status: client.ImageStatusValueSuccess,
+			UploadStatus: &client.UploadStatus{
+				Type:    "aws",
+				Status:  client.UploadStatusValueSuccess,
+				Options: map[string]interface{}{"ami": "ami-1", "region": "eu-central-1"},
+			},
+		},
+		KojiStatus: &client.KojiStatus{BuildId: &buildID},
+	})
+	require.Equal(t, `compose id: success
+koji build: 42
+image 0: success
+  upload to aws: success
+  upload options: {"ami":"ami-1","region":"eu-central-1"}
+`, buf.String())
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"errors"
@@ -10,7 +11,10 @@ import (
 	"net"
 	"net/http"
 	"os"
+	"os/signal"
 	"path"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -20,11 +24,15 @@ import (
 	"github.com/osbuild/osbuild-composer/internal/auth"
 	"github.com/osbuild/osbuild-composer/internal/cloudapi"
 	v2 "github.com/osbuild/osbuild-composer/internal/cloudapi/v2"
+	"github.com/osbuild/osbuild-composer/internal/common"
 	"github.com/osbuild/osbuild-composer/internal/distroregistry"
 	"github.com/osbuild/osbuild-composer/internal/jobqueue"
 	"github.com/osbuild/osbuild-composer/internal/jobqueue/dbjobqueue"
 	"github.com/osbuild/osbuild-composer/internal/jobqueue/fsjobqueue"
 	"github.com/osbuild/osbuild-composer/internal/kojiapi"
+	"github.com/osbuild/osbuild-composer/internal/notification"
+	"github.com/osbuild/osbuild-composer/internal/notification/dbstore"
+	"github.com/osbuild/osbuild-composer/internal/notification/fsstore"
 	"github.com/osbuild/osbuild-composer/internal/rpmmd"
 	"github.com/osbuild/osbuild-composer/internal/weldr"
 	"github.com/osbuild/osbuild-composer/internal/worker"
@@ -44,7 +52,7 @@ type Composer struct {
 	api     *cloudapi.Server
 	koji    *kojiapi.Server
 
-	weldrListener, localWorkerListener, workerListener, apiListener net.Listener
+	weldrListener, weldrTCPListener, localWorkerListener, workerListener, apiListener net.Listener
 }
 
 func NewComposer(config *ComposerConfigFile, stateDir, cacheDir string) (*Composer, error) {
@@ -58,6 +66,7 @@ func NewComposer(config *ComposerConfigFile, stateDir, cacheDir string) (*Compos
 		BasePath:             config.Worker.BasePath,
 		JWTEnabled:           config.Worker.EnableJWT,
 		TenantProviderFields: config.Worker.JWTTenantProviderFields,
+		DeduplicateComposes:  config.Worker.DeduplicateComposes,
 	}
 
 	var err error
@@ -74,6 +83,7 @@ func NewComposer(config *ComposerConfigFile, stateDir, cacheDir string) (*Compos
 	c.rpm = rpmmd.NewRPMMD(path.Join(c.cacheDir, "rpmmd"))
 
 	var jobs jobqueue.JobQueue
+	var notifications notification.Store
 	if config.Worker.PGDatabase != "" {
 		dbURL := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=%s",
 			config.Worker.PGUser,
@@ -92,6 +102,10 @@ func NewComposer(config *ComposerConfigFile, stateDir, cacheDir string) (*Compos
 		if err != nil {
 			return nil, fmt.Errorf("cannot create jobqueue: %v", err)
 		}
+		notifications, err = dbstore.New(dbURL)
+		if err != nil {
+			return nil, fmt.Errorf("cannot create notification store: %v", err)
+		}
 	} else {
 		queueDir, err := c.ensureStateDirectory("jobs", 0700)
 		if err != nil {
@@ -101,6 +115,14 @@ func NewComposer(config *ComposerConfigFile, stateDir, cacheDir string) (*Compos
 		if err != nil {
 			return nil, fmt.Errorf("cannot create jobqueue: %v", err)
 		}
+		notificationsDir, err := c.ensureStateDirectory("notifications", 0700)
+		if err != nil {
+			return nil, err
+		}
+		notifications, err = fsstore.New(notificationsDir)
+		if err != nil {
+			return nil, fmt.Errorf("cannot create notification store: %v", err)
+		}
 	}
 
 	workerConfig.RequestJobTimeout, err = time.ParseDuration(config.Worker.RequestJobTimeout)
@@ -108,6 +130,11 @@ func NewComposer(config *ComposerConfigFile, stateDir, cacheDir string) (*Compos
 		return nil, fmt.Errorf("Unable to parse request job timeout: %v", err)
 	}
 
+	workerConfig.Notifier, err = newNotifier(config.Notifications, notifications)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create notifier: %v", err)
+	}
+
 	c.workers = worker.NewServer(c.logger, jobs, workerConfig)
 
 	return &c, nil
@@ -121,9 +148,60 @@ func (c *Composer) InitWeldr(repoPaths []string, weldrListener net.Listener,
 	}
 	c.weldrListener = weldrListener
 
+	usersConfig := weldr.UsersConfig{
+		MultiUser: c.config.WeldrAPI.MultiUser,
+		Admins:    c.config.WeldrAPI.Admins,
+	}
+
+	if c.config.WeldrAPI.TCPListen != "" {
+		if c.config.WeldrAPI.TokensFile == "" {
+			return fmt.Errorf("weldr API: tokens_file is required for tcp_listen")
+		}
+		usersConfig.Tokens, err = loadWeldrTokens(c.config.WeldrAPI.TokensFile)
+		if err != nil {
+			return fmt.Errorf("weldr API: %v", err)
+		}
+
+		c.weldrTCPListener, err = net.Listen("tcp", c.config.WeldrAPI.TCPListen)
+		if err != nil {
+			return fmt.Errorf("weldr API: %v", err)
+		}
+	}
+
+	c.weldr.SetUsersConfig(usersConfig)
+
 	return nil
 }
 
+// loadWeldrTokens reads a file with a "user:token" pair on each line and
+// returns a map from the tokens to the users. Empty lines and lines starting
+// with # are ignored.
+func loadWeldrTokens(name string) (map[string]string, error) {
+	data, err := ioutil.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read tokens: %v", err)
+	}
+
+	tokens := make(map[string]string)
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("%s:%d: expected user:token", name, i+1)
+		}
+		if _, exists := tokens[parts[1]]; exists {
+			return nil, fmt.Errorf("%s:%d: duplicate token", name, i+1)
+		}
+		tokens[parts[1]] = parts[0]
+	}
+
+	return tokens, nil
+}
+
 func (c *Composer) InitAPI(cert, key string, enableTLS bool, enableMTLS bool, enableJWT bool, l net.Listener) error {
 	config := v2.ServerConfig{
 		AWSBucket:            c.config.Koji.AWS.Bucket,
@@ -131,6 +209,17 @@ func (c *Composer) InitAPI(cert, key string, enableTLS bool, enableMTLS bool, en
 		TenantProviderFields: c.config.Koji.JWTTenantProviderFields,
 	}
 
+	roles, err := newRoleMapping(c.config.Koji.JWTRoles)
+	if err != nil {
+		return fmt.Errorf("API: invalid jwt_roles configuration: %v", err)
+	}
+	config.Roles = roles
+
+	config.Quotas, config.TenantQuotas, err = newQuotas(c.config.Koji.Quotas)
+	if err != nil {
+		return fmt.Errorf("API: invalid quotas configuration: %v", err)
+	}
+
 	c.api = cloudapi.NewServer(c.workers, c.distros, config)
 	c.koji = kojiapi.NewServer(c.logger, c.workers, c.rpm, c.distros)
 
@@ -278,16 +367,23 @@ func (c *Composer) Start() error {
 			handler := http.Handler(mux)
 			var err error
 			if c.config.Koji.EnableJWT {
+				exclude := []string{
+					"/api/image-builder-composer/v2/openapi/?$",
+					"/metrics/?$",
+				}
+				// listing errors is restricted to admins when
+				// roles are enforced, which needs a token
+				if len(c.config.Koji.JWTRoles.Claims) == 0 {
+					exclude = append(exclude, "/api/image-builder-composer/v2/errors/?$")
+				}
+
 				keysURLs := c.config.Koji.JWTKeysURLs
 				handler, err = auth.BuildJWTAuthHandler(
 					keysURLs,
 					c.config.Koji.JWTKeysCA,
 					c.config.Koji.JWTACLFile,
-					[]string{
-						"/api/image-builder-composer/v2/openapi/?$",
-						"/api/image-builder-composer/v2/errors/?$",
-						"/metrics/?$",
-					}, mux)
+					exclude,
+					mux)
 				if err != nil {
 					panic(err)
 				}
@@ -304,6 +400,13 @@ func (c *Composer) Start() error {
 		}()
 	}
 
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if c.weldr != nil {
+		go c.weldr.WatchSchedules(ctx)
+	}
+
 	if c.weldrListener != nil {
 		go func() {
 			err := c.weldr.Serve(c.weldrListener)
@@ -313,8 +416,18 @@ func (c *Composer) Start() error {
 		}()
 	}
 
-	// wait indefinitely
-	select {}
+	if c.weldrTCPListener != nil {
+		go func() {
+			err := c.weldr.Serve(c.weldrTCPListener)
+			if err != nil {
+				panic(err)
+			}
+		}()
+	}
+
+	// wait until composer is asked to shut down
+	<-ctx.Done()
+	return nil
 }
 
 func (c *Composer) ensureStateDirectory(name string, perm os.FileMode) (string, error) {
@@ -377,3 +490,111 @@ func createTLSConfig(c *connectionConfig) (*tls.Config, error) {
 		},
 	}, nil
 }
+
+// newRoleMapping converts the roles configuration of the cloud API into a
+// mapping that can be used to authorize requests. It returns nil if roles
+// aren't configured.
+func newRoleMapping(config JWTRolesConfig) (*auth.RoleMapping, error) {
+	if len(config.Claims) == 0 {
+		if config.DefaultRole != "" || len(config.Viewer) > 0 || len(config.Builder) > 0 || len(config.Admin) > 0 {
+			return nil, fmt.Errorf("no claims with groups configured")
+		}
+		return nil, nil
+	}
+
+	mapping := &auth.RoleMapping{
+		Claims: config.Claims,
+		Groups: make(map[string]auth.Role),
+	}
+
+	if config.DefaultRole != "" {
+		role, err := auth.ParseRole(config.DefaultRole)
+		if err != nil {
+			return nil, err
+		}
+		mapping.DefaultRole = role
+	}
+
+	groups := map[auth.Role][]string{
+		auth.RoleViewer:  config.Viewer,
+		auth.RoleBuilder: config.Builder,
+		auth.RoleAdmin:   config.Admin,
+	}
+	for role, names := range groups {
+		for _, name := range names {
+			if existing, exists := mapping.Groups[name]; exists && existing.Allows(role) {
+				continue
+			}
+			mapping.Groups[name] = role
+		}
+	}
+
+	return mapping, nil
+}
+
+// newNotifier creates the notifier which sends the webhooks of subscribed
+// composes, keeping subscriptions and deliveries in `store`.
+func newNotifier(config NotificationsConfig, store notification.Store) (*notification.Notifier, error) {
+	notifierConfig := notification.Config{
+		MaxAttempts: config.MaxAttempts,
+	}
+	if config.RetryInterval != "" {
+		interval, err := time.ParseDuration(config.RetryInterval)
+		if err != nil {
+			return nil, fmt.Errorf("invalid retry interval: %v", err)
+		}
+		notifierConfig.RetryInterval = interval
+	}
+	for _, webhook := range config.Webhooks {
+		notifierConfig.Webhooks = append(notifierConfig.Webhooks, notification.Webhook{
+			URL:    webhook.URL,
+			Secret: webhook.Secret,
+		})
+	}
+
+	return notification.New(notifierConfig, store)
+}
+
+// newQuotas converts the quotas configuration of the cloud API into the
+// default quotas and those of specific tenants.
+func newQuotas(config QuotasConfig) (v2.Quotas, map[string]v2.Quotas, error) {
+	convert := func(config QuotasConfig) (v2.Quotas, error) {
+		if config.MaxConcurrentComposes < 0 || config.MaxComposesPerHour < 0 {
+			return v2.Quotas{}, fmt.Errorf("limits must not be negative")
+		}
+		quotas := v2.Quotas{
+			MaxConcurrentComposes: config.MaxConcurrentComposes,
+			MaxComposesPerHour:    config.MaxComposesPerHour,
+		}
+		if config.MaxImageSizePerDay != "" {
+			size, err := common.DataSizeToUint64(config.MaxImageSizePerDay)
+			if err != nil {
+				return v2.Quotas{}, err
+			}
+			quotas.MaxImageSizePerDay = size
+		}
+		return quotas, nil
+	}
+
+	quotas, err := convert(config)
+	if err != nil {
+		return v2.Quotas{}, nil, err
+	}
+
+	var tenantQuotas map[string]v2.Quotas
+	for tenant, tenantConfig := range config.Tenants {
+		if len(tenantConfig.Tenants) > 0 {
+			return v2.Quotas{}, nil, fmt.Errorf("tenant %s: quotas of tenants can't be nested", tenant)
+		}
+		q, err := convert(tenantConfig)
+		if err != nil {
+			return v2.Quotas{}, nil, fmt.Errorf("tenant %s: %v", tenant, err)
+		}
+		if tenantQuotas == nil {
+			tenantQuotas = make(map[string]v2.Quotas)
+		}
+		tenantQuotas[tenant] = q
+	}
+
+	return quotas, tenantQuotas, nil
+}
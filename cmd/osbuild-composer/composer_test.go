@@ -0,0 +1,32 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadWeldrTokens(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "tokens")
+
+	require.NoError(t, ioutil.WriteFile(name, []byte("# comment\nalice:secret\n\nbob:with:colon\n"), 0600))
+	tokens, err := loadWeldrTokens(name)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"secret":     "alice",
+		"with:colon": "bob",
+	}, tokens)
+
+	require.NoError(t, ioutil.WriteFile(name, []byte("alice\n"), 0600))
+	_, err = loadWeldrTokens(name)
+	require.Error(t, err)
+
+	require.NoError(t, ioutil.WriteFile(name, []byte("alice:secret\nbob:secret\n"), 0600))
+	_, err = loadWeldrTokens(name)
+	require.Error(t, err)
+
+	_, err = loadWeldrTokens(filepath.Join(t.TempDir(), "missing"))
+	require.Error(t, err)
+}
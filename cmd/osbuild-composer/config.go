@@ -11,24 +11,50 @@ import (
 )
 
 type ComposerConfigFile struct {
-	Koji      KojiAPIConfig   `toml:"koji"`
-	Worker    WorkerAPIConfig `toml:"worker"`
-	WeldrAPI  WeldrAPIConfig  `toml:"weldr_api"`
-	LogLevel  string          `toml:"log_level"`
-	LogFormat string          `toml:"log_format"`
+	Koji          KojiAPIConfig       `toml:"koji"`
+	Worker        WorkerAPIConfig     `toml:"worker"`
+	WeldrAPI      WeldrAPIConfig      `toml:"weldr_api"`
+	Notifications NotificationsConfig `toml:"notifications"`
+	LogLevel      string              `toml:"log_level"`
+	LogFormat     string              `toml:"log_format"`
 }
 
 type KojiAPIConfig struct {
-	AllowedDomains          []string  `toml:"allowed_domains"`
-	CA                      string    `toml:"ca"`
-	EnableTLS               bool      `toml:"enable_tls"`
-	EnableMTLS              bool      `toml:"enable_mtls"`
-	EnableJWT               bool      `toml:"enable_jwt"`
-	JWTKeysURLs             []string  `toml:"jwt_keys_urls"`
-	JWTKeysCA               string    `toml:"jwt_ca_file"`
-	JWTACLFile              string    `toml:"jwt_acl_file"`
-	JWTTenantProviderFields []string  `toml:"jwt_tenant_provider_fields"`
-	AWS                     AWSConfig `toml:"aws_config"`
+	AllowedDomains          []string       `toml:"allowed_domains"`
+	CA                      string         `toml:"ca"`
+	EnableTLS               bool           `toml:"enable_tls"`
+	EnableMTLS              bool           `toml:"enable_mtls"`
+	EnableJWT               bool           `toml:"enable_jwt"`
+	JWTKeysURLs             []string       `toml:"jwt_keys_urls"`
+	JWTKeysCA               string         `toml:"jwt_ca_file"`
+	JWTACLFile              string         `toml:"jwt_acl_file"`
+	JWTTenantProviderFields []string       `toml:"jwt_tenant_provider_fields"`
+	JWTRoles                JWTRolesConfig `toml:"jwt_roles"`
+	Quotas                  QuotasConfig   `toml:"quotas"`
+	AWS                     AWSConfig      `toml:"aws_config"`
+}
+
+// JWTRolesConfig maps the groups found in the claims of JWTs to the roles of
+// the cloud API. Requests whose token doesn't map to any role get
+// DefaultRole, or are rejected if it is empty. Roles are only enforced when
+// at least one claim is configured.
+type JWTRolesConfig struct {
+	Claims      []string `toml:"claims"`
+	DefaultRole string   `toml:"default_role"`
+	Viewer      []string `toml:"viewer"`
+	Builder     []string `toml:"builder"`
+	Admin       []string `toml:"admin"`
+}
+
+// QuotasConfig limits the composes each tenant of the cloud API can request.
+// The limits apply to all tenants, except those with an entry in Tenants,
+// which replaces all of the limits for them. Limits of zero aren't enforced.
+// MaxImageSizePerDay is a size like "2 TiB".
+type QuotasConfig struct {
+	MaxConcurrentComposes int                     `toml:"max_concurrent_composes"`
+	MaxComposesPerHour    int                     `toml:"max_composes_per_hour"`
+	MaxImageSizePerDay    string                  `toml:"max_image_size_per_day"`
+	Tenants               map[string]QuotasConfig `toml:"tenants"`
 }
 
 type AWSConfig struct {
@@ -41,6 +67,7 @@ type WorkerAPIConfig struct {
 	RequestJobTimeout       string   `toml:"request_job_timeout"`
 	BasePath                string   `toml:"base_path"`
 	EnableArtifacts         bool     `toml:"enable_artifacts"`
+	DeduplicateComposes     bool     `toml:"deduplicate_composes"`
 	PGHost                  string   `toml:"pg_host" env:"PGHOST"`
 	PGPort                  string   `toml:"pg_port" env:"PGPORT"`
 	PGDatabase              string   `toml:"pg_database" env:"PGDATABASE"`
@@ -59,6 +86,32 @@ type WorkerAPIConfig struct {
 
 type WeldrAPIConfig struct {
 	DistroConfigs map[string]WeldrDistroConfig `toml:"distros"`
+	// MultiUser restricts users to their own blueprints, composes,
+	// sources and schedules. Admins can access all of them.
+	MultiUser bool     `toml:"multi_user"`
+	Admins    []string `toml:"admins"`
+	// TCPListen is an additional address to serve the weldr API on. Its
+	// clients must send one of the tokens in TokensFile, which contains a
+	// "user:token" pair on each line. The connection is not encrypted, so
+	// it should only be exposed through a TLS terminating proxy.
+	TCPListen  string `toml:"tcp_listen"`
+	TokensFile string `toml:"tokens_file"`
+}
+
+// NotificationsConfig configures the webhooks which are notified about the
+// status of all composes, in addition to those given in compose requests.
+// Failed notifications are attempted MaxAttempts times, waiting
+// RetryInterval (a duration like "10s") before the first retry and twice as
+// long before each further one.
+type NotificationsConfig struct {
+	Webhooks      []WebhookConfig `toml:"webhooks"`
+	MaxAttempts   int             `toml:"max_attempts"`
+	RetryInterval string          `toml:"retry_interval"`
+}
+
+type WebhookConfig struct {
+	URL    string `toml:"url"`
+	Secret string `toml:"secret"`
 }
 
 type WeldrDistroConfig struct {
@@ -101,7 +154,7 @@ func GetDefaultConfig() *ComposerConfigFile {
 			EnableJWT:         false,
 		},
 		WeldrAPI: WeldrAPIConfig{
-			map[string]WeldrDistroConfig{
+			DistroConfigs: map[string]WeldrDistroConfig{
 				"rhel-*": {
 					ImageTypeDenyList: []string{
 						"azure-rhui",
@@ -188,5 +241,9 @@ func loadConfigFromEnv(intf interface{}) error {
 func DumpConfig(c ComposerConfigFile, w io.Writer) error {
 	// sensor sensitive fields
 	c.Worker.PGPassword = ""
+	c.Notifications.Webhooks = append([]WebhookConfig{}, c.Notifications.Webhooks...)
+	for i := range c.Notifications.Webhooks {
+		c.Notifications.Webhooks[i].Secret = ""
+	}
 	return toml.NewEncoder(w).Encode(c)
 }
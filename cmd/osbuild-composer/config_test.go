@@ -6,6 +6,10 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/osbuild-composer/internal/auth"
+	v2 "github.com/osbuild/osbuild-composer/internal/cloudapi/v2"
+	"github.com/osbuild/osbuild-composer/internal/notification/fsstore"
 )
 
 func TestEmpty(t *testing.T) {
@@ -74,9 +78,14 @@ func TestConfig(t *testing.T) {
 
 	require.Equal(t, config.Worker.AllowedDomains, []string{"osbuild.org"})
 	require.Equal(t, config.Worker.CA, "/etc/osbuild-composer/ca-crt.pem")
+	require.True(t, config.Worker.DeduplicateComposes)
 
 	require.Equal(t, []string{"qcow2", "vmdk"}, config.WeldrAPI.DistroConfigs["*"].ImageTypeDenyList)
 	require.Equal(t, []string{"qcow2"}, config.WeldrAPI.DistroConfigs["rhel-84"].ImageTypeDenyList)
+	require.True(t, config.WeldrAPI.MultiUser)
+	require.Equal(t, []string{"alice"}, config.WeldrAPI.Admins)
+	require.Equal(t, "localhost:4040", config.WeldrAPI.TCPListen)
+	require.Equal(t, "/etc/osbuild-composer/weldr-tokens", config.WeldrAPI.TokensFile)
 
 	require.Equal(t, "overwrite-me-db", config.Worker.PGDatabase)
 
@@ -90,6 +99,83 @@ func TestConfig(t *testing.T) {
 	require.Equal(t, []string{"https://sso.redhat.com/auth/realms/redhat-external/protocol/openid-connect/certs"}, config.Koji.JWTKeysURLs)
 	require.Equal(t, "", config.Koji.JWTKeysCA)
 	require.Equal(t, "/var/lib/osbuild-composer/acl", config.Koji.JWTACLFile)
+
+	require.Equal(t, JWTRolesConfig{
+		Claims:      []string{"groups", "realm_access.roles"},
+		DefaultRole: "viewer",
+		Builder:     []string{"image-builders"},
+		Admin:       []string{"image-builder-admins", "image-builders-ops"},
+	}, config.Koji.JWTRoles)
+
+	require.Equal(t, QuotasConfig{
+		MaxConcurrentComposes: 10,
+		MaxImageSizePerDay:    "2 TiB",
+		Tenants: map[string]QuotasConfig{
+			"000001": {MaxComposesPerHour: 100},
+		},
+	}, config.Koji.Quotas)
+
+	require.Equal(t, NotificationsConfig{
+		Webhooks: []WebhookConfig{
+			{URL: "https://hooks.example.com/composes", Secret: "hook-secret"},
+		},
+		MaxAttempts:   3,
+		RetryInterval: "30s",
+	}, config.Notifications)
+}
+
+func TestRoleMapping(t *testing.T) {
+	mapping, err := newRoleMapping(JWTRolesConfig{
+		Claims:      []string{"groups"},
+		DefaultRole: "viewer",
+		Builder:     []string{"builders", "ops"},
+		Admin:       []string{"ops"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, auth.RoleViewer, mapping.DefaultRole)
+	require.Equal(t, map[string]auth.Role{
+		"builders": auth.RoleBuilder,
+		"ops":      auth.RoleAdmin,
+	}, mapping.Groups)
+
+	_, err = newRoleMapping(JWTRolesConfig{Claims: []string{"groups"}, DefaultRole: "superuser"})
+	require.Error(t, err)
+
+	_, err = newRoleMapping(JWTRolesConfig{Admin: []string{"admins"}})
+	require.Error(t, err)
+
+	mapping, err = newRoleMapping(JWTRolesConfig{})
+	require.NoError(t, err)
+	require.Nil(t, mapping)
+}
+
+func TestQuotas(t *testing.T) {
+	quotas, tenantQuotas, err := newQuotas(QuotasConfig{
+		MaxConcurrentComposes: 10,
+		MaxImageSizePerDay:    "2 GiB",
+		Tenants: map[string]QuotasConfig{
+			"000001": {MaxComposesPerHour: 100},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, v2.Quotas{MaxConcurrentComposes: 10, MaxImageSizePerDay: 2 * 1024 * 1024 * 1024}, quotas)
+	require.Equal(t, map[string]v2.Quotas{"000001": {MaxComposesPerHour: 100}}, tenantQuotas)
+
+	quotas, tenantQuotas, err = newQuotas(QuotasConfig{})
+	require.NoError(t, err)
+	require.Equal(t, v2.Quotas{}, quotas)
+	require.Nil(t, tenantQuotas)
+
+	_, _, err = newQuotas(QuotasConfig{MaxImageSizePerDay: "lots"})
+	require.Error(t, err)
+
+	_, _, err = newQuotas(QuotasConfig{MaxComposesPerHour: -1})
+	require.Error(t, err)
+
+	_, _, err = newQuotas(QuotasConfig{Tenants: map[string]QuotasConfig{
+		"000001": {Tenants: map[string]QuotasConfig{"000002": {}}},
+	}})
+	require.Error(t, err)
 }
 
 func TestWeldrDistrosImageTypeDenyList(t *testing.T) {
@@ -118,4 +204,29 @@ func TestDumpConfig(t *testing.T) {
 	require.NotContains(t, buf.String(), "sensitive")
 	// DumpConfig takes a copy
 	require.Equal(t, "sensitive", config.Worker.PGPassword)
+
+	config.Notifications.Webhooks = []WebhookConfig{{URL: "https://hooks.example.com", Secret: "sensitive"}}
+	buf.Reset()
+	require.NoError(t, DumpConfig(*config, &buf))
+	require.Contains(t, buf.String(), "https://hooks.example.com")
+	require.NotContains(t, buf.String(), "sensitive")
+	require.Equal(t, "sensitive", config.Notifications.Webhooks[0].Secret)
+}
+
+func TestNotifier(t *testing.T) {
+	store, err := fsstore.New(t.TempDir())
+	require.NoError(t, err)
+
+	notifier, err := newNotifier(NotificationsConfig{
+		Webhooks:      []WebhookConfig{{URL: "https://hooks.example.com", Secret: "secret"}},
+		RetryInterval: "30s",
+	}, store)
+	require.NoError(t, err)
+	notifier.Close()
+
+	_, err = newNotifier(NotificationsConfig{RetryInterval: "soon"}, store)
+	require.Error(t, err)
+
+	_, err = newNotifier(NotificationsConfig{Webhooks: []WebhookConfig{{URL: "hooks.example.com"}}}, store)
+	require.Error(t, err)
 }
@@ -79,10 +79,11 @@ func main() {
 
 	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
 		type customClaims struct {
-			Type      string `json:"typ"`
-			ExpiresAt int64  `json:"exp"`
-			IssuedAt  int64  `json:"iat"`
-			RHOrgID   string `json:"rh-org-id"`
+			Type      string   `json:"typ"`
+			ExpiresAt int64    `json:"exp"`
+			IssuedAt  int64    `json:"iat"`
+			RHOrgID   string   `json:"rh-org-id"`
+			Groups    []string `json:"groups,omitempty"`
 			jwt.Claims
 		}
 
@@ -96,6 +97,8 @@ func main() {
 			IssuedAt:  time.Now().Unix(),
 			// Use refresh_token as rh-org-id
 			RHOrgID: r.Form.Get("refresh_token"),
+			// Pass groups through to test role-based access control
+			Groups: r.Form["groups"],
 		}
 		token := jwt.NewWithClaims(jwt.SigningMethodRS256, cc)
 		token.Header["kid"] = "key-id"
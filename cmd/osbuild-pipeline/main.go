@@ -151,6 +151,7 @@ func main() {
 					Parent: composeRequest.OSTree.Parent,
 					URL:    composeRequest.OSTree.URL,
 				},
+				EnabledModules: composeRequest.Blueprint.EnabledModules,
 			},
 			repos,
 			packageSpecSets,
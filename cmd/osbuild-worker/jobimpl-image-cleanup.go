@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/osbuild/osbuild-composer/internal/cloud/awscloud"
+	"github.com/osbuild/osbuild-composer/internal/cloud/gcp"
+	"github.com/osbuild/osbuild-composer/internal/target"
+	"github.com/osbuild/osbuild-composer/internal/worker"
+	"github.com/osbuild/osbuild-composer/internal/worker/clienterrors"
+)
+
+type ImageCleanupJobImpl struct {
+	GCPCreds []byte
+	AWSCreds string
+}
+
+// removeImage removes the image described by the result of an upload target.
+func (impl *ImageCleanupJobImpl) removeImage(targetResult *target.TargetResult) error {
+	switch options := targetResult.Options.(type) {
+	case *target.AWSTargetResultOptions:
+		var a *awscloud.AWS
+		var err error
+		if impl.AWSCreds != "" {
+			a, err = awscloud.NewFromFile(impl.AWSCreds, options.Region)
+		} else {
+			a, err = awscloud.NewDefault(options.Region)
+		}
+		if err != nil {
+			return err
+		}
+		logrus.Infof("[AWS] 🧹 Removing image %s in %s", options.Ami, options.Region)
+		return a.DeleteImage(options.Ami)
+	case *target.GCPTargetResultOptions:
+		g, err := gcp.New(impl.GCPCreds)
+		if err != nil {
+			return err
+		}
+		logrus.Infof("[GCP] 🧹 Removing image %s", options.ImageName)
+		return g.ComputeImageDelete(context.Background(), options.ImageName)
+	default:
+		return fmt.Errorf("removing images of target %s is not supported", targetResult.Name)
+	}
+}
+
+func (impl *ImageCleanupJobImpl) Run(job worker.Job) error {
+	var args worker.ImageCleanupJob
+	err := job.Args(&args)
+	if err != nil {
+		return err
+	}
+
+	var failures []string
+	for _, targetResult := range args.TargetResults {
+		err = impl.removeImage(targetResult)
+		if err != nil {
+			logrus.Warnf("Removing image of target %s failed: %v", targetResult.Name, err)
+			failures = append(failures, err.Error())
+		}
+	}
+
+	var result worker.ImageCleanupJobResult
+	if len(failures) > 0 {
+		result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorImageCleanup, "Failed to remove images", failures)
+	}
+
+	err = job.Update(&result)
+	if err != nil {
+		return fmt.Errorf("Error reporting job result: %v", err)
+	}
+
+	return nil
+}
@@ -95,14 +95,25 @@ func (impl *KojiFinalizeJobImpl) Run(job worker.Job) error {
 		return err
 	}
 
-	// Check the dependencies early. Fail the koji build if any of them failed.
-	if hasFailedDependency(*initArgs, osbuildKojiResults) {
-		err = impl.kojiFail(args.Server, int(initArgs.BuildID), initArgs.Token)
+	// When only uploading or importing the images failed, the koji build
+	// is left open, so that the compose can be retried with the same
+	// koji-init results. The last attempt fails it, so that it isn't stuck
+	// in BUILDING.
+	lastAttempt := args.Attempt+1 >= worker.KojiFinalizeAttempts
 
+	// Check the dependencies early. Fail the koji build if any of them failed.
+	if failure := failedDependency(*initArgs, osbuildKojiResults); failure != "" {
 		// Update the status immediately and bail out.
-		var result worker.KojiFinalizeJobResult
-		if err != nil {
-			result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorKojiFailedDependency, err.Error())
+		result := worker.KojiFinalizeJobResult{
+			Failure: failure,
+		}
+		if failure == worker.KojiFailureBuild || lastAttempt {
+			err = impl.kojiFail(args.Server, int(initArgs.BuildID), initArgs.Token)
+			if err != nil {
+				result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorKojiFailedDependency, err.Error())
+			}
+		} else {
+			result.Retryable = true
 		}
 		err = job.Update(&result)
 		if err != nil {
@@ -169,20 +180,34 @@ func (impl *KojiFinalizeJobImpl) Run(job worker.Job) error {
 			Arch:         buildArgs.Arch,
 			ChecksumType: "md5",
 			MD5:          buildArgs.ImageHash,
-			Type:         "image",
+			Type:         koji.CGOutputTypeImage,
 			RPMs:         imageRPMs,
 			Extra: koji.ImageExtra{
 				Info: koji.ImageExtraInfo{
-					Arch: buildArgs.Arch,
+					Arch:      buildArgs.Arch,
+					Distro:    buildArgs.Distro,
+					ImageType: buildArgs.ImageType,
+					Artifact:  "image",
 				},
 			},
 		})
+		images = append(images, kojiExtraImages(uint64(i), args.KojiFilenames[i], buildArgs)...)
 	}
 
 	var result worker.KojiFinalizeJobResult
 	err = impl.kojiImport(args.Server, build, buildRoots, images, args.KojiDirectory, initArgs.Token)
 	if err != nil {
-		result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorKojiFinalize, err.Error())
+		reason := err.Error()
+		if lastAttempt {
+			err = impl.kojiFail(args.Server, int(initArgs.BuildID), initArgs.Token)
+			if err != nil {
+				reason = fmt.Sprintf("%s, and failing the koji build failed as well: %v", reason, err)
+			}
+		} else {
+			result.Retryable = true
+		}
+		result.Failure = worker.KojiFailureImport
+		result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorKojiFinalize, reason)
 	}
 
 	err = job.Update(&result)
@@ -193,6 +218,39 @@ func (impl *KojiFinalizeJobImpl) Run(job worker.Job) error {
 	return nil
 }
 
+// kojiExtraImages returns the outputs for the files which were uploaded
+// along with the image `imageFilename`. Logs are attached to the build, all
+// other files are imported as image archives which refer to their image.
+func kojiExtraImages(buildRootID uint64, imageFilename string, result worker.OSBuildKojiJobResult) []koji.Image {
+	images := make([]koji.Image, 0, len(result.ExtraOutputs))
+	for _, output := range result.ExtraOutputs {
+		outputType := koji.CGOutputTypeImage
+		if output.Kind == worker.KojiOutputLog {
+			outputType = koji.CGOutputTypeLog
+		}
+		images = append(images, koji.Image{
+			BuildRootID:  buildRootID,
+			Filename:     output.Filename,
+			FileSize:     output.Size,
+			Arch:         result.Arch,
+			ChecksumType: "md5",
+			MD5:          output.MD5,
+			Type:         outputType,
+			RPMs:         []rpmmd.RPM{},
+			Extra: koji.ImageExtra{
+				Info: koji.ImageExtraInfo{
+					Arch:      result.Arch,
+					Distro:    result.Distro,
+					ImageType: result.ImageType,
+					Artifact:  output.Kind,
+					Image:     imageFilename,
+				},
+			},
+		})
+	}
+	return images
+}
+
 // Extracts dynamic args of the koji-finalize job. Returns an error if they
 // cannot be unmarshalled.
 func extractDynamicArgs(job worker.Job) (*worker.KojiInitJobResult, []worker.OSBuildKojiJobResult, error) {
@@ -214,18 +272,27 @@ func extractDynamicArgs(job worker.Job) (*worker.KojiInitJobResult, []worker.OSB
 	return &kojiInitResult, osbuildKojiResults, nil
 }
 
-// Returns true if any of koji-finalize dependencies failed.
-func hasFailedDependency(kojiInitResult worker.KojiInitJobResult, osbuildKojiResults []worker.OSBuildKojiJobResult) bool {
+// Returns the step at which the koji build failed if any of koji-finalize
+// dependencies failed, or an empty string if none of them did.
+func failedDependency(kojiInitResult worker.KojiInitJobResult, osbuildKojiResults []worker.OSBuildKojiJobResult) worker.KojiFailure {
 	if kojiInitResult.JobError != nil {
-		return true
+		return worker.KojiFailureBuild
 	}
 
+	var failure worker.KojiFailure
 	for _, r := range osbuildKojiResults {
 		// No `OSBuildOutput` implies failure: either osbuild crashed or
 		// rejected the input (manifest or command line arguments)
-		if r.OSBuildOutput == nil || !r.OSBuildOutput.Success || r.JobError != nil {
-			return true
+		if r.OSBuildOutput == nil || !r.OSBuildOutput.Success {
+			return worker.KojiFailureBuild
+		}
+		// osbuild-koji jobs report failed uploads as koji build errors
+		if r.JobError != nil {
+			if r.JobError.ID != clienterrors.ErrorKojiBuild {
+				return worker.KojiFailureBuild
+			}
+			failure = worker.KojiFailureUpload
 		}
 	}
-	return false
+	return failure
 }
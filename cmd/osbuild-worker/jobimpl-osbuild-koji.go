@@ -1,16 +1,22 @@
 package main
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/url"
 	"os"
 	"path"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
 	"github.com/osbuild/osbuild-composer/internal/common"
 	"github.com/osbuild/osbuild-composer/internal/distro"
+	osbuild "github.com/osbuild/osbuild-composer/internal/osbuild2"
+	"github.com/osbuild/osbuild-composer/internal/rpmmd"
+	"github.com/osbuild/osbuild-composer/internal/sbom"
 	"github.com/osbuild/osbuild-composer/internal/upload/koji"
 	"github.com/osbuild/osbuild-composer/internal/worker"
 	"github.com/osbuild/osbuild-composer/internal/worker/clienterrors"
@@ -20,25 +26,83 @@ type OSBuildKojiJobImpl struct {
 	Store              string
 	Output             string
 	KojiServers        map[string]koji.GSSAPICredentials
+	RPMCache           *RPMCacheConfig
+	OSTreeSigning      *OSTreeSigningConfig
 	relaxTimeoutFactor uint
 }
 
-func (impl *OSBuildKojiJobImpl) kojiUpload(file *os.File, server, directory, filename string) (string, uint64, error) {
+func (impl *OSBuildKojiJobImpl) kojiLogin(server string) (*koji.Koji, error) {
 	transport := koji.CreateKojiTransport(impl.relaxTimeoutFactor)
 
 	serverURL, err := url.Parse(server)
 	if err != nil {
-		return "", 0, err
+		return nil, err
 	}
 
 	creds, exists := impl.KojiServers[serverURL.Hostname()]
 	if !exists {
-		return "", 0, fmt.Errorf("Koji server has not been configured: %s", serverURL.Hostname())
+		return nil, fmt.Errorf("Koji server has not been configured: %s", serverURL.Hostname())
 	}
 
-	k, err := koji.NewFromGSSAPI(server, &creds, transport)
+	return koji.NewFromGSSAPI(server, &creds, transport)
+}
+
+// kojiExtraOutputs returns the files which are uploaded along with the
+// image, keyed by their kind: the manifest, the software bill of materials,
+// and the list of packages in the image. Only the files which could be
+// generated are returned.
+func kojiExtraOutputs(args *worker.OSBuildKojiJob, packageSpecs []rpmmd.PackageSpec, output *osbuild.Result) map[string][]byte {
+	outputs := map[string][]byte{
+		worker.KojiOutputManifest: args.Manifest,
+	}
+
+	rpms := payloadRPMs(args.PipelineNames, output)
+	doc := sbom.Document{
+		Name:     args.KojiFilename,
+		Distro:   args.Distro,
+		Packages: sbom.NewPackages(rpms, packageSpecs),
+		Created:  time.Now(),
+	}
+	spdx, err := doc.SPDX()
+	if err != nil {
+		// like for other composes, a missing bill of materials
+		// shouldn't fail the build
+		logrus.Errorf("Error generating software bill of materials: %v", err)
+	} else {
+		outputs[worker.KojiOutputSBOM] = spdx
+	}
+
+	var packages bytes.Buffer
+	for _, rpm := range rpms {
+		fmt.Fprintln(&packages, rpm.String())
+	}
+	outputs[worker.KojiOutputPackages] = packages.Bytes()
+
+	return outputs
+}
+
+// kojiExtraOutputFilename returns the name under which a file of `kind` is
+// uploaded along with the image named `imageFilename`.
+func kojiExtraOutputFilename(imageFilename, kind string) string {
+	switch kind {
+	case worker.KojiOutputManifest:
+		return imageFilename + ".manifest.json"
+	case worker.KojiOutputSBOM:
+		return imageFilename + ".spdx.json"
+	case worker.KojiOutputLog:
+		return imageFilename + ".osbuild.log"
+	default:
+		return imageFilename + "." + kind + ".txt"
+	}
+}
+
+// kojiUploadOutputs uploads the image at `imagePath`, osbuild's log at
+// `logPath`, and the files describing the image to the koji directory of
+// the build, and records them in `result`.
+func (impl *OSBuildKojiJobImpl) kojiUploadOutputs(result *worker.OSBuildKojiJobResult, args *worker.OSBuildKojiJob, packageSpecs []rpmmd.PackageSpec, imagePath, logPath string) error {
+	k, err := impl.kojiLogin(args.KojiServer)
 	if err != nil {
-		return "", 0, err
+		return err
 	}
 	defer func() {
 		err := k.Logout()
@@ -47,7 +111,47 @@ func (impl *OSBuildKojiJobImpl) kojiUpload(file *os.File, server, directory, fil
 		}
 	}()
 
-	return k.Upload(file, directory, filename)
+	image, err := os.Open(imagePath)
+	if err != nil {
+		return err
+	}
+	defer image.Close()
+	result.ImageHash, result.ImageSize, err = k.Upload(image, args.KojiDirectory, args.KojiFilename)
+	if err != nil {
+		return err
+	}
+
+	upload := func(kind string, r io.Reader) error {
+		filename := kojiExtraOutputFilename(args.KojiFilename, kind)
+		hash, size, err := k.Upload(r, args.KojiDirectory, filename)
+		if err != nil {
+			return fmt.Errorf("error uploading %s: %v", filename, err)
+		}
+		result.ExtraOutputs = append(result.ExtraOutputs, worker.KojiExtraOutput{
+			Kind:     kind,
+			Filename: filename,
+			Size:     size,
+			MD5:      hash,
+		})
+		return nil
+	}
+
+	outputs := kojiExtraOutputs(args, packageSpecs, result.OSBuildOutput)
+	for _, kind := range []string{worker.KojiOutputManifest, worker.KojiOutputSBOM, worker.KojiOutputPackages} {
+		if content, ok := outputs[kind]; ok {
+			err = upload(kind, bytes.NewReader(content))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	log, err := os.Open(logPath)
+	if err != nil {
+		return err
+	}
+	defer log.Close()
+	return upload(worker.KojiOutputLog, log)
 }
 
 func validateKojiResult(result *worker.OSBuildKojiJobResult, jobID string) {
@@ -100,6 +204,8 @@ func (impl *OSBuildKojiJobImpl) Run(job worker.Job) error {
 		return err
 	}
 
+	var packageSpecs []rpmmd.PackageSpec
+
 	result.Arch = common.CurrentArch()
 	result.HostOS, err = distro.GetRedHatRelease()
 	if err != nil {
@@ -122,6 +228,7 @@ func (impl *OSBuildKojiJobImpl) Run(job worker.Job) error {
 				return nil
 			}
 			args.Manifest = manifestJR.Manifest
+			packageSpecs = manifestJR.PackageSpecs
 			if len(args.Manifest) == 0 {
 				err := fmt.Errorf("Received empty manifest")
 				result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorManifestDependency, err.Error())
@@ -146,7 +253,16 @@ func (impl *OSBuildKojiJobImpl) Run(job worker.Job) error {
 			result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorBuildJob, err.Error())
 			return err
 		}
-		result.OSBuildOutput, err = RunOSBuild(args.Manifest, impl.Store, outputDirectory, exports, os.Stderr)
+		// osbuild's log is shown while the job is running and
+		// imported along with the image
+		logFile, err := os.Create(path.Join(outputDirectory, "osbuild.log"))
+		if err != nil {
+			return err
+		}
+		defer logFile.Close()
+		logWriter := newJobLogWriter(job)
+		result.OSBuildOutput, err = RunOSBuild(rewriteManifestForRPMCache(args.Manifest, impl.RPMCache), impl.Store, outputDirectory, exports, args.Proxy, os.Stderr, io.MultiWriter(logWriter, logFile))
+		logWriter.Close()
 		if err != nil {
 			return err
 		}
@@ -156,19 +272,26 @@ func (impl *OSBuildKojiJobImpl) Run(job worker.Job) error {
 		// Use the first (and presumably only) export for the imagePath.
 		exportPath := exports[0]
 		if result.OSBuildOutput.Success {
-			f, err := os.Open(path.Join(outputDirectory, exportPath, args.ImageName))
-			if err != nil {
-				return err
+			if args.OSTreeCommit != nil {
+				archive := path.Join(outputDirectory, exportPath, args.OSTreeCommit.Filename)
+				err = postprocessOSTreeCommit(archive, args.OSTreeCommit, impl.OSTreeSigning, outputDirectory)
+				if err != nil {
+					result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorOSTreeCommit, err.Error())
+					return nil
+				}
 			}
-			result.ImageHash, result.ImageSize, err = impl.kojiUpload(f, args.KojiServer, args.KojiDirectory, args.KojiFilename)
+
+			err = impl.kojiUploadOutputs(&result, &args, packageSpecs, path.Join(outputDirectory, exportPath, args.ImageName), logFile.Name())
 			if err != nil {
 				result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorKojiBuild, err.Error())
 			}
 		}
 	}
 
-	// copy pipeline info to the result
+	// copy pipeline info and the image's description to the result
 	result.PipelineNames = args.PipelineNames
+	result.Distro = args.Distro
+	result.ImageType = args.ImageType
 
 	return nil
 }
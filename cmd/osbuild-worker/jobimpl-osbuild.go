@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"crypto/rand"
 	"fmt"
@@ -11,6 +12,7 @@ import (
 	"os"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/osbuild/osbuild-composer/internal/upload/oci"
 
@@ -21,9 +23,12 @@ import (
 	"github.com/osbuild/osbuild-composer/internal/cloud/gcp"
 	"github.com/osbuild/osbuild-composer/internal/common"
 	osbuild "github.com/osbuild/osbuild-composer/internal/osbuild2"
+	"github.com/osbuild/osbuild-composer/internal/rpmmd"
+	"github.com/osbuild/osbuild-composer/internal/sbom"
 	"github.com/osbuild/osbuild-composer/internal/target"
 	"github.com/osbuild/osbuild-composer/internal/upload/azure"
 	"github.com/osbuild/osbuild-composer/internal/upload/koji"
+	"github.com/osbuild/osbuild-composer/internal/upload/ostree"
 	"github.com/osbuild/osbuild-composer/internal/upload/vmware"
 	"github.com/osbuild/osbuild-composer/internal/worker"
 	"github.com/osbuild/osbuild-composer/internal/worker/clienterrors"
@@ -37,6 +42,13 @@ type OSBuildJobImpl struct {
 	AzureCreds  *azure.Credentials
 	AWSCreds    string
 	AWSBucket   string
+	RPMCache    *RPMCacheConfig
+	// OSTreeSigning is the key to sign OSTree commits with, nil if the
+	// worker doesn't have one.
+	OSTreeSigning *OSTreeSigningConfig
+	// OSTreeRepos are the repositories the ostree target can publish
+	// commits to, by name.
+	OSTreeRepos map[string]*ostree.Repository
 }
 
 // Returns an *awscloud.AWS object with the credentials of the request. If they
@@ -71,6 +83,54 @@ func validateResult(result *worker.OSBuildJobResult, jobID string) {
 	result.Success = true
 }
 
+// payloadRPMs returns the packages which osbuild installed in the payload
+// pipelines of a build.
+func payloadRPMs(pipelineNames *worker.PipelineNames, output *osbuild.Result) []rpmmd.RPM {
+	var rpms []rpmmd.RPM
+	if pipelineNames != nil {
+		for _, plName := range pipelineNames.Payload {
+			rpms = append(rpms, osbuild.OSBuildMetadataToRPMs(output.Metadata[plName])...)
+		}
+	}
+	return rpmmd.DeduplicateRPMs(rpms)
+}
+
+// uploadSBOM generates the software bill of materials of the image built by
+// the job and uploads it as SPDX and CycloneDX artifacts.
+func uploadSBOM(job worker.Job, args *worker.OSBuildJob, output *osbuild.Result) error {
+	rpms := payloadRPMs(args.PipelineNames, output)
+
+	name := args.ImageName
+	if name == "" && len(args.Targets) > 0 {
+		name = args.Targets[0].ImageName
+	}
+	if name == "" {
+		name = job.Id().String()
+	}
+
+	doc := sbom.Document{
+		Name:     name,
+		Distro:   args.Distro,
+		Packages: sbom.NewPackages(rpms, args.PackageSpecs),
+		Created:  time.Now(),
+	}
+
+	spdx, err := doc.SPDX()
+	if err != nil {
+		return err
+	}
+	err = job.UploadArtifact(sbom.SPDXArtifact, bytes.NewReader(spdx))
+	if err != nil {
+		return err
+	}
+
+	cyclonedx, err := doc.CycloneDX()
+	if err != nil {
+		return err
+	}
+	return job.UploadArtifact(sbom.CycloneDXArtifact, bytes.NewReader(cyclonedx))
+}
+
 func (impl *OSBuildJobImpl) Run(job worker.Job) error {
 	logWithId := logrus.WithField("jobId", job.Id().String())
 	// Initialize variable needed for reporting back to osbuild-composer.
@@ -126,6 +186,7 @@ func (impl *OSBuildJobImpl) Run(job worker.Job) error {
 			return nil
 		}
 		args.Manifest = manifestJR.Manifest
+		args.PackageSpecs = manifestJR.PackageSpecs
 	}
 	// copy pipeline info to the result
 	osbuildJobResult.PipelineNames = args.PipelineNames
@@ -149,7 +210,9 @@ func (impl *OSBuildJobImpl) Run(job worker.Job) error {
 	}
 
 	// Run osbuild and handle two kinds of errors
-	osbuildJobResult.OSBuildOutput, err = RunOSBuild(args.Manifest, impl.Store, outputDirectory, exports, os.Stderr)
+	logWriter := newJobLogWriter(job)
+	osbuildJobResult.OSBuildOutput, err = RunOSBuild(rewriteManifestForRPMCache(args.Manifest, impl.RPMCache), impl.Store, outputDirectory, exports, args.Proxy, os.Stderr, logWriter)
+	logWriter.Close()
 	// First handle the case when "running" osbuild failed
 	if err != nil {
 		osbuildJobResult.JobError = clienterrors.WorkerClientError(clienterrors.ErrorBuildJob, "osbuild build failed")
@@ -184,12 +247,29 @@ func (impl *OSBuildJobImpl) Run(job worker.Job) error {
 		return nil
 	}
 
-	streamOptimizedPath := ""
-
 	// NOTE: Currently OSBuild supports multiple exports, but this isn't used
 	// by any of the image types and it can't be specified during the request.
 	// Use the first (and presumably only) export for the imagePath.
 	exportPath := exports[0]
+
+	if args.OSTreeCommit != nil {
+		archive := path.Join(outputDirectory, exportPath, args.OSTreeCommit.Filename)
+		err = postprocessOSTreeCommit(archive, args.OSTreeCommit, impl.OSTreeSigning, outputDirectory)
+		if err != nil {
+			osbuildJobResult.JobError = clienterrors.WorkerClientError(clienterrors.ErrorOSTreeCommit, err.Error())
+			return nil
+		}
+	}
+
+	// A missing bill of materials shouldn't fail the build, but is logged
+	// and can be noticed when it is requested from composer.
+	err = uploadSBOM(job, &args, osbuildJobResult.OSBuildOutput)
+	if err != nil {
+		logWithId.Errorf("Error uploading software bill of materials: %v", err)
+	}
+
+	streamOptimizedPath := ""
+
 	if osbuildJobResult.OSBuildOutput.Success && args.ImageName != "" {
 		var f *os.File
 		imagePath := path.Join(outputDirectory, exportPath, args.ImageName)
@@ -582,6 +662,27 @@ func (impl *OSBuildJobImpl) Run(job worker.Job) error {
 			)
 			osbuildJobResult.Success = true
 			osbuildJobResult.UploadStatus = "success"
+		case *target.OSTreeTargetOptions:
+			repo, exists := impl.OSTreeRepos[options.Repository]
+			if !exists {
+				osbuildJobResult.JobError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidTargetConfig, fmt.Sprintf("unknown ostree repository: %s", options.Repository))
+				return nil
+			}
+
+			logWithId.Infof("[OSTree] ⬆ Publishing the commit to %s", repo.Path)
+			ref, commit, err := repo.Publish(path.Join(outputDirectory, exportPath, options.Filename), options.Ref, outputDirectory)
+			if err != nil {
+				osbuildJobResult.JobError = clienterrors.WorkerClientError(clienterrors.ErrorUploadingImage, err.Error())
+				return nil
+			}
+			logWithId.Infof("[OSTree] 🎉 Published commit %s as %s", commit, ref)
+
+			osbuildJobResult.TargetResults = append(osbuildJobResult.TargetResults, target.NewOSTreeTargetResult(&target.OSTreeTargetResultOptions{
+				Ref:    ref,
+				Commit: commit,
+			}))
+			osbuildJobResult.Success = true
+			osbuildJobResult.UploadStatus = "success"
 		default:
 			osbuildJobResult.JobError = clienterrors.WorkerClientError(clienterrors.ErrorInvalidTarget, fmt.Sprintf("invalid target type: %s", args.Targets[0].Name))
 			return nil
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/osbuild/osbuild-composer/internal/rpmmd"
+	"github.com/osbuild/osbuild-composer/internal/worker"
+)
+
+type RepositoryHealthJobImpl struct {
+	RPMMDCache string
+}
+
+func (impl *RepositoryHealthJobImpl) Run(job worker.Job) error {
+	var args worker.RepositoryHealthJob
+	err := job.Args(&args)
+	if err != nil {
+		return err
+	}
+
+	// the repositories come from API requests, so they must not make the
+	// worker read local files
+	rpmMD := rpmmd.NewRPMMD(impl.RPMMDCache)
+	result := worker.RepositoryHealthJobResult{
+		Health: rpmMD.CheckRepositories(args.Repos, args.Arch, args.Releasever, false),
+	}
+
+	err = job.Update(&result)
+	if err != nil {
+		return fmt.Errorf("Error reporting job result: %v", err)
+	}
+
+	return nil
+}
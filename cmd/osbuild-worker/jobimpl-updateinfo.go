@@ -0,0 +1,35 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/osbuild/osbuild-composer/internal/rpmmd"
+	"github.com/osbuild/osbuild-composer/internal/worker"
+	"github.com/osbuild/osbuild-composer/internal/worker/clienterrors"
+)
+
+type UpdateInfoJobImpl struct {
+	RPMMDCache string
+}
+
+func (impl *UpdateInfoJobImpl) Run(job worker.Job) error {
+	var args worker.UpdateInfoJob
+	err := job.Args(&args)
+	if err != nil {
+		return err
+	}
+
+	var result worker.UpdateInfoJobResult
+	rpmMD := rpmmd.NewRPMMD(impl.RPMMDCache)
+	result.Advisories, err = rpmMD.FetchUpdateInfo(args.Repos, args.Arch, args.Releasever)
+	if err != nil {
+		result.JobError = clienterrors.WorkerClientError(clienterrors.ErrorRPMMDError, err.Error())
+	}
+
+	err = job.Update(&result)
+	if err != nil {
+		return fmt.Errorf("Error reporting job result: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/osbuild/osbuild-composer/internal/worker"
+)
+
+// How often the lines written to a jobLogWriter are sent to composer
+const jobLogInterval = 2 * time.Second
+
+// osbuild's log monitor colors its output for terminals
+var terminalEscapes = regexp.MustCompile("\x1b\\[[0-9;]*[A-Za-z]")
+
+// jobLogWriter sends the lines written to it to composer as the log of a
+// running job. Lines are collected and sent every jobLogInterval, so that
+// chatty stages don't result in a request per line. Errors are only logged:
+// the log is informational and must never fail the job.
+type jobLogWriter struct {
+	job worker.Job
+
+	mu      sync.Mutex
+	partial []byte
+	lines   []string
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newJobLogWriter(job worker.Job) *jobLogWriter {
+	w := &jobLogWriter{
+		job:  job,
+		done: make(chan struct{}),
+	}
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		ticker := time.NewTicker(jobLogInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				w.flush()
+			case <-w.done:
+				return
+			}
+		}
+	}()
+
+	return w
+}
+
+func (w *jobLogWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.partial = append(w.partial, p...)
+	for {
+		i := bytes.IndexByte(w.partial, '\n')
+		if i < 0 {
+			break
+		}
+		w.lines = append(w.lines, cleanLogLine(w.partial[:i]))
+		w.partial = w.partial[i+1:]
+	}
+
+	return len(p), nil
+}
+
+// Close sends the remaining lines, including an unterminated last one.
+func (w *jobLogWriter) Close() error {
+	close(w.done)
+	w.wg.Wait()
+
+	w.mu.Lock()
+	if len(w.partial) > 0 {
+		w.lines = append(w.lines, cleanLogLine(w.partial))
+		w.partial = nil
+	}
+	w.mu.Unlock()
+
+	w.flush()
+	return nil
+}
+
+func (w *jobLogWriter) flush() {
+	w.mu.Lock()
+	lines := w.lines
+	w.lines = nil
+	w.mu.Unlock()
+
+	if len(lines) == 0 {
+		return
+	}
+
+	err := w.job.Log(lines)
+	if err != nil {
+		logrus.Warnf("Error sending %d lines of the log of job %s: %v", len(lines), w.job.Id(), err)
+	}
+}
+
+func cleanLogLine(line []byte) string {
+	return string(terminalEscapes.ReplaceAll(bytes.TrimRight(line, "\r"), nil))
+}
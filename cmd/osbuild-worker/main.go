@@ -16,9 +16,11 @@ import (
 	"github.com/BurntSushi/toml"
 	"github.com/sirupsen/logrus"
 
+	"github.com/osbuild/osbuild-composer/internal/cloud/awscloud"
 	"github.com/osbuild/osbuild-composer/internal/common"
 	"github.com/osbuild/osbuild-composer/internal/upload/azure"
 	"github.com/osbuild/osbuild-composer/internal/upload/koji"
+	"github.com/osbuild/osbuild-composer/internal/upload/ostree"
 	"github.com/osbuild/osbuild-composer/internal/worker"
 )
 
@@ -143,6 +145,34 @@ func main() {
 			OAuthURL         string `toml:"oauth_url"`
 			OfflineTokenPath string `toml:"offline_token"`
 		} `toml:"authentication"`
+		RPMCache *struct {
+			// Either serve a package cache from this worker...
+			Directory string `toml:"directory"`
+			MaxSize   int64  `toml:"max_size"`
+			Listen    string `toml:"listen"`
+			// ... or use the one of another worker.
+			URL string `toml:"url"`
+			// Base urls of the repositories packages are cached from
+			Upstreams []string `toml:"upstreams"`
+		} `toml:"rpm_cache"`
+		OSTreeSigning *struct {
+			GPGHomedir string `toml:"gpg_homedir"`
+			GPGKeyID   string `toml:"gpg_key_id"`
+			Ed25519Key string `toml:"ed25519_key"`
+		} `toml:"ostree_signing"`
+		OSTreeRepositories map[string]struct {
+			Path        string `toml:"path"`
+			KeepCommits int    `toml:"keep_commits"`
+			GPGKeyID    string `toml:"gpg_key_id"`
+			GPGHomedir  string `toml:"gpg_homedir"`
+			S3Bucket    string `toml:"s3_bucket"`
+			S3Region    string `toml:"s3_region"`
+			S3Prefix    string `toml:"s3_prefix"`
+			// WebDAV url the repository is pushed to, with the bearer
+			// token in the file push_token
+			PushURL   string `toml:"push_url"`
+			PushToken string `toml:"push_token"`
+		} `toml:"ostree_repositories"`
 		RelaxTimeoutFactor uint   `toml:"RelaxTimeoutFactor"`
 		BasePath           string `toml:"base_path"`
 	}
@@ -187,6 +217,42 @@ func main() {
 	output := path.Join(cacheDirectory, "output")
 	_ = os.Mkdir(output, os.ModeDir)
 
+	// Packages are downloaded through the cache only if it is configured
+	// explicitly, because the cache server might not be reachable from
+	// everywhere osbuild downloads packages from.
+	var rpmCache *RPMCacheConfig
+	if config.RPMCache != nil {
+		rpmCache = &RPMCacheConfig{
+			URL:       config.RPMCache.URL,
+			Upstreams: config.RPMCache.Upstreams,
+		}
+		if rpmCache.URL == "" {
+			rpmCache.URL, err = startRPMCache(
+				config.RPMCache.Directory,
+				config.RPMCache.MaxSize,
+				config.RPMCache.Listen,
+				config.RPMCache.Upstreams,
+				path.Join(cacheDirectory, "rpms"),
+			)
+			if err != nil {
+				logrus.Fatalf("Error starting the package cache: %v", err)
+			}
+		}
+		if len(rpmCache.Upstreams) == 0 {
+			logrus.Warn("The package cache has no upstreams configured, no packages will be downloaded through it")
+		}
+		logrus.Infof("Downloading packages through the package cache at %s", rpmCache.URL)
+	}
+
+	var ostreeSigning *OSTreeSigningConfig
+	if config.OSTreeSigning != nil {
+		ostreeSigning = &OSTreeSigningConfig{
+			GPGHomedir:     config.OSTreeSigning.GPGHomedir,
+			GPGKeyID:       config.OSTreeSigning.GPGKeyID,
+			Ed25519KeyFile: config.OSTreeSigning.Ed25519Key,
+		}
+	}
+
 	kojiServers := make(map[string]koji.GSSAPICredentials)
 	for server, creds := range config.KojiServers {
 		if creds.Kerberos == nil {
@@ -283,7 +349,48 @@ func main() {
 		awsCredentials = config.AWS.Credentials
 	}
 
-	// depsolve jobs can be done during other jobs
+	ostreeRepositories := make(map[string]*ostree.Repository)
+	for name, repo := range config.OSTreeRepositories {
+		if repo.Path == "" {
+			logrus.Fatalf("OSTree repository %s doesn't have a path", name)
+		}
+		ostreeRepositories[name] = &ostree.Repository{
+			Path:        repo.Path,
+			KeepCommits: repo.KeepCommits,
+			GPGKeyID:    repo.GPGKeyID,
+			GPGHomedir:  repo.GPGHomedir,
+		}
+		if repo.S3Bucket != "" {
+			var a *awscloud.AWS
+			if awsCredentials != "" {
+				a, err = awscloud.NewFromFile(awsCredentials, repo.S3Region)
+			} else {
+				a, err = awscloud.NewDefault(repo.S3Region)
+			}
+			if err != nil {
+				logrus.Fatalf("Error creating the S3 client of OSTree repository %s: %v", name, err)
+			}
+			ostreeRepositories[name].Mirror = &ostree.S3Mirror{
+				Client: a,
+				Bucket: repo.S3Bucket,
+				Prefix: repo.S3Prefix,
+			}
+		}
+		if repo.PushURL != "" {
+			remote := &ostree.HTTPRemote{URL: repo.PushURL}
+			if repo.PushToken != "" {
+				token, err := ioutil.ReadFile(repo.PushToken)
+				if err != nil {
+					logrus.Fatalf("Error reading the push token of OSTree repository %s: %v", name, err)
+				}
+				remote.Token = strings.TrimSpace(string(token))
+			}
+			ostreeRepositories[name].Remote = remote
+		}
+	}
+
+	// depsolve, updateinfo and repository-health jobs can be done during
+	// other jobs
 	depsolveCtx, depsolveCtxCancel := context.WithCancel(context.Background())
 	defer depsolveCtxCancel()
 	go func() {
@@ -291,6 +398,12 @@ func main() {
 			"depsolve": &DepsolveJobImpl{
 				RPMMDCache: rpmmd_cache,
 			},
+			"updateinfo": &UpdateInfoJobImpl{
+				RPMMDCache: rpmmd_cache,
+			},
+			"repository-health": &RepositoryHealthJobImpl{
+				RPMMDCache: rpmmd_cache,
+			},
 		}
 		acceptedJobTypes := []string{}
 		for jt := range jobImpls {
@@ -317,17 +430,22 @@ func main() {
 	// non-depsolve job
 	jobImpls := map[string]JobImplementation{
 		"osbuild": &OSBuildJobImpl{
-			Store:       store,
-			Output:      output,
-			KojiServers: kojiServers,
-			GCPCreds:    gcpCredentials,
-			AzureCreds:  azureCredentials,
-			AWSCreds:    awsCredentials,
+			Store:         store,
+			Output:        output,
+			KojiServers:   kojiServers,
+			GCPCreds:      gcpCredentials,
+			AzureCreds:    azureCredentials,
+			AWSCreds:      awsCredentials,
+			RPMCache:      rpmCache,
+			OSTreeSigning: ostreeSigning,
+			OSTreeRepos:   ostreeRepositories,
 		},
 		"osbuild-koji": &OSBuildKojiJobImpl{
 			Store:              store,
 			Output:             output,
 			KojiServers:        kojiServers,
+			RPMCache:           rpmCache,
+			OSTreeSigning:      ostreeSigning,
 			relaxTimeoutFactor: config.RelaxTimeoutFactor,
 		},
 		"koji-init": &KojiInitJobImpl{
@@ -338,6 +456,10 @@ func main() {
 			KojiServers:        kojiServers,
 			relaxTimeoutFactor: config.RelaxTimeoutFactor,
 		},
+		"image-cleanup": &ImageCleanupJobImpl{
+			GCPCreds: gcpCredentials,
+			AWSCreds: awsCredentials,
+		},
 	}
 
 	acceptedJobTypes := []string{}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"os"
 	"os/exec"
 
 	"github.com/osbuild/osbuild-composer/internal/distro"
@@ -16,7 +17,11 @@ import (
 // Note that osbuild returns non-zero when the pipeline fails. This function
 // does not return an error in this case. Instead, the failure is communicated
 // with its corresponding logs through osbuild.Result.
-func RunOSBuild(manifest distro.Manifest, store, outputDirectory string, exports []string, errorWriter io.Writer) (*osbuild.Result, error) {
+//
+// If `proxy` isn't empty, the sources download through it: curl reads it from
+// osbuild's environment. If `logWriter` isn't nil, osbuild's log is written to
+// it while osbuild is running.
+func RunOSBuild(manifest distro.Manifest, store, outputDirectory string, exports []string, proxy string, errorWriter, logWriter io.Writer) (*osbuild.Result, error) {
 	cmd := exec.Command(
 		"osbuild",
 		"--store", store,
@@ -29,6 +34,32 @@ func RunOSBuild(manifest distro.Manifest, store, outputDirectory string, exports
 	}
 	cmd.Stderr = errorWriter
 
+	// osbuild only reports the result on stdout when --json is given,
+	// its log goes to the monitor's file descriptor (3, the first extra
+	// file) instead
+	var logDone chan struct{}
+	if logWriter != nil {
+		logReader, logPipe, err := os.Pipe()
+		if err != nil {
+			return nil, fmt.Errorf("error creating pipe for osbuild's log: %v", err)
+		}
+		defer logReader.Close()
+		defer logPipe.Close()
+
+		cmd.Args = append(cmd.Args, "--monitor", "LogMonitor", "--monitor-fd", "3")
+		cmd.ExtraFiles = []*os.File{logPipe}
+
+		logDone = make(chan struct{})
+		go func() {
+			defer close(logDone)
+			_, _ = io.Copy(logWriter, logReader)
+		}()
+	}
+
+	if proxy != "" {
+		cmd.Env = append(os.Environ(), "http_proxy="+proxy, "https_proxy="+proxy)
+	}
+
 	stdin, err := cmd.StdinPipe()
 	if err != nil {
 		return nil, fmt.Errorf("error setting up stdin for osbuild: %v", err)
@@ -42,6 +73,12 @@ func RunOSBuild(manifest distro.Manifest, store, outputDirectory string, exports
 		return nil, fmt.Errorf("error starting osbuild: %v", err)
 	}
 
+	if logWriter != nil {
+		// only osbuild writes to the pipe from now on, so that copying
+		// the log stops when it exits
+		cmd.ExtraFiles[0].Close()
+	}
+
 	_, err = stdin.Write(manifest)
 	if err != nil {
 		return nil, fmt.Errorf("error writing osbuild manifest: %v", err)
@@ -53,6 +90,9 @@ func RunOSBuild(manifest distro.Manifest, store, outputDirectory string, exports
 	}
 
 	err = cmd.Wait()
+	if logDone != nil {
+		<-logDone
+	}
 
 	// try to decode the output even though the job could have failed
 	var result osbuild.Result
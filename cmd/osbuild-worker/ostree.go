@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/osbuild/osbuild-composer/internal/ostree"
+	"github.com/osbuild/osbuild-composer/internal/worker"
+)
+
+// OSTreeSigningConfig is the key the worker signs OSTree commits with. Either
+// or both of a GPG key and an ed25519 key can be configured.
+type OSTreeSigningConfig struct {
+	GPGHomedir string
+	GPGKeyID   string
+	// Ed25519KeyFile contains the base64 encoded secret key, as expected
+	// by `ostree sign --keys-file`.
+	Ed25519KeyFile string
+}
+
+// postprocessOSTreeCommit signs the commit in the archive at `archive` and
+// generates a static delta from its parent, as requested by `options`. The
+// archive is replaced by one containing the changed repository.
+func postprocessOSTreeCommit(archive string, options *worker.OSTreeCommitOptions, signing *OSTreeSigningConfig, tmpdir string) error {
+	dir, err := ioutil.TempDir(tmpdir, "ostree-commit-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	err = runCommand("tar", "--extract", "--file", archive, "--directory", dir)
+	if err != nil {
+		return err
+	}
+
+	repo := filepath.Join(dir, "repo")
+	commit, err := ioutil.ReadFile(filepath.Join(repo, "refs", "heads", options.Ref))
+	if err != nil {
+		return fmt.Errorf("error reading ref %s of the commit: %v", options.Ref, err)
+	}
+	checksum := strings.TrimSpace(string(commit))
+
+	// sign before generating the delta, so that the signature is part of
+	// the delta's superblock
+	if options.Sign {
+		err = signOSTreeCommit(repo, checksum, signing)
+		if err != nil {
+			return err
+		}
+	}
+
+	if options.StaticDelta {
+		// keep the GPG key out of `dir`, which is archived again
+		keyDir, err := ioutil.TempDir(tmpdir, "ostree-parent-")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(keyDir)
+
+		err = generateStaticDelta(repo, options, checksum, keyDir)
+		if err != nil {
+			return err
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(repo, "summary")); err == nil {
+		args := []string{"summary", "--repo", repo, "--update"}
+		if options.Sign && signing.GPGKeyID != "" {
+			args = append(args, "--gpg-sign", signing.GPGKeyID)
+			if signing.GPGHomedir != "" {
+				args = append(args, "--gpg-homedir", signing.GPGHomedir)
+			}
+		}
+		err = runCommand("ostree", args...)
+		if err != nil {
+			return err
+		}
+	}
+
+	newArchive := archive + ".new"
+	err = runCommand("tar", "--create", "--file", newArchive, "--directory", dir, ".")
+	if err != nil {
+		return err
+	}
+	return os.Rename(newArchive, archive)
+}
+
+func signOSTreeCommit(repo, checksum string, signing *OSTreeSigningConfig) error {
+	if signing == nil || (signing.GPGKeyID == "" && signing.Ed25519KeyFile == "") {
+		return fmt.Errorf("signing of ostree commits was requested, but this worker doesn't have a signing key")
+	}
+
+	if signing.GPGKeyID != "" {
+		args := []string{"gpg-sign", "--repo", repo, checksum, signing.GPGKeyID}
+		if signing.GPGHomedir != "" {
+			args = append(args, "--gpg-homedir", signing.GPGHomedir)
+		}
+		err := runCommand("ostree", args...)
+		if err != nil {
+			return err
+		}
+	}
+
+	if signing.Ed25519KeyFile != "" {
+		err := runCommand("ostree", "sign", "--repo", repo, "--sign-type", "ed25519", "--keys-file", signing.Ed25519KeyFile, checksum)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// generateStaticDelta pulls the parent commit from the repository of
+// `options` into `repo`, generates a static delta from it to `checksum`, and
+// removes the parent again, so that the archive only grows by the delta.
+// The GPG key is written to `dir`, which must not be part of the archive. If
+// `options` ask for RHSM, the parent is pulled with the host's consumer
+// certificate, like osbuild's ostree source does.
+func generateStaticDelta(repo string, options *worker.OSTreeCommitOptions, checksum string, dir string) error {
+	const remote = "osbuild-parent"
+
+	args := []string{"remote", "add", "--repo", repo}
+	if options.GPGKey != "" {
+		keyFile := filepath.Join(dir, "parent-gpg-key.asc")
+		err := ioutil.WriteFile(keyFile, []byte(options.GPGKey), 0600)
+		if err != nil {
+			return err
+		}
+		args = append(args, "--gpg-import", keyFile)
+	} else {
+		args = append(args, "--no-gpg-verify")
+	}
+
+	if options.RHSM {
+		args = append(args,
+			"--set", "tls-client-cert-path="+ostree.ConsumerCert,
+			"--set", "tls-client-key-path="+ostree.ConsumerKey,
+		)
+	}
+	args = append(args, remote, options.URL)
+
+	err := runCommand("ostree", args...)
+	if err != nil {
+		return err
+	}
+
+	err = runCommand("ostree", "pull", "--repo", repo, remote, options.Parent)
+	if err != nil {
+		return fmt.Errorf("error pulling parent commit %s: %v", options.Parent, err)
+	}
+
+	err = runCommand("ostree", "static-delta", "generate", "--repo", repo, "--from", options.Parent, "--to", checksum)
+	if err != nil {
+		return err
+	}
+
+	err = runCommand("ostree", "remote", "delete", "--repo", repo, remote)
+	if err != nil {
+		return err
+	}
+
+	return runCommand("ostree", "prune", "--repo", repo, "--refs-only", "--depth", "0")
+}
+
+// runCommand runs `name`, returning its output in the error if it fails.
+func runCommand(name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s %s failed: %v: %s", name, args[0], err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sirupsen/logrus"
+
+	"github.com/osbuild/osbuild-composer/internal/distro"
+	"github.com/osbuild/osbuild-composer/internal/rpmcache"
+)
+
+const (
+	defaultRPMCacheMaxSize = 20 * 1024 * 1024 * 1024
+	defaultRPMCacheListen  = "localhost:8701"
+)
+
+// RPMCacheConfig is the package cache osbuild downloads packages through.
+type RPMCacheConfig struct {
+	URL string
+	// Base urls of the repositories whose packages are cached
+	Upstreams []string
+}
+
+// startRPMCache opens the package cache in `directory` (or `defaultDirectory`)
+// and serves it, together with its metrics, on `listen`. Only packages below
+// `upstreams` are downloaded into it. It returns the url osbuild should
+// download packages from.
+func startRPMCache(directory string, maxSize int64, listen string, upstreams []string, defaultDirectory string) (string, error) {
+	if directory == "" {
+		directory = defaultDirectory
+	}
+	if maxSize == 0 {
+		maxSize = defaultRPMCacheMaxSize
+	}
+	if listen == "" {
+		listen = defaultRPMCacheListen
+	}
+
+	cache, err := rpmcache.New(directory, maxSize)
+	if err != nil {
+		return "", err
+	}
+
+	listener, err := net.Listen("tcp", listen)
+	if err != nil {
+		return "", err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/rpms/", rpmcache.NewServer(cache, nil, upstreams))
+	mux.Handle("/metrics", promhttp.Handler())
+
+	go func() {
+		/* #nosec G114 */
+		err := http.Serve(listener, mux)
+		logrus.Fatalf("Package cache server stopped: %v", err)
+	}()
+
+	return "http://" + listener.Addr().String(), nil
+}
+
+// rewriteManifestForRPMCache makes osbuild download the packages of
+// `manifest` through the package cache `config`, if there is one. The cache
+// is only an optimization, so the manifest is used unchanged when that fails.
+func rewriteManifestForRPMCache(manifest distro.Manifest, config *RPMCacheConfig) distro.Manifest {
+	if config == nil {
+		return manifest
+	}
+
+	rewritten, err := rpmcache.RewriteManifest(manifest, config.URL, config.Upstreams)
+	if err != nil {
+		logrus.Warnf("Not using the package cache: %v", err)
+		return manifest
+	}
+	return rewritten
+}
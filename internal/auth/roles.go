@@ -0,0 +1,119 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/openshift-online/ocm-sdk-go/authentication"
+)
+
+var NoRoleError = errors.New("no role found in jwt claims")
+
+// Role is what the subject of a token is allowed to do. Each role includes the
+// permissions of the roles before it.
+type Role string
+
+const (
+	// RoleViewer can read the status and results of composes
+	RoleViewer Role = "viewer"
+	// RoleBuilder can also start composes
+	RoleBuilder Role = "builder"
+	// RoleAdmin can do everything, including administrative operations
+	RoleAdmin Role = "admin"
+)
+
+var roleLevels = map[Role]int{
+	RoleViewer:  1,
+	RoleBuilder: 2,
+	RoleAdmin:   3,
+}
+
+// ParseRole returns the role called `name`.
+func ParseRole(name string) (Role, error) {
+	role := Role(name)
+	if _, exists := roleLevels[role]; !exists {
+		return "", fmt.Errorf("unknown role %q, must be one of viewer, builder, or admin", name)
+	}
+	return role, nil
+}
+
+// Allows returns whether `r` includes the permissions of `required`.
+func (r Role) Allows(required Role) bool {
+	return roleLevels[r] >= roleLevels[required]
+}
+
+// RoleMapping maps the groups or roles in the claims of a JWT to a Role.
+type RoleMapping struct {
+	// Claims contain the groups of the token's subject, either as a string
+	// or as a list of strings. Nested claims are separated by dots, for
+	// example "realm_access.roles".
+	Claims []string
+	// Groups maps the group names to the role their members get. Subjects
+	// in several groups get the highest of their roles.
+	Groups map[string]Role
+	// DefaultRole is the role of tokens that don't contain any of the
+	// groups. Those tokens are rejected if it is empty.
+	DefaultRole Role
+}
+
+// RoleFromContext returns the role of the JWT in `ctx`.
+//
+// If the token doesn't contain any of the groups and there is no default role,
+// NoRoleError is returned.
+func (m *RoleMapping) RoleFromContext(ctx context.Context) (Role, error) {
+	token, err := authentication.TokenFromContext(ctx)
+	if err != nil {
+		return "", err
+	} else if token == nil {
+		return "", NoJWTError
+	}
+
+	claims := token.Claims.(jwt.MapClaims)
+
+	var role Role
+	for _, claim := range m.Claims {
+		for _, group := range groupsFromClaim(claims, claim) {
+			if r, exists := m.Groups[group]; exists && !role.Allows(r) {
+				role = r
+			}
+		}
+	}
+
+	if role == "" {
+		role = m.DefaultRole
+	}
+	if role == "" {
+		return "", NoRoleError
+	}
+	return role, nil
+}
+
+// groupsFromClaim returns the strings in the claim at the dot-separated
+// `path`, which may be a single string or a list.
+func groupsFromClaim(claims map[string]interface{}, path string) []string {
+	var value interface{} = claims
+	for _, key := range strings.Split(path, ".") {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = m[key]
+	}
+
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		var groups []string
+		for _, g := range v {
+			if s, ok := g.(string); ok {
+				groups = append(groups, s)
+			}
+		}
+		return groups
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package auth_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/openshift-online/ocm-sdk-go/authentication"
+	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/osbuild-composer/internal/auth"
+)
+
+func TestRoleFromContext(t *testing.T) {
+	mapping := auth.RoleMapping{
+		Claims: []string{"groups", "realm_access.roles"},
+		Groups: map[string]auth.Role{
+			"image-builder-viewers":  auth.RoleViewer,
+			"image-builder-builders": auth.RoleBuilder,
+			"image-builder-admins":   auth.RoleAdmin,
+		},
+	}
+
+	tests := []struct {
+		name   string
+		claims jwt.MapClaims
+		role   auth.Role
+		err    error
+	}{
+		{
+			name:   "single group",
+			claims: jwt.MapClaims{"groups": "image-builder-viewers"},
+			role:   auth.RoleViewer,
+		},
+		{
+			name:   "highest of several groups",
+			claims: jwt.MapClaims{"groups": []interface{}{"image-builder-admins", "image-builder-viewers", "unrelated"}},
+			role:   auth.RoleAdmin,
+		},
+		{
+			name: "nested claim",
+			claims: jwt.MapClaims{"realm_access": map[string]interface{}{
+				"roles": []interface{}{"image-builder-builders"},
+			}},
+			role: auth.RoleBuilder,
+		},
+		{
+			name:   "groups in several claims",
+			claims: jwt.MapClaims{"groups": "image-builder-viewers", "realm_access": map[string]interface{}{"roles": []interface{}{"image-builder-builders"}}},
+			role:   auth.RoleBuilder,
+		},
+		{
+			name:   "no matching group",
+			claims: jwt.MapClaims{"groups": []interface{}{"unrelated", 42}},
+			err:    auth.NoRoleError,
+		},
+		{
+			name:   "no groups",
+			claims: jwt.MapClaims{"rh-org-id": "42"},
+			err:    auth.NoRoleError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := authentication.ContextWithToken(context.Background(), &jwt.Token{Claims: tt.claims})
+			role, err := mapping.RoleFromContext(ctx)
+			require.Equal(t, tt.err, err)
+			require.Equal(t, tt.role, role)
+		})
+	}
+
+	t.Run("default role", func(t *testing.T) {
+		withDefault := mapping
+		withDefault.DefaultRole = auth.RoleViewer
+		ctx := authentication.ContextWithToken(context.Background(), &jwt.Token{Claims: jwt.MapClaims{}})
+		role, err := withDefault.RoleFromContext(ctx)
+		require.NoError(t, err)
+		require.Equal(t, auth.RoleViewer, role)
+	})
+
+	t.Run("no jwt token in context", func(t *testing.T) {
+		_, err := mapping.RoleFromContext(context.Background())
+		require.ErrorIs(t, err, auth.NoJWTError)
+	})
+}
+
+func TestRoleAllows(t *testing.T) {
+	require.True(t, auth.RoleAdmin.Allows(auth.RoleBuilder))
+	require.True(t, auth.RoleBuilder.Allows(auth.RoleBuilder))
+	require.True(t, auth.RoleBuilder.Allows(auth.RoleViewer))
+	require.False(t, auth.RoleViewer.Allows(auth.RoleBuilder))
+	require.False(t, auth.RoleBuilder.Allows(auth.RoleAdmin))
+	require.False(t, auth.Role("").Allows(auth.RoleViewer))
+}
+
+func TestParseRole(t *testing.T) {
+	role, err := auth.ParseRole("builder")
+	require.NoError(t, err)
+	require.Equal(t, auth.RoleBuilder, role)
+
+	_, err = auth.ParseRole("superuser")
+	require.Error(t, err)
+}
@@ -16,6 +16,7 @@ type Blueprint struct {
 	Packages       []Package       `json:"packages" toml:"packages"`
 	Modules        []Package       `json:"modules" toml:"modules"`
 	Groups         []Group         `json:"groups" toml:"groups"`
+	EnabledModules []EnabledModule `json:"enabled_modules,omitempty" toml:"enabled_modules,omitempty"`
 	Customizations *Customizations `json:"customizations,omitempty" toml:"customizations,omitempty"`
 	Distro         string          `json:"distro" toml:"distro"`
 }
@@ -34,6 +35,14 @@ type Package struct {
 	Version string `json:"version,omitempty" toml:"version,omitempty"`
 }
 
+// An EnabledModule specifies a module stream to enable when depsolving and in
+// the image. Unlike the entries of Modules, which are installed as packages,
+// it doesn't install anything by itself.
+type EnabledModule struct {
+	Name   string `json:"name" toml:"name"`
+	Stream string `json:"stream" toml:"stream"`
+}
+
 // A group specifies an package group.
 type Group struct {
 	Name string `json:"name" toml:"name"`
@@ -69,6 +78,11 @@ func (b *Blueprint) Initialize() error {
 	if b.Version == "" {
 		b.Version = "0.0.0"
 	}
+	for _, m := range b.EnabledModules {
+		if m.Name == "" || m.Stream == "" {
+			return fmt.Errorf("Invalid 'enabled_modules', every module must have a name and a stream")
+		}
+	}
 	// Return an error if the version is not valid
 	_, err := semver.NewVersion(b.Version)
 	if err != nil {
@@ -112,6 +126,20 @@ func (b *Blueprint) GetPackages() []string {
 	return packages
 }
 
+// GetEnabledModules returns the module streams to enable, in the "name:stream"
+// format understood by dnf.
+func (b *Blueprint) GetEnabledModules() []string {
+	modules := []string{}
+	for _, m := range b.EnabledModules {
+		modules = append(modules, m.ToNameStream())
+	}
+	return modules
+}
+
+func (m EnabledModule) ToNameStream() string {
+	return m.Name + ":" + m.Stream
+}
+
 func (p Package) ToNameVersion() string {
 	// Omit version to prevent all packages with prefix of name to be installed
 	if p.Version == "*" || p.Version == "" {
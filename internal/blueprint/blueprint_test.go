@@ -19,6 +19,10 @@ version = "0.0.0"
 name = "httpd"
 version = "2.4.*"
 
+[[enabled_modules]]
+name = "postgresql"
+stream = "13"
+
 [[customizations.filesystem]]
 mountpoint = "/var"
 size = 2147483648
@@ -32,6 +36,7 @@ size = "20 GB"
 	err := toml.Unmarshal([]byte(blueprint), &bp)
 	require.Nil(t, err)
 	assert.Equal(t, bp.Name, "test")
+	assert.Equal(t, []EnabledModule{{Name: "postgresql", Stream: "13"}}, bp.EnabledModules)
 	assert.Equal(t, "/var", bp.Customizations.Filesystem[0].Mountpoint)
 	assert.Equal(t, uint64(2147483648), bp.Customizations.Filesystem[0].MinSize)
 	assert.Equal(t, "/opt", bp.Customizations.Filesystem[1].Mountpoint)
@@ -89,6 +94,8 @@ func TestBlueprintInitialize(t *testing.T) {
 		{Blueprint{Name: "bp-test-5", Description: "Invalid version 5", Version: "foo"}, true},
 		{Blueprint{Name: "bp-test-7", Description: "Zero version", Version: "0.0.0"}, false},
 		{Blueprint{Name: "bp-test-8", Description: "X.Y.Z version", Version: "2.1.3"}, false},
+		{Blueprint{Name: "bp-test-9", Description: "Module stream", EnabledModules: []EnabledModule{{Name: "nodejs", Stream: "16"}}}, false},
+		{Blueprint{Name: "bp-test-10", Description: "Module without stream", EnabledModules: []EnabledModule{{Name: "nodejs"}}}, true},
 	}
 
 	for _, c := range cases {
@@ -140,6 +147,19 @@ func TestGetPackages(t *testing.T) {
 	assert.ElementsMatch(t, []string{"tmux-1.2", "openssh-server", "@anaconda-tools", "kernel"}, Received_packages)
 }
 
+func TestGetEnabledModules(t *testing.T) {
+	bp := Blueprint{
+		Name: "modules-test",
+		EnabledModules: []EnabledModule{
+			{Name: "postgresql", Stream: "13"},
+			{Name: "nodejs", Stream: "16"}},
+	}
+	assert.Equal(t, []string{"postgresql:13", "nodejs:16"}, bp.GetEnabledModules())
+
+	// enabled modules don't install any packages
+	assert.Equal(t, []string{"kernel"}, bp.GetPackages())
+}
+
 func TestKernelNameCustomization(t *testing.T) {
 	kernels := []string{"kernel", "kernel-debug", "kernel-rt"}
 
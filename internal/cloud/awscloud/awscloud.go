@@ -346,6 +346,24 @@ func (a *AWS) RemoveSnapshotAndDeregisterImage(image *ec2.Image) error {
 	return err
 }
 
+// DeleteImage deregisters the image with the given id and removes its
+// snapshots.
+func (a *AWS) DeleteImage(imageID string) error {
+	imgs, err := a.ec2.DescribeImages(
+		&ec2.DescribeImagesInput{
+			ImageIds: []*string{aws.String(imageID)},
+		},
+	)
+	if err != nil {
+		return err
+	}
+	if len(imgs.Images) == 0 {
+		return fmt.Errorf("image %s not found", imageID)
+	}
+
+	return a.RemoveSnapshotAndDeregisterImage(imgs.Images[0])
+}
+
 // For service maintenance images are discovered by the "Name:composer-api-*" tag filter. Currently
 // all image names in the service are generated, so they're guaranteed to be unique as well. If
 // users are ever allowed to name their images, an extra tag should be added.
@@ -376,3 +394,74 @@ func (a *AWS) S3ObjectPresignedURL(bucket, objectKey string) (string, error) {
 	logrus.Info("[AWS] 🎉 S3 Presigned URL ready")
 	return url, nil
 }
+
+// PutObject uploads `filename` to `bucket` as `key`. Unlike Upload, it doesn't
+// log each file, because it is used to upload many small files.
+func (a *AWS) PutObject(filename, bucket, key string) error {
+	file, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = a.uploader.Upload(
+		&s3manager.UploadInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(key),
+			Body:   file,
+		},
+	)
+	return err
+}
+
+// ListObjectSizes returns the size of all objects in `bucket` whose keys start
+// with `prefix`, by key.
+func (a *AWS) ListObjectSizes(bucket, prefix string) (map[string]int64, error) {
+	sizes := make(map[string]int64)
+	err := a.s3.ListObjectsV2Pages(&s3.ListObjectsV2Input{
+		Bucket: aws.String(bucket),
+		Prefix: aws.String(prefix),
+	}, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		for _, object := range page.Contents {
+			sizes[aws.StringValue(object.Key)] = aws.Int64Value(object.Size)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sizes, nil
+}
+
+// DeleteObjects deletes the objects with `keys` from `bucket`.
+func (a *AWS) DeleteObjects(bucket string, keys []string) error {
+	// DeleteObjects accepts at most 1000 keys per request
+	for len(keys) > 0 {
+		n := len(keys)
+		if n > 1000 {
+			n = 1000
+		}
+
+		var objects []*s3.ObjectIdentifier
+		for _, key := range keys[:n] {
+			objects = append(objects, &s3.ObjectIdentifier{Key: aws.String(key)})
+		}
+
+		out, err := a.s3.DeleteObjects(&s3.DeleteObjectsInput{
+			Bucket: aws.String(bucket),
+			Delete: &s3.Delete{
+				Objects: objects,
+				Quiet:   aws.Bool(true),
+			},
+		})
+		if err != nil {
+			return err
+		}
+		if len(out.Errors) > 0 {
+			return fmt.Errorf("error deleting %s: %s", aws.StringValue(out.Errors[0].Key), aws.StringValue(out.Errors[0].Message))
+		}
+
+		keys = keys[n:]
+	}
+	return nil
+}
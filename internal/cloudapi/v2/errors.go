@@ -1,8 +1,10 @@
 package v2
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/labstack/echo/v4"
@@ -42,6 +44,22 @@ const (
 	ErrorInvalidJobType               ServiceErrorCode = 26
 	ErrorInvalidOSTreeParams          ServiceErrorCode = 27
 	ErrorTenantNotFound               ServiceErrorCode = 28
+	ErrorFetchingAdvisories           ServiceErrorCode = 29
+	ErrorSBOMNotFound                 ServiceErrorCode = 30
+	ErrorInvalidSBOMFormat            ServiceErrorCode = 31
+	ErrorComposeManifestUnavailable   ServiceErrorCode = 32
+	ErrorInvalidModule                ServiceErrorCode = 33
+	ErrorInvalidRepositoryProxy       ServiceErrorCode = 34
+	ErrorConflictingRepositoryProxy   ServiceErrorCode = 35
+	ErrorForbidden                    ServiceErrorCode = 36
+	ErrorInvalidComposeFilter         ServiceErrorCode = 37
+	ErrorComposeNotFinished           ServiceErrorCode = 38
+	ErrorQuotaExceeded                ServiceErrorCode = 39
+	ErrorInvalidWebhook               ServiceErrorCode = 40
+	ErrorNotificationsDisabled        ServiceErrorCode = 41
+	ErrorTooManyRepositories          ServiceErrorCode = 42
+	ErrorCheckingRepositories         ServiceErrorCode = 43
+	ErrorComposeNotRetryable          ServiceErrorCode = 44
 
 	// Internal errors, these are bugs
 	ErrorFailedToInitializeBlueprint              ServiceErrorCode = 1000
@@ -60,6 +78,16 @@ const (
 	ErrorGettingDepsolveJobStatus                 ServiceErrorCode = 1013
 	ErrorDepsolveJobCanceled                      ServiceErrorCode = 1014
 	ErrorUnexpectedNumberOfImageBuilds            ServiceErrorCode = 1015
+	ErrorGettingComposeRepositories               ServiceErrorCode = 1016
+	ErrorComparingComposes                        ServiceErrorCode = 1017
+	ErrorListingComposes                          ServiceErrorCode = 1018
+	ErrorCancelingCompose                         ServiceErrorCode = 1019
+	ErrorDeletingCompose                          ServiceErrorCode = 1020
+	ErrorCheckingQuotas                           ServiceErrorCode = 1021
+	ErrorSubscribingCompose                       ServiceErrorCode = 1022
+	ErrorGettingNotifications                     ServiceErrorCode = 1023
+	ErrorStreamingComposeEvents                   ServiceErrorCode = 1024
+	ErrorRetryingCompose                          ServiceErrorCode = 1025
 
 	// Errors contained within this file
 	ErrorUnspecified          ServiceErrorCode = 10000
@@ -107,6 +135,22 @@ func getServiceErrors() serviceErrors {
 		serviceError{ErrorInvalidNumberOfImageBuilds, http.StatusBadRequest, "Compose request has unsupported number of image builds"},
 		serviceError{ErrorInvalidOSTreeParams, http.StatusBadRequest, "Invalid OSTree parameters or parameter combination"},
 		serviceError{ErrorTenantNotFound, http.StatusBadRequest, "Tenant not found in JWT claims"},
+		serviceError{ErrorFetchingAdvisories, http.StatusBadGateway, "Unable to fetch advisories from the compose's repositories"},
+		serviceError{ErrorSBOMNotFound, http.StatusNotFound, "Software bill of materials not found for compose"},
+		serviceError{ErrorInvalidSBOMFormat, http.StatusBadRequest, "Invalid software bill of materials format, must be spdx or cyclonedx"},
+		serviceError{ErrorComposeManifestUnavailable, http.StatusBadRequest, "The manifest of the compose has not been generated successfully"},
+		serviceError{ErrorInvalidModule, http.StatusBadRequest, "Enabled modules must have a name and a stream"},
+		serviceError{ErrorInvalidRepositoryProxy, http.StatusBadRequest, "Invalid proxy for a repository"},
+		serviceError{ErrorConflictingRepositoryProxy, http.StatusBadRequest, "All repositories with a proxy must use the same one"},
+		serviceError{ErrorForbidden, http.StatusForbidden, "The role in the JWT claims doesn't allow this operation"},
+		serviceError{ErrorInvalidComposeFilter, http.StatusBadRequest, "Invalid status or image type to filter composes by"},
+		serviceError{ErrorComposeNotFinished, http.StatusConflict, "Compose must have finished or been canceled before it can be deleted"},
+		serviceError{ErrorQuotaExceeded, http.StatusTooManyRequests, "The compose would exceed a quota of the tenant"},
+		serviceError{ErrorInvalidWebhook, http.StatusBadRequest, "Webhook URLs must be absolute http or https URLs"},
+		serviceError{ErrorNotificationsDisabled, http.StatusBadRequest, "Webhook notifications are not enabled"},
+		serviceError{ErrorTooManyRepositories, http.StatusBadRequest, "Too many repositories to check at once"},
+		serviceError{ErrorCheckingRepositories, http.StatusBadGateway, "Unable to check the repositories"},
+		serviceError{ErrorComposeNotRetryable, http.StatusConflict, "Only koji composes whose images couldn't be uploaded or imported can be retried, a limited number of times"},
 
 		serviceError{ErrorFailedToInitializeBlueprint, http.StatusInternalServerError, "Failed to initialize blueprint"},
 		serviceError{ErrorFailedToGenerateManifestSeed, http.StatusInternalServerError, "Failed to generate manifest seed"},
@@ -124,6 +168,16 @@ func getServiceErrors() serviceErrors {
 		serviceError{ErrorGettingDepsolveJobStatus, http.StatusInternalServerError, "Unable to get depsolve job status"},
 		serviceError{ErrorDepsolveJobCanceled, http.StatusInternalServerError, "Depsolve job was cancelled"},
 		serviceError{ErrorUnexpectedNumberOfImageBuilds, http.StatusInternalServerError, "Compose has unexpected number of image builds"},
+		serviceError{ErrorGettingComposeRepositories, http.StatusInternalServerError, "Unable to get the packages and repositories of the compose"},
+		serviceError{ErrorComparingComposes, http.StatusInternalServerError, "Unable to compare the composes"},
+		serviceError{ErrorListingComposes, http.StatusInternalServerError, "Unable to list the composes"},
+		serviceError{ErrorCancelingCompose, http.StatusInternalServerError, "Unable to cancel the compose"},
+		serviceError{ErrorDeletingCompose, http.StatusInternalServerError, "Unable to delete the compose"},
+		serviceError{ErrorCheckingQuotas, http.StatusInternalServerError, "Unable to check the quotas of the tenant"},
+		serviceError{ErrorSubscribingCompose, http.StatusInternalServerError, "Unable to subscribe the compose to notifications"},
+		serviceError{ErrorGettingNotifications, http.StatusInternalServerError, "Unable to get the notifications of the compose"},
+		serviceError{ErrorStreamingComposeEvents, http.StatusInternalServerError, "Unable to stream the events of the compose"},
+		serviceError{ErrorRetryingCompose, http.StatusInternalServerError, "Unable to retry the compose"},
 
 		serviceError{ErrorUnspecified, http.StatusInternalServerError, "Unspecified internal error "},
 		serviceError{ErrorNotHTTPError, http.StatusInternalServerError, "Error is not an instance of HTTPError"},
@@ -245,6 +299,16 @@ func (s *Server) HTTPErrorHandler(echoError error, c echo.Context) {
 				c.Logger().Error(errMsg)
 			}
 
+			// tell clients how long to wait before they retry
+			var quotaErr *quotaExceededError
+			if errors.As(internal, &quotaErr) {
+				var details interface{} = quotaErr.details()
+				apiErr.Details = &details
+				if quotaErr.retryAfter > 0 {
+					c.Response().Header().Set("Retry-After", strconv.Itoa(quotaErr.retryAfterSeconds()))
+				}
+			}
+
 			if c.Request().Method == http.MethodHead {
 				err = c.NoContent(sec.httpStatus)
 			} else {
@@ -12,6 +12,7 @@ import (
 	"net/url"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/deepmap/oapi-codegen/pkg/runtime"
 	"github.com/getkin/kin-openapi/openapi3"
@@ -22,6 +23,19 @@ const (
 	BearerScopes = "Bearer.Scopes"
 )
 
+// Defines values for ComposeNotificationStatus.
+const (
+	ComposeNotificationStatusBuilding ComposeNotificationStatus = "building"
+
+	ComposeNotificationStatusFailure ComposeNotificationStatus = "failure"
+
+	ComposeNotificationStatusPending ComposeNotificationStatus = "pending"
+
+	ComposeNotificationStatusSuccess ComposeNotificationStatus = "success"
+
+	ComposeNotificationStatusUploading ComposeNotificationStatus = "uploading"
+)
+
 // Defines values for ComposeStatusValue.
 const (
 	ComposeStatusValueFailure ComposeStatusValue = "failure"
@@ -75,6 +89,53 @@ const (
 	ImageTypesVsphere ImageTypes = "vsphere"
 )
 
+// Defines values for KojiStatusFailure.
+const (
+	KojiStatusFailureBuild KojiStatusFailure = "build"
+
+	KojiStatusFailureImport KojiStatusFailure = "import"
+
+	KojiStatusFailureUpload KojiStatusFailure = "upload"
+)
+
+// Defines values for PackageExplanationSource.
+const (
+	PackageExplanationSourceImageType PackageExplanationSource = "image_type"
+
+	PackageExplanationSourceRequest PackageExplanationSource = "request"
+)
+
+// Defines values for RepositoryHealthCheckName.
+const (
+	RepositoryHealthCheckNameGpgKey RepositoryHealthCheckName = "gpg_key"
+
+	RepositoryHealthCheckNameMetadataAge RepositoryHealthCheckName = "metadata_age"
+
+	RepositoryHealthCheckNameReachable RepositoryHealthCheckName = "reachable"
+
+	RepositoryHealthCheckNameRepomdSignature RepositoryHealthCheckName = "repomd_signature"
+)
+
+// Defines values for RepositoryHealthCheckStatus.
+const (
+	RepositoryHealthCheckStatusError RepositoryHealthCheckStatus = "error"
+
+	RepositoryHealthCheckStatusOk RepositoryHealthCheckStatus = "ok"
+
+	RepositoryHealthCheckStatusSkipped RepositoryHealthCheckStatus = "skipped"
+
+	RepositoryHealthCheckStatusWarning RepositoryHealthCheckStatus = "warning"
+)
+
+// Defines values for StageChangeChange.
+const (
+	StageChangeChangeAdded StageChangeChange = "added"
+
+	StageChangeChangeChanged StageChangeChange = "changed"
+
+	StageChangeChangeRemoved StageChangeChange = "removed"
+)
+
 // Defines values for UploadStatusValue.
 const (
 	UploadStatusValueFailure UploadStatusValue = "failure"
@@ -120,6 +181,30 @@ type AWSS3UploadStatus struct {
 	Url string `json:"url"`
 }
 
+// Advisory defines model for Advisory.
+type Advisory struct {
+	Cves   *[]string `json:"cves,omitempty"`
+	Id     string    `json:"id"`
+	Issued *string   `json:"issued,omitempty"`
+
+	// Packages containing the fix
+	Packages []AdvisoryPackage `json:"packages"`
+	Severity *string           `json:"severity,omitempty"`
+	Title    string            `json:"title"`
+
+	// security, bugfix, enhancement, or newpackage
+	Type string `json:"type"`
+}
+
+// AdvisoryPackage defines model for AdvisoryPackage.
+type AdvisoryPackage struct {
+	Arch    string `json:"arch"`
+	Epoch   int    `json:"epoch"`
+	Name    string `json:"name"`
+	Release string `json:"release"`
+	Version string `json:"version"`
+}
+
 // AzureUploadOptions defines model for AzureUploadOptions.
 type AzureUploadOptions struct {
 	// Name of the uploaded image. It must be unique in the given resource group.
@@ -149,6 +234,50 @@ type AzureUploadStatus struct {
 	ImageName string `json:"image_name"`
 }
 
+// ComposeAdvisories defines model for ComposeAdvisories.
+type ComposeAdvisories struct {
+	// Embedded struct due to allOf(#/components/schemas/ObjectReference)
+	ObjectReference `yaml:",inline"`
+	// Embedded fields due to inline allOf schema
+	// Advisories whose fixes are included in the compose
+	Fixed []Advisory `json:"fixed"`
+
+	// Advisories for which the compose contains outdated packages
+	Unpatched []Advisory `json:"unpatched"`
+
+	// CVEs referenced by the unpatched advisories
+	UnpatchedCves []string `json:"unpatched_cves"`
+}
+
+// ComposeDiff defines model for ComposeDiff.
+type ComposeDiff struct {
+	// Embedded struct due to allOf(#/components/schemas/ObjectReference)
+	ObjectReference `yaml:",inline"`
+	// Embedded fields due to inline allOf schema
+	// Changes of the blueprint and its customizations
+	Blueprint []FieldChange `json:"blueprint"`
+
+	// ID of the compose compared to
+	OtherId  string      `json:"other_id"`
+	Packages PackageDiff `json:"packages"`
+
+	// Changes of the partition table, without partition UUIDs
+	PartitionTable []FieldChange `json:"partition_table"`
+
+	// Stages of the manifest which were added, removed, or changed
+	Stages []StageChange `json:"stages"`
+}
+
+// ComposeEvent defines model for ComposeEvent.
+type ComposeEvent struct {
+	// The job which logged the lines
+	JobId *string `json:"job_id,omitempty"`
+
+	// Lines of the job's osbuild log
+	Lines  *[]string                  `json:"lines,omitempty"`
+	Status *ComposeNotificationStatus `json:"status,omitempty"`
+}
+
 // ComposeId defines model for ComposeId.
 type ComposeId struct {
 	// Embedded struct due to allOf(#/components/schemas/ObjectReference)
@@ -157,6 +286,14 @@ type ComposeId struct {
 	Id string `json:"id"`
 }
 
+// ComposeList defines model for ComposeList.
+type ComposeList struct {
+	// Embedded struct due to allOf(#/components/schemas/List)
+	List `yaml:",inline"`
+	// Embedded fields due to inline allOf schema
+	Items []ComposeStatus `json:"items"`
+}
+
 // ComposeLogs defines model for ComposeLogs.
 type ComposeLogs struct {
 	// Embedded struct due to allOf(#/components/schemas/ObjectReference)
@@ -186,6 +323,17 @@ type ComposeMetadata struct {
 	Packages *[]PackageMetadata `json:"packages,omitempty"`
 }
 
+// ComposeNotificationStatus defines model for ComposeNotificationStatus.
+type ComposeNotificationStatus string
+
+// ComposeNotifications defines model for ComposeNotifications.
+type ComposeNotifications struct {
+	// Embedded struct due to allOf(#/components/schemas/ObjectReference)
+	ObjectReference `yaml:",inline"`
+	// Embedded fields due to inline allOf schema
+	Deliveries []NotificationDelivery `json:"deliveries"`
+}
+
 // ComposeRequest defines model for ComposeRequest.
 type ComposeRequest struct {
 	Customizations *Customizations `json:"customizations,omitempty"`
@@ -193,6 +341,13 @@ type ComposeRequest struct {
 	ImageRequest   *ImageRequest   `json:"image_request,omitempty"`
 	ImageRequests  *[]ImageRequest `json:"image_requests,omitempty"`
 	Koji           *Koji           `json:"koji,omitempty"`
+
+	// URLs to POST to whenever the status of the compose changes. The
+	// body is a JSON object with the fields id, compose_id, status and
+	// time. Requests to webhooks with a secret are signed with it: the
+	// X-Composer-Signature header contains "sha256=" followed by the
+	// hex-encoded HMAC-SHA256 of the body.
+	Webhooks *[]Webhook `json:"webhooks,omitempty"`
 }
 
 // ComposeStatus defines model for ComposeStatus.
@@ -200,10 +355,14 @@ type ComposeStatus struct {
 	// Embedded struct due to allOf(#/components/schemas/ObjectReference)
 	ObjectReference `yaml:",inline"`
 	// Embedded fields due to inline allOf schema
-	ImageStatus   ImageStatus        `json:"image_status"`
-	ImageStatuses *[]ImageStatus     `json:"image_statuses,omitempty"`
-	KojiStatus    *KojiStatus        `json:"koji_status,omitempty"`
-	Status        ComposeStatusValue `json:"status"`
+	// Set when the image was not built, because a previous compose
+	// with identical content (packages, customizations and image
+	// type) was found. Its image and upload results were reused.
+	DeduplicatedFrom *string            `json:"deduplicated_from,omitempty"`
+	ImageStatus      ImageStatus        `json:"image_status"`
+	ImageStatuses    *[]ImageStatus     `json:"image_statuses,omitempty"`
+	KojiStatus       *KojiStatus        `json:"koji_status,omitempty"`
+	Status           ComposeStatusValue `json:"status"`
 }
 
 // ComposeStatusError defines model for ComposeStatusError.
@@ -218,8 +377,16 @@ type ComposeStatusValue string
 
 // Customizations defines model for Customizations.
 type Customizations struct {
-	Filesystem *[]Filesystem `json:"filesystem,omitempty"`
-	Packages   *[]string     `json:"packages,omitempty"`
+	// Module streams to enable when depsolving the packages of the image.
+	// The streams are also recorded as enabled in the image.
+	EnabledModules *[]Module     `json:"enabled_modules,omitempty"`
+	Filesystem     *[]Filesystem `json:"filesystem,omitempty"`
+	Firewall       *Firewall     `json:"firewall,omitempty"`
+	Groups         *[]Group      `json:"groups,omitempty"`
+	Hostname       *string       `json:"hostname,omitempty"`
+	Kernel         *Kernel       `json:"kernel,omitempty"`
+	Locale         *Locale       `json:"locale,omitempty"`
+	Packages       *[]string     `json:"packages,omitempty"`
 
 	// Extra repositories for packages specified in customizations. These
 	// repositories will only be used to depsolve and retrieve packages
@@ -227,8 +394,13 @@ type Customizations struct {
 	// any other part of the build process). The package_sets field for these
 	// repositories is ignored.
 	PayloadRepositories *[]Repository `json:"payload_repositories,omitempty"`
-	Subscription        *Subscription `json:"subscription,omitempty"`
-	Users               *[]User       `json:"users,omitempty"`
+
+	// Services to enable or disable. For the firewall, these are the names
+	// of firewalld services, otherwise of systemd units.
+	Services     *Services     `json:"services,omitempty"`
+	Subscription *Subscription `json:"subscription,omitempty"`
+	Timezone     *Timezone     `json:"timezone,omitempty"`
+	Users        *[]User       `json:"users,omitempty"`
 }
 
 // Error defines model for Error.
@@ -236,9 +408,12 @@ type Error struct {
 	// Embedded struct due to allOf(#/components/schemas/ObjectReference)
 	ObjectReference `yaml:",inline"`
 	// Embedded fields due to inline allOf schema
-	Code        string `json:"code"`
-	OperationId string `json:"operation_id"`
-	Reason      string `json:"reason"`
+	Code string `json:"code"`
+
+	// Additional information about the error, e.g. the exceeded quota
+	Details     *interface{} `json:"details,omitempty"`
+	OperationId string       `json:"operation_id"`
+	Reason      string       `json:"reason"`
 }
 
 // ErrorList defines model for ErrorList.
@@ -249,12 +424,33 @@ type ErrorList struct {
 	Items []Error `json:"items"`
 }
 
+// FieldChange defines model for FieldChange.
+type FieldChange struct {
+	// Value in the compose compared from, missing if the value was added
+	From *interface{} `json:"from,omitempty"`
+
+	// Location of the value, or empty when the values differ as a whole
+	Path string `json:"path"`
+
+	// Value in the compose compared to, missing if the value was removed
+	To *interface{} `json:"to,omitempty"`
+}
+
 // Filesystem defines model for Filesystem.
 type Filesystem struct {
 	MinSize    int    `json:"min_size"`
 	Mountpoint string `json:"mountpoint"`
 }
 
+// Firewall defines model for Firewall.
+type Firewall struct {
+	Ports *[]string `json:"ports,omitempty"`
+
+	// Services to enable or disable. For the firewall, these are the names
+	// of firewalld services, otherwise of systemd units.
+	Services *Services `json:"services,omitempty"`
+}
+
 // GCPUploadOptions defines model for GCPUploadOptions.
 type GCPUploadOptions struct {
 	// Name of an existing STANDARD Storage class Bucket.
@@ -293,6 +489,12 @@ type GCPUploadStatus struct {
 	ProjectId string `json:"project_id"`
 }
 
+// Group defines model for Group.
+type Group struct {
+	Gid  *int   `json:"gid,omitempty"`
+	Name string `json:"name"`
+}
+
 // ImageRequest defines model for ImageRequest.
 type ImageRequest struct {
 	Architecture  string         `json:"architecture"`
@@ -315,6 +517,15 @@ type ImageStatusValue string
 // ImageTypes defines model for ImageTypes.
 type ImageTypes string
 
+// Kernel defines model for Kernel.
+type Kernel struct {
+	// Arguments appended to the kernel command line
+	Append *string `json:"append,omitempty"`
+
+	// Name of the kernel package to install instead of the default one
+	Name *string `json:"name,omitempty"`
+}
+
 // Koji defines model for Koji.
 type Koji struct {
 	Name    string `json:"name"`
@@ -333,8 +544,22 @@ type KojiLogs struct {
 // KojiStatus defines model for KojiStatus.
 type KojiStatus struct {
 	BuildId *int `json:"build_id,omitempty"`
+
+	// The step at which a failed koji build failed. When only uploading
+	// or importing the images failed, the koji build is left open until
+	// the compose was retried a few times.
+	Failure *KojiStatusFailure `json:"failure,omitempty"`
+
+	// Whether the koji build was left open, so that the compose can be
+	// retried with /composes/{id}/retry.
+	Retryable *bool `json:"retryable,omitempty"`
 }
 
+// The step at which a failed koji build failed. When only uploading
+// or importing the images failed, the koji build is left open until
+// the compose was retried a few times.
+type KojiStatusFailure string
+
 // List defines model for List.
 type List struct {
 	Kind  string `json:"kind"`
@@ -343,11 +568,50 @@ type List struct {
 	Total int    `json:"total"`
 }
 
+// Locale defines model for Locale.
+type Locale struct {
+	Keyboard  *string   `json:"keyboard,omitempty"`
+	Languages *[]string `json:"languages,omitempty"`
+}
+
+// Module defines model for Module.
+type Module struct {
+	Name   string `json:"name"`
+	Stream string `json:"stream"`
+}
+
+// NotificationDelivery defines model for NotificationDelivery.
+type NotificationDelivery struct {
+	Attempts    int                       `json:"attempts"`
+	Delivered   bool                      `json:"delivered"`
+	EventId     string                    `json:"event_id"`
+	LastAttempt time.Time                 `json:"last_attempt"`
+	Status      ComposeNotificationStatus `json:"status"`
+	Url         string                    `json:"url"`
+}
+
 // OSTree defines model for OSTree.
 type OSTree struct {
+	// ASCII-armored public GPG key the parent commit must be signed with.
+	GpgKey *string `json:"gpg_key,omitempty"`
 	Parent *string `json:"parent,omitempty"`
 	Ref    *string `json:"ref,omitempty"`
-	Url    *string `json:"url,omitempty"`
+
+	// Pull the parent commit from url with the subscription manager
+	// (candlepin) identity of the worker's host: its consumer
+	// certificate is used as client certificate. Composer doesn't access
+	// the repository itself, so this requires a url and the checksum of
+	// the parent commit as parent. Other credentials, like custom CA or
+	// client certificates and tokens, are not supported.
+	Rhsm *bool `json:"rhsm,omitempty"`
+
+	// Sign the commit with the signing key of the worker
+	Sign *bool `json:"sign,omitempty"`
+
+	// Generate a static delta from the parent commit to the new commit
+	// and include it in the commit archive. Requires a url.
+	StaticDelta *bool   `json:"static_delta,omitempty"`
+	Url         *string `json:"url,omitempty"`
 }
 
 // ObjectReference defines model for ObjectReference.
@@ -357,6 +621,57 @@ type ObjectReference struct {
 	Kind string `json:"kind"`
 }
 
+// PackageDiff defines model for PackageDiff.
+type PackageDiff struct {
+	Added   []PackageVersion       `json:"added"`
+	Changed []PackageVersionChange `json:"changed"`
+	Removed []PackageVersion       `json:"removed"`
+}
+
+// PackageExplainRequest defines model for PackageExplainRequest.
+type PackageExplainRequest struct {
+	Architecture   string     `json:"architecture"`
+	Distribution   string     `json:"distribution"`
+	EnabledModules *[]Module  `json:"enabled_modules,omitempty"`
+	ImageType      ImageTypes `json:"image_type"`
+
+	// Packages to add to the image, like in the customizations of a compose
+	Packages     *[]string    `json:"packages,omitempty"`
+	Repositories []Repository `json:"repositories"`
+}
+
+// PackageExplanation defines model for PackageExplanation.
+type PackageExplanation struct {
+	Arch string `json:"arch"`
+
+	// The shortest chain of requirements from a requested package to
+	// this one, each package requiring the next one
+	Chain []string `json:"chain"`
+	Epoch int      `json:"epoch"`
+	Name  string   `json:"name"`
+
+	// Why dnf installs the package, "user" for requested packages
+	Reason  *string `json:"reason,omitempty"`
+	Release string  `json:"release"`
+
+	// The package specs that requested the first package of the chain
+	RequestedBy []string `json:"requested_by"`
+
+	// Whether the first package of the chain was requested in the
+	// request or is one of the image type's default packages
+	Source  *PackageExplanationSource `json:"source,omitempty"`
+	Version string                    `json:"version"`
+}
+
+// Whether the first package of the chain was requested in the
+// request or is one of the image type's default packages
+type PackageExplanationSource string
+
+// PackageExplanationList defines model for PackageExplanationList.
+type PackageExplanationList struct {
+	PackageSets []PackageSetExplanation `json:"package_sets"`
+}
+
 // PackageMetadata defines model for PackageMetadata.
 type PackageMetadata struct {
 	Arch      string  `json:"arch"`
@@ -369,6 +684,29 @@ type PackageMetadata struct {
 	Version   string  `json:"version"`
 }
 
+// PackageSetExplanation defines model for PackageSetExplanation.
+type PackageSetExplanation struct {
+	Name     string               `json:"name"`
+	Packages []PackageExplanation `json:"packages"`
+}
+
+// PackageVersion defines model for PackageVersion.
+type PackageVersion struct {
+	Arch string `json:"arch"`
+	Name string `json:"name"`
+
+	// Version in the form [epoch:]version-release
+	Version string `json:"version"`
+}
+
+// PackageVersionChange defines model for PackageVersionChange.
+type PackageVersionChange struct {
+	Arch        string `json:"arch"`
+	FromVersion string `json:"from_version"`
+	Name        string `json:"name"`
+	ToVersion   string `json:"to_version"`
+}
+
 // Repository defines model for Repository.
 type Repository struct {
 	Baseurl    *string `json:"baseurl,omitempty"`
@@ -381,9 +719,78 @@ type Repository struct {
 	// Naming package sets for a repository assigns it to a specific part
 	// (pipeline) of the build process.
 	PackageSets *[]string `json:"package_sets,omitempty"`
-	Rhsm        bool      `json:"rhsm"`
+
+	// Proxy to access the repository through. It must not contain
+	// credentials. All repositories of a compose which use a proxy must
+	// use the same one.
+	Proxy *string `json:"proxy,omitempty"`
+	Rhsm  bool    `json:"rhsm"`
+}
+
+// RepositoryHealth defines model for RepositoryHealth.
+type RepositoryHealth struct {
+	Checks []RepositoryHealthCheck `json:"checks"`
+
+	// False when any of the checks failed, warnings are ignored
+	Healthy bool `json:"healthy"`
+
+	// When the newest metadata of the repository was generated
+	MetadataTimestamp *time.Time `json:"metadata_timestamp,omitempty"`
+
+	// The baseurl, mirrorlist, or metalink of the repository
+	Url string `json:"url"`
+}
+
+// RepositoryHealthCheck defines model for RepositoryHealthCheck.
+type RepositoryHealthCheck struct {
+	Message *string                     `json:"message,omitempty"`
+	Name    RepositoryHealthCheckName   `json:"name"`
+	Status  RepositoryHealthCheckStatus `json:"status"`
+}
+
+// RepositoryHealthCheckName defines model for RepositoryHealthCheck.Name.
+type RepositoryHealthCheckName string
+
+// RepositoryHealthCheckStatus defines model for RepositoryHealthCheck.Status.
+type RepositoryHealthCheckStatus string
+
+// RepositoryHealthList defines model for RepositoryHealthList.
+type RepositoryHealthList struct {
+	Items []RepositoryHealth `json:"items"`
+}
+
+// RepositoryHealthRequest defines model for RepositoryHealthRequest.
+type RepositoryHealthRequest struct {
+	// Architecture the repositories are used for, to expand $basearch
+	Architecture string `json:"architecture"`
+
+	// Distribution the repositories are used for, to expand $releasever
+	Distribution string       `json:"distribution"`
+	Repositories []Repository `json:"repositories"`
+}
+
+// Services to enable or disable. For the firewall, these are the names
+// of firewalld services, otherwise of systemd units.
+type Services struct {
+	Disabled *[]string `json:"disabled,omitempty"`
+	Enabled  *[]string `json:"enabled,omitempty"`
+}
+
+// StageChange defines model for StageChange.
+type StageChange struct {
+	Change      StageChangeChange       `json:"change"`
+	FromOptions *map[string]interface{} `json:"from_options,omitempty"`
+
+	// The stage is the n-th stage of its type in the pipeline
+	Index     int                     `json:"index"`
+	Pipeline  string                  `json:"pipeline"`
+	ToOptions *map[string]interface{} `json:"to_options,omitempty"`
+	Type      string                  `json:"type"`
 }
 
+// StageChangeChange defines model for StageChange.Change.
+type StageChangeChange string
+
 // Subscription defines model for Subscription.
 type Subscription struct {
 	ActivationKey string `json:"activation_key"`
@@ -393,6 +800,12 @@ type Subscription struct {
 	ServerUrl     string `json:"server_url"`
 }
 
+// Timezone defines model for Timezone.
+type Timezone struct {
+	Ntpservers *[]string `json:"ntpservers,omitempty"`
+	Timezone   *string   `json:"timezone,omitempty"`
+}
+
 // UploadOptions defines model for UploadOptions.
 type UploadOptions interface{}
 
@@ -419,6 +832,16 @@ type User struct {
 	Name   string    `json:"name"`
 }
 
+// Webhook defines model for Webhook.
+type Webhook struct {
+	// Key of the HMAC signature of the requests
+	Secret *string `json:"secret,omitempty"`
+
+	// Events are only delivered to public addresses, not to loopback,
+	// link-local or private ones
+	Url string `json:"url"`
+}
+
 // Page defines model for page.
 type Page string
 
@@ -428,6 +851,45 @@ type Size string
 // PostComposeJSONBody defines parameters for PostCompose.
 type PostComposeJSONBody ComposeRequest
 
+// GetComposeListParams defines parameters for GetComposeList.
+type GetComposeListParams struct {
+	// Page index
+	Page *Page `json:"page,omitempty"`
+
+	// Number of items in each page
+	Size *Size `json:"size,omitempty"`
+
+	// Only list composes with this status
+	Status *ComposeStatusValue `json:"status,omitempty"`
+
+	// Only list composes which build an image of this type
+	ImageType *ImageTypes `json:"image_type,omitempty"`
+
+	// Only list composes of this distribution
+	Distribution *string `json:"distribution,omitempty"`
+
+	// Only list composes created at or after this time
+	CreatedAfter *time.Time `json:"created_after,omitempty"`
+
+	// Only list composes created before this time
+	CreatedBefore *time.Time `json:"created_before,omitempty"`
+}
+
+// DeleteComposeParams defines parameters for DeleteCompose.
+type DeleteComposeParams struct {
+	// Also remove the images uploaded to AWS EC2 or GCP
+	DeleteImages *bool `json:"delete_images,omitempty"`
+}
+
+// GetComposeSBOMParams defines parameters for GetComposeSBOM.
+type GetComposeSBOMParams struct {
+	// Format of the software bill of materials
+	Format *GetComposeSBOMParamsFormat `json:"format,omitempty"`
+}
+
+// GetComposeSBOMParamsFormat defines parameters for GetComposeSBOM.
+type GetComposeSBOMParamsFormat string
+
 // GetErrorListParams defines parameters for GetErrorList.
 type GetErrorListParams struct {
 	// Page index
@@ -437,17 +899,47 @@ type GetErrorListParams struct {
 	Size *Size `json:"size,omitempty"`
 }
 
+// PostPackagesExplainJSONBody defines parameters for PostPackagesExplain.
+type PostPackagesExplainJSONBody PackageExplainRequest
+
+// PostRepositoriesHealthJSONBody defines parameters for PostRepositoriesHealth.
+type PostRepositoriesHealthJSONBody RepositoryHealthRequest
+
 // PostComposeJSONRequestBody defines body for PostCompose for application/json ContentType.
 type PostComposeJSONRequestBody PostComposeJSONBody
 
+// PostPackagesExplainJSONRequestBody defines body for PostPackagesExplain for application/json ContentType.
+type PostPackagesExplainJSONRequestBody PostPackagesExplainJSONBody
+
+// PostRepositoriesHealthJSONRequestBody defines body for PostRepositoriesHealth for application/json ContentType.
+type PostRepositoriesHealthJSONRequestBody PostRepositoriesHealthJSONBody
+
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
 	// Create compose
 	// (POST /compose)
 	PostCompose(ctx echo.Context) error
+	// The composes of the tenant
+	// (GET /composes)
+	GetComposeList(ctx echo.Context, params GetComposeListParams) error
+	// Delete a compose
+	// (DELETE /composes/{id})
+	DeleteCompose(ctx echo.Context, id string, params DeleteComposeParams) error
 	// The status of a compose
 	// (GET /composes/{id})
 	GetComposeStatus(ctx echo.Context, id string) error
+	// Compare a compose with the current update advisories.
+	// (GET /composes/{id}/advisories)
+	GetComposeAdvisories(ctx echo.Context, id string) error
+	// Cancel a compose
+	// (POST /composes/{id}/cancel)
+	PostComposeCancel(ctx echo.Context, id string) error
+	// Compare two composes
+	// (GET /composes/{id}/diff/{other_id})
+	GetComposeDiff(ctx echo.Context, id string, otherId string) error
+	// Stream the status transitions and build log of a compose.
+	// (GET /composes/{id}/events)
+	GetComposeEvents(ctx echo.Context, id string) error
 	// Get logs for a compose.
 	// (GET /composes/{id}/logs)
 	GetComposeLogs(ctx echo.Context, id string) error
@@ -457,6 +949,15 @@ type ServerInterface interface {
 	// Get the metadata for a compose.
 	// (GET /composes/{id}/metadata)
 	GetComposeMetadata(ctx echo.Context, id string) error
+	// Get the log of the webhook notifications sent about a compose.
+	// (GET /composes/{id}/notifications)
+	GetComposeNotifications(ctx echo.Context, id string) error
+	// Retry a koji compose
+	// (POST /composes/{id}/retry)
+	PostComposeRetry(ctx echo.Context, id string) error
+	// Get the software bill of materials of a compose.
+	// (GET /composes/{id}/sbom)
+	GetComposeSBOM(ctx echo.Context, id string, params GetComposeSBOMParams) error
 	// Get a list of all possible errors
 	// (GET /errors)
 	GetErrorList(ctx echo.Context, params GetErrorListParams) error
@@ -466,6 +967,12 @@ type ServerInterface interface {
 	// Get the openapi spec in json format
 	// (GET /openapi)
 	GetOpenapi(ctx echo.Context) error
+	// Explain why packages are part of an image
+	// (POST /packages/explain)
+	PostPackagesExplain(ctx echo.Context) error
+	// Check the health of repositories
+	// (POST /repositories/health)
+	PostRepositoriesHealth(ctx echo.Context) error
 }
 
 // ServerInterfaceWrapper converts echo contexts to parameters.
@@ -484,6 +991,95 @@ func (w *ServerInterfaceWrapper) PostCompose(ctx echo.Context) error {
 	return err
 }
 
+// GetComposeList converts echo context to params.
+func (w *ServerInterfaceWrapper) GetComposeList(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerScopes, []string{""})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetComposeListParams
+	// ------------- Optional query parameter "page" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page", ctx.QueryParams(), &params.Page)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter page: %s", err))
+	}
+
+	// ------------- Optional query parameter "size" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "size", ctx.QueryParams(), &params.Size)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter size: %s", err))
+	}
+
+	// ------------- Optional query parameter "status" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "status", ctx.QueryParams(), &params.Status)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter status: %s", err))
+	}
+
+	// ------------- Optional query parameter "image_type" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "image_type", ctx.QueryParams(), &params.ImageType)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter image_type: %s", err))
+	}
+
+	// ------------- Optional query parameter "distribution" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "distribution", ctx.QueryParams(), &params.Distribution)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter distribution: %s", err))
+	}
+
+	// ------------- Optional query parameter "created_after" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "created_after", ctx.QueryParams(), &params.CreatedAfter)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter created_after: %s", err))
+	}
+
+	// ------------- Optional query parameter "created_before" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "created_before", ctx.QueryParams(), &params.CreatedBefore)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter created_before: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetComposeList(ctx, params)
+	return err
+}
+
+// DeleteCompose converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteCompose(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(BearerScopes, []string{""})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DeleteComposeParams
+	// ------------- Optional query parameter "delete_images" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "delete_images", ctx.QueryParams(), &params.DeleteImages)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter delete_images: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.DeleteCompose(ctx, id, params)
+	return err
+}
+
 // GetComposeStatus converts echo context to params.
 func (w *ServerInterfaceWrapper) GetComposeStatus(ctx echo.Context) error {
 	var err error
@@ -502,6 +1098,86 @@ func (w *ServerInterfaceWrapper) GetComposeStatus(ctx echo.Context) error {
 	return err
 }
 
+// GetComposeAdvisories converts echo context to params.
+func (w *ServerInterfaceWrapper) GetComposeAdvisories(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(BearerScopes, []string{""})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetComposeAdvisories(ctx, id)
+	return err
+}
+
+// PostComposeCancel converts echo context to params.
+func (w *ServerInterfaceWrapper) PostComposeCancel(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(BearerScopes, []string{""})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.PostComposeCancel(ctx, id)
+	return err
+}
+
+// GetComposeDiff converts echo context to params.
+func (w *ServerInterfaceWrapper) GetComposeDiff(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// ------------- Path parameter "other_id" -------------
+	var otherId string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "other_id", runtime.ParamLocationPath, ctx.Param("other_id"), &otherId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter other_id: %s", err))
+	}
+
+	ctx.Set(BearerScopes, []string{""})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetComposeDiff(ctx, id, otherId)
+	return err
+}
+
+// GetComposeEvents converts echo context to params.
+func (w *ServerInterfaceWrapper) GetComposeEvents(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(BearerScopes, []string{""})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetComposeEvents(ctx, id)
+	return err
+}
+
 // GetComposeLogs converts echo context to params.
 func (w *ServerInterfaceWrapper) GetComposeLogs(ctx echo.Context) error {
 	var err error
@@ -552,6 +1228,69 @@ func (w *ServerInterfaceWrapper) GetComposeMetadata(ctx echo.Context) error {
 	return err
 }
 
+// GetComposeNotifications converts echo context to params.
+func (w *ServerInterfaceWrapper) GetComposeNotifications(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(BearerScopes, []string{""})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetComposeNotifications(ctx, id)
+	return err
+}
+
+// PostComposeRetry converts echo context to params.
+func (w *ServerInterfaceWrapper) PostComposeRetry(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(BearerScopes, []string{""})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.PostComposeRetry(ctx, id)
+	return err
+}
+
+// GetComposeSBOM converts echo context to params.
+func (w *ServerInterfaceWrapper) GetComposeSBOM(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, ctx.Param("id"), &id)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(BearerScopes, []string{""})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetComposeSBOMParams
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", ctx.QueryParams(), &params.Format)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter format: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetComposeSBOM(ctx, id, params)
+	return err
+}
+
 // GetErrorList converts echo context to params.
 func (w *ServerInterfaceWrapper) GetErrorList(ctx echo.Context) error {
 	var err error
@@ -608,6 +1347,28 @@ func (w *ServerInterfaceWrapper) GetOpenapi(ctx echo.Context) error {
 	return err
 }
 
+// PostPackagesExplain converts echo context to params.
+func (w *ServerInterfaceWrapper) PostPackagesExplain(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerScopes, []string{""})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.PostPackagesExplain(ctx)
+	return err
+}
+
+// PostRepositoriesHealth converts echo context to params.
+func (w *ServerInterfaceWrapper) PostRepositoriesHealth(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(BearerScopes, []string{""})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.PostRepositoriesHealth(ctx)
+	return err
+}
+
 // This is a simple interface which specifies echo.Route addition functions which
 // are present on both echo.Echo and echo.Group, since we want to allow using
 // either of them for path registration
@@ -637,93 +1398,183 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 	}
 
 	router.POST(baseURL+"/compose", wrapper.PostCompose)
+	router.GET(baseURL+"/composes", wrapper.GetComposeList)
+	router.DELETE(baseURL+"/composes/:id", wrapper.DeleteCompose)
 	router.GET(baseURL+"/composes/:id", wrapper.GetComposeStatus)
+	router.GET(baseURL+"/composes/:id/advisories", wrapper.GetComposeAdvisories)
+	router.POST(baseURL+"/composes/:id/cancel", wrapper.PostComposeCancel)
+	router.GET(baseURL+"/composes/:id/diff/:other_id", wrapper.GetComposeDiff)
+	router.GET(baseURL+"/composes/:id/events", wrapper.GetComposeEvents)
 	router.GET(baseURL+"/composes/:id/logs", wrapper.GetComposeLogs)
 	router.GET(baseURL+"/composes/:id/manifests", wrapper.GetComposeManifests)
 	router.GET(baseURL+"/composes/:id/metadata", wrapper.GetComposeMetadata)
+	router.GET(baseURL+"/composes/:id/notifications", wrapper.GetComposeNotifications)
+	router.POST(baseURL+"/composes/:id/retry", wrapper.PostComposeRetry)
+	router.GET(baseURL+"/composes/:id/sbom", wrapper.GetComposeSBOM)
 	router.GET(baseURL+"/errors", wrapper.GetErrorList)
 	router.GET(baseURL+"/errors/:id", wrapper.GetError)
 	router.GET(baseURL+"/openapi", wrapper.GetOpenapi)
+	router.POST(baseURL+"/packages/explain", wrapper.PostPackagesExplain)
+	router.POST(baseURL+"/repositories/health", wrapper.PostRepositoriesHealth)
 
 }
 
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/+xce2/buLL/KoTOBdriSrbiR17AYk+aZnuy2xeSdA/ubQKDlsYWG4nUklQct8h3v+BD",
-	"sh70I7vZPfcc5J/GNsmZ4XDmx5kh2e9exLKcUaBSeMffvRxznIEEbr/NQf2NQUSc5JIw6h17n/AcEKEx",
-	"3Hu+B/c4y1NodL/DaQHesbfnPTz4HlFjfiuALz3fozhTLbqn74kogQyrIXKZq9+F5ITO9TBBvjl4fyiy",
-	"KXDEZohIyAQiFAGOEmQJ1qUpCVTShOFaeXTfTfI8lI2a9Mk/L89OB5/zlOH4oxbNzJ+zHLgkhj+HuZb5",
-	"eymVd+xBESxAyGDP89ssfE8kmMNkQWQywVHECrsk1egv3t5gOBrvHxwehXsD78b3tA4c4lbEMed4qWlT",
-	"nIuEyYmZcF2mbBmUrV2pHnyPw28F4RArAeyc3LLeVKPZ9CtEUvGta+pSYlk4FIUz0pQIZyQIo8NheHA0",
-	"PDgYj4/G8Wjq0tgjVdyajOJb0Vgj/OXwaVfZrc8tzNcpruCp23fqLFQnJ/1vBYctkyMZnkNlMi1PxBko",
-	"P5QJoEKTgRjpAT10LlFWCImmgApKfisUXOiOc3IHFHEQrOARoDlnRd67puczpJggIhDLiJQQoxlnmR6i",
-	"5gJC+ggjjmnMMsQooCkWECNGEUafP5+/QURc0zlQ4FhC3LumKywwFq4Fc5lQyiIs7Qo2J/jOtqBFAhy0",
-	"LJoKEgkr0lhPrpw3pjFSaykkcM3/H2yBJEMpERLhNEUlG3F8TRMpc3Hc78csEr2MRJwJNpO9iGV9oEEh",
-	"+lFK+lgtT9/61o93BBY/6J+CKCVBiiUI+Tf8rXS+iWI0qZi8aClAWSMUamndXmSWY6KXY/NKN5duB9W0",
-	"1+KKFRGmF5bMW83RhYXFtBJhQuKuUOdvlEj1br9DmBGM48PpIArwdDAKRqO9YXAURuNgf28wDPfhMDyC",
-	"gUs6CRRTuUEuJYTptJtU1lxmhMaIyNJbtIuiT4xLnO5iN6XNSHIHQUw4RJLxZX9W0BhnQCVORac1SNgi",
-	"kCxQrAMjcktJ4+gAZuPpfrAXDWfBKMZhgPcHgyCchvvhYHgUH8QHW4FupbHu2nYssOaVW5BrHTI2gWsX",
-	"JGjJWyPgEuFUBU0CzrUB4DT9OPOOv3z3/ovDzDv2/tZfBVV9Gzb0P+rBFzADDjQC78HvCB03hd0bDEFt",
-	"9wEcHk2DvUE8DPBovB+MBvv74/FoFIZh6PnejPEMS+/YKwqtzC0Tix0TullN6R2biyedlFbktCBpXI9U",
-	"qsDkln3Vu/8mLr+wr0TL5V4lS3zjtN5jSmYg5JPOLasTbU6sJeiq52YpQeIYS/yUQjIhOcAkYllGpBOv",
-	"XiZYJK9K2FLalMh2d2BfjqNbPDe023mBbjGbHqFRWsSEztGHs18vTrxasLppPpZGpYhOKPuwSX8XJlbo",
-	"4kFUCMky8g1Xgc4mIU6bvR98LyZKAdNCdmI9nkAaHLoUZYyTr0TaxPJcdS7Fbw82jrSL+tpkfq+3dQy4",
-	"oYCaxlcA/LSAISq6W6drRaiUZobCI5W2ouLS2Y7yKNWtCO02pqHIX3Wu2la+JdSc4GYkMeTOOGe86w0x",
-	"SExS9VEpLa6hF6ES5sBNVIiFMfatG0rVuSOAmY9yGFpkeipFFIFQc5lhkhZc7cQ5UAUUakIrv1p17DjW",
-	"aceZm9ObkRTEUkjIdjaBn1ZDHBZQh7xaNp4zIeccxOMy8RwvVeQy4ZAzQSTjxIWlZ/eSY1Tvg2aMo1IS",
-	"JHKIyIyolIuiJrr10FUCAq5pY/SCpCliNF3qyFMlT5KhGHLB0juw+YvkBO6gYnJNFUu1J3y8REQKSGfo",
-	"pUxgaYhRphM8fIdJiqcpoLK33o8RZ0wixq8ppkvEZAJKei7r20yMcs7UKr/SMpeMJwKkQDMCaVzS7EyH",
-	"CETmlPEypt9plS9KCktniaQWlm6jdFnv++B7hbAVs53k+CyMi23b2XyvcuCnQteIxeA0U9UJ17ItR5a4",
-	"Gx5oDlX3FmE3ZulZviNml9xtprq3Y/Mo1b/TOhjtuhaiAXCalFvynxpQ04oOCZ2UNcgKNfbCwch3oG2m",
-	"svicESqb4UX/DvOtMX1tsL9i68pb3p5+2lLymRbRLcj1RQBMEdwTIVVkd3l18uHNycUbdCkZV5FflGIh",
-	"0GtNotcuwdgvgeWwNmJyl5sUPugakWQKviqwIVnOuLQlGF2VjJHaggoJ6IzOCbV5d++aXlU5uCbUqlAt",
-	"iExs3v329JNCJqU0Hy0SEiUKbxRoNiFR0zJZvGZvZOmh85kGxxVGl6Wra/oiMtsjD3BOgusiDIeRytv0",
-	"J3iBjDJKdgiLWuVASf2Y0taqNNlVpZqiaa8VKKo5aXyf1pQrWV2/M84yq09dXK9UidV3EmvqZQrfQ5cA",
-	"qKxdRCkr4t6csXkKunIhjOnooka/KmDZmmBdib4WMStSSQIredkdRSkTIKQSU3UyxYRr+tLWqkrzNIZZ",
-	"DXul1BwlTABFuJAsw5JEOE2XbSVD8YhyfauIqHIhNiv1oueNyu5KXk2lacku89Xm2bumZzhKSiPRWo8Y",
-	"lZhQhCtN8XKLtWyQkryHftUSmGKBQJjD8TVFKEAv1N51/B0yTFISP7w4RicU6W8IxzEHoUwQSxWLcBAK",
-	"P1e8IkUCtabVQz8xjqz2fPQCpySCv9vvas1f9CxnAfyORHBixj1SBsPakljHO1sGOvQIcJ7/Hee5yJns",
-	"ze2gckxdJF2Aeqw27PzLaraSq6WCOCNUOHUQswwTevzd/FUMtXuiy4JIQOZX9DLnJMN8+arLPE0NQ12G",
-	"V1GIWX0s7di2Rlau9wIxjl60ZHJ73WbTJMKMMeCgDBVhurympX6b3vRFB0vHHavwfK9lD7sunud7Ztm6",
-	"avZ8zyq4/uMj4vV15192E9u4xz5dcdL37HY0adcIsYiAxpjKYMoxiYNhOBzvDbdGDDVy/rZaZ6Om0D28",
-	"41FCJERS5XIN0e4P9yf7o/X7vPl5h9T8apmDTqhNKWvbmI+XV6qXnnEzxXqCJMHs9hOW71RIasZanfPH",
-	"uuoaWmmJflOuwjqLgjJN2LnQUIW+jy602BJFpYrdCDQ8Yk19ozXNR9UOlEeS1H40kpnP5amcLTB0bLFm",
-	"YTVWeKHY4IUIeFIQ+zHB9W8C59XXb0YYc0Bnf4R4DkFVRrXf9F4NvPyBUCFxmuof5lGu/lVeVsGA/tvo",
-	"dSdyFa45p/KLLe41baOLMz9BzDgOTlUoFrzGYk3wmIJqaowchIMwPAoPeqEzIAJ+B7w5ooz7btlX0ptp",
-	"xhZ7eozP9c9JMW2cYvDUeeSGxW0b/UYDVyp1B1x0yrTD7WfxVvwVK3s7ZEVxpRUXTlanFA7AV9unLbdR",
-	"XYXvJJlUm4ntuY78Ov/Xtr+Ldlw1hjLxbpK8JdRdBygv+XQVXya73RbJJE5dTS0taKZ+dTvIXMoxg/21",
-	"ebjvWcTvzCHHHBoJtS2BKJ+02hJFpgIr79g7sWce6PyNWnJ7aSgc7Iej6SDG+3A0Hk3j4Wh6OD0c4MPh",
-	"GMb44CAeTPfD2Qwbl5m1SU45plESpOQWkGpeEeYJpP3Dvtkk+woN6qtT98NZ98ChNdAxbO3VkK7yWjWj",
-	"jhYTK0J3G3cbyBrLcZWN7XprDq6FbZ8GOaMPpxCQszUtJR5uQrwutJF5Fo/XNVFcRj9roklHQw2ltpyc",
-	"m4BgLRT5RgmVjGoXrcUwXazAAqx1dGE6immPQ5xgc61AbVhAZT8mQvaV4R2uLE/RYaLPRH8H9I4SiG4n",
-	"83xem++UsRSwLp/O8/nkFpZuK9Nl3okQqXtsBhKnhN66J5QRFegIx9ZTjvtRhVo/mPZgOLguwnCwr1T6",
-	"QxXAbpudYZJaHG0KUcmgmnsRUMmE5v+jXcAfDgMV1uKsxhmrf/dH5hctn9qmP17uIEu9hu6s4hE6Lwvt",
-	"yBTaGUe1o4YlwkLZtEBEF1RWyb6u31/TlznJISUUXjlr+Z10T7d6Knp/3EEJT0TmWvJ2Sqa6ucDjslXP",
-	"byFHJMmdqUtby2veiISIgwxUU03nORZiwXjsUrzyh4nTsbp+tcM6EirIPGndAJW8AN/hA4zPMbXnP+2I",
-	"bRQOB6P14VpX5Po5SE9ptyb51iCqIYnf1nKDaU1ltem6VrJTsmYUdjgjcN3SffC3jmlf+dw2pFNT38qj",
-	"e/NSHyZsrhqwPzL96lB659nvOKJd7HjE3MsRNztnofVxVRq6SxnBDLR1hHWH63ajLfXcXpFHpqO8oHRd",
-	"zlkXx5V09sSwSghNbumkIuBJjwZ1Da1ZJ1mBgm503lrvXJdoo6kQSQDxYDzeO0InJycnp8MP3/DpXvq/",
-	"b873PlydjdVv5x/421/O+Pv/If/9/v3nRfEPfHHyc3bxjp1/u5gNfnsziN+Mv4Wvr+77+/cuIbpJbiGA",
-	"b8/51hS9bvR9f4gKTuTyUmnQqOg1YG6UPtWffipB/Od/XpXPBzQ0m34VXbULmEcEhM5Yd1u+tFVsyexe",
-	"qk+TTNpviqyi5/leSiKgJkK17xZOchwlgAY6IddIXkU+i8Wih3WzDjfsWNF/d3569uHyLBj0wl4is1Sv",
-	"IZFaaR8vX2v2tlTEkT6uQTgntdDz2BvYA2OqGo69YS/s7em0TSZaTX17yKVBjAnHaeIpBywBYURhgWxv",
-	"H+VMRZsEp+kSRYwKe8zIZkjAHXBc6kKrx5676dcf5tyHcBSDGmLPkOqHz+exd+x9YkLaqXnGDkDI1yxe",
-	"mrRQx7rao/I8JeaMqP/VHnqvnobsUGOrbmA17U1t3+a+dc6ovaE0CPeemvt5bBi3VG4aUYIFEhJzCbFa",
-	"xlEYPhl/W1Ts8j6n5vzLrnR5p9/w3/vz+Z8UUhnJLVB9ccRIY7gP/3zunykuZMI4+WZOUnPgKvpDlXEa",
-	"SUZ/hSS3lC1otQ5GCeO/wgQ+U7jPIZIQI12tRiyKCq7coo61ehsrUfbLzcONXyunWNAowUWNK5FG9L+T",
-	"+EHvYq7LC29BmoNhvZPrawzIbtCIcU0xBSWaJacPt7WlRGkRg0CLBPQNJsb1UZeiVepQhwEQQ9zFm7cg",
-	"m9ck/cb7ui/utwMVYSOsZGiur0vod2sKY1fP1uzduzq+1B+xPflV8psOeIVPDV7VEUHHgpp6+ZdhVwkc",
-	"z7D1DFs7wdZVC3jW41c/tccHvwfEZoQSkdQwDG2EMCJXyOXrgAqngqEMJEYqSFVAQBhFeMoKWT79KlK5",
-	"CeX06cczxm3FOPuWpWNsylKUCVSX28xzySo+JhRRpqtwJCpSzO1tHvRSJqyYJ/aS0c+XHz+86rnxUcK9",
-	"7OcpJi2hHc+dd0PB0VMxcPn4Q92N3uqbW/OyXllaucuNGu9yNvpS1XMHd7oAWXAq9PPTcpwWRqcg9ioM",
-	"rb9Z7SF9XavqHDHtWKK8p2aXL4YZoRAjLFE9eWNC54KmWI1p334PSnK98QZXXL13evbHrf64UtYap2ws",
-	"d8cx/zN9rekeOzhd7ZBus8/ZjsblOn5m7pXCPY5kYyPi2v0gRjHkQGPlh3VfKx+fm9uOmzyjlPPZMbY7",
-	"RvUMb41flEv5GL94jtGfY/T/bzF6B5tceKeJ12OKDsSsHrJ0wMU1s1WXvr7ysu7wpNZP34n5U11/NQeX",
-	"tZvHvWyGrDKe3exf42bG0P/9nAxXBoTTFOVMCDJNobKmlZttL+hhao5IaFT97yRGstW7m+kS6a3T7ai7",
-	"RQAV3T+66w//4j28WspnH3320cf4qBlbJ639sjrwW7//fbRd3FbdFNaS096q8malA5sR/ztGDhun81Bd",
-	"t3HhzHv7xIfFRWTepVVXkZtHujgnPcVHJMT+vz84J31zSVzXBoAH5fvC/t1AxxOtg2aJ54TONzEQEs/h",
-	"D7LRSqTlE6SKzTY6Nw//FwAA///Ls+uMj1AAAA==",
+	"H4sIAAAAAAAC/+x9DXPbtrLoX8Ho3pk08yhZlj+SeKZzrpukaU7bJBM77Xm3yvhA5EpCTQEsAFpWOvnv",
+	"b7AASJCEvlI3bd7xTKexSAJYLBa7i/3C771ULArBgWvVO/u9V1BJF6BBul8zMP9moFLJCs0E75313tAZ",
+	"EMYzuO0lPbiliyKHxuc3NC+hd9Y77H38mPSYafNbCXLVS3qcLswb/DLpqXQOC2qa6FVhnistGZ9hM8U+",
+	"RMZ+VS4mIImYEqZhoQjjBGg6J67DEBrfQQXNcLgWHvx2Ezwf/Uvs+vzni+dPR++KXNDsNYJm5y9FAVIz",
+	"O76EGcL8u4eqd9aDsr8EpfuHvaQ9RNJTcyrhasn0/IqmqSjdklStf+kdjo6OT04fPX4yPBz13ic9xEEE",
+	"3KpzKiVdYd+cFmou9JWdcAjTYtX3b7tQfUx6En4rmYTMAODmFIf1fdVaTH6FVJtxQ0xdaKrLCKLogjUh",
+	"ogvWH6aPj4aPnhw9enRy8uQkO57EMLYniluTMeNWfawB/uLoblc5js8tg69DXCnz+N4JhzAfRfvPbpgS",
+	"ctXtNr2x/+5OXSxrIuDtdxfn/dFwdHg2HI2itM6UKqHVyjToDw/7o1MyHJ7hf7GmBU2v6QxUlzm8cW9I",
+	"KrimjDM+I3oOZMoMp6qm898Spr2z3n8d1KzvwG3uA48V11V0K8ENSKZXTeBfLgohNeU6BrJmOocNmGxP",
+	"REFamiESMilnU3abEOBzylNYANcJEZJwWDo81FwvaLiV8FjmP/HQBYjdRC4eMd1dLNN5dIpQiMYbxjXM",
+	"QJpXnhlFNnYOVMXf3YBUbsttniP27sev29W9Jxbo6HQ/lBK2bH22oDOoGGpLTtEFGCllyK/EbiAj2GBA",
+	"XmqyKJUmEyAlZ7+VRpjihzN2A5xIUKKUKZCZFGUxGPOXU2IGIUwRsWBaQ0amUiywiZkvKJ0QSiTlmVgQ",
+	"wYFMqIKMCE4oeffu5TPC1JjPgIOkGrLBmDdoZrHqI2Axws1FSrVDdnOCP7g3ZDkHCQgL9kLUXJR5RibB",
+	"vCnPiOF0SoPE8b8TS6IFyZnShOY58cOoszGfa12os4ODTKRqsGCpFEpM9SAViwPg/VIdpDk7oGZ5Dpzk",
+	"+ccNg+XX+Kif5qyfUw1K/xf94EXTlRnoqhrkQQsBhldDaZY2LmPsclzhcmxe6ebS7YCa9lpcijKl/K3r",
+	"5gWOGIFJlZMKhCuWdYF6+cyAFH72CcAcw0n2eDJK+3QyOu4fHx8e9Z8M05P+6eHoaHgKj4dPYBTldsAp",
+	"1xvgMkDYj3aDypHLlPGMMO13C25R8sZw3XwXuvE0o9kN9DMmIdVCrg6mJc+o4as0V523/blY9rXom6H7",
+	"FuQWkk7SRzA9mZz2D9Ojaf84o8M+PR2N+sPJ8HQ4OnqSPcoebeXGNca6a9uhwGBXbuFc6/SGJuPahRO0",
+	"pUfdQQyEp0auKnAiw8uHPH897Z39sln+vsZO3sIUJPDUyN828FN2CxHKqgcjy7lQKPRBESoNd03zEvmv",
+	"JZzUgrevShDTBUpeUJ3OtwA0FZIs5yydh8N7JUURUerMMGZSCeC7hOzKq3NN8J7+9FwR6fGckcnKyirf",
+	"jNB69ZLwDPL0p+eo2vWPDk9O9zmEtGjIrmOIwg7QXdp6X1PXMzad3iVdTfISCsm4juBqTrlRKx3rqr5E",
+	"wca0ImmptFiwD1a+7Lp83zLIM9t3bAWFnoPcwkVrWloUVEJGtOglvamQC6p7Z72yrPW8dSr0Jgidsoeo",
+	"xmZSM+RLmk5y2Iqo6nuC3yfEnBdFqYMXRj25K4QpHT8WXGgaALWgnE1BabcjlyCB0CyDLCESFuLG/CEk",
+	"SXGYbFfQcIx1oLUov1rYYCGSgP6qmXQxvnFHPL8BS71Nwv5VTKJUdDkH8quYODzkYjYz9DMHkjMOahcy",
+	"sh92VUPGa3T/KiYPFBFqUrI8M6P09jJbVBJsE/Ld/F8JzabMikYn+j5+XC+eXmZ3yT7aR+DD0REYW00f",
+	"Hj+Z9A9H2VGfHp+c9o9Hp6cnJ8fHw+FwuB3H3VPbRgr4gSm9+6Tw68hM/OrsRPhuZI/vLaRvu9w8BzG7",
+	"U33B6ipIfiHNVSR2LX5l2yb5vfiVIVxxRch1vnFaPzq2c6dzW4SdbkR8/eVmKEHTjGp6l0AKpSXAVSoW",
+	"C6ajwuyrOVXzh5V4LVmuifv8U+w/9lxptT5jAnr1/Ke357syctdHhYgYRa/HX4QFGa7Ay4VZhAK4Acgw",
+	"e0Mx9k97zrF/qzJNQSHvpSwvZcjwawRExrpTqsogZ8bEBbtzgRCWZ7b5aiszCMbZSJRvrY0jYqhsql3b",
+	"OFXz649JL2MGqZNSdyy4cg55/3HUcIk7XtYgbRrypfnYg99uvDt22918Kgsz3y5hMhfiOrJ73r39QZlj",
+	"9pvXF5fm3+UcuDF14qa0grijdlp9b0Au5zDmE5GtjJGKkn9evH5F7EqizuessJBnirAs8e2vzN+uZ8qz",
+	"MddsAQPiZorAeHBtN5QoSCVoPNYpNuOQ2RdMn5kxxvxffUc0sn/BZpxqYyOYA81A1geucU/N6ejk9Otx",
+	"j0xFnotldQAa8znc9oGnwhwYv/vx/Gn/4rvz0clpxZ1Ethrsykx+ttBv3wkhIQaUX7OQu9vbWVnkZqdC",
+	"dmWsiBF1GdAswwOrzJIqwoW2rDkhE0hpqYBQUki4YaJUfkXH3C5HBlyzlOaIc+CafOW5dtI6LNkzlBll",
+	"zA2OHuJgU1HyzFhKlYPAfGU5JZGgylwrq7lLKFXXcnU3ypffr7spobhJazUobAp7bvV1ypTd6TvCYzZ8",
+	"3dFeirRt9hP6Tduk6jpq4WYj/7bdPZdSyC4Pz0BTlps/Kz9S108ggapdLP7OXIYfdwCw8wnEcVfaJpWI",
+	"ft/wqlQfdmVxRwQ1pwfcnNuyq4XIyjyms/yIL4jSEugCWZ5tYrdgBoUS+Y13ZPlN5JmRdSiM+eW87sGw",
+	"RporQSSkQqL5Xbk+KwuYb7crH7NAxghyynJQK6VhsTOJf1s3iXYoYUnzfHsv7ruPSQ/torvvMWtWj4w9",
+	"F0rHrKLV8wgBXIPksBXa7+1XzqOSw7bvf7BftbTdwApXCKVn0mpOux+nC7oyLPRKQiEU05VttkmSz2+1",
+	"pCT8Bi2YFfGpAlI2ZZacmtwcNQEjBhqtlyzPieD5Cu36Cs1UnrTBeYe0ZHBTU/iYmyENrb6+IEwryKfk",
+	"Kz2Hle0MhREQekNZjrvFf23NDFIITYQcc8pXBM0taHIKTxgZKaQwu/ohwuwHvlKglVVVfJ+d6TBF2IwL",
+	"6eXOTkT31vewivuU5Q1LtxvkLvx3LSfQ1nbht2Z8toAPgm+lw0v/nbEoKxcVtNN83ymQ3ZnGzDGVYLgr",
+	"HcfobtHtUMuajq0+QysbzQnjViswxkk6McZKQzFgYEwIDGYD+/s2BTC89bdS2EOiAYAG/riIH3E3GYbQ",
+	"V5+3Oo7LWcTgX2D7sSv3R2w+oUW3Izvj6imK8ZYrp7Z/mzYJWTCljMxkdr9jBBjqlWjotZxQzzc4tUXQ",
+	"EM3BsCj0qlaL8YUiGZtOQRoBS43XKYeWHtpijlZWDGhhtIyo51TsO18tNszW2bM7K4KTjznuvm1I85at",
+	"ifErH1JXzfBwODpOIgrbwrjdC+G8KdXnvYMbKrdaOYPGST1sHN5aWWhCWwjZOmAHcUajM51G/ep/nC3H",
+	"GNyLp2+2RJJMyvQa9PrYAsoJ3DKlzSpfXJ6/enb+9hm50EKao1GaU6XIN9jFoB3Z4X703QhrzzjxKBYj",
+	"GM0bI6/Ngc9LWYbBTi6yA0MBM2J07VIDec5njDdVU/zbdtQKfDGnRUfaL56+MSLZIC1xPgmmUFto6gLY",
+	"lzsK2rOjgWVATJSM0IFy4iNixvxB6m0CtGD9cTkcHqXmyId/wQNikeGHM9tZN6DeJ2KmjgfsotJM0b4P",
+	"4h6qOaFiMwmQq0WIX8PZHD7tDveopOY3y7B3HxkwIBcAxIdEpLkos8FMiFkOGBChLOlgrMSBb6NcqFGI",
+	"xARBXJS5Zn0Huf+cpLlQoLQB03xkYxTG/Cv7R0WeljCrZg8NmtO5UMAJLbUwojaleb5qIxnKPWJk2w4o",
+	"hbqewwvOm/jPDbzYS5OSY+SL5DkY8+cmwtgRCWLdWZIIrTAlvchwwxAD+YD8hBBYjQKPZmdjTkifPDDK",
+	"1NnvsKAsZ9nHB2fknBP8ZUSUBGVIkGqjhEtQYMCuxkpNF6Q1rQH51kgpi72EPKA5S+F/3G+z5g8GbmTH",
+	"085tuz1hsEO7LtaNvVj1Uefu06L4H1oUqhB6MHONfJsQJDy/7YsNN38fJGfgaqEgWzCuojjIxIIyfva7",
+	"/dcMiNuTXJRMA7FPyVeFZAsqVw+7g+e5HRAjJhRIdyCn2rVtY6Teeg+MMvGgBVN8120mTWYtco45WOso",
+	"X425x29zN/2C2vtZhyp6Sa9FD7suXs8du8+6aO4lPYfg8OGnR4pUQedOiL3fJGPvLuYp6TlxdNX27VKV",
+	"As8o1/2JpCzrHw2PTg6Ptuo1QXfJthCqFz7YsDmLWQuUw+HRMNkQWFvDjIu1PSB9LUANH0Q0+JdpSHUp",
+	"W+PePj69Oj1er3jYxzsYRS9XhT33Wn/itjavLy7NVzi9prHjDo7rVv24EsVOjqem8tfGeAN1Day0QH/v",
+	"V2EdiYM/SO9s4q0OcHubuJ1xuELFbh00tugay3JrmntZbdc6Vn30sTPtdmgxoLBgKLo0w9Cl6st5ydyf",
+	"cxr+UrSofn6wwOC//iFkM+hXvmz3C5UHkP4B40rTPMcHMzyYzMwuq/gS/tv46kYVc1jjHf6+Mki2tqg9",
+	"dXZtH3JWLqxYwy+s4mkEkD2uoife6KE5481kAy7UQn89FTKNcs/tgfFuAGd6M8O6SeK/QDP/YQZTWuaa",
+	"iBYEtoN+BpNyFmVtHTb2vXOVNnHTZZffQiYk7T81mnP/G5sssClLoZHFMhw+GT4aRJNXjCAF2Wzh1XTj",
+	"3BlMcWAnKgZCzvDxvJw0/FUyj3WuqbpuC6vjUUw+BDkUNRxH28WDA78eKvFZFt3sivdr0O8DfNry2Wg7",
+	"zg3EMVCkPTg+TvyX67pfxx2RM+yGHc9Yooc4paEg1IcPUmI+hoyYVXKGZftkQH421iI0e1esaMyFdIqd",
+	"9+ng7laukdX8gr6YIjlMNREFcFJyzfIxDw1B1syjpT3wTmFJNFuAcuqf42PYVcURYygMaVrLVTy68+c5",
+	"oCm9BeKSBjAmRAmrCYdQppSTCZrRLaSorh64t+rgd5Z9PMCBLdwOqIkQOVAe38je3Nlc5GvG49ZXn5ra",
+	"XWxv0+q+0ULTPPaqRZc4aFLltNpUUts4WWv9THo/VJ6g1hxgNRFUtnTOMup+zCmfld49FDF1Ab96dzF4",
+	"d/ltPJhlu3Heef524JjeI/VblDVZ72QrRPJoN3W0ah3DYjTyqCv8tIZFoVVsNRMfiAQh6VTEl/TAxNU6",
+	"zrE9JpYqfeWGazTIqIa+2Zxx9PzBONdkt2TQaipJ7ci3wqTCUIiO1nSMaub06u7JpJhdXcMqol9cPH35",
+	"sk/lQpiDalFOcpaSF29ekGtY+Shx4D7gr7JqBdE9DaYQbmkJDfuyc/6YbtxqqXJhTvAGCt//y2dGWLmU",
+	"8OHodHg8GWX0FJ6cHE+yo+PJ48njEX18dAIn9NGjbDQ5HU6n1DLGabvLiaQ8nfdzdg0mlSLo2MSPHTw+",
+	"sIefA6Plhdsr5LbTbuBZq2Gs2Vw5zwjqRb2zKc0VJO1wyDLPIxjG3MFS5nVkViNPbEE5nYEc869SyrMc",
+	"CsYfuoAevfIK2VLIa5APFJkLpc9sDoTgqlyYhilIR6AQ2lTTnCEU9dsB8cFaJBOg+ANtTEqglJVx1eln",
+	"5TzATrgwRRxNGzuQmYlRT02LdA7ptSoXREzHvDtzqtyDAXmNkiyVgBOjuUoILqP12JCn5+g+7oJs45W0",
+	"uAauEoy1QCtMWVhbTVx+GaEw49sXzESseblp4K0XiM0wmfkaWisQH0wbm+pVBrmm2wd94fJCjYERGxJs",
+	"WKeYNnHozgYclu6JcbJnPscqSM/zODfH2xsX1Vcv2TpEreViHcbf9gN3ONLcba6u4SGuIqzRHWIhRk7i",
+	"4wgxoRTmzXRlEfogdzVEuK5+cup1xBjhM1U+rcf16TTee3hXkLbtHoiGeph6Ihsw+vy2yCnjd2qH+pQo",
+	"4EhY1x8MpPpUa9gO1RC0MGZ0v3NxIMft/FZtRmQah2OQJBmJOvot3y/u6A5McAvGX9pWh/sE0yZ72Ne2",
+	"kB2vMuF3LHyQzilb4wlUcyE1KE3wG4NxNwNriUHeS31yf50ZSrQwco0pIjgkvvKNfWU78KdKDrdoLhnz",
+	"tSuY9HI2KX7bbyU/rZiDj3xpnydXJONTb/RRYYhjQsbossAIbdnFRCMptZcBWq54utpipom8cx1fTVbx",
+	"pfL4VQWklR/KQ2Pj2qXS1Wc+Qh7Xfh/MWrfp5lP3+qGcIcDDZTf2mLsnxulkiaYROYpOygeqMq/VwXeB",
+	"8cB10dw6McvBndfl8DuotUq77dO4fSCM8ttXsF2ADvrfKt8aQ22AOUy5+sSKKl2b6157QLHZIjtZ98rm",
+	"UGwsXvPppOD48FobYkUJDsb3Nd5ay7GDhUKobelk+5DDPrTg5rexwE5LfdqdGtauebAKrZAy+8KLf2Oi",
+	"IL8gUZ29d4369QoEBpuzw8HhaDAa9k8G0/ToZFfzjVtDD8/22a+LCFyLAyMwr9ZT3QYkaXG1L+Ny02mM",
+	"2egoNsFAm+mapqkCd/DpegXSjA8kZHNqa5m4hJoDo+UcGAX1cW0uMP0IdSDUwQ7OAjwrX82KWdzoFdhz",
+	"Ok1t9POVUnm87QI0zRm/jk9owYzXUUU8Hb7dPyQU4mv7vn80MmFao1OD9a8rLX7b7OwguRMCTSAqGMzr",
+	"QQpcC4Xj/8MR/deP+9biGIxMzf9Pj+0ThM94hV5f7ABLW+h0HGJGaavUDIw/F5LQ0P5BleHEitgDeB0K",
+	"hGHtY/5VwQow/rmH0RD3TjCIdwiIffMHpLiNqElvzGMEDK03pGW80XMpytm8LnzFRRVCNeaBDWZAzvO8",
+	"mXgQnkWcy8VnnpkxTXdjbp6YMRX6FnknXs8tOjYZBDEpZ0eHo8c7LKA3t0X8Eo04FfPZ5r3/HdBcz7sc",
+	"wBquPuFoZDt8appHM1rwdWTJvqW5cjlGmCMxDexnlS9qSaUxO7kqOjblIWq1WTgd5gqdT5ouiqgey73p",
+	"CJQmvo0fOyAYo8tW5cp6yY42dMdBuxq8Y68JqXc9xnR7dtOFYKtgs4TisZv49dtl8e1adcOrQammj6or",
+	"vGqVnKZz9NHVfNoeZhfZVa2yBetien6/0e/g+xbXvaTnFr6XuJiSpKeuWVFAFulkncdmTV5gFyFxTX2/",
+	"TIR2n5+clNAFb2dbUzuson7bpC7mylKhXXwqZGI4J9wWxoz634ZWnX7xKbarJgzPgrd7wOCkoHX072IN",
+	"uwvzDr115p3R8O6MPVvtOxdBgH87C9q+CTIyhSQZU+ZPG9/pTuSYgYCOeyOY3GqbbaDGXEyrLzIfNqsS",
+	"m5K2ZAqP4zbhIjMh8dqJ6laKrB00W+PjTUV6XTA9UGJdiH/HiMM39afUPPs0Z3FYBCki4vzzKrxqBwtw",
+	"S8UPIu46o9uK1GtiNlzILC5NX8/dEywjrdAM4g9DXpPqxQJDqpc7nCu12AitPzsH3cjZwNVJGshisT2c",
+	"tAWpx4BHY5zgW0mDLZZmyh/aZDOn+zfLREMqQfetuKmEckGVWgoZpRnDzq6iR5vuyWYHRYxxxWbzVlls",
+	"LUuIKSVCzih3Ru12iNbx8Gh0vD4+qwty6CIdGEUvgHzrSjUgSdpYbgwaoCyYbmwlL4MUzpblQxe2x3VR",
+	"IVhmoxmwvZVnhBmjdU/PsWzqwTcgc8Z3i8DrpEIJDjskLMZKrn9Mtra5ONqvSSdXa+sY3ULBmNm4ORpd",
+	"/JHpVzEfO89+xxbtIPo95u5bvN85mCVsV0UT7+L/sg2dA2xddQrHET2e2yuyZ1SxLDlfFzocghOLHR6o",
+	"oyqu14YIR3tRcKc50JGSCNtzASL1RtpSQKl5H7LRycnhE3J+fn7+9OjVB/r0MP/fZy8PX10+PzHPXr6S",
+	"L75/Ln/8v+z//Pjju2X5HX17/s/F2x/Eyw9vp6Pfno2yZycfht9c3h6c3m4KHA6D30B+cu6CWXtfiKez",
+	"Ea1I62oN39eRFqYIEKlOVfVh0RVx2vUkitUZrdaNMaFVlJXRMF1YlMt2ApWgjUQLkgtRTGh6nYy5Oar2",
+	"sXyE0UQLaWSI6cv7azqGtoDDH5jZqyricqfz7fuPH5OqoPyFoTyLsm+ASkusE/zrWy+0//nzpb9DA0Wx",
+	"/a4ayoBlb9IwOfZrNW4za2u9snElLiAco2QH6LRMgVtPhiWU3nlB0zmQEUZcI+4rFCyXywHF12jgc23V",
+	"wQ8vnz5/dfG8PxoMB3O9yIMi/b3XF9/g8FVsEqZPElqwwIZ91hu5rH9uXpz1jgbDwWHPJpYjmjyyzd+F",
+	"ULGyshJs3I0LpjFfJ6QQ2prD8hXGVLm0X3NQMEcy6nERlkRCR7D17zNJMjBNXE5nWEHAVLzsvRFKP62c",
+	"+46MvxHZykbPoXXZ5QvkLsjw4Ffnv63vR9khTLEqWNYkLqOu4QNVCO5KI42Gh3c9+svMDtxCuX1J5lSZ",
+	"I4DUthrA8XB4Z+O7nJru2C+5zUf11kxZF3Q7Hh7++eOfl3puI9ewgomFxo5+9OeP/o7TUs+FZB8cxwOJ",
+	"fqeKOC0kx58DkmsulrxaB4eE0ZM/f+jLMHIfC93bQiKE2kIizeL4tjCNK1riXo05WuRcVv4cbLukioB8",
+	"C1qu+udTDdKXvVN0pQi1T8RyzBfG4KsgFTxTRHcgmmBeLxTaWIBLnoNCt4MFVFkYHLAcT89iyQfWGJq5",
+	"I0cARBNpnUB6g6KTz7H53nG4LSDVkNlaLkSkaSmlK47hpRwqXl6+/fL+4/skiPd17NqzddOuFqhG5Yrp",
+	"ES9Ar6+fqMIa1HXwxmQVEAHakMxPWm3fhCyE0kRCClwbKYGAZTY6pMvwX4AOywMnjTu+1iia9ScHhb0G",
+	"Z+t3mPfwMWkj4LXRdbAgaz1pG9bKFKmLycXu5PIv9+L7rdp1u4CDa+BVDhcZg0hn1jS0Br5GJNluMIah",
+	"ezvB5sFomTlj4LQ+qQHabsHdCRRPZRSjiSw/sSiy/pgYTK7NFX69BqjR0NyWcdwfHl76W5/+d0efz15w",
+	"T2AqJOwMsv18I8wnnwTz+47uM7xr3ccWd+oyQc/oq6qUn1v1KTCyz3AKQ0NTlmuQ9/pPrf98KaLwcg4t",
+	"HuXFVVMsYmafKykNOuIle4bPTeYi40zNITN0kVKegsmprM5ERs34VUxUdeEGNQkZNEV/yWWdRMl0cOmU",
+	"P2g794J1dv/bQnJlG/wbb8ZSYMoH2R5cPY3JqjptVfP0VW/FlNRVdLFMnIQxv4ZC2wRNxEZOlS83uDDk",
+	"ZofNElft0EKkDURsOuZdqIgCjWEgrIatKgjCuBbE5LriJLFI85i7Trvy3+K4PvK1xH/sahHPKLBMo2nt",
+	"maU53QYSMOu1T3ZxfnlXlyF0nKy2zqmZeJhLW9GAFuT85wvy/OnI0NWLp2/Wyc4Q/Q2e386d6YSAdNn5",
+	"cZfMw2xdRwl/2bGTZfcc9+9x4hx+hhNnaPDgQtd8dgX6S5I4laCojl/JrgcuSpz5HmWLMCxJ18IFBQhT",
+	"PoHNHAdsvL2Q1gYbHJDRSQAZZJsOWRf+2LIHn3XAakHMnP4WvPYzqKlVHZgtiuo9q/xPZ5Vfkl4cMp6o",
+	"rciWu6CNyxGjrOxb0O7KwLLI0FNQtSFfgZRU04cEQUQzEPqQkLfX2T/rImmNFupqyFoVtUo1rqp8m8xe",
+	"f2PGgJgjpbUW0vCiRSyBIu1li+37FRN3p8e8Dt11+dL1DRIclhBUFneGMAw/y4hiPAU7k1CBwksoNnHg",
+	"8/Dmwv20XYeT/xgWHKBqjbk6WG46nUJa1a2xlxV7KXrPo+959BdixndsL0gr8LzP8dIuux3EOLg1U2xw",
+	"7+L7FtOlriQHWjOY9h5eZM+WR87pDfAHesxDVX1AzjtmEdTpXa2pTV5eC8f+rNA3u1dG75XRe0b3BTK6",
+	"Fv+J8TBzgcHB7/5C2o9rVVHPNPVS+O7UWVNfxMtZnMKYNO9Jti/HHNU7e72jvadB+9hsb0VmsrqfV1VO",
+	"bSbrC4PHHC/CVZv0Pyy7shO7Cw/3tfaHwP1NLZ4b4dYiDnVw4fAdwX7492DauNJrFFd7NwfwFE33egnQ",
+	"OJ3cM/J7Rv6Faawh840xcyzut96ccIEpzbgH7Jctk4AiNmugr4Br94kLQbJpmmOOcYb4xt7zGV48Tvyl",
+	"mVTh/Z8DMnYhHOOeHxA9Zdh9R+eOXzBaXbhj87FtzJ6yJceq+0mZri8iHfdyMasH9Bc15OGV5C7JZsxz",
+	"MWsaiPF5YkcxvysobXt3R3oVSWAmE8AFmUWXewYmvsrHSdRT8mr9Jgn23K7kvjLsS1HWNdxqS639ui7o",
+	"XowfEbSO89fUHeLmnt3fs/svgt0HfNr7hSTlitXX5dpYtYp71Ta4rkzIxWy9RNjiK6sMEH4AstFTxnTt",
+	"IEucsUMJzLJfc6uevcl3Y8SimO3IBv+zXWmIpzXs0JBAdXNXw2qbGIs9F/Z0lZY5le6qInPVpinZ4W5Q",
+	"MuL84RoTL/JyLMoYDfWtkLAznzy+qwFiXOBjuNFegK6Rs3kbVYfSrXup+nKH7fQWdCm5rcvm2yEwqGe5",
+	"e354mG40IHgXVfVxKnBjKV/c0S1fBlPGbaxmmAnjs4ux1g7lB+5333c3ONmwFX+sUHC/H7fuxxpZazZl",
+	"Y7l3dad84XutuT122HRBZbzNey4oJUO7+8yGCMItTXVDEEncfpARWz7S7MNwr0EWVEncJKSqCn73G2P7",
+	"xvC4Wrcv/FLusy/utfh7Lf7vpsV3eNN2fseDex22Kxqi1KmwdymhurC0Kc4k7IWgrcUq3DTU+mx1fCGz",
+	"+uJKb/AJb5cIQzyUtveTGm3G3eMy5r4AvjsTbDRqNDr+/9a28Yc5ZBNN64zb7qYTfwi8N3Tcs8gvlkUG",
+	"NBxjYirKxaIcFO+QWh+RgXmphNrLq2oTs/m/yxxITQosf2CvqA6yCMxlIM30C9OHv6fa/O1uQBxU92cR",
+	"DXmuquQNHGbMbaf+0jBa+UPrZH7zeIY+VOf+5Bg04nNJ0ITeBKUqfVlfymUN0X6K1wCFwrwZtjlUBBG0",
+	"d6SIdK3uA0XuA0Xu+fBnTvC4bF6zZ1mX08++JGEQ480xFq8mYrHdoC2meonMlVnWuaAaJKNVGQV3sre8",
+	"d7IKlOMxB4ZWbarIxZtn/yKjASaPPV2lueDw7F/kcHCMFlGSiRRvTsXCxrkPkB7zOoTaXdlaRUbjqBuT",
+	"R755/eNe/PdvGqZiawJ5ZK9fjjX5eG68aCJeTxXZbXA/hfuZ2vXJbnvv714stCpKxXfhdqL76zV0tENb",
+	"5N7LiL9aRmAR6jA0wCbpbdouX6B2v3lbtPV5HC00fnQ4JcL8GSqZ/JmqZD2H2IaxBSyMXcci436n/jU7",
+	"1VL+l3emphUBmYNjIZRi5jzqqaneZlV5hrXKFOVWieFpVfjQQuYuoLClElAniG/U3e18vtL8H1Jnjj7z",
+	"mbBayvs9er9H99mjtm3YNe7Lqp7kevn32n0Sp+omsK473K3mDGJwEOh/X5oqsXE6Bn3+5HUAt5UXO24K",
+	"fAaFErkrG9K4e0dM6ypgWBi+CgK1rkg/hs1utT7kxEZtoEXed2aPfD7JYeN9j2PuLnz0bfGWweaNfq4o",
+	"XHChn3uyyUdtTHv+GlB3deqfVPMzfj/rTqU/h38KEMEtgOvCROvPVCUsHbISg92ALD77ke2+OOgXWxzL",
+	"7QGynK8CZiHxIm0dshfLs8Ik/YN5fT9UlG29kWLSusbFRaOXyiVmLwznqY5VZ1WUJuZnofeguj4oCV8y",
+	"6W/lt9AiBbS/qLzZWDervqI/uFp0YR0Xc7EkIs+67dA10apNgNgxU8usMc5ebp7UAAWWTeM9kWIx5rYU",
+	"9pRFs8EM53sbDOGuBfpzmN+6K4M+M/uLXqy0hvlZQquCB9o3YDHDAp3R0kYJIPlgAh+KwXuGeM8Qd85f",
+	"MjeeEd2gupDS7LDVlSWdM+KPhpt+VUiRlal59NAlKnWqvdOCDUQBXM3Z1N4pQwt2gLy2j25RkH3HF+XB",
+	"zShSrdbcYGT46IYBMF/1Dw7jk54ysbAqmR1mWz/vP/6/AQAm5zF/r8MAAA==",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
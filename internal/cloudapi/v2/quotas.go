@@ -0,0 +1,218 @@
+package v2
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/osbuild/osbuild-composer/internal/prometheus"
+	"github.com/osbuild/osbuild-composer/internal/worker"
+)
+
+// Quotas limit the composes a tenant can request. Limits which are zero are
+// not enforced.
+type Quotas struct {
+	// MaxConcurrentComposes limits the composes which haven't finished
+	// and weren't canceled.
+	MaxConcurrentComposes int
+	// MaxComposesPerHour limits the composes requested in the last hour.
+	MaxComposesPerHour int
+	// MaxImageSizePerDay limits the total size in bytes of the images
+	// requested in the last 24 hours.
+	MaxImageSizePerDay uint64
+}
+
+// concurrentComposesRetryAfter is the retry hint for tenants who exceed their
+// concurrent composes. There's no telling when one of their composes will
+// finish.
+const concurrentComposesRetryAfter = time.Minute
+
+// quotaExceededError is the internal error of ErrorQuotaExceeded. The error
+// handler turns it into the details and the Retry-After header of the
+// response.
+type quotaExceededError struct {
+	quota string
+	limit uint64
+	usage uint64
+	// retryAfter is zero when waiting doesn't help, because the request
+	// exceeds the quota on its own
+	retryAfter time.Duration
+}
+
+func (e *quotaExceededError) Error() string {
+	return fmt.Sprintf("quota %s exceeded: %d of %d used", e.quota, e.usage, e.limit)
+}
+
+// retryAfterSeconds returns the value of the Retry-After header, rounded up
+// to full seconds.
+func (e *quotaExceededError) retryAfterSeconds() int {
+	return int(math.Ceil(e.retryAfter.Seconds()))
+}
+
+func (e *quotaExceededError) details() interface{} {
+	details := map[string]interface{}{
+		"quota": e.quota,
+		"limit": e.limit,
+		"usage": e.usage,
+	}
+	if e.retryAfter > 0 {
+		details["retry_after"] = e.retryAfterSeconds()
+	}
+	return details
+}
+
+// quotas returns the quotas of `tenant`. Tenants without their own quotas
+// get the default ones.
+func (c *ServerConfig) quotas(tenant string) Quotas {
+	if quotas, exists := c.TenantQuotas[tenant]; exists {
+		return quotas
+	}
+	return c.Quotas
+}
+
+// checkQuotas returns ErrorQuotaExceeded if enqueuing a compose with the
+// given image requests on the tenant's channel would exceed one of the
+// tenant's quotas. It updates the tenant's usage metrics on the way.
+func (s *Server) checkQuotas(tenant, channel string, irs []imageRequest) error {
+	quotas := s.config.quotas(tenant)
+	now := time.Now()
+
+	var exceeded *quotaExceededError
+	if quotas.MaxConcurrentComposes > 0 {
+		concurrent, err := s.concurrentComposes(channel)
+		if err != nil {
+			return HTTPErrorWithInternal(ErrorCheckingQuotas, err)
+		}
+		prometheus.TenantConcurrentComposes.WithLabelValues(tenant).Set(float64(concurrent))
+
+		if concurrent >= quotas.MaxConcurrentComposes {
+			exceeded = &quotaExceededError{
+				quota:      "max_concurrent_composes",
+				limit:      uint64(quotas.MaxConcurrentComposes),
+				usage:      uint64(concurrent),
+				retryAfter: concurrentComposesRetryAfter,
+			}
+		}
+	}
+
+	if exceeded == nil && quotas.MaxComposesPerHour > 0 {
+		ids, err := s.workers.JobsByChannel(channel, []string{"osbuild", "koji-finalize"}, now.Add(-time.Hour), time.Time{})
+		if err != nil {
+			return HTTPErrorWithInternal(ErrorCheckingQuotas, err)
+		}
+		prometheus.TenantComposesLastHour.WithLabelValues(tenant).Set(float64(len(ids)))
+
+		if len(ids) >= quotas.MaxComposesPerHour {
+			// ids are sorted newest first, a new compose is allowed
+			// as soon as the one at the limit is older than an hour
+			status, err := s.workers.JobStatus(ids[quotas.MaxComposesPerHour-1])
+			if err != nil {
+				return HTTPErrorWithInternal(ErrorCheckingQuotas, err)
+			}
+			exceeded = &quotaExceededError{
+				quota:      "max_composes_per_hour",
+				limit:      uint64(quotas.MaxComposesPerHour),
+				usage:      uint64(len(ids)),
+				retryAfter: status.Queued.Add(time.Hour).Sub(now),
+			}
+		}
+	}
+
+	if exceeded == nil && quotas.MaxImageSizePerDay > 0 {
+		images, err := s.imageSizes(channel, now.Add(-24*time.Hour))
+		if err != nil {
+			return HTTPErrorWithInternal(ErrorCheckingQuotas, err)
+		}
+		var used uint64
+		for _, image := range images {
+			used += image.size
+		}
+		prometheus.TenantImageSizeLastDay.WithLabelValues(tenant).Set(float64(used))
+
+		var requested uint64
+		for _, ir := range irs {
+			requested += ir.imageOptions.Size
+		}
+
+		if used+requested > quotas.MaxImageSizePerDay {
+			exceeded = &quotaExceededError{
+				quota: "max_image_size_per_day",
+				limit: quotas.MaxImageSizePerDay,
+				usage: used,
+			}
+			// images are sorted newest first, find the one after
+			// whose expiry enough space is left for the request
+			for i := len(images) - 1; i >= 0 && requested <= quotas.MaxImageSizePerDay; i-- {
+				used -= images[i].size
+				if used+requested <= quotas.MaxImageSizePerDay {
+					exceeded.retryAfter = images[i].queued.Add(24 * time.Hour).Sub(now)
+					break
+				}
+			}
+		}
+	}
+
+	if exceeded != nil {
+		prometheus.TenantQuotaRejections.WithLabelValues(tenant, exceeded.quota).Inc()
+		if exceeded.retryAfter < 0 {
+			// the oldest job expired between listing and now
+			exceeded.retryAfter = time.Second
+		}
+		return HTTPErrorWithInternal(ErrorQuotaExceeded, exceeded)
+	}
+	return nil
+}
+
+// concurrentComposes returns the number of composes on `channel` which haven't
+// finished and weren't canceled.
+func (s *Server) concurrentComposes(channel string) (int, error) {
+	ids, err := s.workers.UnfinishedJobsByChannel(channel, []string{"osbuild", "koji-finalize"})
+	if err != nil {
+		return 0, err
+	}
+	return len(ids), nil
+}
+
+type imageSize struct {
+	size   uint64
+	queued time.Time
+}
+
+// imageSizes returns the sizes of the images whose build jobs were enqueued
+// on `channel` after `queuedAfter`, newest first.
+func (s *Server) imageSizes(channel string, queuedAfter time.Time) ([]imageSize, error) {
+	ids, err := s.workers.JobsByChannel(channel, []string{"osbuild", "osbuild-koji"}, queuedAfter, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+
+	var images []imageSize
+	for _, id := range ids {
+		jobType, err := s.workers.JobType(id)
+		if err != nil {
+			return nil, err
+		}
+
+		var size uint64
+		switch jobType {
+		case "osbuild":
+			var job worker.OSBuildJob
+			err = s.workers.OSBuildJob(id, &job)
+			size = job.ImageSize
+		case "osbuild-koji":
+			var job worker.OSBuildKojiJob
+			err = s.workers.OSBuildKojiJob(id, &job)
+			size = job.ImageSize
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		status, err := s.workers.JobStatus(id)
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, imageSize{size, status.Queued})
+	}
+	return images, nil
+}
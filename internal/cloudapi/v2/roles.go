@@ -0,0 +1,69 @@
+package v2
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/osbuild/osbuild-composer/internal/auth"
+)
+
+// operationRoles are the roles needed for operations which differ from the
+// defaults of requiredRole(), by method and route relative to the API's base
+// path.
+var operationRoles = map[string]auth.Role{
+	// errors of the service are administrative information
+	"GET /errors":     auth.RoleAdmin,
+	"GET /errors/:id": auth.RoleAdmin,
+	// explaining packages only depsolves, it doesn't start a compose
+	"POST /packages/explain": auth.RoleViewer,
+}
+
+// publicOperations don't need any role. The JWT middleware doesn't require a
+// token for them either, see composer's list of excluded paths.
+var publicOperations = map[string]bool{
+	"GET /openapi": true,
+}
+
+// requiredRole returns the role needed to call the operation at `route`.
+// Reading is allowed for viewers and everything else for builders, unless the
+// operation is listed in operationRoles.
+func requiredRole(method, route string) auth.Role {
+	if role, exists := operationRoles[method+" "+route]; exists {
+		return role
+	}
+	if method == http.MethodGet || method == http.MethodHead {
+		return auth.RoleViewer
+	}
+	return auth.RoleBuilder
+}
+
+// roleMiddleware rejects requests whose JWT doesn't have the role needed for
+// the operation. It must be added to the group of the API's routes, so that
+// the route is known when it runs.
+func (s *Server) roleMiddleware(basePath string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			if !s.config.JWTEnabled || s.config.Roles == nil {
+				return next(ctx)
+			}
+
+			route := strings.TrimPrefix(ctx.Path(), basePath)
+			if publicOperations[ctx.Request().Method+" "+route] {
+				return next(ctx)
+			}
+			required := requiredRole(ctx.Request().Method, route)
+
+			role, err := s.config.Roles.RoleFromContext(ctx.Request().Context())
+			if err != nil {
+				return HTTPErrorWithInternal(ErrorForbidden, err)
+			}
+			if !role.Allows(required) {
+				return HTTPError(ErrorForbidden)
+			}
+
+			return next(ctx)
+		}
+	}
+}
@@ -6,9 +6,11 @@ import (
 	"crypto/rand"
 	"encoding/json"
 	"fmt"
+	"io"
 	"math"
 	"math/big"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -21,13 +23,16 @@ import (
 	"github.com/osbuild/osbuild-composer/internal/auth"
 	"github.com/osbuild/osbuild-composer/internal/blueprint"
 	"github.com/osbuild/osbuild-composer/internal/common"
+	"github.com/osbuild/osbuild-composer/internal/compare"
 	"github.com/osbuild/osbuild-composer/internal/distro"
 	"github.com/osbuild/osbuild-composer/internal/distroregistry"
 	"github.com/osbuild/osbuild-composer/internal/jobqueue"
+	"github.com/osbuild/osbuild-composer/internal/notification"
 	osbuild "github.com/osbuild/osbuild-composer/internal/osbuild2"
 	"github.com/osbuild/osbuild-composer/internal/ostree"
 	"github.com/osbuild/osbuild-composer/internal/prometheus"
 	"github.com/osbuild/osbuild-composer/internal/rpmmd"
+	"github.com/osbuild/osbuild-composer/internal/sbom"
 	"github.com/osbuild/osbuild-composer/internal/target"
 	"github.com/osbuild/osbuild-composer/internal/worker"
 	"github.com/osbuild/osbuild-composer/internal/worker/clienterrors"
@@ -44,6 +49,13 @@ type ServerConfig struct {
 	AWSBucket            string
 	TenantProviderFields []string
 	JWTEnabled           bool
+	// Roles maps the claims of JWTs to roles, which are checked for
+	// each operation. All operations are allowed if it is nil.
+	Roles *auth.RoleMapping
+	// Quotas are the quotas of all tenants without an entry in
+	// TenantQuotas.
+	Quotas       Quotas
+	TenantQuotas map[string]Quotas
 }
 
 type apiHandlers struct {
@@ -72,7 +84,7 @@ func (server *Server) Handler(path string) http.Handler {
 	handler := apiHandlers{
 		server: server,
 	}
-	RegisterHandlers(e.Group(path, prometheus.MetricsMiddleware), &handler)
+	RegisterHandlers(e.Group(path, prometheus.MetricsMiddleware, server.roleMiddleware(path)), &handler)
 
 	return e
 }
@@ -152,13 +164,42 @@ func splitExtension(filename string) string {
 	return "." + strings.Join(filenameParts[1:], ".")
 }
 
+// tenant returns the tenant who sent the request. It is empty if JWT is not
+// enabled.
+func (s *Server) tenant(ctx echo.Context) (string, error) {
+	if !s.config.JWTEnabled {
+		return "", nil
+	}
+
+	tenant, err := auth.GetFromClaims(ctx.Request().Context(), s.config.TenantProviderFields)
+	if err != nil {
+		return "", HTTPErrorWithInternal(ErrorTenantNotFound, err)
+	}
+	return tenant, nil
+}
+
+// tenantChannel returns the channel on which the jobs of the tenant who sent
+// the request are enqueued. It is empty if JWT is not enabled.
+func (s *Server) tenantChannel(ctx echo.Context) (string, error) {
+	tenant, err := s.tenant(ctx)
+	if err != nil || tenant == "" {
+		return "", err
+	}
+
+	// prefix the tenant to prevent collisions if support for specifying channels in a request is ever added
+	return "org-" + tenant, nil
+}
+
 type imageRequest struct {
+	apiImageType            ImageTypes
 	imageType               distro.ImageType
 	arch                    distro.Arch
 	repositories            []rpmmd.RepoConfig
 	packageSetsRepositories map[string][]rpmmd.RepoConfig
 	imageOptions            distro.ImageOptions
 	target                  *target.Target
+	proxy                   string
+	ostreeCommit            *worker.OSTreeCommitOptions
 }
 
 func (h *apiHandlers) PostCompose(ctx echo.Context) error {
@@ -168,16 +209,13 @@ func (h *apiHandlers) PostCompose(ctx echo.Context) error {
 		return err
 	}
 
-	// channel is empty if JWT is not enabled
-	var channel string
-	if h.server.config.JWTEnabled {
-		tenant, err := auth.GetFromClaims(ctx.Request().Context(), h.server.config.TenantProviderFields)
-		if err != nil {
-			return HTTPErrorWithInternal(ErrorTenantNotFound, err)
-		}
-
-		// prefix the tenant to prevent collisions if support for specifying channels in a request is ever added
-		channel = "org-" + tenant
+	tenant, err := h.server.tenant(ctx)
+	if err != nil {
+		return err
+	}
+	channel, err := h.server.tenantChannel(ctx)
+	if err != nil {
+		return err
 	}
 
 	distribution := h.server.distros.GetDistro(request.Distribution)
@@ -226,6 +264,18 @@ func (h *apiHandlers) PostCompose(ctx echo.Context) error {
 		}
 	}
 
+	if request.Customizations != nil && request.Customizations.EnabledModules != nil {
+		for _, m := range *request.Customizations.EnabledModules {
+			if m.Name == "" || m.Stream == "" {
+				return HTTPError(ErrorInvalidModule)
+			}
+			bp.EnabledModules = append(bp.EnabledModules, blueprint.EnabledModule{
+				Name:   m.Name,
+				Stream: m.Stream,
+			})
+		}
+	}
+
 	if request.Customizations != nil && request.Customizations.Filesystem != nil {
 		var fsCustomizations []blueprint.FilesystemCustomization
 		for _, f := range *request.Customizations.Filesystem {
@@ -246,6 +296,85 @@ func (h *apiHandlers) PostCompose(ctx echo.Context) error {
 		}
 	}
 
+	if request.Customizations != nil {
+		customizations := request.Customizations
+		// only allocate the blueprint's customizations when they are used,
+		// some image types don't support any
+		bpCustomizations := func() *blueprint.Customizations {
+			if bp.Customizations == nil {
+				bp.Customizations = &blueprint.Customizations{}
+			}
+			return bp.Customizations
+		}
+
+		if customizations.Hostname != nil {
+			bpCustomizations().Hostname = customizations.Hostname
+		}
+
+		if customizations.Kernel != nil {
+			kernel := &blueprint.KernelCustomization{}
+			if customizations.Kernel.Name != nil {
+				kernel.Name = *customizations.Kernel.Name
+			}
+			if customizations.Kernel.Append != nil {
+				kernel.Append = *customizations.Kernel.Append
+			}
+			bpCustomizations().Kernel = kernel
+		}
+
+		if customizations.Groups != nil {
+			for _, group := range *customizations.Groups {
+				bpCustomizations().Group = append(bpCustomizations().Group, blueprint.GroupCustomization{
+					Name: group.Name,
+					GID:  group.Gid,
+				})
+			}
+		}
+
+		if customizations.Timezone != nil {
+			timezone := &blueprint.TimezoneCustomization{
+				Timezone: customizations.Timezone.Timezone,
+			}
+			if customizations.Timezone.Ntpservers != nil {
+				timezone.NTPServers = *customizations.Timezone.Ntpservers
+			}
+			bpCustomizations().Timezone = timezone
+		}
+
+		if customizations.Locale != nil {
+			locale := &blueprint.LocaleCustomization{
+				Keyboard: customizations.Locale.Keyboard,
+			}
+			if customizations.Locale.Languages != nil {
+				locale.Languages = *customizations.Locale.Languages
+			}
+			bpCustomizations().Locale = locale
+		}
+
+		if customizations.Firewall != nil {
+			firewall := &blueprint.FirewallCustomization{}
+			if customizations.Firewall.Ports != nil {
+				firewall.Ports = *customizations.Firewall.Ports
+			}
+			if customizations.Firewall.Services != nil {
+				enabled, disabled := servicesCustomization(customizations.Firewall.Services)
+				firewall.Services = &blueprint.FirewallServicesCustomization{
+					Enabled:  enabled,
+					Disabled: disabled,
+				}
+			}
+			bpCustomizations().Firewall = firewall
+		}
+
+		if customizations.Services != nil {
+			enabled, disabled := servicesCustomization(customizations.Services)
+			bpCustomizations().Services = &blueprint.ServicesCustomization{
+				Enabled:  enabled,
+				Disabled: disabled,
+			}
+		}
+	}
+
 	// add the user-defined repositories only to the depsolve job for the
 	// payload (the packages for the final image)
 	var payloadRepositories []Repository
@@ -289,7 +418,20 @@ func (h *apiHandlers) PostCompose(ctx echo.Context) error {
 			return err
 		}
 
-		imageOptions := distro.ImageOptions{Size: imageType.Size(0)}
+		// osbuild downloads all packages of an image through the same proxy
+		allRepos := append([]rpmmd.RepoConfig{}, repos...)
+		for _, pkgSetRepos := range pkgSetsRepos {
+			allRepos = append(allRepos, pkgSetRepos...)
+		}
+		proxy, err := rpmmd.CommonProxy(allRepos)
+		if err != nil {
+			return HTTPErrorWithInternal(ErrorConflictingRepositoryProxy, err)
+		}
+
+		imageOptions := distro.ImageOptions{
+			Size:           imageType.Size(0),
+			EnabledModules: bp.EnabledModules,
+		}
 		if request.Customizations != nil && request.Customizations.Subscription != nil {
 			imageOptions.Subscription = &distro.SubscriptionImageOptions{
 				Organization:  request.Customizations.Subscription.Organization,
@@ -311,6 +453,18 @@ func (h *apiHandlers) PostCompose(ctx echo.Context) error {
 			if ir.Ostree.Parent != nil {
 				ostreeOptions.Parent = *ir.Ostree.Parent
 			}
+			if ir.Ostree.Sign != nil {
+				ostreeOptions.Sign = *ir.Ostree.Sign
+			}
+			if ir.Ostree.StaticDelta != nil {
+				ostreeOptions.StaticDelta = *ir.Ostree.StaticDelta
+			}
+			if ir.Ostree.GpgKey != nil {
+				ostreeOptions.GPGKey = *ir.Ostree.GpgKey
+			}
+			if ir.Ostree.Rhsm != nil {
+				ostreeOptions.RHSM = *ir.Ostree.Rhsm
+			}
 		}
 		if imageOptions.OSTree, err = ostree.ResolveParams(ostreeOptions, imageType.OSTreeRef()); err != nil {
 			switch v := err.(type) {
@@ -320,11 +474,17 @@ func (h *apiHandlers) PostCompose(ctx echo.Context) error {
 				return HTTPErrorWithInternal(ErrorInvalidOSTreeRepo, v)
 			case ostree.ParameterComboError:
 				return HTTPError(ErrorInvalidOSTreeParams)
+			case ostree.RemoteConfigError:
+				return HTTPErrorWithInternal(ErrorInvalidOSTreeParams, v)
 			default:
 				// general case
 				return HTTPError(ErrorInvalidOSTreeParams)
 			}
 		}
+		ostreeCommit, err := worker.NewOSTreeCommitOptions(imageType, imageOptions.OSTree)
+		if err != nil {
+			return HTTPErrorWithInternal(ErrorInvalidOSTreeParams, err)
+		}
 
 		var irTarget *target.Target
 		if ir.UploadOptions == nil {
@@ -473,15 +633,49 @@ func (h *apiHandlers) PostCompose(ctx echo.Context) error {
 		}
 
 		irs = append(irs, imageRequest{
+			apiImageType:            ir.ImageType,
 			imageType:               imageType,
 			arch:                    arch,
 			repositories:            repos,
 			imageOptions:            imageOptions,
 			packageSetsRepositories: pkgSetsRepos,
 			target:                  irTarget,
+			proxy:                   proxy,
+			ostreeCommit:            ostreeCommit,
 		})
 	}
 
+	var webhooks []notification.Webhook
+	if request.Webhooks != nil {
+		for _, w := range *request.Webhooks {
+			webhook := notification.Webhook{URL: w.Url}
+			if w.Secret != nil {
+				webhook.Secret = *w.Secret
+			}
+			err = webhook.Validate()
+			if err != nil {
+				return HTTPErrorWithInternal(ErrorInvalidWebhook, err)
+			}
+			webhooks = append(webhooks, webhook)
+		}
+	}
+	if len(webhooks) > 0 && !h.server.workers.NotificationsEnabled() {
+		return HTTPError(ErrorNotificationsDisabled)
+	}
+
+	// other composes of the tenant must not be enqueued between checking
+	// the quotas and enqueuing this one
+	unlock, err := h.server.workers.LockChannel(ctx.Request().Context(), channel)
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorCheckingQuotas, err)
+	}
+	defer unlock()
+
+	err = h.server.checkQuotas(tenant, channel, irs)
+	if err != nil {
+		return err
+	}
+
 	var id uuid.UUID
 	if request.Koji != nil {
 		id, err = enqueueKojiCompose(h.server.workers, uint64(request.Koji.TaskId), request.Koji.Server, request.Koji.Name, request.Koji.Version, request.Koji.Release, distribution, bp, manifestSeed, irs, channel)
@@ -495,6 +689,11 @@ func (h *apiHandlers) PostCompose(ctx echo.Context) error {
 		}
 	}
 
+	err = h.server.workers.SubscribeCompose(id, id, webhooks)
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorSubscribingCompose, err)
+	}
+
 	ctx.Logger().Infof("Job ID %s enqueued for operationID %s", id, ctx.Get("operationID"))
 
 	return ctx.JSON(http.StatusCreated, &ComposeId{
@@ -507,6 +706,18 @@ func (h *apiHandlers) PostCompose(ctx echo.Context) error {
 	})
 }
 
+// servicesCustomization returns the services `s` enables and disables.
+func servicesCustomization(s *Services) ([]string, []string) {
+	var enabled, disabled []string
+	if s.Enabled != nil {
+		enabled = *s.Enabled
+	}
+	if s.Disabled != nil {
+		disabled = *s.Disabled
+	}
+	return enabled, disabled
+}
+
 func enqueueCompose(workers *worker.Server, distribution distro.Distro, bp blueprint.Blueprint, manifestSeed int64, irs []imageRequest, channel string) (uuid.UUID, error) {
 	var id uuid.UUID
 	if len(irs) != 1 {
@@ -526,7 +737,7 @@ func enqueueCompose(workers *worker.Server, distribution distro.Distro, bp bluep
 		return id, HTTPErrorWithInternal(ErrorEnqueueingJob, err)
 	}
 
-	manifestJobID, err := workers.EnqueueManifestJobByID(&worker.ManifestJobByID{}, depsolveJobID, channel)
+	manifestJobID, err := workers.EnqueueManifestJobByID(&worker.ManifestJobByID{Blueprint: &bp}, depsolveJobID, channel)
 	if err != nil {
 		return id, HTTPErrorWithInternal(ErrorEnqueueingJob, err)
 	}
@@ -538,13 +749,18 @@ func enqueueCompose(workers *worker.Server, distribution distro.Distro, bp bluep
 			Build:   ir.imageType.BuildPipelines(),
 			Payload: ir.imageType.PayloadPipelines(),
 		},
+		Distro:       distribution.Name(),
+		ImageType:    string(ir.apiImageType),
+		ImageSize:    ir.imageOptions.Size,
+		Proxy:        ir.proxy,
+		OSTreeCommit: ir.ostreeCommit,
 	}, manifestJobID, channel)
 	if err != nil {
 		return id, HTTPErrorWithInternal(ErrorEnqueueingJob, err)
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Minute*5)
-	go generateManifest(ctx, cancel, workers, depsolveJobID, manifestJobID, ir.imageType, ir.repositories, ir.imageOptions, manifestSeed, bp.Customizations)
+	go generateManifest(ctx, cancel, workers, depsolveJobID, manifestJobID, id, ir.imageType, ir.repositories, ir.imageOptions, manifestSeed, bp.Customizations)
 
 	return id, nil
 }
@@ -578,7 +794,7 @@ func enqueueKojiCompose(workers *worker.Server, taskID uint64, server, name, ver
 			return id, HTTPErrorWithInternal(ErrorEnqueueingJob, err)
 		}
 
-		manifestJobID, err := workers.EnqueueManifestJobByID(&worker.ManifestJobByID{}, depsolveJobID, channel)
+		manifestJobID, err := workers.EnqueueManifestJobByID(&worker.ManifestJobByID{Blueprint: &bp}, depsolveJobID, channel)
 		if err != nil {
 			return id, HTTPErrorWithInternal(ErrorEnqueueingJob, err)
 		}
@@ -600,6 +816,11 @@ func enqueueKojiCompose(workers *worker.Server, taskID uint64, server, name, ver
 			KojiServer:    server,
 			KojiDirectory: kojiDirectory,
 			KojiFilename:  kojiFilename,
+			Distro:        distribution.Name(),
+			ImageType:     string(ir.apiImageType),
+			ImageSize:     ir.imageOptions.Size,
+			Proxy:         ir.proxy,
+			OSTreeCommit:  ir.ostreeCommit,
 		}, manifestJobID, initID, channel)
 		if err != nil {
 			return id, HTTPErrorWithInternal(ErrorEnqueueingJob, err)
@@ -608,7 +829,9 @@ func enqueueKojiCompose(workers *worker.Server, taskID uint64, server, name, ver
 		buildIDs = append(buildIDs, buildID)
 
 		ctx, cancel := context.WithTimeout(context.Background(), time.Minute*5)
-		go generateManifest(ctx, cancel, workers, depsolveJobID, manifestJobID, ir.imageType, ir.repositories, ir.imageOptions, manifestSeed, bp.Customizations)
+		// koji builds are never deduplicated, because each of them must
+		// be imported as a separate build
+		go generateManifest(ctx, cancel, workers, depsolveJobID, manifestJobID, uuid.Nil, ir.imageType, ir.repositories, ir.imageOptions, manifestSeed, bp.Customizations)
 	}
 	id, err = workers.EnqueueKojiFinalize(&worker.KojiFinalizeJob{
 		Server:        server,
@@ -627,7 +850,10 @@ func enqueueKojiCompose(workers *worker.Server, taskID uint64, server, name, ver
 	return id, nil
 }
 
-func generateManifest(ctx context.Context, cancel context.CancelFunc, workers *worker.Server, depsolveJobID uuid.UUID, manifestJobID uuid.UUID, imageType distro.ImageType, repos []rpmmd.RepoConfig, options distro.ImageOptions, seed int64, b *blueprint.Customizations) {
+// generateManifest finishes the manifest job `manifestJobID` once the depsolve
+// job it depends on has finished. When `osbuildJobID` is set, it then tries to
+// deduplicate that osbuild job, which depends on the manifest job.
+func generateManifest(ctx context.Context, cancel context.CancelFunc, workers *worker.Server, depsolveJobID uuid.UUID, manifestJobID uuid.UUID, osbuildJobID uuid.UUID, imageType distro.ImageType, repos []rpmmd.RepoConfig, options distro.ImageOptions, seed int64, b *blueprint.Customizations) {
 	defer cancel()
 
 	// wait until job is in a pending state
@@ -672,6 +898,18 @@ func generateManifest(ctx context.Context, cancel context.CancelFunc, workers *w
 		err = workers.FinishJob(token, result)
 		if err != nil {
 			logWithId.Errorf("Error finishing manifest job: %v", err)
+			return
+		}
+
+		if jobResult.JobError != nil || osbuildJobID == uuid.Nil {
+			return
+		}
+
+		deduplicated, err := workers.DeduplicateOSBuildJob(ctx, osbuildJobID)
+		if err != nil {
+			logWithId.Errorf("Error deduplicating osbuild job %s: %v", osbuildJobID, err)
+		} else if deduplicated {
+			logWithId.Infof("Osbuild job %s was deduplicated", osbuildJobID)
 		}
 	}()
 
@@ -712,7 +950,20 @@ func generateManifest(ctx context.Context, cancel context.CancelFunc, workers *w
 		return
 	}
 
+	contentHash, err := worker.ContentHash(imageType, depsolveResults.PackageSpecs, b, options)
+	if err != nil {
+		// not fatal, the image just can't be deduplicated
+		logWithId.Warningf("Error computing content hash: %v", err)
+	}
+
+	var payloadPackageSpecs []rpmmd.PackageSpec
+	for _, name := range imageType.PayloadPackageSets() {
+		payloadPackageSpecs = append(payloadPackageSpecs, depsolveResults.PackageSpecs[name]...)
+	}
+
 	jobResult.Manifest = manifest
+	jobResult.ContentHash = contentHash
+	jobResult.PackageSpecs = payloadPackageSpecs
 }
 
 func imageTypeFromApiImageType(it ImageTypes) string {
@@ -747,6 +998,158 @@ func imageTypeFromApiImageType(it ImageTypes) string {
 	return ""
 }
 
+func (h *apiHandlers) GetComposeList(ctx echo.Context, params GetComposeListParams) error {
+	page := 0
+	var err error
+	if params.Page != nil {
+		page, err = strconv.Atoi(string(*params.Page))
+		if err != nil || page < 0 {
+			return HTTPError(ErrorInvalidPageParam)
+		}
+	}
+
+	size := 100
+	if params.Size != nil {
+		size, err = strconv.Atoi(string(*params.Size))
+		if err != nil || size < 0 {
+			return HTTPError(ErrorInvalidSizeParam)
+		}
+	}
+
+	if params.Status != nil {
+		switch *params.Status {
+		case ComposeStatusValueSuccess, ComposeStatusValueFailure, ComposeStatusValuePending:
+		default:
+			return HTTPError(ErrorInvalidComposeFilter)
+		}
+	}
+	if params.ImageType != nil && imageTypeFromApiImageType(*params.ImageType) == "" {
+		return HTTPError(ErrorInvalidComposeFilter)
+	}
+
+	// composes store the name of the distribution, which might differ
+	// from the alias in the request
+	distribution := params.Distribution
+	if distribution != nil {
+		if d := h.server.distros.GetDistro(*distribution); d != nil {
+			name := d.Name()
+			distribution = &name
+		}
+	}
+
+	var createdAfter, createdBefore time.Time
+	if params.CreatedAfter != nil {
+		createdAfter = *params.CreatedAfter
+	}
+	if params.CreatedBefore != nil {
+		createdBefore = *params.CreatedBefore
+	}
+
+	channel, err := h.server.tenantChannel(ctx)
+	if err != nil {
+		return err
+	}
+
+	ids, err := h.server.workers.JobsByChannel(channel, []string{"osbuild", "koji-finalize"}, createdAfter, createdBefore)
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorListingComposes, err)
+	}
+
+	list := ComposeList{
+		List: List{
+			Kind: "ComposeList",
+			Page: page,
+		},
+		Items: []ComposeStatus{},
+	}
+	for _, id := range ids {
+		jobType, err := h.server.workers.JobType(id)
+		if err != nil {
+			return HTTPErrorWithInternal(ErrorListingComposes, err)
+		}
+
+		if distribution != nil || params.ImageType != nil {
+			matches, err := h.server.composeMatches(id, jobType, distribution, params.ImageType)
+			if err != nil {
+				return HTTPErrorWithInternal(ErrorListingComposes, err)
+			}
+			if !matches {
+				continue
+			}
+		}
+
+		// the status is only needed for filtering and for the composes on
+		// the requested page
+		var status *ComposeStatus
+		inPage := list.Total >= page*size && list.Total < (page+1)*size
+		if params.Status != nil || inPage {
+			status, err = h.server.composeStatus(id, jobType)
+			if err != nil {
+				logrus.Warningf("Skipping compose %s in compose list: %v", id, err)
+				continue
+			}
+		}
+		if params.Status != nil && status.Status != *params.Status {
+			continue
+		}
+
+		if inPage {
+			list.Items = append(list.Items, *status)
+		}
+		list.Total++
+	}
+	list.Size = len(list.Items)
+
+	return ctx.JSON(http.StatusOK, list)
+}
+
+// composeMatches returns whether the compose with the given id builds an
+// image for the given distribution and of the given image type. Nil filters
+// match all composes.
+func (s *Server) composeMatches(id uuid.UUID, jobType string, distribution *string, imageType *ImageTypes) (bool, error) {
+	type build struct {
+		distro    string
+		imageType string
+	}
+
+	var builds []build
+	switch jobType {
+	case "osbuild":
+		var job worker.OSBuildJob
+		err := s.workers.OSBuildJob(id, &job)
+		if err != nil {
+			return false, err
+		}
+		builds = append(builds, build{job.Distro, job.ImageType})
+	case "koji-finalize":
+		var result worker.KojiFinalizeJobResult
+		_, deps, err := s.workers.KojiFinalizeJobStatus(id, &result)
+		if err != nil {
+			return false, err
+		}
+		// the first dependency is the koji-init job
+		for i := 1; i < len(deps); i++ {
+			var job worker.OSBuildKojiJob
+			err := s.workers.OSBuildKojiJob(deps[i], &job)
+			if err != nil {
+				return false, err
+			}
+			builds = append(builds, build{job.Distro, job.ImageType})
+		}
+	}
+
+	for _, b := range builds {
+		if distribution != nil && b.distro != *distribution {
+			continue
+		}
+		if imageType != nil && b.imageType != string(*imageType) {
+			continue
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
 func (h *apiHandlers) GetComposeStatus(ctx echo.Context, id string) error {
 	jobId, err := uuid.Parse(id)
 	if err != nil {
@@ -758,18 +1161,28 @@ func (h *apiHandlers) GetComposeStatus(ctx echo.Context, id string) error {
 		return HTTPError(ErrorComposeNotFound)
 	}
 
+	response, err := h.server.composeStatus(jobId, jobType)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// composeStatus returns the status of the compose with the given id, whose
+// root job has the given type.
+func (s *Server) composeStatus(jobId uuid.UUID, jobType string) (*ComposeStatus, error) {
 	if jobType == "osbuild" {
 		var result worker.OSBuildJobResult
-		status, _, err := h.server.workers.OSBuildJobStatus(jobId, &result)
+		status, _, err := s.workers.OSBuildJobStatus(jobId, &result)
 		if err != nil {
-			return HTTPError(ErrorMalformedOSBuildJobResult)
+			return nil, HTTPError(ErrorMalformedOSBuildJobResult)
 		}
 
 		var us *UploadStatus
 		if result.TargetResults != nil {
 			// Only single upload target is allowed, therefore only a single upload target result is allowed as well
 			if len(result.TargetResults) != 1 {
-				return HTTPError(ErrorSeveralUploadTargets)
+				return nil, HTTPError(ErrorSeveralUploadTargets)
 			}
 			tr := *result.TargetResults[0]
 
@@ -804,7 +1217,7 @@ func (h *apiHandlers) GetComposeStatus(ctx echo.Context, id string) error {
 					ImageName: gcpOptions.ImageName,
 				}
 			default:
-				return HTTPError(ErrorUnknownUploadTarget)
+				return nil, HTTPError(ErrorUnknownUploadTarget)
 			}
 
 			us = &UploadStatus{
@@ -814,7 +1227,7 @@ func (h *apiHandlers) GetComposeStatus(ctx echo.Context, id string) error {
 			}
 		}
 
-		return ctx.JSON(http.StatusOK, ComposeStatus{
+		response := ComposeStatus{
 			ObjectReference: ObjectReference{
 				Href: fmt.Sprintf("/api/image-builder-composer/v2/composes/%v", jobId),
 				Id:   jobId.String(),
@@ -826,28 +1239,33 @@ func (h *apiHandlers) GetComposeStatus(ctx echo.Context, id string) error {
 				Error:        composeStatusErrorFromJobError(result.JobError),
 				UploadStatus: us,
 			},
-		})
+		}
+		if result.DeduplicatedFrom != nil {
+			deduplicatedFrom := result.DeduplicatedFrom.String()
+			response.DeduplicatedFrom = &deduplicatedFrom
+		}
+		return &response, nil
 	} else if jobType == "koji-finalize" {
 		var result worker.KojiFinalizeJobResult
-		finalizeStatus, deps, err := h.server.workers.KojiFinalizeJobStatus(jobId, &result)
+		finalizeStatus, deps, err := s.workers.KojiFinalizeJobStatus(jobId, &result)
 		if err != nil {
-			return HTTPError(ErrorMalformedOSBuildJobResult)
+			return nil, HTTPError(ErrorMalformedOSBuildJobResult)
 		}
 		if len(deps) < 2 {
-			return HTTPError(ErrorUnexpectedNumberOfImageBuilds)
+			return nil, HTTPError(ErrorUnexpectedNumberOfImageBuilds)
 		}
 		var initResult worker.KojiInitJobResult
-		_, _, err = h.server.workers.KojiInitJobStatus(deps[0], &initResult)
+		_, _, err = s.workers.KojiInitJobStatus(deps[0], &initResult)
 		if err != nil {
-			return HTTPError(ErrorMalformedOSBuildJobResult)
+			return nil, HTTPError(ErrorMalformedOSBuildJobResult)
 		}
 		var buildJobResults []worker.OSBuildKojiJobResult
 		var buildJobStatuses []ImageStatus
 		for i := 1; i < len(deps); i++ {
 			var buildJobResult worker.OSBuildKojiJobResult
-			buildJobStatus, _, err := h.server.workers.OSBuildKojiJobStatus(deps[i], &buildJobResult)
+			buildJobStatus, _, err := s.workers.OSBuildKojiJobStatus(deps[i], &buildJobResult)
 			if err != nil {
-				return HTTPError(ErrorMalformedOSBuildJobResult)
+				return nil, HTTPError(ErrorMalformedOSBuildJobResult)
 			}
 			buildJobResults = append(buildJobResults, buildJobResult)
 			buildJobStatuses = append(buildJobStatuses, ImageStatus{
@@ -870,87 +1288,346 @@ func (h *apiHandlers) GetComposeStatus(ctx echo.Context, id string) error {
 		if buildID != 0 {
 			response.KojiStatus.BuildId = &buildID
 		}
-		return ctx.JSON(http.StatusOK, response)
+		if result.Failure != "" {
+			failure := KojiStatusFailure(result.Failure)
+			response.KojiStatus.Failure = &failure
+		}
+		if result.Retryable {
+			response.KojiStatus.Retryable = common.BoolToPtr(true)
+		}
+		return &response, nil
 	} else {
-		return HTTPError(ErrorInvalidJobType)
+		return nil, HTTPError(ErrorInvalidJobType)
 	}
 }
 
-func composeStatusErrorFromJobError(jobError *clienterrors.Error) *ComposeStatusError {
-	if jobError == nil {
-		return nil
+func (h *apiHandlers) PostComposeCancel(ctx echo.Context, id string) error {
+	jobId, jobType, err := h.server.tenantCompose(ctx, id)
+	if err != nil {
+		return err
 	}
-	return &ComposeStatusError{
-		Id:      int(jobError.ID),
-		Reason:  jobError.Reason,
-		Details: &jobError.Details,
+
+	err = h.server.workers.CancelIncludingDependencies(jobId)
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorCancelingCompose, err)
 	}
-}
 
-func imageStatusFromOSBuildJobStatus(js *worker.JobStatus, result *worker.OSBuildJobResult) ImageStatusValue {
-	if js.Canceled {
-		return ImageStatusValueFailure
+	response, err := h.server.composeStatus(jobId, jobType)
+	if err != nil {
+		return err
 	}
+	return ctx.JSON(http.StatusOK, response)
+}
 
-	if js.Started.IsZero() {
-		return ImageStatusValuePending
+func (h *apiHandlers) PostComposeRetry(ctx echo.Context, id string) error {
+	jobId, jobType, err := h.server.tenantCompose(ctx, id)
+	if err != nil {
+		return err
 	}
 
-	if js.Finished.IsZero() {
-		// TODO: handle also ImageStatusValueUploading
-		// TODO: handle also ImageStatusValueRegistering
-		return ImageStatusValueBuilding
+	if jobType != "koji-finalize" {
+		return HTTPError(ErrorComposeNotRetryable)
 	}
 
-	if result.Success {
-		return ImageStatusValueSuccess
+	err = h.server.workers.RetryKojiCompose(jobId)
+	if err == worker.ErrComposeNotRetryable {
+		return HTTPError(ErrorComposeNotRetryable)
+	} else if err != nil {
+		return HTTPErrorWithInternal(ErrorRetryingCompose, err)
 	}
 
-	return ImageStatusValueFailure
+	response, err := h.server.composeStatus(jobId, jobType)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, response)
 }
 
-func imageStatusFromKojiJobStatus(js *worker.JobStatus, initResult *worker.KojiInitJobResult, buildResult *worker.OSBuildKojiJobResult) ImageStatusValue {
-	if js.Canceled {
-		return ImageStatusValueFailure
+func (h *apiHandlers) DeleteCompose(ctx echo.Context, id string, params DeleteComposeParams) error {
+	jobId, jobType, err := h.server.tenantCompose(ctx, id)
+	if err != nil {
+		return err
 	}
 
-	if initResult.JobError != nil {
-		return ImageStatusValueFailure
+	channel, err := h.server.tenantChannel(ctx)
+	if err != nil {
+		return err
 	}
 
-	if js.Started.IsZero() {
-		return ImageStatusValuePending
+	// the results of the jobs are gone after deleting them
+	var cleanupJob *worker.ImageCleanupJob
+	if params.DeleteImages != nil && *params.DeleteImages && jobType == "osbuild" {
+		cleanupJob, err = h.server.imageCleanupJob(jobId, channel)
+		if err != nil {
+			return HTTPErrorWithInternal(ErrorDeletingCompose, err)
+		}
 	}
 
-	if js.Finished.IsZero() {
-		return ImageStatusValueBuilding
+	err = h.server.workers.DeleteJobIncludingDependencies(jobId)
+	if err == worker.ErrJobNotFinished {
+		return HTTPError(ErrorComposeNotFinished)
+	} else if err != nil {
+		return HTTPErrorWithInternal(ErrorDeletingCompose, err)
 	}
 
-	if buildResult.JobError != nil {
-		return ImageStatusValueFailure
+	err = h.server.workers.UnsubscribeCompose(jobId, channel)
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorDeletingCompose, err)
 	}
 
-	return ImageStatusValueSuccess
+	if cleanupJob != nil {
+		_, err = h.server.workers.EnqueueImageCleanup(cleanupJob, channel)
+		if err != nil {
+			return HTTPErrorWithInternal(ErrorEnqueueingJob, err)
+		}
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
 }
 
-func composeStatusFromOSBuildJobStatus(js *worker.JobStatus, result *worker.OSBuildJobResult) ComposeStatusValue {
-	if js.Canceled {
-		return ComposeStatusValueFailure
+func (h *apiHandlers) GetComposeNotifications(ctx echo.Context, id string) error {
+	jobId, _, err := h.server.tenantCompose(ctx, id)
+	if err != nil {
+		return err
 	}
 
-	if js.Finished.IsZero() {
-		return ComposeStatusValuePending
+	channel, err := h.server.tenantChannel(ctx)
+	if err != nil {
+		return err
 	}
 
-	if result.Success {
-		return ComposeStatusValueSuccess
+	deliveries, err := h.server.workers.ComposeDeliveries(jobId, channel)
+	if err != nil && err != notification.ErrNotSubscribed {
+		return HTTPErrorWithInternal(ErrorGettingNotifications, err)
 	}
 
-	return ComposeStatusValueFailure
-}
-
-func composeStatusFromKojiJobStatus(js *worker.JobStatus, initResult *worker.KojiInitJobResult, buildResults []worker.OSBuildKojiJobResult, result *worker.KojiFinalizeJobResult) ComposeStatusValue {
-	if js.Canceled {
+	response := ComposeNotifications{
+		ObjectReference: ObjectReference{
+			Href: fmt.Sprintf("/api/image-builder-composer/v2/composes/%v/notifications", jobId),
+			Id:   jobId.String(),
+			Kind: "ComposeNotifications",
+		},
+		Deliveries: []NotificationDelivery{},
+	}
+	for _, d := range deliveries {
+		response.Deliveries = append(response.Deliveries, NotificationDelivery{
+			EventId:     d.EventID.String(),
+			Status:      ComposeNotificationStatus(d.Status),
+			Url:         d.URL,
+			Attempts:    d.Attempts,
+			Delivered:   d.Delivered,
+			LastAttempt: d.LastAttempt,
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func (h *apiHandlers) GetComposeEvents(ctx echo.Context, id string) error {
+	jobId, _, err := h.server.tenantCompose(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	events, err := h.server.workers.WatchCompose(ctx.Request().Context(), jobId)
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorStreamingComposeEvents, err)
+	}
+
+	stream, err := common.NewEventStream(ctx.Response())
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorStreamingComposeEvents, err)
+	}
+
+	// the response has started, errors can only end the stream from here on
+	keepAlive := time.NewTicker(common.EventStreamKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if event.Status != "" {
+				status := ComposeNotificationStatus(event.Status)
+				err = stream.Send("status", ComposeEvent{Status: &status})
+			} else {
+				lines := event.Lines
+				err = stream.Send("log", ComposeEvent{JobId: common.StringToPtr(event.JobID.String()), Lines: &lines})
+			}
+		case <-keepAlive.C:
+			err = stream.KeepAlive()
+		}
+		if err != nil {
+			logrus.Debugf("Stopped streaming the events of compose %s: %v", jobId, err)
+			return nil
+		}
+	}
+}
+
+// tenantCompose parses the id of a compose and returns it together with the
+// type of the compose's root job. It returns ErrorComposeNotFound if the
+// compose doesn't belong to the tenant who sent the request.
+func (s *Server) tenantCompose(ctx echo.Context, id string) (uuid.UUID, string, error) {
+	jobId, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, "", HTTPError(ErrorInvalidComposeId)
+	}
+
+	jobType, err := s.workers.JobType(jobId)
+	if err != nil {
+		return uuid.Nil, "", HTTPError(ErrorComposeNotFound)
+	}
+	if jobType != "osbuild" && jobType != "koji-finalize" {
+		return uuid.Nil, "", HTTPError(ErrorInvalidJobType)
+	}
+
+	jobChannel, err := s.workers.JobChannel(jobId)
+	if err != nil {
+		return uuid.Nil, "", HTTPError(ErrorComposeNotFound)
+	}
+	channel, err := s.tenantChannel(ctx)
+	if err != nil {
+		return uuid.Nil, "", err
+	}
+	if jobChannel != channel {
+		return uuid.Nil, "", HTTPError(ErrorComposeNotFound)
+	}
+
+	return jobId, jobType, nil
+}
+
+// imageCleanupJob returns the job which removes the images uploaded by the
+// osbuild job with the given id, or nil if there is nothing to remove.
+//
+// Because of deduplication, several composes can share the same images: the
+// one which uploaded them, and those whose DeduplicatedFrom refers to it.
+// Images are kept as long as any other of these composes exists, and removed
+// with the last one of them.
+func (s *Server) imageCleanupJob(jobId uuid.UUID, channel string) (*worker.ImageCleanupJob, error) {
+	var result worker.OSBuildJobResult
+	_, _, err := s.workers.OSBuildJobStatus(jobId, &result)
+	if err != nil {
+		return nil, err
+	}
+	uploadedBy := jobId
+	if result.DeduplicatedFrom != nil {
+		uploadedBy = *result.DeduplicatedFrom
+	}
+
+	job := &worker.ImageCleanupJob{}
+	for _, tr := range result.TargetResults {
+		switch tr.Options.(type) {
+		case *target.AWSTargetResultOptions, *target.GCPTargetResultOptions:
+			job.TargetResults = append(job.TargetResults, tr)
+		}
+	}
+	if len(job.TargetResults) == 0 {
+		return nil, nil
+	}
+
+	// deduplication only happens between the composes of a tenant
+	ids, err := s.workers.JobsByChannel(channel, []string{"osbuild"}, time.Time{}, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		if id == jobId {
+			continue
+		}
+		if id == uploadedBy {
+			logrus.Infof("Keeping the images of compose %s, which were uploaded by compose %s", jobId, id)
+			return nil, nil
+		}
+		var other worker.OSBuildJobResult
+		_, _, err := s.workers.OSBuildJobStatus(id, &other)
+		if err != nil {
+			return nil, err
+		}
+		if other.DeduplicatedFrom != nil && *other.DeduplicatedFrom == uploadedBy {
+			logrus.Infof("Keeping the images of compose %s, which are used by compose %s", jobId, id)
+			return nil, nil
+		}
+	}
+
+	return job, nil
+}
+
+func composeStatusErrorFromJobError(jobError *clienterrors.Error) *ComposeStatusError {
+	if jobError == nil {
+		return nil
+	}
+	return &ComposeStatusError{
+		Id:      int(jobError.ID),
+		Reason:  jobError.Reason,
+		Details: &jobError.Details,
+	}
+}
+
+func imageStatusFromOSBuildJobStatus(js *worker.JobStatus, result *worker.OSBuildJobResult) ImageStatusValue {
+	if js.Canceled {
+		return ImageStatusValueFailure
+	}
+
+	if js.Started.IsZero() {
+		return ImageStatusValuePending
+	}
+
+	if js.Finished.IsZero() {
+		// TODO: handle also ImageStatusValueUploading
+		// TODO: handle also ImageStatusValueRegistering
+		return ImageStatusValueBuilding
+	}
+
+	if result.Success {
+		return ImageStatusValueSuccess
+	}
+
+	return ImageStatusValueFailure
+}
+
+func imageStatusFromKojiJobStatus(js *worker.JobStatus, initResult *worker.KojiInitJobResult, buildResult *worker.OSBuildKojiJobResult) ImageStatusValue {
+	if js.Canceled {
+		return ImageStatusValueFailure
+	}
+
+	if initResult.JobError != nil {
+		return ImageStatusValueFailure
+	}
+
+	if js.Started.IsZero() {
+		return ImageStatusValuePending
+	}
+
+	if js.Finished.IsZero() {
+		return ImageStatusValueBuilding
+	}
+
+	if buildResult.JobError != nil {
+		return ImageStatusValueFailure
+	}
+
+	return ImageStatusValueSuccess
+}
+
+func composeStatusFromOSBuildJobStatus(js *worker.JobStatus, result *worker.OSBuildJobResult) ComposeStatusValue {
+	if js.Canceled {
+		return ComposeStatusValueFailure
+	}
+
+	if js.Finished.IsZero() {
+		return ComposeStatusValuePending
+	}
+
+	if result.Success {
+		return ComposeStatusValueSuccess
+	}
+
+	return ComposeStatusValueFailure
+}
+
+func composeStatusFromKojiJobStatus(js *worker.JobStatus, initResult *worker.KojiInitJobResult, buildResults []worker.OSBuildKojiJobResult, result *worker.KojiFinalizeJobResult) ComposeStatusValue {
+	if js.Canceled {
 		return ComposeStatusValueFailure
 	}
 
@@ -1065,6 +1742,592 @@ func (h *apiHandlers) GetComposeMetadata(ctx echo.Context, id string) error {
 	return ctx.JSON(200, resp)
 }
 
+func (h *apiHandlers) GetComposeAdvisories(ctx echo.Context, id string) error {
+	jobId, jobType, err := h.server.tenantCompose(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// TODO: support koji builds
+	if jobType != "osbuild" {
+		return HTTPError(ErrorInvalidJobType)
+	}
+
+	depsolveJobID, err := h.server.composeDepsolveJob(jobId)
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorGettingComposeRepositories, err)
+	}
+
+	var depsolveJob worker.DepsolveJob
+	if err = h.server.workers.DepsolveJob(depsolveJobID, &depsolveJob); err != nil {
+		return HTTPErrorWithInternal(ErrorGettingComposeRepositories, err)
+	}
+
+	var depsolveResult worker.DepsolveJobResult
+	if _, _, err = h.server.workers.DepsolveJobStatus(depsolveJobID, &depsolveResult); err != nil {
+		return HTTPErrorWithInternal(ErrorGettingComposeRepositories, err)
+	}
+
+	// only the packages of the image itself are of interest, not the ones
+	// of the build root
+	repos := depsolveJob.Repos
+	var packages []rpmmd.PackageSpec
+	for name, pkgs := range depsolveResult.PackageSpecs {
+		if name == "build" {
+			continue
+		}
+		packages = append(packages, pkgs...)
+		repos = append(repos, depsolveJob.PackageSetsRepos[name]...)
+	}
+
+	channel, err := h.server.tenantChannel(ctx)
+	if err != nil {
+		return err
+	}
+	updateInfoJobID, err := h.server.workers.EnqueueUpdateInfo(&worker.UpdateInfoJob{
+		Repos:      repos,
+		Arch:       depsolveJob.Arch,
+		Releasever: depsolveJob.Releasever,
+	}, channel)
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorEnqueueingJob, err)
+	}
+	err = h.server.waitForJob(ctx.Request().Context(), updateInfoJobID)
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorFetchingAdvisories, err)
+	}
+
+	var updateInfoResult worker.UpdateInfoJobResult
+	if _, _, err = h.server.workers.UpdateInfoJobStatus(updateInfoJobID, &updateInfoResult); err != nil {
+		return HTTPErrorWithInternal(ErrorFetchingAdvisories, err)
+	}
+	if updateInfoResult.JobError != nil {
+		return HTTPErrorWithInternal(ErrorFetchingAdvisories, fmt.Errorf("%s", updateInfoResult.JobError.Reason))
+	}
+
+	report := rpmmd.NewAdvisoryReport(updateInfoResult.Advisories, packages)
+
+	return ctx.JSON(http.StatusOK, ComposeAdvisories{
+		ObjectReference: ObjectReference{
+			Href: fmt.Sprintf("/api/image-builder-composer/v2/composes/%v/advisories", jobId),
+			Id:   jobId.String(),
+			Kind: "ComposeAdvisories",
+		},
+		Fixed:         advisoriesFromRpmmd(report.Fixed),
+		Unpatched:     advisoriesFromRpmmd(report.Unpatched),
+		UnpatchedCves: report.UnpatchedCVEs(),
+	})
+}
+
+func (h *apiHandlers) GetComposeSBOM(ctx echo.Context, id string, params GetComposeSBOMParams) error {
+	jobId, jobType, err := h.server.tenantCompose(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	artifact := sbom.SPDXArtifact
+	if params.Format != nil {
+		switch *params.Format {
+		case "spdx":
+		case "cyclonedx":
+			artifact = sbom.CycloneDXArtifact
+		default:
+			return HTTPError(ErrorInvalidSBOMFormat)
+		}
+	}
+
+	// TODO: support koji builds
+	if jobType != "osbuild" {
+		return HTTPError(ErrorInvalidJobType)
+	}
+
+	reader, _, err := h.server.workers.JobArtifact(jobId, artifact)
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorSBOMNotFound, err)
+	}
+	if closer, ok := reader.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	return ctx.Stream(http.StatusOK, echo.MIMEApplicationJSON, reader)
+}
+
+// maxHealthRepositories is how many repositories can be checked with a
+// single request.
+const maxHealthRepositories = 20
+
+func (h *apiHandlers) PostRepositoriesHealth(ctx echo.Context) error {
+	var request RepositoryHealthRequest
+	err := ctx.Bind(&request)
+	if err != nil {
+		return err
+	}
+
+	distribution := h.server.distros.GetDistro(request.Distribution)
+	if distribution == nil {
+		return HTTPError(ErrorUnsupportedDistribution)
+	}
+	if _, err := distribution.GetArch(request.Architecture); err != nil {
+		return HTTPError(ErrorUnsupportedArchitecture)
+	}
+	if len(request.Repositories) == 0 {
+		return HTTPError(ErrorInvalidRepository)
+	}
+	if len(request.Repositories) > maxHealthRepositories {
+		return HTTPError(ErrorTooManyRepositories)
+	}
+
+	repos := make([]rpmmd.RepoConfig, 0, len(request.Repositories))
+	for _, r := range request.Repositories {
+		repo, err := genRepoConfig(r)
+		if err != nil {
+			return err
+		}
+		repos = append(repos, *repo)
+	}
+
+	// the repositories are probed by a worker, which has the entitlements
+	// for rhsm repositories, instead of by composer itself
+	channel, err := h.server.tenantChannel(ctx)
+	if err != nil {
+		return err
+	}
+	healthJobID, err := h.server.workers.EnqueueRepositoryHealth(&worker.RepositoryHealthJob{
+		Repos:      repos,
+		Arch:       request.Architecture,
+		Releasever: distribution.Releasever(),
+	}, channel)
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorEnqueueingJob, err)
+	}
+	err = h.server.waitForJob(ctx.Request().Context(), healthJobID)
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorCheckingRepositories, err)
+	}
+
+	var healthResult worker.RepositoryHealthJobResult
+	if _, _, err = h.server.workers.RepositoryHealthJobStatus(healthJobID, &healthResult); err != nil {
+		return HTTPErrorWithInternal(ErrorCheckingRepositories, err)
+	}
+	if healthResult.JobError != nil {
+		return HTTPErrorWithInternal(ErrorCheckingRepositories, fmt.Errorf("%s", healthResult.JobError.Reason))
+	}
+	results := healthResult.Health
+	if len(results) != len(repos) {
+		return HTTPErrorWithInternal(ErrorCheckingRepositories, fmt.Errorf("expected %d results, got %d", len(repos), len(results)))
+	}
+
+	resp := RepositoryHealthList{
+		Items: make([]RepositoryHealth, 0, len(results)),
+	}
+	for i, result := range results {
+		// genRepoConfig() sets exactly one of them
+		url := repos[i].BaseURL
+		if repos[i].MirrorList != "" {
+			url = repos[i].MirrorList
+		} else if repos[i].Metalink != "" {
+			url = repos[i].Metalink
+		}
+
+		item := RepositoryHealth{
+			Url:     url,
+			Healthy: result.Healthy,
+			Checks:  make([]RepositoryHealthCheck, 0, len(result.Checks)),
+		}
+		for _, check := range result.Checks {
+			c := RepositoryHealthCheck{
+				Name:   RepositoryHealthCheckName(check.Name),
+				Status: RepositoryHealthCheckStatus(check.Status),
+			}
+			if check.Message != "" {
+				c.Message = common.StringToPtr(check.Message)
+			}
+			item.Checks = append(item.Checks, c)
+		}
+		if !result.MetadataTimestamp.IsZero() {
+			timestamp := result.MetadataTimestamp.UTC()
+			item.MetadataTimestamp = &timestamp
+		}
+		resp.Items = append(resp.Items, item)
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+func (h *apiHandlers) PostPackagesExplain(ctx echo.Context) error {
+	var request PackageExplainRequest
+	err := ctx.Bind(&request)
+	if err != nil {
+		return err
+	}
+
+	distribution := h.server.distros.GetDistro(request.Distribution)
+	if distribution == nil {
+		return HTTPError(ErrorUnsupportedDistribution)
+	}
+	arch, err := distribution.GetArch(request.Architecture)
+	if err != nil {
+		return HTTPError(ErrorUnsupportedArchitecture)
+	}
+	imageType, err := arch.GetImageType(imageTypeFromApiImageType(request.ImageType))
+	if err != nil {
+		return HTTPError(ErrorUnsupportedImageType)
+	}
+	if len(request.Repositories) == 0 {
+		return HTTPError(ErrorInvalidRepository)
+	}
+
+	repos, pkgSetsRepos, err := collectRepos(request.Repositories, nil, imageType.PayloadPackageSets())
+	if err != nil {
+		return err
+	}
+
+	var bp = blueprint.Blueprint{}
+	err = bp.Initialize()
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorFailedToInitializeBlueprint, err)
+	}
+
+	requested := make(map[string]bool)
+	if request.Packages != nil {
+		for _, p := range *request.Packages {
+			bp.Packages = append(bp.Packages, blueprint.Package{
+				Name: p,
+			})
+			requested[p] = true
+		}
+	}
+	if request.EnabledModules != nil {
+		for _, m := range *request.EnabledModules {
+			if m.Name == "" || m.Stream == "" {
+				return HTTPError(ErrorInvalidModule)
+			}
+			bp.EnabledModules = append(bp.EnabledModules, blueprint.EnabledModule{
+				Name:   m.Name,
+				Stream: m.Stream,
+			})
+		}
+	}
+
+	// like for composes, depsolving is left to the workers
+	channel, err := h.server.tenantChannel(ctx)
+	if err != nil {
+		return err
+	}
+	packageSets := imageType.PackageSets(bp)
+	depsolveJobID, err := h.server.workers.EnqueueDepsolve(&worker.DepsolveJob{
+		PackageSets:      packageSets,
+		Repos:            repos,
+		ModulePlatformID: distribution.ModulePlatformID(),
+		Arch:             arch.Name(),
+		Releasever:       distribution.Releasever(),
+		PackageSetsRepos: pkgSetsRepos,
+		Explain:          true,
+	}, channel)
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorEnqueueingJob, err)
+	}
+	err = h.server.waitForJob(ctx.Request().Context(), depsolveJobID)
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorGettingDepsolveJobStatus, err)
+	}
+
+	var depsolveResult worker.DepsolveJobResult
+	if _, _, err = h.server.workers.DepsolveJobStatus(depsolveJobID, &depsolveResult); err != nil {
+		return HTTPErrorWithInternal(ErrorGettingDepsolveJobStatus, err)
+	}
+	if depsolveResult.JobError != nil {
+		return HTTPErrorWithInternal(ErrorDNFError, fmt.Errorf("%s", depsolveResult.JobError.Reason))
+	}
+
+	names := make([]string, 0, len(packageSets))
+	for name := range packageSets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	resp := PackageExplanationList{
+		PackageSets: make([]PackageSetExplanation, 0, len(names)),
+	}
+	for _, name := range names {
+		specs := depsolveResult.PackageSpecs[name]
+
+		set := PackageSetExplanation{
+			Name:     name,
+			Packages: make([]PackageExplanation, 0, len(specs)),
+		}
+		for i, explanation := range rpmmd.ExplainPackages(specs) {
+			pkg := PackageExplanation{
+				Name:        specs[i].Name,
+				Epoch:       int(specs[i].Epoch),
+				Version:     specs[i].Version,
+				Release:     specs[i].Release,
+				Arch:        specs[i].Arch,
+				Chain:       explanation.Chain,
+				RequestedBy: explanation.RequestedBy,
+			}
+			if pkg.Chain == nil {
+				pkg.Chain = []string{}
+			}
+			if pkg.RequestedBy == nil {
+				pkg.RequestedBy = []string{}
+			}
+			if explanation.Reason != "" {
+				pkg.Reason = common.StringToPtr(explanation.Reason)
+			}
+			if len(pkg.Chain) > 0 {
+				source := PackageExplanationSourceImageType
+				for _, spec := range pkg.RequestedBy {
+					if requested[spec] {
+						source = PackageExplanationSourceRequest
+						break
+					}
+				}
+				pkg.Source = &source
+			}
+			set.Packages = append(set.Packages, pkg)
+		}
+		resp.PackageSets = append(resp.PackageSets, set)
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+func (h *apiHandlers) GetComposeDiff(ctx echo.Context, id string, otherId string) error {
+	var ids [2]uuid.UUID
+	var composes [2]*compare.Compose
+	for i, idString := range []string{id, otherId} {
+		jobId, jobType, err := h.server.tenantCompose(ctx, idString)
+		if err != nil {
+			return err
+		}
+
+		// TODO: support koji builds
+		if jobType != "osbuild" {
+			return HTTPError(ErrorInvalidJobType)
+		}
+
+		ids[i] = jobId
+	}
+
+	// both composes must belong to the tenant before either is looked at
+	for i, jobId := range ids {
+		var err error
+		composes[i], err = h.server.composeContent(jobId)
+		if err != nil {
+			return err
+		}
+	}
+
+	diff, err := compare.Composes(*composes[0], *composes[1])
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorComparingComposes, err)
+	}
+
+	resp := ComposeDiff{
+		ObjectReference: ObjectReference{
+			Href: fmt.Sprintf("/api/image-builder-composer/v2/composes/%v/diff/%v", ids[0], ids[1]),
+			Id:   ids[0].String(),
+			Kind: "ComposeDiff",
+		},
+		OtherId: ids[1].String(),
+		Packages: PackageDiff{
+			Added:   make([]PackageVersion, 0, len(diff.Packages.Added)),
+			Removed: make([]PackageVersion, 0, len(diff.Packages.Removed)),
+			Changed: make([]PackageVersionChange, 0, len(diff.Packages.Changed)),
+		},
+		Blueprint:      fieldChangesFromCompare(diff.Blueprint),
+		Stages:         make([]StageChange, 0, len(diff.Stages)),
+		PartitionTable: fieldChangesFromCompare(diff.PartitionTable),
+	}
+	for _, p := range diff.Packages.Added {
+		resp.Packages.Added = append(resp.Packages.Added, PackageVersion{Name: p.Name, Arch: p.Arch, Version: p.Version})
+	}
+	for _, p := range diff.Packages.Removed {
+		resp.Packages.Removed = append(resp.Packages.Removed, PackageVersion{Name: p.Name, Arch: p.Arch, Version: p.Version})
+	}
+	for _, p := range diff.Packages.Changed {
+		resp.Packages.Changed = append(resp.Packages.Changed, PackageVersionChange{
+			Name:        p.Name,
+			Arch:        p.Arch,
+			FromVersion: p.FromVersion,
+			ToVersion:   p.ToVersion,
+		})
+	}
+	for _, s := range diff.Stages {
+		resp.Stages = append(resp.Stages, StageChange{
+			Pipeline:    s.Pipeline,
+			Type:        s.Type,
+			Index:       s.Index,
+			Change:      StageChangeChange(s.Change),
+			FromOptions: stageOptionsFromCompare(s.FromOptions),
+			ToOptions:   stageOptionsFromCompare(s.ToOptions),
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+// composeContent returns the blueprint, payload packages, and manifest of the
+// osbuild job `id`, which are stored with the manifest job it depends on.
+func (s *Server) composeContent(id uuid.UUID) (*compare.Compose, error) {
+	var result worker.OSBuildJobResult
+	_, deps, err := s.workers.OSBuildJobStatus(id, &result)
+	if err != nil {
+		return nil, HTTPErrorWithInternal(ErrorComposeNotFound, err)
+	}
+	if len(deps) != 1 {
+		return nil, HTTPError(ErrorComposeManifestUnavailable)
+	}
+
+	var manifestResult worker.ManifestJobByIDResult
+	manifestStatus, manifestDeps, err := s.workers.ManifestJobStatus(deps[0], &manifestResult)
+	if err != nil {
+		return nil, HTTPErrorWithInternal(ErrorComposeManifestUnavailable, err)
+	}
+	if manifestStatus.Finished.IsZero() || manifestResult.JobError != nil || len(manifestResult.Manifest) == 0 {
+		return nil, HTTPError(ErrorComposeManifestUnavailable)
+	}
+
+	var manifestJob worker.ManifestJobByID
+	err = s.workers.ManifestJobByID(deps[0], &manifestJob)
+	if err != nil {
+		return nil, HTTPErrorWithInternal(ErrorComparingComposes, err)
+	}
+
+	packages := manifestResult.PackageSpecs
+	if packages == nil && len(manifestDeps) == 1 {
+		// manifests generated by older versions don't record the payload
+		// packages: fall back to all packages except the build root's
+		var depsolveResult worker.DepsolveJobResult
+		_, _, err = s.workers.DepsolveJobStatus(manifestDeps[0], &depsolveResult)
+		if err != nil {
+			return nil, HTTPErrorWithInternal(ErrorComparingComposes, err)
+		}
+		for name, specs := range depsolveResult.PackageSpecs {
+			if name != "build" {
+				packages = append(packages, specs...)
+			}
+		}
+	}
+
+	return &compare.Compose{
+		Blueprint: manifestJob.Blueprint,
+		Packages:  packages,
+		Manifest:  manifestResult.Manifest,
+	}, nil
+}
+
+func fieldChangesFromCompare(changes []compare.FieldChange) []FieldChange {
+	result := make([]FieldChange, 0, len(changes))
+	for _, c := range changes {
+		change := FieldChange{Path: c.Path}
+		if c.From != nil {
+			from := c.From
+			change.From = &from
+		}
+		if c.To != nil {
+			to := c.To
+			change.To = &to
+		}
+		result = append(result, change)
+	}
+	return result
+}
+
+func stageOptionsFromCompare(options json.RawMessage) *map[string]interface{} {
+	if len(options) == 0 {
+		return nil
+	}
+	var result map[string]interface{}
+	if err := json.Unmarshal(options, &result); err != nil || result == nil {
+		return nil
+	}
+	return &result
+}
+
+// requestJobTimeout is how long a request waits for the job it enqueued.
+const requestJobTimeout = time.Minute * 5
+
+// waitForJob waits for a job which was enqueued to answer a request, e.g. to
+// fetch repository metadata, which only workers are supposed to access. The
+// job is canceled if it doesn't finish within requestJobTimeout.
+func (s *Server) waitForJob(ctx context.Context, id uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, requestJobTimeout)
+	defer cancel()
+
+	status, err := s.workers.WaitForJob(ctx, id)
+	if err != nil {
+		if cancelErr := s.workers.Cancel(id); cancelErr != nil {
+			logrus.Errorf("Error canceling job %s: %v", id, cancelErr)
+		}
+		return err
+	}
+	if status.Canceled {
+		return fmt.Errorf("job %s was canceled", id)
+	}
+	return nil
+}
+
+// composeDepsolveJob returns the ID of the depsolve job of the osbuild job
+// `id`, which the osbuild job depends on through its manifest job.
+func (s *Server) composeDepsolveJob(id uuid.UUID) (uuid.UUID, error) {
+	var result worker.OSBuildJobResult
+	_, deps, err := s.workers.OSBuildJobStatus(id, &result)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if len(deps) != 1 {
+		return uuid.Nil, fmt.Errorf("unexpected number of dependencies of job %s: %d", id, len(deps))
+	}
+
+	var manifestResult worker.ManifestJobByIDResult
+	_, deps, err = s.workers.ManifestJobStatus(deps[0], &manifestResult)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if len(deps) != 1 {
+		return uuid.Nil, fmt.Errorf("unexpected number of dependencies of manifest job of %s: %d", id, len(deps))
+	}
+
+	return deps[0], nil
+}
+
+func advisoriesFromRpmmd(advisories []rpmmd.Advisory) []Advisory {
+	result := make([]Advisory, 0, len(advisories))
+	for _, a := range advisories {
+		advisory := Advisory{
+			Id:       a.ID,
+			Type:     a.Type,
+			Title:    a.Title,
+			Packages: make([]AdvisoryPackage, 0, len(a.Packages)),
+		}
+		if a.Severity != "" {
+			severity := a.Severity
+			advisory.Severity = &severity
+		}
+		if a.Issued != "" {
+			issued := a.Issued
+			advisory.Issued = &issued
+		}
+		if len(a.CVEs) > 0 {
+			cves := a.CVEs
+			advisory.Cves = &cves
+		}
+		for _, p := range a.Packages {
+			advisory.Packages = append(advisory.Packages, AdvisoryPackage{
+				Name:    p.Name,
+				Epoch:   int(p.Epoch),
+				Version: p.Version,
+				Release: p.Release,
+				Arch:    p.Arch,
+			})
+		}
+		result = append(result, advisory)
+	}
+	return result
+}
+
 func stagesToPackageMetadata(stages []osbuild.RPMStageMetadata) []PackageMetadata {
 	packages := make([]PackageMetadata, 0)
 	for _, md := range stages {
@@ -1264,6 +2527,12 @@ func genRepoConfig(repo Repository) (*rpmmd.RepoConfig, error) {
 	if repo.IgnoreSsl != nil {
 		repoConfig.IgnoreSSL = *repo.IgnoreSsl
 	}
+	if repo.Proxy != nil {
+		repoConfig.Proxy = *repo.Proxy
+	}
+	if err := repoConfig.ValidateProxy(); err != nil {
+		return nil, HTTPErrorWithInternal(ErrorInvalidRepositoryProxy, err)
+	}
 
 	return repoConfig, nil
 }
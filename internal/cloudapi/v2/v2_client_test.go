@@ -0,0 +1,84 @@
+package v2_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/osbuild-composer/internal/distro/test_distro"
+	"github.com/osbuild/osbuild-composer/internal/osbuild2"
+	"github.com/osbuild/osbuild-composer/internal/target"
+	"github.com/osbuild/osbuild-composer/internal/worker"
+	"github.com/osbuild/osbuild-composer/pkg/cloudapi/client"
+)
+
+func TestClient(t *testing.T) {
+	srv, wrksrv, _, cancel := newV2Server(t, t.TempDir(), []string{""}, false)
+	defer cancel()
+	// fails the next `unavailable` requests, like a restarting composer
+	var unavailable int32
+	handler := srv.Handler("/api/image-builder-composer/v2")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&unavailable, -1) >= 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	c, err := client.NewClientWithResponses(server.URL + "/api/image-builder-composer/v2")
+	require.NoError(t, err)
+
+	var request client.ComposeRequest
+	require.NoError(t, json.Unmarshal([]byte(awsComposeRequest()), &request))
+
+	id, err := c.Compose(context.Background(), request)
+	require.NoError(t, err)
+
+	status, err := c.ComposeStatus(context.Background(), id)
+	require.NoError(t, err)
+	require.Equal(t, client.ComposeStatusValuePending, status.Status)
+
+	_, token, _, _, _, err := wrksrv.RequestJob(context.Background(), test_distro.TestArch3Name, []string{"osbuild"}, []string{""})
+	require.NoError(t, err)
+	res, err := json.Marshal(&worker.OSBuildJobResult{
+		Success:       true,
+		OSBuildOutput: &osbuild2.Result{Success: true},
+		UploadStatus:  "success",
+		TargetResults: []*target.TargetResult{
+			target.NewAWSTargetResult(&target.AWSTargetResultOptions{Ami: "ami-1", Region: "eu-central-1"}),
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, wrksrv.FinishJob(token, res))
+
+	// transient errors are retried
+	atomic.StoreInt32(&unavailable, 2)
+	ctx, cancelWait := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelWait()
+	status, err = c.WaitForCompose(ctx, id, 10*time.Millisecond)
+	require.NoError(t, err)
+	require.Less(t, atomic.LoadInt32(&unavailable), int32(0))
+	require.Equal(t, client.ComposeStatusValueSuccess, status.Status)
+	require.NotNil(t, status.ImageStatus.UploadStatus)
+	require.Equal(t, client.UploadStatusValueSuccess, status.ImageStatus.UploadStatus.Status)
+	require.Equal(t, client.UploadTypes("aws"), status.ImageStatus.UploadStatus.Type)
+
+	// errors of the API are returned as *client.Error
+	_, err = c.ComposeStatus(context.Background(), "not-a-uuid")
+	var apiError *client.Error
+	require.ErrorAs(t, err, &apiError)
+	require.Equal(t, "IMAGE-BUILDER-COMPOSER-14", apiError.Code)
+
+	// ... also while waiting for a compose
+	_, err = c.WaitForCompose(ctx, "not-a-uuid", 10*time.Millisecond)
+	require.ErrorAs(t, err, &apiError)
+	require.Equal(t, "IMAGE-BUILDER-COMPOSER-14", apiError.Code)
+}
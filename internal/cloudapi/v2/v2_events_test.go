@@ -0,0 +1,117 @@
+package v2_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/osbuild-composer/internal/cloudapi/v2"
+	"github.com/osbuild/osbuild-composer/internal/distro/test_distro"
+	"github.com/osbuild/osbuild-composer/internal/osbuild2"
+	"github.com/osbuild/osbuild-composer/internal/test"
+	"github.com/osbuild/osbuild-composer/internal/worker"
+)
+
+// nextServerSentEvent reads the next event from a stream of server-sent
+// events and decodes its data into `data`. It returns the event's type.
+func nextServerSentEvent(t *testing.T, reader *bufio.Reader, data interface{}) string {
+	var event string
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimSuffix(line, "\n")
+
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), data))
+		case line == "" && event != "":
+			return event
+		}
+	}
+}
+
+func TestComposeEvents(t *testing.T) {
+	srv, wrksrv, _, cancel := newV2Server(t, t.TempDir(), []string{""}, false)
+	defer cancel()
+	handler := srv.Handler("/api/image-builder-composer/v2")
+
+	test.TestRoute(t, handler, false, "POST", "/api/image-builder-composer/v2/compose", fmt.Sprintf(`
+	{
+		"distribution": "%s",
+		"image_request":{
+			"architecture": "%s",
+			"image_type": "aws",
+			"repositories": [{
+				"baseurl": "somerepo.org",
+				"rhsm": false
+			}],
+			"upload_options": {
+				"region": "eu-central-1"
+			}
+		 }
+	}`, test_distro.TestDistroName, test_distro.TestArch3Name), http.StatusCreated, `
+	{
+		"href": "/api/image-builder-composer/v2/compose",
+		"kind": "ComposeId"
+	}`, "id")
+
+	jobId, token, _, _, _, err := wrksrv.RequestJob(context.Background(), test_distro.TestArch3Name, []string{"osbuild"}, []string{""})
+	require.NoError(t, err)
+	require.NoError(t, wrksrv.AppendJobLog(token, []string{"first line"}))
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	response, err := http.Get(fmt.Sprintf("%s/api/image-builder-composer/v2/composes/%v/events", server.URL, jobId))
+	require.NoError(t, err)
+	defer response.Body.Close()
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	require.Equal(t, "text/event-stream", response.Header.Get("Content-Type"))
+	reader := bufio.NewReader(response.Body)
+
+	var event v2.ComposeEvent
+	require.Equal(t, "status", nextServerSentEvent(t, reader, &event))
+	require.Equal(t, v2.ComposeNotificationStatus("building"), *event.Status)
+
+	event = v2.ComposeEvent{}
+	require.Equal(t, "log", nextServerSentEvent(t, reader, &event))
+	require.Equal(t, jobId.String(), *event.JobId)
+	require.Equal(t, []string{"first line"}, *event.Lines)
+
+	require.NoError(t, wrksrv.AppendJobLog(token, []string{"second line", "third line"}))
+	event = v2.ComposeEvent{}
+	require.Equal(t, "log", nextServerSentEvent(t, reader, &event))
+	require.Equal(t, []string{"second line", "third line"}, *event.Lines)
+
+	result, err := json.Marshal(&worker.OSBuildJobResult{
+		Success:       true,
+		OSBuildOutput: &osbuild2.Result{},
+	})
+	require.NoError(t, err)
+	require.NoError(t, wrksrv.FinishJob(token, result))
+	event = v2.ComposeEvent{}
+	require.Equal(t, "status", nextServerSentEvent(t, reader, &event))
+	require.Equal(t, v2.ComposeNotificationStatus("success"), *event.Status)
+
+	// the stream ends after the compose finished
+	_, err = reader.ReadString('\n')
+	require.Error(t, err)
+
+	test.TestRoute(t, handler, false, "GET", "/api/image-builder-composer/v2/composes/not-a-uuid/events", ``, http.StatusBadRequest, `
+	{
+		"href": "/api/image-builder-composer/v2/errors/14",
+		"id": "14",
+		"kind": "Error",
+		"code": "IMAGE-BUILDER-COMPOSER-14",
+		"reason": "Invalid format for compose id"
+	}`, "operation_id")
+}
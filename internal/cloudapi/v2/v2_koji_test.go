@@ -14,7 +14,9 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 
+	"github.com/osbuild/osbuild-composer/internal/blueprint"
 	v2 "github.com/osbuild/osbuild-composer/internal/cloudapi/v2"
+	"github.com/osbuild/osbuild-composer/internal/common"
 	"github.com/osbuild/osbuild-composer/internal/distro/test_distro"
 	"github.com/osbuild/osbuild-composer/internal/kojiapi/api"
 	osbuild "github.com/osbuild/osbuild-composer/internal/osbuild2"
@@ -460,6 +462,231 @@ func TestKojiCompose(t *testing.T) {
 	}
 }
 
+func TestKojiComposeCustomizations(t *testing.T) {
+	kojiServer, workerServer, _, cancel := newV2Server(t, t.TempDir(), []string{""}, false)
+	handler := kojiServer.Handler("/api/image-builder-composer/v2")
+	defer cancel()
+
+	result := test.APICall{
+		Handler: handler,
+		Method:  http.MethodPost,
+		Path:    "/api/image-builder-composer/v2/compose",
+		RequestBody: test.JSONRequestBody(fmt.Sprintf(`
+		{
+			"distribution": "%s",
+			"customizations": {
+				"packages": [ "pkg1" ],
+				"hostname": "builder",
+				"kernel": {
+					"name": "kernel-debug",
+					"append": "nosmt=force"
+				},
+				"groups": [{
+					"name": "group1",
+					"gid": 1030
+				}],
+				"timezone": {
+					"timezone": "Europe/Berlin",
+					"ntpservers": [ "time.example.com" ]
+				},
+				"locale": {
+					"languages": [ "en_US.UTF-8" ],
+					"keyboard": "us"
+				},
+				"firewall": {
+					"ports": [ "22:tcp" ],
+					"services": {
+						"enabled": [ "ssh" ]
+					}
+				},
+				"services": {
+					"enabled": [ "sshd" ],
+					"disabled": [ "cockpit.socket" ]
+				}
+			},
+			"image_request": {
+				"architecture": "%s",
+				"image_type": "guest-image",
+				"repositories": [{
+					"baseurl": "https://repo.example.com/"
+				}],
+				"ostree": {
+					"ref": "test/ref"
+				}
+			},
+			"koji": {
+				"server": "koji.example.com",
+				"task_id": 42,
+				"name": "foo",
+				"version": "1",
+				"release": "2"
+			}
+		}`, test_distro.TestDistroName, test_distro.TestArch3Name)),
+		ExpectedStatus: http.StatusCreated,
+	}.Do(t)
+
+	var id v2.ComposeId
+	require.NoError(t, json.Unmarshal(result.Body, &id))
+	finalizeID, err := uuid.Parse(id.Id)
+	require.NoError(t, err)
+
+	// koji-finalize depends on koji-init and the osbuild-koji job, which
+	// depends on koji-init and the manifest job
+	_, deps, err := workerServer.KojiFinalizeJobStatus(finalizeID, &worker.KojiFinalizeJobResult{})
+	require.NoError(t, err)
+	require.Len(t, deps, 2)
+	_, deps, err = workerServer.OSBuildKojiJobStatus(deps[1], &worker.OSBuildKojiJobResult{})
+	require.NoError(t, err)
+	require.Len(t, deps, 2)
+
+	var manifestJob worker.ManifestJobByID
+	require.NoError(t, workerServer.ManifestJobByID(deps[1], &manifestJob))
+	customizations := manifestJob.Blueprint.Customizations
+	require.NotNil(t, customizations)
+	require.Equal(t, "builder", *customizations.Hostname)
+	require.Equal(t, &blueprint.KernelCustomization{Name: "kernel-debug", Append: "nosmt=force"}, customizations.Kernel)
+	require.Equal(t, []blueprint.GroupCustomization{{Name: "group1", GID: common.IntToPtr(1030)}}, customizations.Group)
+	require.Equal(t, &blueprint.TimezoneCustomization{Timezone: common.StringToPtr("Europe/Berlin"), NTPServers: []string{"time.example.com"}}, customizations.Timezone)
+	require.Equal(t, &blueprint.LocaleCustomization{Languages: []string{"en_US.UTF-8"}, Keyboard: common.StringToPtr("us")}, customizations.Locale)
+	require.Equal(t, &blueprint.FirewallCustomization{
+		Ports:    []string{"22:tcp"},
+		Services: &blueprint.FirewallServicesCustomization{Enabled: []string{"ssh"}},
+	}, customizations.Firewall)
+	require.Equal(t, &blueprint.ServicesCustomization{Enabled: []string{"sshd"}, Disabled: []string{"cockpit.socket"}}, customizations.Services)
+	require.Equal(t, []blueprint.Package{{Name: "pkg1"}}, manifestJob.Blueprint.Packages)
+}
+
+func TestKojiComposeFailure(t *testing.T) {
+	server, workers, _, cancel := newV2Server(t, t.TempDir(), []string{""}, false)
+	handler := server.Handler("/api/image-builder-composer/v2")
+	defer cancel()
+
+	initID, err := workers.EnqueueKojiInit(&worker.KojiInitJob{Server: "test-server"}, "")
+	require.NoError(t, err)
+	buildID, err := workers.EnqueueOSBuildKoji(test_distro.TestArchName, &worker.OSBuildKojiJob{KojiFilename: "image"}, initID, "")
+	require.NoError(t, err)
+	finalizeID, err := workers.EnqueueKojiFinalize(&worker.KojiFinalizeJob{KojiFilenames: []string{"image"}}, initID, []uuid.UUID{buildID}, "")
+	require.NoError(t, err)
+
+	finishJob := func(jobType string, result interface{}) {
+		_, token, _, _, _, err := workers.RequestJob(context.Background(), test_distro.TestArchName, []string{jobType}, []string{""})
+		require.NoError(t, err)
+		raw, err := json.Marshal(result)
+		require.NoError(t, err)
+		require.NoError(t, workers.FinishJob(token, raw))
+	}
+	finishJob("koji-init", &worker.KojiInitJobResult{BuildID: 42, Token: "token"})
+	finishJob("osbuild-koji", &worker.OSBuildKojiJobResult{OSBuildOutput: &osbuild.Result{Success: true}})
+	// the images don't need to be built again when only the import failed
+	finishJob("koji-finalize", &worker.KojiFinalizeJobResult{
+		Failure: worker.KojiFailureImport,
+		JobResult: worker.JobResult{
+			JobError: clienterrors.WorkerClientError(clienterrors.ErrorKojiFinalize, "Koji finalize error"),
+		},
+	})
+
+	result := test.APICall{
+		Handler:        handler,
+		Method:         http.MethodGet,
+		Path:           fmt.Sprintf("/api/image-builder-composer/v2/composes/%v", finalizeID),
+		ExpectedStatus: http.StatusOK,
+	}.Do(t)
+
+	var status v2.ComposeStatus
+	require.NoError(t, json.Unmarshal(result.Body, &status))
+	require.Equal(t, v2.ComposeStatusValueFailure, status.Status)
+	require.Equal(t, v2.ImageStatusValueSuccess, status.ImageStatus.Status)
+	require.Equal(t, 42, *status.KojiStatus.BuildId)
+	require.Equal(t, v2.KojiStatusFailureImport, *status.KojiStatus.Failure)
+}
+
+func TestKojiComposeRetry(t *testing.T) {
+	server, workers, _, cancel := newV2Server(t, t.TempDir(), []string{""}, false)
+	handler := server.Handler("/api/image-builder-composer/v2")
+	defer cancel()
+
+	initID, err := workers.EnqueueKojiInit(&worker.KojiInitJob{Server: "test-server"}, "")
+	require.NoError(t, err)
+	var buildIDs []uuid.UUID
+	for _, filename := range []string{"image-1", "image-2"} {
+		buildID, err := workers.EnqueueOSBuildKoji(test_distro.TestArchName, &worker.OSBuildKojiJob{KojiFilename: filename}, initID, "")
+		require.NoError(t, err)
+		buildIDs = append(buildIDs, buildID)
+	}
+	finalizeID, err := workers.EnqueueKojiFinalize(&worker.KojiFinalizeJob{KojiFilenames: []string{"image-1", "image-2"}}, initID, buildIDs, "")
+	require.NoError(t, err)
+
+	finishJob := func(jobType string, result interface{}) uuid.UUID {
+		id, token, _, _, _, err := workers.RequestJob(context.Background(), test_distro.TestArchName, []string{jobType}, []string{""})
+		require.NoError(t, err)
+		raw, err := json.Marshal(result)
+		require.NoError(t, err)
+		require.NoError(t, workers.FinishJob(token, raw))
+		return id
+	}
+	retry := func(expectedStatus int) []byte {
+		return test.APICall{
+			Handler:        handler,
+			Method:         http.MethodPost,
+			Path:           fmt.Sprintf("/api/image-builder-composer/v2/composes/%v/retry", finalizeID),
+			ExpectedStatus: expectedStatus,
+		}.Do(t).Body
+	}
+
+	finishJob("koji-init", &worker.KojiInitJobResult{BuildID: 42, Token: "token"})
+	finishJob("osbuild-koji", &worker.OSBuildKojiJobResult{OSBuildOutput: &osbuild.Result{Success: true}})
+	failedID := finishJob("osbuild-koji", &worker.OSBuildKojiJobResult{
+		OSBuildOutput: &osbuild.Result{Success: true},
+		JobResult: worker.JobResult{
+			JobError: clienterrors.WorkerClientError(clienterrors.ErrorKojiBuild, "Koji upload error"),
+		},
+	})
+
+	// only finished composes can be retried
+	retry(http.StatusConflict)
+
+	finishJob("koji-finalize", &worker.KojiFinalizeJobResult{
+		Failure:   worker.KojiFailureUpload,
+		Retryable: true,
+		JobResult: worker.JobResult{
+			JobError: clienterrors.WorkerClientError(clienterrors.ErrorKojiFailedDependency, "Koji upload failed"),
+		},
+	})
+
+	result := test.APICall{
+		Handler:        handler,
+		Method:         http.MethodGet,
+		Path:           fmt.Sprintf("/api/image-builder-composer/v2/composes/%v", finalizeID),
+		ExpectedStatus: http.StatusOK,
+	}.Do(t)
+	var status v2.ComposeStatus
+	require.NoError(t, json.Unmarshal(result.Body, &status))
+	require.Equal(t, v2.ComposeStatusValueFailure, status.Status)
+	require.Equal(t, v2.KojiStatusFailureUpload, *status.KojiStatus.Failure)
+	require.True(t, *status.KojiStatus.Retryable)
+
+	status = v2.ComposeStatus{}
+	require.NoError(t, json.Unmarshal(retry(http.StatusOK), &status))
+	require.Equal(t, v2.ComposeStatusValuePending, status.Status)
+	require.Nil(t, status.KojiStatus.Retryable)
+
+	// only the image which failed to upload is built again, and the
+	// finalize job knows it is the second attempt
+	require.Equal(t, failedID, finishJob("osbuild-koji", &worker.OSBuildKojiJobResult{OSBuildOutput: &osbuild.Result{Success: true}}))
+	var finalizeJob worker.KojiFinalizeJob
+	require.NoError(t, workers.KojiFinalizeJob(finalizeID, &finalizeJob))
+	require.Equal(t, 1, finalizeJob.Attempt)
+
+	finishJob("koji-finalize", &worker.KojiFinalizeJobResult{
+		Failure: worker.KojiFailureImport,
+		JobResult: worker.JobResult{
+			JobError: clienterrors.WorkerClientError(clienterrors.ErrorKojiFinalize, "Koji finalize error"),
+		},
+	})
+	// the koji build was failed, because there were no attempts left
+	retry(http.StatusConflict)
+}
+
 func TestKojiRequest(t *testing.T) {
 	dir, err := ioutil.TempDir("", "osbuild-composer-test-api-v2-")
 	require.NoError(t, err)
@@ -276,3 +276,189 @@ func TestMultitenancy(t *testing.T) {
 		require.NotEqual(t, "pending", result.Status)
 	}
 }
+
+func TestComposeList(t *testing.T) {
+	dir, err := ioutil.TempDir("", "osbuild-composer-test-api-v2-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	apiServer, _, _, cancel := newV2Server(t, dir, []string{}, true)
+	handler := apiServer.Handler("/api/image-builder-composer/v2")
+	defer cancel()
+
+	s3ID := scheduleRequest(t, handler, "42", s3Request())
+	kojiID := scheduleRequest(t, handler, "42", kojiRequest())
+	otherID := scheduleRequest(t, handler, "123", s3Request())
+
+	listComposes := func(orgID, query string) v2.ComposeList {
+		result := test.APICall{
+			Handler:        handler,
+			Context:        reqContext(orgID),
+			Method:         http.MethodGet,
+			Path:           "/api/image-builder-composer/v2/composes" + query,
+			ExpectedStatus: http.StatusOK,
+		}.Do(t)
+
+		var list v2.ComposeList
+		require.NoError(t, json.Unmarshal(result.Body, &list))
+		require.Equal(t, "ComposeList", list.Kind)
+		require.Equal(t, len(list.Items), list.Size)
+		return list
+	}
+
+	ids := func(list v2.ComposeList) []string {
+		result := []string{}
+		for _, item := range list.Items {
+			result = append(result, item.Id)
+		}
+		return result
+	}
+
+	// tenants only see their own composes, most recent first
+	list := listComposes("42", "")
+	require.Equal(t, 2, list.Total)
+	require.Equal(t, []string{kojiID.String(), s3ID.String()}, ids(list))
+	require.Equal(t, v2.ComposeStatusValuePending, list.Items[0].Status)
+
+	list = listComposes("123", "")
+	require.Equal(t, 1, list.Total)
+	require.Equal(t, []string{otherID.String()}, ids(list))
+
+	list = listComposes("987", "")
+	require.Equal(t, 0, list.Total)
+	require.Empty(t, list.Items)
+
+	// pagination
+	list = listComposes("42", "?page=1&size=1")
+	require.Equal(t, 2, list.Total)
+	require.Equal(t, 1, list.Page)
+	require.Equal(t, []string{s3ID.String()}, ids(list))
+
+	list = listComposes("42", "?page=2&size=1")
+	require.Equal(t, 2, list.Total)
+	require.Empty(t, list.Items)
+
+	// filters
+	list = listComposes("42", "?status=pending")
+	require.Equal(t, 2, list.Total)
+
+	list = listComposes("42", "?status=success")
+	require.Equal(t, 0, list.Total)
+
+	list = listComposes("42", "?image_type=guest-image&distribution="+test_distro.TestDistroName)
+	require.Equal(t, 2, list.Total)
+
+	list = listComposes("42", "?image_type=aws")
+	require.Equal(t, 0, list.Total)
+
+	list = listComposes("42", "?distribution=unknown")
+	require.Equal(t, 0, list.Total)
+
+	list = listComposes("42", "?created_after=2000-01-01T00:00:00Z&created_before=3000-01-01T00:00:00Z")
+	require.Equal(t, 2, list.Total)
+
+	list = listComposes("42", "?created_after=3000-01-01T00:00:00Z")
+	require.Equal(t, 0, list.Total)
+
+	for _, query := range []string{"?page=-1", "?size=x", "?status=done", "?image_type=iso"} {
+		test.APICall{
+			Handler:        handler,
+			Context:        reqContext("42"),
+			Method:         http.MethodGet,
+			Path:           "/api/image-builder-composer/v2/composes" + query,
+			ExpectedStatus: http.StatusBadRequest,
+		}.Do(t)
+	}
+}
+
+func TestComposeCancelAndDeleteOtherTenant(t *testing.T) {
+	dir, err := ioutil.TempDir("", "osbuild-composer-test-api-v2-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	apiServer, _, _, cancel := newV2Server(t, dir, []string{}, true)
+	handler := apiServer.Handler("/api/image-builder-composer/v2")
+	defer cancel()
+
+	for _, request := range []string{s3Request(), kojiRequest()} {
+		id := scheduleRequest(t, handler, "42", request)
+		path := fmt.Sprintf("/api/image-builder-composer/v2/composes/%v", id)
+
+		// other tenants can neither cancel nor delete the compose
+		test.APICall{
+			Handler:        handler,
+			Context:        reqContext("123"),
+			Method:         http.MethodPost,
+			Path:           path + "/cancel",
+			ExpectedStatus: http.StatusNotFound,
+		}.Do(t)
+		test.APICall{
+			Handler:        handler,
+			Context:        reqContext("123"),
+			Method:         http.MethodDelete,
+			Path:           path,
+			ExpectedStatus: http.StatusNotFound,
+		}.Do(t)
+
+		result := test.APICall{
+			Handler:        handler,
+			Context:        reqContext("42"),
+			Method:         http.MethodPost,
+			Path:           path + "/cancel",
+			ExpectedStatus: http.StatusOK,
+		}.Do(t)
+		var status v2.ComposeStatus
+		require.NoError(t, json.Unmarshal(result.Body, &status))
+		require.Equal(t, v2.ComposeStatusValueFailure, status.Status)
+
+		test.APICall{
+			Handler:        handler,
+			Context:        reqContext("42"),
+			Method:         http.MethodDelete,
+			Path:           path,
+			ExpectedStatus: http.StatusNoContent,
+		}.Do(t)
+	}
+
+	list := test.APICall{
+		Handler:        handler,
+		Context:        reqContext("42"),
+		Method:         http.MethodGet,
+		Path:           "/api/image-builder-composer/v2/composes",
+		ExpectedStatus: http.StatusOK,
+	}.Do(t)
+	require.Contains(t, string(list.Body), `"total":0`)
+}
+
+func TestComposeContentOtherTenant(t *testing.T) {
+	dir, err := ioutil.TempDir("", "osbuild-composer-test-api-v2-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	apiServer, _, _, cancel := newV2Server(t, dir, []string{}, true)
+	handler := apiServer.Handler("/api/image-builder-composer/v2")
+	defer cancel()
+
+	id := scheduleRequest(t, handler, "42", s3Request())
+	otherID := scheduleRequest(t, handler, "123", s3Request())
+	path := fmt.Sprintf("/api/image-builder-composer/v2/composes/%v", id)
+
+	// other tenants can't look into the compose, not even by comparing it
+	// with one of their own
+	for _, subpath := range []string{"/advisories", "/sbom", "/diff/" + otherID.String()} {
+		test.APICall{
+			Handler:        handler,
+			Context:        reqContext("123"),
+			Method:         http.MethodGet,
+			Path:           path + subpath,
+			ExpectedStatus: http.StatusNotFound,
+		}.Do(t)
+	}
+	test.APICall{
+		Handler:        handler,
+		Context:        reqContext("123"),
+		Method:         http.MethodGet,
+		Path:           fmt.Sprintf("/api/image-builder-composer/v2/composes/%v/diff/%v", otherID, id),
+		ExpectedStatus: http.StatusNotFound,
+	}.Do(t)
+}
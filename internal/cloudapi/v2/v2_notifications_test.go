@@ -0,0 +1,142 @@
+package v2_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/osbuild-composer/internal/cloudapi/v2"
+	"github.com/osbuild/osbuild-composer/internal/notification"
+	"github.com/osbuild/osbuild-composer/internal/notification/fsstore"
+	"github.com/osbuild/osbuild-composer/internal/test"
+	"github.com/osbuild/osbuild-composer/internal/worker"
+)
+
+// newNotificationServer returns the handler of a cloud API with JWT. Its
+// composes can be subscribed to notifications unless `notifier` is nil.
+func newNotificationServer(t *testing.T, notifier *notification.Notifier) http.Handler {
+	config := v2.ServerConfig{
+		JWTEnabled:           true,
+		TenantProviderFields: []string{"rh-org-id", "account_id"},
+	}
+	apiServer, _, _, cancel := newV2ServerWithConfig(t, t.TempDir(), []string{}, config, worker.Config{Notifier: notifier})
+	t.Cleanup(cancel)
+
+	return apiServer.Handler("/api/image-builder-composer/v2")
+}
+
+// webhookRequest returns s3Request() with the given webhooks.
+func webhookRequest(t *testing.T, webhooks ...v2.Webhook) string {
+	var request map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(s3Request()), &request))
+	request["webhooks"] = webhooks
+
+	body, err := json.Marshal(request)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func requireErrorCode(t *testing.T, body []byte, code v2.ServiceErrorCode) {
+	var apiErr struct {
+		Code string `json:"code"`
+	}
+	require.NoError(t, json.Unmarshal(body, &apiErr))
+	require.Equal(t, fmt.Sprintf("%s%d", v2.ErrorCodePrefix, code), apiErr.Code)
+}
+
+func TestComposeNotifications(t *testing.T) {
+	events := make(chan notification.Event, 10)
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event notification.Event
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&event))
+		events <- event
+	}))
+	defer webhook.Close()
+
+	store, err := fsstore.New(t.TempDir())
+	require.NoError(t, err)
+	notifier, err := notification.New(notification.Config{Client: &http.Client{}}, store)
+	require.NoError(t, err)
+	defer notifier.Close()
+	handler := newNotificationServer(t, notifier)
+
+	result := test.APICall{
+		Handler:        handler,
+		Context:        reqContext("notified-org"),
+		Method:         http.MethodPost,
+		Path:           "/api/image-builder-composer/v2/compose",
+		RequestBody:    test.JSONRequestBody(webhookRequest(t, v2.Webhook{Url: "ftp://example.com/hook"})),
+		ExpectedStatus: http.StatusBadRequest,
+	}.Do(t)
+	requireErrorCode(t, result.Body, v2.ErrorInvalidWebhook)
+
+	id := scheduleRequest(t, handler, "notified-org", webhookRequest(t, v2.Webhook{Url: webhook.URL}))
+
+	select {
+	case event := <-events:
+		require.Equal(t, id, event.ComposeID)
+		require.Equal(t, notification.StatusPending, event.Status)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "no notification was sent")
+	}
+
+	path := "/api/image-builder-composer/v2/composes/" + id.String() + "/notifications"
+	var notifications v2.ComposeNotifications
+	require.Eventually(t, func() bool {
+		result := test.APICall{
+			Handler:        handler,
+			Context:        reqContext("notified-org"),
+			Method:         http.MethodGet,
+			Path:           path,
+			ExpectedStatus: http.StatusOK,
+		}.Do(t)
+		require.NoError(t, json.Unmarshal(result.Body, &notifications))
+		return len(notifications.Deliveries) == 1 && notifications.Deliveries[0].Delivered
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, id.String(), notifications.Id)
+	require.Equal(t, webhook.URL, notifications.Deliveries[0].Url)
+	require.Equal(t, v2.ComposeNotificationStatus("pending"), notifications.Deliveries[0].Status)
+
+	// other tenants can't see the deliveries
+	test.APICall{
+		Handler:        handler,
+		Context:        reqContext("other-org"),
+		Method:         http.MethodGet,
+		Path:           path,
+		ExpectedStatus: http.StatusNotFound,
+	}.Do(t)
+
+	// composes without webhooks have no deliveries
+	id = scheduleRequest(t, handler, "notified-org", s3Request())
+	result = test.APICall{
+		Handler:        handler,
+		Context:        reqContext("notified-org"),
+		Method:         http.MethodGet,
+		Path:           "/api/image-builder-composer/v2/composes/" + id.String() + "/notifications",
+		ExpectedStatus: http.StatusOK,
+	}.Do(t)
+	require.NoError(t, json.Unmarshal(result.Body, &notifications))
+	require.Equal(t, "ComposeNotifications", notifications.Kind)
+	require.Empty(t, notifications.Deliveries)
+}
+
+func TestComposeNotificationsDisabled(t *testing.T) {
+	handler := newNotificationServer(t, nil)
+
+	result := test.APICall{
+		Handler:        handler,
+		Context:        reqContext("notified-org"),
+		Method:         http.MethodPost,
+		Path:           "/api/image-builder-composer/v2/compose",
+		RequestBody:    test.JSONRequestBody(webhookRequest(t, v2.Webhook{Url: "https://example.com/hook"})),
+		ExpectedStatus: http.StatusBadRequest,
+	}.Do(t)
+	requireErrorCode(t, result.Body, v2.ErrorNotificationsDisabled)
+
+	scheduleRequest(t, handler, "notified-org", s3Request())
+}
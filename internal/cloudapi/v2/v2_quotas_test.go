@@ -0,0 +1,118 @@
+package v2_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/osbuild-composer/internal/cloudapi/v2"
+	"github.com/osbuild/osbuild-composer/internal/distro/test_distro"
+	"github.com/osbuild/osbuild-composer/internal/test"
+	"github.com/osbuild/osbuild-composer/internal/worker"
+)
+
+// newQuotaServer returns the handler of a cloud API with JWT and the given
+// quotas. None of its jobs are ever run.
+func newQuotaServer(t *testing.T, quotas v2.Quotas, tenantQuotas map[string]v2.Quotas) (http.Handler, *worker.Server) {
+	config := v2.ServerConfig{
+		JWTEnabled:           true,
+		TenantProviderFields: []string{"rh-org-id", "account_id"},
+		Quotas:               quotas,
+		TenantQuotas:         tenantQuotas,
+	}
+	apiServer, workerServer, _, cancel := newV2ServerWithConfig(t, t.TempDir(), []string{}, config, worker.Config{})
+	t.Cleanup(cancel)
+
+	return apiServer.Handler("/api/image-builder-composer/v2"), workerServer
+}
+
+// requireQuotaExceeded sends a compose request for `orgID`, which must be
+// rejected because of `quota`. It returns the Retry-After header in seconds,
+// or -1 if there is none.
+func requireQuotaExceeded(t *testing.T, handler http.Handler, orgID, quota string) int {
+	result := test.APICall{
+		Handler:        handler,
+		Context:        reqContext(orgID),
+		Method:         http.MethodPost,
+		Path:           "/api/image-builder-composer/v2/compose",
+		RequestBody:    test.JSONRequestBody(s3Request()),
+		ExpectedStatus: http.StatusTooManyRequests,
+	}.Do(t)
+
+	var apiErr struct {
+		Code    string                 `json:"code"`
+		Details map[string]interface{} `json:"details"`
+	}
+	require.NoError(t, json.Unmarshal(result.Body, &apiErr))
+	require.Equal(t, fmt.Sprintf("%s%d", v2.ErrorCodePrefix, v2.ErrorQuotaExceeded), apiErr.Code)
+	require.Equal(t, quota, apiErr.Details["quota"])
+
+	header := result.Header.Get("Retry-After")
+	if header == "" {
+		require.NotContains(t, apiErr.Details, "retry_after")
+		return -1
+	}
+	retryAfter, err := strconv.Atoi(header)
+	require.NoError(t, err)
+	require.Equal(t, float64(retryAfter), apiErr.Details["retry_after"])
+	return retryAfter
+}
+
+func TestQuotaConcurrentComposes(t *testing.T) {
+	handler, _ := newQuotaServer(t, v2.Quotas{MaxConcurrentComposes: 2}, nil)
+
+	first := scheduleRequest(t, handler, "quota-org", s3Request())
+	scheduleRequest(t, handler, "quota-org", s3Request())
+	require.Equal(t, 60, requireQuotaExceeded(t, handler, "quota-org", "max_concurrent_composes"))
+
+	// the quota is per tenant
+	scheduleRequest(t, handler, "other-org", s3Request())
+
+	// canceled composes don't count
+	test.APICall{
+		Handler:        handler,
+		Context:        reqContext("quota-org"),
+		Method:         http.MethodPost,
+		Path:           "/api/image-builder-composer/v2/composes/" + first.String() + "/cancel",
+		ExpectedStatus: http.StatusOK,
+	}.Do(t)
+	scheduleRequest(t, handler, "quota-org", s3Request())
+}
+
+func TestQuotaComposesPerHour(t *testing.T) {
+	handler, _ := newQuotaServer(t, v2.Quotas{}, map[string]v2.Quotas{
+		"quota-org": {MaxComposesPerHour: 1},
+	})
+
+	scheduleRequest(t, handler, "quota-org", s3Request())
+	retryAfter := requireQuotaExceeded(t, handler, "quota-org", "max_composes_per_hour")
+	require.Greater(t, retryAfter, 3500)
+	require.LessOrEqual(t, retryAfter, 3600)
+
+	// tenants without their own quotas get the unlimited defaults
+	scheduleRequest(t, handler, "other-org", s3Request())
+	scheduleRequest(t, handler, "other-org", s3Request())
+}
+
+func TestQuotaImageSizePerDay(t *testing.T) {
+	const gib = 1024 * 1024 * 1024
+	handler, workerServer := newQuotaServer(t, v2.Quotas{MaxImageSizePerDay: 10 * gib}, nil)
+
+	// the images of the mock distro have no size, enqueue some that do
+	for _, size := range []uint64{6 * gib, 6 * gib} {
+		_, err := workerServer.EnqueueOSBuild(test_distro.TestArchName, &worker.OSBuildJob{ImageSize: size}, "org-quota-org")
+		require.NoError(t, err)
+	}
+
+	retryAfter := requireQuotaExceeded(t, handler, "quota-org", "max_image_size_per_day")
+	require.Greater(t, retryAfter, 24*3600-100)
+	require.LessOrEqual(t, retryAfter, 24*3600)
+
+	id := scheduleRequest(t, handler, "other-org", s3Request())
+	require.NotEqual(t, uuid.Nil, id)
+}
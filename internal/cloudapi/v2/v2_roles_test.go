@@ -0,0 +1,96 @@
+package v2_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
+	"github.com/openshift-online/ocm-sdk-go/authentication"
+
+	"github.com/osbuild/osbuild-composer/internal/auth"
+	"github.com/osbuild/osbuild-composer/internal/cloudapi/v2"
+	"github.com/osbuild/osbuild-composer/internal/test"
+	"github.com/osbuild/osbuild-composer/internal/worker"
+)
+
+func roleContext(groups ...string) context.Context {
+	claims := jwt.MapClaims{
+		"rh-org-id": "42",
+	}
+	if len(groups) > 0 {
+		var g []interface{}
+		for _, group := range groups {
+			g = append(g, group)
+		}
+		claims["groups"] = g
+	}
+	return authentication.ContextWithToken(context.Background(), &jwt.Token{Claims: claims})
+}
+
+func TestRoles(t *testing.T) {
+	config := v2.ServerConfig{
+		JWTEnabled:           true,
+		TenantProviderFields: []string{"rh-org-id"},
+		Roles: &auth.RoleMapping{
+			Claims: []string{"groups"},
+			Groups: map[string]auth.Role{
+				"viewers":  auth.RoleViewer,
+				"builders": auth.RoleBuilder,
+				"admins":   auth.RoleAdmin,
+			},
+		},
+	}
+	apiServer, _, _, cancel := newV2ServerWithConfig(t, t.TempDir(), []string{}, config, worker.Config{})
+	handler := apiServer.Handler("/api/image-builder-composer/v2")
+	defer cancel()
+
+	composePath := "/api/image-builder-composer/v2/composes/" + uuid.New().String()
+
+	tests := []struct {
+		ctx    context.Context
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		// without a matching group, nothing is allowed
+		{roleContext(), http.MethodGet, composePath, "", http.StatusForbidden},
+		{roleContext("unknown"), http.MethodGet, composePath, "", http.StatusForbidden},
+
+		// the spec is public, it is requested without a token
+		{context.Background(), http.MethodGet, "/api/image-builder-composer/v2/openapi", "", http.StatusOK},
+		{roleContext(), http.MethodGet, "/api/image-builder-composer/v2/openapi", "", http.StatusOK},
+
+		// viewers can read composes, but not start them or list errors
+		{roleContext("viewers"), http.MethodGet, composePath, "", http.StatusNotFound},
+		{roleContext("viewers"), http.MethodPost, "/api/image-builder-composer/v2/compose", "{}", http.StatusForbidden},
+		{roleContext("viewers"), http.MethodGet, "/api/image-builder-composer/v2/errors", "", http.StatusForbidden},
+
+		// builders can start composes (the request is invalid, but it passes
+		// the role check), but not list errors
+		{roleContext("builders"), http.MethodGet, composePath, "", http.StatusNotFound},
+		{roleContext("builders"), http.MethodPost, "/api/image-builder-composer/v2/compose", "{}", http.StatusBadRequest},
+		{roleContext("builders"), http.MethodGet, "/api/image-builder-composer/v2/errors", "", http.StatusForbidden},
+		{roleContext("builders"), http.MethodGet, "/api/image-builder-composer/v2/errors/4", "", http.StatusForbidden},
+
+		// admins can do everything, and the highest role wins
+		{roleContext("admins"), http.MethodGet, "/api/image-builder-composer/v2/errors", "", http.StatusOK},
+		{roleContext("viewers", "admins"), http.MethodGet, "/api/image-builder-composer/v2/errors/4", "", http.StatusOK},
+		{roleContext("admins"), http.MethodPost, "/api/image-builder-composer/v2/compose", "{}", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			test.APICall{
+				Handler:        handler,
+				Context:        tt.ctx,
+				Method:         tt.method,
+				Path:           tt.path,
+				RequestBody:    test.JSONRequestBody(tt.body),
+				ExpectedStatus: tt.status,
+			}.Do(t)
+		})
+	}
+}
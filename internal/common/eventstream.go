@@ -0,0 +1,62 @@
+package common
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// How often event streams send a comment while there are no events, so that
+// proxies don't close idle connections
+const EventStreamKeepAlive = 15 * time.Second
+
+// EventStream writes server-sent events (text/event-stream) to the response
+// of an HTTP request.
+type EventStream struct {
+	writer  http.ResponseWriter
+	flusher http.Flusher
+}
+
+// NewEventStream sends the headers of an event stream to `writer`. It fails
+// if `writer` doesn't support flushing, because events would be buffered
+// instead of being sent right away.
+func NewEventStream(writer http.ResponseWriter) (*EventStream, error) {
+	flusher, ok := writer.(http.Flusher)
+	if !ok {
+		return nil, errors.New("response writer does not support streaming")
+	}
+
+	writer.Header().Set("Content-Type", "text/event-stream")
+	writer.Header().Set("Cache-Control", "no-cache")
+	writer.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	return &EventStream{writer, flusher}, nil
+}
+
+// Send writes an event of type `event` whose data is `data` encoded as JSON.
+func (s *EventStream) Send(event string, data interface{}) error {
+	buf, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(s.writer, "event: %s\ndata: %s\n\n", event, buf)
+	if err != nil {
+		return err
+	}
+	s.flusher.Flush()
+	return nil
+}
+
+// KeepAlive writes a comment, which clients ignore.
+func (s *EventStream) KeepAlive() error {
+	_, err := fmt.Fprint(s.writer, ": keep-alive\n\n")
+	if err != nil {
+		return err
+	}
+	s.flusher.Flush()
+	return nil
+}
@@ -0,0 +1,29 @@
+package common
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type noFlushWriter struct {
+	http.ResponseWriter
+}
+
+func TestEventStream(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	stream, err := NewEventStream(recorder)
+	require.NoError(t, err)
+	require.Equal(t, "text/event-stream", recorder.Header().Get("Content-Type"))
+	require.True(t, recorder.Flushed)
+
+	require.NoError(t, stream.Send("status", map[string]string{"status": "building"}))
+	require.NoError(t, stream.KeepAlive())
+	require.NoError(t, stream.Send("log", []string{"line"}))
+	require.Equal(t, "event: status\ndata: {\"status\":\"building\"}\n\n: keep-alive\n\nevent: log\ndata: [\"line\"]\n\n", recorder.Body.String())
+
+	_, err = NewEventStream(noFlushWriter{httptest.NewRecorder()})
+	require.Error(t, err)
+}
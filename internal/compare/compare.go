@@ -0,0 +1,407 @@
+// Package compare computes the differences between two composes: their
+// packages, blueprints, manifest stages, and partition tables.
+package compare
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/osbuild/osbuild-composer/internal/blueprint"
+	"github.com/osbuild/osbuild-composer/internal/distro"
+	"github.com/osbuild/osbuild-composer/internal/rpmmd"
+)
+
+// Compose contains the parts of a compose which are compared.
+type Compose struct {
+	// Blueprint the compose was built from, may be nil if unknown
+	Blueprint *blueprint.Blueprint
+
+	// Packages are the depsolved packages of the image's payload
+	Packages []rpmmd.PackageSpec
+
+	Manifest distro.Manifest
+}
+
+// Diff describes the differences between two composes, called "from" and
+// "to".
+type Diff struct {
+	Packages       PackageDiff   `json:"packages"`
+	Blueprint      []FieldChange `json:"blueprint"`
+	Stages         []StageChange `json:"stages"`
+	PartitionTable []FieldChange `json:"partition_table"`
+}
+
+// Package identifies a package by name, architecture, and version, which is
+// formatted as [epoch:]version-release.
+type Package struct {
+	Name    string `json:"name"`
+	Arch    string `json:"arch"`
+	Version string `json:"version"`
+}
+
+// PackageChange is a package whose version differs between the composes.
+type PackageChange struct {
+	Name        string `json:"name"`
+	Arch        string `json:"arch"`
+	FromVersion string `json:"from_version"`
+	ToVersion   string `json:"to_version"`
+}
+
+type PackageDiff struct {
+	Added   []Package       `json:"added"`
+	Removed []Package       `json:"removed"`
+	Changed []PackageChange `json:"changed"`
+}
+
+// FieldChange is a value which differs between the composes. Path is the
+// location of the value, in the form "customizations.kernel.append" or
+// "partitions[1].size", or empty when the values differ as a whole, for example
+// when only one of the composes has a partition table. From is nil for added
+// values, and To for removed ones.
+type FieldChange struct {
+	Path string      `json:"path"`
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// StageChange is a stage of a manifest pipeline which was added, removed, or
+// whose options changed. Stages are matched by type and order of appearance
+// within a pipeline, so that Index is the n-th stage of Type in Pipeline.
+type StageChange struct {
+	Pipeline    string          `json:"pipeline"`
+	Type        string          `json:"type"`
+	Index       int             `json:"index"`
+	Change      string          `json:"change"` // added, removed, or changed
+	FromOptions json.RawMessage `json:"from_options,omitempty"`
+	ToOptions   json.RawMessage `json:"to_options,omitempty"`
+}
+
+// Composes returns the differences between compose `from` and compose `to`.
+func Composes(from, to Compose) (*Diff, error) {
+	blueprintChanges, err := diffBlueprints(from.Blueprint, to.Blueprint)
+	if err != nil {
+		return nil, err
+	}
+
+	fromManifest, err := parseManifest(from.Manifest)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing manifest: %v", err)
+	}
+	toManifest, err := parseManifest(to.Manifest)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing manifest: %v", err)
+	}
+
+	partitionTableChanges, err := diffPartitionTables(fromManifest, toManifest)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing partition table: %v", err)
+	}
+
+	return &Diff{
+		Packages:       diffPackages(from.Packages, to.Packages),
+		Blueprint:      blueprintChanges,
+		Stages:         diffStages(fromManifest, toManifest),
+		PartitionTable: partitionTableChanges,
+	}, nil
+}
+
+func diffPackages(from, to []rpmmd.PackageSpec) PackageDiff {
+	type nameArch struct {
+		name, arch string
+	}
+	index := func(specs []rpmmd.PackageSpec) map[nameArch]rpmmd.PackageSpec {
+		m := make(map[nameArch]rpmmd.PackageSpec, len(specs))
+		for _, s := range specs {
+			m[nameArch{s.Name, s.Arch}] = s
+		}
+		return m
+	}
+	evr := func(s rpmmd.PackageSpec) string {
+		if s.Epoch == 0 {
+			return fmt.Sprintf("%s-%s", s.Version, s.Release)
+		}
+		return fmt.Sprintf("%d:%s-%s", s.Epoch, s.Version, s.Release)
+	}
+
+	fromPackages := index(from)
+	toPackages := index(to)
+
+	diff := PackageDiff{
+		Added:   []Package{},
+		Removed: []Package{},
+		Changed: []PackageChange{},
+	}
+	for key, f := range fromPackages {
+		t, ok := toPackages[key]
+		if !ok {
+			diff.Removed = append(diff.Removed, Package{f.Name, f.Arch, evr(f)})
+		} else if evr(f) != evr(t) {
+			diff.Changed = append(diff.Changed, PackageChange{f.Name, f.Arch, evr(f), evr(t)})
+		}
+	}
+	for key, t := range toPackages {
+		if _, ok := fromPackages[key]; !ok {
+			diff.Added = append(diff.Added, Package{t.Name, t.Arch, evr(t)})
+		}
+	}
+
+	sortPackages := func(packages []Package) {
+		sort.Slice(packages, func(i, j int) bool {
+			if packages[i].Name != packages[j].Name {
+				return packages[i].Name < packages[j].Name
+			}
+			return packages[i].Arch < packages[j].Arch
+		})
+	}
+	sortPackages(diff.Added)
+	sortPackages(diff.Removed)
+	sort.Slice(diff.Changed, func(i, j int) bool {
+		if diff.Changed[i].Name != diff.Changed[j].Name {
+			return diff.Changed[i].Name < diff.Changed[j].Name
+		}
+		return diff.Changed[i].Arch < diff.Changed[j].Arch
+	})
+
+	return diff
+}
+
+func diffBlueprints(from, to *blueprint.Blueprint) ([]FieldChange, error) {
+	fromValue, err := toGeneric(from)
+	if err != nil {
+		return nil, err
+	}
+	toValue, err := toGeneric(to)
+	if err != nil {
+		return nil, err
+	}
+
+	return diffValues("", fromValue, toValue), nil
+}
+
+// toGeneric converts `v` to the generic representation of its JSON encoding,
+// consisting of maps, slices, and scalar values.
+func toGeneric(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var generic interface{}
+	err = json.Unmarshal(data, &generic)
+	if err != nil {
+		return nil, err
+	}
+	return generic, nil
+}
+
+// diffValues recursively compares two generic JSON values. Objects are
+// compared key by key and arrays element by element, all other values as a
+// whole.
+func diffValues(path string, from, to interface{}) []FieldChange {
+	changes := []FieldChange{}
+
+	fromMap, fromIsMap := from.(map[string]interface{})
+	toMap, toIsMap := to.(map[string]interface{})
+	if fromIsMap && toIsMap {
+		keys := make(map[string]bool)
+		for k := range fromMap {
+			keys[k] = true
+		}
+		for k := range toMap {
+			keys[k] = true
+		}
+		sortedKeys := make([]string, 0, len(keys))
+		for k := range keys {
+			sortedKeys = append(sortedKeys, k)
+		}
+		sort.Strings(sortedKeys)
+
+		for _, k := range sortedKeys {
+			p := k
+			if path != "" {
+				p = path + "." + k
+			}
+			changes = append(changes, diffValues(p, fromMap[k], toMap[k])...)
+		}
+		return changes
+	}
+
+	fromSlice, fromIsSlice := from.([]interface{})
+	toSlice, toIsSlice := to.([]interface{})
+	if fromIsSlice && toIsSlice {
+		for i := 0; i < len(fromSlice) || i < len(toSlice); i++ {
+			var f, t interface{}
+			if i < len(fromSlice) {
+				f = fromSlice[i]
+			}
+			if i < len(toSlice) {
+				t = toSlice[i]
+			}
+			changes = append(changes, diffValues(fmt.Sprintf("%s[%d]", path, i), f, t)...)
+		}
+		return changes
+	}
+
+	if !reflect.DeepEqual(from, to) {
+		changes = append(changes, FieldChange{Path: path, From: from, To: to})
+	}
+	return changes
+}
+
+// manifest is the part of an osbuild manifest that is compared. Only the
+// pipelines of version 2 manifests are supported; version 1 manifests are
+// treated as a single pipeline named "tree".
+type manifest struct {
+	Pipelines []pipeline `json:"pipelines"`
+}
+
+type pipeline struct {
+	Name   string  `json:"name"`
+	Stages []stage `json:"stages"`
+}
+
+type stage struct {
+	Type    string          `json:"type"`
+	Name    string          `json:"name"` // the type of version 1 stages
+	Options json.RawMessage `json:"options"`
+}
+
+func parseManifest(m distro.Manifest) (*manifest, error) {
+	if len(m) == 0 {
+		return &manifest{}, nil
+	}
+
+	var result struct {
+		manifest
+		Pipeline *struct {
+			Stages    []stage `json:"stages"`
+			Assembler *stage  `json:"assembler"`
+		} `json:"pipeline"`
+	}
+	err := json.Unmarshal(m, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Pipeline != nil {
+		tree := pipeline{Name: "tree", Stages: result.Pipeline.Stages}
+		if result.Pipeline.Assembler != nil {
+			tree.Stages = append(tree.Stages, *result.Pipeline.Assembler)
+		}
+		result.Pipelines = append(result.Pipelines, tree)
+	}
+
+	return &result.manifest, nil
+}
+
+func diffStages(from, to *manifest) []StageChange {
+	changes := []StageChange{}
+
+	type stageKey struct {
+		pipeline, stageType string
+		index               int
+	}
+	index := func(m *manifest) (map[stageKey]json.RawMessage, []stageKey) {
+		stages := make(map[stageKey]json.RawMessage)
+		var order []stageKey
+		for _, p := range m.Pipelines {
+			count := make(map[string]int)
+			for _, s := range p.Stages {
+				stageType := s.Type
+				if stageType == "" {
+					stageType = s.Name
+				}
+				key := stageKey{p.Name, stageType, count[stageType]}
+				count[stageType]++
+				stages[key] = s.Options
+				order = append(order, key)
+			}
+		}
+		return stages, order
+	}
+
+	fromStages, fromOrder := index(from)
+	toStages, toOrder := index(to)
+
+	for _, key := range fromOrder {
+		fromOptions := fromStages[key]
+		toOptions, ok := toStages[key]
+		if !ok {
+			changes = append(changes, StageChange{key.pipeline, key.stageType, key.index, "removed", fromOptions, nil})
+		} else if !jsonEqual(fromOptions, toOptions) {
+			changes = append(changes, StageChange{key.pipeline, key.stageType, key.index, "changed", fromOptions, toOptions})
+		}
+	}
+	for _, key := range toOrder {
+		if _, ok := fromStages[key]; !ok {
+			changes = append(changes, StageChange{key.pipeline, key.stageType, key.index, "added", nil, toStages[key]})
+		}
+	}
+
+	return changes
+}
+
+func jsonEqual(a, b json.RawMessage) bool {
+	var va, vb interface{}
+	if len(a) > 0 {
+		if err := json.Unmarshal(a, &va); err != nil {
+			return false
+		}
+	}
+	if len(b) > 0 {
+		if err := json.Unmarshal(b, &vb); err != nil {
+			return false
+		}
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+// partitionTable returns the partition table created by the first sfdisk
+// stage of the manifest, without the randomly generated UUIDs, or nil if the
+// manifest doesn't partition a disk.
+func partitionTable(m *manifest) (interface{}, error) {
+	for _, p := range m.Pipelines {
+		for _, s := range p.Stages {
+			if s.Type != "org.osbuild.sfdisk" {
+				continue
+			}
+
+			var options interface{}
+			err := json.Unmarshal(s.Options, &options)
+			if err != nil {
+				return nil, err
+			}
+			stripUUIDs(options)
+			return options, nil
+		}
+	}
+	return nil, nil
+}
+
+func stripUUIDs(v interface{}) {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		delete(value, "uuid")
+		for _, child := range value {
+			stripUUIDs(child)
+		}
+	case []interface{}:
+		for _, child := range value {
+			stripUUIDs(child)
+		}
+	}
+}
+
+func diffPartitionTables(from, to *manifest) ([]FieldChange, error) {
+	fromTable, err := partitionTable(from)
+	if err != nil {
+		return nil, err
+	}
+	toTable, err := partitionTable(to)
+	if err != nil {
+		return nil, err
+	}
+	return diffValues("", fromTable, toTable), nil
+}
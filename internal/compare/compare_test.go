@@ -0,0 +1,151 @@
+package compare
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/osbuild-composer/internal/blueprint"
+	"github.com/osbuild/osbuild-composer/internal/common"
+	"github.com/osbuild/osbuild-composer/internal/distro"
+	"github.com/osbuild/osbuild-composer/internal/rpmmd"
+)
+
+const fromManifest = `{
+  "version": "2",
+  "pipelines": [
+    {
+      "name": "os",
+      "stages": [
+        {"type": "org.osbuild.rpm", "options": {"gpgkeys": ["a"]}},
+        {"type": "org.osbuild.hostname", "options": {"hostname": "old"}},
+        {"type": "org.osbuild.locale", "options": {"language": "en_US"}}
+      ]
+    },
+    {
+      "name": "image",
+      "stages": [
+        {"type": "org.osbuild.sfdisk", "options": {"label": "gpt", "uuid": "D209C89E", "partitions": [
+          {"size": 2048, "start": 2048, "type": "21686148", "uuid": "FAC7F1FB"},
+          {"size": 4096, "start": 4096, "type": "0FC63DAF", "uuid": "6264D520"}
+        ]}}
+      ]
+    }
+  ]
+}`
+
+const toManifest = `{
+  "version": "2",
+  "pipelines": [
+    {
+      "name": "os",
+      "stages": [
+        {"type": "org.osbuild.rpm", "options": {"gpgkeys": ["a"]}},
+        {"type": "org.osbuild.hostname", "options": {"hostname": "new"}},
+        {"type": "org.osbuild.firewall", "options": {"ports": ["22:tcp"]}}
+      ]
+    },
+    {
+      "name": "image",
+      "stages": [
+        {"type": "org.osbuild.sfdisk", "options": {"label": "gpt", "uuid": "8DFDFF87", "partitions": [
+          {"size": 2048, "start": 2048, "type": "21686148", "uuid": "CB07C243"},
+          {"size": 8192, "start": 4096, "type": "0FC63DAF", "uuid": "B3E5D8FF"}
+        ]}}
+      ]
+    }
+  ]
+}`
+
+func TestComposes(t *testing.T) {
+	from := Compose{
+		Blueprint: &blueprint.Blueprint{
+			Name:     "test",
+			Version:  "0.0.1",
+			Packages: []blueprint.Package{{Name: "bash"}},
+			Customizations: &blueprint.Customizations{
+				Hostname: common.StringToPtr("old"),
+			},
+		},
+		Packages: []rpmmd.PackageSpec{
+			{Name: "bash", Version: "5.1.8", Release: "1.fc35", Arch: "x86_64"},
+			{Name: "dbus", Epoch: 1, Version: "1.12.20", Release: "5.fc35", Arch: "x86_64"},
+			{Name: "glibc", Version: "2.34", Release: "7.fc35", Arch: "x86_64"},
+		},
+		Manifest: distro.Manifest(fromManifest),
+	}
+	to := Compose{
+		Blueprint: &blueprint.Blueprint{
+			Name:     "test",
+			Version:  "0.0.2",
+			Packages: []blueprint.Package{{Name: "bash"}, {Name: "firewalld"}},
+			Customizations: &blueprint.Customizations{
+				Hostname: common.StringToPtr("new"),
+			},
+		},
+		Packages: []rpmmd.PackageSpec{
+			{Name: "bash", Version: "5.1.8", Release: "2.fc35", Arch: "x86_64"},
+			{Name: "firewalld", Version: "1.0.1", Release: "1.fc35", Arch: "noarch"},
+			{Name: "glibc", Version: "2.34", Release: "7.fc35", Arch: "x86_64"},
+		},
+		Manifest: distro.Manifest(toManifest),
+	}
+
+	diff, err := Composes(from, to)
+	require.NoError(t, err)
+
+	require.Equal(t, PackageDiff{
+		Added:   []Package{{"firewalld", "noarch", "1.0.1-1.fc35"}},
+		Removed: []Package{{"dbus", "x86_64", "1:1.12.20-5.fc35"}},
+		Changed: []PackageChange{{"bash", "x86_64", "5.1.8-1.fc35", "5.1.8-2.fc35"}},
+	}, diff.Packages)
+
+	require.Equal(t, []FieldChange{
+		{Path: "customizations.hostname", From: "old", To: "new"},
+		{Path: "packages[1]", From: nil, To: map[string]interface{}{"name": "firewalld"}},
+		{Path: "version", From: "0.0.1", To: "0.0.2"},
+	}, diff.Blueprint)
+
+	require.Equal(t, []StageChange{
+		{"os", "org.osbuild.hostname", 0, "changed", json.RawMessage(`{"hostname": "old"}`), json.RawMessage(`{"hostname": "new"}`)},
+		{"os", "org.osbuild.locale", 0, "removed", json.RawMessage(`{"language": "en_US"}`), nil},
+		{"image", "org.osbuild.sfdisk", 0, "changed", diff.Stages[2].FromOptions, diff.Stages[2].ToOptions},
+		{"os", "org.osbuild.firewall", 0, "added", nil, json.RawMessage(`{"ports": ["22:tcp"]}`)},
+	}, diff.Stages)
+
+	// partition UUIDs are random and not reported
+	require.Equal(t, []FieldChange{
+		{Path: "partitions[1].size", From: float64(4096), To: float64(8192)},
+	}, diff.PartitionTable)
+}
+
+func TestComposesIdentical(t *testing.T) {
+	c := Compose{
+		Blueprint: &blueprint.Blueprint{Name: "test"},
+		Packages:  []rpmmd.PackageSpec{{Name: "bash", Version: "5.1.8", Release: "1.fc35", Arch: "x86_64"}},
+		Manifest:  distro.Manifest(fromManifest),
+	}
+
+	diff, err := Composes(c, c)
+	require.NoError(t, err)
+	require.Empty(t, diff.Packages.Added)
+	require.Empty(t, diff.Packages.Removed)
+	require.Empty(t, diff.Packages.Changed)
+	require.Empty(t, diff.Blueprint)
+	require.Empty(t, diff.Stages)
+	require.Empty(t, diff.PartitionTable)
+}
+
+func TestComposesManifestV1(t *testing.T) {
+	from := Compose{Manifest: distro.Manifest(`{"pipeline": {"stages": [{"name": "org.osbuild.rpm", "options": {}}], "assembler": {"name": "org.osbuild.qemu", "options": {"format": "qcow2"}}}}`)}
+	to := Compose{}
+
+	diff, err := Composes(from, to)
+	require.NoError(t, err)
+	require.Len(t, diff.Stages, 2)
+	require.Equal(t, "removed", diff.Stages[0].Change)
+
+	_, err = Composes(Compose{Manifest: distro.Manifest(`{`)}, to)
+	require.Error(t, err)
+}
@@ -0,0 +1,205 @@
+// Package cron implements parsing and evaluation of cron-like schedule
+// expressions.
+//
+// An expression consists of five whitespace-separated fields: minute, hour,
+// day of month, month and day of week. Each field is either `*`, a number, a
+// range (`1-5`), a list (`1,3,5`), or any of those with a step (`*/15`,
+// `0-30/10`). Days of week are numbered 0 (Sunday) to 6 (Saturday); 7 is
+// accepted as an alias for Sunday. The shorthands `@hourly`, `@daily`,
+// `@weekly`, `@monthly` and `@yearly` are supported as well.
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Expression is a parsed cron expression.
+type Expression struct {
+	minute, hour, dom, month, dow uint64 // bit sets of allowed values
+
+	// true if day of month or day of week was not restricted (`*`). This
+	// mirrors the classic cron semantics: when both are restricted, a day
+	// matches if it matches *either* of them.
+	domStar, dowStar bool
+}
+
+type field struct {
+	name     string
+	min, max int
+}
+
+var (
+	minuteField = field{"minute", 0, 59}
+	hourField   = field{"hour", 0, 23}
+	domField    = field{"day of month", 1, 31}
+	monthField  = field{"month", 1, 12}
+	dowField    = field{"day of week", 0, 7}
+)
+
+var shorthands = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Parse parses a cron expression.
+func Parse(spec string) (*Expression, error) {
+	spec = strings.TrimSpace(spec)
+	if s, ok := shorthands[spec]; ok {
+		spec = s
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("expected 5 fields in cron expression, got %d: %q", len(fields), spec)
+	}
+
+	var e Expression
+	var err error
+
+	if e.minute, err = parseField(fields[0], minuteField); err != nil {
+		return nil, err
+	}
+	if e.hour, err = parseField(fields[1], hourField); err != nil {
+		return nil, err
+	}
+	if e.dom, err = parseField(fields[2], domField); err != nil {
+		return nil, err
+	}
+	if e.month, err = parseField(fields[3], monthField); err != nil {
+		return nil, err
+	}
+	if e.dow, err = parseField(fields[4], dowField); err != nil {
+		return nil, err
+	}
+
+	// sunday can be written as either 0 or 7
+	if e.dow&(1<<7) != 0 {
+		e.dow |= 1
+	}
+
+	e.domStar = strings.HasPrefix(fields[2], "*")
+	e.dowStar = strings.HasPrefix(fields[4], "*")
+
+	return &e, nil
+}
+
+func parseField(s string, f field) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(s, ",") {
+		b, err := parseRange(part, f)
+		if err != nil {
+			return 0, err
+		}
+		bits |= b
+	}
+	return bits, nil
+}
+
+func parseRange(s string, f field) (uint64, error) {
+	rangeAndStep := strings.SplitN(s, "/", 2)
+
+	var start, end int
+	var err error
+	if rangeAndStep[0] == "*" {
+		start, end = f.min, f.max
+	} else {
+		bounds := strings.SplitN(rangeAndStep[0], "-", 2)
+		start, err = parseNumber(bounds[0], f)
+		if err != nil {
+			return 0, err
+		}
+		end = start
+		if len(bounds) == 2 {
+			end, err = parseNumber(bounds[1], f)
+			if err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	step := 1
+	if len(rangeAndStep) == 2 {
+		step, err = strconv.Atoi(rangeAndStep[1])
+		if err != nil || step <= 0 {
+			return 0, fmt.Errorf("invalid step in %s field: %q", f.name, s)
+		}
+		// `5/10` means "from 5 to the end, every 10"
+		if rangeAndStep[0] != "*" && !strings.Contains(rangeAndStep[0], "-") {
+			end = f.max
+		}
+	}
+
+	if start > end {
+		return 0, fmt.Errorf("invalid range in %s field: %q", f.name, s)
+	}
+
+	var bits uint64
+	for i := start; i <= end; i += step {
+		bits |= 1 << uint(i)
+	}
+	return bits, nil
+}
+
+func parseNumber(s string, f field) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value in %s field: %q", f.name, s)
+	}
+	if n < f.min || n > f.max {
+		return 0, fmt.Errorf("value out of range in %s field: %d (expected %d-%d)", f.name, n, f.min, f.max)
+	}
+	return n, nil
+}
+
+// Next returns the first time matching the expression that is strictly after
+// `t`. Times are evaluated in the location of `t`. Returns the zero time if no
+// matching time exists within the next five years (e.g. for `0 0 30 2 *`).
+func (e *Expression) Next(t time.Time) time.Time {
+	// cron has a resolution of one minute
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if !has(e.month, int(t.Month())) {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !e.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !has(e.hour, t.Hour()) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if !has(e.minute, t.Minute()) {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+
+	return time.Time{}
+}
+
+func (e *Expression) matchesDay(t time.Time) bool {
+	domMatch := has(e.dom, t.Day())
+	dowMatch := has(e.dow, int(t.Weekday()))
+
+	if e.domStar || e.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+func has(bits uint64, n int) bool {
+	return bits&(1<<uint(n)) != 0
+}
@@ -0,0 +1,65 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseInvalid(t *testing.T) {
+	invalid := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+		"@fortnightly",
+	}
+
+	for _, spec := range invalid {
+		_, err := Parse(spec)
+		assert.Errorf(t, err, "expected %q to be invalid", spec)
+	}
+}
+
+func TestNext(t *testing.T) {
+	// a Wednesday
+	start := time.Date(2021, time.December, 15, 10, 42, 31, 0, time.UTC)
+
+	cases := []struct {
+		spec     string
+		expected time.Time
+	}{
+		{"* * * * *", time.Date(2021, time.December, 15, 10, 43, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2021, time.December, 15, 10, 45, 0, 0, time.UTC)},
+		{"0 * * * *", time.Date(2021, time.December, 15, 11, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2021, time.December, 15, 11, 0, 0, 0, time.UTC)},
+		{"30 2 * * *", time.Date(2021, time.December, 16, 2, 30, 0, 0, time.UTC)},
+		{"@daily", time.Date(2021, time.December, 16, 0, 0, 0, 0, time.UTC)},
+		{"0 3 * * 0", time.Date(2021, time.December, 19, 3, 0, 0, 0, time.UTC)},
+		{"0 3 * * 7", time.Date(2021, time.December, 19, 3, 0, 0, 0, time.UTC)},
+		{"0 3 * * 1-5", time.Date(2021, time.December, 16, 3, 0, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 12 1,20 * *", time.Date(2021, time.December, 20, 12, 0, 0, 0, time.UTC)},
+		{"10/20 10 * * *", time.Date(2021, time.December, 15, 10, 50, 0, 0, time.UTC)},
+		// both day of month and day of week restricted: either matches
+		{"0 0 1 * 5", time.Date(2021, time.December, 17, 0, 0, 0, 0, time.UTC)},
+		// never matches
+		{"0 0 30 2 *", time.Time{}},
+	}
+
+	for _, c := range cases {
+		e, err := Parse(c.spec)
+		require.NoError(t, err, c.spec)
+		assert.Equal(t, c.expected, e.Next(start), c.spec)
+	}
+}
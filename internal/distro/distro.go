@@ -131,6 +131,10 @@ type ImageOptions struct {
 	OSTree       ostree.RequestParams
 	Size         uint64
 	Subscription *SubscriptionImageOptions
+
+	// EnabledModules are the module streams which were enabled when
+	// depsolving, and are recorded as enabled in the image.
+	EnabledModules []blueprint.EnabledModule
 }
 
 // The SubscriptionImageOptions specify subscription-specific image options
@@ -1,11 +1,16 @@
 package distro_test
 
 import (
+	"encoding/json"
 	"testing"
 
+	"github.com/osbuild/osbuild-composer/internal/blueprint"
 	"github.com/osbuild/osbuild-composer/internal/distro"
 	"github.com/osbuild/osbuild-composer/internal/distro/distro_test_common"
 	"github.com/osbuild/osbuild-composer/internal/distroregistry"
+	"github.com/osbuild/osbuild-composer/internal/osbuild2"
+	"github.com/osbuild/osbuild-composer/internal/ostree"
+	"github.com/osbuild/osbuild-composer/internal/rpmmd"
 	"github.com/stretchr/testify/require"
 )
 
@@ -22,6 +27,75 @@ func TestDistro_Manifest(t *testing.T) {
 	)
 }
 
+// TestDistro_OSTreeSources checks that all distros pull parent commits with the
+// GPG key and secrets of the request.
+func TestDistro_OSTreeSources(t *testing.T) {
+	options := distro.ImageOptions{
+		Size: 2147483648,
+		OSTree: ostree.RequestParams{
+			URL:    "https://ostree.example.com/repo",
+			Ref:    "rhel/edge",
+			Parent: "5330bb1b8820944567f519de66ad6354c729b6b490dea1c5a7ba320c9f147c58",
+			RHSM:   true,
+			GPGKey: "-----BEGIN PGP PUBLIC KEY BLOCK-----",
+		},
+	}
+
+	registry := distroregistry.NewDefault()
+	for _, distroName := range []string{"rhel-84", "rhel-85", "rhel-86", "rhel-90", "rhel-90-beta"} {
+		d := registry.GetDistro(distroName)
+		require.NotNil(t, d, distroName)
+		arch, err := d.GetArch("x86_64")
+		require.NoError(t, err)
+
+		found := false
+		for _, imageTypeName := range arch.ListImageTypes() {
+			imageType, err := arch.GetImageType(imageTypeName)
+			require.NoError(t, err)
+
+			// pretend that exactly the included packages were depsolved
+			packageSpecSets := make(map[string][]rpmmd.PackageSpec)
+			for name, set := range imageType.PackageSets(blueprint.Blueprint{}) {
+				specs := []rpmmd.PackageSpec{}
+				for _, pkg := range set.Include {
+					specs = append(specs, rpmmd.PackageSpec{
+						Name:     pkg,
+						Version:  "1",
+						Release:  "1",
+						Arch:     "x86_64",
+						Checksum: "sha256:" + pkg,
+					})
+				}
+				packageSpecSets[name] = specs
+			}
+			manifest, err := imageType.Manifest(nil, options, nil, packageSpecSets, 0)
+			if err != nil {
+				// not every image type can be built from a parent commit
+				continue
+			}
+
+			var parsed struct {
+				Sources struct {
+					OSTree *osbuild2.OSTreeSource `json:"org.osbuild.ostree"`
+				} `json:"sources"`
+			}
+			require.NoError(t, json.Unmarshal(manifest, &parsed))
+			if parsed.Sources.OSTree == nil {
+				continue
+			}
+
+			found = true
+			item, exists := parsed.Sources.OSTree.Items[options.OSTree.Parent]
+			require.True(t, exists, "%s %s", distroName, imageTypeName)
+			require.Equal(t, options.OSTree.URL, item.Remote.URL)
+			require.Equal(t, []string{options.OSTree.GPGKey}, item.Remote.GPGKeys, "%s %s", distroName, imageTypeName)
+			require.NotNil(t, item.Remote.Secrets, "%s %s", distroName, imageTypeName)
+			require.Equal(t, ostree.SourceSecretsName, item.Remote.Secrets.Name)
+		}
+		require.True(t, found, "%s has no image type which pulls the parent commit", distroName)
+	}
+}
+
 var (
 	v1manifests = []string{
 		`{}`,
@@ -299,9 +299,9 @@ func sources(packages []rpmmd.PackageSpec) *osbuild.Sources {
 		fileSource := osbuild.FileSource{
 			URL: pkg.RemoteLocation,
 		}
-		if pkg.Secrets == "org.osbuild.rhsm" {
+		if pkg.Secrets != "" {
 			fileSource.Secrets = &osbuild.Secret{
-				Name: "org.osbuild.rhsm",
+				Name: pkg.Secrets,
 			}
 		}
 		files.URLs[pkg.Checksum] = fileSource
@@ -316,6 +316,10 @@ func (t *imageType) pipeline(c *blueprint.Customizations, options distro.ImageOp
 	// if options.Size is 0, this will be the default size of the image type
 	imageSize := t.Size(options.Size)
 
+	if len(options.EnabledModules) > 0 {
+		return nil, fmt.Errorf("enabling module streams is not supported for %s", t.arch.distro.name)
+	}
+
 	if kernelOpts := c.GetKernel(); kernelOpts != nil && kernelOpts.Append != "" && t.rpmOstree {
 		return nil, fmt.Errorf("kernel boot parameter customizations are not supported for ostree types")
 	}
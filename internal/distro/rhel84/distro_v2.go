@@ -161,7 +161,7 @@ func (t *imageTypeS2) Manifest(c *blueprint.Customizations,
 
 	var commits []ostreeCommit
 	if t.bootISO && options.OSTree.Parent != "" && options.OSTree.URL != "" {
-		commit := ostreeCommit{Checksum: options.OSTree.Parent, URL: options.OSTree.URL}
+		commit := ostreeCommit{Checksum: options.OSTree.Parent, URL: options.OSTree.URL, GPGKey: options.OSTree.GPGKey, Secrets: options.OSTree.SourceSecrets()}
 		commits = []ostreeCommit{commit}
 	}
 	return json.Marshal(
@@ -177,6 +177,8 @@ func (t *imageTypeS2) Manifest(c *blueprint.Customizations,
 type ostreeCommit struct {
 	Checksum string
 	URL      string
+	GPGKey   string
+	Secrets  string
 }
 
 func (t *imageTypeS2) sources(packages []rpmmd.PackageSpec, ostreeCommits []ostreeCommit) osbuild.Sources {
@@ -185,14 +187,7 @@ func (t *imageTypeS2) sources(packages []rpmmd.PackageSpec, ostreeCommits []ostr
 		Items: make(map[string]osbuild.CurlSourceItem),
 	}
 	for _, pkg := range packages {
-		item := new(osbuild.URLWithSecrets)
-		item.URL = pkg.RemoteLocation
-		if pkg.Secrets == "org.osbuild.rhsm" {
-			item.Secrets = &osbuild.URLSecrets{
-				Name: "org.osbuild.rhsm",
-			}
-		}
-		curl.Items[pkg.Checksum] = item
+		curl.Items[pkg.Checksum] = osbuild.NewURLWithSecrets(pkg.RemoteLocation, pkg.Secrets, pkg.IgnoreSSL)
 	}
 	if len(curl.Items) > 0 {
 		sources["org.osbuild.curl"] = curl
@@ -202,9 +197,7 @@ func (t *imageTypeS2) sources(packages []rpmmd.PackageSpec, ostreeCommits []ostr
 		Items: make(map[string]osbuild.OSTreeSourceItem),
 	}
 	for _, commit := range ostreeCommits {
-		item := new(osbuild.OSTreeSourceItem)
-		item.Remote.URL = commit.URL
-		ostree.Items[commit.Checksum] = *item
+		ostree.Items[commit.Checksum] = *osbuild.NewOSTreeSourceItem(commit.URL, commit.GPGKey, commit.Secrets)
 	}
 	if len(ostree.Items) > 0 {
 		sources["org.osbuild.ostree"] = ostree
@@ -223,6 +216,10 @@ func (t *imageTypeS2) pipelines(customizations *blueprint.Customizations, option
 		}
 	}
 
+	if len(options.EnabledModules) > 0 {
+		return nil, fmt.Errorf("enabling module streams is not supported for %s", t.arch.distro.name)
+	}
+
 	if kernelOpts := customizations.GetKernel(); kernelOpts.Append != "" && t.rpmOstree {
 		return nil, fmt.Errorf("kernel boot parameter customizations are not supported for ostree types")
 	}
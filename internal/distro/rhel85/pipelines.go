@@ -184,6 +184,9 @@ func ec2BaseTreePipeline(repos []rpmmd.RepoConfig, packages []rpmmd.PackageSpec,
 	p.Build = "name:build"
 	packages = append(packages, bpPackages...)
 	p.AddStage(osbuild.NewRPMStage(rpmStageOptions(repos), osbuild.NewRpmStageSourceFilesInputs(packages)))
+	for _, module := range options.EnabledModules {
+		p.AddStage(osbuild.NewDNFModuleConfigStage(osbuild.NewDNFModuleEnableStageOptions(module.Name, module.Stream)))
+	}
 
 	// If the /boot is on a separate partition, the prefix for the BLS stage must be ""
 	if pt.FindMountable("/boot") == nil {
@@ -636,6 +639,9 @@ func osPipeline(repos []rpmmd.RepoConfig, packages []rpmmd.PackageSpec, bpPackag
 	p.Build = "name:build"
 	packages = append(packages, bpPackages...)
 	p.AddStage(osbuild.NewRPMStage(rpmStageOptions(repos), osbuild.NewRpmStageSourceFilesInputs(packages)))
+	for _, module := range options.EnabledModules {
+		p.AddStage(osbuild.NewDNFModuleConfigStage(osbuild.NewDNFModuleEnableStageOptions(module.Name, module.Stream)))
+	}
 	p.AddStage(osbuild.NewFixBLSStage(&osbuild.FixBLSStageOptions{}))
 	language, keyboard := c.GetPrimaryLocale()
 	if language != nil {
@@ -722,6 +728,9 @@ func ostreeTreePipeline(repos []rpmmd.RepoConfig, packages []rpmmd.PackageSpec,
 	}
 
 	p.AddStage(osbuild.NewRPMStage(rpmStageOptions(repos), osbuild.NewRpmStageSourceFilesInputs(packages)))
+	for _, module := range options.EnabledModules {
+		p.AddStage(osbuild.NewDNFModuleConfigStage(osbuild.NewDNFModuleEnableStageOptions(module.Name, module.Stream)))
+	}
 	p.AddStage(osbuild.NewFixBLSStage(&osbuild.FixBLSStageOptions{}))
 	language, keyboard := c.GetPrimaryLocale()
 	if language != nil {
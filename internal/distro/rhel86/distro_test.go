@@ -807,3 +807,23 @@ func TestDistro_CustomUsrPartitionNotLargeEnough(t *testing.T) {
 		}
 	}
 }
+
+func TestDistro_EnabledModules(t *testing.T) {
+	r8distro := rhel86.New()
+	bp := blueprint.Blueprint{
+		EnabledModules: []blueprint.EnabledModule{{Name: "postgresql", Stream: "13"}},
+	}
+	arch, err := r8distro.GetArch("x86_64")
+	require.NoError(t, err)
+	imgType, err := arch.GetImageType("qcow2")
+	require.NoError(t, err)
+
+	packageSets := imgType.PackageSets(bp)
+	assert.Equal(t, []string{"postgresql:13"}, packageSets["blueprint"].EnabledModules)
+	assert.Empty(t, packageSets["build"].EnabledModules)
+
+	testPackageSpecSets := distro_test_common.GetTestingPackageSpecSets("kernel", arch.Name(), imgType.PayloadPackageSets())
+	manifest, err := imgType.Manifest(bp.Customizations, distro.ImageOptions{EnabledModules: bp.EnabledModules}, nil, testPackageSpecSets, 0)
+	require.NoError(t, err)
+	assert.Contains(t, string(manifest), `{"type":"org.osbuild.dnf.module-config","options":{"conf":{"name":"postgresql","stream":"13","profiles":[],"state":"enabled"}}}`)
+}
@@ -339,7 +339,7 @@ func (t *imageType) PackageSets(bp blueprint.Blueprint) map[string]rpmmd.Package
 
 	// depsolve bp packages separately
 	// bp packages aren't restricted by exclude lists
-	mergedSets[blueprintPkgsKey] = rpmmd.PackageSet{Include: bpPackages}
+	mergedSets[blueprintPkgsKey] = rpmmd.PackageSet{Include: bpPackages, EnabledModules: bp.GetEnabledModules()}
 	kernel := bp.Customizations.GetKernel().Name
 
 	// add bp kernel to main OS package set to avoid duplicate kernels
@@ -429,6 +429,8 @@ func (t *imageType) PartitionType() string {
 type ostreeCommit struct {
 	Checksum string
 	URL      string
+	GPGKey   string
+	Secrets  string
 }
 
 func (t *imageType) Manifest(customizations *blueprint.Customizations,
@@ -460,7 +462,7 @@ func (t *imageType) Manifest(customizations *blueprint.Customizations,
 	// handle OSTree commit inputs
 	var commits []ostreeCommit
 	if options.OSTree.Parent != "" && options.OSTree.URL != "" {
-		commits = []ostreeCommit{{Checksum: options.OSTree.Parent, URL: options.OSTree.URL}}
+		commits = []ostreeCommit{{Checksum: options.OSTree.Parent, URL: options.OSTree.URL, GPGKey: options.OSTree.GPGKey, Secrets: options.OSTree.SourceSecrets()}}
 	}
 
 	// handle inline sources
@@ -486,14 +488,7 @@ func (t *imageType) sources(packages []rpmmd.PackageSpec, ostreeCommits []ostree
 		Items: make(map[string]osbuild.CurlSourceItem),
 	}
 	for _, pkg := range packages {
-		item := new(osbuild.URLWithSecrets)
-		item.URL = pkg.RemoteLocation
-		if pkg.Secrets == "org.osbuild.rhsm" {
-			item.Secrets = &osbuild.URLSecrets{
-				Name: "org.osbuild.rhsm",
-			}
-		}
-		curl.Items[pkg.Checksum] = item
+		curl.Items[pkg.Checksum] = osbuild.NewURLWithSecrets(pkg.RemoteLocation, pkg.Secrets, pkg.IgnoreSSL)
 	}
 	if len(curl.Items) > 0 {
 		sources["org.osbuild.curl"] = curl
@@ -503,9 +498,7 @@ func (t *imageType) sources(packages []rpmmd.PackageSpec, ostreeCommits []ostree
 		Items: make(map[string]osbuild.OSTreeSourceItem),
 	}
 	for _, commit := range ostreeCommits {
-		item := new(osbuild.OSTreeSourceItem)
-		item.Remote.URL = commit.URL
-		ostree.Items[commit.Checksum] = *item
+		ostree.Items[commit.Checksum] = *osbuild.NewOSTreeSourceItem(commit.URL, commit.GPGKey, commit.Secrets)
 	}
 	if len(ostree.Items) > 0 {
 		sources["org.osbuild.ostree"] = ostree
@@ -366,6 +366,9 @@ func osPipeline(t *imageType,
 	rpmOptions := rpmStageOptions(repos)
 	rpmOptions.GPGKeysFromTree = imageConfig.GPGKeyFiles
 	p.AddStage(osbuild.NewRPMStage(rpmOptions, osbuild.NewRpmStageSourceFilesInputs(packages)))
+	for _, module := range options.EnabledModules {
+		p.AddStage(osbuild.NewDNFModuleConfigStage(osbuild.NewDNFModuleEnableStageOptions(module.Name, module.Stream)))
+	}
 
 	// If the /boot is on a separate partition, the prefix for the BLS stage must be ""
 	if pt == nil || pt.FindMountable("/boot") == nil {
@@ -28,6 +28,14 @@ const (
 	sqlListen   = `LISTEN jobs`
 	sqlUnlisten = `UNLISTEN jobs`
 
+	sqlBroadcastEvent = `SELECT pg_notify('job_events', $1)`
+	sqlListenEvents   = `LISTEN job_events`
+	sqlUnlistenEvents = `UNLISTEN job_events`
+
+	// advisory locks are identified by numbers, hash the channel's name
+	sqlLockChannel   = `SELECT pg_advisory_lock(hashtext('channel:' || $1))`
+	sqlUnlockChannel = `SELECT pg_advisory_unlock(hashtext('channel:' || $1))`
+
 	sqlEnqueue = `INSERT INTO jobs(id, type, args, queued_at, channel) VALUES ($1, $2, $3, NOW(), $4)`
 	sqlDequeue = `
 		UPDATE jobs
@@ -83,6 +91,37 @@ const (
 		SET canceled = TRUE
 		WHERE id = $1 AND finished_at IS NULL
 		RETURNING type, started_at`
+	sqlRequeueJob = `
+		UPDATE jobs
+		SET token = NULL, started_at = NULL, finished_at = NULL, result = NULL, args = COALESCE($2, args)
+		WHERE id = $1 AND finished_at IS NOT NULL
+		RETURNING type`
+
+	sqlQueryJobsByChannel = `
+		SELECT id
+		FROM jobs
+		WHERE channel = $1
+		  AND (type = ANY($2) OR split_part(type, ':', 1) = ANY($2))
+		  AND ($3::timestamp IS NULL OR queued_at >= $3)
+		  AND ($4::timestamp IS NULL OR queued_at < $4)
+		ORDER BY queued_at DESC, id`
+	sqlQueryUnfinishedJobsByChannel = `
+		SELECT id
+		FROM jobs
+		WHERE channel = $1
+		  AND finished_at IS NULL AND canceled = FALSE
+		  AND (type = ANY($2) OR split_part(type, ':', 1) = ANY($2))
+		ORDER BY queued_at DESC, id`
+
+	sqlSetJobKey = `
+		UPDATE jobs
+		SET lookup_key = $1
+		WHERE id = $2`
+	sqlQueryJobsByKey = `
+		SELECT id
+		FROM jobs
+		WHERE lookup_key = $1
+		ORDER BY queued_at DESC, id`
 
 	sqlInsertHeartbeat = `
                 INSERT INTO heartbeats(token, id, heartbeat)
@@ -118,6 +157,9 @@ const (
 	sqlDeleteJobDependencies = `
                 DELETE FROM job_dependencies
                 WHERE dependency_id = ANY($1)`
+	sqlDeleteHeartbeats = `
+                DELETE FROM heartbeats
+                WHERE id = ANY($1)`
 	sqlDeleteJobs = `
                 DELETE FROM jobs
                 WHERE id = ANY($1)`
@@ -247,6 +289,78 @@ func (q *DBJobQueue) Dequeue(ctx context.Context, jobTypes []string, channels []
 
 	return id, token, dependencies, jobType, args, nil
 }
+
+// BroadcastEvent sends `payload` to the processes listening for events with
+// ListenForEvents() through a notification channel of the database.
+func (q *DBJobQueue) BroadcastEvent(payload []byte) error {
+	conn, err := q.pool.Acquire(context.Background())
+	if err != nil {
+		return fmt.Errorf("error connecting to database: %v", err)
+	}
+	defer conn.Release()
+
+	_, err = conn.Exec(context.Background(), sqlBroadcastEvent, string(payload))
+	if err != nil {
+		return fmt.Errorf("error notifying job events channel: %v", err)
+	}
+	return nil
+}
+
+func (q *DBJobQueue) ListenForEvents(ctx context.Context, handler func(payload []byte)) error {
+	conn, err := q.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("error connecting to database: %v", err)
+	}
+	defer func() {
+		// ctx might be done already
+		_, err := conn.Exec(context.Background(), sqlUnlistenEvents)
+		if err != nil {
+			logrus.Error("Error unlistening for job events: ", err)
+		}
+		conn.Release()
+	}()
+
+	_, err = conn.Exec(ctx, sqlListenEvents)
+	if err != nil {
+		return fmt.Errorf("error listening on job events channel: %v", err)
+	}
+
+	for {
+		notification, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("error waiting for notification on job events channel: %v", err)
+		}
+		handler([]byte(notification.Payload))
+	}
+}
+
+// LockChannel takes an advisory lock of the database, which is held by the
+// connection it was taken on. The connection is kept until the lock is
+// released.
+func (q *DBJobQueue) LockChannel(ctx context.Context, channel string) (func(), error) {
+	conn, err := q.pool.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to database: %v", err)
+	}
+
+	_, err = conn.Exec(ctx, sqlLockChannel, channel)
+	if err != nil {
+		conn.Release()
+		return nil, fmt.Errorf("error locking channel %s: %v", channel, err)
+	}
+
+	unlock := func() {
+		_, err := conn.Exec(context.Background(), sqlUnlockChannel, channel)
+		if err != nil {
+			// closing the connection releases the lock as well
+			logrus.Errorf("Error unlocking channel %s, closing the connection: %v", channel, err)
+			_ = conn.Conn().Close(context.Background())
+		}
+		conn.Release()
+	}
+	return unlock, nil
+}
+
 func (q *DBJobQueue) DequeueByID(ctx context.Context, id uuid.UUID) (uuid.UUID, []uuid.UUID, string, json.RawMessage, error) {
 	// Return early if the context is already canceled.
 	if err := ctx.Err(); err != nil {
@@ -377,6 +491,64 @@ func (q *DBJobQueue) CancelJob(id uuid.UUID) error {
 	return nil
 }
 
+func (q *DBJobQueue) RequeueJob(id uuid.UUID, args interface{}) error {
+	conn, err := q.pool.Acquire(context.Background())
+	if err != nil {
+		return fmt.Errorf("error connecting to database: %v", err)
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(context.Background())
+	if err != nil {
+		return fmt.Errorf("error starting database transaction: %v", err)
+	}
+	defer func() {
+		err = tx.Rollback(context.Background())
+		if err != nil && !errors.As(err, &pgx.ErrTxClosed) {
+			logrus.Errorf("error rolling back requeue job transaction for job %s: %v", id, err)
+		}
+	}()
+
+	var finished *time.Time
+	canceled := false
+	err = conn.QueryRow(context.Background(), sqlQueryJob, id).Scan(nil, nil, nil, nil, &finished, &canceled)
+	if err == pgx.ErrNoRows {
+		return jobqueue.ErrNotExist
+	}
+	if err != nil {
+		return fmt.Errorf("error requeuing job %s: %v", id, err)
+	}
+	if canceled {
+		return jobqueue.ErrCanceled
+	}
+	if finished == nil {
+		return jobqueue.ErrNotFinished
+	}
+
+	var jobType string
+	err = conn.QueryRow(context.Background(), sqlRequeueJob, id, args).Scan(&jobType)
+	if err == pgx.ErrNoRows {
+		return jobqueue.ErrNotFinished
+	}
+	if err != nil {
+		return fmt.Errorf("error requeuing job %s: %v", id, err)
+	}
+
+	_, err = conn.Exec(context.Background(), sqlNotify)
+	if err != nil {
+		return fmt.Errorf("error notifying jobs channel: %v", err)
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return fmt.Errorf("unable to commit database transaction: %v", err)
+	}
+
+	logrus.Infof("Requeued job of type %s with ID %s", jobType, id)
+
+	return nil
+}
+
 func (q *DBJobQueue) JobStatus(id uuid.UUID) (jobType string, result json.RawMessage, queued, started, finished time.Time, canceled bool, deps []uuid.UUID, err error) {
 	conn, err := q.pool.Acquire(context.Background())
 	if err != nil {
@@ -428,6 +600,103 @@ func (q *DBJobQueue) Job(id uuid.UUID) (jobType string, args json.RawMessage, de
 	return
 }
 
+// List the jobs of a channel by type, the type and time filtering happens in
+// sqlQueryJobsByChannel
+func (q *DBJobQueue) JobsByChannel(channel string, jobTypes []string, queuedAfter, queuedBefore time.Time) ([]uuid.UUID, error) {
+	conn, err := q.pool.Acquire(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to database: %v", err)
+	}
+	defer conn.Release()
+
+	// zero times are passed as NULL, which leaves the interval open
+	var after, before *time.Time
+	if !queuedAfter.IsZero() {
+		after = &queuedAfter
+	}
+	if !queuedBefore.IsZero() {
+		before = &queuedBefore
+	}
+
+	rows, err := conn.Query(context.Background(), sqlQueryJobsByChannel, channel, jobTypes, after, before)
+	if err != nil {
+		return nil, fmt.Errorf("error querying jobs: %v", err)
+	}
+	return scanJobIds(rows)
+}
+
+func (q *DBJobQueue) UnfinishedJobsByChannel(channel string, jobTypes []string) ([]uuid.UUID, error) {
+	conn, err := q.pool.Acquire(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to database: %v", err)
+	}
+	defer conn.Release()
+
+	rows, err := conn.Query(context.Background(), sqlQueryUnfinishedJobsByChannel, channel, jobTypes)
+	if err != nil {
+		return nil, fmt.Errorf("error querying jobs: %v", err)
+	}
+	return scanJobIds(rows)
+}
+
+func (q *DBJobQueue) SetJobKey(id uuid.UUID, key string) error {
+	conn, err := q.pool.Acquire(context.Background())
+	if err != nil {
+		return fmt.Errorf("error connecting to database: %v", err)
+	}
+	defer conn.Release()
+
+	// an empty key is stored as NULL, so that it isn't indexed
+	var k *string
+	if key != "" {
+		k = &key
+	}
+
+	tag, err := conn.Exec(context.Background(), sqlSetJobKey, k, id)
+	if err != nil {
+		return fmt.Errorf("error setting key of job %s: %v", id, err)
+	}
+	if tag.RowsAffected() != 1 {
+		return jobqueue.ErrNotExist
+	}
+	return nil
+}
+
+func (q *DBJobQueue) JobsByKey(key string) ([]uuid.UUID, error) {
+	conn, err := q.pool.Acquire(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to database: %v", err)
+	}
+	defer conn.Release()
+
+	rows, err := conn.Query(context.Background(), sqlQueryJobsByKey, key)
+	if err != nil {
+		return nil, fmt.Errorf("error querying jobs: %v", err)
+	}
+	return scanJobIds(rows)
+}
+
+// scanJobIds reads the ids of jobs from the first column of `rows` and
+// closes them.
+func scanJobIds(rows pgx.Rows) ([]uuid.UUID, error) {
+	defer rows.Close()
+
+	ids := []uuid.UUID{}
+	for rows.Next() {
+		var id uuid.UUID
+		err := rows.Scan(&id)
+		if err != nil {
+			return nil, fmt.Errorf("error reading job id: %v", err)
+		}
+		ids = append(ids, id)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("error reading jobs: %v", rows.Err())
+	}
+
+	return ids, nil
+}
+
 // Find job by token, this will return an error if the job hasn't been dequeued
 func (q *DBJobQueue) IdFromToken(token uuid.UUID) (id uuid.UUID, err error) {
 	conn, err := q.pool.Acquire(context.Background())
@@ -591,6 +860,13 @@ func (q *DBJobQueue) DeleteJobIncludingDependencies(jobId uuid.UUID) error {
 	}
 
 	jobAndDependencies := append(dependencies, jobId)
+
+	// canceled jobs which were running still have a heartbeat
+	_, err = conn.Exec(context.Background(), sqlDeleteHeartbeats, jobAndDependencies)
+	if err != nil {
+		return fmt.Errorf("Error removing heartbeats for job %v: %v", jobId, err)
+	}
+
 	jobsTag, err := conn.Exec(context.Background(), sqlDeleteJobs, jobAndDependencies)
 	if err != nil {
 		return fmt.Errorf("Error removing from jobs recursively for job %v: %v", jobId, err)
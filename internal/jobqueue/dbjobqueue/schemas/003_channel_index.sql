@@ -0,0 +1,2 @@
+-- Composes are listed per channel (i.e., tenant), newest first.
+CREATE INDEX jobs_channel_queued_at ON jobs(channel, queued_at);
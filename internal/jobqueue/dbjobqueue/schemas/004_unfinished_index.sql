@@ -0,0 +1,3 @@
+-- Quotas count the composes of a channel which haven't finished yet.
+CREATE INDEX jobs_channel_unfinished ON jobs(channel, queued_at)
+WHERE finished_at IS NULL AND canceled = FALSE;
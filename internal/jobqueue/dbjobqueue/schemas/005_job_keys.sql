@@ -0,0 +1,7 @@
+-- Jobs can be looked up by a key, e.g., deduplicated composes by their
+-- content.
+ALTER TABLE jobs
+ADD COLUMN lookup_key varchar;
+
+CREATE INDEX jobs_lookup_key ON jobs(lookup_key, queued_at)
+WHERE lookup_key IS NOT NULL;
@@ -0,0 +1,40 @@
+-- Subscriptions of composes to webhook notifications, see package
+-- notification/dbstore. They're kept next to the jobs, because composer
+-- instances which share a job queue share them as well.
+CREATE TABLE notification_subscriptions(
+       compose_id uuid PRIMARY KEY,
+       channel varchar NOT NULL,
+       root_job uuid NOT NULL,
+       webhooks jsonb NOT NULL,
+       last_status varchar,
+       created_at timestamptz NOT NULL
+);
+
+-- The jobs whose changes can change the status of a subscribed compose
+CREATE TABLE notification_subscription_jobs(
+       compose_id uuid NOT NULL REFERENCES notification_subscriptions(compose_id) ON DELETE CASCADE,
+       job_id uuid NOT NULL,
+       PRIMARY KEY (compose_id, job_id)
+);
+
+CREATE INDEX notification_subscription_jobs_job_id ON notification_subscription_jobs(job_id);
+
+-- Deliveries of events to webhooks. `next_attempt` is NULL once the delivery
+-- succeeded or was given up.
+CREATE TABLE notification_deliveries(
+       id uuid PRIMARY KEY,
+       seq bigserial NOT NULL,
+       compose_id uuid NOT NULL REFERENCES notification_subscriptions(compose_id) ON DELETE CASCADE,
+       event jsonb NOT NULL,
+       webhook jsonb NOT NULL,
+       url varchar NOT NULL,
+       attempts integer NOT NULL DEFAULT 0,
+       delivered boolean NOT NULL DEFAULT FALSE,
+       last_attempt timestamptz,
+       next_attempt timestamptz
+);
+
+CREATE INDEX notification_deliveries_compose_id ON notification_deliveries(compose_id, seq);
+
+CREATE INDEX notification_deliveries_pending ON notification_deliveries(next_attempt)
+WHERE next_attempt IS NOT NULL;
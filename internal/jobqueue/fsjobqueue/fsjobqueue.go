@@ -16,6 +16,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -55,6 +57,24 @@ type fsJobQueue struct {
 	// reported as done.
 	jobIdByToken map[uuid.UUID]uuid.UUID
 	heartbeats   map[uuid.UUID]time.Time // token -> heartbeat
+
+	// Maps channels and keys to their jobs, so that they can be listed
+	// without reading every job in the database. The jobs which haven't
+	// finished and weren't canceled are also kept by channel and id.
+	indexedJobs         map[uuid.UUID]*indexedJob
+	jobsByChannel       map[string][]*indexedJob
+	jobsByKey           map[string][]*indexedJob
+	unfinishedByChannel map[string]map[uuid.UUID]*indexedJob
+}
+
+// indexedJob is the part of a job which is kept in memory to find jobs
+// without reading them.
+type indexedJob struct {
+	id       uuid.UUID
+	jobType  string
+	channel  string
+	key      string
+	queuedAt time.Time
 }
 
 // On-disk job struct. Contains all necessary (but non-redundant) information
@@ -68,6 +88,7 @@ type job struct {
 	Dependencies []uuid.UUID     `json:"dependencies"`
 	Result       json.RawMessage `json:"result,omitempty"`
 	Channel      string          `json:"channel"`
+	Key          string          `json:"key,omitempty"`
 
 	QueuedAt   time.Time `json:"queued_at,omitempty"`
 	StartedAt  time.Time `json:"started_at,omitempty"`
@@ -81,12 +102,16 @@ type job struct {
 // loaded and rescheduled to run if necessary.
 func New(dir string) (*fsJobQueue, error) {
 	q := &fsJobQueue{
-		db:           jsondb.New(dir, 0600),
-		pending:      list.New(),
-		dependants:   make(map[uuid.UUID][]uuid.UUID),
-		jobIdByToken: make(map[uuid.UUID]uuid.UUID),
-		heartbeats:   make(map[uuid.UUID]time.Time),
-		listeners:    make(map[chan struct{}]struct{}),
+		db:                  jsondb.New(dir, 0600),
+		pending:             list.New(),
+		dependants:          make(map[uuid.UUID][]uuid.UUID),
+		jobIdByToken:        make(map[uuid.UUID]uuid.UUID),
+		heartbeats:          make(map[uuid.UUID]time.Time),
+		listeners:           make(map[chan struct{}]struct{}),
+		indexedJobs:         make(map[uuid.UUID]*indexedJob),
+		jobsByChannel:       make(map[string][]*indexedJob),
+		jobsByKey:           make(map[string][]*indexedJob),
+		unfinishedByChannel: make(map[string]map[uuid.UUID]*indexedJob),
 	}
 
 	// Look for jobs that are still pending and build the dependant map.
@@ -113,6 +138,10 @@ func New(dir string) (*fsJobQueue, error) {
 				if err != nil {
 					return nil, fmt.Errorf("Error finishing job '%s' without a token: %v", j.Id, err)
 				}
+				j, err = q.readJob(jobId)
+				if err != nil {
+					return nil, err
+				}
 			} else {
 				q.jobIdByToken[j.Token] = j.Id
 				q.heartbeats[j.Token] = time.Now()
@@ -123,6 +152,7 @@ func New(dir string) (*fsJobQueue, error) {
 		if err != nil {
 			return nil, err
 		}
+		q.indexJob(j)
 	}
 
 	return q, nil
@@ -170,6 +200,7 @@ func (q *fsJobQueue) Enqueue(jobType string, args interface{}, dependencies []uu
 	if err != nil {
 		return uuid.Nil, err
 	}
+	q.indexJob(&j)
 
 	return j.Id, nil
 }
@@ -297,6 +328,7 @@ func (q *fsJobQueue) FinishJob(id uuid.UUID, result interface{}) error {
 	if err != nil {
 		return fmt.Errorf("error writing job %s: %v", id, err)
 	}
+	q.markJobDone(j)
 
 	for _, depid := range q.dependants[id] {
 		dep, err := q.readJob(depid)
@@ -339,10 +371,48 @@ func (q *fsJobQueue) CancelJob(id uuid.UUID) error {
 	if err != nil {
 		return fmt.Errorf("error writing job %s: %v", id, err)
 	}
+	q.markJobDone(j)
 
 	return nil
 }
 
+func (q *fsJobQueue) RequeueJob(id uuid.UUID, args interface{}) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	j, err := q.readJob(id)
+	if err != nil {
+		return err
+	}
+
+	if j.Canceled {
+		return jobqueue.ErrCanceled
+	}
+
+	if j.FinishedAt.IsZero() {
+		return jobqueue.ErrNotFinished
+	}
+
+	if args != nil {
+		j.Args, err = json.Marshal(args)
+		if err != nil {
+			return fmt.Errorf("error marshaling job arguments: %v", err)
+		}
+	}
+	j.Token = uuid.Nil
+	j.StartedAt = time.Time{}
+	j.FinishedAt = time.Time{}
+	j.Result = nil
+
+	err = q.db.Write(id.String(), j)
+	if err != nil {
+		return fmt.Errorf("error writing job %s: %v", id, err)
+	}
+	q.markJobUndone(j)
+
+	return q.maybeEnqueue(j, true)
+}
+
 func (q *fsJobQueue) JobStatus(id uuid.UUID) (jobType string, result json.RawMessage, queued, started, finished time.Time, canceled bool, deps []uuid.UUID, err error) {
 	j, err := q.readJob(id)
 	if err != nil {
@@ -374,6 +444,126 @@ func (q *fsJobQueue) Job(id uuid.UUID) (jobType string, args json.RawMessage, de
 	return
 }
 
+func (q *fsJobQueue) JobsByChannel(channel string, jobTypes []string, queuedAfter, queuedBefore time.Time) ([]uuid.UUID, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	jobs := []*indexedJob{}
+	for _, j := range q.jobsByChannel[channel] {
+		if !hasJobType(jobTypes, j.jobType) {
+			continue
+		}
+		if !queuedAfter.IsZero() && j.queuedAt.Before(queuedAfter) {
+			continue
+		}
+		if !queuedBefore.IsZero() && !j.queuedAt.Before(queuedBefore) {
+			continue
+		}
+		jobs = append(jobs, j)
+	}
+
+	return newestFirst(jobs), nil
+}
+
+func (q *fsJobQueue) UnfinishedJobsByChannel(channel string, jobTypes []string) ([]uuid.UUID, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	jobs := []*indexedJob{}
+	for _, j := range q.unfinishedByChannel[channel] {
+		if hasJobType(jobTypes, j.jobType) {
+			jobs = append(jobs, j)
+		}
+	}
+
+	return newestFirst(jobs), nil
+}
+
+func (q *fsJobQueue) SetJobKey(id uuid.UUID, key string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	j, err := q.readJob(id)
+	if err != nil {
+		return err
+	}
+
+	j.Key = key
+	err = q.db.Write(id.String(), j)
+	if err != nil {
+		return fmt.Errorf("error writing job %s: %v", id, err)
+	}
+
+	ij := q.indexedJobs[id]
+	if ij.key != "" {
+		q.jobsByKey[ij.key] = removeIndexedJob(q.jobsByKey[ij.key], id)
+		if len(q.jobsByKey[ij.key]) == 0 {
+			delete(q.jobsByKey, ij.key)
+		}
+	}
+	ij.key = key
+	if key != "" {
+		q.jobsByKey[key] = append(q.jobsByKey[key], ij)
+	}
+
+	return nil
+}
+
+func (q *fsJobQueue) JobsByKey(key string) ([]uuid.UUID, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if key == "" {
+		return []uuid.UUID{}, nil
+	}
+	jobs := append([]*indexedJob{}, q.jobsByKey[key]...)
+	return newestFirst(jobs), nil
+}
+
+func (q *fsJobQueue) DeleteJobIncludingDependencies(id uuid.UUID) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	// walk the dependencies breadth-first; they might not form a tree
+	jobs := []*job{}
+	seen := map[uuid.UUID]bool{id: true}
+	queue := []uuid.UUID{id}
+	for len(queue) > 0 {
+		j, err := q.readJob(queue[0])
+		queue = queue[1:]
+		if err == jobqueue.ErrNotExist {
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		jobs = append(jobs, j)
+		for _, d := range j.Dependencies {
+			if !seen[d] {
+				seen[d] = true
+				queue = append(queue, d)
+			}
+		}
+	}
+
+	for _, j := range jobs {
+		q.removePendingJob(j.Id)
+		delete(q.dependants, j.Id)
+		if j.Token != uuid.Nil {
+			delete(q.jobIdByToken, j.Token)
+			delete(q.heartbeats, j.Token)
+		}
+		q.unindexJob(j)
+
+		err := q.db.Delete(j.Id.String())
+		if err != nil {
+			return fmt.Errorf("error deleting job %s: %v", j.Id, err)
+		}
+	}
+
+	return nil
+}
+
 func (q *fsJobQueue) IdFromToken(token uuid.UUID) (id uuid.UUID, err error) {
 	q.mu.Lock()
 	defer q.mu.Unlock()
@@ -406,6 +596,117 @@ func (q *fsJobQueue) RefreshHeartbeat(token uuid.UUID) {
 	}
 }
 
+// indexJob adds `j` to the in-memory indexes. `q.mu` must be locked, except
+// in `New()`.
+func (q *fsJobQueue) indexJob(j *job) {
+	ij := &indexedJob{
+		id:       j.Id,
+		jobType:  j.Type,
+		channel:  j.Channel,
+		key:      j.Key,
+		queuedAt: j.QueuedAt,
+	}
+	q.indexedJobs[j.Id] = ij
+	q.jobsByChannel[j.Channel] = append(q.jobsByChannel[j.Channel], ij)
+	if j.Key != "" {
+		q.jobsByKey[j.Key] = append(q.jobsByKey[j.Key], ij)
+	}
+
+	if j.FinishedAt.IsZero() && !j.Canceled {
+		unfinished, ok := q.unfinishedByChannel[j.Channel]
+		if !ok {
+			unfinished = make(map[uuid.UUID]*indexedJob)
+			q.unfinishedByChannel[j.Channel] = unfinished
+		}
+		unfinished[j.Id] = ij
+	}
+}
+
+// markJobDone removes `j` from the index of unfinished jobs, after it
+// finished or was canceled. `q.mu` must be locked.
+func (q *fsJobQueue) markJobDone(j *job) {
+	unfinished := q.unfinishedByChannel[j.Channel]
+	delete(unfinished, j.Id)
+	if len(unfinished) == 0 {
+		delete(q.unfinishedByChannel, j.Channel)
+	}
+}
+
+// markJobUndone adds `j` back to the index of unfinished jobs, after it
+// was requeued. `q.mu` must be locked.
+func (q *fsJobQueue) markJobUndone(j *job) {
+	ij, ok := q.indexedJobs[j.Id]
+	if !ok {
+		return
+	}
+	unfinished, ok := q.unfinishedByChannel[j.Channel]
+	if !ok {
+		unfinished = make(map[uuid.UUID]*indexedJob)
+		q.unfinishedByChannel[j.Channel] = unfinished
+	}
+	unfinished[j.Id] = ij
+}
+
+// unindexJob removes `j` from the in-memory indexes. `q.mu` must be locked.
+func (q *fsJobQueue) unindexJob(j *job) {
+	q.markJobDone(j)
+
+	ij, ok := q.indexedJobs[j.Id]
+	if !ok {
+		return
+	}
+	delete(q.indexedJobs, j.Id)
+
+	q.jobsByChannel[ij.channel] = removeIndexedJob(q.jobsByChannel[ij.channel], j.Id)
+	if len(q.jobsByChannel[ij.channel]) == 0 {
+		delete(q.jobsByChannel, ij.channel)
+	}
+	if ij.key != "" {
+		q.jobsByKey[ij.key] = removeIndexedJob(q.jobsByKey[ij.key], j.Id)
+		if len(q.jobsByKey[ij.key]) == 0 {
+			delete(q.jobsByKey, ij.key)
+		}
+	}
+}
+
+// removeIndexedJob returns `jobs` without the job with `id`.
+func removeIndexedJob(jobs []*indexedJob, id uuid.UUID) []*indexedJob {
+	for i := range jobs {
+		if jobs[i].id == id {
+			return append(jobs[:i], jobs[i+1:]...)
+		}
+	}
+	return jobs
+}
+
+// newestFirst returns the ids of `jobs`, most recently queued first.
+func newestFirst(jobs []*indexedJob) []uuid.UUID {
+	sort.Slice(jobs, func(i, k int) bool {
+		if jobs[i].queuedAt.Equal(jobs[k].queuedAt) {
+			return jobs[i].id.String() < jobs[k].id.String()
+		}
+		return jobs[i].queuedAt.After(jobs[k].queuedAt)
+	})
+
+	ids := make([]uuid.UUID, len(jobs))
+	for i, j := range jobs {
+		ids[i] = j.id
+	}
+	return ids
+}
+
+// hasJobType returns true if `jobType` or its prefix before a colon is one
+// of `jobTypes`.
+func hasJobType(jobTypes []string, jobType string) bool {
+	prefix := strings.SplitN(jobType, ":", 2)[0]
+	for _, t := range jobTypes {
+		if t == jobType || t == prefix {
+			return true
+		}
+	}
+	return false
+}
+
 // Reads job with `id`. This is a thin wrapper around `q.db.Read`, which
 // returns the job directly, or and error if a job with `id` does not exist.
 func (q *fsJobQueue) readJob(id uuid.UUID) (*job, error) {
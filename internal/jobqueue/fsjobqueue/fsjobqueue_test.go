@@ -4,7 +4,9 @@ import (
 	"io/ioutil"
 	"os"
 	"testing"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 
 	"github.com/osbuild/osbuild-composer/internal/jobqueue"
@@ -34,3 +36,25 @@ func TestNonExistant(t *testing.T) {
 	require.Error(t, err)
 	require.Nil(t, q)
 }
+
+func TestIndexAfterRestart(t *testing.T) {
+	dir := t.TempDir()
+	q, err := fsjobqueue.New(dir)
+	require.NoError(t, err)
+	id, err := q.Enqueue("osbuild:x86_64", nil, nil, "toucan")
+	require.NoError(t, err)
+	require.NoError(t, q.SetJobKey(id, "reef"))
+
+	// jobs are found by channel and key again when the queue is loaded
+	q, err = fsjobqueue.New(dir)
+	require.NoError(t, err)
+	ids, err := q.JobsByChannel("toucan", []string{"osbuild"}, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	require.Equal(t, []uuid.UUID{id}, ids)
+	ids, err = q.UnfinishedJobsByChannel("toucan", []string{"osbuild"})
+	require.NoError(t, err)
+	require.Equal(t, []uuid.UUID{id}, ids)
+	ids, err = q.JobsByKey("reef")
+	require.NoError(t, err)
+	require.Equal(t, []uuid.UUID{id}, ids)
+}
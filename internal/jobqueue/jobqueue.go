@@ -56,6 +56,14 @@ type JobQueue interface {
 	// Cancel a job. Does nothing if the job has already finished.
 	CancelJob(id uuid.UUID) error
 
+	// Requeues a job which has finished, so that it runs again. Its
+	// result is dropped, and it waits for its dependencies like a new
+	// job. If `args` is not nil, it replaces the job's arguments.
+	//
+	// Returns ErrNotFinished if the job hasn't finished yet, and
+	// ErrCanceled if it was canceled.
+	RequeueJob(id uuid.UUID, args interface{}) error
+
 	// If the job has finished, returns the result as raw JSON.
 	//
 	// Returns the current status of the job, in the form of three times:
@@ -69,6 +77,35 @@ type JobQueue interface {
 	// Job returns all the parameters that define a job (everything provided during Enqueue).
 	Job(id uuid.UUID) (jobType string, args json.RawMessage, dependencies []uuid.UUID, channel string, err error)
 
+	// Returns the ids of the jobs which were enqueued on `channel` and have
+	// one of the types in `jobTypes`, most recently queued first. A type
+	// also matches jobs whose type has it as prefix, followed by a colon
+	// (e.g., "osbuild" matches "osbuild:x86_64").
+	//
+	// Only jobs queued in [queuedAfter, queuedBefore) are returned. Zero
+	// times leave the respective end of the interval open.
+	JobsByChannel(channel string, jobTypes []string, queuedAfter, queuedBefore time.Time) ([]uuid.UUID, error)
+
+	// Returns the ids of the jobs which were enqueued on `channel`, have
+	// one of the types in `jobTypes`, and have neither finished nor been
+	// canceled, most recently queued first. Types match as in
+	// JobsByChannel().
+	UnfinishedJobsByChannel(channel string, jobTypes []string) ([]uuid.UUID, error)
+
+	// Sets the key of the job with `id`, by which it is found with
+	// JobsByKey(). A job has at most one key, but several jobs can have
+	// the same one.
+	SetJobKey(id uuid.UUID, key string) error
+
+	// Returns the ids of the jobs whose key is `key`, most recently queued
+	// first.
+	JobsByKey(key string) ([]uuid.UUID, error)
+
+	// Deletes the job with `id` and all the jobs it depends on, recursively.
+	// Jobs which are running should be canceled first. Jobs which don't exist
+	// are ignored.
+	DeleteJobIncludingDependencies(id uuid.UUID) error
+
 	// Find job by token, this will return an error if the job hasn't been dequeued
 	IdFromToken(token uuid.UUID) (id uuid.UUID, err error)
 
@@ -79,10 +116,36 @@ type JobQueue interface {
 	RefreshHeartbeat(token uuid.UUID)
 }
 
+// EventBroadcaster is implemented by job queues which are shared by several
+// processes, to pass events such as the log lines of running jobs between
+// them.
+type EventBroadcaster interface {
+	// Sends `payload` to all processes listening for events, including
+	// this one. It must be smaller than MaxEventSize.
+	BroadcastEvent(payload []byte) error
+
+	// Calls `handler` for each broadcast event, in the order they were
+	// sent, until `ctx` is canceled or the connection to the queue breaks.
+	ListenForEvents(ctx context.Context, handler func(payload []byte)) error
+}
+
+// MaxEventSize is the maximum size of a broadcast event's payload.
+const MaxEventSize = 7000
+
+// ChannelLocker is implemented by job queues which are shared by several
+// processes, to serialize changes to a channel between them.
+type ChannelLocker interface {
+	// Blocks until no other process holds the lock of `channel` and
+	// takes it, or until `ctx` is canceled. The returned function
+	// releases the lock.
+	LockChannel(ctx context.Context, channel string) (unlock func(), err error)
+}
+
 var (
 	ErrNotExist       = errors.New("job does not exist")
 	ErrNotPending     = errors.New("job is not pending")
 	ErrNotRunning     = errors.New("job is not running")
 	ErrCanceled       = errors.New("job ws canceled")
+	ErrNotFinished    = errors.New("job has not finished")
 	ErrDequeueTimeout = errors.New("dequeue context timed out or was canceled")
 )
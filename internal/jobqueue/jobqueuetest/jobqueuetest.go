@@ -42,6 +42,11 @@ func TestJobQueue(t *testing.T, makeJobQueue MakeJobQueue) {
 	t.Run("timeout", wrap(testDequeueTimeout))
 	t.Run("dequeue-by-id", wrap(testDequeueByID))
 	t.Run("multiple-channels", wrap(testMultipleChannels))
+	t.Run("jobs-by-channel", wrap(testJobsByChannel))
+	t.Run("unfinished-jobs-by-channel", wrap(testUnfinishedJobsByChannel))
+	t.Run("jobs-by-key", wrap(testJobsByKey))
+	t.Run("delete", wrap(testDeleteJobIncludingDependencies))
+	t.Run("requeue", wrap(testRequeueJob))
 }
 
 func pushTestJob(t *testing.T, q jobqueue.JobQueue, jobType string, args interface{}, dependencies []uuid.UUID, channel string) uuid.UUID {
@@ -585,3 +590,182 @@ func testMultipleChannels(t *testing.T, q jobqueue.JobQueue) {
 		require.NoError(t, err)
 	})
 }
+
+func testJobsByChannel(t *testing.T, q jobqueue.JobQueue) {
+	one := pushTestJob(t, q, "osbuild:x86_64", nil, nil, "toucan")
+	time.Sleep(10 * time.Millisecond)
+	pushTestJob(t, q, "depsolve", nil, nil, "toucan")
+	pushTestJob(t, q, "osbuild:aarch64", nil, nil, "kingfisher")
+	time.Sleep(10 * time.Millisecond)
+	two := pushTestJob(t, q, "koji-finalize", nil, nil, "toucan")
+
+	ids, err := q.JobsByChannel("toucan", []string{"osbuild", "koji-finalize"}, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	require.Equal(t, []uuid.UUID{two, one}, ids)
+
+	ids, err = q.JobsByChannel("toucan", []string{"osbuild:x86_64"}, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	require.Equal(t, []uuid.UUID{one}, ids)
+
+	ids, err = q.JobsByChannel("", []string{"osbuild", "koji-finalize"}, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	require.Empty(t, ids)
+
+	_, _, queued, _, _, _, _, err := q.JobStatus(two)
+	require.NoError(t, err)
+
+	ids, err = q.JobsByChannel("toucan", []string{"osbuild", "koji-finalize"}, queued, time.Time{})
+	require.NoError(t, err)
+	require.Equal(t, []uuid.UUID{two}, ids)
+
+	ids, err = q.JobsByChannel("toucan", []string{"osbuild", "koji-finalize"}, time.Time{}, queued)
+	require.NoError(t, err)
+	require.Equal(t, []uuid.UUID{one}, ids)
+
+	// deleted jobs aren't listed
+	require.NoError(t, q.DeleteJobIncludingDependencies(one))
+	ids, err = q.JobsByChannel("toucan", []string{"osbuild", "koji-finalize"}, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	require.Equal(t, []uuid.UUID{two}, ids)
+}
+
+func testUnfinishedJobsByChannel(t *testing.T, q jobqueue.JobQueue) {
+	finished := pushTestJob(t, q, "osbuild:x86_64", nil, nil, "toucan")
+	canceled := pushTestJob(t, q, "osbuild:x86_64", nil, nil, "toucan")
+	running := pushTestJob(t, q, "osbuild:aarch64", nil, nil, "toucan")
+	time.Sleep(10 * time.Millisecond)
+	pending := pushTestJob(t, q, "koji-finalize", nil, nil, "toucan")
+	pushTestJob(t, q, "depsolve", nil, nil, "toucan")
+	pushTestJob(t, q, "osbuild:x86_64", nil, nil, "kingfisher")
+
+	for _, id := range []uuid.UUID{finished, running} {
+		_, _, _, _, err := q.DequeueByID(context.Background(), id)
+		require.NoError(t, err)
+	}
+	require.NoError(t, q.FinishJob(finished, testResult{}))
+	require.NoError(t, q.CancelJob(canceled))
+
+	ids, err := q.UnfinishedJobsByChannel("toucan", []string{"osbuild", "koji-finalize"})
+	require.NoError(t, err)
+	require.Equal(t, []uuid.UUID{pending, running}, ids)
+
+	require.NoError(t, q.DeleteJobIncludingDependencies(pending))
+	ids, err = q.UnfinishedJobsByChannel("toucan", []string{"osbuild", "koji-finalize"})
+	require.NoError(t, err)
+	require.Equal(t, []uuid.UUID{running}, ids)
+}
+
+func testJobsByKey(t *testing.T, q jobqueue.JobQueue) {
+	one := pushTestJob(t, q, "octopus", nil, nil, "")
+	time.Sleep(10 * time.Millisecond)
+	two := pushTestJob(t, q, "clownfish", nil, nil, "toucan")
+	other := pushTestJob(t, q, "octopus", nil, nil, "")
+
+	require.NoError(t, q.SetJobKey(one, "reef"))
+	require.NoError(t, q.SetJobKey(two, "reef"))
+	require.NoError(t, q.SetJobKey(other, "lagoon"))
+	require.ErrorIs(t, q.SetJobKey(uuid.New(), "reef"), jobqueue.ErrNotExist)
+
+	ids, err := q.JobsByKey("reef")
+	require.NoError(t, err)
+	require.Equal(t, []uuid.UUID{two, one}, ids)
+
+	// keys can be changed and jobs deleted
+	require.NoError(t, q.SetJobKey(other, "reef"))
+	require.NoError(t, q.DeleteJobIncludingDependencies(two))
+	ids, err = q.JobsByKey("reef")
+	require.NoError(t, err)
+	require.Equal(t, []uuid.UUID{other, one}, ids)
+
+	ids, err = q.JobsByKey("lagoon")
+	require.NoError(t, err)
+	require.Empty(t, ids)
+}
+
+func testDeleteJobIncludingDependencies(t *testing.T, q jobqueue.JobQueue) {
+	// one -> two -> three, and one -> three
+	one := pushTestJob(t, q, "octopus", nil, nil, "")
+	two := pushTestJob(t, q, "clownfish", nil, []uuid.UUID{one}, "")
+	three := pushTestJob(t, q, "sea-urchin", nil, []uuid.UUID{one, two}, "")
+	control := pushTestJob(t, q, "zebra", nil, nil, "")
+
+	// a canceled job which was running
+	finishNextTestJob(t, q, "octopus", testResult{}, nil)
+	id, _, _, _, _, err := q.Dequeue(context.Background(), []string{"clownfish"}, []string{""})
+	require.NoError(t, err)
+	require.Equal(t, two, id)
+	require.NoError(t, q.CancelJob(two))
+	require.NoError(t, q.CancelJob(three))
+
+	require.NoError(t, q.DeleteJobIncludingDependencies(three))
+	for _, id := range []uuid.UUID{one, two, three} {
+		_, _, _, _, err = q.Job(id)
+		require.ErrorIs(t, err, jobqueue.ErrNotExist)
+	}
+
+	_, _, _, _, err = q.Job(control)
+	require.NoError(t, err)
+
+	// pending jobs are removed from the queue
+	pending := pushTestJob(t, q, "octopus", nil, nil, "")
+	require.NoError(t, q.DeleteJobIncludingDependencies(pending))
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, _, _, _, _, err = q.Dequeue(ctx, []string{"octopus"}, []string{""})
+	require.Equal(t, jobqueue.ErrDequeueTimeout, err)
+
+	// jobs which don't exist are ignored
+	require.NoError(t, q.DeleteJobIncludingDependencies(uuid.New()))
+}
+
+func testRequeueJob(t *testing.T, q jobqueue.JobQueue) {
+	// one -> two
+	one := pushTestJob(t, q, "octopus", "first", nil, "")
+	two := pushTestJob(t, q, "clownfish", nil, []uuid.UUID{one}, "")
+
+	// only finished jobs can be requeued
+	require.Equal(t, jobqueue.ErrNotFinished, q.RequeueJob(one, nil))
+	require.Equal(t, jobqueue.ErrNotExist, q.RequeueJob(uuid.New(), nil))
+
+	finishNextTestJob(t, q, "octopus", testResult{}, nil)
+	finishNextTestJob(t, q, "clownfish", testResult{}, []uuid.UUID{one})
+
+	// the dependant waits for the requeued dependency again
+	require.NoError(t, q.RequeueJob(one, "second"))
+	require.NoError(t, q.RequeueJob(two, nil))
+
+	_, result, _, started, finished, _, _, err := q.JobStatus(two)
+	require.NoError(t, err)
+	require.Nil(t, result)
+	require.True(t, started.IsZero())
+	require.True(t, finished.IsZero())
+
+	unfinished, err := q.UnfinishedJobsByChannel("", []string{"octopus", "clownfish"})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []uuid.UUID{one, two}, unfinished)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, _, _, _, _, err = q.Dequeue(ctx, []string{"clownfish"}, []string{""})
+	require.Equal(t, jobqueue.ErrDequeueTimeout, err)
+
+	id, _, _, _, args, err := q.Dequeue(context.Background(), []string{"octopus"}, []string{""})
+	require.NoError(t, err)
+	require.Equal(t, one, id)
+	var parsedArgs string
+	require.NoError(t, json.Unmarshal(args, &parsedArgs))
+	require.Equal(t, "second", parsedArgs)
+	require.NoError(t, q.FinishJob(one, testResult{}))
+
+	// the arguments are kept when they aren't replaced
+	id, _, _, _, args, err = q.Dequeue(context.Background(), []string{"clownfish"}, []string{""})
+	require.NoError(t, err)
+	require.Equal(t, two, id)
+	require.JSONEq(t, "null", string(args))
+	require.NoError(t, q.FinishJob(two, testResult{}))
+
+	// canceled jobs can't be requeued
+	three := pushTestJob(t, q, "sea-urchin", nil, nil, "")
+	require.NoError(t, q.CancelJob(three))
+	require.Equal(t, jobqueue.ErrCanceled, q.RequeueJob(three, nil))
+}
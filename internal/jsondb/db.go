@@ -88,6 +88,16 @@ func (db *JSONDatabase) Write(name string, document interface{}) error {
 	})
 }
 
+// Deletes the document at `name`. It is not an error if the document does
+// not exist.
+func (db *JSONDatabase) Delete(name string) error {
+	err := os.Remove(path.Join(db.dir, name+".json"))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error deleting db file %s: %v", name, err)
+	}
+	return nil
+}
+
 // writeFileAtomically writes data to `filename` in `directory` atomically, by
 // first creating a temporary file in `directory` and only moving it when
 // writing succeeded. `writer` gets passed the open file handle to write to and
@@ -135,3 +135,31 @@ func TestMultiple(t *testing.T) {
 		require.Equalf(t, doc, d, "error retrieving document '%s'", name)
 	}
 }
+
+func TestDelete(t *testing.T) {
+	dir, err := ioutil.TempDir("", "jsondb-test-")
+	require.NoError(t, err)
+	defer cleanupTempDir(t, dir)
+
+	db := jsondb.New(dir, 0600)
+
+	err = db.Write("one", document{"octopus", true})
+	require.NoError(t, err)
+	err = db.Write("two", document{"zebra", false})
+	require.NoError(t, err)
+
+	err = db.Delete("one")
+	require.NoError(t, err)
+
+	exists, err := db.Read("one", nil)
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	names, err := db.List()
+	require.NoError(t, err)
+	require.Equal(t, []string{"two"}, names)
+
+	// deleting a document which doesn't exist isn't an error
+	err = db.Delete("one")
+	require.NoError(t, err)
+}
@@ -86,6 +86,44 @@ func createBaseDepsolveFixture() []rpmmd.PackageSpec {
 	}
 }
 
+// generateAdvisories returns advisories for the packages of the composes in
+// store.FixtureBase(): one which is fixed in them, one which isn't, and one
+// for an unrelated package.
+func generateAdvisories() []rpmmd.Advisory {
+	return []rpmmd.Advisory{
+		{
+			ID:       "FEDORA-2019-0001",
+			Type:     "security",
+			Severity: "Important",
+			Title:    "test1 security update",
+			Issued:   "2019-05-01 00:00:00",
+			CVEs:     []string{"CVE-2019-0001"},
+			Packages: []rpmmd.AdvisoryPackage{
+				{Name: "test1", Epoch: 0, Version: "2.11.2", Release: "1.fc35", Arch: "test_arch"},
+			},
+		},
+		{
+			ID:       "FEDORA-2019-0002",
+			Type:     "security",
+			Severity: "Critical",
+			Title:    "test2 security update",
+			Issued:   "2019-06-01 00:00:00",
+			CVEs:     []string{"CVE-2019-0002", "CVE-2019-0003"},
+			Packages: []rpmmd.AdvisoryPackage{
+				{Name: "test2", Epoch: 3, Version: "4.2.10", Release: "1.fc35", Arch: "test_arch"},
+			},
+		},
+		{
+			ID:    "FEDORA-2019-0003",
+			Type:  "bugfix",
+			Title: "unrelated bug fix update",
+			Packages: []rpmmd.AdvisoryPackage{
+				{Name: "package0", Epoch: 0, Version: "0.2", Release: "0.fc30", Arch: "test_arch"},
+			},
+		},
+	}
+}
+
 func BaseFixture(tmpdir string) Fixture {
 	return Fixture{
 		fetchPackageList{
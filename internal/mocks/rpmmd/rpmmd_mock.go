@@ -37,5 +37,47 @@ func (r *rpmmdMock) FetchMetadata(repos []rpmmd.RepoConfig, modulePlatformID, ar
 }
 
 func (r *rpmmdMock) Depsolve(packageSet rpmmd.PackageSet, repos []rpmmd.RepoConfig, modulePlatformID, arch, releasever string) ([]rpmmd.PackageSpec, map[string]string, error) {
-	return r.Fixture.depsolve.ret, r.Fixture.fetchPackageList.checksums, r.Fixture.depsolve.err
+	if !packageSet.Explain || r.Fixture.depsolve.ret == nil {
+		return r.Fixture.depsolve.ret, r.Fixture.fetchPackageList.checksums, r.Fixture.depsolve.err
+	}
+
+	// pretend that the first package was requested by all specs and that
+	// each of the others is required by the one before it
+	specs := make([]rpmmd.PackageSpec, len(r.Fixture.depsolve.ret))
+	copy(specs, r.Fixture.depsolve.ret)
+	for i := range specs {
+		if i == 0 {
+			specs[i].Reason = "user"
+			specs[i].RequestedBy = packageSet.Include
+		} else {
+			specs[i].Reason = "dependency"
+			specs[i].RequiredBy = []string{specs[i-1].Name}
+		}
+	}
+	return specs, r.Fixture.fetchPackageList.checksums, r.Fixture.depsolve.err
+}
+
+func (r *rpmmdMock) FetchUpdateInfo(repos []rpmmd.RepoConfig, arch, releasever string) ([]rpmmd.Advisory, error) {
+	return generateAdvisories(), r.Fixture.fetchPackageList.err
+}
+
+func (r *rpmmdMock) CheckRepositories(repos []rpmmd.RepoConfig, arch, releasever string, localKeys bool) []rpmmd.RepoHealth {
+	results := make([]rpmmd.RepoHealth, 0, len(repos))
+	for _, repo := range repos {
+		health := rpmmd.RepoHealth{
+			Name:    repo.Name,
+			Healthy: r.Fixture.fetchPackageList.err == nil,
+		}
+		if health.Healthy {
+			health.Checks = []rpmmd.RepoHealthCheck{
+				{Name: rpmmd.RepoCheckReachable, Status: rpmmd.RepoHealthOK, Message: repo.BaseURL},
+			}
+		} else {
+			health.Checks = []rpmmd.RepoHealthCheck{
+				{Name: rpmmd.RepoCheckReachable, Status: rpmmd.RepoHealthError, Message: r.Fixture.fetchPackageList.err.Error()},
+			}
+		}
+		results = append(results, health)
+	}
+	return results
 }
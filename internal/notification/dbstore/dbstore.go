@@ -0,0 +1,358 @@
+// Package dbstore implements a notification.Store backed by a PostgreSQL
+// database. Its tables are created by the migrations of package dbjobqueue,
+// because it is meant to share the database with the job queue.
+package dbstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	logrus "github.com/sirupsen/logrus"
+
+	"github.com/osbuild/osbuild-composer/internal/notification"
+)
+
+const (
+	sqlSubscribe = `
+		INSERT INTO notification_subscriptions(compose_id, channel, root_job, webhooks, created_at)
+		VALUES ($1, $2, $3, $4, now())
+		ON CONFLICT DO NOTHING`
+	sqlInsertSubscriptionJob = `INSERT INTO notification_subscription_jobs VALUES ($1, $2)`
+	sqlUnsubscribe           = `
+		DELETE FROM notification_subscriptions
+		WHERE compose_id = $1`
+
+	sqlQuerySubscription = `
+		SELECT channel, root_job, webhooks,
+		       ARRAY(SELECT job_id::text FROM notification_subscription_jobs j WHERE j.compose_id = s.compose_id)
+		FROM notification_subscriptions s
+		WHERE compose_id = $1`
+	sqlQuerySubscriptionsOfJob = `
+		SELECT s.compose_id, s.channel, s.root_job, s.webhooks,
+		       ARRAY(SELECT job_id::text FROM notification_subscription_jobs j WHERE j.compose_id = s.compose_id)
+		FROM notification_subscriptions s
+		JOIN notification_subscription_jobs j ON j.compose_id = s.compose_id
+		WHERE j.job_id = $1
+		ORDER BY s.created_at, s.compose_id`
+	sqlQuerySubscribed = `
+		SELECT EXISTS(SELECT 1 FROM notification_subscriptions WHERE compose_id = $1)`
+
+	// the row lock taken by the update orders concurrent events of a compose
+	sqlSetLastStatus = `
+		UPDATE notification_subscriptions
+		SET last_status = $2
+		WHERE compose_id = $1 AND last_status IS DISTINCT FROM $2`
+	sqlInsertDelivery = `
+		INSERT INTO notification_deliveries(id, compose_id, event, webhook, url, next_attempt)
+		VALUES ($1, $2, $3, $4, $5, $6)`
+
+	// Deliveries to a URL are made in the order of the events of a
+	// compose, so skip those which wait for an earlier one
+	sqlClaimDelivery = `
+		UPDATE notification_deliveries
+		SET attempts = attempts + 1, last_attempt = $1, next_attempt = $2
+		WHERE id = (
+		  SELECT id
+		  FROM notification_deliveries d
+		  WHERE next_attempt <= $1
+		    AND NOT EXISTS (
+		      SELECT 1
+		      FROM notification_deliveries e
+		      WHERE e.compose_id = d.compose_id AND e.url = d.url
+		        AND e.seq < d.seq AND e.next_attempt IS NOT NULL)
+		  ORDER BY next_attempt
+		  LIMIT 1
+		  FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, event, webhook, attempts`
+	sqlFinishAttempt = `
+		UPDATE notification_deliveries
+		SET delivered = $2, next_attempt = $3
+		WHERE id = $1`
+
+	sqlQueryDeliveries = `
+		SELECT event, url, attempts, delivered, last_attempt
+		FROM notification_deliveries
+		WHERE compose_id = $1 AND next_attempt IS NULL
+		ORDER BY seq`
+)
+
+type DBStore struct {
+	pool *pgxpool.Pool
+}
+
+// Create a new DBStore object for `url`.
+func New(url string) (*DBStore, error) {
+	pool, err := pgxpool.Connect(context.Background(), url)
+	if err != nil {
+		return nil, fmt.Errorf("error establishing connection: %v", err)
+	}
+
+	return &DBStore{pool}, nil
+}
+
+func (s *DBStore) Close() {
+	s.pool.Close()
+}
+
+func (s *DBStore) Subscribe(sub notification.Subscription) error {
+	conn, err := s.pool.Acquire(context.Background())
+	if err != nil {
+		return fmt.Errorf("error connecting to database: %v", err)
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(context.Background())
+	if err != nil {
+		return fmt.Errorf("error starting database transaction: %v", err)
+	}
+	defer func() {
+		err := tx.Rollback(context.Background())
+		if err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			logrus.Error("error rolling back subscribe transaction: ", err)
+		}
+	}()
+
+	// a nil slice would be stored as NULL
+	webhooks := sub.Webhooks
+	if webhooks == nil {
+		webhooks = []notification.Webhook{}
+	}
+
+	tag, err := tx.Exec(context.Background(), sqlSubscribe, sub.ComposeID, sub.Channel, sub.RootJob, webhooks)
+	if err != nil {
+		return fmt.Errorf("error subscribing compose %s: %v", sub.ComposeID, err)
+	}
+	if tag.RowsAffected() != 1 {
+		return notification.ErrAlreadySubscribed
+	}
+
+	for _, job := range sub.Jobs {
+		_, err = tx.Exec(context.Background(), sqlInsertSubscriptionJob, sub.ComposeID, job)
+		if err != nil {
+			return fmt.Errorf("error adding job %s to subscription of compose %s: %v", job, sub.ComposeID, err)
+		}
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return fmt.Errorf("unable to commit database transaction: %v", err)
+	}
+	return nil
+}
+
+func (s *DBStore) Unsubscribe(composeID uuid.UUID) error {
+	conn, err := s.pool.Acquire(context.Background())
+	if err != nil {
+		return fmt.Errorf("error connecting to database: %v", err)
+	}
+	defer conn.Release()
+
+	// jobs and deliveries are deleted by the foreign keys
+	_, err = conn.Exec(context.Background(), sqlUnsubscribe, composeID)
+	if err != nil {
+		return fmt.Errorf("error unsubscribing compose %s: %v", composeID, err)
+	}
+	return nil
+}
+
+func (s *DBStore) Subscription(composeID uuid.UUID) (*notification.Subscription, error) {
+	conn, err := s.pool.Acquire(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to database: %v", err)
+	}
+	defer conn.Release()
+
+	sub := notification.Subscription{ComposeID: composeID}
+	var jobs []string
+	err = conn.QueryRow(context.Background(), sqlQuerySubscription, composeID).Scan(&sub.Channel, &sub.RootJob, &sub.Webhooks, &jobs)
+	if err == pgx.ErrNoRows {
+		return nil, notification.ErrNotSubscribed
+	} else if err != nil {
+		return nil, fmt.Errorf("error querying subscription of compose %s: %v", composeID, err)
+	}
+	sub.Jobs, err = parseJobIds(jobs)
+	if err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+func (s *DBStore) SubscriptionsOfJob(job uuid.UUID) ([]notification.Subscription, error) {
+	conn, err := s.pool.Acquire(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to database: %v", err)
+	}
+	defer conn.Release()
+
+	rows, err := conn.Query(context.Background(), sqlQuerySubscriptionsOfJob, job)
+	if err != nil {
+		return nil, fmt.Errorf("error querying subscriptions of job %s: %v", job, err)
+	}
+	defer rows.Close()
+
+	var subs []notification.Subscription
+	for rows.Next() {
+		var sub notification.Subscription
+		var jobs []string
+		err = rows.Scan(&sub.ComposeID, &sub.Channel, &sub.RootJob, &sub.Webhooks, &jobs)
+		if err != nil {
+			return nil, fmt.Errorf("error reading subscription: %v", err)
+		}
+		sub.Jobs, err = parseJobIds(jobs)
+		if err != nil {
+			return nil, err
+		}
+		subs = append(subs, sub)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("error reading subscriptions: %v", rows.Err())
+	}
+	return subs, nil
+}
+
+func (s *DBStore) AddEvent(event notification.Event, webhooks []notification.Webhook) (bool, error) {
+	conn, err := s.pool.Acquire(context.Background())
+	if err != nil {
+		return false, fmt.Errorf("error connecting to database: %v", err)
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(context.Background())
+	if err != nil {
+		return false, fmt.Errorf("error starting database transaction: %v", err)
+	}
+	defer func() {
+		err := tx.Rollback(context.Background())
+		if err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			logrus.Error("error rolling back event transaction: ", err)
+		}
+	}()
+
+	tag, err := tx.Exec(context.Background(), sqlSetLastStatus, event.ComposeID, event.Status)
+	if err != nil {
+		return false, fmt.Errorf("error setting status of compose %s: %v", event.ComposeID, err)
+	}
+	if tag.RowsAffected() == 0 {
+		var subscribed bool
+		err = tx.QueryRow(context.Background(), sqlQuerySubscribed, event.ComposeID).Scan(&subscribed)
+		if err != nil {
+			return false, fmt.Errorf("error querying subscription of compose %s: %v", event.ComposeID, err)
+		}
+		if !subscribed {
+			return false, notification.ErrNotSubscribed
+		}
+		return false, nil
+	}
+
+	for _, webhook := range webhooks {
+		_, err = tx.Exec(context.Background(), sqlInsertDelivery, uuid.New(), event.ComposeID, event, webhook, webhook.URL, event.Time)
+		if err != nil {
+			return false, fmt.Errorf("error adding delivery of event %s: %v", event.ID, err)
+		}
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return false, fmt.Errorf("unable to commit database transaction: %v", err)
+	}
+	return true, nil
+}
+
+func (s *DBStore) ClaimDelivery(now time.Time, lease time.Duration) (*notification.PendingDelivery, error) {
+	conn, err := s.pool.Acquire(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to database: %v", err)
+	}
+	defer conn.Release()
+
+	var delivery notification.PendingDelivery
+	err = conn.QueryRow(context.Background(), sqlClaimDelivery, now, now.Add(lease)).Scan(&delivery.ID, &delivery.Event, &delivery.Webhook, &delivery.Attempts)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("error claiming delivery: %v", err)
+	}
+	return &delivery, nil
+}
+
+func (s *DBStore) FinishAttempt(id uuid.UUID, delivered bool, retryAt time.Time) error {
+	conn, err := s.pool.Acquire(context.Background())
+	if err != nil {
+		return fmt.Errorf("error connecting to database: %v", err)
+	}
+	defer conn.Release()
+
+	var next *time.Time
+	if !delivered && !retryAt.IsZero() {
+		next = &retryAt
+	}
+
+	_, err = conn.Exec(context.Background(), sqlFinishAttempt, id, delivered, next)
+	if err != nil {
+		return fmt.Errorf("error finishing attempt of delivery %s: %v", id, err)
+	}
+	return nil
+}
+
+func (s *DBStore) Deliveries(composeID uuid.UUID) ([]notification.Delivery, error) {
+	conn, err := s.pool.Acquire(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to database: %v", err)
+	}
+	defer conn.Release()
+
+	var subscribed bool
+	err = conn.QueryRow(context.Background(), sqlQuerySubscribed, composeID).Scan(&subscribed)
+	if err != nil {
+		return nil, fmt.Errorf("error querying subscription of compose %s: %v", composeID, err)
+	}
+	if !subscribed {
+		return nil, notification.ErrNotSubscribed
+	}
+
+	rows, err := conn.Query(context.Background(), sqlQueryDeliveries, composeID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying deliveries of compose %s: %v", composeID, err)
+	}
+	defer rows.Close()
+
+	var deliveries []notification.Delivery
+	for rows.Next() {
+		var event notification.Event
+		var delivery notification.Delivery
+		var lastAttempt *time.Time
+		err = rows.Scan(&event, &delivery.URL, &delivery.Attempts, &delivery.Delivered, &lastAttempt)
+		if err != nil {
+			return nil, fmt.Errorf("error reading delivery: %v", err)
+		}
+		delivery.EventID = event.ID
+		delivery.Status = event.Status
+		if lastAttempt != nil {
+			delivery.LastAttempt = lastAttempt.UTC()
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("error reading deliveries: %v", rows.Err())
+	}
+	return deliveries, nil
+}
+
+func parseJobIds(ids []string) ([]uuid.UUID, error) {
+	jobs := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		job, err := uuid.Parse(id)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing job id %s: %v", id, err)
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
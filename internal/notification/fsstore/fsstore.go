@@ -0,0 +1,352 @@
+// Package fsstore implements a notification.Store backed by the file system.
+//
+// Each subscription is stored in its own document, using the `jsondb`
+// package, together with its deliveries. Like fsjobqueue, it keeps indexes in
+// memory, so access to a given directory must be exclusive to only one store
+// at a time.
+package fsstore
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/osbuild/osbuild-composer/internal/jsondb"
+	"github.com/osbuild/osbuild-composer/internal/notification"
+)
+
+type fsStore struct {
+	// Protects all fields of this struct and ensures that changes to
+	// documents in `db` are atomic.
+	mu sync.Mutex
+
+	db *jsondb.JSONDatabase
+
+	// Maps job ids to the composes whose subscriptions contain them
+	composesByJob map[uuid.UUID]map[uuid.UUID]struct{}
+
+	// Deliveries which neither succeeded nor were given up
+	pending map[uuid.UUID]*pendingDelivery
+}
+
+// pendingDelivery is the in-memory index entry of a delivery
+type pendingDelivery struct {
+	composeID   uuid.UUID
+	index       int
+	url         string
+	nextAttempt time.Time
+}
+
+// subscription is the document of a subscribed compose
+type subscription struct {
+	notification.Subscription
+	LastStatus notification.Status `json:"last_status,omitempty"`
+	Deliveries []delivery          `json:"deliveries,omitempty"`
+}
+
+type delivery struct {
+	ID          uuid.UUID            `json:"id"`
+	Event       notification.Event   `json:"event"`
+	Webhook     notification.Webhook `json:"webhook"`
+	Attempts    int                  `json:"attempts"`
+	Delivered   bool                 `json:"delivered"`
+	LastAttempt time.Time            `json:"last_attempt,omitempty"`
+
+	// Zero when the delivery succeeded or was given up
+	NextAttempt time.Time `json:"next_attempt,omitempty"`
+}
+
+// New creates a store in `dir`, which must exist. Subscriptions which are
+// already in `dir` are loaded.
+func New(dir string) (*fsStore, error) {
+	s := &fsStore{
+		db:            jsondb.New(dir, 0600),
+		composesByJob: make(map[uuid.UUID]map[uuid.UUID]struct{}),
+		pending:       make(map[uuid.UUID]*pendingDelivery),
+	}
+
+	names, err := s.db.List()
+	if err != nil {
+		return nil, fmt.Errorf("error listing subscriptions: %v", err)
+	}
+	for _, name := range names {
+		var sub subscription
+		exists, err := s.db.Read(name, &sub)
+		if err != nil || !exists {
+			return nil, fmt.Errorf("error reading subscription '%s': %v", name, err)
+		}
+		s.index(&sub)
+	}
+
+	return s, nil
+}
+
+func (s *fsStore) Subscribe(sub notification.Subscription) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	exists, err := s.db.Read(sub.ComposeID.String(), nil)
+	if err != nil {
+		return fmt.Errorf("error reading subscription of compose %s: %v", sub.ComposeID, err)
+	}
+	if exists {
+		return notification.ErrAlreadySubscribed
+	}
+
+	doc := &subscription{Subscription: sub}
+	err = s.db.Write(sub.ComposeID.String(), doc)
+	if err != nil {
+		return fmt.Errorf("error writing subscription of compose %s: %v", sub.ComposeID, err)
+	}
+	s.index(doc)
+	return nil
+}
+
+func (s *fsStore) Unsubscribe(composeID uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	doc, err := s.read(composeID)
+	if err == notification.ErrNotSubscribed {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	err = s.db.Delete(composeID.String())
+	if err != nil {
+		return fmt.Errorf("error deleting subscription of compose %s: %v", composeID, err)
+	}
+	s.unindex(doc)
+	return nil
+}
+
+func (s *fsStore) Subscription(composeID uuid.UUID) (*notification.Subscription, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	doc, err := s.read(composeID)
+	if err != nil {
+		return nil, err
+	}
+	return &doc.Subscription, nil
+}
+
+func (s *fsStore) SubscriptionsOfJob(job uuid.UUID) ([]notification.Subscription, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var subs []notification.Subscription
+	for composeID := range s.composesByJob[job] {
+		doc, err := s.read(composeID)
+		if err != nil {
+			return nil, err
+		}
+		subs = append(subs, doc.Subscription)
+	}
+	return subs, nil
+}
+
+func (s *fsStore) AddEvent(event notification.Event, webhooks []notification.Webhook) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	doc, err := s.read(event.ComposeID)
+	if err != nil {
+		return false, err
+	}
+	if doc.LastStatus == event.Status {
+		return false, nil
+	}
+
+	doc.LastStatus = event.Status
+	for _, webhook := range webhooks {
+		doc.Deliveries = append(doc.Deliveries, delivery{
+			ID:          uuid.New(),
+			Event:       event,
+			Webhook:     webhook,
+			NextAttempt: event.Time,
+		})
+	}
+
+	err = s.write(doc)
+	if err != nil {
+		return false, err
+	}
+	s.index(doc)
+	return true, nil
+}
+
+func (s *fsStore) ClaimDelivery(now time.Time, lease time.Duration) (*notification.PendingDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var id uuid.UUID
+	var claimed *pendingDelivery
+	for pid, p := range s.pending {
+		if p.nextAttempt.After(now) || s.waiting(p) {
+			continue
+		}
+		if claimed == nil || p.nextAttempt.Before(claimed.nextAttempt) {
+			id, claimed = pid, p
+		}
+	}
+	if claimed == nil {
+		return nil, nil
+	}
+
+	doc, err := s.read(claimed.composeID)
+	if err != nil {
+		return nil, err
+	}
+	d := &doc.Deliveries[claimed.index]
+	d.Attempts++
+	d.LastAttempt = now.UTC()
+	d.NextAttempt = now.Add(lease).UTC()
+
+	err = s.write(doc)
+	if err != nil {
+		return nil, err
+	}
+	claimed.nextAttempt = d.NextAttempt
+
+	return &notification.PendingDelivery{
+		ID:       id,
+		Event:    d.Event,
+		Webhook:  d.Webhook,
+		Attempts: d.Attempts,
+	}, nil
+}
+
+func (s *fsStore) FinishAttempt(id uuid.UUID, delivered bool, retryAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.pending[id]
+	if !ok {
+		return nil
+	}
+
+	doc, err := s.read(p.composeID)
+	if err != nil {
+		return err
+	}
+	d := &doc.Deliveries[p.index]
+	d.Delivered = delivered
+	if delivered {
+		d.NextAttempt = time.Time{}
+	} else {
+		d.NextAttempt = retryAt.UTC()
+	}
+
+	err = s.write(doc)
+	if err != nil {
+		return err
+	}
+	if d.NextAttempt.IsZero() {
+		delete(s.pending, id)
+	} else {
+		p.nextAttempt = d.NextAttempt
+	}
+	return nil
+}
+
+func (s *fsStore) Deliveries(composeID uuid.UUID) ([]notification.Delivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	doc, err := s.read(composeID)
+	if err != nil {
+		return nil, err
+	}
+
+	var deliveries []notification.Delivery
+	for _, d := range doc.Deliveries {
+		if !d.NextAttempt.IsZero() {
+			continue
+		}
+		deliveries = append(deliveries, notification.Delivery{
+			EventID:     d.Event.ID,
+			Status:      d.Event.Status,
+			URL:         d.Webhook.URL,
+			Attempts:    d.Attempts,
+			Delivered:   d.Delivered,
+			LastAttempt: d.LastAttempt,
+		})
+	}
+	return deliveries, nil
+}
+
+// waiting returns true if an earlier delivery of the same compose to the
+// same URL is pending. Must be called with s.mu held.
+func (s *fsStore) waiting(p *pendingDelivery) bool {
+	for _, other := range s.pending {
+		if other.composeID == p.composeID && other.url == p.url && other.index < p.index {
+			return true
+		}
+	}
+	return false
+}
+
+// Reads the document of a compose. Must be called with s.mu held.
+func (s *fsStore) read(composeID uuid.UUID) (*subscription, error) {
+	var doc subscription
+	exists, err := s.db.Read(composeID.String(), &doc)
+	if err != nil {
+		return nil, fmt.Errorf("error reading subscription of compose %s: %v", composeID, err)
+	}
+	if !exists {
+		return nil, notification.ErrNotSubscribed
+	}
+	return &doc, nil
+}
+
+// Writes the document of a compose. Must be called with s.mu held.
+func (s *fsStore) write(doc *subscription) error {
+	err := s.db.Write(doc.ComposeID.String(), doc)
+	if err != nil {
+		return fmt.Errorf("error writing subscription of compose %s: %v", doc.ComposeID, err)
+	}
+	return nil
+}
+
+// Adds a subscription and its pending deliveries to the in-memory indexes.
+// Must be called with s.mu held.
+func (s *fsStore) index(doc *subscription) {
+	for _, job := range doc.Jobs {
+		composes, ok := s.composesByJob[job]
+		if !ok {
+			composes = make(map[uuid.UUID]struct{})
+			s.composesByJob[job] = composes
+		}
+		composes[doc.ComposeID] = struct{}{}
+	}
+
+	for i, d := range doc.Deliveries {
+		if d.NextAttempt.IsZero() {
+			continue
+		}
+		s.pending[d.ID] = &pendingDelivery{
+			composeID:   doc.ComposeID,
+			index:       i,
+			url:         d.Webhook.URL,
+			nextAttempt: d.NextAttempt,
+		}
+	}
+}
+
+// Removes a subscription and its deliveries from the in-memory indexes. Must
+// be called with s.mu held.
+func (s *fsStore) unindex(doc *subscription) {
+	for _, job := range doc.Jobs {
+		delete(s.composesByJob[job], doc.ComposeID)
+		if len(s.composesByJob[job]) == 0 {
+			delete(s.composesByJob, job)
+		}
+	}
+	for _, d := range doc.Deliveries {
+		delete(s.pending, d.ID)
+	}
+}
@@ -0,0 +1,70 @@
+package fsstore_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/osbuild-composer/internal/notification"
+	"github.com/osbuild/osbuild-composer/internal/notification/fsstore"
+	"github.com/osbuild/osbuild-composer/internal/notification/storetest"
+)
+
+func TestStoreInterface(t *testing.T) {
+	storetest.TestStore(t, func() (notification.Store, func(), error) {
+		dir, err := ioutil.TempDir("", "notification-test-")
+		if err != nil {
+			return nil, nil, err
+		}
+		s, err := fsstore.New(dir)
+		if err != nil {
+			return nil, nil, err
+		}
+		stop := func() {
+			_ = os.RemoveAll(dir)
+		}
+		return s, stop, nil
+	})
+}
+
+func TestNonExistant(t *testing.T) {
+	s, err := fsstore.New("/non-existant-directory")
+	require.Error(t, err)
+	require.Nil(t, s)
+}
+
+func TestIndexAfterRestart(t *testing.T) {
+	dir := t.TempDir()
+	s, err := fsstore.New(dir)
+	require.NoError(t, err)
+
+	job := uuid.New()
+	sub := notification.Subscription{
+		ComposeID: uuid.New(),
+		RootJob:   job,
+		Jobs:      []uuid.UUID{job},
+	}
+	require.NoError(t, s.Subscribe(sub))
+	now := time.Now()
+	_, err = s.AddEvent(notification.Event{ID: uuid.New(), ComposeID: sub.ComposeID, Status: notification.StatusPending, Time: now},
+		[]notification.Webhook{{URL: "https://example.com/hook"}})
+	require.NoError(t, err)
+
+	// subscriptions and pending deliveries are found by a new store
+	s, err = fsstore.New(dir)
+	require.NoError(t, err)
+
+	subs, err := s.SubscriptionsOfJob(job)
+	require.NoError(t, err)
+	require.Len(t, subs, 1)
+	require.Equal(t, sub.ComposeID, subs[0].ComposeID)
+
+	d, err := s.ClaimDelivery(now, time.Minute)
+	require.NoError(t, err)
+	require.NotNil(t, d)
+	require.Equal(t, "https://example.com/hook", d.Webhook.URL)
+}
@@ -0,0 +1,392 @@
+// Package notification delivers webhooks about state changes of composes.
+//
+// When the status of a compose changes, a JSON Event is POSTed to each of
+// its webhooks and to the global ones. A bounded pool of senders makes the
+// deliveries in the background. Failed deliveries are rescheduled with an
+// exponential backoff, rather than waited for.
+//
+// Webhooks with a secret are signed: the X-Composer-Signature header contains
+// "sha256=" followed by the hex-encoded HMAC-SHA256 of the body, keyed with
+// the secret.
+//
+// Webhooks are given by tenants, so by default they're only sent to public
+// addresses. The address is checked when connecting, after the host name has
+// been resolved, and for each redirect.
+//
+// Subscriptions, events and deliveries are kept in a Store, which is shared
+// by all composer instances. See the fsstore and dbstore packages.
+package notification
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// SignatureHeader is the header containing the signature of signed events.
+const SignatureHeader = "X-Composer-Signature"
+
+var ErrNotSubscribed = errors.New("compose is not subscribed")
+var ErrAddressNotAllowed = errors.New("webhooks can only be sent to public addresses")
+
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusBuilding  Status = "building"
+	StatusUploading Status = "uploading"
+	StatusSuccess   Status = "success"
+	StatusFailure   Status = "failure"
+)
+
+// Webhook is a URL which is notified about composes. Events sent to it are
+// signed if Secret is not empty.
+type Webhook struct {
+	URL    string `json:"url"`
+	Secret string `json:"secret,omitempty"`
+}
+
+// Validate returns an error if the webhook's URL isn't an absolute http or
+// https URL.
+func (w Webhook) Validate() error {
+	u, err := url.Parse(w.URL)
+	if err != nil {
+		return fmt.Errorf("invalid webhook URL: %v", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("webhook URL must be an absolute http or https URL: %s", w.URL)
+	}
+	return nil
+}
+
+// Event is the body of a webhook request.
+type Event struct {
+	ID        uuid.UUID `json:"id"`
+	ComposeID uuid.UUID `json:"compose_id"`
+	Status    Status    `json:"status"`
+	Time      time.Time `json:"time"`
+}
+
+// Delivery is the outcome of sending an event to one webhook. The reasons of
+// failed attempts are only logged, because the responses of the webhook's
+// host aren't meant for the tenant who configured it.
+type Delivery struct {
+	EventID     uuid.UUID `json:"event_id"`
+	Status      Status    `json:"status"`
+	URL         string    `json:"url"`
+	Attempts    int       `json:"attempts"`
+	Delivered   bool      `json:"delivered"`
+	LastAttempt time.Time `json:"last_attempt"`
+}
+
+// Signature returns the value of SignatureHeader for `body`.
+func Signature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature returns true if `signature` is the signature of `body`.
+func VerifySignature(secret string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Signature(secret, body)), []byte(signature))
+}
+
+type Config struct {
+	// Webhooks are notified about all subscribed composes.
+	Webhooks []Webhook
+	// MaxAttempts is the number of times an event is sent to a webhook
+	// before giving up. Defaults to 5.
+	MaxAttempts int
+	// RetryInterval is the time to wait before the first retry. It
+	// doubles for each further retry. Defaults to 10 seconds.
+	RetryInterval time.Duration
+	// Client sends the requests. Defaults to a client with a timeout of
+	// 10 seconds, which refuses to connect to addresses that aren't
+	// public.
+	Client *http.Client
+	// Senders is the number of deliveries which are attempted at the
+	// same time. Defaults to 4.
+	Senders int
+	// PollInterval is how often the store is checked for deliveries which
+	// became due, such as retries or events added by other composer
+	// instances. Defaults to 5 seconds.
+	PollInterval time.Duration
+}
+
+// attemptLease is the time after which an attempt which wasn't finished,
+// because the composer instance making it stopped, is made again. It is
+// longer than the timeout of the default client.
+const attemptLease = time.Minute
+
+type Notifier struct {
+	config Config
+	store  Store
+	cancel context.CancelFunc
+
+	// woken is closed to wake up the senders when deliveries became due.
+	wokenMu sync.Mutex
+	woken   chan struct{}
+}
+
+// New returns a notifier which keeps its state in `store` and starts
+// config.Senders goroutines delivering events. Call Close() to stop them.
+func New(config Config, store Store) (*Notifier, error) {
+	for _, w := range config.Webhooks {
+		if err := w.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = 5
+	}
+	if config.RetryInterval <= 0 {
+		config.RetryInterval = 10 * time.Second
+	}
+	if config.Client == nil {
+		config.Client = NewPublicClient(10 * time.Second)
+	}
+	if config.Senders <= 0 {
+		config.Senders = 4
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = 5 * time.Second
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	n := &Notifier{
+		config: config,
+		store:  store,
+		cancel: cancel,
+		woken:  make(chan struct{}),
+	}
+	for i := 0; i < config.Senders; i++ {
+		go n.send(ctx)
+	}
+	return n, nil
+}
+
+// Close stops the senders. Attempts which are in progress are made again
+// by another composer instance once their lease has expired.
+func (n *Notifier) Close() {
+	n.cancel()
+}
+
+// Webhooks returns the global webhooks.
+func (n *Notifier) Webhooks() []Webhook {
+	return n.config.Webhooks
+}
+
+func (n *Notifier) Subscribe(subscription Subscription) error {
+	return n.store.Subscribe(subscription)
+}
+
+func (n *Notifier) Unsubscribe(composeID uuid.UUID) error {
+	return n.store.Unsubscribe(composeID)
+}
+
+func (n *Notifier) Subscription(composeID uuid.UUID) (*Subscription, error) {
+	return n.store.Subscription(composeID)
+}
+
+func (n *Notifier) SubscriptionsOfJob(job uuid.UUID) ([]Subscription, error) {
+	return n.store.SubscriptionsOfJob(job)
+}
+
+func (n *Notifier) Deliveries(composeID uuid.UUID) ([]Delivery, error) {
+	return n.store.Deliveries(composeID)
+}
+
+// Notify adds an event with `status` to `subscription`, unless its last event
+// had the same status. The event is sent to the global webhooks and to those
+// of the subscription in the background.
+func (n *Notifier) Notify(subscription *Subscription, status Status) error {
+	event := Event{
+		ID:        uuid.New(),
+		ComposeID: subscription.ComposeID,
+		Status:    status,
+		Time:      time.Now().UTC(),
+	}
+	webhooks := append(append([]Webhook{}, n.config.Webhooks...), subscription.Webhooks...)
+
+	added, err := n.store.AddEvent(event, webhooks)
+	if err != nil {
+		return err
+	}
+	if added {
+		n.wake()
+	}
+	return nil
+}
+
+func (n *Notifier) wake() {
+	n.wokenMu.Lock()
+	defer n.wokenMu.Unlock()
+	close(n.woken)
+	n.woken = make(chan struct{})
+}
+
+func (n *Notifier) wakeup() <-chan struct{} {
+	n.wokenMu.Lock()
+	defer n.wokenMu.Unlock()
+	return n.woken
+}
+
+// send attempts the deliveries which are due, one at a time, and waits for
+// more when there are none.
+func (n *Notifier) send(ctx context.Context) {
+	for {
+		// Get the channel before claiming, so that deliveries added in
+		// between aren't missed.
+		woken := n.wakeup()
+
+		delivery, err := n.store.ClaimDelivery(time.Now(), attemptLease)
+		if err != nil {
+			logrus.Errorf("error claiming a webhook delivery: %v", err)
+		} else if delivery != nil {
+			n.attempt(delivery)
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-woken:
+		case <-time.After(n.config.PollInterval):
+		}
+	}
+}
+
+// attempt makes one attempt of `delivery`. A failed attempt is retried after
+// RetryInterval, which doubles with each further attempt, until MaxAttempts
+// is reached.
+func (n *Notifier) attempt(delivery *PendingDelivery) {
+	event := delivery.Event
+	err := n.post(delivery.Webhook, event)
+	if err == nil {
+		err = n.store.FinishAttempt(delivery.ID, true, time.Time{})
+		if err != nil {
+			logrus.Errorf("error finishing delivery %s of compose %s: %v", delivery.ID, event.ComposeID, err)
+		}
+		return
+	}
+	logrus.Warnf("attempt %d of delivering event %s of compose %s to %s failed: %v",
+		delivery.Attempts, event.ID, event.ComposeID, delivery.Webhook.URL, err)
+
+	var retryAt time.Time
+	if delivery.Attempts < n.config.MaxAttempts {
+		retryAt = time.Now().Add(n.config.RetryInterval << (delivery.Attempts - 1))
+	}
+	err = n.store.FinishAttempt(delivery.ID, false, retryAt)
+	if err != nil {
+		logrus.Errorf("error finishing delivery %s of compose %s: %v", delivery.ID, event.ComposeID, err)
+	}
+}
+
+func (n *Notifier) post(webhook Webhook, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if webhook.Secret != "" {
+		request.Header.Set(SignatureHeader, Signature(webhook.Secret, body))
+	}
+
+	response, err := n.config.Client.Do(request)
+	if err != nil {
+		return err
+	}
+	response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("unexpected response: %s", response.Status)
+	}
+	return nil
+}
+
+// NewPublicClient returns a client which only connects to public addresses.
+// It doesn't use proxies, because they would connect on its behalf.
+func NewPublicClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: checkPublicAddress,
+	}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+		},
+	}
+}
+
+// privateNetworks are the ranges which aren't covered by the methods of
+// net.IP, mostly those of RFC 1918 and RFC 4193.
+var privateNetworks = mustParseCIDRs(
+	"0.0.0.0/8",
+	"10.0.0.0/8",
+	"100.64.0.0/10",
+	"172.16.0.0/12",
+	"192.168.0.0/16",
+	"fc00::/7",
+)
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	var networks []*net.IPNet
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
+
+// IsPublic returns false for loopback, link-local, private and other
+// addresses which don't belong to hosts on the internet.
+func IsPublic(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsUnspecified() || ip.IsMulticast() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() {
+		return false
+	}
+	for _, network := range privateNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkPublicAddress is a net.Dialer.Control function. It is called with the
+// resolved address, right before connecting to it.
+func checkPublicAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !IsPublic(ip) {
+		return fmt.Errorf("%w: %s", ErrAddressNotAllowed, host)
+	}
+	return nil
+}
@@ -0,0 +1,281 @@
+package notification_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/osbuild-composer/internal/notification"
+	"github.com/osbuild/osbuild-composer/internal/notification/fsstore"
+)
+
+// receiver is a webhook which records the events it receives. It fails the
+// first `failures` requests. Requests block while `blocked` is open.
+type receiver struct {
+	t        *testing.T
+	secret   string
+	failures int
+	blocked  chan struct{}
+
+	mu     sync.Mutex
+	events []notification.Event
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, request *http.Request) {
+	body, err := ioutil.ReadAll(request.Body)
+	require.NoError(r.t, err)
+
+	if r.blocked != nil {
+		<-r.blocked
+	}
+
+	if r.secret != "" {
+		require.True(r.t, notification.VerifySignature(r.secret, body, request.Header.Get(notification.SignatureHeader)))
+	} else {
+		require.Empty(r.t, request.Header.Get(notification.SignatureHeader))
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.failures > 0 {
+		r.failures--
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	var event notification.Event
+	require.NoError(r.t, json.Unmarshal(body, &event))
+	r.events = append(r.events, event)
+}
+
+func (r *receiver) statuses() []notification.Status {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var statuses []notification.Status
+	for _, event := range r.events {
+		statuses = append(statuses, event.Status)
+	}
+	return statuses
+}
+
+func TestSignature(t *testing.T) {
+	body := []byte(`{"status":"success"}`)
+	signature := notification.Signature("secret", body)
+
+	require.Regexp(t, "^sha256=[0-9a-f]{64}$", signature)
+	require.True(t, notification.VerifySignature("secret", body, signature))
+	require.False(t, notification.VerifySignature("other", body, signature))
+	require.False(t, notification.VerifySignature("secret", []byte(`{"status":"failure"}`), signature))
+}
+
+func TestWebhookValidate(t *testing.T) {
+	require.NoError(t, notification.Webhook{URL: "https://example.com/hook"}.Validate())
+	require.NoError(t, notification.Webhook{URL: "http://localhost:8080"}.Validate())
+	require.Error(t, notification.Webhook{URL: ""}.Validate())
+	require.Error(t, notification.Webhook{URL: "/hook"}.Validate())
+	require.Error(t, notification.Webhook{URL: "ftp://example.com/hook"}.Validate())
+
+	_, err := notification.New(notification.Config{Webhooks: []notification.Webhook{{URL: "example.com"}}}, nil)
+	require.Error(t, err)
+}
+
+// newNotifier returns a notifier with a store in a temporary directory,
+// which retries quickly.
+func newNotifier(t *testing.T, config notification.Config) *notification.Notifier {
+	store, err := fsstore.New(t.TempDir())
+	require.NoError(t, err)
+
+	if config.RetryInterval == 0 {
+		config.RetryInterval = time.Millisecond
+	}
+	if config.Client == nil {
+		config.Client = &http.Client{}
+	}
+	config.PollInterval = 10 * time.Millisecond
+
+	n, err := notification.New(config, store)
+	require.NoError(t, err)
+	t.Cleanup(n.Close)
+	return n
+}
+
+func subscribe(t *testing.T, n *notification.Notifier, webhooks ...notification.Webhook) *notification.Subscription {
+	job := uuid.New()
+	subscription := notification.Subscription{
+		ComposeID: uuid.New(),
+		Channel:   "org-42",
+		RootJob:   job,
+		Jobs:      []uuid.UUID{job},
+		Webhooks:  webhooks,
+	}
+	require.NoError(t, n.Subscribe(subscription))
+	return &subscription
+}
+
+// waitForDeliveries waits until `count` deliveries of a compose succeeded or
+// were given up.
+func waitForDeliveries(t *testing.T, n *notification.Notifier, composeID uuid.UUID, count int) []notification.Delivery {
+	var deliveries []notification.Delivery
+	require.Eventually(t, func() bool {
+		var err error
+		deliveries, err = n.Deliveries(composeID)
+		require.NoError(t, err)
+		return len(deliveries) >= count
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Len(t, deliveries, count)
+	return deliveries
+}
+
+func TestDeliver(t *testing.T) {
+	r := &receiver{t: t, secret: "secret", failures: 1}
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	global := &receiver{t: t}
+	globalServer := httptest.NewServer(global)
+	defer globalServer.Close()
+
+	n := newNotifier(t, notification.Config{Webhooks: []notification.Webhook{{URL: globalServer.URL}}})
+	subscription := subscribe(t, n, notification.Webhook{URL: server.URL, Secret: "secret"})
+
+	// the first attempt fails
+	require.NoError(t, n.Notify(subscription, notification.StatusPending))
+	deliveries := waitForDeliveries(t, n, subscription.ComposeID, 2)
+
+	byURL := map[string]notification.Delivery{}
+	for _, delivery := range deliveries {
+		require.True(t, delivery.Delivered)
+		require.Equal(t, notification.StatusPending, delivery.Status)
+		require.False(t, delivery.LastAttempt.IsZero())
+		byURL[delivery.URL] = delivery
+	}
+	require.Equal(t, 1, byURL[globalServer.URL].Attempts)
+	require.Equal(t, 2, byURL[server.URL].Attempts)
+
+	require.Len(t, r.events, 1)
+	require.Equal(t, byURL[server.URL].EventID, r.events[0].ID)
+	require.Equal(t, subscription.ComposeID, r.events[0].ComposeID)
+	require.Equal(t, []notification.Status{notification.StatusPending}, global.statuses())
+
+	// the status didn't change
+	require.NoError(t, n.Notify(subscription, notification.StatusPending))
+	time.Sleep(50 * time.Millisecond)
+	deliveries, err := n.Deliveries(subscription.ComposeID)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 2)
+}
+
+func TestOrder(t *testing.T) {
+	r := &receiver{t: t, failures: 2}
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	n := newNotifier(t, notification.Config{})
+	subscription := subscribe(t, n, notification.Webhook{URL: server.URL})
+
+	// the second event waits for the first one to be delivered
+	require.NoError(t, n.Notify(subscription, notification.StatusPending))
+	require.NoError(t, n.Notify(subscription, notification.StatusBuilding))
+	require.NoError(t, n.Notify(subscription, notification.StatusSuccess))
+
+	deliveries := waitForDeliveries(t, n, subscription.ComposeID, 3)
+	require.Equal(t, 3, deliveries[0].Attempts)
+	require.Equal(t, []notification.Status{notification.StatusPending, notification.StatusBuilding, notification.StatusSuccess}, r.statuses())
+}
+
+func TestConcurrentDeliveries(t *testing.T) {
+	slow := &receiver{t: t, blocked: make(chan struct{})}
+	slowServer := httptest.NewServer(slow)
+	defer slowServer.Close()
+
+	r := &receiver{t: t}
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	n := newNotifier(t, notification.Config{Senders: 2})
+	blocked := subscribe(t, n, notification.Webhook{URL: slowServer.URL})
+	subscription := subscribe(t, n, notification.Webhook{URL: server.URL})
+
+	// a webhook which doesn't respond doesn't hold up the others
+	require.NoError(t, n.Notify(blocked, notification.StatusPending))
+	require.NoError(t, n.Notify(subscription, notification.StatusPending))
+	waitForDeliveries(t, n, subscription.ComposeID, 1)
+
+	deliveries, err := n.Deliveries(blocked.ComposeID)
+	require.NoError(t, err)
+	require.Empty(t, deliveries)
+
+	close(slow.blocked)
+	waitForDeliveries(t, n, blocked.ComposeID, 1)
+}
+
+func TestGiveUp(t *testing.T) {
+	r := &receiver{t: t, failures: 100}
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	n := newNotifier(t, notification.Config{MaxAttempts: 3})
+	subscription := subscribe(t, n, notification.Webhook{URL: server.URL})
+
+	require.NoError(t, n.Notify(subscription, notification.StatusFailure))
+	deliveries := waitForDeliveries(t, n, subscription.ComposeID, 1)
+	require.False(t, deliveries[0].Delivered)
+	require.Equal(t, 3, deliveries[0].Attempts)
+	require.Empty(t, r.statuses())
+}
+
+func TestUnsubscribe(t *testing.T) {
+	n := newNotifier(t, notification.Config{})
+	subscription := subscribe(t, n, notification.Webhook{URL: "https://example.com/hook"})
+	require.Equal(t, notification.ErrAlreadySubscribed, n.Subscribe(*subscription))
+
+	require.NoError(t, n.Unsubscribe(subscription.ComposeID))
+	_, err := n.Subscription(subscription.ComposeID)
+	require.Equal(t, notification.ErrNotSubscribed, err)
+	require.Equal(t, notification.ErrNotSubscribed, n.Notify(subscription, notification.StatusPending))
+}
+
+func TestIsPublic(t *testing.T) {
+	for _, ip := range []string{"1.1.1.1", "8.8.8.8", "2001:4860:4860::8888"} {
+		require.True(t, notification.IsPublic(net.ParseIP(ip)), ip)
+	}
+	for _, ip := range []string{
+		"127.0.0.1", "::1", "0.0.0.0", "::", "10.1.2.3", "172.16.0.1", "192.168.1.1",
+		"100.64.0.1", "169.254.169.254", "fe80::1", "fd00::1", "224.0.0.1", "::ffff:127.0.0.1",
+	} {
+		require.False(t, notification.IsPublic(net.ParseIP(ip)), ip)
+	}
+}
+
+func TestPrivateAddress(t *testing.T) {
+	r := &receiver{t: t}
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	// the default client refuses to connect to the loopback address
+	store, err := fsstore.New(t.TempDir())
+	require.NoError(t, err)
+	n, err := notification.New(notification.Config{MaxAttempts: 1, PollInterval: 10 * time.Millisecond}, store)
+	require.NoError(t, err)
+	defer n.Close()
+
+	subscription := subscribe(t, n, notification.Webhook{URL: server.URL})
+	require.NoError(t, n.Notify(subscription, notification.StatusPending))
+	deliveries := waitForDeliveries(t, n, subscription.ComposeID, 1)
+	require.False(t, deliveries[0].Delivered)
+	require.Empty(t, r.statuses())
+
+	_, err = notification.NewPublicClient(time.Second).Get(server.URL)
+	require.True(t, errors.Is(err, notification.ErrAddressNotAllowed))
+}
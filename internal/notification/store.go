@@ -0,0 +1,78 @@
+package notification
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrAlreadySubscribed = errors.New("compose is subscribed already")
+
+// Subscription is a compose whose status changes are sent to webhooks.
+type Subscription struct {
+	ComposeID uuid.UUID `json:"compose_id"`
+	// Channel is the channel the compose's jobs were enqueued on, i.e.,
+	// the tenant it belongs to.
+	Channel string `json:"channel"`
+	// RootJob decides the status of the compose.
+	RootJob uuid.UUID `json:"root_job"`
+	// Jobs are the root job and all jobs it depends on. The status of the
+	// compose can change whenever one of them changes.
+	Jobs []uuid.UUID `json:"jobs"`
+	// Webhooks are notified in addition to the global ones.
+	Webhooks []Webhook `json:"webhooks,omitempty"`
+}
+
+// PendingDelivery is the delivery of an event to a webhook, which didn't
+// succeed yet.
+type PendingDelivery struct {
+	ID      uuid.UUID
+	Event   Event
+	Webhook Webhook
+	// Attempts counts the attempts so far, including the current one.
+	Attempts int
+}
+
+// Store keeps the subscriptions, their events, and the deliveries of those
+// events. Composer instances which share a job queue must share the store as
+// well. It is safe for concurrent use.
+type Store interface {
+	// Adds a subscription. Returns ErrAlreadySubscribed if the compose is
+	// subscribed already.
+	Subscribe(subscription Subscription) error
+
+	// Removes the subscription of a compose, together with its events and
+	// their deliveries. Does nothing if the compose isn't subscribed.
+	Unsubscribe(composeID uuid.UUID) error
+
+	// Returns the subscription of a compose, or ErrNotSubscribed.
+	Subscription(composeID uuid.UUID) (*Subscription, error)
+
+	// Returns the subscriptions whose Jobs contain `job`.
+	SubscriptionsOfJob(job uuid.UUID) ([]Subscription, error)
+
+	// Adds `event` to the subscription of its compose, with a delivery to
+	// each of `webhooks`, which are due right away. Does nothing and
+	// returns false if the last event of the compose has the same status.
+	// Returns ErrNotSubscribed if the compose isn't subscribed.
+	AddEvent(event Event, webhooks []Webhook) (bool, error)
+
+	// Claims the delivery which has been due for the longest time at
+	// `now`. Deliveries to a webhook are made in the order of the events,
+	// so a delivery isn't due while an earlier one to the same webhook
+	// is pending. The attempt is counted and the next one postponed by
+	// `lease`, so that it is retried if the process which claimed it
+	// stops. Returns nil if no delivery is due.
+	ClaimDelivery(now time.Time, lease time.Duration) (*PendingDelivery, error)
+
+	// Records the outcome of an attempt of delivery `id`. If it failed,
+	// the delivery is attempted again at `retryAt`, or given up if that
+	// is the zero time. Deliveries which don't exist anymore are ignored.
+	FinishAttempt(id uuid.UUID, delivered bool, retryAt time.Time) error
+
+	// Returns the deliveries of a compose which succeeded or were given
+	// up, in the order of their events. Returns ErrNotSubscribed if the
+	// compose isn't subscribed.
+	Deliveries(composeID uuid.UUID) ([]Delivery, error)
+}
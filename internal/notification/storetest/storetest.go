@@ -0,0 +1,250 @@
+// Package storetest provides test functions to verify a Store implementation
+// satisfies the interface in package notification.
+
+package storetest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/osbuild-composer/internal/notification"
+)
+
+type MakeStore func() (s notification.Store, stop func(), err error)
+
+func TestStore(t *testing.T, makeStore MakeStore) {
+	wrap := func(f func(t *testing.T, s notification.Store)) func(*testing.T) {
+		s, stop, err := makeStore()
+		require.NoError(t, err)
+		return func(t *testing.T) {
+			defer stop() // use defer because f() might call testing.T.FailNow()
+			f(t, s)
+		}
+	}
+
+	t.Run("subscriptions", wrap(testSubscriptions))
+	t.Run("subscriptions-of-job", wrap(testSubscriptionsOfJob))
+	t.Run("events", wrap(testEvents))
+	t.Run("claim", wrap(testClaim))
+	t.Run("order", wrap(testOrder))
+	t.Run("unsubscribe", wrap(testUnsubscribe))
+}
+
+func subscribe(t *testing.T, s notification.Store, jobs ...uuid.UUID) notification.Subscription {
+	t.Helper()
+	sub := notification.Subscription{
+		ComposeID: uuid.New(),
+		Channel:   "org-42",
+		RootJob:   jobs[0],
+		Jobs:      jobs,
+		Webhooks:  []notification.Webhook{{URL: "https://example.com/hook", Secret: "secret"}},
+	}
+	require.NoError(t, s.Subscribe(sub))
+	return sub
+}
+
+func event(sub notification.Subscription, status notification.Status, at time.Time) notification.Event {
+	return notification.Event{
+		ID:        uuid.New(),
+		ComposeID: sub.ComposeID,
+		Status:    status,
+		Time:      at.UTC(),
+	}
+}
+
+func testSubscriptions(t *testing.T, s notification.Store) {
+	_, err := s.Subscription(uuid.New())
+	require.Equal(t, notification.ErrNotSubscribed, err)
+	_, err = s.Deliveries(uuid.New())
+	require.Equal(t, notification.ErrNotSubscribed, err)
+
+	sub := subscribe(t, s, uuid.New(), uuid.New())
+	require.Equal(t, notification.ErrAlreadySubscribed, s.Subscribe(sub))
+
+	stored, err := s.Subscription(sub.ComposeID)
+	require.NoError(t, err)
+	require.Equal(t, sub.ComposeID, stored.ComposeID)
+	require.Equal(t, sub.Channel, stored.Channel)
+	require.Equal(t, sub.RootJob, stored.RootJob)
+	require.ElementsMatch(t, sub.Jobs, stored.Jobs)
+	require.Equal(t, sub.Webhooks, stored.Webhooks)
+
+	deliveries, err := s.Deliveries(sub.ComposeID)
+	require.NoError(t, err)
+	require.Empty(t, deliveries)
+}
+
+func testSubscriptionsOfJob(t *testing.T, s notification.Store) {
+	shared := uuid.New()
+	a := subscribe(t, s, uuid.New(), shared)
+	b := subscribe(t, s, uuid.New(), shared)
+
+	subs, err := s.SubscriptionsOfJob(a.RootJob)
+	require.NoError(t, err)
+	require.Len(t, subs, 1)
+	require.Equal(t, a.ComposeID, subs[0].ComposeID)
+
+	subs, err = s.SubscriptionsOfJob(shared)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []uuid.UUID{a.ComposeID, b.ComposeID}, []uuid.UUID{subs[0].ComposeID, subs[1].ComposeID})
+
+	subs, err = s.SubscriptionsOfJob(uuid.New())
+	require.NoError(t, err)
+	require.Empty(t, subs)
+}
+
+func testEvents(t *testing.T, s notification.Store) {
+	now := time.Now()
+	_, err := s.AddEvent(notification.Event{ID: uuid.New(), ComposeID: uuid.New(), Status: notification.StatusPending, Time: now}, nil)
+	require.Equal(t, notification.ErrNotSubscribed, err)
+
+	sub := subscribe(t, s, uuid.New())
+	webhooks := []notification.Webhook{{URL: "https://example.com/a"}, {URL: "https://example.com/b"}}
+
+	added, err := s.AddEvent(event(sub, notification.StatusPending, now), webhooks)
+	require.NoError(t, err)
+	require.True(t, added)
+
+	// the status didn't change
+	added, err = s.AddEvent(event(sub, notification.StatusPending, now), webhooks)
+	require.NoError(t, err)
+	require.False(t, added)
+
+	added, err = s.AddEvent(event(sub, notification.StatusBuilding, now), webhooks)
+	require.NoError(t, err)
+	require.True(t, added)
+}
+
+func testClaim(t *testing.T, s notification.Store) {
+	now := time.Now()
+	sub := subscribe(t, s, uuid.New())
+	pending := event(sub, notification.StatusPending, now)
+	_, err := s.AddEvent(pending, []notification.Webhook{{URL: "https://example.com/a"}, {URL: "https://example.com/b", Secret: "secret"}})
+	require.NoError(t, err)
+
+	// not due yet
+	d, err := s.ClaimDelivery(now.Add(-time.Second), time.Minute)
+	require.NoError(t, err)
+	require.Nil(t, d)
+
+	first, err := s.ClaimDelivery(now, time.Minute)
+	require.NoError(t, err)
+	require.NotNil(t, first)
+	require.Equal(t, pending.ID, first.Event.ID)
+	require.Equal(t, notification.StatusPending, first.Event.Status)
+	require.Equal(t, 1, first.Attempts)
+
+	second, err := s.ClaimDelivery(now, time.Hour)
+	require.NoError(t, err)
+	require.NotNil(t, second)
+	require.NotEqual(t, first.ID, second.ID)
+	require.ElementsMatch(t, []string{"https://example.com/a", "https://example.com/b"}, []string{first.Webhook.URL, second.Webhook.URL})
+
+	// both are claimed
+	d, err = s.ClaimDelivery(now, time.Minute)
+	require.NoError(t, err)
+	require.Nil(t, d)
+
+	// the lease of an unfinished attempt expires
+	d, err = s.ClaimDelivery(now.Add(2*time.Minute), time.Minute)
+	require.NoError(t, err)
+	require.NotNil(t, d)
+	require.Equal(t, first.ID, d.ID)
+	require.Equal(t, 2, d.Attempts)
+
+	// the first is delivered, the second retried and then given up
+	require.NoError(t, s.FinishAttempt(first.ID, true, time.Time{}))
+	require.NoError(t, s.FinishAttempt(second.ID, false, now.Add(time.Hour)))
+
+	deliveries, err := s.Deliveries(sub.ComposeID)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	require.Equal(t, first.Webhook.URL, deliveries[0].URL)
+	require.True(t, deliveries[0].Delivered)
+
+	d, err = s.ClaimDelivery(now.Add(2*time.Hour), time.Minute)
+	require.NoError(t, err)
+	require.NotNil(t, d)
+	require.Equal(t, second.ID, d.ID)
+	require.Equal(t, 2, d.Attempts)
+	require.NoError(t, s.FinishAttempt(d.ID, false, time.Time{}))
+
+	d, err = s.ClaimDelivery(now.Add(3*time.Hour), time.Minute)
+	require.NoError(t, err)
+	require.Nil(t, d)
+
+	deliveries, err = s.Deliveries(sub.ComposeID)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 2)
+	require.False(t, deliveries[1].Delivered)
+	require.Equal(t, 2, deliveries[1].Attempts)
+	require.Equal(t, pending.ID, deliveries[1].EventID)
+	require.Equal(t, notification.StatusPending, deliveries[1].Status)
+	require.False(t, deliveries[1].LastAttempt.IsZero())
+
+	// deliveries which don't exist anymore are ignored
+	require.NoError(t, s.FinishAttempt(uuid.New(), true, time.Time{}))
+}
+
+func testOrder(t *testing.T, s notification.Store) {
+	now := time.Now()
+	sub := subscribe(t, s, uuid.New())
+	webhooks := []notification.Webhook{{URL: "https://example.com/hook"}}
+
+	pending := event(sub, notification.StatusPending, now)
+	_, err := s.AddEvent(pending, webhooks)
+	require.NoError(t, err)
+	building := event(sub, notification.StatusBuilding, now)
+	_, err = s.AddEvent(building, webhooks)
+	require.NoError(t, err)
+
+	d, err := s.ClaimDelivery(now, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, pending.ID, d.Event.ID)
+	require.NoError(t, s.FinishAttempt(d.ID, false, now))
+
+	// the second event waits for the first one, even though it is due
+	d, err = s.ClaimDelivery(now, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, pending.ID, d.Event.ID)
+	require.NoError(t, s.FinishAttempt(d.ID, true, time.Time{}))
+
+	d, err = s.ClaimDelivery(now, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, building.ID, d.Event.ID)
+	require.NoError(t, s.FinishAttempt(d.ID, true, time.Time{}))
+
+	deliveries, err := s.Deliveries(sub.ComposeID)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 2)
+	require.Equal(t, pending.ID, deliveries[0].EventID)
+	require.Equal(t, 2, deliveries[0].Attempts)
+	require.Equal(t, building.ID, deliveries[1].EventID)
+}
+
+func testUnsubscribe(t *testing.T, s notification.Store) {
+	now := time.Now()
+	sub := subscribe(t, s, uuid.New())
+	_, err := s.AddEvent(event(sub, notification.StatusPending, now), sub.Webhooks)
+	require.NoError(t, err)
+
+	require.NoError(t, s.Unsubscribe(sub.ComposeID))
+	require.NoError(t, s.Unsubscribe(sub.ComposeID))
+
+	_, err = s.Subscription(sub.ComposeID)
+	require.Equal(t, notification.ErrNotSubscribed, err)
+	subs, err := s.SubscriptionsOfJob(sub.RootJob)
+	require.NoError(t, err)
+	require.Empty(t, subs)
+
+	// its deliveries are gone as well
+	d, err := s.ClaimDelivery(now, time.Minute)
+	require.NoError(t, err)
+	require.Nil(t, d)
+
+	// and it can subscribe again
+	require.NoError(t, s.Subscribe(sub))
+}
@@ -24,14 +24,36 @@ func (URL) isCurlSourceItem() {}
 type URLWithSecrets struct {
 	URL     string      `json:"url"`
 	Secrets *URLSecrets `json:"secrets,omitempty"`
+
+	// Insecure disables the verification of the server's certificate
+	Insecure bool `json:"insecure,omitempty"`
 }
 
 func (URLWithSecrets) isCurlSourceItem() {}
 
+// URLSecrets names the provider of the secrets needed to download an item.
+// The secrets themselves never appear in the manifest: "org.osbuild.rhsm",
+// the only provider, looks up the client certificate for the item's url in
+// the subscription configuration of the host osbuild runs on.
 type URLSecrets struct {
 	Name string `json:"name"`
 }
 
+// NewURLWithSecrets returns the curl source item for downloading `url` with
+// the secrets provider `secrets`, which may be empty.
+func NewURLWithSecrets(url, secrets string, insecure bool) *URLWithSecrets {
+	item := &URLWithSecrets{
+		URL:      url,
+		Insecure: insecure,
+	}
+	if secrets != "" {
+		item.Secrets = &URLSecrets{
+			Name: secrets,
+		}
+	}
+	return item
+}
+
 // Unmarshal method for CurlSource for handling the CurlSourceItem interface:
 // Tries each of the implementations until it finds the one that works.
 func (cs *CurlSource) UnmarshalJSON(data []byte) (err error) {
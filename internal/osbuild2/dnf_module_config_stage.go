@@ -0,0 +1,37 @@
+package osbuild2
+
+// DNFModuleConfigStageOptions represents the state of a DNF module, as
+// recorded in /etc/dnf/modules.d/<name>.module.
+type DNFModuleConfigStageOptions struct {
+	Conf *DNFModuleConfig `json:"conf"`
+}
+
+func (DNFModuleConfigStageOptions) isStageOptions() {}
+
+type DNFModuleConfig struct {
+	Name     string   `json:"name"`
+	Stream   string   `json:"stream,omitempty"`
+	Profiles []string `json:"profiles"`
+	State    string   `json:"state"`
+}
+
+// NewDNFModuleConfigStage creates a new DNFModuleConfig Stage object.
+func NewDNFModuleConfigStage(options *DNFModuleConfigStageOptions) *Stage {
+	return &Stage{
+		Type:    "org.osbuild.dnf.module-config",
+		Options: options,
+	}
+}
+
+// NewDNFModuleEnableStageOptions returns the options which record the stream
+// `stream` of the module `name` as enabled.
+func NewDNFModuleEnableStageOptions(name, stream string) *DNFModuleConfigStageOptions {
+	return &DNFModuleConfigStageOptions{
+		Conf: &DNFModuleConfig{
+			Name:     name,
+			Stream:   stream,
+			Profiles: []string{},
+			State:    "enabled",
+		},
+	}
+}
@@ -0,0 +1,36 @@
+package osbuild2
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDNFModuleConfigStage(t *testing.T) {
+	expectedStage := &Stage{
+		Type: "org.osbuild.dnf.module-config",
+		Options: &DNFModuleConfigStageOptions{
+			Conf: &DNFModuleConfig{
+				Name:     "postgresql",
+				Stream:   "13",
+				Profiles: []string{},
+				State:    "enabled",
+			},
+		},
+	}
+	actualStage := NewDNFModuleConfigStage(NewDNFModuleEnableStageOptions("postgresql", "13"))
+	assert.Equal(t, expectedStage, actualStage)
+}
+
+func TestJSONDNFModuleConfigStage(t *testing.T) {
+	options := NewDNFModuleEnableStageOptions("nodejs", "16")
+	data, err := json.Marshal(options)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"conf":{"name":"nodejs","stream":"16","profiles":[],"state":"enabled"}}`, string(data))
+
+	var actualOptions DNFModuleConfigStageOptions
+	require.NoError(t, json.Unmarshal(data, &actualOptions))
+	assert.Equal(t, options, &actualOptions)
+}
@@ -15,5 +15,33 @@ type OSTreeSourceRemote struct {
 	// URL of the repository.
 	URL string `json:"url"`
 	// GPG keys to verify the commits
-	GPGKeys []string `json:"secrets,omitempty"`
+	GPGKeys []string `json:"gpgkeys,omitempty"`
+	// Secrets provider to access the repository with
+	Secrets *OSTreeSourceSecrets `json:"secrets,omitempty"`
+}
+
+// OSTreeSourceSecrets names the provider of the secrets needed to pull from a
+// repository, like URLSecrets does for curl sources.
+type OSTreeSourceSecrets struct {
+	Name string `json:"name"`
+}
+
+// NewOSTreeSourceItem returns the source item for pulling a commit from the
+// repository at `url`, verified with the armored GPG key `gpgKey` and pulled
+// with the secrets provider `secrets`. Both may be empty.
+func NewOSTreeSourceItem(url, gpgKey, secrets string) *OSTreeSourceItem {
+	item := &OSTreeSourceItem{
+		Remote: OSTreeSourceRemote{
+			URL: url,
+		},
+	}
+	if gpgKey != "" {
+		item.Remote.GPGKeys = []string{gpgKey}
+	}
+	if secrets != "" {
+		item.Remote.Secrets = &OSTreeSourceSecrets{
+			Name: secrets,
+		}
+	}
+	return item
 }
@@ -73,6 +73,32 @@ func TestSource_UnmarshalJSON(t *testing.T) {
 				data: []byte(`{"org.osbuild.curl":{"items":{"checksum1":{"url":"url1","secrets":{"name":"org.osbuild.rhsm"}},"checksum2":{"url":"url2","secrets":{"name":"whatever"}}}}}`),
 			},
 		},
+		{
+			name: "curl-rhsm-insecure",
+			fields: fields{
+				Type: "org.osbuild.curl",
+				Source: &CurlSource{
+					Items: map[string]CurlSourceItem{
+						"checksum1": URLWithSecrets{URL: "url1", Secrets: &URLSecrets{Name: "org.osbuild.rhsm"}, Insecure: true},
+					}},
+			},
+			args: args{
+				data: []byte(`{"org.osbuild.curl":{"items":{"checksum1":{"url":"url1","secrets":{"name":"org.osbuild.rhsm"},"insecure":true}}}}`),
+			},
+		},
+		{
+			name: "ostree-with-gpgkeys-and-secrets",
+			fields: fields{
+				Type: "org.osbuild.ostree",
+				Source: &OSTreeSource{
+					Items: map[string]OSTreeSourceItem{
+						"commit1": {Remote: OSTreeSourceRemote{URL: "url1", GPGKeys: []string{"key1"}, Secrets: &OSTreeSourceSecrets{Name: "org.osbuild.rhsm.consumer"}}},
+					}},
+			},
+			args: args{
+				data: []byte(`{"org.osbuild.ostree":{"items":{"commit1":{"remote":{"url":"url1","gpgkeys":["key1"],"secrets":{"name":"org.osbuild.rhsm.consumer"}}}}}}`),
+			},
+		},
 		{
 			name: "curl-url-only",
 			fields: fields{
@@ -113,3 +139,27 @@ func TestSource_UnmarshalJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestNewURLWithSecrets(t *testing.T) {
+	item := NewURLWithSecrets("url1", "", false)
+	if !reflect.DeepEqual(item, &URLWithSecrets{URL: "url1"}) {
+		t.Errorf("unexpected item without secrets: %#v", item)
+	}
+
+	item = NewURLWithSecrets("url1", "org.osbuild.rhsm", true)
+	if !reflect.DeepEqual(item, &URLWithSecrets{URL: "url1", Secrets: &URLSecrets{Name: "org.osbuild.rhsm"}, Insecure: true}) {
+		t.Errorf("unexpected item with secrets: %#v", item)
+	}
+}
+
+func TestNewOSTreeSourceItem(t *testing.T) {
+	item := NewOSTreeSourceItem("url1", "", "")
+	if !reflect.DeepEqual(item, &OSTreeSourceItem{Remote: OSTreeSourceRemote{URL: "url1"}}) {
+		t.Errorf("unexpected item without key and secrets: %#v", item)
+	}
+
+	item = NewOSTreeSourceItem("url1", "key1", "org.osbuild.rhsm.consumer")
+	if !reflect.DeepEqual(item, &OSTreeSourceItem{Remote: OSTreeSourceRemote{URL: "url1", GPGKeys: []string{"key1"}, Secrets: &OSTreeSourceSecrets{Name: "org.osbuild.rhsm.consumer"}}}) {
+		t.Errorf("unexpected item with key and secrets: %#v", item)
+	}
+}
@@ -146,6 +146,16 @@ func TestStage_UnmarshalJSON(t *testing.T) {
 				data: []byte(`{"type":"org.osbuild.dnf.config","options":{}}`),
 			},
 		},
+		{
+			name: "dnf-module-config",
+			fields: fields{
+				Type:    "org.osbuild.dnf.module-config",
+				Options: &DNFModuleConfigStageOptions{},
+			},
+			args: args{
+				data: []byte(`{"type":"org.osbuild.dnf.module-config","options":{"conf":null}}`),
+			},
+		},
 		{
 			name: "dnf-automatic-config",
 			fields: fields{
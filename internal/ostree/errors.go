@@ -45,3 +45,17 @@ func (e ParameterComboError) Error() string {
 func NewParameterComboError(msg string, args ...interface{}) ParameterComboError {
 	return ParameterComboError{msg: fmt.Sprintf(msg, args...)}
 }
+
+// RemoteConfigError is returned when the secrets or GPG key of the remote
+// repository are invalid.
+type RemoteConfigError struct {
+	msg string
+}
+
+func (e RemoteConfigError) Error() string {
+	return e.msg
+}
+
+func NewRemoteConfigError(msg string, args ...interface{}) RemoteConfigError {
+	return RemoteConfigError{msg: fmt.Sprintf(msg, args...)}
+}
@@ -16,6 +16,34 @@ type RequestParams struct {
 	URL    string `json:"url"`
 	Ref    string `json:"ref"`
 	Parent string `json:"parent"`
+	// Sign the commit with the key of the worker building it.
+	Sign bool `json:"sign,omitempty"`
+	// StaticDelta generates a static delta from the parent commit to the
+	// new one, so that clients don't have to pull all changed objects.
+	StaticDelta bool `json:"static_delta,omitempty"`
+	// RHSM makes osbuild pull the parent commit from URL with the
+	// entitlement consumer certificate of the worker's host. Composer
+	// doesn't access the repository itself, so Parent must be the
+	// checksum of the commit rather than a ref.
+	RHSM bool `json:"rhsm,omitempty"`
+	// GPGKey is an armored public key the parent commit must be signed
+	// with.
+	GPGKey string `json:"gpg_key,omitempty"`
+}
+
+// SourceSecrets returns the name of the osbuild secrets provider to pull the
+// parent commit with, or an empty string if the repository doesn't need any.
+func (p RequestParams) SourceSecrets() string {
+	if !p.RHSM {
+		return ""
+	}
+	return SourceSecretsName
+}
+
+// isChecksum returns true if `s` is the SHA256 checksum of an ostree commit.
+func isChecksum(s string) bool {
+	_, err := hex.DecodeString(s)
+	return len(s) == 64 && err == nil
 }
 
 func VerifyRef(ref string) bool {
@@ -35,6 +63,7 @@ func ResolveRef(location, ref string) (string, error) {
 	if err != nil {
 		return "", NewResolveRefError(err.Error())
 	}
+	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
 		return "", NewResolveRefError("ostree repository %q returned status: %s", u.String(), resp.Status)
 	}
@@ -54,10 +83,13 @@ func ResolveRef(location, ref string) (string, error) {
 // ResolveParams resolves all necessary missing parameters in the given struct:
 // it sets the defaultRef if none is provided and resolves the parent commit if
 // a URL and Ref are provided. If there is an error, it will be of type
-// InvalidParameterError or ResolveRefError (from the ResolveRef function)
+// InvalidParameterError, RemoteConfigError or ResolveRefError (from the
+// ResolveRef function)
 func ResolveParams(params RequestParams, defaultRef string) (RequestParams, error) {
 	resolved := RequestParams{}
 	resolved.Ref = params.Ref
+	resolved.Sign = params.Sign
+	resolved.StaticDelta = params.StaticDelta
 	// if ref is not provided, use distro default
 	if resolved.Ref == "" {
 		resolved.Ref = defaultRef
@@ -76,8 +108,27 @@ func ResolveParams(params RequestParams, defaultRef string) (RequestParams, erro
 		}
 	}
 
+	if (params.RHSM || params.GPGKey != "") && params.URL == "" {
+		return resolved, NewParameterComboError("ostree rhsm or GPG key specified, but no URL to use them with")
+	}
+	resolved.RHSM = params.RHSM
+	if params.GPGKey != "" {
+		if err := validateGPGKey(params.GPGKey); err != nil {
+			return resolved, err
+		}
+		resolved.GPGKey = params.GPGKey
+	}
+
 	resolved.URL = params.URL
-	if resolved.URL != "" {
+	if resolved.RHSM {
+		// the consumer certificate of composer's host must not be sent
+		// to repositories named in requests, so the commit isn't
+		// resolved here
+		if !isChecksum(params.Parent) {
+			return resolved, NewParameterComboError("ostree rhsm requires the checksum of the parent commit")
+		}
+		resolved.Parent = params.Parent
+	} else if resolved.URL != "" {
 		// if a URL is specified, we need to fetch the commit at the URL
 		// the reference to resolve is the parent commit which is defined by
 		// the 'parent' argument
@@ -93,5 +144,9 @@ func ResolveParams(params RequestParams, defaultRef string) (RequestParams, erro
 		}
 		resolved.Parent = parent
 	}
+
+	if resolved.StaticDelta && resolved.Parent == "" {
+		return resolved, NewParameterComboError("ostree static delta requested, but no parent commit to generate it from")
+	}
 	return resolved, nil
 }
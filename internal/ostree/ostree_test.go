@@ -82,3 +82,30 @@ func TestVerifyRef(t *testing.T) {
 		assert.Equal(t, expOut, VerifyRef(in), in)
 	}
 }
+
+func TestResolveParamsSignAndStaticDelta(t *testing.T) {
+	parent := "5330bb1b8820944567f519de66ad6354c729b6b490dea1c5a7ba320c9f147c58"
+	handler := http.NewServeMux()
+	handler.HandleFunc("/refs/heads/rhel/8/x86_64/edge", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, parent)
+	})
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+
+	resolved, err := ResolveParams(RequestParams{URL: srv.URL, Sign: true, StaticDelta: true}, "rhel/8/x86_64/edge")
+	assert.NoError(t, err)
+	assert.Equal(t, RequestParams{
+		URL:         srv.URL,
+		Ref:         "rhel/8/x86_64/edge",
+		Parent:      parent,
+		Sign:        true,
+		StaticDelta: true,
+	}, resolved)
+
+	resolved, err = ResolveParams(RequestParams{Sign: true}, "rhel/8/x86_64/edge")
+	assert.NoError(t, err)
+	assert.Equal(t, RequestParams{Ref: "rhel/8/x86_64/edge", Sign: true}, resolved)
+
+	_, err = ResolveParams(RequestParams{StaticDelta: true}, "rhel/8/x86_64/edge")
+	assert.IsType(t, ParameterComboError{}, err)
+}
@@ -0,0 +1,35 @@
+package ostree
+
+import (
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+)
+
+// SourceSecretsName is osbuild's secrets provider which authenticates the
+// ostree source with the entitlement consumer certificate of the host osbuild
+// runs on. It is the only kind of secrets osbuild's ostree source supports:
+// per-request CA certificates, client certificates or tokens can't be passed
+// to it, so they aren't supported either.
+const SourceSecretsName = "org.osbuild.rhsm.consumer"
+
+// The consumer certificate and key of the host, which osbuild's secrets
+// provider reads. Workers pull parent commits for static deltas with them as
+// well. Composer never uses them.
+var (
+	ConsumerCert = "/etc/pki/consumer/cert.pem"
+	ConsumerKey  = "/etc/pki/consumer/key.pem"
+)
+
+// validateGPGKey returns an error if `key` doesn't contain an armored public
+// key that commits can be verified with.
+func validateGPGKey(key string) error {
+	entities, err := openpgp.ReadArmoredKeyRing(strings.NewReader(key))
+	if err != nil {
+		return NewRemoteConfigError("invalid ostree GPG key: %v", err)
+	}
+	if len(entities) == 0 {
+		return NewRemoteConfigError("invalid ostree GPG key: no keys found")
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package ostree
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveParamsRHSM(t *testing.T) {
+	commit := "5330bb1b8820944567f519de66ad6354c729b6b490dea1c5a7ba320c9f147c58"
+
+	// composer doesn't send the consumer certificate of its host anywhere,
+	// so it never accesses rhsm repositories
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("rhsm repository was accessed: %s", r.URL)
+	}))
+	defer srv.Close()
+
+	resolved, err := ResolveParams(RequestParams{URL: srv.URL, Parent: commit, RHSM: true}, "rhel/8/x86_64/edge")
+	require.NoError(t, err)
+	assert.Equal(t, commit, resolved.Parent)
+	assert.True(t, resolved.RHSM)
+	assert.Equal(t, SourceSecretsName, resolved.SourceSecrets())
+
+	// refs can't be resolved without accessing the repository
+	_, err = ResolveParams(RequestParams{URL: srv.URL, RHSM: true}, "rhel/8/x86_64/edge")
+	assert.IsType(t, ParameterComboError{}, err)
+	_, err = ResolveParams(RequestParams{URL: srv.URL, Parent: "rhel/8/x86_64/edge", RHSM: true}, "rhel/8/x86_64/edge")
+	assert.IsType(t, ParameterComboError{}, err)
+
+	_, err = ResolveParams(RequestParams{Parent: commit, RHSM: true}, "rhel/8/x86_64/edge")
+	assert.IsType(t, ParameterComboError{}, err)
+}
+
+func TestResolveParamsRemoteConfig(t *testing.T) {
+	entity, err := openpgp.NewEntity("osbuild", "", "osbuild@example.com", nil)
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	w, err := armor.Encode(&buf, openpgp.PublicKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, entity.Serialize(w))
+	require.NoError(t, w.Close())
+	gpgKey := buf.String()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "5330bb1b8820944567f519de66ad6354c729b6b490dea1c5a7ba320c9f147c58")
+	}))
+	defer srv.Close()
+
+	resolved, err := ResolveParams(RequestParams{URL: srv.URL, GPGKey: gpgKey}, "rhel/8/x86_64/edge")
+	require.NoError(t, err)
+	assert.Equal(t, gpgKey, resolved.GPGKey)
+	assert.Equal(t, "", resolved.SourceSecrets())
+
+	_, err = ResolveParams(RequestParams{GPGKey: gpgKey}, "rhel/8/x86_64/edge")
+	assert.IsType(t, ParameterComboError{}, err)
+
+	_, err = ResolveParams(RequestParams{URL: srv.URL, GPGKey: "not a key"}, "rhel/8/x86_64/edge")
+	assert.IsType(t, RemoteConfigError{}, err)
+
+	_, err = ResolveParams(RequestParams{RHSM: true}, "rhel/8/x86_64/edge")
+	assert.IsType(t, ParameterComboError{}, err)
+}
@@ -0,0 +1,42 @@
+package prometheus
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	TenantConcurrentComposes = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name:      "tenant_concurrent_composes",
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Help:      "Composes of a tenant which haven't finished yet",
+	}, []string{"tenant"})
+)
+
+var (
+	TenantComposesLastHour = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name:      "tenant_composes_last_hour",
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Help:      "Composes a tenant requested in the last hour",
+	}, []string{"tenant"})
+)
+
+var (
+	TenantImageSizeLastDay = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name:      "tenant_image_size_last_day_bytes",
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Help:      "Total size of the images a tenant requested in the last 24 hours",
+	}, []string{"tenant"})
+)
+
+var (
+	TenantQuotaRejections = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name:      "tenant_quota_rejections",
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Help:      "Compose requests rejected because a quota of the tenant was exceeded",
+	}, []string{"tenant", "quota"})
+)
@@ -0,0 +1,42 @@
+package prometheus
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	RPMCacheHits = promauto.NewCounter(prometheus.CounterOpts{
+		Name:      "rpm_cache_hits",
+		Namespace: namespace,
+		Subsystem: workerSubsystem,
+		Help:      "Total number of packages served from the package cache",
+	})
+)
+
+var (
+	RPMCacheMisses = promauto.NewCounter(prometheus.CounterOpts{
+		Name:      "rpm_cache_misses",
+		Namespace: namespace,
+		Subsystem: workerSubsystem,
+		Help:      "Total number of packages which had to be downloaded into the package cache",
+	})
+)
+
+var (
+	RPMCacheEvictions = promauto.NewCounter(prometheus.CounterOpts{
+		Name:      "rpm_cache_evictions",
+		Namespace: namespace,
+		Subsystem: workerSubsystem,
+		Help:      "Total number of packages removed from the package cache to make room for new ones",
+	})
+)
+
+var (
+	RPMCacheSize = promauto.NewGauge(prometheus.GaugeOpts{
+		Name:      "rpm_cache_size_bytes",
+		Namespace: namespace,
+		Subsystem: workerSubsystem,
+		Help:      "Total size of the packages in the package cache",
+	})
+)
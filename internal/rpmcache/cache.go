@@ -0,0 +1,279 @@
+// Package rpmcache implements a content-addressed cache for the packages
+// osbuild downloads with its curl source. Packages are keyed by the checksums
+// in their PackageSpecs, so that workers building images from the same
+// repositories don't have to download the same packages again and again.
+package rpmcache
+
+import (
+	"container/list"
+	/* #nosec G505 */
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/osbuild/osbuild-composer/internal/prometheus"
+)
+
+var hashes = map[string]func() hash.Hash{
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+	"sha384": sha512.New384,
+	"sha512": sha512.New,
+}
+
+// parseChecksum splits a checksum of the form "<algorithm>:<hex digest>" and
+// verifies that it is well-formed.
+func parseChecksum(checksum string) (string, string, error) {
+	parts := strings.SplitN(checksum, ":", 2)
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("invalid checksum %q", checksum)
+	}
+	algorithm, digest := parts[0], parts[1]
+
+	newHash, ok := hashes[algorithm]
+	if !ok {
+		return "", "", fmt.Errorf("unsupported checksum algorithm %q", algorithm)
+	}
+	// hex.EncodeToString() produces lower case digests
+	if b, err := hex.DecodeString(digest); err != nil || len(b) != newHash().Size() || digest != strings.ToLower(digest) {
+		return "", "", fmt.Errorf("invalid checksum %q", checksum)
+	}
+
+	return algorithm, digest, nil
+}
+
+type entry struct {
+	checksum string
+	size     int64
+}
+
+// Cache is a directory of packages, named by their checksums. When the
+// packages in it grow larger than its maximum size, the least recently used
+// ones are removed.
+type Cache struct {
+	dir     string
+	maxSize int64
+
+	mu      sync.Mutex
+	size    int64
+	lru     *list.List
+	entries map[string]*list.Element
+}
+
+// New opens the cache in `dir`, creating the directory if it doesn't exist
+// yet. Packages that are already in it are loaded, ordered by their
+// modification times, which are updated every time a package is used.
+func New(dir string, maxSize int64) (*Cache, error) {
+	if maxSize <= 0 {
+		return nil, fmt.Errorf("the maximum size of the package cache must be positive")
+	}
+
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Cache{
+		dir:     dir,
+		maxSize: maxSize,
+		lru:     list.New(),
+		entries: make(map[string]*list.Element),
+	}
+
+	type file struct {
+		entry
+		modified time.Time
+	}
+	var files []file
+	for algorithm := range hashes {
+		infos, err := ioutil.ReadDir(filepath.Join(dir, algorithm))
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		for _, info := range infos {
+			checksum := algorithm + ":" + info.Name()
+			if !info.Mode().IsRegular() {
+				continue
+			}
+			if _, _, err := parseChecksum(checksum); err != nil {
+				continue
+			}
+			files = append(files, file{entry{checksum, info.Size()}, info.ModTime()})
+		}
+	}
+
+	// oldest first, so that the most recently used ends up in front
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modified.Before(files[j].modified)
+	})
+	for _, f := range files {
+		c.entries[f.checksum] = c.lru.PushFront(&entry{f.checksum, f.size})
+		c.size += f.size
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.evict()
+
+	return c, nil
+}
+
+// Size returns the total size of all packages in the cache.
+func (c *Cache) Size() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.size
+}
+
+func (c *Cache) path(checksum string) (string, error) {
+	algorithm, digest, err := parseChecksum(checksum)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(c.dir, algorithm, digest), nil
+}
+
+// Open returns the cached package with `checksum` and marks it as the most
+// recently used one. The returned error satisfies os.IsNotExist() when the
+// package is not cached.
+func (c *Cache) Open(checksum string) (*os.File, error) {
+	path, err := c.path(checksum)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[checksum]
+	if !ok {
+		return nil, &os.PathError{Op: "open", Path: path, Err: os.ErrNotExist}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// removed behind our back
+			c.remove(element)
+		}
+		return nil, err
+	}
+
+	c.lru.MoveToFront(element)
+	now := time.Now()
+	// the modification time is only used to restore the order on restarts
+	_ = os.Chtimes(path, now, now)
+
+	return f, nil
+}
+
+// Add reads a package from `r` and stores it in the cache, after verifying
+// that its contents match `checksum`. Packages that are larger than the cache
+// are not stored, and reading them stops as soon as they exceed its size.
+//
+// The stored package is returned opened for reading, so that the caller can
+// still serve it when other packages evict it right away.
+func (c *Cache) Add(checksum string, r io.Reader) (*os.File, error) {
+	algorithm, _, err := parseChecksum(checksum)
+	if err != nil {
+		return nil, err
+	}
+	path, err := c.path(checksum)
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(path), ".download-")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		tmp.Close()
+		os.Remove(tmp.Name())
+	}()
+
+	h := hashes[algorithm]()
+	size, err := io.Copy(io.MultiWriter(tmp, h), io.LimitReader(r, c.maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if size > c.maxSize {
+		return nil, fmt.Errorf("package %s is larger than the cache", checksum)
+	}
+	if actual := algorithm + ":" + hex.EncodeToString(h.Sum(nil)); actual != checksum {
+		return nil, fmt.Errorf("checksum mismatch: expected %s, got %s", checksum, actual)
+	}
+
+	err = tmp.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	err = os.Rename(tmp.Name(), path)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if element, ok := c.entries[checksum]; ok {
+		c.remove(element)
+	}
+	c.entries[checksum] = c.lru.PushFront(&entry{checksum, size})
+	c.size += size
+	c.evict()
+
+	return f, nil
+}
+
+// remove drops an element from the LRU list. It must be called with the
+// mutex held and does not touch the file.
+func (c *Cache) remove(element *list.Element) {
+	e := c.lru.Remove(element).(*entry)
+	delete(c.entries, e.checksum)
+	c.size -= e.size
+	prometheus.RPMCacheSize.Set(float64(c.size))
+}
+
+// evict removes the least recently used packages until the cache fits into
+// its maximum size. It must be called with the mutex held.
+func (c *Cache) evict() {
+	for c.size > c.maxSize {
+		element := c.lru.Back()
+		e := element.Value.(*entry)
+		path, _ := c.path(e.checksum)
+		err := os.Remove(path)
+		if err != nil && !os.IsNotExist(err) {
+			// keep track of it, so that the size doesn't go out of sync
+			// with what's actually on disk
+			break
+		}
+		c.remove(element)
+		prometheus.RPMCacheEvictions.Inc()
+	}
+	prometheus.RPMCacheSize.Set(float64(c.size))
+}
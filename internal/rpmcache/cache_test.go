@@ -0,0 +1,174 @@
+package rpmcache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sha256sum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func readPackage(t *testing.T, c *Cache, checksum string) []byte {
+	f, err := c.Open(checksum)
+	require.NoError(t, err)
+	defer f.Close()
+	data, err := ioutil.ReadAll(f)
+	require.NoError(t, err)
+	return data
+}
+
+func addPackage(t *testing.T, c *Cache, data []byte) {
+	f, err := c.Add(sha256sum(data), bytes.NewReader(data))
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+}
+
+// countingReader counts the bytes read from it
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += int64(n)
+	return n, err
+}
+
+func TestParseChecksum(t *testing.T) {
+	algorithm, digest, err := parseChecksum(sha256sum([]byte("a")))
+	require.NoError(t, err)
+	assert.Equal(t, "sha256", algorithm)
+	assert.Len(t, digest, 64)
+
+	for _, checksum := range []string{
+		"",
+		"sha256",
+		"sha256:abc",
+		"md5:0cc175b9c0f1b6a831c399e269772661",
+		"sha256:../../../../etc/passwd",
+		"sha1:86F7E437FAA5A7FCE15D1DDCB9EAEAEA377667B8",
+	} {
+		_, _, err := parseChecksum(checksum)
+		assert.Errorf(t, err, "checksum %q should be invalid", checksum)
+	}
+}
+
+func TestCacheAdd(t *testing.T) {
+	c, err := New(t.TempDir(), 1024)
+	require.NoError(t, err)
+
+	data := []byte("package")
+	checksum := sha256sum(data)
+
+	_, err = c.Open(checksum)
+	require.True(t, os.IsNotExist(err))
+
+	_, err = c.Add(checksum, bytes.NewReader([]byte("tampered")))
+	require.EqualError(t, err, "checksum mismatch: expected "+checksum+", got "+sha256sum([]byte("tampered")))
+	require.Equal(t, int64(0), c.Size())
+
+	addPackage(t, c, data)
+	require.Equal(t, data, readPackage(t, c, checksum))
+	require.Equal(t, int64(len(data)), c.Size())
+
+	// adding it again doesn't count it twice
+	addPackage(t, c, data)
+	require.Equal(t, int64(len(data)), c.Size())
+
+	// reading stops as soon as a package is larger than the cache
+	large := &countingReader{r: bytes.NewReader(bytes.Repeat([]byte("x"), 4096))}
+	_, err = c.Add(sha256sum([]byte("large")), large)
+	require.EqualError(t, err, "package "+sha256sum([]byte("large"))+" is larger than the cache")
+	require.LessOrEqual(t, large.n, int64(1025))
+	require.Equal(t, int64(len(data)), c.Size())
+}
+
+func TestCacheAddEvicted(t *testing.T) {
+	c, err := New(t.TempDir(), 10)
+	require.NoError(t, err)
+
+	a := bytes.Repeat([]byte("a"), 10)
+	b := bytes.Repeat([]byte("b"), 10)
+
+	f, err := c.Add(sha256sum(a), bytes.NewReader(a))
+	require.NoError(t, err)
+	defer f.Close()
+
+	// a is evicted right away, but can still be read from the returned file
+	addPackage(t, c, b)
+	_, err = c.Open(sha256sum(a))
+	require.True(t, os.IsNotExist(err))
+
+	read, err := ioutil.ReadAll(f)
+	require.NoError(t, err)
+	require.Equal(t, a, read)
+}
+
+func TestCacheEviction(t *testing.T) {
+	dir := t.TempDir()
+	c, err := New(dir, 30)
+	require.NoError(t, err)
+
+	a := bytes.Repeat([]byte("a"), 10)
+	b := bytes.Repeat([]byte("b"), 10)
+	d := bytes.Repeat([]byte("d"), 10)
+	e := bytes.Repeat([]byte("e"), 10)
+
+	for _, data := range [][]byte{a, b, d} {
+		addPackage(t, c, data)
+	}
+	require.Equal(t, int64(30), c.Size())
+
+	// use a, so that b is the least recently used one
+	readPackage(t, c, sha256sum(a))
+
+	addPackage(t, c, e)
+	require.Equal(t, int64(30), c.Size())
+
+	_, err = c.Open(sha256sum(b))
+	require.True(t, os.IsNotExist(err))
+	_, err = os.Stat(dir + "/sha256/" + sha256sum(b)[len("sha256:"):])
+	require.True(t, os.IsNotExist(err))
+
+	for _, data := range [][]byte{a, d, e} {
+		require.Equal(t, data, readPackage(t, c, sha256sum(data)))
+	}
+}
+
+func TestCacheReopen(t *testing.T) {
+	dir := t.TempDir()
+	c, err := New(dir, 30)
+	require.NoError(t, err)
+
+	a := bytes.Repeat([]byte("a"), 10)
+	b := bytes.Repeat([]byte("b"), 10)
+	addPackage(t, c, a)
+	addPackage(t, c, b)
+
+	// make a the most recently used one, even with coarse timestamps
+	past := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(dir+"/sha256/"+sha256sum(b)[len("sha256:"):], past, past))
+
+	// stray files are ignored
+	require.NoError(t, ioutil.WriteFile(dir+"/sha256/garbage", []byte("garbage"), 0600))
+
+	// reopening with a smaller size evicts the least recently used package
+	c, err = New(dir, 15)
+	require.NoError(t, err)
+	require.Equal(t, int64(10), c.Size())
+	require.Equal(t, a, readPackage(t, c, sha256sum(a)))
+	_, err = c.Open(sha256sum(b))
+	require.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,117 @@
+package rpmcache
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/osbuild/osbuild-composer/internal/distro"
+)
+
+// sourceItems maps the sources which download packages to the key of their
+// items, for osbuild1 ("org.osbuild.files") and osbuild2 ("org.osbuild.curl")
+// manifests.
+var sourceItems = map[string]string{
+	"org.osbuild.files": "urls",
+	"org.osbuild.curl":  "items",
+}
+
+// RewriteManifest returns a copy of `manifest` in which the packages of its
+// curl sources are downloaded through the cache server at `baseURL`.
+//
+// Items which need secrets or skip certificate verification are left alone,
+// because the cache server can't download them on the client's behalf. So
+// are items outside of `upstreams`, which the server refuses to download.
+func RewriteManifest(manifest distro.Manifest, baseURL string, upstreams []string) (distro.Manifest, error) {
+	var m map[string]json.RawMessage
+	err := json.Unmarshal(manifest, &m)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing manifest: %v", err)
+	}
+
+	rawSources, ok := m["sources"]
+	if !ok {
+		return manifest, nil
+	}
+
+	var sources map[string]map[string]json.RawMessage
+	err = json.Unmarshal(rawSources, &sources)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing manifest sources: %v", err)
+	}
+
+	for name, key := range sourceItems {
+		rawItems, ok := sources[name][key]
+		if !ok {
+			continue
+		}
+
+		var items map[string]json.RawMessage
+		err = json.Unmarshal(rawItems, &items)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing items of %s: %v", name, err)
+		}
+
+		for checksum, item := range items {
+			rewritten, err := rewriteItem(checksum, item, baseURL, upstreams)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing item %s of %s: %v", checksum, name, err)
+			}
+			items[checksum] = rewritten
+		}
+
+		sources[name][key], err = json.Marshal(items)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	m["sources"], err = json.Marshal(sources)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(m)
+}
+
+// rewriteItem returns a source item which downloads the package `checksum`
+// through the cache server, or `item` unchanged when that's not possible.
+func rewriteItem(checksum string, item json.RawMessage, baseURL string, upstreams []string) (json.RawMessage, error) {
+	if _, _, err := parseChecksum(checksum); err != nil {
+		return item, nil
+	}
+
+	var url string
+	if err := json.Unmarshal(item, &url); err == nil {
+		if !AllowedUpstream(upstreams, url) {
+			return item, nil
+		}
+		return json.Marshal(PackageURL(baseURL, checksum, url))
+	}
+
+	var object map[string]json.RawMessage
+	err := json.Unmarshal(item, &object)
+	if err != nil {
+		return nil, err
+	}
+
+	if secrets, ok := object["secrets"]; ok && string(secrets) != "null" {
+		return item, nil
+	}
+	if insecure, ok := object["insecure"]; ok && string(insecure) != "false" {
+		return item, nil
+	}
+
+	err = json.Unmarshal(object["url"], &url)
+	if err != nil {
+		return nil, err
+	}
+	if !AllowedUpstream(upstreams, url) {
+		return item, nil
+	}
+	object["url"], err = json.Marshal(PackageURL(baseURL, checksum, url))
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(object)
+}
@@ -0,0 +1,54 @@
+package rpmcache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/osbuild-composer/internal/distro"
+)
+
+func TestRewriteManifest(t *testing.T) {
+	a := sha256sum([]byte("a"))
+	b := sha256sum([]byte("b"))
+	c := sha256sum([]byte("c"))
+	d := sha256sum([]byte("d"))
+
+	tests := []struct {
+		name     string
+		manifest string
+		expected string
+	}{
+		{
+			name:     "v2",
+			manifest: `{"version":"2","pipelines":[],"sources":{"org.osbuild.curl":{"items":{"` + a + `":"https://example.com/a.rpm","` + b + `":{"url":"https://example.com/b.rpm"},"` + c + `":{"url":"https://example.com/c.rpm","secrets":{"name":"org.osbuild.rhsm"}},"` + d + `":{"url":"https://example.com/d.rpm","insecure":true}}},"org.osbuild.inline":{"items":{}}}}`,
+			expected: `{"version":"2","pipelines":[],"sources":{"org.osbuild.curl":{"items":{"` + a + `":"http://cache:8700/rpms/` + a + `?url=https%3A%2F%2Fexample.com%2Fa.rpm","` + b + `":{"url":"http://cache:8700/rpms/` + b + `?url=https%3A%2F%2Fexample.com%2Fb.rpm"},"` + c + `":{"url":"https://example.com/c.rpm","secrets":{"name":"org.osbuild.rhsm"}},"` + d + `":{"url":"https://example.com/d.rpm","insecure":true}}},"org.osbuild.inline":{"items":{}}}}`,
+		},
+		{
+			name:     "v1",
+			manifest: `{"pipeline":{},"sources":{"org.osbuild.files":{"urls":{"` + a + `":{"url":"https://example.com/a.rpm"},"` + b + `":{"url":"https://example.com/b.rpm","secrets":{"name":"org.osbuild.rhsm"}}}}}}`,
+			expected: `{"pipeline":{},"sources":{"org.osbuild.files":{"urls":{"` + a + `":{"url":"http://cache:8700/rpms/` + a + `?url=https%3A%2F%2Fexample.com%2Fa.rpm"},"` + b + `":{"url":"https://example.com/b.rpm","secrets":{"name":"org.osbuild.rhsm"}}}}}}`,
+		},
+		{
+			name:     "other upstream",
+			manifest: `{"version":"2","pipelines":[],"sources":{"org.osbuild.curl":{"items":{"` + a + `":"https://example.com/a.rpm","` + b + `":{"url":"https://example.org/b.rpm"}}}}}`,
+			expected: `{"version":"2","pipelines":[],"sources":{"org.osbuild.curl":{"items":{"` + a + `":"http://cache:8700/rpms/` + a + `?url=https%3A%2F%2Fexample.com%2Fa.rpm","` + b + `":{"url":"https://example.org/b.rpm"}}}}}`,
+		},
+		{
+			name:     "no sources",
+			manifest: `{"version":"2","pipelines":[]}`,
+			expected: `{"version":"2","pipelines":[]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rewritten, err := RewriteManifest(distro.Manifest(tt.manifest), "http://cache:8700/", []string{"https://example.com/"})
+			require.NoError(t, err)
+			require.JSONEq(t, tt.expected, string(rewritten))
+		})
+	}
+
+	_, err := RewriteManifest(distro.Manifest(`{"sources":{"org.osbuild.curl":{"items":{"`+a+`":42}}}}`), "http://cache:8700", []string{"https://example.com/"})
+	require.Error(t, err)
+}
@@ -0,0 +1,196 @@
+package rpmcache
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/osbuild/osbuild-composer/internal/prometheus"
+)
+
+// Server serves the packages of a cache over HTTP, at
+//
+//	GET /rpms/<checksum>?url=<upstream url>
+//
+// Packages that are not cached yet are downloaded from the upstream url
+// first. When that fails, clients are redirected to the upstream url.
+// Packages are verified against their checksums, so the cache can be shared
+// by all workers that can reach the server. To keep it from being used as an
+// open proxy, only upstream urls below one of the configured base urls are
+// downloaded.
+type Server struct {
+	cache     *Cache
+	client    *http.Client
+	upstreams []string
+
+	mu        sync.Mutex
+	downloads map[string]*download
+}
+
+// download is a package that is currently being downloaded, so that
+// concurrent requests for the same package wait for it instead of starting
+// their own download.
+type download struct {
+	done chan struct{}
+	err  error
+}
+
+// NewServer returns a server for `cache`, which downloads packages with
+// `client` from below the base urls in `upstreams`, usually those of the
+// repositories images are built from. It uses http.DefaultClient when
+// `client` is nil.
+func NewServer(cache *Cache, client *http.Client, upstreams []string) *Server {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Server{
+		cache:     cache,
+		client:    client,
+		upstreams: upstreams,
+		downloads: make(map[string]*download),
+	}
+}
+
+// AllowedUpstream returns whether `upstream` is below one of the base urls in
+// `upstreams`. Urls with credentials or relative path segments never are.
+func AllowedUpstream(upstreams []string, upstream string) bool {
+	u, err := url.Parse(upstream)
+	if err != nil || u.User != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return false
+	}
+	for _, segment := range strings.Split(u.Path, "/") {
+		if segment == "." || segment == ".." {
+			return false
+		}
+	}
+
+	for _, base := range upstreams {
+		b, err := url.Parse(base)
+		if err != nil || b.User != nil {
+			continue
+		}
+		if strings.EqualFold(u.Scheme, b.Scheme) && strings.EqualFold(u.Host, b.Host) &&
+			strings.HasPrefix(u.Path, strings.TrimSuffix(b.Path, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodGet && request.Method != http.MethodHead {
+		http.Error(writer, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	dir, checksum := path.Split(request.URL.Path)
+	if dir != "/rpms/" {
+		http.NotFound(writer, request)
+		return
+	}
+	if _, _, err := parseChecksum(checksum); err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	f, err := s.cache.Open(checksum)
+	if err == nil {
+		prometheus.RPMCacheHits.Inc()
+	} else if os.IsNotExist(err) {
+		prometheus.RPMCacheMisses.Inc()
+
+		upstream, perr := url.Parse(request.URL.Query().Get("url"))
+		if perr != nil || (upstream.Scheme != "http" && upstream.Scheme != "https") {
+			http.Error(writer, fmt.Sprintf("%s is not cached and no valid upstream url was given", checksum), http.StatusNotFound)
+			return
+		}
+
+		if !AllowedUpstream(s.upstreams, upstream.String()) {
+			http.Error(writer, fmt.Sprintf("%s is not an allowed upstream", upstream), http.StatusForbidden)
+			return
+		}
+
+		f, err = s.fetch(checksum, upstream.String())
+		if err == nil && f == nil {
+			// downloaded by a concurrent request
+			f, err = s.cache.Open(checksum)
+		}
+		if err != nil {
+			// Don't fail the build because of the cache. The client
+			// verifies the checksum of what it downloads itself.
+			logrus.Warnf("Error downloading %s into the package cache: %v", upstream, err)
+			http.Redirect(writer, request, upstream.String(), http.StatusFound)
+			return
+		}
+	}
+	if err != nil {
+		logrus.Errorf("Error opening %s in the package cache: %v", checksum, err)
+		http.Error(writer, "error reading package from the cache", http.StatusInternalServerError)
+		return
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		http.Error(writer, "error reading package from the cache", http.StatusInternalServerError)
+		return
+	}
+
+	writer.Header().Set("Content-Type", "application/octet-stream")
+	http.ServeContent(writer, request, "", info.ModTime(), f)
+}
+
+// fetch downloads `checksum` from `upstream` into the cache and returns the
+// downloaded package, unless another request is already downloading it. In
+// that case, it waits for that one and returns no package, which must be
+// opened from the cache then.
+func (s *Server) fetch(checksum, upstream string) (*os.File, error) {
+	s.mu.Lock()
+	d, inProgress := s.downloads[checksum]
+	if !inProgress {
+		d = &download{done: make(chan struct{})}
+		s.downloads[checksum] = d
+	}
+	s.mu.Unlock()
+
+	if inProgress {
+		<-d.done
+		return nil, d.err
+	}
+
+	var f *os.File
+	f, d.err = s.download(checksum, upstream)
+
+	s.mu.Lock()
+	delete(s.downloads, checksum)
+	s.mu.Unlock()
+	close(d.done)
+
+	return f, d.err
+}
+
+func (s *Server) download(checksum, upstream string) (*os.File, error) {
+	resp, err := s.client.Get(upstream)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned %s", upstream, resp.Status)
+	}
+
+	return s.cache.Add(checksum, resp.Body)
+}
+
+// PackageURL returns the url under which the server at `baseURL` serves the
+// package with `checksum`, downloading it from `upstream` if necessary.
+func PackageURL(baseURL, checksum, upstream string) string {
+	return strings.TrimSuffix(baseURL, "/") + "/rpms/" + checksum + "?url=" + url.QueryEscape(upstream)
+}
@@ -0,0 +1,51 @@
+package rpmmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// RepoChecksumError is returned by VerifyRepoChecksums when the metadata of
+// repositories doesn't match the recorded checksums anymore.
+type RepoChecksumError struct {
+	// Changed are repositories whose metadata changed
+	Changed []string
+	// Added are repositories which were used but not recorded
+	Added []string
+}
+
+func (e *RepoChecksumError) Error() string {
+	var problems []string
+	if len(e.Changed) > 0 {
+		problems = append(problems, "changed: "+strings.Join(e.Changed, ", "))
+	}
+	if len(e.Added) > 0 {
+		problems = append(problems, "added: "+strings.Join(e.Added, ", "))
+	}
+	return fmt.Sprintf("repositories do not match the snapshot (%s)", strings.Join(problems, "; "))
+}
+
+// VerifyRepoChecksums compares the metadata checksums of repositories, as
+// returned by Depsolve() and FetchMetadata(), to previously recorded ones. It
+// returns a *RepoChecksumError when any of the repositories changed or wasn't
+// recorded. Recorded repositories which are missing from `checksums` are
+// ignored, because not all of them are used for every image type.
+func VerifyRepoChecksums(recorded, checksums map[string]string) error {
+	var e RepoChecksumError
+	for repo, checksum := range checksums {
+		if previous, ok := recorded[repo]; !ok {
+			e.Added = append(e.Added, repo)
+		} else if previous != checksum {
+			e.Changed = append(e.Changed, repo)
+		}
+	}
+
+	if len(e.Changed) == 0 && len(e.Added) == 0 {
+		return nil
+	}
+
+	sort.Strings(e.Changed)
+	sort.Strings(e.Added)
+	return &e
+}
@@ -0,0 +1,138 @@
+package rpmmd
+
+import (
+	"strings"
+)
+
+// CompareEVR compares two packages' epoch, version and release in the same
+// way rpm does. It returns -1 if the first is older than the second, 1 if it
+// is newer, and 0 if both are equal.
+func CompareEVR(epoch1 uint, version1, release1 string, epoch2 uint, version2, release2 string) int {
+	if epoch1 < epoch2 {
+		return -1
+	} else if epoch1 > epoch2 {
+		return 1
+	}
+
+	if c := rpmvercmp(version1, version2); c != 0 {
+		return c
+	}
+
+	return rpmvercmp(release1, release2)
+}
+
+// rpmvercmp is a port of rpm's version comparison algorithm (rpmvercmp() in
+// rpmio/rpmvercmp.c). Strings are split into alternating runs of digits and
+// letters, which are compared pairwise. Digit runs compare numerically and are
+// always newer than letter runs. A tilde sorts before anything, even the end
+// of the string, and a caret sorts after the end of the string but before
+// anything else.
+func rpmvercmp(a, b string) int {
+	if a == b {
+		return 0
+	}
+
+	isSeparator := func(c byte) bool {
+		return !isAlnum(c) && c != '~' && c != '^'
+	}
+
+	for len(a) > 0 || len(b) > 0 {
+		_, a = splitRun(a, isSeparator)
+		_, b = splitRun(b, isSeparator)
+
+		// tilde sorts before everything else
+		if strings.HasPrefix(a, "~") || strings.HasPrefix(b, "~") {
+			if !strings.HasPrefix(a, "~") {
+				return 1
+			}
+			if !strings.HasPrefix(b, "~") {
+				return -1
+			}
+			a, b = a[1:], b[1:]
+			continue
+		}
+
+		// caret sorts after the end of the string, but before anything else
+		if strings.HasPrefix(a, "^") || strings.HasPrefix(b, "^") {
+			if len(a) == 0 {
+				return -1
+			}
+			if len(b) == 0 {
+				return 1
+			}
+			if !strings.HasPrefix(a, "^") {
+				return 1
+			}
+			if !strings.HasPrefix(b, "^") {
+				return -1
+			}
+			a, b = a[1:], b[1:]
+			continue
+		}
+
+		if len(a) == 0 || len(b) == 0 {
+			break
+		}
+
+		var segA, segB string
+		numeric := isDigit(a[0])
+		if numeric {
+			segA, a = splitRun(a, isDigit)
+			segB, b = splitRun(b, isDigit)
+		} else {
+			segA, a = splitRun(a, isAlpha)
+			segB, b = splitRun(b, isAlpha)
+		}
+
+		// segments of different types: numeric is newer
+		if len(segB) == 0 {
+			if numeric {
+				return 1
+			}
+			return -1
+		}
+
+		if numeric {
+			segA = strings.TrimLeft(segA, "0")
+			segB = strings.TrimLeft(segB, "0")
+			if len(segA) != len(segB) {
+				if len(segA) > len(segB) {
+					return 1
+				}
+				return -1
+			}
+		}
+
+		if c := strings.Compare(segA, segB); c != 0 {
+			return c
+		}
+	}
+
+	if len(a) == 0 && len(b) == 0 {
+		return 0
+	}
+	if len(a) == 0 {
+		return -1
+	}
+	return 1
+}
+
+func splitRun(s string, f func(byte) bool) (string, string) {
+	i := 0
+	for i < len(s) && f(s[i]) {
+		i++
+	}
+	return s[:i], s[i:]
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isAlpha(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isAlnum(c byte) bool {
+	return isDigit(c) || isAlpha(c)
+}
@@ -0,0 +1,118 @@
+package rpmmd
+
+import (
+	"sort"
+)
+
+// PackageExplanation describes why a package is part of a depsolved package
+// set.
+type PackageExplanation struct {
+	Name   string
+	Reason string
+	// Chain is the shortest chain of requirements that pulls in the
+	// package. It starts at a package that was requested explicitly and
+	// ends with the package itself, so that each package in it requires
+	// the next one.
+	Chain []string
+	// RequestedBy are the package specs that requested the first package
+	// of Chain.
+	RequestedBy []string
+}
+
+// requested returns whether a package was requested explicitly, either by a
+// package spec or as part of a group.
+func (spec *PackageSpec) requested() bool {
+	return len(spec.RequestedBy) > 0 || spec.Reason == "user" || spec.Reason == "group"
+}
+
+// ExplainPackages returns the requirement chain of each of `specs`, in the
+// same order. The specs must have been depsolved with PackageSet.Explain set.
+// Packages that can't be traced back to a requested package get an empty
+// chain.
+func ExplainPackages(specs []PackageSpec) []PackageExplanation {
+	// requires maps each package name to the names of the packages it
+	// requires, which is the reverse of RequiredBy. Packages with the same
+	// name (multilib) are treated as one.
+	requires := make(map[string][]string)
+	requestedBy := make(map[string][]string)
+	var roots []string
+	for _, spec := range specs {
+		for _, name := range spec.RequiredBy {
+			requires[name] = append(requires[name], spec.Name)
+		}
+		if spec.requested() {
+			if _, seen := requestedBy[spec.Name]; !seen {
+				roots = append(roots, spec.Name)
+			}
+			requestedBy[spec.Name] = append(requestedBy[spec.Name], spec.RequestedBy...)
+		}
+	}
+	for _, names := range requires {
+		sort.Strings(names)
+	}
+	sort.Strings(roots)
+
+	// breadth-first search from all requested packages at once finds the
+	// shortest chain to every other package
+	parent := make(map[string]string)
+	origin := make(map[string]string)
+	queue := []string{}
+	for _, root := range roots {
+		origin[root] = root
+		queue = append(queue, root)
+	}
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		for _, dep := range requires[name] {
+			if _, visited := origin[dep]; visited {
+				continue
+			}
+			parent[dep] = name
+			origin[dep] = origin[name]
+			queue = append(queue, dep)
+		}
+	}
+
+	explanations := make([]PackageExplanation, len(specs))
+	for i, spec := range specs {
+		explanations[i] = PackageExplanation{
+			Name:   spec.Name,
+			Reason: spec.Reason,
+		}
+
+		root, ok := origin[spec.Name]
+		if !ok {
+			continue
+		}
+
+		var chain []string
+		for name := spec.Name; name != root; name = parent[name] {
+			chain = append(chain, name)
+		}
+		chain = append(chain, root)
+		for l, r := 0, len(chain)-1; l < r; l, r = l+1, r-1 {
+			chain[l], chain[r] = chain[r], chain[l]
+		}
+
+		explanations[i].Chain = chain
+		explanations[i].RequestedBy = uniqueStrings(requestedBy[root])
+	}
+
+	return explanations
+}
+
+func uniqueStrings(strs []string) []string {
+	if len(strs) == 0 {
+		return nil
+	}
+	seen := make(map[string]bool, len(strs))
+	var unique []string
+	for _, s := range strs {
+		if !seen[s] {
+			seen[s] = true
+			unique = append(unique, s)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,32 @@
+package rpmmd_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/osbuild-composer/internal/rpmmd"
+)
+
+func TestExplainPackages(t *testing.T) {
+	specs := []rpmmd.PackageSpec{
+		{Name: "tmux", Reason: "user", RequestedBy: []string{"tmux"}},
+		{Name: "libevent", Reason: "dependency", RequiredBy: []string{"tmux"}},
+		{Name: "openssl-libs", Reason: "dependency", RequiredBy: []string{"libevent", "systemd"}},
+		{Name: "systemd", Reason: "user", RequestedBy: []string{"systemd", "@core"}},
+		{Name: "glibc", Reason: "dependency", RequiredBy: []string{"libevent", "openssl-libs", "systemd", "tmux"}},
+		{Name: "kernel", Reason: "group"},
+		{Name: "orphan", Reason: "dependency"},
+	}
+
+	explanations := rpmmd.ExplainPackages(specs)
+	require.Equal(t, []rpmmd.PackageExplanation{
+		{Name: "tmux", Reason: "user", Chain: []string{"tmux"}, RequestedBy: []string{"tmux"}},
+		{Name: "libevent", Reason: "dependency", Chain: []string{"tmux", "libevent"}, RequestedBy: []string{"tmux"}},
+		{Name: "openssl-libs", Reason: "dependency", Chain: []string{"systemd", "openssl-libs"}, RequestedBy: []string{"systemd", "@core"}},
+		{Name: "systemd", Reason: "user", Chain: []string{"systemd"}, RequestedBy: []string{"systemd", "@core"}},
+		{Name: "glibc", Reason: "dependency", Chain: []string{"systemd", "glibc"}, RequestedBy: []string{"systemd", "@core"}},
+		{Name: "kernel", Reason: "group", Chain: []string{"kernel"}},
+		{Name: "orphan", Reason: "dependency"},
+	}, explanations)
+}
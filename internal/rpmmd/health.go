@@ -0,0 +1,330 @@
+package rpmmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+)
+
+// RepoHealthStatus is the outcome of one of the checks of a repository.
+type RepoHealthStatus string
+
+const (
+	RepoHealthOK      RepoHealthStatus = "ok"
+	RepoHealthWarning RepoHealthStatus = "warning"
+	RepoHealthError   RepoHealthStatus = "error"
+	RepoHealthSkipped RepoHealthStatus = "skipped"
+)
+
+// Names of the checks CheckRepositories runs for each repository.
+const (
+	RepoCheckReachable       = "reachable"
+	RepoCheckGPGKey          = "gpg_key"
+	RepoCheckRepomdSignature = "repomd_signature"
+	RepoCheckMetadataAge     = "metadata_age"
+)
+
+// StaleMetadataAge is the age of repository metadata from which on
+// CheckRepositories warns about it.
+const StaleMetadataAge = 30 * 24 * time.Hour
+
+// repoHealthTimeout limits how long each request of a health check may take,
+// so that unreachable repositories are reported in a reasonable time.
+const repoHealthTimeout = 30 * time.Second
+
+// repoHealthParallelism is how many repositories CheckRepositories probes at
+// the same time.
+const repoHealthParallelism = 8
+
+type RepoHealthCheck struct {
+	Name    string
+	Status  RepoHealthStatus
+	Message string
+}
+
+// RepoHealth is the result of checking a repository.
+type RepoHealth struct {
+	Name string
+	// Healthy is false when any of the checks returned an error. Warnings
+	// don't make a repository unhealthy.
+	Healthy bool
+	Checks  []RepoHealthCheck
+	// MetadataTimestamp is when the newest metadata of the repository was
+	// generated, or the zero time if that isn't known.
+	MetadataTimestamp time.Time
+}
+
+func (h *RepoHealth) add(name string, status RepoHealthStatus, format string, a ...interface{}) {
+	h.Checks = append(h.Checks, RepoHealthCheck{
+		Name:    name,
+		Status:  status,
+		Message: fmt.Sprintf(format, a...),
+	})
+	if status == RepoHealthError {
+		h.Healthy = false
+	}
+}
+
+// CheckRepositories probes each of `repos` and reports whether it is
+// reachable, whether its GPG keys are valid, whether its repomd.xml is signed
+// with one of them, and how old its metadata is. The repositories are checked
+// concurrently and the results returned in the same order as `repos`.
+//
+// GPG keys are read from file urls only if `localKeys` is set, because
+// repositories which aren't configured on the host are not trusted to point
+// at files on it.
+func (r *rpmmdImpl) CheckRepositories(repos []RepoConfig, arch, releasever string, localKeys bool) []RepoHealth {
+	results := make([]RepoHealth, len(repos))
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, repoHealthParallelism)
+	for i := range repos {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			results[i] = r.checkRepository(repos[i], arch, releasever, localKeys, time.Now())
+		}(i)
+	}
+	wg.Wait()
+
+	return results
+}
+
+func (r *rpmmdImpl) checkRepository(repo RepoConfig, arch, releasever string, localKeys bool, now time.Time) RepoHealth {
+	health := RepoHealth{
+		Name:    repo.Name,
+		Healthy: true,
+	}
+
+	client, err := r.repoHTTPClient(repo, arch, releasever)
+	if err != nil {
+		health.add(RepoCheckReachable, RepoHealthError, "%v", err)
+		return skipRemainingChecks(health)
+	}
+	client.Timeout = repoHealthTimeout
+
+	baseURL, err := resolveBaseURL(client, repo, arch, releasever)
+	if err != nil {
+		health.add(RepoCheckReachable, RepoHealthError, "error resolving base url: %v", err)
+		return skipRemainingChecks(health)
+	}
+
+	rawRepomd, repomd, err := fetchRepomd(client, baseURL)
+	if err != nil {
+		health.add(RepoCheckReachable, RepoHealthError, "%v", err)
+		return skipRemainingChecks(health)
+	}
+	health.add(RepoCheckReachable, RepoHealthOK, "%s", baseURL)
+
+	keyring := checkGPGKeys(&health, client, repo, localKeys, now)
+	checkRepomdSignature(&health, client, baseURL, rawRepomd, keyring)
+
+	health.MetadataTimestamp = repomd.timestamp()
+	if health.MetadataTimestamp.IsZero() {
+		health.add(RepoCheckMetadataAge, RepoHealthWarning, "repomd.xml doesn't contain timestamps")
+	} else {
+		age := now.Sub(health.MetadataTimestamp).Truncate(time.Second)
+		if age > StaleMetadataAge {
+			health.add(RepoCheckMetadataAge, RepoHealthWarning, "metadata was generated %v ago", age)
+		} else {
+			health.add(RepoCheckMetadataAge, RepoHealthOK, "metadata was generated %v ago", age)
+		}
+	}
+
+	return health
+}
+
+// skipRemainingChecks marks all checks after reachability as skipped, for
+// repositories which couldn't be reached.
+func skipRemainingChecks(health RepoHealth) RepoHealth {
+	for _, name := range []string{RepoCheckGPGKey, RepoCheckRepomdSignature, RepoCheckMetadataAge} {
+		health.add(name, RepoHealthSkipped, "repository is not reachable")
+	}
+	return health
+}
+
+// checkGPGKeys loads the GPG keys of `repo`, which are either inline or
+// given as urls, and checks that they are neither expired nor revoked. It
+// returns the valid keys.
+func checkGPGKeys(health *RepoHealth, client *http.Client, repo RepoConfig, localKeys bool, now time.Time) openpgp.EntityList {
+	if repo.GPGKey == "" {
+		if repo.CheckGPG {
+			health.add(RepoCheckGPGKey, RepoHealthWarning, "check_gpg is enabled, but no GPG key is configured")
+		} else {
+			health.add(RepoCheckGPGKey, RepoHealthSkipped, "no GPG key is configured")
+		}
+		return nil
+	}
+
+	var keys []byte
+	if strings.HasPrefix(strings.TrimSpace(repo.GPGKey), "-----BEGIN PGP") {
+		keys = []byte(repo.GPGKey)
+	} else {
+		for _, keyURL := range strings.Fields(repo.GPGKey) {
+			key, err := fetchGPGKey(client, keyURL, localKeys)
+			if err != nil {
+				health.add(RepoCheckGPGKey, RepoHealthError, "error fetching GPG key %s: %v", keyURL, err)
+				return nil
+			}
+			keys = append(keys, key...)
+			keys = append(keys, '\n')
+		}
+	}
+
+	entities, err := readArmoredKeys(keys)
+	if err != nil {
+		health.add(RepoCheckGPGKey, RepoHealthError, "invalid GPG key: %v", err)
+		return nil
+	}
+
+	var valid openpgp.EntityList
+	var problems []string
+	for _, entity := range entities {
+		if problem := gpgKeyProblem(entity, now); problem != "" {
+			problems = append(problems, fmt.Sprintf("key %s %s", entity.PrimaryKey.KeyIdString(), problem))
+		} else {
+			valid = append(valid, entity)
+		}
+	}
+
+	if len(valid) == 0 {
+		health.add(RepoCheckGPGKey, RepoHealthError, "%s", strings.Join(problems, ", "))
+	} else if len(problems) > 0 {
+		health.add(RepoCheckGPGKey, RepoHealthWarning, "%s", strings.Join(problems, ", "))
+	} else {
+		health.add(RepoCheckGPGKey, RepoHealthOK, "%d valid key(s)", len(valid))
+	}
+
+	return valid
+}
+
+// fetchGPGKey downloads a GPG key from an http or https url, or reads it from
+// a file url if `localKeys` is set.
+func fetchGPGKey(client *http.Client, keyURL string, localKeys bool) ([]byte, error) {
+	u, err := url.Parse(keyURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var body io.ReadCloser
+	switch u.Scheme {
+	case "http", "https":
+		body, err = httpGet(client, keyURL)
+	case "file":
+		if !localKeys {
+			return nil, fmt.Errorf("GPG keys can't be read from local files")
+		}
+		body, err = os.Open(u.Path)
+	default:
+		return nil, fmt.Errorf("unsupported url scheme %q", u.Scheme)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	return ioutil.ReadAll(io.LimitReader(body, 1024*1024))
+}
+
+// readArmoredKeys reads all armored key blocks in `data`, because key files
+// of repositories sometimes contain more than one.
+func readArmoredKeys(data []byte) (openpgp.EntityList, error) {
+	// armor.Decode() reads ahead, so split the blocks before decoding them
+	const begin = "-----BEGIN PGP PUBLIC KEY BLOCK-----"
+	blocks := strings.Split(string(data), begin)
+
+	var entities openpgp.EntityList
+	for _, block := range blocks[1:] {
+		el, err := openpgp.ReadArmoredKeyRing(strings.NewReader(begin + block))
+		if err != nil {
+			return nil, err
+		}
+		entities = append(entities, el...)
+	}
+
+	if len(entities) == 0 {
+		return nil, fmt.Errorf("no keys found")
+	}
+	return entities, nil
+}
+
+// gpgKeyProblem returns why `entity` is unusable at `now`, or an empty string
+// if it is usable.
+func gpgKeyProblem(entity *openpgp.Entity, now time.Time) string {
+	if len(entity.Revocations) > 0 {
+		return "is revoked"
+	}
+
+	for _, identity := range entity.Identities {
+		sig := identity.SelfSignature
+		if sig == nil || sig.KeyLifetimeSecs == nil || *sig.KeyLifetimeSecs == 0 {
+			continue
+		}
+		expiry := entity.PrimaryKey.CreationTime.Add(time.Duration(*sig.KeyLifetimeSecs) * time.Second)
+		if now.After(expiry) {
+			return fmt.Sprintf("expired on %s", expiry.UTC().Format("2006-01-02"))
+		}
+	}
+
+	return ""
+}
+
+// checkRepomdSignature verifies the detached signature of repomd.xml with
+// `keyring`, if the repository has one.
+func checkRepomdSignature(health *RepoHealth, client *http.Client, baseURL string, repomd []byte, keyring openpgp.EntityList) {
+	resp, err := client.Get(baseURL + "repodata/repomd.xml.asc")
+	if err != nil {
+		health.add(RepoCheckRepomdSignature, RepoHealthWarning, "error fetching repomd.xml.asc: %v", err)
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		health.add(RepoCheckRepomdSignature, RepoHealthSkipped, "repomd.xml is not signed")
+		return
+	} else if resp.StatusCode != http.StatusOK {
+		health.add(RepoCheckRepomdSignature, RepoHealthWarning, "unexpected status fetching repomd.xml.asc: %s", resp.Status)
+		return
+	}
+
+	if len(keyring) == 0 {
+		health.add(RepoCheckRepomdSignature, RepoHealthWarning, "repomd.xml is signed, but there is no valid GPG key to verify it with")
+		return
+	}
+
+	signer, err := openpgp.CheckArmoredDetachedSignature(keyring, bytes.NewReader(repomd), io.LimitReader(resp.Body, 64*1024), nil)
+	if err != nil {
+		health.add(RepoCheckRepomdSignature, RepoHealthError, "invalid signature of repomd.xml: %v", err)
+		return
+	}
+
+	health.add(RepoCheckRepomdSignature, RepoHealthOK, "signed by key %s", signer.PrimaryKey.KeyIdString())
+}
+
+// timestamp returns the time at which the newest metadata file of the
+// repository was generated, or the zero time if none of them has a timestamp.
+func (r *repomdXML) timestamp() time.Time {
+	var newest time.Time
+	for _, d := range r.Data {
+		seconds, err := strconv.ParseFloat(strings.TrimSpace(d.Timestamp), 64)
+		if err != nil {
+			continue
+		}
+		if t := time.Unix(int64(seconds), 0); t.After(newest) {
+			newest = t
+		}
+	}
+	return newest
+}
@@ -0,0 +1,152 @@
+package rpmmd_test
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/osbuild-composer/internal/rpmmd"
+)
+
+// testGPGKey returns a new GPG key, created at `created` and valid for
+// `lifetime` (forever if 0), and its armored public key.
+func testGPGKey(t *testing.T, created time.Time, lifetime time.Duration) (*openpgp.Entity, string) {
+	config := &packet.Config{
+		RSABits: 1024,
+		Time:    func() time.Time { return created },
+	}
+	entity, err := openpgp.NewEntity("osbuild-composer test", "", "test@example.com", config)
+	require.NoError(t, err)
+
+	if lifetime != 0 {
+		seconds := uint32(lifetime.Seconds())
+		for _, identity := range entity.Identities {
+			identity.SelfSignature.KeyLifetimeSecs = &seconds
+			err = identity.SelfSignature.SignUserId(identity.UserId.Id, entity.PrimaryKey, entity.PrivateKey, config)
+			require.NoError(t, err)
+		}
+	}
+
+	var buf bytes.Buffer
+	w, err := armor.Encode(&buf, openpgp.PublicKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, entity.Serialize(w))
+	require.NoError(t, w.Close())
+
+	return entity, buf.String()
+}
+
+func checkStatuses(health rpmmd.RepoHealth) map[string]rpmmd.RepoHealthStatus {
+	statuses := make(map[string]rpmmd.RepoHealthStatus)
+	for _, check := range health.Checks {
+		statuses[check.Name] = check.Status
+	}
+	return statuses
+}
+
+func TestCheckRepositories(t *testing.T) {
+	now := time.Now()
+	signer, signerKey := testGPGKey(t, now, 0)
+	_, otherKey := testGPGKey(t, now, 0)
+	_, expiredKey := testGPGKey(t, now.Add(-48*time.Hour), time.Hour)
+
+	fresh := fmt.Sprintf(`<repomd><data type="primary"><location href="repodata/primary.xml.gz"/><timestamp>%d</timestamp></data></repomd>`, now.Add(-time.Hour).Unix())
+	stale := fmt.Sprintf(`<repomd><data type="primary"><location href="repodata/primary.xml.gz"/><timestamp>%d</timestamp></data></repomd>`, now.Add(-2*rpmmd.StaleMetadataAge).Unix())
+
+	var signature bytes.Buffer
+	require.NoError(t, openpgp.ArmoredDetachSign(&signature, signer, strings.NewReader(fresh), nil))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/signed/repodata/repomd.xml", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(fresh))
+	})
+	mux.HandleFunc("/signed/repodata/repomd.xml.asc", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(signature.Bytes())
+	})
+	mux.HandleFunc("/key.asc", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(signerKey))
+	})
+	mux.HandleFunc("/stale/repodata/repomd.xml", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(stale))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	rpm := rpmmd.NewRPMMD(t.TempDir())
+	repos := []rpmmd.RepoConfig{
+		{Name: "signed", BaseURL: server.URL + "/signed/", GPGKey: signerKey, CheckGPG: true},
+		{Name: "key-url", BaseURL: server.URL + "/signed/", GPGKey: server.URL + "/key.asc", CheckGPG: true},
+		{Name: "wrong-key", BaseURL: server.URL + "/signed/", GPGKey: otherKey, CheckGPG: true},
+		{Name: "expired-key", BaseURL: server.URL + "/stale/", GPGKey: expiredKey + signerKey, CheckGPG: true},
+		{Name: "unsigned", BaseURL: server.URL + "/stale/"},
+		{Name: "missing", BaseURL: server.URL + "/missing/", GPGKey: signerKey},
+	}
+
+	results := rpm.CheckRepositories(repos, "x86_64", "35", false)
+	require.Len(t, results, len(repos))
+	for i, repo := range repos {
+		require.Equal(t, repo.Name, results[i].Name)
+	}
+
+	require.True(t, results[0].Healthy)
+	require.Equal(t, map[string]rpmmd.RepoHealthStatus{
+		rpmmd.RepoCheckReachable:       rpmmd.RepoHealthOK,
+		rpmmd.RepoCheckGPGKey:          rpmmd.RepoHealthOK,
+		rpmmd.RepoCheckRepomdSignature: rpmmd.RepoHealthOK,
+		rpmmd.RepoCheckMetadataAge:     rpmmd.RepoHealthOK,
+	}, checkStatuses(results[0]))
+	require.Equal(t, now.Add(-time.Hour).Unix(), results[0].MetadataTimestamp.Unix())
+
+	require.True(t, results[1].Healthy)
+	require.Equal(t, rpmmd.RepoHealthOK, checkStatuses(results[1])[rpmmd.RepoCheckRepomdSignature])
+
+	require.False(t, results[2].Healthy)
+	require.Equal(t, rpmmd.RepoHealthError, checkStatuses(results[2])[rpmmd.RepoCheckRepomdSignature])
+
+	// one of the keys is still valid
+	require.True(t, results[3].Healthy, "%+v", results[3].Checks)
+	require.Equal(t, map[string]rpmmd.RepoHealthStatus{
+		rpmmd.RepoCheckReachable:       rpmmd.RepoHealthOK,
+		rpmmd.RepoCheckGPGKey:          rpmmd.RepoHealthWarning,
+		rpmmd.RepoCheckRepomdSignature: rpmmd.RepoHealthSkipped,
+		rpmmd.RepoCheckMetadataAge:     rpmmd.RepoHealthWarning,
+	}, checkStatuses(results[3]))
+	require.Contains(t, results[3].Checks[1].Message, "expired on")
+
+	require.True(t, results[4].Healthy)
+	require.Equal(t, rpmmd.RepoHealthSkipped, checkStatuses(results[4])[rpmmd.RepoCheckGPGKey])
+
+	require.False(t, results[5].Healthy)
+	require.Equal(t, map[string]rpmmd.RepoHealthStatus{
+		rpmmd.RepoCheckReachable:       rpmmd.RepoHealthError,
+		rpmmd.RepoCheckGPGKey:          rpmmd.RepoHealthSkipped,
+		rpmmd.RepoCheckRepomdSignature: rpmmd.RepoHealthSkipped,
+		rpmmd.RepoCheckMetadataAge:     rpmmd.RepoHealthSkipped,
+	}, checkStatuses(results[5]))
+
+	// keys in local files are only read when allowed
+	keyFile := filepath.Join(t.TempDir(), "key.asc")
+	require.NoError(t, ioutil.WriteFile(keyFile, []byte(signerKey), 0600))
+	repos = []rpmmd.RepoConfig{
+		{Name: "key-file", BaseURL: server.URL + "/signed/", GPGKey: "file://" + keyFile, CheckGPG: true},
+	}
+
+	results = rpm.CheckRepositories(repos, "x86_64", "35", true)
+	require.True(t, results[0].Healthy)
+	require.Equal(t, rpmmd.RepoHealthOK, checkStatuses(results[0])[rpmmd.RepoCheckRepomdSignature])
+
+	results = rpm.CheckRepositories(repos, "x86_64", "35", false)
+	require.False(t, results[0].Healthy)
+	require.Equal(t, rpmmd.RepoHealthError, checkStatuses(results[0])[rpmmd.RepoCheckGPGKey])
+}
@@ -0,0 +1,306 @@
+package rpmmd
+
+import (
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// compatibleArches lists the package architectures which can be installed on
+// each base architecture, in addition to "noarch".
+var compatibleArches = map[string][]string{
+	"x86_64":  {"x86_64", "i686", "i586", "i486", "i386", "athlon"},
+	"aarch64": {"aarch64"},
+	"ppc64le": {"ppc64le"},
+	"s390x":   {"s390x"},
+}
+
+// archFilter returns whether packages of an architecture can be installed on
+// `arch`.
+func archFilter(arch string) func(string) bool {
+	allowed := map[string]bool{"noarch": true, arch: true}
+	for _, a := range compatibleArches[arch] {
+		allowed[a] = true
+	}
+	return func(a string) bool {
+		return allowed[a]
+	}
+}
+
+type primaryPackageXML struct {
+	Type    string `xml:"type,attr"`
+	Name    string `xml:"name"`
+	Arch    string `xml:"arch"`
+	Version struct {
+		Epoch   string `xml:"epoch,attr"`
+		Version string `xml:"ver,attr"`
+		Release string `xml:"rel,attr"`
+	} `xml:"version"`
+	Summary     string `xml:"summary"`
+	Description string `xml:"description"`
+	URL         string `xml:"url"`
+	Time        struct {
+		Build int64 `xml:"build,attr"`
+	} `xml:"time"`
+	Format struct {
+		License string `xml:"license"`
+	} `xml:"format"`
+}
+
+// ParsePrimary parses the contents of a primary.xml file. It returns the
+// packages which can be installed on `arch`, skipping source packages.
+func ParsePrimary(r io.Reader, arch string) (PackageList, error) {
+	installable := archFilter(arch)
+
+	// decode one package at a time, primary.xml of large repositories is
+	// hundreds of megabytes
+	decoder := xml.NewDecoder(r)
+	var packages PackageList
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("error parsing primary.xml: %v", err)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "package" {
+			continue
+		}
+
+		var p primaryPackageXML
+		err = decoder.DecodeElement(&p, &start)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing primary.xml: %v", err)
+		}
+
+		if (p.Type != "" && p.Type != "rpm") || !installable(p.Arch) {
+			continue
+		}
+
+		var epoch uint64
+		if p.Version.Epoch != "" {
+			epoch, err = strconv.ParseUint(p.Version.Epoch, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid epoch for package %s: %s", p.Name, p.Version.Epoch)
+			}
+		}
+
+		packages = append(packages, Package{
+			Name:        p.Name,
+			Summary:     p.Summary,
+			Description: p.Description,
+			URL:         p.URL,
+			Epoch:       uint(epoch),
+			Version:     p.Version.Version,
+			Release:     p.Version.Release,
+			Arch:        p.Arch,
+			BuildTime:   time.Unix(p.Time.Build, 0).UTC(),
+			License:     p.Format.License,
+		})
+	}
+
+	return packages, nil
+}
+
+// FetchMetadata returns the packages available in `repos` for `arch`, sorted
+// by name, and the checksums of the repositories' metadata.
+//
+// Unlike depsolving, this doesn't go through dnf-json: the primary metadata
+// of each repository is downloaded and parsed directly, and kept in an index
+// in the cache directory, keyed by the checksum of the repository's
+// repomd.xml. The index is reused as long as the repository doesn't change.
+// Repositories with modular metadata are dumped by dnf-json instead, because
+// only dnf can tell which module streams are enabled for modulePlatformID.
+func (r *rpmmdImpl) FetchMetadata(repos []RepoConfig, modulePlatformID, arch, releasever string) (PackageList, map[string]string, error) {
+	var packages PackageList
+	checksums := make(map[string]string)
+
+	for _, repo := range repos {
+		client, err := r.repoHTTPClient(repo, arch, releasever)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		repoPackages, checksum, err := r.fetchRepoPackages(client, repo, modulePlatformID, arch, releasever)
+		if err != nil {
+			return nil, nil, &RepositoryError{fmt.Sprintf("error fetching metadata of repository %s: %v", repo.Name, err)}
+		}
+
+		packages = append(packages, repoPackages...)
+		checksums[repo.Name] = checksum
+	}
+
+	packages.sort()
+
+	return packages, checksums, nil
+}
+
+// fetchRepoPackages returns the packages of `repo` and the checksum of its
+// repomd.xml, in the same format as dnf-json.
+func (r *rpmmdImpl) fetchRepoPackages(client *http.Client, repo RepoConfig, modulePlatformID, arch, releasever string) (PackageList, string, error) {
+	baseURL, err := resolveBaseURL(client, repo, arch, releasever)
+	if err != nil {
+		return nil, "", err
+	}
+
+	rawRepomd, repomd, err := fetchRepomd(client, baseURL)
+	if err != nil {
+		return nil, "", err
+	}
+	checksum := fmt.Sprintf("sha256:%x", sha256.Sum256(rawRepomd))
+
+	if repomd.location("modules") != "" {
+		return r.dumpRepoPackages(repo, modulePlatformID, arch, releasever)
+	}
+
+	index := r.packageIndex(repo, arch, checksum)
+	packages, err := index.read()
+	if err == nil {
+		return packages, checksum, nil
+	}
+
+	location := repomd.location("primary")
+	if location == "" {
+		return nil, "", fmt.Errorf("repository has no primary metadata")
+	}
+
+	primary, err := openRepoFile(client, baseURL, location)
+	if err != nil {
+		return nil, "", err
+	}
+	defer primary.Close()
+
+	packages, err = ParsePrimary(primary, arch)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := index.write(packages); err != nil {
+		// not fatal, the metadata will be fetched again next time
+		logrus.Warnf("Error writing package index for repository %s: %v", repo.Name, err)
+	}
+
+	return packages, checksum, nil
+}
+
+// dumpRepoPackages returns the packages of `repo` which are available with
+// the module streams enabled by default for modulePlatformID, and the
+// checksum of its repomd.xml, as reported by dnf-json.
+func (r *rpmmdImpl) dumpRepoPackages(repo RepoConfig, modulePlatformID, arch, releasever string) (PackageList, string, error) {
+	dnfRepo, err := repo.toDNFRepoConfig(r, 0, arch, releasever)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var arguments = struct {
+		Repos            []dnfRepoConfig `json:"repos"`
+		CacheDir         string          `json:"cachedir"`
+		ModulePlatformID string          `json:"module_platform_id"`
+		Arch             string          `json:"arch"`
+	}{[]dnfRepoConfig{dnfRepo}, r.CacheDir, modulePlatformID, arch}
+	var reply struct {
+		Checksums map[string]string `json:"checksums"`
+		Packages  PackageList       `json:"packages"`
+	}
+
+	err = runDNF("dump", arguments, &reply)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return reply.Packages, reply.Checksums[dnfRepo.ID], nil
+}
+
+// packageIndex is the on-disk index of the packages of a repository, for one
+// version of its metadata. It lives in a directory per repository and
+// architecture, which contains at most one index.
+type packageIndex struct {
+	dir  string
+	path string
+}
+
+func (r *rpmmdImpl) packageIndex(repo RepoConfig, arch, checksum string) packageIndex {
+	// like dnf, identify the repository by its url
+	url := repo.BaseURL
+	if repo.Metalink != "" {
+		url = repo.Metalink
+	} else if repo.MirrorList != "" {
+		url = repo.MirrorList
+	}
+	dir := filepath.Join(r.CacheDir, "index", fmt.Sprintf("%x", sha256.Sum256([]byte(url+"\x00"+arch)))[:16])
+
+	return packageIndex{
+		dir:  dir,
+		path: filepath.Join(dir, fmt.Sprintf("%x.gob", sha256.Sum256([]byte(checksum)))),
+	}
+}
+
+func (i packageIndex) read() (PackageList, error) {
+	f, err := os.Open(i.path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var packages PackageList
+	err = gob.NewDecoder(f).Decode(&packages)
+	if err != nil {
+		return nil, err
+	}
+	return packages, nil
+}
+
+// write atomically replaces the repository's index with one containing
+// `packages`.
+func (i packageIndex) write(packages PackageList) error {
+	err := os.MkdirAll(i.dir, 0700)
+	if err != nil {
+		return err
+	}
+
+	f, err := ioutil.TempFile(i.dir, "index-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	err = gob.NewEncoder(f).Encode(packages)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	err = f.Close()
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(f.Name(), i.path)
+	if err != nil {
+		return err
+	}
+
+	// remove indexes of previous versions of the metadata
+	stale, err := filepath.Glob(filepath.Join(i.dir, "*.gob"))
+	if err != nil {
+		return err
+	}
+	for _, p := range stale {
+		if p != i.path {
+			os.Remove(p)
+		}
+	}
+
+	return nil
+}
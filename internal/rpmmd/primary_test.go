@@ -0,0 +1,183 @@
+package rpmmd_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/osbuild-composer/internal/rpmmd"
+)
+
+const testPrimary = `<?xml version="1.0" encoding="UTF-8"?>
+<metadata xmlns="http://linux.duke.edu/metadata/common" xmlns:rpm="http://linux.duke.edu/metadata/rpm" packages="5">
+<package type="rpm">
+  <name>tmux</name>
+  <arch>x86_64</arch>
+  <version epoch="0" ver="3.2a" rel="2.fc35"/>
+  <checksum type="sha256" pkgid="YES">abc</checksum>
+  <summary>A terminal multiplexer</summary>
+  <description>tmux is a "terminal multiplexer."</description>
+  <url>https://tmux.github.io/</url>
+  <time file="1628000000" build="1627000000"/>
+  <format>
+    <rpm:license>ISC and BSD</rpm:license>
+    <rpm:requires><rpm:entry name="libc.so.6()(64bit)"/></rpm:requires>
+  </format>
+</package>
+<package type="rpm">
+  <name>bash</name>
+  <arch>x86_64</arch>
+  <version epoch="0" ver="5.1.8" rel="2.fc35"/>
+  <summary>The GNU Bourne Again shell</summary>
+  <time build="1626000000"/>
+  <format><rpm:license>GPLv3+</rpm:license></format>
+</package>
+<package type="rpm">
+  <name>bash</name>
+  <arch>src</arch>
+  <version epoch="0" ver="5.1.8" rel="2.fc35"/>
+</package>
+<package type="rpm">
+  <name>bash-completion</name>
+  <arch>noarch</arch>
+  <version epoch="1" ver="2.11" rel="3.fc35"/>
+</package>
+<package type="rpm">
+  <name>glibc</name>
+  <arch>aarch64</arch>
+  <version epoch="0" ver="2.34" rel="7.fc35"/>
+</package>
+</metadata>
+`
+
+func TestParsePrimary(t *testing.T) {
+	packages, err := rpmmd.ParsePrimary(strings.NewReader(testPrimary), "x86_64")
+	require.NoError(t, err)
+	require.Len(t, packages, 3)
+
+	require.Equal(t, rpmmd.Package{
+		Name:        "tmux",
+		Summary:     "A terminal multiplexer",
+		Description: `tmux is a "terminal multiplexer."`,
+		URL:         "https://tmux.github.io/",
+		Version:     "3.2a",
+		Release:     "2.fc35",
+		Arch:        "x86_64",
+		BuildTime:   time.Unix(1627000000, 0).UTC(),
+		License:     "ISC and BSD",
+	}, packages[0])
+	require.Equal(t, uint(1), packages[2].Epoch)
+
+	packages, err = rpmmd.ParsePrimary(strings.NewReader(testPrimary), "aarch64")
+	require.NoError(t, err)
+	require.Len(t, packages, 2)
+
+	_, err = rpmmd.ParsePrimary(strings.NewReader("<metadata><package>"), "x86_64")
+	require.Error(t, err)
+}
+
+func TestFetchMetadata(t *testing.T) {
+	var gzPrimary bytes.Buffer
+	gz := gzip.NewWriter(&gzPrimary)
+	_, err := gz.Write([]byte(testPrimary))
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+
+	var zstPrimary bytes.Buffer
+	zst, err := zstd.NewWriter(&zstPrimary)
+	require.NoError(t, err)
+	_, err = zst.Write([]byte(`<metadata><package type="rpm"><name>zsh</name><arch>x86_64</arch><version ver="5.8" rel="8.fc35"/></package></metadata>`))
+	require.NoError(t, err)
+	require.NoError(t, zst.Close())
+
+	primaryRequests := 0
+	repomd := `<repomd><data type="primary"><location href="repodata/abc-primary.xml.gz"/></data></repomd>`
+	mux := http.NewServeMux()
+	mux.HandleFunc("/gz/x86_64/repodata/repomd.xml", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(repomd))
+	})
+	mux.HandleFunc("/gz/x86_64/repodata/abc-primary.xml.gz", func(w http.ResponseWriter, r *http.Request) {
+		primaryRequests++
+		_, _ = w.Write(gzPrimary.Bytes())
+	})
+	mux.HandleFunc("/gz/x86_64/repodata/def-primary.xml.gz", func(w http.ResponseWriter, r *http.Request) {
+		primaryRequests++
+		_, _ = w.Write(gzPrimary.Bytes())
+	})
+	mux.HandleFunc("/zst/repodata/repomd.xml", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<repomd><data type="primary"><location href="repodata/primary.xml.zst"/></data></repomd>`))
+	})
+	mux.HandleFunc("/zst/repodata/primary.xml.zst", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(zstPrimary.Bytes())
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	rpm := rpmmd.NewRPMMD(t.TempDir())
+	repos := []rpmmd.RepoConfig{
+		{Name: "gz", BaseURL: server.URL + "/gz/$basearch"},
+		{Name: "zst", BaseURL: server.URL + "/zst/"},
+	}
+
+	packages, checksums, err := rpm.FetchMetadata(repos, "platform:f35", "x86_64", "35")
+	require.NoError(t, err)
+	require.Equal(t, []string{"bash", "bash-completion", "tmux", "zsh"}, packageNames(packages))
+	require.Len(t, checksums, 2)
+	require.True(t, strings.HasPrefix(checksums["gz"], "sha256:"))
+	require.Equal(t, 1, primaryRequests)
+
+	// the index is used as long as repomd.xml doesn't change
+	_, checksums2, err := rpm.FetchMetadata(repos, "platform:f35", "x86_64", "35")
+	require.NoError(t, err)
+	require.Equal(t, checksums, checksums2)
+	require.Equal(t, 1, primaryRequests)
+
+	repomd = `<repomd><data type="primary"><location href="repodata/def-primary.xml.gz"/></data></repomd>`
+	_, checksums2, err = rpm.FetchMetadata(repos, "platform:f35", "x86_64", "35")
+	require.NoError(t, err)
+	require.NotEqual(t, checksums["gz"], checksums2["gz"])
+	require.Equal(t, 2, primaryRequests)
+
+	_, _, err = rpm.FetchMetadata([]rpmmd.RepoConfig{{Name: "missing", BaseURL: server.URL + "/missing/"}}, "platform:f35", "x86_64", "35")
+	require.Error(t, err)
+	require.IsType(t, &rpmmd.RepositoryError{}, err)
+}
+
+func TestPackageListSearch(t *testing.T) {
+	packages := rpmmd.PackageList{
+		{Name: "bash"}, {Name: "bash-completion"}, {Name: "glibc"}, {Name: "glibc-common"}, {Name: "tmux"},
+	}
+
+	for _, list := range []rpmmd.PackageList{packages, {packages[4], packages[1], packages[3], packages[0], packages[2]}} {
+		found, err := list.Search("bash")
+		require.NoError(t, err)
+		require.Equal(t, []string{"bash"}, packageNames(found))
+
+		found, err = list.Search("glibc*", "tmux")
+		require.NoError(t, err)
+		require.Equal(t, []string{"glibc", "glibc-common", "tmux"}, packageNames(found))
+
+		found, err = list.Search("*-c*")
+		require.NoError(t, err)
+		require.Equal(t, []string{"bash-completion", "glibc-common"}, packageNames(found))
+
+		found, err = list.Search("zsh")
+		require.NoError(t, err)
+		require.Empty(t, found)
+	}
+}
+
+func packageNames(packages rpmmd.PackageList) []string {
+	names := []string{}
+	for _, p := range packages {
+		names = append(names, p.Name)
+	}
+	return names
+}
@@ -0,0 +1,249 @@
+package rpmmd
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+type repomdXML struct {
+	Data []struct {
+		Type     string `xml:"type,attr"`
+		Location struct {
+			Href string `xml:"href,attr"`
+		} `xml:"location"`
+		Timestamp string `xml:"timestamp"`
+	} `xml:"data"`
+}
+
+// location returns the location of the metadata file of type `dataType`,
+// relative to the repository's base url, or an empty string if the
+// repository doesn't contain this type of metadata.
+func (r *repomdXML) location(dataType string) string {
+	for _, d := range r.Data {
+		if d.Type == dataType {
+			return d.Location.Href
+		}
+	}
+	return ""
+}
+
+type metalinkXML struct {
+	URLs []struct {
+		Protocol string `xml:"protocol,attr"`
+		URL      string `xml:",chardata"`
+	} `xml:"files>file>resources>url"`
+}
+
+// repoHTTPClient returns an http client set up to access `repo`, with the
+// same certificates and proxy as dnf-json would use for it.
+func (r *rpmmdImpl) repoHTTPClient(repo RepoConfig, arch, releasever string) (*http.Client, error) {
+	dnfRepo, err := repo.toDNFRepoConfig(r, 0, arch, releasever)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: dnfRepo.IgnoreSSL, // #nosec G402 -- explicitly requested by the repository configuration
+	}
+
+	if dnfRepo.SSLClientCert != "" {
+		cert, err := tls.LoadX509KeyPair(dnfRepo.SSLClientCert, dnfRepo.SSLClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("error loading client certificate of repository %s: %v", repo.Name, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if dnfRepo.SSLCACert != "" {
+		caCert, err := ioutil.ReadFile(dnfRepo.SSLCACert)
+		if err != nil {
+			return nil, fmt.Errorf("error loading CA certificate of repository %s: %v", repo.Name, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("invalid CA certificate for repository %s", repo.Name)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	proxy := http.ProxyFromEnvironment
+	if dnfRepo.Proxy != "" {
+		proxyURL, err := url.Parse(dnfRepo.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy for repository %s: %v", repo.Name, err)
+		}
+		proxy = http.ProxyURL(proxyURL)
+	}
+
+	return &http.Client{
+		Timeout: 5 * time.Minute,
+		Transport: &http.Transport{
+			Proxy:           proxy,
+			TLSClientConfig: tlsConfig,
+		},
+	}, nil
+}
+
+// fetchRepomd downloads and parses the repomd.xml of the repository at
+// `baseURL`. It returns its raw contents, too.
+func fetchRepomd(client *http.Client, baseURL string) ([]byte, *repomdXML, error) {
+	body, err := httpGet(client, baseURL+"repodata/repomd.xml")
+	if err != nil {
+		return nil, nil, err
+	}
+	defer body.Close()
+
+	data, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var repomd repomdXML
+	err = xml.NewDecoder(bytes.NewReader(data)).Decode(&repomd)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error parsing repomd.xml: %v", err)
+	}
+
+	return data, &repomd, nil
+}
+
+// openRepoFile downloads the metadata file at `location` of the repository at
+// `baseURL`, decompressing it based on its extension.
+func openRepoFile(client *http.Client, baseURL, location string) (io.ReadCloser, error) {
+	body, err := httpGet(client, baseURL+location)
+	if err != nil {
+		return nil, err
+	}
+
+	var reader io.Reader
+	var decompressor io.Closer
+	switch {
+	case strings.HasSuffix(location, ".gz"):
+		gz, err := gzip.NewReader(body)
+		if err != nil {
+			body.Close()
+			return nil, err
+		}
+		reader, decompressor = gz, gz
+	case strings.HasSuffix(location, ".zst"):
+		zst, err := zstd.NewReader(body)
+		if err != nil {
+			body.Close()
+			return nil, err
+		}
+		rc := zst.IOReadCloser()
+		reader, decompressor = rc, rc
+	case strings.HasSuffix(location, ".bz2"):
+		reader = bzip2.NewReader(body)
+	case strings.HasSuffix(location, ".xml"):
+		reader = body
+	default:
+		body.Close()
+		return nil, fmt.Errorf("unsupported compression of %s", location)
+	}
+
+	return &repoFile{reader, decompressor, body}, nil
+}
+
+// repoFile is a decompressed metadata file. Closing it closes both the
+// decompressor and the underlying http response.
+type repoFile struct {
+	io.Reader
+	decompressor io.Closer
+	body         io.Closer
+}
+
+func (f *repoFile) Close() error {
+	if f.decompressor != nil {
+		f.decompressor.Close()
+	}
+	return f.body.Close()
+}
+
+// resolveBaseURL returns the base url of `repo`, ending in a slash. For
+// repositories which are configured with a mirrorlist or metalink, the first
+// mirror is used.
+func resolveBaseURL(client *http.Client, repo RepoConfig, arch, releasever string) (string, error) {
+	expand := func(s string) string {
+		return strings.NewReplacer("$basearch", arch, "$arch", arch, "$releasever", releasever).Replace(s)
+	}
+
+	var baseURL string
+	switch {
+	case repo.BaseURL != "":
+		baseURL = expand(repo.BaseURL)
+
+	case repo.MirrorList != "":
+		body, err := httpGet(client, expand(repo.MirrorList))
+		if err != nil {
+			return "", err
+		}
+		defer body.Close()
+
+		scanner := bufio.NewScanner(body)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line != "" && !strings.HasPrefix(line, "#") {
+				baseURL = line
+				break
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return "", err
+		}
+
+	case repo.Metalink != "":
+		body, err := httpGet(client, expand(repo.Metalink))
+		if err != nil {
+			return "", err
+		}
+		defer body.Close()
+
+		var metalink metalinkXML
+		err = xml.NewDecoder(body).Decode(&metalink)
+		if err != nil {
+			return "", fmt.Errorf("error parsing metalink: %v", err)
+		}
+		for _, u := range metalink.URLs {
+			if u.Protocol == "https" || u.Protocol == "http" {
+				baseURL = strings.TrimSuffix(strings.TrimSpace(u.URL), "repodata/repomd.xml")
+				break
+			}
+		}
+	}
+
+	if baseURL == "" {
+		return "", fmt.Errorf("no usable base url")
+	}
+
+	if !strings.HasSuffix(baseURL, "/") {
+		baseURL += "/"
+	}
+	return baseURL, nil
+}
+
+func httpGet(client *http.Client, url string) (io.ReadCloser, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status fetching %s: %s", url, resp.Status)
+	}
+	return resp.Body, nil
+}
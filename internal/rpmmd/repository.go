@@ -9,6 +9,7 @@ import (
 	"log"
 	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"sort"
@@ -43,6 +44,7 @@ type dnfRepoConfig struct {
 	SSLClientKey   string `json:"sslclientkey,omitempty"`
 	SSLClientCert  string `json:"sslclientcert,omitempty"`
 	MetadataExpire string `json:"metadata_expire,omitempty"`
+	Proxy          string `json:"proxy,omitempty"`
 }
 
 type RepoConfig struct {
@@ -56,6 +58,42 @@ type RepoConfig struct {
 	MetadataExpire string
 	RHSM           bool
 	ImageTypeTags  []string
+
+	// Proxy is the url of the HTTP proxy to access the repository through.
+	Proxy string
+}
+
+// ValidateProxy returns an error if the proxy of the repository is invalid.
+// Credentials are not allowed in the proxy url, because repositories are
+// stored and passed to workers as they are.
+func (repo RepoConfig) ValidateProxy() error {
+	if repo.Proxy != "" {
+		u, err := url.Parse(repo.Proxy)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("invalid proxy url")
+		}
+		if u.User != nil {
+			return fmt.Errorf("credentials are not supported in proxy urls")
+		}
+	}
+	return nil
+}
+
+// CommonProxy returns the proxy which is used by the repositories in `repos`
+// which have one. osbuild only supports one proxy per build, so all of them
+// must use the same one.
+func CommonProxy(repos []RepoConfig) (string, error) {
+	var proxy string
+	for _, repo := range repos {
+		if repo.Proxy == "" {
+			continue
+		}
+		if proxy != "" && proxy != repo.Proxy {
+			return "", fmt.Errorf("repository %q uses a different proxy than other repositories of the build", repo.Name)
+		}
+		proxy = repo.Proxy
+	}
+	return proxy, nil
 }
 
 type DistrosRepoConfigs map[string]map[string][]RepoConfig
@@ -106,17 +144,24 @@ func (pkg Package) ToPackageInfo() PackageInfo {
 }
 
 // The inputs to depsolve, a set of packages to include and a set of
-// packages to exclude.
+// packages to exclude. EnabledModules lists the module streams, as
+// "name:stream", that are enabled before resolving the packages. When
+// Explain is set, the resulting PackageSpecs record why each package was
+// pulled in.
 type PackageSet struct {
-	Include []string
-	Exclude []string
+	Include        []string
+	Exclude        []string
+	EnabledModules []string
+	Explain        bool
 }
 
-// Append the Include and Exclude package list from another PackageSet and
-// return the result.
+// Append the Include, Exclude, and EnabledModules lists from another
+// PackageSet and return the result.
 func (ps PackageSet) Append(other PackageSet) PackageSet {
 	ps.Include = append(ps.Include, other.Include...)
 	ps.Exclude = append(ps.Exclude, other.Exclude...)
+	ps.EnabledModules = append(ps.EnabledModules, other.EnabledModules...)
+	ps.Explain = ps.Explain || other.Explain
 	return ps
 }
 
@@ -131,6 +176,16 @@ type PackageSpec struct {
 	Checksum       string `json:"checksum,omitempty"`
 	Secrets        string `json:"secrets,omitempty"`
 	CheckGPG       bool   `json:"check_gpg,omitempty"`
+	IgnoreSSL      bool   `json:"ignore_ssl,omitempty"`
+
+	// Only set when depsolving with PackageSet.Explain. Reason is why dnf
+	// installs the package ("user" for packages that were requested,
+	// "dependency" for the others), RequiredBy the names of the packages
+	// in the same set that require it, and RequestedBy the package specs
+	// that matched it.
+	Reason      string   `json:"reason,omitempty"`
+	RequiredBy  []string `json:"required_by,omitempty"`
+	RequestedBy []string `json:"requested_by,omitempty"`
 }
 
 type dnfPackageSpec struct {
@@ -144,6 +199,10 @@ type dnfPackageSpec struct {
 	RemoteLocation string `json:"remote_location,omitempty"`
 	Checksum       string `json:"checksum,omitempty"`
 	Secrets        string `json:"secrets,omitempty"`
+
+	Reason      string   `json:"reason,omitempty"`
+	RequiredBy  []string `json:"required_by,omitempty"`
+	RequestedBy []string `json:"requested_by,omitempty"`
 }
 
 type PackageSource struct {
@@ -184,6 +243,15 @@ type RPMMD interface {
 	// or repositories, and platform ID for modularity. It returns a list of all packages (with solved
 	// dependencies) that will be installed into the system.
 	Depsolve(packageSet PackageSet, repos []RepoConfig, modulePlatformID, arch, releasever string) ([]PackageSpec, map[string]string, error)
+
+	// FetchUpdateInfo returns the update advisories published in the
+	// repositories' updateinfo metadata.
+	FetchUpdateInfo(repos []RepoConfig, arch, releasever string) ([]Advisory, error)
+
+	// CheckRepositories probes the repositories and reports problems with
+	// each of them, such as being unreachable or having invalid GPG keys.
+	// GPG keys are read from local files only if localKeys is set.
+	CheckRepositories(repos []RepoConfig, arch, releasever string, localKeys bool) []RepoHealth
 }
 
 type DNFError struct {
@@ -415,6 +483,7 @@ func (repo RepoConfig) toDNFRepoConfig(rpmmd *rpmmdImpl, i int, arch, releasever
 		GPGKey:         repo.GPGKey,
 		IgnoreSSL:      repo.IgnoreSSL,
 		MetadataExpire: repo.MetadataExpire,
+		Proxy:          repo.Proxy,
 	}
 	if repo.RHSM {
 		if rpmmd.subscriptions == nil {
@@ -431,39 +500,6 @@ func (repo RepoConfig) toDNFRepoConfig(rpmmd *rpmmdImpl, i int, arch, releasever
 	return dnfRepo, nil
 }
 
-func (r *rpmmdImpl) FetchMetadata(repos []RepoConfig, modulePlatformID, arch, releasever string) (PackageList, map[string]string, error) {
-	var dnfRepoConfigs []dnfRepoConfig
-	for i, repo := range repos {
-		dnfRepo, err := repo.toDNFRepoConfig(r, i, arch, releasever)
-		if err != nil {
-			return nil, nil, err
-		}
-		dnfRepoConfigs = append(dnfRepoConfigs, dnfRepo)
-	}
-
-	var arguments = struct {
-		Repos            []dnfRepoConfig `json:"repos"`
-		CacheDir         string          `json:"cachedir"`
-		ModulePlatformID string          `json:"module_platform_id"`
-		Arch             string          `json:"arch"`
-	}{dnfRepoConfigs, r.CacheDir, modulePlatformID, arch}
-	var reply struct {
-		Checksums map[string]string `json:"checksums"`
-		Packages  PackageList       `json:"packages"`
-	}
-
-	err := runDNF("dump", arguments, &reply)
-
-	sort.Slice(reply.Packages, func(i, j int) bool {
-		return reply.Packages[i].Name < reply.Packages[j].Name
-	})
-	checksums := make(map[string]string)
-	for i, repo := range repos {
-		checksums[repo.Name] = reply.Checksums[strconv.Itoa(i)]
-	}
-	return reply.Packages, checksums, err
-}
-
 func (r *rpmmdImpl) Depsolve(packageSet PackageSet, repos []RepoConfig, modulePlatformID, arch, releasever string) ([]PackageSpec, map[string]string, error) {
 	var dnfRepoConfigs []dnfRepoConfig
 
@@ -476,13 +512,15 @@ func (r *rpmmdImpl) Depsolve(packageSet PackageSet, repos []RepoConfig, modulePl
 	}
 
 	var arguments = struct {
-		PackageSpecs     []string        `json:"package-specs"`
-		ExcludSpecs      []string        `json:"exclude-specs"`
-		Repos            []dnfRepoConfig `json:"repos"`
-		CacheDir         string          `json:"cachedir"`
-		ModulePlatformID string          `json:"module_platform_id"`
-		Arch             string          `json:"arch"`
-	}{packageSet.Include, packageSet.Exclude, dnfRepoConfigs, r.CacheDir, modulePlatformID, arch}
+		PackageSpecs      []string        `json:"package-specs"`
+		ExcludSpecs       []string        `json:"exclude-specs"`
+		ModuleEnableSpecs []string        `json:"module-enable-specs,omitempty"`
+		Explain           bool            `json:"explain,omitempty"`
+		Repos             []dnfRepoConfig `json:"repos"`
+		CacheDir          string          `json:"cachedir"`
+		ModulePlatformID  string          `json:"module_platform_id"`
+		Arch              string          `json:"arch"`
+	}{packageSet.Include, packageSet.Exclude, packageSet.EnabledModules, packageSet.Explain, dnfRepoConfigs, r.CacheDir, modulePlatformID, arch}
 	var reply struct {
 		Checksums    map[string]string `json:"checksums"`
 		Dependencies []dnfPackageSpec  `json:"dependencies"`
@@ -505,18 +543,56 @@ func (r *rpmmdImpl) Depsolve(packageSet PackageSet, repos []RepoConfig, modulePl
 		dependencies[i].RemoteLocation = dep.RemoteLocation
 		dependencies[i].Checksum = dep.Checksum
 		dependencies[i].CheckGPG = repo.CheckGPG
+		dependencies[i].IgnoreSSL = repo.IgnoreSSL
+		dependencies[i].Reason = dep.Reason
+		dependencies[i].RequiredBy = dep.RequiredBy
+		dependencies[i].RequestedBy = dep.RequestedBy
 		if repo.RHSM {
 			dependencies[i].Secrets = "org.osbuild.rhsm"
 		}
 	}
 
-	return dependencies, reply.Checksums, err
+	// dnf-json identifies repositories by their index, return the checksums
+	// by name like FetchMetadata() does
+	checksums := make(map[string]string, len(reply.Checksums))
+	for repoID, checksum := range reply.Checksums {
+		id, err := strconv.Atoi(repoID)
+		if err != nil || id < 0 || id >= len(repos) || repos[id].Name == "" {
+			checksums[repoID] = checksum
+			continue
+		}
+		checksums[repos[id].Name] = checksum
+	}
+
+	return dependencies, checksums, err
 }
 
+// Search returns the packages whose name matches any of `globPatterns`,
+// sorted by name. Patterns without wildcards, or whose only wildcard is a
+// trailing "*", are looked up with a binary search when the list is sorted,
+// which is the case for lists returned by FetchMetadata.
 func (packages PackageList) Search(globPatterns ...string) (PackageList, error) {
+	sorted := sort.SliceIsSorted(packages, func(i, j int) bool {
+		return packages[i].Name < packages[j].Name
+	})
+
 	var globs []glob.Glob
+	found := make(map[int]bool)
 
 	for _, globPattern := range globPatterns {
+		prefix := strings.TrimSuffix(globPattern, "*")
+		if sorted && !strings.ContainsAny(prefix, "*?[]{}\\") {
+			isPrefix := prefix != globPattern
+			for i := sort.Search(len(packages), func(i int) bool { return packages[i].Name >= prefix }); i < len(packages); i++ {
+				name := packages[i].Name
+				if (isPrefix && !strings.HasPrefix(name, prefix)) || (!isPrefix && name != prefix) {
+					break
+				}
+				found[i] = true
+			}
+			continue
+		}
+
 		g, err := glob.Compile(globPattern)
 		if err != nil {
 			return nil, err
@@ -527,7 +603,11 @@ func (packages PackageList) Search(globPatterns ...string) (PackageList, error)
 
 	var foundPackages PackageList
 
-	for _, pkg := range packages {
+	for i, pkg := range packages {
+		if found[i] {
+			foundPackages = append(foundPackages, pkg)
+			continue
+		}
 		for _, g := range globs {
 			if g.Match(pkg.Name) {
 				foundPackages = append(foundPackages, pkg)
@@ -536,13 +616,19 @@ func (packages PackageList) Search(globPatterns ...string) (PackageList, error)
 		}
 	}
 
-	sort.Slice(packages, func(i, j int) bool {
-		return packages[i].Name < packages[j].Name
-	})
+	foundPackages.sort()
 
 	return foundPackages, nil
 }
 
+// sort sorts the packages by name, keeping the order of packages with the
+// same name.
+func (packages PackageList) sort() {
+	sort.SliceStable(packages, func(i, j int) bool {
+		return packages[i].Name < packages[j].Name
+	})
+}
+
 func (packages PackageList) ToPackageInfos() []PackageInfo {
 	resultsNames := make(map[string]int)
 	var results []PackageInfo
@@ -0,0 +1,224 @@
+package rpmmd
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Advisory is an update advisory (also known as erratum) as published in the
+// updateinfo.xml metadata of a repository.
+type Advisory struct {
+	ID       string            `json:"id"`
+	Type     string            `json:"type"` // security, bugfix, enhancement, or newpackage
+	Severity string            `json:"severity,omitempty"`
+	Title    string            `json:"title"`
+	Issued   string            `json:"issued,omitempty"`
+	CVEs     []string          `json:"cves,omitempty"`
+	Packages []AdvisoryPackage `json:"packages"`
+}
+
+// AdvisoryPackage is a package which contains the fix for an advisory.
+type AdvisoryPackage struct {
+	Name    string `json:"name"`
+	Epoch   uint   `json:"epoch"`
+	Version string `json:"version"`
+	Release string `json:"release"`
+	Arch    string `json:"arch"`
+}
+
+// AdvisoryReport relates a set of packages to the advisories affecting them.
+type AdvisoryReport struct {
+	// Fixed contains the advisories whose fixes are part of the packages.
+	Fixed []Advisory `json:"fixed"`
+
+	// Unpatched contains the advisories for which at least one of the
+	// packages is older than the version containing the fix.
+	Unpatched []Advisory `json:"unpatched"`
+}
+
+// UnpatchedCVEs returns the sorted list of CVEs referenced by unpatched
+// advisories.
+func (r AdvisoryReport) UnpatchedCVEs() []string {
+	seen := make(map[string]bool)
+	cves := []string{}
+	for _, a := range r.Unpatched {
+		for _, cve := range a.CVEs {
+			if !seen[cve] {
+				seen[cve] = true
+				cves = append(cves, cve)
+			}
+		}
+	}
+	sort.Strings(cves)
+	return cves
+}
+
+// NewAdvisoryReport checks which of `advisories` apply to `packages`. An
+// advisory applies when it lists a package with the same name and
+// architecture as one of `packages`. It is fixed when all of those are at
+// least as new as the version listed in the advisory, and unpatched
+// otherwise.
+func NewAdvisoryReport(advisories []Advisory, packages []PackageSpec) AdvisoryReport {
+	type nameArch struct {
+		name, arch string
+	}
+	installed := make(map[nameArch]PackageSpec)
+	for _, p := range packages {
+		installed[nameArch{p.Name, p.Arch}] = p
+	}
+
+	report := AdvisoryReport{
+		Fixed:     []Advisory{},
+		Unpatched: []Advisory{},
+	}
+	for _, a := range advisories {
+		applies := false
+		fixed := true
+		for _, ap := range a.Packages {
+			p, ok := installed[nameArch{ap.Name, ap.Arch}]
+			if !ok {
+				continue
+			}
+			applies = true
+			if CompareEVR(p.Epoch, p.Version, p.Release, ap.Epoch, ap.Version, ap.Release) < 0 {
+				fixed = false
+			}
+		}
+
+		if !applies {
+			continue
+		}
+		if fixed {
+			report.Fixed = append(report.Fixed, a)
+		} else {
+			report.Unpatched = append(report.Unpatched, a)
+		}
+	}
+
+	sortAdvisories := func(advisories []Advisory) {
+		sort.Slice(advisories, func(i, j int) bool {
+			return advisories[i].ID < advisories[j].ID
+		})
+	}
+	sortAdvisories(report.Fixed)
+	sortAdvisories(report.Unpatched)
+
+	return report
+}
+
+type updateInfoXML struct {
+	Updates []struct {
+		Type     string `xml:"type,attr"`
+		ID       string `xml:"id"`
+		Title    string `xml:"title"`
+		Severity string `xml:"severity"`
+		Issued   struct {
+			Date string `xml:"date,attr"`
+		} `xml:"issued"`
+		References []struct {
+			ID   string `xml:"id,attr"`
+			Type string `xml:"type,attr"`
+		} `xml:"references>reference"`
+		Packages []struct {
+			Name    string `xml:"name,attr"`
+			Epoch   string `xml:"epoch,attr"`
+			Version string `xml:"version,attr"`
+			Release string `xml:"release,attr"`
+			Arch    string `xml:"arch,attr"`
+		} `xml:"pkglist>collection>package"`
+	} `xml:"update"`
+}
+
+// ParseUpdateInfo parses the contents of an updateinfo.xml file.
+func ParseUpdateInfo(r io.Reader) ([]Advisory, error) {
+	var updateInfo updateInfoXML
+	err := xml.NewDecoder(r).Decode(&updateInfo)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing updateinfo: %v", err)
+	}
+
+	advisories := make([]Advisory, 0, len(updateInfo.Updates))
+	for _, u := range updateInfo.Updates {
+		a := Advisory{
+			ID:       u.ID,
+			Type:     u.Type,
+			Severity: u.Severity,
+			Title:    strings.TrimSpace(u.Title),
+			Issued:   u.Issued.Date,
+			Packages: []AdvisoryPackage{},
+		}
+		for _, ref := range u.References {
+			if ref.Type == "cve" && ref.ID != "" {
+				a.CVEs = append(a.CVEs, ref.ID)
+			}
+		}
+		for _, p := range u.Packages {
+			var epoch uint64
+			if p.Epoch != "" {
+				epoch, err = strconv.ParseUint(p.Epoch, 10, 32)
+				if err != nil {
+					return nil, fmt.Errorf("invalid epoch for package %s in advisory %s: %s", p.Name, u.ID, p.Epoch)
+				}
+			}
+			a.Packages = append(a.Packages, AdvisoryPackage{
+				Name:    p.Name,
+				Epoch:   uint(epoch),
+				Version: p.Version,
+				Release: p.Release,
+				Arch:    p.Arch,
+			})
+		}
+		advisories = append(advisories, a)
+	}
+
+	return advisories, nil
+}
+
+// FetchUpdateInfo downloads and parses the updateinfo metadata of all `repos`.
+// Repositories without updateinfo are skipped.
+func (r *rpmmdImpl) FetchUpdateInfo(repos []RepoConfig, arch, releasever string) ([]Advisory, error) {
+	var advisories []Advisory
+	for _, repo := range repos {
+		client, err := r.repoHTTPClient(repo, arch, releasever)
+		if err != nil {
+			return nil, err
+		}
+
+		a, err := fetchRepoUpdateInfo(client, repo, arch, releasever)
+		if err != nil {
+			return nil, &RepositoryError{fmt.Sprintf("error fetching updateinfo of repository %s: %v", repo.Name, err)}
+		}
+		advisories = append(advisories, a...)
+	}
+	return advisories, nil
+}
+
+func fetchRepoUpdateInfo(client *http.Client, repo RepoConfig, arch, releasever string) ([]Advisory, error) {
+	baseURL, err := resolveBaseURL(client, repo, arch, releasever)
+	if err != nil {
+		return nil, err
+	}
+
+	_, repomd, err := fetchRepomd(client, baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	location := repomd.location("updateinfo")
+	if location == "" {
+		return nil, nil
+	}
+
+	updateInfo, err := openRepoFile(client, baseURL, location)
+	if err != nil {
+		return nil, err
+	}
+	defer updateInfo.Close()
+
+	return ParseUpdateInfo(updateInfo)
+}
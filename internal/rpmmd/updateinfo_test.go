@@ -0,0 +1,190 @@
+package rpmmd_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/osbuild-composer/internal/rpmmd"
+)
+
+const testUpdateInfo = `<?xml version="1.0" encoding="UTF-8"?>
+<updates>
+  <update from="security@redhat.com" status="final" type="security" version="2">
+    <id>RHSA-2021:1234</id>
+    <title>Important: bash security update</title>
+    <issued date="2021-05-18 00:00:00"/>
+    <severity>Important</severity>
+    <references>
+      <reference href="https://access.redhat.com/errata/RHSA-2021:1234" id="RHSA-2021:1234" type="self"/>
+      <reference href="https://access.redhat.com/security/cve/CVE-2021-0001" id="CVE-2021-0001" type="cve"/>
+      <reference href="https://access.redhat.com/security/cve/CVE-2021-0002" id="CVE-2021-0002" type="cve"/>
+    </references>
+    <pkglist>
+      <collection short="rhel-8">
+        <name>rhel-8</name>
+        <package name="bash" version="4.4.20" release="2.el8" epoch="0" arch="x86_64" src="bash-4.4.20-2.el8.src.rpm">
+          <filename>bash-4.4.20-2.el8.x86_64.rpm</filename>
+        </package>
+        <package name="bash-doc" version="4.4.20" release="2.el8" epoch="0" arch="x86_64" src="bash-4.4.20-2.el8.src.rpm">
+          <filename>bash-doc-4.4.20-2.el8.x86_64.rpm</filename>
+        </package>
+      </collection>
+    </pkglist>
+  </update>
+  <update from="release@redhat.com" status="final" type="bugfix" version="2">
+    <id>RHBA-2021:0100</id>
+    <title>tzdata bug fix update</title>
+    <issued date="2021-01-10 00:00:00"/>
+    <pkglist>
+      <collection short="rhel-8">
+        <package name="tzdata" version="2021a" release="1.el8" epoch="0" arch="noarch" src="tzdata-2021a-1.el8.src.rpm"/>
+      </collection>
+    </pkglist>
+  </update>
+</updates>
+`
+
+func TestCompareEVR(t *testing.T) {
+	cases := []struct {
+		a, b     string
+		expected int
+	}{
+		{"1.0", "1.0", 0},
+		{"1.0", "2.0", -1},
+		{"2.0", "1.0", 1},
+		{"2.0.1", "2.0", 1},
+		{"1.10", "1.9", 1},
+		{"1.010", "1.10", 0},
+		{"1.a", "1.b", -1},
+		{"1.0a", "1.0", 1},
+		{"1.0", "1.a", 1},
+		{"5.1", "5.1.fc30", -1},
+		{"1.0~rc1", "1.0", -1},
+		{"1.0~rc1", "1.0~rc2", -1},
+		{"1.0^git1", "1.0", 1},
+		{"1.0^git1", "1.0.1", -1},
+		{"1.0_1", "1.0.1", 0},
+	}
+
+	for _, c := range cases {
+		assert.Equalf(t, c.expected, rpmmd.CompareEVR(0, c.a, "1", 0, c.b, "1"), "%s <=> %s", c.a, c.b)
+		assert.Equalf(t, c.expected, rpmmd.CompareEVR(0, "1", c.a, 0, "1", c.b), "release %s <=> %s", c.a, c.b)
+	}
+
+	// epoch wins over version
+	assert.Equal(t, 1, rpmmd.CompareEVR(1, "1.0", "1", 0, "2.0", "1"))
+	assert.Equal(t, -1, rpmmd.CompareEVR(0, "2.0", "1", 1, "1.0", "1"))
+}
+
+func TestParseUpdateInfo(t *testing.T) {
+	advisories, err := rpmmd.ParseUpdateInfo(strings.NewReader(testUpdateInfo))
+	require.NoError(t, err)
+
+	require.Equal(t, []rpmmd.Advisory{
+		{
+			ID:       "RHSA-2021:1234",
+			Type:     "security",
+			Severity: "Important",
+			Title:    "Important: bash security update",
+			Issued:   "2021-05-18 00:00:00",
+			CVEs:     []string{"CVE-2021-0001", "CVE-2021-0002"},
+			Packages: []rpmmd.AdvisoryPackage{
+				{Name: "bash", Version: "4.4.20", Release: "2.el8", Arch: "x86_64"},
+				{Name: "bash-doc", Version: "4.4.20", Release: "2.el8", Arch: "x86_64"},
+			},
+		},
+		{
+			ID:     "RHBA-2021:0100",
+			Type:   "bugfix",
+			Title:  "tzdata bug fix update",
+			Issued: "2021-01-10 00:00:00",
+			Packages: []rpmmd.AdvisoryPackage{
+				{Name: "tzdata", Version: "2021a", Release: "1.el8", Arch: "noarch"},
+			},
+		},
+	}, advisories)
+
+	_, err = rpmmd.ParseUpdateInfo(strings.NewReader("<updates><update>"))
+	require.Error(t, err)
+}
+
+func TestNewAdvisoryReport(t *testing.T) {
+	advisories, err := rpmmd.ParseUpdateInfo(strings.NewReader(testUpdateInfo))
+	require.NoError(t, err)
+
+	// an old image: bash is older than the fix, tzdata is up to date
+	report := rpmmd.NewAdvisoryReport(advisories, []rpmmd.PackageSpec{
+		{Name: "bash", Version: "4.4.19", Release: "14.el8", Arch: "x86_64"},
+		{Name: "tzdata", Version: "2021b", Release: "1.el8", Arch: "noarch"},
+		{Name: "kernel", Version: "4.18.0", Release: "305.el8", Arch: "x86_64"},
+	})
+	require.Len(t, report.Fixed, 1)
+	require.Equal(t, "RHBA-2021:0100", report.Fixed[0].ID)
+	require.Len(t, report.Unpatched, 1)
+	require.Equal(t, "RHSA-2021:1234", report.Unpatched[0].ID)
+	require.Equal(t, []string{"CVE-2021-0001", "CVE-2021-0002"}, report.UnpatchedCVEs())
+
+	// a current image
+	report = rpmmd.NewAdvisoryReport(advisories, []rpmmd.PackageSpec{
+		{Name: "bash", Version: "4.4.20", Release: "2.el8", Arch: "x86_64"},
+	})
+	require.Len(t, report.Fixed, 1)
+	require.Equal(t, "RHSA-2021:1234", report.Fixed[0].ID)
+	require.Empty(t, report.Unpatched)
+	require.Empty(t, report.UnpatchedCVEs())
+}
+
+func TestFetchUpdateInfo(t *testing.T) {
+	var updateInfo bytes.Buffer
+	gz := gzip.NewWriter(&updateInfo)
+	_, err := gz.Write([]byte(testUpdateInfo))
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/with/x86_64/repodata/repomd.xml", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<repomd xmlns="http://linux.duke.edu/metadata/repo">
+  <data type="primary"><location href="repodata/abc-primary.xml.gz"/></data>
+  <data type="updateinfo"><location href="repodata/def-updateinfo.xml.gz"/></data>
+</repomd>`))
+	})
+	mux.HandleFunc("/with/x86_64/repodata/def-updateinfo.xml.gz", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(updateInfo.Bytes())
+	})
+	mux.HandleFunc("/without/repodata/repomd.xml", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<repomd><data type="primary"><location href="repodata/abc-primary.xml.gz"/></data></repomd>`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	mirrorlist := http.NewServeMux()
+	mirrorlist.HandleFunc("/mirrorlist", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("# mirrors\n" + server.URL + "/with/x86_64\n"))
+	})
+	mirrorServer := httptest.NewServer(mirrorlist)
+	defer mirrorServer.Close()
+
+	rpm := rpmmd.NewRPMMD(t.TempDir())
+
+	advisories, err := rpm.FetchUpdateInfo([]rpmmd.RepoConfig{
+		{Name: "with", BaseURL: server.URL + "/with/$basearch"},
+		{Name: "without", BaseURL: server.URL + "/without/"},
+		{Name: "mirrored", MirrorList: mirrorServer.URL + "/mirrorlist"},
+	}, "x86_64", "8")
+	require.NoError(t, err)
+	require.Len(t, advisories, 4)
+	require.Equal(t, "RHSA-2021:1234", advisories[0].ID)
+	require.Equal(t, "RHSA-2021:1234", advisories[2].ID)
+
+	_, err = rpm.FetchUpdateInfo([]rpmmd.RepoConfig{
+		{Name: "missing", BaseURL: server.URL + "/missing/"},
+	}, "x86_64", "8")
+	require.Error(t, err)
+}
@@ -0,0 +1,362 @@
+// Package sbom generates software bills of materials for images, in the SPDX
+// and CycloneDX JSON formats.
+//
+// The bill of materials is assembled from the metadata of the rpm stages of an
+// image's payload pipelines, which lists the packages that were actually
+// installed, and is enriched with the checksums and download locations of the
+// depsolved package specs.
+package sbom
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/osbuild/osbuild-composer/internal/rpmmd"
+)
+
+const (
+	// SPDXArtifact is the name of the job artifact containing the SPDX
+	// document of an image.
+	SPDXArtifact = "sbom.spdx.json"
+
+	// CycloneDXArtifact is the name of the job artifact containing the
+	// CycloneDX document of an image.
+	CycloneDXArtifact = "sbom.cdx.json"
+
+	toolName = "osbuild-composer"
+)
+
+// Package is a package contained in an image.
+type Package struct {
+	Name      string
+	Epoch     uint
+	Version   string
+	Release   string
+	Arch      string
+	Checksum  string // in the form "sha256:<hex>", if known
+	Location  string // download url, if known
+	Signature string // armored PGP or GPG signature, if known
+}
+
+// EVR returns the package's version as [epoch:]version-release.
+func (p Package) EVR() string {
+	if p.Epoch == 0 {
+		return fmt.Sprintf("%s-%s", p.Version, p.Release)
+	}
+	return fmt.Sprintf("%d:%s-%s", p.Epoch, p.Version, p.Release)
+}
+
+// purl returns the package url of the package, as specified by
+// https://github.com/package-url/purl-spec. `namespace` is the vendor of the
+// distribution, for example "fedora" or "redhat".
+func (p Package) purl(namespace string) string {
+	qualifiers := url.Values{}
+	qualifiers.Set("arch", p.Arch)
+	if p.Epoch != 0 {
+		qualifiers.Set("epoch", fmt.Sprint(p.Epoch))
+	}
+	name := url.PathEscape(p.Name)
+	if namespace != "" {
+		name = url.PathEscape(namespace) + "/" + name
+	}
+	return fmt.Sprintf("pkg:rpm/%s@%s-%s?%s", name, url.PathEscape(p.Version), url.PathEscape(p.Release), qualifiers.Encode())
+}
+
+// checksum splits the package's checksum into its algorithm and value.
+func (p Package) checksum() (string, string, bool) {
+	parts := strings.SplitN(p.Checksum, ":", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+	return strings.ToLower(parts[0]), parts[1], true
+}
+
+// NewPackages returns the packages of an image. `rpms` are the packages listed
+// in the metadata of the image's rpm stages, and `specs` are the depsolved
+// package specs of the image, which are used to add checksums and download
+// locations. When no rpm stage metadata is available, the package specs are
+// used directly. The result is sorted by name, version and architecture.
+func NewPackages(rpms []rpmmd.RPM, specs []rpmmd.PackageSpec) []Package {
+	packages := make([]Package, 0, len(rpms))
+
+	if len(rpms) == 0 {
+		for _, spec := range specs {
+			packages = append(packages, Package{
+				Name:     spec.Name,
+				Epoch:    spec.Epoch,
+				Version:  spec.Version,
+				Release:  spec.Release,
+				Arch:     spec.Arch,
+				Checksum: spec.Checksum,
+				Location: spec.RemoteLocation,
+			})
+		}
+	} else {
+		specsByNEVRA := make(map[string]rpmmd.PackageSpec)
+		for _, spec := range specs {
+			specsByNEVRA[fmt.Sprintf("%s-%d:%s-%s.%s", spec.Name, spec.Epoch, spec.Version, spec.Release, spec.Arch)] = spec
+		}
+
+		for _, rpm := range rpms {
+			var epoch uint64
+			if rpm.Epoch != nil {
+				// rpm reports a missing epoch as "(none)", treat it as 0
+				epoch, _ = strconv.ParseUint(*rpm.Epoch, 10, 32)
+			}
+			p := Package{
+				Name:    rpm.Name,
+				Epoch:   uint(epoch),
+				Version: rpm.Version,
+				Release: rpm.Release,
+				Arch:    rpm.Arch,
+			}
+			if rpm.Signature != nil {
+				p.Signature = *rpm.Signature
+			}
+			if spec, ok := specsByNEVRA[fmt.Sprintf("%s-%d:%s-%s.%s", p.Name, p.Epoch, p.Version, p.Release, p.Arch)]; ok {
+				p.Checksum = spec.Checksum
+				p.Location = spec.RemoteLocation
+			}
+			packages = append(packages, p)
+		}
+	}
+
+	sort.Slice(packages, func(i, j int) bool {
+		if packages[i].Name != packages[j].Name {
+			return packages[i].Name < packages[j].Name
+		}
+		if c := rpmmd.CompareEVR(packages[i].Epoch, packages[i].Version, packages[i].Release, packages[j].Epoch, packages[j].Version, packages[j].Release); c != 0 {
+			return c < 0
+		}
+		return packages[i].Arch < packages[j].Arch
+	})
+
+	return packages
+}
+
+// Document describes an image for which a bill of materials is generated.
+type Document struct {
+	// Name of the image, for example the image file name
+	Name string
+
+	// Distro is the name of the image's distribution, for example "fedora-35"
+	Distro string
+
+	Packages []Package
+
+	Created time.Time
+}
+
+// namespace returns the vendor of the document's distribution, as used in
+// package urls.
+func (d Document) namespace() string {
+	name := strings.SplitN(d.Distro, "-", 2)[0]
+	switch name {
+	case "rhel":
+		return "redhat"
+	default:
+		return name
+	}
+}
+
+type spdxDocument struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	Packages          []spdxPackage      `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	SPDXID           string            `json:"SPDXID"`
+	Name             string            `json:"name"`
+	VersionInfo      string            `json:"versionInfo"`
+	Supplier         string            `json:"supplier"`
+	DownloadLocation string            `json:"downloadLocation"`
+	FilesAnalyzed    bool              `json:"filesAnalyzed"`
+	LicenseConcluded string            `json:"licenseConcluded"`
+	LicenseDeclared  string            `json:"licenseDeclared"`
+	CopyrightText    string            `json:"copyrightText"`
+	Checksums        []spdxChecksum    `json:"checksums,omitempty"`
+	ExternalRefs     []spdxExternalRef `json:"externalRefs"`
+}
+
+type spdxChecksum struct {
+	Algorithm     string `json:"algorithm"`
+	ChecksumValue string `json:"checksumValue"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+// SPDX returns the document in the SPDX 2.2 JSON format.
+func (d Document) SPDX() ([]byte, error) {
+	const noAssertion = "NOASSERTION"
+
+	doc := spdxDocument{
+		SPDXVersion:       "SPDX-2.2",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              d.Name,
+		DocumentNamespace: fmt.Sprintf("https://osbuild.org/spdxdocs/%s-%s", url.PathEscape(d.Name), uuid.New()),
+		CreationInfo: spdxCreationInfo{
+			Created:  d.Created.UTC().Format(time.RFC3339),
+			Creators: []string{"Tool: " + toolName},
+		},
+		Packages:      make([]spdxPackage, 0, len(d.Packages)),
+		Relationships: make([]spdxRelationship, 0, len(d.Packages)),
+	}
+
+	for i, p := range d.Packages {
+		id := fmt.Sprintf("SPDXRef-Package-%d", i)
+		pkg := spdxPackage{
+			SPDXID:           id,
+			Name:             p.Name,
+			VersionInfo:      p.EVR(),
+			Supplier:         noAssertion,
+			DownloadLocation: noAssertion,
+			LicenseConcluded: noAssertion,
+			LicenseDeclared:  noAssertion,
+			CopyrightText:    noAssertion,
+			ExternalRefs: []spdxExternalRef{
+				{
+					ReferenceCategory: "PACKAGE_MANAGER",
+					ReferenceType:     "purl",
+					ReferenceLocator:  p.purl(d.namespace()),
+				},
+			},
+		}
+		if p.Location != "" {
+			pkg.DownloadLocation = p.Location
+		}
+		if alg, value, ok := p.checksum(); ok {
+			pkg.Checksums = []spdxChecksum{{Algorithm: strings.ToUpper(alg), ChecksumValue: value}}
+		}
+		doc.Packages = append(doc.Packages, pkg)
+		doc.Relationships = append(doc.Relationships, spdxRelationship{
+			SPDXElementID:      doc.SPDXID,
+			RelationshipType:   "DESCRIBES",
+			RelatedSPDXElement: id,
+		})
+	}
+
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+type cdxDocument struct {
+	BOMFormat    string         `json:"bomFormat"`
+	SpecVersion  string         `json:"specVersion"`
+	SerialNumber string         `json:"serialNumber"`
+	Version      int            `json:"version"`
+	Metadata     cdxMetadata    `json:"metadata"`
+	Components   []cdxComponent `json:"components"`
+}
+
+type cdxMetadata struct {
+	Timestamp string       `json:"timestamp"`
+	Tools     []cdxTool    `json:"tools"`
+	Component cdxComponent `json:"component"`
+}
+
+type cdxTool struct {
+	Vendor string `json:"vendor"`
+	Name   string `json:"name"`
+}
+
+type cdxComponent struct {
+	Type       string        `json:"type"`
+	BOMRef     string        `json:"bom-ref,omitempty"`
+	Name       string        `json:"name"`
+	Version    string        `json:"version,omitempty"`
+	PURL       string        `json:"purl,omitempty"`
+	Hashes     []cdxHash     `json:"hashes,omitempty"`
+	Properties []cdxProperty `json:"properties,omitempty"`
+}
+
+type cdxHash struct {
+	Algorithm string `json:"alg"`
+	Content   string `json:"content"`
+}
+
+type cdxProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// cdxHashAlgorithms maps the checksum types used in package specs to the
+// names of hash algorithms in CycloneDX.
+var cdxHashAlgorithms = map[string]string{
+	"md5":    "MD5",
+	"sha1":   "SHA-1",
+	"sha256": "SHA-256",
+	"sha384": "SHA-384",
+	"sha512": "SHA-512",
+}
+
+// CycloneDX returns the document in the CycloneDX 1.4 JSON format.
+func (d Document) CycloneDX() ([]byte, error) {
+	doc := cdxDocument{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  "1.4",
+		SerialNumber: "urn:uuid:" + uuid.New().String(),
+		Version:      1,
+		Metadata: cdxMetadata{
+			Timestamp: d.Created.UTC().Format(time.RFC3339),
+			Tools:     []cdxTool{{Vendor: "osbuild", Name: toolName}},
+			Component: cdxComponent{
+				Type: "operating-system",
+				Name: d.Name,
+			},
+		},
+		Components: make([]cdxComponent, 0, len(d.Packages)),
+	}
+
+	for _, p := range d.Packages {
+		purl := p.purl(d.namespace())
+		component := cdxComponent{
+			Type:    "library",
+			BOMRef:  purl,
+			Name:    p.Name,
+			Version: p.EVR(),
+			PURL:    purl,
+		}
+		if alg, value, ok := p.checksum(); ok {
+			if cdxAlg, ok := cdxHashAlgorithms[alg]; ok {
+				component.Hashes = []cdxHash{{Algorithm: cdxAlg, Content: value}}
+			}
+		}
+		if p.Location != "" {
+			component.Properties = append(component.Properties, cdxProperty{Name: "osbuild:download_location", Value: p.Location})
+		}
+		if p.Signature != "" {
+			component.Properties = append(component.Properties, cdxProperty{Name: "osbuild:signature", Value: p.Signature})
+		}
+		doc.Components = append(doc.Components, component)
+	}
+
+	return json.MarshalIndent(doc, "", "  ")
+}
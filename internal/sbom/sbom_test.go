@@ -0,0 +1,95 @@
+package sbom
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/osbuild-composer/internal/common"
+	"github.com/osbuild/osbuild-composer/internal/rpmmd"
+)
+
+func testDocument() Document {
+	rpms := []rpmmd.RPM{
+		{Type: "rpm", Name: "kernel", Epoch: nil, Version: "5.14.10", Release: "300.fc35", Arch: "x86_64", Signature: common.StringToPtr("RSA/SHA256, key ID 9867c58f")},
+		{Type: "rpm", Name: "bash", Epoch: common.StringToPtr("(none)"), Version: "5.1.8", Release: "2.fc35", Arch: "x86_64"},
+		{Type: "rpm", Name: "dbus", Epoch: common.StringToPtr("1"), Version: "1.12.20", Release: "5.fc35", Arch: "x86_64"},
+	}
+	specs := []rpmmd.PackageSpec{
+		{Name: "bash", Version: "5.1.8", Release: "2.fc35", Arch: "x86_64", Checksum: "sha256:abc", RemoteLocation: "https://example.com/bash.rpm"},
+		{Name: "dbus", Epoch: 1, Version: "1.12.20", Release: "5.fc35", Arch: "x86_64", Checksum: "sha256:def", Secrets: "org.osbuild.rhsm"},
+		{Name: "unused", Version: "1", Release: "1", Arch: "noarch", Checksum: "sha256:123"},
+	}
+
+	return Document{
+		Name:     "disk.qcow2",
+		Distro:   "fedora-35",
+		Packages: NewPackages(rpms, specs),
+		Created:  time.Date(2021, 10, 20, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestNewPackages(t *testing.T) {
+	doc := testDocument()
+	require.Equal(t, []Package{
+		{Name: "bash", Version: "5.1.8", Release: "2.fc35", Arch: "x86_64", Checksum: "sha256:abc", Location: "https://example.com/bash.rpm"},
+		{Name: "dbus", Epoch: 1, Version: "1.12.20", Release: "5.fc35", Arch: "x86_64", Checksum: "sha256:def"},
+		{Name: "kernel", Version: "5.14.10", Release: "300.fc35", Arch: "x86_64", Signature: "RSA/SHA256, key ID 9867c58f"},
+	}, doc.Packages)
+
+	// without rpm metadata, the package specs are used
+	packages := NewPackages(nil, []rpmmd.PackageSpec{{Name: "b", Version: "1", Release: "1", Arch: "noarch"}, {Name: "a", Version: "1", Release: "1", Arch: "noarch"}})
+	require.Len(t, packages, 2)
+	require.Equal(t, "a", packages[0].Name)
+}
+
+func TestSPDX(t *testing.T) {
+	data, err := testDocument().SPDX()
+	require.NoError(t, err)
+
+	var doc spdxDocument
+	require.NoError(t, json.Unmarshal(data, &doc))
+	require.Equal(t, "SPDX-2.2", doc.SPDXVersion)
+	require.Equal(t, "disk.qcow2", doc.Name)
+	require.Equal(t, "2021-10-20T12:00:00Z", doc.CreationInfo.Created)
+	require.Len(t, doc.Packages, 3)
+	require.Len(t, doc.Relationships, 3)
+
+	dbus := doc.Packages[1]
+	require.Equal(t, "dbus", dbus.Name)
+	require.Equal(t, "1:1.12.20-5.fc35", dbus.VersionInfo)
+	require.Equal(t, "NOASSERTION", dbus.DownloadLocation)
+	require.Equal(t, []spdxChecksum{{Algorithm: "SHA256", ChecksumValue: "def"}}, dbus.Checksums)
+	require.Equal(t, "pkg:rpm/fedora/dbus@1.12.20-5.fc35?arch=x86_64&epoch=1", dbus.ExternalRefs[0].ReferenceLocator)
+	require.Equal(t, "https://example.com/bash.rpm", doc.Packages[0].DownloadLocation)
+	require.Nil(t, doc.Packages[2].Checksums)
+
+	require.Equal(t, spdxRelationship{"SPDXRef-DOCUMENT", "DESCRIBES", dbus.SPDXID}, doc.Relationships[1])
+}
+
+func TestCycloneDX(t *testing.T) {
+	document := testDocument()
+	document.Distro = "rhel-85"
+	data, err := document.CycloneDX()
+	require.NoError(t, err)
+
+	var doc cdxDocument
+	require.NoError(t, json.Unmarshal(data, &doc))
+	require.Equal(t, "CycloneDX", doc.BOMFormat)
+	require.Equal(t, "1.4", doc.SpecVersion)
+	require.Equal(t, "disk.qcow2", doc.Metadata.Component.Name)
+	require.Len(t, doc.Components, 3)
+
+	require.Equal(t, cdxComponent{
+		Type:       "library",
+		BOMRef:     "pkg:rpm/redhat/bash@5.1.8-2.fc35?arch=x86_64",
+		Name:       "bash",
+		Version:    "5.1.8-2.fc35",
+		PURL:       "pkg:rpm/redhat/bash@5.1.8-2.fc35?arch=x86_64",
+		Hashes:     []cdxHash{{Algorithm: "SHA-256", Content: "abc"}},
+		Properties: []cdxProperty{{Name: "osbuild:download_location", Value: "https://example.com/bash.rpm"}},
+	}, doc.Components[0])
+	require.Equal(t, []cdxProperty{{Name: "osbuild:signature", Value: "RSA/SHA256, key ID 9867c58f"}}, doc.Components[2].Properties)
+}
@@ -76,6 +76,12 @@ type Compose struct {
 	Blueprint  *blueprint.Blueprint
 	ImageBuild ImageBuild
 	Packages   []rpmmd.PackageSpec
+	// RepoChecksums are the metadata checksums of the repositories the
+	// compose's packages were depsolved against
+	RepoChecksums map[string]string
+	// Owner is the name of the user who started the compose, if the weldr
+	// API runs in multi-user mode
+	Owner string
 }
 
 // DeepCopy creates a copy of the Compose structure
@@ -87,10 +93,19 @@ func (c *Compose) DeepCopy() Compose {
 	}
 	pkgs := make([]rpmmd.PackageSpec, len(c.Packages))
 	copy(pkgs, c.Packages)
+	var checksums map[string]string
+	if len(c.RepoChecksums) > 0 {
+		checksums = make(map[string]string, len(c.RepoChecksums))
+		for repo, checksum := range c.RepoChecksums {
+			checksums[repo] = checksum
+		}
+	}
 
 	return Compose{
-		Blueprint:  newBpPtr,
-		ImageBuild: c.ImageBuild.DeepCopy(),
-		Packages:   pkgs,
+		Blueprint:     newBpPtr,
+		ImageBuild:    c.ImageBuild.DeepCopy(),
+		Packages:      pkgs,
+		RepoChecksums: checksums,
+		Owner:         c.Owner,
 	}
 }
@@ -21,10 +21,15 @@ type storeV0 struct {
 	Sources    sourcesV0    `json:"sources"`
 	Changes    changesV0    `json:"changes"`
 	Commits    commitsV0    `json:"commits"`
+	Schedules  schedulesV0  `json:"schedules,omitempty"`
+	Snapshots  snapshotsV0  `json:"snapshots,omitempty"`
+
+	BlueprintOwners blueprintOwnersV0 `json:"blueprint_owners,omitempty"`
 }
 
 type blueprintsV0 map[string]blueprint.Blueprint
 type workspaceV0 map[string]blueprint.Blueprint
+type blueprintOwnersV0 map[string]string
 
 // A Compose represent the task of building a set of images from a single blueprint.
 // It contains all the information necessary to generate the inputs for the job, as
@@ -33,6 +38,9 @@ type composeV0 struct {
 	Blueprint   *blueprint.Blueprint `json:"blueprint"`
 	ImageBuilds []imageBuildV0       `json:"image_builds"`
 	Packages    []rpmmd.PackageSpec  `json:"packages"`
+	// Checksums of the repositories' metadata at depsolve time
+	RepoChecksums map[string]string `json:"repo_checksums,omitempty"`
+	Owner         string            `json:"owner,omitempty"`
 }
 
 type composesV0 map[uuid.UUID]composeV0
@@ -64,6 +72,9 @@ type sourceV0 struct {
 	System   bool     `json:"system"`
 	Distros  []string `json:"distros"`
 	RHSM     bool     `json:"rhsm"`
+	Proxy    string   `json:"proxy,omitempty"`
+
+	Owner string `json:"owner,omitempty"`
 }
 
 type sourcesV0 map[string]sourceV0
@@ -80,6 +91,35 @@ type changesV0 map[string]map[string]changeV0
 
 type commitsV0 map[string][]string
 
+type scheduleV0 struct {
+	Name      string               `json:"name"`
+	Cron      string               `json:"cron"`
+	Blueprint string               `json:"blueprint"`
+	ImageType string               `json:"image_type"`
+	Size      uint64               `json:"size"`
+	Upload    *target.Target       `json:"upload,omitempty"`
+	Created   time.Time            `json:"created"`
+	LastRun   time.Time            `json:"last_run"`
+	History   []scheduledComposeV0 `json:"history"`
+	Owner     string               `json:"owner,omitempty"`
+}
+
+type scheduledComposeV0 struct {
+	Triggered time.Time `json:"triggered"`
+	ComposeID uuid.UUID `json:"compose_id,omitempty"`
+	Error     string    `json:"error,omitempty"`
+}
+
+type schedulesV0 map[uuid.UUID]scheduleV0
+
+type snapshotV0 struct {
+	Distro    string            `json:"distro"`
+	Checksums map[string]string `json:"checksums"`
+	Created   time.Time         `json:"created"`
+}
+
+type snapshotsV0 map[string]snapshotV0
+
 func newBlueprintsFromV0(blueprintsStruct blueprintsV0) map[string]blueprint.Blueprint {
 	blueprints := make(map[string]blueprint.Blueprint)
 	for name, blueprint := range blueprintsStruct {
@@ -157,9 +197,11 @@ func newComposeFromV0(composeStruct composeV0, arch distro.Arch) (Compose, error
 	copy(pkgs, composeStruct.Packages)
 
 	return Compose{
-		Blueprint:  &bp,
-		ImageBuild: ib,
-		Packages:   pkgs,
+		Blueprint:     &bp,
+		ImageBuild:    ib,
+		Packages:      pkgs,
+		RepoChecksums: composeStruct.RepoChecksums,
+		Owner:         composeStruct.Owner,
 	}, nil
 }
 
@@ -234,6 +276,44 @@ func newCommitsFromV0(commitsMapStruct commitsV0, changesMapStruct changesV0) ma
 	return commitsMap
 }
 
+func newSchedulesFromV0(schedulesStruct schedulesV0) map[uuid.UUID]Schedule {
+	schedules := make(map[uuid.UUID]Schedule)
+	for id, s := range schedulesStruct {
+		history := make([]ScheduledCompose, 0, len(s.History))
+		for _, entry := range s.History {
+			history = append(history, ScheduledCompose(entry))
+		}
+		schedules[id] = Schedule{
+			ID:        id,
+			Name:      s.Name,
+			Cron:      s.Cron,
+			Blueprint: s.Blueprint,
+			ImageType: s.ImageType,
+			Size:      s.Size,
+			Upload:    s.Upload,
+			Created:   s.Created,
+			LastRun:   s.LastRun,
+			History:   history,
+			Owner:     s.Owner,
+		}
+	}
+	return schedules
+}
+
+func newSnapshotsFromV0(snapshotsStruct snapshotsV0) map[string]RepoSnapshot {
+	snapshots := make(map[string]RepoSnapshot)
+	for name, s := range snapshotsStruct {
+		snapshot := RepoSnapshot{
+			Name:      name,
+			Distro:    s.Distro,
+			Checksums: s.Checksums,
+			Created:   s.Created,
+		}
+		snapshots[name] = snapshot.DeepCopy()
+	}
+	return snapshots
+}
+
 func newStoreFromV0(storeStruct storeV0, arch distro.Arch, log *log.Logger) *Store {
 	return &Store{
 		blueprints:        newBlueprintsFromV0(storeStruct.Blueprints),
@@ -242,9 +322,20 @@ func newStoreFromV0(storeStruct storeV0, arch distro.Arch, log *log.Logger) *Sto
 		sources:           newSourceConfigsFromV0(storeStruct.Sources),
 		blueprintsChanges: newChangesFromV0(storeStruct.Changes),
 		blueprintsCommits: newCommitsFromV0(storeStruct.Commits, storeStruct.Changes),
+		schedules:         newSchedulesFromV0(storeStruct.Schedules),
+		snapshots:         newSnapshotsFromV0(storeStruct.Snapshots),
+		blueprintOwners:   newBlueprintOwnersFromV0(storeStruct.BlueprintOwners),
 	}
 }
 
+func newBlueprintOwnersFromV0(ownersStruct blueprintOwnersV0) map[string]string {
+	owners := make(map[string]string)
+	for name, owner := range ownersStruct {
+		owners[name] = owner
+	}
+	return owners
+}
+
 func newBlueprintsV0(blueprints map[string]blueprint.Blueprint) blueprintsV0 {
 	blueprintsStruct := make(blueprintsV0)
 	for name, blueprint := range blueprints {
@@ -283,7 +374,9 @@ func newComposeV0(compose Compose) composeV0 {
 				QueueStatus: compose.ImageBuild.QueueStatus,
 			},
 		},
-		Packages: pkgs,
+		Packages:      pkgs,
+		RepoChecksums: compose.RepoChecksums,
+		Owner:         compose.Owner,
 	}
 }
 
@@ -328,6 +421,42 @@ func newCommitsV0(commits map[string][]string) commitsV0 {
 	return commitsStruct
 }
 
+func newSchedulesV0(schedules map[uuid.UUID]Schedule) schedulesV0 {
+	schedulesStruct := make(schedulesV0)
+	for id, s := range schedules {
+		history := make([]scheduledComposeV0, 0, len(s.History))
+		for _, entry := range s.History {
+			history = append(history, scheduledComposeV0(entry))
+		}
+		schedulesStruct[id] = scheduleV0{
+			Name:      s.Name,
+			Cron:      s.Cron,
+			Blueprint: s.Blueprint,
+			ImageType: s.ImageType,
+			Size:      s.Size,
+			Upload:    s.Upload,
+			Created:   s.Created,
+			LastRun:   s.LastRun,
+			History:   history,
+			Owner:     s.Owner,
+		}
+	}
+	return schedulesStruct
+}
+
+func newSnapshotsV0(snapshots map[string]RepoSnapshot) snapshotsV0 {
+	snapshotsStruct := make(snapshotsV0)
+	for name, s := range snapshots {
+		snapshot := s.DeepCopy()
+		snapshotsStruct[name] = snapshotV0{
+			Distro:    snapshot.Distro,
+			Checksums: snapshot.Checksums,
+			Created:   snapshot.Created,
+		}
+	}
+	return snapshotsStruct
+}
+
 func (store *Store) toStoreV0() *storeV0 {
 	return &storeV0{
 		Blueprints: newBlueprintsV0(store.blueprints),
@@ -336,7 +465,19 @@ func (store *Store) toStoreV0() *storeV0 {
 		Sources:    newSourcesV0(store.sources),
 		Changes:    newChangesV0(store.blueprintsChanges),
 		Commits:    newCommitsV0(store.blueprintsCommits),
+		Schedules:  newSchedulesV0(store.schedules),
+		Snapshots:  newSnapshotsV0(store.snapshots),
+
+		BlueprintOwners: newBlueprintOwnersV0(store.blueprintOwners),
+	}
+}
+
+func newBlueprintOwnersV0(owners map[string]string) blueprintOwnersV0 {
+	ownersStruct := make(blueprintOwnersV0)
+	for name, owner := range owners {
+		ownersStruct[name] = owner
 	}
+	return ownersStruct
 }
 
 var imageTypeCompatMapping = map[string]string{
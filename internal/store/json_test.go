@@ -164,6 +164,10 @@ func TestStore_toStoreV0(t *testing.T) {
 				Sources:    make(sourcesV0),
 				Changes:    make(changesV0),
 				Commits:    make(commitsV0),
+				Schedules:  make(schedulesV0),
+				Snapshots:  make(snapshotsV0),
+
+				BlueprintOwners: make(blueprintOwnersV0),
 			},
 		},
 	}
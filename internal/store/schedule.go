@@ -0,0 +1,131 @@
+package store
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/osbuild/osbuild-composer/internal/target"
+)
+
+// MaxScheduleHistory is the number of triggered composes which are kept in
+// the history of a schedule. Older entries are dropped.
+const MaxScheduleHistory = 100
+
+// A Schedule describes a compose which is started periodically, according to
+// a cron-like expression.
+type Schedule struct {
+	ID        uuid.UUID
+	Name      string
+	Cron      string
+	Blueprint string
+	ImageType string
+	Size      uint64
+	// Upload is a template for the upload target of each triggered
+	// compose. A copy with a new UUID is made for every compose.
+	Upload  *target.Target
+	Created time.Time
+	LastRun time.Time
+	History []ScheduledCompose
+	// Owner is the name of the user who created the schedule, if the
+	// weldr API runs in multi-user mode
+	Owner string
+}
+
+// ScheduledCompose is a single entry in the history of a schedule.
+type ScheduledCompose struct {
+	Triggered time.Time
+	ComposeID uuid.UUID
+	// Error is set when the compose could not be started.
+	Error string
+}
+
+// DeepCopy creates a copy of the Schedule structure
+func (s *Schedule) DeepCopy() Schedule {
+	var upload *target.Target
+	if s.Upload != nil {
+		t := *s.Upload
+		upload = &t
+	}
+	history := make([]ScheduledCompose, len(s.History))
+	copy(history, s.History)
+
+	return Schedule{
+		ID:        s.ID,
+		Name:      s.Name,
+		Cron:      s.Cron,
+		Blueprint: s.Blueprint,
+		ImageType: s.ImageType,
+		Size:      s.Size,
+		Upload:    upload,
+		Created:   s.Created,
+		LastRun:   s.LastRun,
+		History:   history,
+		Owner:     s.Owner,
+	}
+}
+
+// PushSchedule stores a new schedule or replaces an existing one with the
+// same ID.
+func (s *Store) PushSchedule(schedule Schedule) error {
+	return s.change(func() error {
+		s.schedules[schedule.ID] = schedule.DeepCopy()
+		return nil
+	})
+}
+
+// GetSchedule returns a copy of the schedule with the given ID.
+func (s *Store) GetSchedule(id uuid.UUID) (Schedule, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	schedule, exists := s.schedules[id]
+	if !exists {
+		return Schedule{}, false
+	}
+	return schedule.DeepCopy(), true
+}
+
+// GetAllSchedules returns a deep copy of all schedules present in this store
+// and returns them as a dictionary with schedule UUIDs as keys
+func (s *Store) GetAllSchedules() map[uuid.UUID]Schedule {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	schedules := make(map[uuid.UUID]Schedule)
+	for id, schedule := range s.schedules {
+		schedules[id] = schedule.DeepCopy()
+	}
+	return schedules
+}
+
+// DeleteSchedule removes the schedule with the given ID. The composes
+// triggered by it are not affected.
+func (s *Store) DeleteSchedule(id uuid.UUID) error {
+	return s.change(func() error {
+		if _, exists := s.schedules[id]; !exists {
+			return &NotFoundError{"schedule does not exist"}
+		}
+		delete(s.schedules, id)
+		return nil
+	})
+}
+
+// PushScheduledCompose records a triggered compose in the history of the
+// schedule with the given ID and sets its last run time.
+func (s *Store) PushScheduledCompose(id uuid.UUID, entry ScheduledCompose) error {
+	return s.change(func() error {
+		schedule, exists := s.schedules[id]
+		if !exists {
+			return &NotFoundError{"schedule does not exist"}
+		}
+
+		schedule.LastRun = entry.Triggered
+		schedule.History = append(schedule.History, entry)
+		if len(schedule.History) > MaxScheduleHistory {
+			schedule.History = schedule.History[len(schedule.History)-MaxScheduleHistory:]
+		}
+		s.schedules[id] = schedule
+		return nil
+	})
+}
@@ -0,0 +1,80 @@
+package store
+
+import (
+	"time"
+)
+
+// A RepoSnapshot records the metadata checksums of the repositories of a
+// distribution at one point in time. Composes which are pinned to a snapshot
+// verify that the repositories they are built from did not change since.
+type RepoSnapshot struct {
+	Name   string
+	Distro string
+	// Checksums maps repository ids to the checksums of their metadata, as
+	// returned by rpmmd.Depsolve()
+	Checksums map[string]string
+	Created   time.Time
+}
+
+// DeepCopy creates a copy of the RepoSnapshot structure
+func (s *RepoSnapshot) DeepCopy() RepoSnapshot {
+	checksums := make(map[string]string, len(s.Checksums))
+	for repo, checksum := range s.Checksums {
+		checksums[repo] = checksum
+	}
+
+	return RepoSnapshot{
+		Name:      s.Name,
+		Distro:    s.Distro,
+		Checksums: checksums,
+		Created:   s.Created,
+	}
+}
+
+// PushSnapshot stores a new snapshot. Snapshots are immutable: it is an
+// error to push a snapshot with the name of an existing one.
+func (s *Store) PushSnapshot(snapshot RepoSnapshot) error {
+	return s.change(func() error {
+		if _, exists := s.snapshots[snapshot.Name]; exists {
+			return &SnapshotExistsError{snapshot.Name}
+		}
+		s.snapshots[snapshot.Name] = snapshot.DeepCopy()
+		return nil
+	})
+}
+
+// GetSnapshot returns a copy of the snapshot with the given name.
+func (s *Store) GetSnapshot(name string) (RepoSnapshot, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	snapshot, exists := s.snapshots[name]
+	if !exists {
+		return RepoSnapshot{}, false
+	}
+	return snapshot.DeepCopy(), true
+}
+
+// GetAllSnapshots returns a deep copy of all snapshots present in this store
+// and returns them as a dictionary with snapshot names as keys
+func (s *Store) GetAllSnapshots() map[string]RepoSnapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	snapshots := make(map[string]RepoSnapshot)
+	for name, snapshot := range s.snapshots {
+		snapshots[name] = snapshot.DeepCopy()
+	}
+	return snapshots
+}
+
+// DeleteSnapshot removes the snapshot with the given name.
+func (s *Store) DeleteSnapshot(name string) error {
+	return s.change(func() error {
+		if _, exists := s.snapshots[name]; !exists {
+			return &NotFoundError{"snapshot does not exist"}
+		}
+		delete(s.snapshots, name)
+		return nil
+	})
+}
@@ -38,6 +38,11 @@ type Store struct {
 	sources           map[string]SourceConfig
 	blueprintsChanges map[string]map[string]blueprint.Change
 	blueprintsCommits map[string][]string
+	schedules         map[uuid.UUID]Schedule
+	snapshots         map[string]RepoSnapshot
+	// blueprintOwners maps blueprint names to the names of the users who
+	// own them. Blueprints without an entry are shared by all users.
+	blueprintOwners map[string]string
 
 	mu       sync.RWMutex // protects all fields
 	stateDir *string
@@ -53,6 +58,13 @@ type SourceConfig struct {
 	System   bool     `json:"system" toml:"system"`
 	Distros  []string `json:"distros" toml:"distros"`
 	RHSM     bool     `json:"rhsm" toml:"rhsm"`
+
+	// Proxy is the url of the HTTP proxy used to access the repository
+	Proxy string `json:"proxy,omitempty" toml:"proxy,omitempty"`
+
+	// Owner is the name of the user who added the source, if the weldr API
+	// runs in multi-user mode. It is never read from or written to requests.
+	Owner string `json:"-" toml:"-"`
 }
 
 type NotFoundError struct {
@@ -63,6 +75,24 @@ func (e *NotFoundError) Error() string {
 	return e.message
 }
 
+type SnapshotExistsError struct {
+	name string
+}
+
+func (e *SnapshotExistsError) Error() string {
+	return fmt.Sprintf("snapshot %s already exists", e.name)
+}
+
+// OwnerError is returned when an object cannot be changed because it is owned
+// by another user.
+type OwnerError struct {
+	message string
+}
+
+func (e *OwnerError) Error() string {
+	return e.message
+}
+
 type NoLocalTargetError struct {
 	message string
 }
@@ -266,9 +296,11 @@ func (s *Store) DeleteBlueprint(name string) error {
 	return s.change(func() error {
 		delete(s.workspace, name)
 		if _, ok := s.blueprints[name]; !ok {
+			delete(s.blueprintOwners, name)
 			return fmt.Errorf("Unknown blueprint: %s", name)
 		}
 		delete(s.blueprints, name)
+		delete(s.blueprintOwners, name)
 		return nil
 	})
 }
@@ -281,6 +313,43 @@ func (s *Store) DeleteBlueprintFromWorkspace(name string) error {
 			return fmt.Errorf("Unknown blueprint: %s", name)
 		}
 		delete(s.workspace, name)
+		if _, ok := s.blueprints[name]; !ok {
+			delete(s.blueprintOwners, name)
+		}
+		return nil
+	})
+}
+
+// GetBlueprintOwner returns the name of the user who owns the blueprint, or
+// an empty string if the blueprint is shared by all users.
+func (s *Store) GetBlueprintOwner(name string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.blueprintOwners[name]
+}
+
+// ClaimBlueprint makes owner the owner of the blueprint with the given name
+// if it doesn't exist yet. Existing blueprints without an owner stay shared.
+// It returns an OwnerError if the blueprint is owned by another user.
+// Claiming with an empty owner is a no-op.
+func (s *Store) ClaimBlueprint(name, owner string) error {
+	if owner == "" {
+		return nil
+	}
+
+	return s.change(func() error {
+		_, committed := s.blueprints[name]
+		_, inWorkspace := s.workspace[name]
+		if !committed && !inWorkspace {
+			// this also takes over owners left behind by failed pushes
+			s.blueprintOwners[name] = owner
+			return nil
+		}
+
+		if current, owned := s.blueprintOwners[name]; owned && current != owner {
+			return &OwnerError{fmt.Sprintf("blueprint %s is owned by another user", name)}
+		}
 		return nil
 	})
 }
@@ -355,7 +424,9 @@ func (s *Store) PushCompose(composeID uuid.UUID,
 	size uint64,
 	targets []*target.Target,
 	jobId uuid.UUID,
-	packages []rpmmd.PackageSpec) error {
+	packages []rpmmd.PackageSpec,
+	repoChecksums map[string]string,
+	owner string) error {
 
 	if _, exists := s.GetCompose(composeID); exists {
 		panic("a compose with this id already exists")
@@ -377,7 +448,9 @@ func (s *Store) PushCompose(composeID uuid.UUID,
 				Size:       size,
 				JobID:      jobId,
 			},
-			Packages: packages,
+			Packages:      packages,
+			RepoChecksums: repoChecksums,
+			Owner:         owner,
 		}
 		return nil
 	})
@@ -394,7 +467,9 @@ func (s *Store) PushTestCompose(composeID uuid.UUID,
 	size uint64,
 	targets []*target.Target,
 	testSuccess bool,
-	packages []rpmmd.PackageSpec) error {
+	packages []rpmmd.PackageSpec,
+	repoChecksums map[string]string,
+	owner string) error {
 
 	if targets == nil {
 		targets = []*target.Target{}
@@ -420,7 +495,9 @@ func (s *Store) PushTestCompose(composeID uuid.UUID,
 				JobStarted:  time.Now(),
 				Size:        size,
 			},
-			Packages: packages,
+			Packages:      packages,
+			RepoChecksums: repoChecksums,
+			Owner:         owner,
 		}
 		return nil
 	})
@@ -571,6 +648,7 @@ func NewSourceConfig(repo rpmmd.RepoConfig, system bool) SourceConfig {
 		CheckSSL: !repo.IgnoreSSL,
 		System:   system,
 		RHSM:     repo.RHSM,
+		Proxy:    repo.Proxy,
 	}
 
 	if repo.BaseURL != "" {
@@ -594,6 +672,7 @@ func (s *SourceConfig) RepoConfig(name string) rpmmd.RepoConfig {
 	repo.IgnoreSSL = !s.CheckSSL
 	repo.CheckGPG = s.CheckGPG
 	repo.RHSM = s.RHSM
+	repo.Proxy = s.Proxy
 
 	if s.Type == "yum-baseurl" {
 		repo.BaseURL = s.URL
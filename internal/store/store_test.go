@@ -260,36 +260,45 @@ func (suite *storeTest) TestDeleteBlueprintFromWorkspace() {
 
 func (suite *storeTest) TestPushCompose() {
 	testID := uuid.New()
-	err := suite.myStore.PushCompose(testID, suite.myManifest, suite.myImageType, &suite.myBP, 123, nil, uuid.New(), []rpmmd.PackageSpec{})
+	err := suite.myStore.PushCompose(testID, suite.myManifest, suite.myImageType, &suite.myBP, 123, nil, uuid.New(), []rpmmd.PackageSpec{}, nil, "")
 	suite.NoError(err)
 	suite.Panics(func() {
-		err = suite.myStore.PushCompose(testID, suite.myManifest, suite.myImageType, &suite.myBP, 123, []*target.Target{suite.myTarget}, uuid.New(), []rpmmd.PackageSpec{})
+		err = suite.myStore.PushCompose(testID, suite.myManifest, suite.myImageType, &suite.myBP, 123, []*target.Target{suite.myTarget}, uuid.New(), []rpmmd.PackageSpec{}, nil, "")
 	})
 	suite.NoError(err)
 
 	// Test with PackageSets
 	testID = uuid.New()
-	err = suite.myStore.PushCompose(testID, suite.myManifest, suite.myImageType, &suite.myBP, 123, nil, uuid.New(), suite.myPackages)
+	err = suite.myStore.PushCompose(testID, suite.myManifest, suite.myImageType, &suite.myBP, 123, nil, uuid.New(), suite.myPackages, nil, "")
 	suite.NoError(err)
+
+	// Test with repository checksums
+	testID = uuid.New()
+	checksums := map[string]string{"baseos": "sha256:aaa"}
+	err = suite.myStore.PushCompose(testID, suite.myManifest, suite.myImageType, &suite.myBP, 123, nil, uuid.New(), suite.myPackages, checksums, "")
+	suite.NoError(err)
+	compose, exists := suite.myStore.GetCompose(testID)
+	suite.True(exists)
+	suite.Equal(checksums, compose.RepoChecksums)
 }
 
 func (suite *storeTest) TestPushTestCompose() {
 	ID := uuid.New()
-	err := suite.myStore.PushTestCompose(ID, suite.myManifest, suite.myImageType, &suite.myBP, 123, nil, true, []rpmmd.PackageSpec{})
+	err := suite.myStore.PushTestCompose(ID, suite.myManifest, suite.myImageType, &suite.myBP, 123, nil, true, []rpmmd.PackageSpec{}, nil, "")
 	suite.NoError(err)
 	suite.Equal(common.ImageBuildState(2), suite.myStore.composes[ID].ImageBuild.QueueStatus)
 	ID = uuid.New()
-	err = suite.myStore.PushTestCompose(ID, suite.myManifest, suite.myImageType, &suite.myBP, 123, []*target.Target{suite.myTarget}, false, []rpmmd.PackageSpec{})
+	err = suite.myStore.PushTestCompose(ID, suite.myManifest, suite.myImageType, &suite.myBP, 123, []*target.Target{suite.myTarget}, false, []rpmmd.PackageSpec{}, nil, "")
 	suite.NoError(err)
 	suite.Equal(common.ImageBuildState(3), suite.myStore.composes[ID].ImageBuild.QueueStatus)
 
 	// Test with PackageSets
 	ID = uuid.New()
-	err = suite.myStore.PushTestCompose(ID, suite.myManifest, suite.myImageType, &suite.myBP, 123, nil, true, suite.myPackages)
+	err = suite.myStore.PushTestCompose(ID, suite.myManifest, suite.myImageType, &suite.myBP, 123, nil, true, suite.myPackages, nil, "")
 	suite.NoError(err)
 	suite.Equal(common.ImageBuildState(2), suite.myStore.composes[ID].ImageBuild.QueueStatus)
 	ID = uuid.New()
-	err = suite.myStore.PushTestCompose(ID, suite.myManifest, suite.myImageType, &suite.myBP, 123, []*target.Target{suite.myTarget}, false, suite.myPackages)
+	err = suite.myStore.PushTestCompose(ID, suite.myManifest, suite.myImageType, &suite.myBP, 123, []*target.Target{suite.myTarget}, false, suite.myPackages, nil, "")
 	suite.NoError(err)
 	suite.Equal(common.ImageBuildState(3), suite.myStore.composes[ID].ImageBuild.QueueStatus)
 }
@@ -427,6 +436,135 @@ func (suite *storeTest) TestRepoConfigMirrorlist() {
 	actualRepo := suite.mySourceConfig.RepoConfig("testSourceConfig")
 	suite.Equal(expectedRepo, actualRepo)
 }
+func (suite *storeTest) TestSchedules() {
+	schedule := Schedule{
+		ID:        uuid.New(),
+		Name:      "weekly",
+		Cron:      "0 3 * * 0",
+		Blueprint: "testBP",
+		ImageType: test_distro.TestImageTypeName,
+		Created:   time.Now(),
+	}
+	suite.NoError(suite.myStore.PushSchedule(schedule))
+
+	actual, exists := suite.myStore.GetSchedule(schedule.ID)
+	suite.True(exists)
+	suite.Equal(schedule.Name, actual.Name)
+	suite.Empty(actual.History)
+	suite.Len(suite.myStore.GetAllSchedules(), 1)
+
+	triggered := time.Now()
+	composeID := uuid.New()
+	suite.NoError(suite.myStore.PushScheduledCompose(schedule.ID, ScheduledCompose{Triggered: triggered, ComposeID: composeID}))
+	actual, _ = suite.myStore.GetSchedule(schedule.ID)
+	suite.Equal(triggered, actual.LastRun)
+	suite.Equal([]ScheduledCompose{{Triggered: triggered, ComposeID: composeID}}, actual.History)
+
+	for i := 0; i < MaxScheduleHistory; i++ {
+		suite.NoError(suite.myStore.PushScheduledCompose(schedule.ID, ScheduledCompose{Triggered: triggered}))
+	}
+	actual, _ = suite.myStore.GetSchedule(schedule.ID)
+	suite.Len(actual.History, MaxScheduleHistory)
+
+	// the store is persisted and read back correctly
+	distro := test_distro.New()
+	arch, err := distro.GetArch(test_distro.TestArchName)
+	suite.NoError(err)
+	reloaded := New(&suite.dir, arch, nil)
+	reloadedSchedule, exists := reloaded.GetSchedule(schedule.ID)
+	suite.True(exists)
+	suite.Equal(schedule.Cron, reloadedSchedule.Cron)
+	suite.Len(reloadedSchedule.History, MaxScheduleHistory)
+
+	suite.NoError(suite.myStore.DeleteSchedule(schedule.ID))
+	_, exists = suite.myStore.GetSchedule(schedule.ID)
+	suite.False(exists)
+	suite.Error(suite.myStore.DeleteSchedule(schedule.ID))
+	suite.Error(suite.myStore.PushScheduledCompose(schedule.ID, ScheduledCompose{}))
+}
+
+func (suite *storeTest) TestSnapshots() {
+	snapshot := RepoSnapshot{
+		Name:      "2021-12",
+		Distro:    test_distro.TestDistroName,
+		Checksums: map[string]string{"baseos": "sha256:aaa"},
+		Created:   time.Now().UTC(),
+	}
+	suite.NoError(suite.myStore.PushSnapshot(snapshot))
+	suite.IsType(&SnapshotExistsError{}, suite.myStore.PushSnapshot(snapshot))
+
+	actual, exists := suite.myStore.GetSnapshot(snapshot.Name)
+	suite.True(exists)
+	suite.Equal(snapshot, actual)
+	suite.Len(suite.myStore.GetAllSnapshots(), 1)
+
+	// the returned snapshot is a copy
+	actual.Checksums["baseos"] = "sha256:bbb"
+	actual, _ = suite.myStore.GetSnapshot(snapshot.Name)
+	suite.Equal("sha256:aaa", actual.Checksums["baseos"])
+
+	// the store is persisted and read back correctly
+	distro := test_distro.New()
+	arch, err := distro.GetArch(test_distro.TestArchName)
+	suite.NoError(err)
+	reloaded := New(&suite.dir, arch, nil)
+	reloadedSnapshot, exists := reloaded.GetSnapshot(snapshot.Name)
+	suite.True(exists)
+	suite.Equal(snapshot.Checksums, reloadedSnapshot.Checksums)
+	suite.Equal(snapshot.Distro, reloadedSnapshot.Distro)
+
+	suite.NoError(suite.myStore.DeleteSnapshot(snapshot.Name))
+	_, exists = suite.myStore.GetSnapshot(snapshot.Name)
+	suite.False(exists)
+	suite.Error(suite.myStore.DeleteSnapshot(snapshot.Name))
+}
+
+func (suite *storeTest) TestOwners() {
+	// a new blueprint can be claimed, but not by another user
+	suite.NoError(suite.myStore.ClaimBlueprint("owned", "alice"))
+	suite.NoError(suite.myStore.PushBlueprint(blueprint.Blueprint{Name: "owned"}, "message"))
+	suite.Equal("alice", suite.myStore.GetBlueprintOwner("owned"))
+	suite.NoError(suite.myStore.ClaimBlueprint("owned", "alice"))
+	suite.IsType(&OwnerError{}, suite.myStore.ClaimBlueprint("owned", "bob"))
+
+	// existing blueprints without owner stay shared
+	suite.NoError(suite.myStore.PushBlueprint(blueprint.Blueprint{Name: "shared"}, "message"))
+	suite.NoError(suite.myStore.ClaimBlueprint("shared", "bob"))
+	suite.Equal("", suite.myStore.GetBlueprintOwner("shared"))
+
+	// owners of blueprints which were never pushed can be taken over
+	suite.NoError(suite.myStore.ClaimBlueprint("failed", "alice"))
+	suite.NoError(suite.myStore.ClaimBlueprint("failed", "bob"))
+	suite.Equal("bob", suite.myStore.GetBlueprintOwner("failed"))
+
+	composeID := uuid.New()
+	suite.NoError(suite.myStore.PushCompose(composeID, suite.myManifest, suite.myImageType, &suite.myBP, 123, nil, uuid.New(), nil, nil, "alice"))
+	sourceConfig := suite.mySourceConfig
+	sourceConfig.Owner = "alice"
+	suite.myStore.PushSource("private", sourceConfig)
+	scheduleID := uuid.New()
+	suite.NoError(suite.myStore.PushSchedule(Schedule{ID: scheduleID, Owner: "alice"}))
+
+	// owners are persisted and read back correctly
+	distro := test_distro.New()
+	arch, err := distro.GetArch(test_distro.TestArchName)
+	suite.NoError(err)
+	reloaded := New(&suite.dir, arch, nil)
+	suite.Equal("alice", reloaded.GetBlueprintOwner("owned"))
+	compose, exists := reloaded.GetCompose(composeID)
+	suite.True(exists)
+	suite.Equal("alice", compose.Owner)
+	suite.Equal("alice", reloaded.GetSource("private").Owner)
+	schedule, exists := reloaded.GetSchedule(scheduleID)
+	suite.True(exists)
+	suite.Equal("alice", schedule.Owner)
+
+	// deleting a blueprint releases it
+	suite.NoError(suite.myStore.DeleteBlueprint("owned"))
+	suite.Equal("", suite.myStore.GetBlueprintOwner("owned"))
+	suite.NoError(suite.myStore.ClaimBlueprint("owned", "bob"))
+}
+
 func TestStore(t *testing.T) {
 	suite.Run(t, new(storeTest))
 }
@@ -0,0 +1,30 @@
+package target
+
+// OSTreeTargetOptions imports the OSTree commit archive of an image into one
+// of the repositories configured on the worker.
+type OSTreeTargetOptions struct {
+	Filename string `json:"filename"`
+	// Repository is the name of the repository in the worker's
+	// configuration. Requests can't point the worker at arbitrary paths.
+	Repository string `json:"repository"`
+	// Ref is updated to point at the imported commit. When it is empty,
+	// the ref of the commit in the archive is used.
+	Ref string `json:"ref,omitempty"`
+}
+
+func (OSTreeTargetOptions) isTargetOptions() {}
+
+func NewOSTreeTarget(options *OSTreeTargetOptions) *Target {
+	return newTarget("org.osbuild.ostree", options)
+}
+
+type OSTreeTargetResultOptions struct {
+	Ref    string `json:"ref"`
+	Commit string `json:"commit"`
+}
+
+func (OSTreeTargetResultOptions) isTargetResultOptions() {}
+
+func NewOSTreeTargetResult(options *OSTreeTargetResultOptions) *TargetResult {
+	return newTargetResult("org.osbuild.ostree", options)
+}
@@ -83,6 +83,8 @@ func UnmarshalTargetOptions(targetName string, rawOptions json.RawMessage) (Targ
 		options = new(VMWareTargetOptions)
 	case "org.osbuild.oci":
 		options = new(OCITargetOptions)
+	case "org.osbuild.ostree":
+		options = new(OSTreeTargetOptions)
 	default:
 		return nil, errors.New("unexpected target name")
 	}
@@ -86,6 +86,7 @@ func (a APICall) Do(t *testing.T) APICallResult {
 	return APICallResult{
 		Body:       body,
 		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
 	}
 }
 
@@ -96,4 +97,7 @@ type APICallResult struct {
 
 	// Status code returned from the server
 	StatusCode int
+
+	// Headers returned from the server
+	Header http.Header
 }
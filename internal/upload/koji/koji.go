@@ -15,12 +15,16 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"hash"
 	"hash/adler32"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"net/rpc"
 	"os"
+	"regexp"
+	"strconv"
 
 	"github.com/kolo/xmlrpc"
 	"github.com/sirupsen/logrus"
@@ -34,8 +38,21 @@ type Koji struct {
 	xmlrpc    *xmlrpc.Client
 	server    string
 	transport http.RoundTripper
+	// How often calls and uploads of chunks are attempted before giving up,
+	// and how long to wait before the first retry. The wait doubles with
+	// every retry.
+	attempts uint
+	backoff  time.Duration
 }
 
+// Defaults for retrying calls which failed because of transient errors. The
+// underlying HTTP client already retries a few times within seconds, these
+// retries bridge longer outages of the hub.
+const (
+	defaultAttempts = 5
+	defaultBackoff  = 10 * time.Second
+)
+
 type TypeInfo struct {
 	Image struct{} `json:"image"`
 }
@@ -85,9 +102,24 @@ type BuildRoot struct {
 	RPMs             []rpmmd.RPM      `json:"components"`
 }
 
+// Types of the outputs of a content generator import. Logs are attached to
+// the build, all other outputs are archives of the image build type.
+const (
+	CGOutputTypeImage = "image"
+	CGOutputTypeLog   = "log"
+)
+
 type ImageExtraInfo struct {
 	// TODO: Ideally this is where the pipeline would be passed.
 	Arch string `json:"arch"` // TODO: why?
+	// Distro and ImageType the image was built for
+	Distro    string `json:"distro,omitempty"`
+	ImageType string `json:"image_type,omitempty"`
+	// Artifact says what the output is: the image itself, or one of the
+	// files describing it, like its manifest or software bill of materials.
+	Artifact string `json:"artifact,omitempty"`
+	// Image is the filename of the image a describing file belongs to.
+	Image string `json:"image,omitempty"`
 }
 
 type ImageExtra struct {
@@ -151,9 +183,89 @@ func newKoji(server string, transport http.RoundTripper, reply loginReply) (*Koj
 		xmlrpc:    client,
 		server:    server,
 		transport: kojiTransport,
+		attempts:  defaultAttempts,
+		backoff:   defaultBackoff,
 	}, nil
 }
 
+/* from `koji/__init__.py`
+class ServerOffline(GenericError):
+    """Raised when the server is offline"""
+    faultCode = 1014
+*/
+const faultServerOffline = 1014
+
+var faultRegexp = regexp.MustCompile(`^Fault\((-?[0-9]+)\)`)
+
+// faultCode returns the code of the XML-RPC fault `err`, if it is one.
+// Faults of calls are passed on as strings by net/rpc, faults of uploads
+// are wrapped.
+func faultCode(err error) (int, bool) {
+	var fault xmlrpc.FaultError
+	if errors.As(err, &fault) {
+		return fault.Code, true
+	}
+
+	serverError, ok := err.(rpc.ServerError)
+	if !ok {
+		return 0, false
+	}
+	match := faultRegexp.FindStringSubmatch(string(serverError))
+	if match == nil {
+		return 0, false
+	}
+	code, err := strconv.Atoi(match[1])
+	if err != nil {
+		return 0, false
+	}
+	return code, true
+}
+
+// isTransient returns whether a call which failed with `err` might succeed
+// when it is tried again. Faults are final, unless the hub is offline:
+// RetryError in particular means that a previous attempt of the call
+// succeeded and must not be run again.
+func isTransient(err error) bool {
+	if err == rpc.ErrShutdown {
+		return false
+	}
+	code, isFault := faultCode(err)
+	return !isFault || code == faultServerOffline
+}
+
+// retry calls `f` until it succeeded, failed with an error which isn't
+// transient, or was attempted k.attempts times. The wait between attempts
+// doubles, starting at k.backoff.
+func (k *Koji) retry(what string, f func() error) error {
+	backoff := k.backoff
+	for attempt := uint(1); ; attempt++ {
+		err := f()
+		if err == nil || attempt >= k.attempts || !isTransient(err) {
+			return err
+		}
+		logrus.Warnf("Koji %s failed (attempt %d of %d), retrying in %v: %v", what, attempt, k.attempts, backoff, err)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// call calls an XML-RPC method of the hub and retries it on transient
+// errors. Retries are sent with the callnum of the first attempt, so that
+// the hub refuses to run a call a second time when only its reply got lost.
+func (k *Koji) call(method string, args interface{}, reply interface{}) error {
+	transport, hasSession := k.transport.(*Transport)
+	var callnum int
+	if hasSession {
+		callnum = transport.callnum
+	}
+	return k.retry("call "+method, func() error {
+		if hasSession {
+			transport.callnum = callnum
+		}
+		return k.xmlrpc.Call(method, args, reply)
+	})
+}
+
 // NewFromPlain creates a new Koji sessions  =authenticated using the plain
 // username/password method. If you want to speak to a public koji instance,
 // you probably cannot use this method.
@@ -237,7 +349,7 @@ func (k *Koji) CGInitBuild(name, version, release string) (*CGInitBuildResult, e
 	buildInfo.Release = release
 
 	var result CGInitBuildResult
-	err := k.xmlrpc.Call("CGInitBuild", []interface{}{"osbuild", buildInfo}, &result)
+	err := k.call("CGInitBuild", []interface{}{"osbuild", buildInfo}, &result)
 	if err != nil {
 		return nil, err
 	}
@@ -264,12 +376,12 @@ const (
 
 // CGFailBuild marks an in-progress build as failed
 func (k *Koji) CGFailBuild(buildID int, token string) error {
-	return k.xmlrpc.Call("CGRefundBuild", []interface{}{"osbuild", buildID, token, buildStateFailed}, nil)
+	return k.call("CGRefundBuild", []interface{}{"osbuild", buildID, token, buildStateFailed}, nil)
 }
 
 // CGCancelBuild marks an in-progress build as cancelled, and
 func (k *Koji) CGCancelBuild(buildID int, token string) error {
-	return k.xmlrpc.Call("CGRefundBuild", []interface{}{"osbuild", buildID, token, buildStateCanceled}, nil)
+	return k.call("CGRefundBuild", []interface{}{"osbuild", buildID, token, buildStateCanceled}, nil)
 }
 
 // CGImport imports previously uploaded content, by specifying its metadata, and the temporary
@@ -286,7 +398,7 @@ func (k *Koji) CGImport(build ImageBuild, buildRoots []BuildRoot, images []Image
 	}
 
 	var result CGImportResult
-	err = k.xmlrpc.Call("CGImport", []interface{}{string(metadata), directory, token}, &result)
+	err = k.call("CGImport", []interface{}{string(metadata), directory, token}, &result)
 	if err != nil {
 		return nil, err
 	}
@@ -309,6 +421,11 @@ func (k *Koji) uploadChunk(chunk []byte, filepath, filename string, offset uint6
 	q.Add("filename", filename)
 	q.Add("offset", fmt.Sprintf("%v", offset))
 	q.Add("fileverify", "adler32")
+	if offset == 0 {
+		// the hub refuses to start an upload over an existing file,
+		// which exists when the upload is restarted
+		q.Add("overwrite", "1")
+	}
 	u.RawQuery = q.Encode()
 
 	retries := uint(0)
@@ -364,7 +481,7 @@ func (k *Koji) uploadChunk(chunk []byte, filepath, filename string, offset uint6
 	resp := xmlrpc.Response(body)
 
 	if resp.Err() != nil {
-		return retries, fmt.Errorf("xmlrpc server returned an error: %v", resp.Err())
+		return retries, fmt.Errorf("xmlrpc server returned an error: %w", resp.Err())
 	}
 
 	err = resp.Unmarshal(&reply)
@@ -384,25 +501,88 @@ func (k *Koji) uploadChunk(chunk []byte, filepath, filename string, offset uint6
 	return retries, nil
 }
 
+// uploadedSize returns how many bytes of filepath/filename were uploaded
+// already, and the Adler32 checksum of them. It returns 0 if the file wasn't
+// uploaded at all.
+func (k *Koji) uploadedSize(filepath, filename string) (uint64, string, error) {
+	var reply struct {
+		Size      int64  `xmlrpc:"size"`
+		HexDigest string `xmlrpc:"hexdigest"`
+	}
+	// returns None if the file doesn't exist, which leaves reply empty
+	err := k.call("checkUpload", []interface{}{filepath, filename, "adler32"}, &reply)
+	if err != nil {
+		return 0, "", err
+	}
+	return uint64(reply.Size), reply.HexDigest, nil
+}
+
+// resumeOffset returns the offset at which the upload of `file` to
+// filepath/filename can be resumed, after hashing the part of `file` which
+// was uploaded already. Uploads can only be resumed if `file` can seek back
+// when the uploaded part differs from it. Otherwise, they start over.
+func (k *Koji) resumeOffset(file io.Reader, filepath, filename string, fileHash hash.Hash) (uint64, error) {
+	seeker, ok := file.(io.Seeker)
+	if !ok {
+		return 0, nil
+	}
+
+	size, digest, err := k.uploadedSize(filepath, filename)
+	if err != nil {
+		return 0, err
+	}
+	if size == 0 {
+		return 0, nil
+	}
+
+	checksum := adler32.New()
+	n, err := io.CopyN(io.MultiWriter(fileHash, checksum), file, int64(size))
+	if err != nil && err != io.EOF {
+		return 0, err
+	}
+	if uint64(n) == size && fmt.Sprintf("%08x", checksum.Sum32()) == digest {
+		logrus.Infof("Resuming koji upload of %s after %d bytes", filename, size)
+		return size, nil
+	}
+
+	logrus.Infof("Restarting koji upload of %s, the uploaded part differs from the file", filename)
+	fileHash.Reset()
+	_, err = seeker.Seek(0, io.SeekStart)
+	return 0, err
+}
+
 // Upload uploads file to the temporary filepath on the kojiserver under the name filename
 // The md5sum and size of the file is returned on success.
+//
+// If the file was partially uploaded before, for example by a previous
+// attempt which failed, the upload continues after the uploaded part. Chunks
+// which can't be uploaded are retried with a backoff.
 func (k *Koji) Upload(file io.Reader, filepath, filename string) (string, uint64, error) {
 	chunk := make([]byte, 1024*1024) // upload a megabyte at a time
 	retries := uint(0)
-	offset := uint64(0)
 	// Koji uses MD5 hashes
 	/* #nosec G401 */
 	hash := md5.New()
-	for {
-		n, err := file.Read(chunk)
-		if err != nil {
-			if err == io.EOF {
-				break
-			}
+	offset, err := k.resumeOffset(file, filepath, filename, hash)
+	if err != nil {
+		return "", 0, err
+	}
+	// empty files are uploaded as a single empty chunk, so that they exist
+	// on the hub
+	for first := offset == 0; ; first = false {
+		n, err := io.ReadFull(file, chunk)
+		if err == io.EOF && !first {
+			break
+		} else if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
 			return "", 0, err
 		}
-		r, err := k.uploadChunk(chunk[:n], filepath, filename, offset)
-		retries += r
+		// writing a chunk at an offset can be repeated, so that the
+		// upload continues after chunks which failed
+		err = k.retry("upload of "+filename, func() error {
+			r, err := k.uploadChunk(chunk[:n], filepath, filename, offset)
+			retries += r
+			return err
+		})
 		if err != nil {
 			logrus.Infof("Koji upload failed after %d retries", retries)
 			return "", 0, err
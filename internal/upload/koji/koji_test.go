@@ -0,0 +1,230 @@
+package koji
+
+import (
+	"bytes"
+	// koji uses MD5 hashes
+	/* #nosec G501 */
+	"crypto/md5"
+	"fmt"
+	"hash/adler32"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeHub implements the parts of koji's hub which are needed to upload
+// files and import builds.
+type fakeHub struct {
+	mu    sync.Mutex
+	files map[string][]byte
+	// offsets of the uploaded chunks
+	offsets []uint64
+	// the next badChunks chunks are acknowledged with a wrong checksum
+	badChunks int
+	// faults returned by the next CGImport calls
+	importFaults []int
+	// callnums of the CGImport calls
+	importCallnums []string
+}
+
+var methodNameRegexp = regexp.MustCompile(`<methodName>(.*)</methodName>`)
+
+func (h *fakeHub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		panic(err)
+	}
+	query := r.URL.Query()
+
+	// uploads pass their parameters in the URL
+	if filename := query.Get("filename"); filename != "" {
+		offset, err := strconv.ParseUint(query.Get("offset"), 10, 64)
+		if err != nil {
+			panic(err)
+		}
+		name := query.Get("filepath") + "/" + filename
+		file := h.files[name]
+		if offset == 0 && len(file) > 0 && query.Get("overwrite") == "" {
+			writeFault(w, 1000, "upload path exists")
+			return
+		}
+		if uint64(len(file)) < offset {
+			writeFault(w, 1000, "offset beyond the end of the file")
+			return
+		}
+		h.files[name] = append(file[:offset], body...)
+		h.offsets = append(h.offsets, offset)
+
+		digest := fmt.Sprintf("%08x", adler32.Checksum(body))
+		if h.badChunks > 0 {
+			h.badChunks--
+			digest = "00000000"
+		}
+		writeResponse(w, fmt.Sprintf(`<struct>
+			<member><name>size</name><value><int>%d</int></value></member>
+			<member><name>hexdigest</name><value><string>%s</string></value></member>
+		</struct>`, len(body), digest))
+		return
+	}
+
+	method := methodNameRegexp.FindSubmatch(body)
+	if method == nil {
+		panic("request without a method name")
+	}
+	switch string(method[1]) {
+	case "checkUpload":
+		params := regexp.MustCompile(`<string>(.*?)</string>`).FindAllSubmatch(body, -1)
+		file, exists := h.files[string(params[0][1])+"/"+string(params[1][1])]
+		if !exists {
+			writeResponse(w, "<nil/>")
+			return
+		}
+		writeResponse(w, fmt.Sprintf(`<struct>
+			<member><name>size</name><value><i8>%d</i8></value></member>
+			<member><name>mtime</name><value><double>1.5</double></value></member>
+			<member><name>hexdigest</name><value><string>%08x</string></value></member>
+		</struct>`, len(file), adler32.Checksum(file)))
+	case "CGImport":
+		h.importCallnums = append(h.importCallnums, query.Get("callnum"))
+		if len(h.importFaults) > 0 {
+			code := h.importFaults[0]
+			h.importFaults = h.importFaults[1:]
+			writeFault(w, code, "import failed")
+			return
+		}
+		writeResponse(w, `<struct><member><name>build_id</name><value><int>42</int></value></member></struct>`)
+	default:
+		writeFault(w, 1000, "unknown method")
+	}
+}
+
+func writeResponse(w http.ResponseWriter, value string) {
+	fmt.Fprintf(w, `<?xml version="1.0"?><methodResponse><params><param><value>%s</value></param></params></methodResponse>`, value)
+}
+
+func writeFault(w http.ResponseWriter, code int, message string) {
+	fmt.Fprintf(w, `<?xml version="1.0"?><methodResponse><fault><value><struct>
+		<member><name>faultCode</name><value><int>%d</int></value></member>
+		<member><name>faultString</name><value><string>%s</string></value></member>
+	</struct></value></fault></methodResponse>`, code, message)
+}
+
+func newTestKoji(t *testing.T, hub *fakeHub) *Koji {
+	server := httptest.NewServer(hub)
+	t.Cleanup(server.Close)
+
+	k, err := newKoji(server.URL, http.DefaultTransport, loginReply{SessionID: 1, SessionKey: "key"})
+	require.NoError(t, err)
+	k.backoff = time.Millisecond
+	return k
+}
+
+func randomFile(size int) []byte {
+	content := make([]byte, size)
+	/* #nosec G404 */
+	rand.New(rand.NewSource(42)).Read(content)
+	return content
+}
+
+func requireUploaded(t *testing.T, content []byte, hash string, size uint64) {
+	/* #nosec G401 */
+	require.Equal(t, fmt.Sprintf("%x", md5.Sum(content)), hash)
+	require.Equal(t, uint64(len(content)), size)
+}
+
+func TestUpload(t *testing.T) {
+	content := randomFile(2*1024*1024 + 42)
+	hub := &fakeHub{files: map[string][]byte{}}
+	k := newTestKoji(t, hub)
+
+	hash, size, err := k.Upload(bytes.NewReader(content), "dir", "image.qcow2")
+	require.NoError(t, err)
+	requireUploaded(t, content, hash, size)
+	require.Equal(t, content, hub.files["dir/image.qcow2"])
+	require.Equal(t, []uint64{0, 1024 * 1024, 2 * 1024 * 1024}, hub.offsets)
+}
+
+func TestUploadEmpty(t *testing.T) {
+	hub := &fakeHub{files: map[string][]byte{}}
+	k := newTestKoji(t, hub)
+
+	hash, size, err := k.Upload(bytes.NewReader(nil), "dir", "empty.log")
+	require.NoError(t, err)
+	requireUploaded(t, []byte{}, hash, size)
+	require.Contains(t, hub.files, "dir/empty.log")
+}
+
+func TestUploadResume(t *testing.T) {
+	content := randomFile(3 * 1024 * 1024)
+	hub := &fakeHub{files: map[string][]byte{
+		"dir/image.qcow2": append([]byte{}, content[:1536*1024]...),
+	}}
+	k := newTestKoji(t, hub)
+
+	// only the missing part is uploaded
+	hash, size, err := k.Upload(bytes.NewReader(content), "dir", "image.qcow2")
+	require.NoError(t, err)
+	requireUploaded(t, content, hash, size)
+	require.Equal(t, content, hub.files["dir/image.qcow2"])
+	require.Equal(t, []uint64{1536 * 1024, 2560 * 1024}, hub.offsets)
+}
+
+func TestUploadRestart(t *testing.T) {
+	content := randomFile(1024*1024 + 42)
+	hub := &fakeHub{files: map[string][]byte{
+		"dir/image.qcow2": []byte("something else"),
+	}}
+	k := newTestKoji(t, hub)
+
+	// a different file is uploaded from the start
+	hash, size, err := k.Upload(bytes.NewReader(content), "dir", "image.qcow2")
+	require.NoError(t, err)
+	requireUploaded(t, content, hash, size)
+	require.Equal(t, content, hub.files["dir/image.qcow2"])
+	require.Equal(t, []uint64{0, 1024 * 1024}, hub.offsets)
+}
+
+func TestUploadRetry(t *testing.T) {
+	content := randomFile(1024*1024 + 42)
+	hub := &fakeHub{files: map[string][]byte{}, badChunks: 2}
+	k := newTestKoji(t, hub)
+
+	hash, size, err := k.Upload(bytes.NewReader(content), "dir", "image.qcow2")
+	require.NoError(t, err)
+	requireUploaded(t, content, hash, size)
+	require.Equal(t, content, hub.files["dir/image.qcow2"])
+	require.Equal(t, []uint64{0, 0, 0, 1024 * 1024}, hub.offsets)
+
+	// give up after k.attempts
+	hub.badChunks = defaultAttempts
+	_, _, err = k.Upload(bytes.NewReader(content), "dir", "other.qcow2")
+	require.Error(t, err)
+}
+
+func TestCGImportRetry(t *testing.T) {
+	hub := &fakeHub{files: map[string][]byte{}, importFaults: []int{faultServerOffline, faultServerOffline}}
+	k := newTestKoji(t, hub)
+
+	result, err := k.CGImport(ImageBuild{}, nil, nil, "dir", "token")
+	require.NoError(t, err)
+	require.Equal(t, 42, result.BuildID)
+	// retries are sent with the same callnum
+	require.Equal(t, []string{"0", "0", "0"}, hub.importCallnums)
+
+	// other faults aren't retried
+	hub.importFaults = []int{1009}
+	_, err = k.CGImport(ImageBuild{}, nil, nil, "dir", "token")
+	require.Error(t, err)
+	require.Equal(t, []string{"0", "0", "0", "1"}, hub.importCallnums)
+}
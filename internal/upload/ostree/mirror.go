@@ -0,0 +1,129 @@
+package ostree
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// S3Client is the subset of awscloud.AWS that is needed to mirror a
+// repository.
+type S3Client interface {
+	PutObject(filename, bucket, key string) error
+	ListObjectSizes(bucket, prefix string) (map[string]int64, error)
+	DeleteObjects(bucket string, keys []string) error
+}
+
+// S3Mirror copies a repository into an S3 bucket, from where it can be
+// served as a static website or through a CDN.
+type S3Mirror struct {
+	Client S3Client
+	Bucket string
+	// Prefix is prepended to the path of each file of the repository.
+	Prefix string
+}
+
+// Directories and files of an archive repository that are served to clients.
+// Everything else (tmp/, state/, ...) is local state of the repository.
+var mirroredPaths = []string{"config", "deltas", "objects", "refs", "summary", "summary.sig"}
+
+// Sync makes the bucket contain the same files as the repository at `repoPath`.
+//
+// Objects and static deltas are content-addressed and only uploaded when they
+// are missing from the bucket. They are uploaded before the refs and the
+// summary, so that clients never see a ref to a commit that isn't complete
+// yet. Files which have been pruned from the repository are deleted last.
+func (m *S3Mirror) Sync(repoPath string) error {
+	prefix := m.Prefix
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	remote, err := m.Client.ListObjectSizes(m.Bucket, prefix)
+	if err != nil {
+		return err
+	}
+
+	var immutable, mutable []string
+	local := make(map[string]bool)
+	err = walkMirrored(repoPath, func(rel string, info os.FileInfo) error {
+		local[prefix+rel] = true
+		if isContentAddressed(rel) {
+			if size, ok := remote[prefix+rel]; !ok || size != info.Size() {
+				immutable = append(immutable, rel)
+			}
+		} else {
+			mutable = append(mutable, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, files := range [][]string{immutable, mutable} {
+		for _, rel := range files {
+			err = m.Client.PutObject(filepath.Join(repoPath, filepath.FromSlash(rel)), m.Bucket, path.Join(prefix, rel))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	var stale []string
+	for key := range remote {
+		rel := strings.TrimPrefix(key, prefix)
+		if !local[key] && isMirrored(rel) {
+			stale = append(stale, key)
+		}
+	}
+	if len(stale) == 0 {
+		return nil
+	}
+	sort.Strings(stale)
+	return m.Client.DeleteObjects(m.Bucket, stale)
+}
+
+// walkMirrored calls `fn` with the slash-separated path, relative to
+// `repoPath`, of each file of the repository that is served to clients.
+func walkMirrored(repoPath string, fn func(rel string, info os.FileInfo) error) error {
+	for _, p := range mirroredPaths {
+		err := filepath.Walk(filepath.Join(repoPath, p), func(file string, info os.FileInfo, err error) error {
+			if os.IsNotExist(err) {
+				return nil
+			} else if err != nil {
+				return err
+			}
+			if info.IsDir() {
+				return nil
+			}
+
+			rel, err := filepath.Rel(repoPath, file)
+			if err != nil {
+				return err
+			}
+			return fn(filepath.ToSlash(rel), info)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func isContentAddressed(rel string) bool {
+	return strings.HasPrefix(rel, "objects/") || strings.HasPrefix(rel, "deltas/")
+}
+
+// isMirrored returns whether `rel` is a path that Sync manages, so that
+// unrelated files under the same prefix are left alone.
+func isMirrored(rel string) bool {
+	for _, p := range mirroredPaths {
+		if rel == p || strings.HasPrefix(rel, p+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package ostree
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeS3 struct {
+	objects map[string]int64
+	puts    []string
+	deletes []string
+}
+
+func (f *fakeS3) PutObject(filename, bucket, key string) error {
+	info, err := os.Stat(filename)
+	if err != nil {
+		return err
+	}
+	f.objects[key] = info.Size()
+	f.puts = append(f.puts, key)
+	return nil
+}
+
+func (f *fakeS3) ListObjectSizes(bucket, prefix string) (map[string]int64, error) {
+	sizes := make(map[string]int64)
+	for key, size := range f.objects {
+		sizes[key] = size
+	}
+	return sizes, nil
+}
+
+func (f *fakeS3) DeleteObjects(bucket string, keys []string) error {
+	for _, key := range keys {
+		delete(f.objects, key)
+	}
+	f.deletes = append(f.deletes, keys...)
+	return nil
+}
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0755))
+		require.NoError(t, ioutil.WriteFile(p, []byte(content), 0644))
+	}
+}
+
+func TestS3MirrorSync(t *testing.T) {
+	repo := t.TempDir()
+	writeFiles(t, repo, map[string]string{
+		"config":                 "[core]",
+		"objects/ab/cdef.commit": "new commit",
+		"objects/12/3456.file":   "unchanged",
+		"refs/heads/rhel/edge":   "abcdef",
+		"summary":                "summary",
+		"tmp/cache/foo":          "local state",
+	})
+
+	s3 := &fakeS3{objects: map[string]int64{
+		"edge/objects/12/3456.file":   int64(len("unchanged")),
+		"edge/objects/98/7654.commit": 10,
+		"edge/summary":                7,
+		"edge/index.html":             4,
+		"other/objects/98/7654.file":  4,
+	}}
+
+	mirror := S3Mirror{Client: s3, Bucket: "bucket", Prefix: "edge"}
+	require.NoError(t, mirror.Sync(repo))
+
+	// objects go first, so that the refs and the summary never point to
+	// missing objects, and unchanged objects aren't uploaded again
+	require.Equal(t, []string{
+		"edge/objects/ab/cdef.commit",
+		"edge/config",
+		"edge/refs/heads/rhel/edge",
+		"edge/summary",
+	}, s3.puts)
+
+	// files outside of the repository's paths and prefix are left alone
+	require.Equal(t, []string{"edge/objects/98/7654.commit"}, s3.deletes)
+}
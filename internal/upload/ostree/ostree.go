@@ -0,0 +1,273 @@
+// Package ostree publishes OSTree commits built by osbuild into long-lived
+// archive repositories, which are served over HTTP either directly from disk,
+// from an S3 bucket they are mirrored to, or from an HTTP server they are
+// pushed to.
+package ostree
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// Repository is an OSTree repository in archive mode on the local disk.
+type Repository struct {
+	Path string
+	// KeepCommits is the number of commits of each ref that are kept when
+	// a new one is published. Older commits are pruned. Zero keeps all
+	// of them.
+	KeepCommits int
+	// GPGKeyID is the key the summary is signed with, if set.
+	GPGKeyID   string
+	GPGHomedir string
+	// Mirror is an S3 bucket the repository is copied to after each
+	// publish, if set.
+	Mirror *S3Mirror
+	// Remote is a repository on an HTTP server the repository is pushed
+	// to after each publish, if set.
+	Remote *HTTPRemote
+}
+
+// Publish imports the commit of the commit archive `archive` (as exported by
+// the edge-commit image types) into `repo`, points `ref` at it, prunes old
+// commits, and regenerates the summary. If `ref` is empty, the ref of the
+// commit in the archive is used. It returns the ref and the checksum of the
+// commit.
+func (repo *Repository) Publish(archive, ref, tmpdir string) (string, string, error) {
+	dir, err := ioutil.TempDir(tmpdir, "ostree-publish-")
+	if err != nil {
+		return "", "", err
+	}
+	defer os.RemoveAll(dir)
+
+	err = runCommand("tar", "--extract", "--file", archive, "--directory", dir)
+	if err != nil {
+		return "", "", err
+	}
+	source := filepath.Join(dir, "repo")
+
+	archiveRef, checksum, err := archiveCommit(source)
+	if err != nil {
+		return "", "", err
+	}
+	if ref == "" {
+		ref = archiveRef
+	}
+
+	unlock, err := repo.lock()
+	if err != nil {
+		return "", "", err
+	}
+	defer unlock()
+
+	err = repo.init()
+	if err != nil {
+		return "", "", err
+	}
+
+	err = runCommand("ostree", "pull-local", "--repo", repo.Path, source, checksum)
+	if err != nil {
+		return "", "", err
+	}
+
+	// pull-local only copies commits, but the archive contains the static
+	// delta from the parent commit when one was requested
+	err = copyStaticDeltas(filepath.Join(source, "deltas"), filepath.Join(repo.Path, "deltas"))
+	if err != nil {
+		return "", "", err
+	}
+
+	err = runCommand("ostree", "refs", "--repo", repo.Path, "--force", "--create="+ref, checksum)
+	if err != nil {
+		return "", "", err
+	}
+
+	if repo.KeepCommits > 0 {
+		// prune also removes the static deltas to pruned commits
+		err = runCommand("ostree", "prune", "--repo", repo.Path, "--refs-only", "--depth", strconv.Itoa(repo.KeepCommits-1))
+		if err != nil {
+			return "", "", err
+		}
+	}
+
+	args := []string{"summary", "--repo", repo.Path, "--update"}
+	if repo.GPGKeyID != "" {
+		args = append(args, "--gpg-sign", repo.GPGKeyID)
+		if repo.GPGHomedir != "" {
+			args = append(args, "--gpg-homedir", repo.GPGHomedir)
+		}
+	}
+	err = runCommand("ostree", args...)
+	if err != nil {
+		return "", "", err
+	}
+
+	if repo.Mirror != nil {
+		err = repo.Mirror.Sync(repo.Path)
+		if err != nil {
+			return "", "", fmt.Errorf("error mirroring repository to s3://%s/%s: %v", repo.Mirror.Bucket, repo.Mirror.Prefix, err)
+		}
+	}
+
+	if repo.Remote != nil {
+		err = repo.Remote.Push(repo.Path)
+		if err != nil {
+			return "", "", fmt.Errorf("error pushing repository to %s: %v", repo.Remote.URL, err)
+		}
+	}
+
+	return ref, checksum, nil
+}
+
+// lock takes an exclusive lock on the repository, so that jobs running
+// concurrently on the same host don't race each other updating refs and the
+// summary. It returns a function that releases the lock.
+func (repo *Repository) lock() (func(), error) {
+	err := os.MkdirAll(repo.Path, 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := os.Open(repo.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	err = syscall.Flock(int(dir.Fd()), syscall.LOCK_EX)
+	if err != nil {
+		dir.Close()
+		return nil, fmt.Errorf("error locking repository %s: %v", repo.Path, err)
+	}
+
+	return func() {
+		// closing the file releases the lock
+		dir.Close()
+	}, nil
+}
+
+// init creates the repository if it doesn't exist yet.
+func (repo *Repository) init() error {
+	_, err := os.Stat(filepath.Join(repo.Path, "config"))
+	if err == nil {
+		return nil
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	return runCommand("ostree", "init", "--repo", repo.Path, "--mode", "archive")
+}
+
+// archiveCommit returns the ref of the repository of a commit archive at
+// `path`, and the checksum of the commit it points to.
+func archiveCommit(path string) (string, string, error) {
+	ref, err := onlyRef(path)
+	if err != nil {
+		return "", "", err
+	}
+
+	commit, err := ioutil.ReadFile(filepath.Join(path, "refs", "heads", filepath.FromSlash(ref)))
+	if err != nil {
+		return "", "", fmt.Errorf("error reading ref %s of the commit archive: %v", ref, err)
+	}
+	return ref, strings.TrimSpace(string(commit)), nil
+}
+
+// onlyRef returns the ref of the repository at `path`, which must have
+// exactly one.
+func onlyRef(path string) (string, error) {
+	heads := filepath.Join(path, "refs", "heads")
+
+	var refs []string
+	err := filepath.Walk(heads, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			ref, err := filepath.Rel(heads, p)
+			if err != nil {
+				return err
+			}
+			refs = append(refs, ref)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("error reading refs of the commit archive: %v", err)
+	}
+
+	if len(refs) != 1 {
+		return "", fmt.Errorf("expected exactly one ref in the commit archive, found %d", len(refs))
+	}
+	return refs[0], nil
+}
+
+// copyStaticDeltas copies all static deltas from `from` into `to` which
+// don't exist there yet. Deltas are named after the commits they connect, so
+// existing ones never change.
+func copyStaticDeltas(from, to string) error {
+	return filepath.Walk(from, func(p string, info os.FileInfo, err error) error {
+		if os.IsNotExist(err) && p == from {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(from, p)
+		if err != nil {
+			return err
+		}
+		dest := filepath.Join(to, rel)
+
+		if info.IsDir() {
+			return os.MkdirAll(dest, 0755)
+		}
+		if _, err := os.Stat(dest); err == nil {
+			return nil
+		}
+		return copyFile(p, dest)
+	})
+}
+
+func copyFile(from, to string) error {
+	source, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	// write to a temporary file first, so that a delta is either complete
+	// or missing when clients look for it
+	tmp := to + ".tmp"
+	dest, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(dest, source)
+	if err != nil {
+		dest.Close()
+		return err
+	}
+	err = dest.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, to)
+}
+
+// runCommand runs `name`, returning its output in the error if it fails.
+func runCommand(name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s %s failed: %v: %s", name, args[0], err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
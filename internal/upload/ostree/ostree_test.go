@@ -0,0 +1,67 @@
+package ostree
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCopyStaticDeltas(t *testing.T) {
+	from := filepath.Join(t.TempDir(), "deltas")
+	to := filepath.Join(t.TempDir(), "deltas")
+
+	// repositories without deltas are fine
+	require.NoError(t, copyStaticDeltas(from, to))
+
+	writeFiles(t, from, map[string]string{
+		"ab/cdef-1234/superblock": "new",
+		"ab/cdef-1234/0":          "part",
+		"12/3456-7890/superblock": "from archive",
+	})
+	writeFiles(t, to, map[string]string{
+		"12/3456-7890/superblock": "existing",
+	})
+
+	require.NoError(t, copyStaticDeltas(from, to))
+
+	for name, content := range map[string]string{
+		"ab/cdef-1234/superblock": "new",
+		"ab/cdef-1234/0":          "part",
+		"12/3456-7890/superblock": "existing",
+	} {
+		data, err := ioutil.ReadFile(filepath.Join(to, filepath.FromSlash(name)))
+		require.NoError(t, err)
+		require.Equal(t, content, string(data))
+	}
+}
+
+func TestOnlyRef(t *testing.T) {
+	repo := t.TempDir()
+	writeFiles(t, repo, map[string]string{
+		"refs/heads/rhel/8/x86_64/edge": "abcdef",
+	})
+
+	ref, err := onlyRef(repo)
+	require.NoError(t, err)
+	require.Equal(t, "rhel/8/x86_64/edge", ref)
+
+	writeFiles(t, repo, map[string]string{
+		"refs/heads/fedora/iot": "123456",
+	})
+	_, err = onlyRef(repo)
+	require.Error(t, err)
+}
+
+func TestArchiveCommit(t *testing.T) {
+	repo := t.TempDir()
+	writeFiles(t, repo, map[string]string{
+		"refs/heads/rhel/8/x86_64/edge": "abcdef\n",
+	})
+
+	ref, commit, err := archiveCommit(repo)
+	require.NoError(t, err)
+	require.Equal(t, "rhel/8/x86_64/edge", ref)
+	require.Equal(t, "abcdef", commit)
+}
@@ -0,0 +1,248 @@
+package ostree
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// HTTPRemote is a repository on an HTTP server which accepts uploads with
+// WebDAV, e.g. Apache's mod_dav or nginx's dav module. Clients pull from the
+// same url.
+type HTTPRemote struct {
+	// URL of the repository, which the files of the repository are PUT
+	// below.
+	URL string
+	// Client is used for all requests, http.DefaultClient if nil.
+	Client *http.Client
+	// Token is sent as bearer token with each request, if set.
+	Token string
+}
+
+// pushManifest is the name of the file in the local repository which keeps
+// track of the files that have been pushed to the remote, with their sizes.
+// It is outside of mirroredPaths, so it is never served to clients.
+const pushManifest = "push-manifest.json"
+
+type pushManifestFile struct {
+	URL   string           `json:"url"`
+	Files map[string]int64 `json:"files"`
+}
+
+// Push uploads the repository at `repoPath` to the remote.
+//
+// WebDAV servers can't be expected to allow listing all files, so the files
+// which have been pushed are recorded in a manifest next to the repository,
+// which assumes that nothing else writes to the remote. Objects and static
+// deltas are content-addressed and only uploaded when they aren't in the
+// manifest yet. They are uploaded before the refs and the summary, so that
+// clients never see a ref to a commit that isn't complete yet. Files which
+// have been pruned from the repository are deleted last.
+func (r *HTTPRemote) Push(repoPath string) error {
+	pushed, err := r.readManifest(repoPath)
+	if err != nil {
+		return err
+	}
+
+	var immutable, mutable []string
+	local := make(map[string]int64)
+	err = walkMirrored(repoPath, func(rel string, info os.FileInfo) error {
+		local[rel] = info.Size()
+		if !isContentAddressed(rel) {
+			mutable = append(mutable, rel)
+		} else if size, ok := pushed[rel]; !ok || size != info.Size() {
+			immutable = append(immutable, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	collections := make(map[string]bool)
+	for _, files := range [][]string{immutable, mutable} {
+		for _, rel := range files {
+			err = r.put(filepath.Join(repoPath, filepath.FromSlash(rel)), rel, collections)
+			if err != nil {
+				return err
+			}
+			pushed[rel] = local[rel]
+		}
+	}
+
+	var stale []string
+	for rel := range pushed {
+		if _, ok := local[rel]; !ok {
+			stale = append(stale, rel)
+		}
+	}
+	sort.Strings(stale)
+	for _, rel := range stale {
+		err = r.delete(rel)
+		if err != nil {
+			break
+		}
+		delete(pushed, rel)
+	}
+
+	// files which couldn't be deleted stay in the manifest, so that the
+	// next push tries again
+	if merr := r.writeManifest(repoPath, pushed); merr != nil && err == nil {
+		err = merr
+	}
+	return err
+}
+
+// readManifest returns the files that have been pushed to the remote from
+// the repository at `repoPath`. Nothing has been pushed when the manifest is
+// missing or was written for a different remote.
+func (r *HTTPRemote) readManifest(repoPath string) (map[string]int64, error) {
+	data, err := ioutil.ReadFile(filepath.Join(repoPath, pushManifest))
+	if os.IsNotExist(err) {
+		return make(map[string]int64), nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var manifest pushManifestFile
+	err = json.Unmarshal(data, &manifest)
+	if err != nil {
+		return nil, fmt.Errorf("error reading push manifest of %s: %v", repoPath, err)
+	}
+	if manifest.URL != r.url("") || manifest.Files == nil {
+		return make(map[string]int64), nil
+	}
+	return manifest.Files, nil
+}
+
+func (r *HTTPRemote) writeManifest(repoPath string, files map[string]int64) error {
+	data, err := json.Marshal(pushManifestFile{URL: r.url(""), Files: files})
+	if err != nil {
+		return err
+	}
+
+	filename := filepath.Join(repoPath, pushManifest)
+	err = ioutil.WriteFile(filename+".tmp", data, 0644)
+	if err != nil {
+		return err
+	}
+	return os.Rename(filename+".tmp", filename)
+}
+
+func (r *HTTPRemote) url(rel string) string {
+	return strings.TrimSuffix(r.URL, "/") + "/" + rel
+}
+
+func (r *HTTPRemote) do(method, rel string, file *os.File, size int64) (*http.Response, error) {
+	var req *http.Request
+	var err error
+	if file != nil {
+		req, err = http.NewRequest(method, r.url(rel), file)
+		req.ContentLength = size
+	} else {
+		req, err = http.NewRequest(method, r.url(rel), nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if r.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+r.Token)
+	}
+
+	client := r.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	return resp, nil
+}
+
+// delete removes `rel` from the remote. Files which don't exist anymore
+// are ignored.
+func (r *HTTPRemote) delete(rel string) error {
+	resp, err := r.do(http.MethodDelete, rel, nil, 0)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusNotFound && (resp.StatusCode < 200 || resp.StatusCode >= 300) {
+		return fmt.Errorf("DELETE %s returned %s", r.url(rel), resp.Status)
+	}
+	return nil
+}
+
+// put uploads `filename` as `rel`. When the server doesn't create missing
+// parent collections itself, they are created and the upload is retried.
+// `collections` keeps track of the ones which exist already.
+func (r *HTTPRemote) put(filename, rel string, collections map[string]bool) error {
+	for attempt := 0; ; attempt++ {
+		status, err := r.putFile(filename, rel)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case status == http.StatusConflict && attempt == 0:
+			err = r.mkcolAll(path.Dir(rel), collections)
+			if err != nil {
+				return err
+			}
+		case status >= 200 && status < 300:
+			return nil
+		default:
+			return fmt.Errorf("PUT %s returned %d %s", r.url(rel), status, http.StatusText(status))
+		}
+	}
+}
+
+func (r *HTTPRemote) putFile(filename, rel string) (int, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := r.do(http.MethodPut, rel, file, info.Size())
+	if err != nil {
+		return 0, err
+	}
+	return resp.StatusCode, nil
+}
+
+// mkcolAll creates the collection `dir` and all of its parents.
+func (r *HTTPRemote) mkcolAll(dir string, collections map[string]bool) error {
+	if dir == "." || collections[dir] {
+		return nil
+	}
+
+	err := r.mkcolAll(path.Dir(dir), collections)
+	if err != nil {
+		return err
+	}
+
+	resp, err := r.do("MKCOL", dir+"/", nil, 0)
+	if err != nil {
+		return err
+	}
+	// 405 Method Not Allowed means that the collection exists already
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusMethodNotAllowed {
+		return fmt.Errorf("MKCOL %s returned %s", r.url(dir+"/"), resp.Status)
+	}
+
+	collections[dir] = true
+	return nil
+}
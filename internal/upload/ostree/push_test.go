@@ -0,0 +1,140 @@
+package ostree
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDAV is a WebDAV server which, like Apache's mod_dav, requires the
+// parent collections of uploaded files to exist.
+type fakeDAV struct {
+	mu          sync.Mutex
+	files       map[string]string
+	collections map[string]bool
+	puts        []string
+	deletes     []string
+}
+
+func (f *fakeDAV) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.Header.Get("Authorization") != "Bearer token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	name := strings.TrimPrefix(r.URL.Path, "/repo/")
+	switch r.Method {
+	case http.MethodDelete:
+		if _, ok := f.files[name]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		delete(f.files, name)
+		f.deletes = append(f.deletes, name)
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodPut:
+		if dir := path.Dir(name); dir != "." && !f.collections[dir] {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		f.files[name] = string(body)
+		f.puts = append(f.puts, name)
+		w.WriteHeader(http.StatusCreated)
+	case "MKCOL":
+		dir := strings.TrimSuffix(name, "/")
+		if f.collections[dir] {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if parent := path.Dir(dir); parent != "." && !f.collections[parent] {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		f.collections[dir] = true
+		w.WriteHeader(http.StatusCreated)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestHTTPRemotePush(t *testing.T) {
+	repo := t.TempDir()
+	writeFiles(t, repo, map[string]string{
+		"config":                 "[core]",
+		"objects/ab/cdef.commit": "first commit",
+		"objects/12/3456.file":   "unchanged",
+		"refs/heads/rhel/edge":   "abcdef",
+		"summary":                "summary",
+		"tmp/cache/foo":          "local state",
+	})
+
+	dav := &fakeDAV{
+		files:       map[string]string{},
+		collections: map[string]bool{},
+	}
+	server := httptest.NewServer(dav)
+	defer server.Close()
+
+	remote := HTTPRemote{URL: server.URL + "/repo/", Token: "token"}
+	require.NoError(t, remote.Push(repo))
+
+	// objects go first, so that the refs and the summary never point to
+	// missing objects
+	require.Equal(t, []string{
+		"objects/12/3456.file",
+		"objects/ab/cdef.commit",
+		"config",
+		"refs/heads/rhel/edge",
+		"summary",
+	}, dav.puts)
+	require.Equal(t, "abcdef", dav.files["refs/heads/rhel/edge"])
+	require.True(t, dav.collections["refs/heads/rhel"])
+	require.Empty(t, dav.deletes)
+
+	// a new commit replaces the first one, which is pruned
+	require.NoError(t, os.Remove(filepath.Join(repo, "objects/ab/cdef.commit")))
+	writeFiles(t, repo, map[string]string{
+		"objects/98/7654.commit": "second commit",
+		"refs/heads/rhel/edge":   "987654",
+	})
+	dav.puts = nil
+	require.NoError(t, remote.Push(repo))
+
+	// objects which have been pushed before aren't uploaded again, and
+	// pruned ones are deleted from the remote
+	require.Equal(t, []string{
+		"objects/98/7654.commit",
+		"config",
+		"refs/heads/rhel/edge",
+		"summary",
+	}, dav.puts)
+	require.Equal(t, []string{"objects/ab/cdef.commit"}, dav.deletes)
+	require.Equal(t, "987654", dav.files["refs/heads/rhel/edge"])
+	require.Contains(t, dav.files, "objects/12/3456.file")
+
+	// the manifest is only valid for the remote it was written for
+	otherDAV := &fakeDAV{
+		files:       map[string]string{},
+		collections: map[string]bool{},
+	}
+	otherServer := httptest.NewServer(otherDAV)
+	defer otherServer.Close()
+	other := HTTPRemote{URL: otherServer.URL + "/repo", Token: "token"}
+	require.NoError(t, other.Push(repo))
+	require.Contains(t, otherDAV.puts, "objects/12/3456.file")
+
+	remote.Token = "wrong"
+	require.Error(t, remote.Push(repo))
+}
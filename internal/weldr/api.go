@@ -3,6 +3,7 @@ package weldr
 import (
 	"archive/tar"
 	"bytes"
+	"context"
 	"crypto/rand"
 	"encoding/json"
 	errors_package "errors"
@@ -30,13 +31,16 @@ import (
 
 	"github.com/osbuild/osbuild-composer/internal/blueprint"
 	"github.com/osbuild/osbuild-composer/internal/common"
+	"github.com/osbuild/osbuild-composer/internal/compare"
 	"github.com/osbuild/osbuild-composer/internal/distro"
 	"github.com/osbuild/osbuild-composer/internal/distroregistry"
 	"github.com/osbuild/osbuild-composer/internal/jobqueue"
+	"github.com/osbuild/osbuild-composer/internal/notification"
 	osbuild "github.com/osbuild/osbuild-composer/internal/osbuild2"
 	"github.com/osbuild/osbuild-composer/internal/ostree"
 	"github.com/osbuild/osbuild-composer/internal/reporegistry"
 	"github.com/osbuild/osbuild-composer/internal/rpmmd"
+	"github.com/osbuild/osbuild-composer/internal/sbom"
 	"github.com/osbuild/osbuild-composer/internal/store"
 	"github.com/osbuild/osbuild-composer/internal/target"
 	"github.com/osbuild/osbuild-composer/internal/worker"
@@ -61,6 +65,11 @@ type API struct {
 
 	//  List of ImageType names, which should not be exposed by the API
 	distrosImageTypeDenylist map[string][]string
+
+	// see UsersConfig
+	multiUser bool
+	admins    map[string]bool
+	tokens    map[string]string
 }
 
 type ComposeState int
@@ -191,6 +200,7 @@ func New(repoPaths []string, stateDir string, rpm rpmmd.RPMMD, dr *distroregistr
 		distros:                  validDistros(rr, dr, hostArch.Name(), logger),
 		distrosImageTypeDenylist: distrosImageTypeDenylist,
 	}
+
 	return setupRouter(api), nil
 }
 
@@ -205,9 +215,15 @@ func setupRouter(api *API) *API {
 	api.router.GET("/api/v:version/projects/source/list", api.sourceListHandler)
 	api.router.GET("/api/v:version/projects/source/info/", api.sourceEmptyInfoHandler)
 	api.router.GET("/api/v:version/projects/source/info/:sources", api.sourceInfoHandler)
+	api.router.GET("/api/v:version/projects/source/health/:sources", api.sourceHealthHandler)
 	api.router.POST("/api/v:version/projects/source/new", api.sourceNewHandler)
 	api.router.DELETE("/api/v:version/projects/source/delete/*source", api.sourceDeleteHandler)
 
+	api.router.GET("/api/v:version/projects/snapshots/list", api.snapshotsListHandler)
+	api.router.GET("/api/v:version/projects/snapshots/info/:name", api.snapshotsInfoHandler)
+	api.router.POST("/api/v:version/projects/snapshots/new", api.snapshotsNewHandler)
+	api.router.DELETE("/api/v:version/projects/snapshots/delete/:name", api.snapshotsDeleteHandler)
+
 	api.router.GET("/api/v:version/projects/depsolve", api.projectsDepsolveHandler)
 	api.router.GET("/api/v:version/projects/depsolve/*projects", api.projectsDepsolveHandler)
 
@@ -245,12 +261,20 @@ func setupRouter(api *API) *API {
 	api.router.GET("/api/v:version/compose/failed", api.composeFailedHandler)
 	api.router.GET("/api/v:version/compose/image/:uuid", api.composeImageHandler)
 	api.router.GET("/api/v:version/compose/metadata/:uuid", api.composeMetadataHandler)
+	api.router.GET("/api/v:version/compose/advisories/:uuid", api.composeAdvisoriesHandler)
+	api.router.GET("/api/v:version/compose/diff/:from/:to", api.composeDiffHandler)
 	api.router.GET("/api/v:version/compose/results/:uuid", api.composeResultsHandler)
 	api.router.GET("/api/v:version/compose/logs/:uuid", api.composeLogsHandler)
 	api.router.GET("/api/v:version/compose/log/:uuid", api.composeLogHandler)
+	api.router.GET("/api/v:version/compose/events", api.composeEventsHandler)
 	api.router.POST("/api/v:version/compose/uploads/schedule/:uuid", api.uploadsScheduleHandler)
 	api.router.DELETE("/api/v:version/compose/cancel/:uuid", api.composeCancelHandler)
 
+	api.router.GET("/api/v:version/compose/schedules/list", api.schedulesListHandler)
+	api.router.GET("/api/v:version/compose/schedules/info/:uuid", api.schedulesInfoHandler)
+	api.router.POST("/api/v:version/compose/schedules/new", api.schedulesNewHandler)
+	api.router.DELETE("/api/v:version/compose/schedules/delete/:uuid", api.schedulesDeleteHandler)
+
 	api.router.DELETE("/api/v:version/upload/delete/:uuid", api.uploadsDeleteHandler)
 	api.router.GET("/api/v:version/upload/info/:uuid", api.uploadsInfoHandler)
 	api.router.GET("/api/v:version/upload/log/:uuid", api.uploadsLogHandler)
@@ -266,7 +290,7 @@ func setupRouter(api *API) *API {
 }
 
 func (api *API) Serve(listener net.Listener) error {
-	server := http.Server{Handler: api}
+	server := http.Server{Handler: api, ConnContext: api.connContext}
 
 	err := server.Serve(listener)
 	if err != nil && err != http.ErrServerClosed {
@@ -282,6 +306,12 @@ func (api *API) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
 	}
 
 	writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+	request, ok := api.authenticate(writer, request)
+	if !ok {
+		return
+	}
+
 	api.router.ServeHTTP(writer, request)
 }
 
@@ -291,6 +321,8 @@ type composeStatus struct {
 	Started  time.Time
 	Finished time.Time
 	Result   *osbuild.Result
+	// the ID of the job whose result was reused for this compose
+	DeduplicatedFrom uuid.UUID
 }
 
 func composeStateFromJobStatus(js *worker.JobStatus, result *worker.OSBuildJobResult) ComposeState {
@@ -350,13 +382,17 @@ func (api *API) getComposeStatus(compose store.Compose) *composeStatus {
 		panic(err)
 	}
 
-	return &composeStatus{
+	status := &composeStatus{
 		State:    composeStateFromJobStatus(jobStatus, &result),
 		Queued:   jobStatus.Queued,
 		Started:  jobStatus.Started,
 		Finished: jobStatus.Finished,
 		Result:   result.OSBuildOutput,
 	}
+	if result.DeduplicatedFrom != nil {
+		status.DeduplicatedFrom = *result.DeduplicatedFrom
+	}
+	return status
 }
 
 // Opens the image file for `compose`. This asks the worker server for the
@@ -581,12 +617,15 @@ func (api *API) sourceListHandler(writer http.ResponseWriter, request *http.Requ
 
 	// The v0 API used the repo Name, a descriptive string, as the key
 	// In the v1 API this was changed to separate the Name and the Id (a short identifier)
-	var names []string
-	if isRequestVersionAtLeast(params, 1) {
-		names = api.store.ListSourcesById()
-	} else {
-		names = api.store.ListSourcesByName()
+	names := []string{}
+	for id, source := range api.getAllSourcesByID(request) {
+		if isRequestVersionAtLeast(params, 1) {
+			names = append(names, id)
+		} else {
+			names = append(names, source.Name)
+		}
 	}
+	sort.Strings(names)
 	names = append(names, api.systemRepoNames()...)
 
 	err := json.NewEncoder(writer).Encode(reply{
@@ -610,7 +649,7 @@ func (api *API) sourceEmptyInfoHandler(writer http.ResponseWriter, request *http
 
 // getSourceConfigs retrieves the list of sources from the system repos an store
 // Returning a list of store.SourceConfig entries indexed by the id of the source
-func (api *API) getSourceConfigs(params httprouter.Params) (map[string]store.SourceConfig, []responseError) {
+func (api *API) getSourceConfigs(request *http.Request, params httprouter.Params) (map[string]store.SourceConfig, []responseError) {
 	names := params.ByName("sources")
 
 	sources := map[string]store.SourceConfig{}
@@ -627,7 +666,7 @@ func (api *API) getSourceConfigs(params httprouter.Params) (map[string]store.Sou
 
 	// if names is "*" we want all sources
 	if names == "*" {
-		sources = api.store.GetAllSourcesByID()
+		sources = api.getAllSourcesByID(request)
 		for _, repo := range repos {
 			sources[repo.Name] = store.NewSourceConfig(repo, true)
 		}
@@ -646,7 +685,7 @@ func (api *API) getSourceConfigs(params httprouter.Params) (map[string]store.Sou
 				continue
 			}
 			// check if the source is in the store
-			if source := api.store.GetSource(name); source != nil {
+			if source := api.getSource(request, name); source != nil {
 				sources[name] = *source
 			} else {
 				error := responseError{
@@ -681,7 +720,7 @@ func (api *API) sourceInfoHandler(writer http.ResponseWriter, request *http.Requ
 
 // sourceInfoHandlerV0 handles the API v0 response
 func (api *API) sourceInfoHandlerV0(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
-	sources, errors := api.getSourceConfigs(params)
+	sources, errors := api.getSourceConfigs(request, params)
 
 	// V0 responses use the source name as the key
 	v0Sources := make(map[string]SourceConfigV0, len(sources))
@@ -722,7 +761,7 @@ func (api *API) sourceInfoHandlerV0(writer http.ResponseWriter, request *http.Re
 
 // sourceInfoHandlerV1 handles the API v0 response
 func (api *API) sourceInfoHandlerV1(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
-	sources, errors := api.getSourceConfigs(params)
+	sources, errors := api.getSourceConfigs(request, params)
 
 	// V1 responses use the source id as the key
 	v1Sources := make(map[string]SourceConfigV1, len(sources))
@@ -877,6 +916,9 @@ func (api *API) sourceNewHandler(writer http.ResponseWriter, request *http.Reque
 			err = errors_package.New("'type' field is missing from request")
 		} else if len(source.SourceConfig().URL) == 0 {
 			err = errors_package.New("'url' field is missing from request")
+		} else {
+			sourceConfig := source.SourceConfig()
+			err = sourceConfig.RepoConfig(source.GetKey()).ValidateProxy()
 		}
 	}
 	if err != nil {
@@ -917,7 +959,18 @@ func (api *API) sourceNewHandler(writer http.ResponseWriter, request *http.Reque
 		}
 	}
 
-	api.store.PushSource(source.GetKey(), source.SourceConfig())
+	sourceConfig := source.SourceConfig()
+	sourceConfig.Owner = api.owner(request)
+	if existing := api.store.GetSource(source.GetKey()); existing != nil {
+		if !api.canAccess(requestUserName(request), existing.Owner) {
+			ownerError(writer, "source", source.GetKey())
+			return
+		}
+		// replacing a source doesn't change its owner
+		sourceConfig.Owner = existing.Owner
+	}
+
+	api.store.PushSource(source.GetKey(), sourceConfig)
 
 	statusResponseOK(writer)
 }
@@ -952,7 +1005,7 @@ func (api *API) sourceDeleteHandler(writer http.ResponseWriter, request *http.Re
 	}
 
 	// Return an error for unknown sources
-	s := api.store.GetSource(name[0][1:])
+	s := api.getSource(request, name[0][1:])
 	if s == nil {
 		errors := responseError{
 			ID:  "UnknownSource",
@@ -962,12 +1015,9 @@ func (api *API) sourceDeleteHandler(writer http.ResponseWriter, request *http.Re
 		return
 	}
 
-	// Only delete the first name, which will have a / at the start because of the /*source route
-	if isRequestVersionAtLeast(params, 1) {
-		api.store.DeleteSourceByID(name[0][1:])
-	} else {
-		api.store.DeleteSourceByName(name[0][1:])
-	}
+	// Only delete the first name, which will have a / at the start because of the /*source route.
+	// Delete it by id for all versions, that is the source whose owner was checked above.
+	api.store.DeleteSourceByID(name[0][1:])
 
 	statusResponseOK(writer)
 }
@@ -1013,7 +1063,7 @@ func (api *API) modulesListHandler(writer http.ResponseWriter, request *http.Req
 		statusResponseError(writer, http.StatusBadRequest, errors)
 		return
 	}
-	availablePackages, err := api.fetchPackageList(distroName)
+	availablePackages, err := api.fetchPackageList(distroName, api.owner(request))
 
 	if err != nil {
 		errors := responseError{
@@ -1110,7 +1160,7 @@ func (api *API) projectsListHandler(writer http.ResponseWriter, request *http.Re
 		statusResponseError(writer, http.StatusBadRequest, errors)
 		return
 	}
-	availablePackages, err := api.fetchPackageList(distroName)
+	availablePackages, err := api.fetchPackageList(distroName, api.owner(request))
 
 	if err != nil {
 		errors := responseError{
@@ -1193,7 +1243,7 @@ func (api *API) modulesInfoHandler(writer http.ResponseWriter, request *http.Req
 		statusResponseError(writer, http.StatusBadRequest, errors)
 		return
 	}
-	availablePackages, err := api.fetchPackageList(distroName)
+	availablePackages, err := api.fetchPackageList(distroName, api.owner(request))
 
 	if err != nil {
 		errors := responseError{
@@ -1227,7 +1277,7 @@ func (api *API) modulesInfoHandler(writer http.ResponseWriter, request *http.Req
 	packageInfos := foundPackages.ToPackageInfos()
 
 	if modulesRequested {
-		repos, err := api.allRepositories(distroName)
+		repos, err := api.allRepositories(distroName, api.owner(request))
 		if err != nil {
 			errors := responseError{
 				ID:  "InternalError",
@@ -1313,7 +1363,7 @@ func (api *API) projectsDepsolveHandler(writer http.ResponseWriter, request *htt
 		return
 	}
 
-	repos, err := api.allRepositories(distroName)
+	repos, err := api.allRepositories(distroName, api.owner(request))
 	if err != nil {
 		errors := responseError{
 			ID:  "ProjectsError",
@@ -1365,7 +1415,7 @@ func (api *API) blueprintsListHandler(writer http.ResponseWriter, request *http.
 		return
 	}
 
-	names := api.store.ListBlueprints()
+	names := api.listBlueprints(request)
 	total := uint(len(names))
 	offset = min(offset, total)
 	limit = min(limit, total-offset)
@@ -1427,7 +1477,7 @@ func (api *API) blueprintsInfoHandler(writer http.ResponseWriter, request *http.
 	blueprintErrors := []responseError{}
 
 	for _, name := range names {
-		blueprint, changed := api.store.GetBlueprint(name)
+		blueprint, changed := api.getBlueprint(request, name)
 		if blueprint == nil {
 			blueprintErrors = append(blueprintErrors, responseError{
 				ID:  "UnknownBlueprint",
@@ -1485,12 +1535,37 @@ func (api *API) blueprintsDepsolveHandler(writer http.ResponseWriter, request *h
 	type entry struct {
 		Blueprint    blueprint.Blueprint `json:"blueprint"`
 		Dependencies []rpmmd.PackageSpec `json:"dependencies"`
+		// PackageSets explains the package sets of the image type,
+		// only with ?explain=1
+		PackageSets map[string][]PackageExplanationV0 `json:"package_sets,omitempty"`
 	}
 	type reply struct {
 		Blueprints []entry         `json:"blueprints"`
 		Errors     []responseError `json:"errors"`
 	}
 
+	// ?explain=1&type=<compose type> also depsolves the package sets of
+	// the image type and explains why each package is part of them
+	q, err := url.ParseQuery(request.URL.RawQuery)
+	if err != nil {
+		errors := responseError{
+			ID:  "InvalidChars",
+			Msg: fmt.Sprintf("invalid query string: %v", err),
+		}
+		statusResponseError(writer, http.StatusBadRequest, errors)
+		return
+	}
+	explain := q.Get("explain") == "1"
+	composeType := q.Get("type")
+	if explain && composeType == "" {
+		errors := responseError{
+			ID:  "UnknownComposeType",
+			Msg: "explain requires the compose type to be set with ?type=",
+		}
+		statusResponseError(writer, http.StatusBadRequest, errors)
+		return
+	}
+
 	names := strings.Split(params.ByName("blueprints"), ",")
 	if names[0] == "/" {
 		errors := responseError{
@@ -1512,7 +1587,7 @@ func (api *API) blueprintsDepsolveHandler(writer http.ResponseWriter, request *h
 	blueprints := []entry{}
 	blueprintsErrors := []responseError{}
 	for _, name := range names {
-		blueprint, _ := api.store.GetBlueprint(name)
+		blueprint, _ := api.getBlueprint(request, name)
 		if blueprint == nil {
 			blueprintsErrors = append(blueprintsErrors, responseError{
 				ID:  "UnknownBlueprint",
@@ -1521,7 +1596,7 @@ func (api *API) blueprintsDepsolveHandler(writer http.ResponseWriter, request *h
 			continue
 		}
 
-		dependencies, err := api.depsolveBlueprint(*blueprint)
+		dependencies, err := api.depsolveBlueprint(*blueprint, api.owner(request))
 
 		if err != nil {
 			blueprintsErrors = append(blueprintsErrors, responseError{
@@ -1531,10 +1606,21 @@ func (api *API) blueprintsDepsolveHandler(writer http.ResponseWriter, request *h
 			dependencies = []rpmmd.PackageSpec{}
 		}
 
-		blueprints = append(blueprints, entry{*blueprint, dependencies})
+		var packageSets map[string][]PackageExplanationV0
+		if explain {
+			packageSets, err = api.explainBlueprint(*blueprint, composeType, api.owner(request))
+			if err != nil {
+				blueprintsErrors = append(blueprintsErrors, responseError{
+					ID:  "BlueprintsError",
+					Msg: fmt.Sprintf("%s: %s", name, err.Error()),
+				})
+			}
+		}
+
+		blueprints = append(blueprints, entry{*blueprint, dependencies, packageSets})
 	}
 
-	err := json.NewEncoder(writer).Encode(reply{
+	err = json.NewEncoder(writer).Encode(reply{
 		Blueprints: blueprints,
 		Errors:     blueprintsErrors,
 	})
@@ -1599,7 +1685,7 @@ func (api *API) blueprintsFreezeHandler(writer http.ResponseWriter, request *htt
 	blueprints := []blueprintFrozen{}
 	errors := []responseError{}
 	for _, name := range names {
-		bp, _ := api.store.GetBlueprint(name)
+		bp, _ := api.getBlueprint(request, name)
 		if bp == nil {
 			rerr := responseError{
 				ID:  "UnknownBlueprint",
@@ -1610,7 +1696,7 @@ func (api *API) blueprintsFreezeHandler(writer http.ResponseWriter, request *htt
 		}
 		// Make a copy of the blueprint since we will be replacing the version globs
 		blueprint := bp.DeepCopy()
-		dependencies, err := api.depsolveBlueprint(blueprint)
+		dependencies, err := api.depsolveBlueprint(blueprint, api.owner(request))
 		if err != nil {
 			rerr := responseError{
 				ID:  "BlueprintsError",
@@ -1739,8 +1825,8 @@ func (api *API) blueprintsDiffHandler(writer http.ResponseWriter, request *http.
 	}
 
 	// Fetch old and new blueprint details from store and return error if not found
-	oldBlueprint := api.store.GetBlueprintCommitted(name)
-	newBlueprint, _ := api.store.GetBlueprint(name)
+	oldBlueprint := api.getBlueprintCommitted(request, name)
+	newBlueprint, _ := api.getBlueprint(request, name)
 	if oldBlueprint == nil || newBlueprint == nil {
 		errors := responseError{
 			ID:  "UnknownBlueprint",
@@ -1831,7 +1917,10 @@ func (api *API) blueprintsChangesHandler(writer http.ResponseWriter, request *ht
 	allChanges := []change{}
 	errors := []responseError{}
 	for _, name := range names {
-		bpChanges := api.store.GetBlueprintChanges(name)
+		var bpChanges []blueprint.Change
+		if api.canAccessBlueprint(request, name) {
+			bpChanges = api.store.GetBlueprintChanges(name)
+		}
 		// Reverse the changes, newest first
 		reversed := make([]blueprint.Change, 0, len(bpChanges))
 		for i := len(bpChanges) - 1; i >= 0; i-- {
@@ -1921,6 +2010,10 @@ func (api *API) blueprintsNewHandler(writer http.ResponseWriter, request *http.R
 		}
 	}
 
+	if !api.claimBlueprint(writer, request, blueprint.Name) {
+		return
+	}
+
 	commitMsg := "Recipe " + blueprint.Name + ", version " + blueprint.Version + " saved."
 	err = api.store.PushBlueprint(blueprint, commitMsg)
 	if err != nil {
@@ -1982,6 +2075,10 @@ func (api *API) blueprintsWorkspaceHandler(writer http.ResponseWriter, request *
 		return
 	}
 
+	if !api.claimBlueprint(writer, request, blueprint.Name) {
+		return
+	}
+
 	err = api.store.PushBlueprintToWorkspace(blueprint)
 	if err != nil {
 		errors := responseError{
@@ -2010,6 +2107,15 @@ func (api *API) blueprintUndoHandler(writer http.ResponseWriter, request *http.R
 		return
 	}
 
+	if !api.canAccessBlueprint(request, name) {
+		errors := responseError{
+			ID:  "UnknownCommit",
+			Msg: "Unknown blueprint",
+		}
+		statusResponseError(writer, http.StatusBadRequest, errors)
+		return
+	}
+
 	bpChange, err := api.store.GetBlueprintChange(name, commit)
 	if err != nil {
 		errors := responseError{
@@ -2053,6 +2159,15 @@ func (api *API) blueprintDeleteHandler(writer http.ResponseWriter, request *http
 		return
 	}
 
+	if !api.canAccessBlueprint(request, name) {
+		errors := responseError{
+			ID:  "BlueprintsError",
+			Msg: fmt.Sprintf("Unknown blueprint: %s", name),
+		}
+		statusResponseError(writer, http.StatusBadRequest, errors)
+		return
+	}
+
 	if err := api.store.DeleteBlueprint(name); err != nil {
 		errors := responseError{
 			ID:  "BlueprintsError",
@@ -2074,6 +2189,15 @@ func (api *API) blueprintDeleteWorkspaceHandler(writer http.ResponseWriter, requ
 		return
 	}
 
+	if !api.canAccessBlueprint(request, name) {
+		errors := responseError{
+			ID:  "BlueprintsError",
+			Msg: fmt.Sprintf("Unknown blueprint: %s", name),
+		}
+		statusResponseError(writer, http.StatusBadRequest, errors)
+		return
+	}
+
 	if err := api.store.DeleteBlueprintFromWorkspace(name); err != nil {
 		errors := responseError{
 			ID:  "BlueprintsError",
@@ -2096,6 +2220,15 @@ func (api *API) blueprintsTagHandler(writer http.ResponseWriter, request *http.R
 		return
 	}
 
+	if !api.canAccessBlueprint(request, name) {
+		errors := responseError{
+			ID:  "BlueprintsError",
+			Msg: "Unknown blueprint",
+		}
+		statusResponseError(writer, http.StatusBadRequest, errors)
+		return
+	}
+
 	err := api.store.TagBlueprint(name)
 	if err != nil {
 		errors := responseError{
@@ -2109,38 +2242,46 @@ func (api *API) blueprintsTagHandler(writer http.ResponseWriter, request *http.R
 }
 
 // depsolveBlueprintForImageType handles depsolving the blueprint package list and
-// the packages required for the image type.
+// the packages required for the image type. It also returns the checksums of
+// the repositories' metadata the packages were depsolved against. With
+// `explain`, the returned PackageSpecs record why each package is part of its
+// set. Only sources which can be used by `owner` are included.
 // NOTE: The imageType *must* be from the same distribution as the blueprint.
-func (api *API) depsolveBlueprintForImageType(bp blueprint.Blueprint, imageType distro.ImageType) (map[string][]rpmmd.PackageSpec, error) {
+func (api *API) depsolveBlueprintForImageType(bp blueprint.Blueprint, imageType distro.ImageType, explain bool, owner string) (map[string][]rpmmd.PackageSpec, map[string]string, error) {
 	// Depsolve using the host distro if none has been specified
 	if bp.Distro == "" {
 		bp.Distro = api.hostDistroName
 	}
 
 	if bp.Distro != imageType.Arch().Distro().Name() {
-		return nil, fmt.Errorf("Blueprint distro %s does not match imageType distro %s", bp.Distro, imageType.Arch().Distro().Name())
+		return nil, nil, fmt.Errorf("Blueprint distro %s does not match imageType distro %s", bp.Distro, imageType.Arch().Distro().Name())
 	}
 	packageSets := imageType.PackageSets(bp)
 	packageSpecSets := make(map[string][]rpmmd.PackageSpec)
+	repoChecksums := make(map[string]string)
 
-	imageTypeRepos, err := api.allRepositoriesByImageType(imageType)
+	imageTypeRepos, err := api.allRepositoriesByImageType(imageType, owner)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	platformID := imageType.Arch().Distro().ModulePlatformID()
 	releasever := imageType.Arch().Distro().Releasever()
 	for name, packageSet := range packageSets {
-		packageSpecs, _, err := api.rpmmd.Depsolve(packageSet,
+		packageSet.Explain = explain
+		packageSpecs, checksums, err := api.rpmmd.Depsolve(packageSet,
 			imageTypeRepos,
 			platformID,
 			api.arch.Name(),
 			releasever)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		packageSpecSets[name] = packageSpecs
+		for repo, checksum := range checksums {
+			repoChecksums[repo] = checksum
+		}
 	}
-	return packageSpecSets, nil
+	return packageSpecSets, repoChecksums, nil
 }
 
 // Schedule new compose by first translating the appropriate blueprint into a pipeline and then
@@ -2158,10 +2299,19 @@ func (api *API) composeHandler(writer http.ResponseWriter, request *http.Request
 		OSTree        ostree.RequestParams `json:"ostree"`
 		Branch        string               `json:"branch"`
 		Upload        *uploadRequest       `json:"upload"`
+		// Snapshot is the name of a repository snapshot to pin the
+		// compose to. SnapshotPolicy is either "fail" (the default)
+		// or "warn".
+		Snapshot       string `json:"snapshot"`
+		SnapshotPolicy string `json:"snapshot_policy"`
+		// Webhooks are notified whenever the status of the compose
+		// changes.
+		Webhooks []notification.Webhook `json:"webhooks"`
 	}
 	type ComposeReply struct {
-		BuildID uuid.UUID `json:"build_id"`
-		Status  bool      `json:"status"`
+		BuildID  uuid.UUID `json:"build_id"`
+		Status   bool      `json:"status"`
+		Warnings []string  `json:"warnings,omitempty"`
 	}
 
 	contentType := request.Header["Content-Type"]
@@ -2190,7 +2340,7 @@ func (api *API) composeHandler(writer http.ResponseWriter, request *http.Request
 		return
 	}
 
-	bp := api.store.GetBlueprintCommitted(cr.BlueprintName)
+	bp := api.getBlueprintCommitted(request, cr.BlueprintName)
 	if bp == nil {
 		errors := responseError{
 			ID:  "UnknownBlueprint",
@@ -2224,10 +2374,47 @@ func (api *API) composeHandler(writer http.ResponseWriter, request *http.Request
 		return
 	}
 
+	var pin *snapshotPin
+	if isRequestVersionAtLeast(params, 1) && cr.Snapshot != "" {
+		pin, err = api.newSnapshotPin(cr.Snapshot, cr.SnapshotPolicy, distroName)
+		if err != nil {
+			errors := responseError{
+				ID:  "SnapshotError",
+				Msg: err.Error(),
+			}
+			statusResponseError(writer, http.StatusBadRequest, errors)
+			return
+		}
+	}
+
+	for _, webhook := range cr.Webhooks {
+		err = webhook.Validate()
+		if err == nil && !api.workers.NotificationsEnabled() {
+			err = worker.ErrNotificationsDisabled
+		}
+		if err != nil {
+			errors := responseError{
+				ID:  "WebhookError",
+				Msg: err.Error(),
+			}
+			statusResponseError(writer, http.StatusBadRequest, errors)
+			return
+		}
+	}
+
 	composeID := uuid.New()
 
 	var targets []*target.Target
 	if isRequestVersionAtLeast(params, 1) && cr.Upload != nil {
+		err = checkUploadRequest(*cr.Upload, imageType)
+		if err != nil {
+			errors := responseError{
+				ID:  "UploadError",
+				Msg: err.Error(),
+			}
+			statusResponseError(writer, http.StatusBadRequest, errors)
+			return
+		}
 		t := uploadRequestToTarget(*cr.Upload, imageType)
 		targets = append(targets, t)
 	}
@@ -2257,16 +2444,67 @@ func (api *API) composeHandler(writer http.ResponseWriter, request *http.Request
 			return
 		}
 		cr.OSTree = ostreeParams
+
+		// publish the commit under the ref it was built for
+		for _, t := range targets {
+			if options, ok := t.Options.(*target.OSTreeTargetOptions); ok {
+				options.Ref = cr.OSTree.Ref
+			}
+		}
 	}
 
-	packageSets, err := api.depsolveBlueprintForImageType(*bp, imageType)
+	warnings, cerr := api.startCompose(composeID, bp, imageType, cr.Size, cr.OSTree, targets, pin, testMode, api.owner(request), cr.Webhooks)
+	if cerr != nil {
+		statusResponseError(writer, cerr.status, cerr.responseError)
+		return
+	}
+
+	err = json.NewEncoder(writer).Encode(ComposeReply{
+		BuildID:  composeID,
+		Status:   true,
+		Warnings: warnings,
+	})
+	common.PanicOnError(err)
+}
+
+// composeError is returned by startCompose(). It contains the error which
+// should be reported to the client, together with the HTTP status code.
+type composeError struct {
+	status int
+	responseError
+}
+
+// startCompose depsolves the blueprint, generates the manifest for the image
+// type, and enqueues the compose with id `composeID`. The `ostreeParams` must
+// already be resolved. When `pin` is not nil, the repositories are verified
+// against its snapshot; warnings about changed repositories are returned.
+// `testMode` corresponds to the `test` query parameter of the compose route:
+// "1" and "2" only create a failed or finished compose in the store,
+// respectively, without running a job. The compose is owned by `owner` and
+// only uses the sources which can be used by them. Changes of its status are
+// sent to `webhooks`.
+func (api *API) startCompose(composeID uuid.UUID, bp *blueprint.Blueprint, imageType distro.ImageType, requestedSize uint64,
+	ostreeParams ostree.RequestParams, targets []*target.Target, pin *snapshotPin, testMode, owner string, webhooks []notification.Webhook) ([]string, *composeError) {
+	packageSets, repoChecksums, err := api.depsolveBlueprintForImageType(*bp, imageType, false, owner)
 	if err != nil {
-		errors := responseError{
+		return nil, &composeError{http.StatusInternalServerError, responseError{
 			ID:  "DepsolveError",
 			Msg: err.Error(),
+		}}
+	}
+
+	var warnings []string
+	if pin != nil {
+		err := rpmmd.VerifyRepoChecksums(pin.snapshot.Checksums, repoChecksums)
+		if err != nil && !pin.warnOnly {
+			return nil, &composeError{http.StatusBadRequest, responseError{
+				ID:  "SnapshotMismatch",
+				Msg: fmt.Sprintf("snapshot %s: %v", pin.snapshot.Name, err),
+			}}
+		} else if err != nil {
+			log.Printf("compose %s is not reproducible from snapshot %s: %v", composeID, pin.snapshot.Name, err)
+			warnings = append(warnings, fmt.Sprintf("snapshot %s: %v", pin.snapshot.Name, err))
 		}
-		statusResponseError(writer, http.StatusInternalServerError, errors)
-		return
 	}
 
 	var size uint64
@@ -2274,10 +2512,10 @@ func (api *API) composeHandler(writer http.ResponseWriter, request *http.Request
 	// check if filesytem customizations have been set.
 	// if compose size parameter is set, take the larger of
 	// the two values
-	if minSize := bp.Customizations.GetFilesystemsMinSize(); bp.Customizations != nil && minSize > 0 && minSize > cr.Size {
+	if minSize := bp.Customizations.GetFilesystemsMinSize(); bp.Customizations != nil && minSize > 0 && minSize > requestedSize {
 		size = imageType.Size(minSize)
 	} else {
-		size = imageType.Size(cr.Size)
+		size = imageType.Size(requestedSize)
 	}
 
 	bigSeed, err := rand.Int(rand.Reader, big.NewInt(math.MaxInt64))
@@ -2286,44 +2524,69 @@ func (api *API) composeHandler(writer http.ResponseWriter, request *http.Request
 	}
 	seed := bigSeed.Int64()
 
-	imageRepos, err := api.allRepositoriesByImageType(imageType)
+	imageRepos, err := api.allRepositoriesByImageType(imageType, owner)
 	// this should not happen if the api.depsolveBlueprintForImageType() call above worked
 	if err != nil {
-		errors := responseError{
+		return nil, &composeError{http.StatusInternalServerError, responseError{
 			ID:  "InternalError",
 			Msg: err.Error(),
-		}
-		statusResponseError(writer, http.StatusInternalServerError, errors)
-		return
+		}}
 	}
 
-	manifest, err := imageType.Manifest(bp.Customizations,
-		distro.ImageOptions{
-			Size: size,
-			OSTree: ostree.RequestParams{
-				Ref:    cr.OSTree.Ref,
-				Parent: cr.OSTree.Parent,
-				URL:    cr.OSTree.URL,
-			},
+	proxy, err := rpmmd.CommonProxy(imageRepos)
+	if err != nil {
+		return nil, &composeError{http.StatusBadRequest, responseError{
+			ID:  "ProjectsError",
+			Msg: err.Error(),
+		}}
+	}
+
+	imageOptions := distro.ImageOptions{
+		Size: size,
+		OSTree: ostree.RequestParams{
+			Ref:         ostreeParams.Ref,
+			Parent:      ostreeParams.Parent,
+			URL:         ostreeParams.URL,
+			Sign:        ostreeParams.Sign,
+			StaticDelta: ostreeParams.StaticDelta,
+			RHSM:        ostreeParams.RHSM,
+			GPGKey:      ostreeParams.GPGKey,
 		},
+		EnabledModules: bp.EnabledModules,
+	}
+
+	ostreeCommit, err := worker.NewOSTreeCommitOptions(imageType, ostreeParams)
+	if err != nil {
+		return nil, &composeError{http.StatusBadRequest, responseError{
+			ID:  "OSTreeOptionsError",
+			Msg: err.Error(),
+		}}
+	}
+
+	manifest, err := imageType.Manifest(bp.Customizations,
+		imageOptions,
 		imageRepos,
 		packageSets,
 		seed)
 	if err != nil {
-		errors := responseError{
+		return nil, &composeError{http.StatusBadRequest, responseError{
 			ID:  "ManifestCreationFailed",
 			Msg: fmt.Sprintf("failed to create osbuild manifest: %v", err),
-		}
-		statusResponseError(writer, http.StatusBadRequest, errors)
-		return
+		}}
+	}
+
+	contentHash, err := worker.ContentHash(imageType, packageSets, bp.Customizations, imageOptions)
+	if err != nil {
+		// not fatal, the image just can't be deduplicated
+		log.Printf("error computing content hash for compose %s: %v", composeID, err)
 	}
 
 	if testMode == "1" {
 		// Create a failed compose
-		err = api.store.PushTestCompose(composeID, manifest, imageType, bp, size, targets, false, packageSets["packages"])
+		err = api.store.PushTestCompose(composeID, manifest, imageType, bp, size, targets, false, packageSets["packages"], repoChecksums, owner)
 	} else if testMode == "2" {
 		// Create a successful compose
-		err = api.store.PushTestCompose(composeID, manifest, imageType, bp, size, targets, true, packageSets["packages"])
+		err = api.store.PushTestCompose(composeID, manifest, imageType, bp, size, targets, true, packageSets["packages"], repoChecksums, owner)
 	} else {
 		var jobId uuid.UUID
 
@@ -2337,9 +2600,23 @@ func (api *API) composeHandler(writer http.ResponseWriter, request *http.Request
 				Build:   imageType.BuildPipelines(),
 				Payload: imageType.PayloadPipelines(),
 			},
+			ContentHash:  contentHash,
+			Distro:       imageType.Arch().Distro().Name(),
+			PackageSpecs: packageSets["packages"],
+			Proxy:        proxy,
+			OSTreeCommit: ostreeCommit,
 		}, "")
 		if err == nil {
-			err = api.store.PushCompose(composeID, manifest, imageType, bp, size, targets, jobId, packageSets["packages"])
+			err = api.store.PushCompose(composeID, manifest, imageType, bp, size, targets, jobId, packageSets["packages"], repoChecksums, owner)
+		}
+		if err == nil {
+			err = api.workers.SubscribeCompose(composeID, jobId, webhooks)
+		}
+		if err == nil {
+			_, dedupErr := api.workers.DeduplicateOSBuildJob(context.Background(), jobId)
+			if dedupErr != nil {
+				log.Printf("error deduplicating compose %s: %v", composeID, dedupErr)
+			}
 		}
 	}
 
@@ -2347,19 +2624,13 @@ func (api *API) composeHandler(writer http.ResponseWriter, request *http.Request
 	// for now, let's just 500 and bail out
 	if err != nil {
 		log.Println("error when pushing new compose: ", err.Error())
-		errors := responseError{
+		return nil, &composeError{http.StatusInternalServerError, responseError{
 			ID:  "ComposePushErrored",
 			Msg: err.Error(),
-		}
-		statusResponseError(writer, http.StatusInternalServerError, errors)
-		return
+		}}
 	}
 
-	err = json.NewEncoder(writer).Encode(ComposeReply{
-		BuildID: composeID,
-		Status:  true,
-	})
-	common.PanicOnError(err)
+	return warnings, nil
 }
 
 func (api *API) composeDeleteHandler(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
@@ -2392,7 +2663,7 @@ func (api *API) composeDeleteHandler(writer http.ResponseWriter, request *http.R
 			continue
 		}
 
-		compose, exists := api.store.GetCompose(id)
+		compose, exists := api.getCompose(request, id)
 		if !exists {
 			errors = append(errors, composeDeleteError{
 				"UnknownUUID",
@@ -2419,6 +2690,11 @@ func (api *API) composeDeleteHandler(writer http.ResponseWriter, request *http.R
 			continue
 		}
 
+		err = api.workers.UnsubscribeCompose(id, "")
+		if err != nil {
+			log.Printf("error unsubscribing deleted compose %s from notifications: %v", id, err)
+		}
+
 		// Delete artifacts from the worker server or — if that doesn't
 		// have this job — the compat output dir. Ignore errors,
 		// because there's no point of reporting them to the client
@@ -2456,7 +2732,7 @@ func (api *API) composeCancelHandler(writer http.ResponseWriter, request *http.R
 		return
 	}
 
-	compose, exists := api.store.GetCompose(id)
+	compose, exists := api.getCompose(request, id)
 	if !exists {
 		errors := responseError{
 			ID:  "UnknownUUID",
@@ -2568,7 +2844,7 @@ func (api *API) composeQueueHandler(writer http.ResponseWriter, request *http.Re
 
 	includeUploads := isRequestVersionAtLeast(params, 1)
 
-	composes := api.store.GetAllComposes()
+	composes := api.getAllComposes(request)
 	for id, compose := range composes {
 		composeStatus := api.getComposeStatus(compose)
 		switch composeStatus.State {
@@ -2595,7 +2871,7 @@ func (api *API) composeStatusHandler(writer http.ResponseWriter, request *http.R
 
 	uuidsParam := params.ByName("uuids")
 
-	composes := api.store.GetAllComposes()
+	composes := api.getAllComposes(request)
 	uuids := []uuid.UUID{}
 
 	if uuidsParam != "*" {
@@ -2682,7 +2958,7 @@ func (api *API) composeInfoHandler(writer http.ResponseWriter, request *http.Req
 		return
 	}
 
-	compose, exists := api.store.GetCompose(id)
+	compose, exists := api.getCompose(request, id)
 
 	if !exists {
 		errors := responseError{
@@ -2705,6 +2981,8 @@ func (api *API) composeInfoHandler(writer http.ResponseWriter, request *http.Req
 		QueueStatus string           `json:"queue_status"`
 		ImageSize   uint64           `json:"image_size"`
 		Uploads     []uploadResponse `json:"uploads,omitempty"`
+		// the compose whose image was reused instead of building it again
+		DeduplicatedFrom *uuid.UUID `json:"deduplicated_from,omitempty"`
 	}
 
 	reply.ID = id
@@ -2718,6 +2996,15 @@ func (api *API) composeInfoHandler(writer http.ResponseWriter, request *http.Req
 
 	if isRequestVersionAtLeast(params, 1) {
 		reply.Uploads = targetsToUploadResponses(compose.ImageBuild.Targets, composeStatus.State)
+
+		if composeStatus.DeduplicatedFrom != uuid.Nil {
+			for composeID, c := range api.getAllComposes(request) {
+				if c.ImageBuild.JobID == composeStatus.DeduplicatedFrom {
+					reply.DeduplicatedFrom = &composeID
+					break
+				}
+			}
+		}
 	}
 
 	// Add package dependencies from the compose
@@ -2748,7 +3035,7 @@ func (api *API) composeImageHandler(writer http.ResponseWriter, request *http.Re
 		return
 	}
 
-	compose, exists := api.store.GetCompose(uuid)
+	compose, exists := api.getCompose(request, uuid)
 	if !exists {
 		errors := responseError{
 			ID:  "UnknownUUID",
@@ -2789,6 +3076,135 @@ func (api *API) composeImageHandler(writer http.ResponseWriter, request *http.Re
 	common.PanicOnError(err)
 }
 
+// composeAdvisoriesHandler compares the packages of a compose with the update
+// advisories currently published in its repositories. It lists the advisories
+// which are fixed in the compose, and those which are not, because newer
+// packages have been released since it was built.
+func (api *API) composeAdvisoriesHandler(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	if !verifyRequestVersion(writer, params, 1) {
+		return
+	}
+
+	type reply struct {
+		UUID          uuid.UUID        `json:"uuid"`
+		Fixed         []rpmmd.Advisory `json:"fixed"`
+		Unpatched     []rpmmd.Advisory `json:"unpatched"`
+		UnpatchedCVEs []string         `json:"unpatched_cves"`
+	}
+
+	uuidString := params.ByName("uuid")
+	id, err := uuid.Parse(uuidString)
+	if err != nil {
+		errors := responseError{
+			ID:  "UnknownUUID",
+			Msg: fmt.Sprintf("%s is not a valid build uuid", uuidString),
+		}
+		statusResponseError(writer, http.StatusBadRequest, errors)
+		return
+	}
+
+	compose, exists := api.getCompose(request, id)
+	if !exists {
+		errors := responseError{
+			ID:  "UnknownUUID",
+			Msg: fmt.Sprintf("Compose %s doesn't exist", uuidString),
+		}
+		statusResponseError(writer, http.StatusBadRequest, errors)
+		return
+	}
+
+	imageType := compose.ImageBuild.ImageType
+	repos, err := api.allRepositoriesByImageType(imageType, compose.Owner)
+	if err != nil {
+		errors := responseError{
+			ID:  "InternalError",
+			Msg: err.Error(),
+		}
+		statusResponseError(writer, http.StatusInternalServerError, errors)
+		return
+	}
+
+	advisories, err := api.rpmmd.FetchUpdateInfo(repos, imageType.Arch().Name(), imageType.Arch().Distro().Releasever())
+	if err != nil {
+		errors := responseError{
+			ID:  "AdvisoriesError",
+			Msg: err.Error(),
+		}
+		statusResponseError(writer, http.StatusBadGateway, errors)
+		return
+	}
+
+	report := rpmmd.NewAdvisoryReport(advisories, compose.Packages)
+	err = json.NewEncoder(writer).Encode(reply{
+		UUID:          id,
+		Fixed:         report.Fixed,
+		Unpatched:     report.Unpatched,
+		UnpatchedCVEs: report.UnpatchedCVEs(),
+	})
+	common.PanicOnError(err)
+}
+
+func (api *API) composeDiffHandler(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	if !verifyRequestVersion(writer, params, 1) {
+		return
+	}
+
+	type reply struct {
+		From uuid.UUID `json:"from"`
+		To   uuid.UUID `json:"to"`
+		*compare.Diff
+	}
+
+	var ids [2]uuid.UUID
+	var composes [2]compare.Compose
+	for i, name := range []string{"from", "to"} {
+		uuidString := params.ByName(name)
+		id, err := uuid.Parse(uuidString)
+		if err != nil {
+			errors := responseError{
+				ID:  "UnknownUUID",
+				Msg: fmt.Sprintf("%s is not a valid build uuid", uuidString),
+			}
+			statusResponseError(writer, http.StatusBadRequest, errors)
+			return
+		}
+
+		compose, exists := api.getCompose(request, id)
+		if !exists {
+			errors := responseError{
+				ID:  "UnknownUUID",
+				Msg: fmt.Sprintf("Compose %s doesn't exist", uuidString),
+			}
+			statusResponseError(writer, http.StatusBadRequest, errors)
+			return
+		}
+
+		ids[i] = id
+		composes[i] = compare.Compose{
+			Blueprint: compose.Blueprint,
+			Packages:  compose.Packages,
+			Manifest:  compose.ImageBuild.Manifest,
+		}
+	}
+
+	diff, err := compare.Composes(composes[0], composes[1])
+	if err != nil {
+		errors := responseError{
+			ID:  "InternalError",
+			Msg: err.Error(),
+		}
+		statusResponseError(writer, http.StatusInternalServerError, errors)
+		return
+	}
+
+	err = json.NewEncoder(writer).Encode(reply{
+		From: ids[0],
+		To:   ids[1],
+		Diff: diff,
+	})
+	common.PanicOnError(err)
+}
+
 // composeMetadataHandler returns a tar of the metadata used to compose the requested UUID
 func (api *API) composeMetadataHandler(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
 	if !verifyRequestVersion(writer, params, 0) {
@@ -2806,7 +3222,7 @@ func (api *API) composeMetadataHandler(writer http.ResponseWriter, request *http
 		return
 	}
 
-	compose, exists := api.store.GetCompose(uuid)
+	compose, exists := api.getCompose(request, uuid)
 	if !exists {
 		errors := responseError{
 			ID:  "UnknownUUID",
@@ -2846,6 +3262,30 @@ func (api *API) composeMetadataHandler(writer http.ResponseWriter, request *http
 	_, err = tw.Write(metadata)
 	common.PanicOnError(err)
 
+	// add the software bill of materials, if the worker generated one
+	for _, name := range []string{sbom.SPDXArtifact, sbom.CycloneDXArtifact} {
+		reader, size, err := api.workers.JobArtifact(compose.ImageBuild.JobID, name)
+		if err != nil {
+			continue
+		}
+
+		hdr := &tar.Header{
+			Name:    uuid.String() + "-" + name,
+			Mode:    0600,
+			Size:    size,
+			ModTime: time.Now().Truncate(time.Second),
+		}
+		err = tw.WriteHeader(hdr)
+		common.PanicOnError(err)
+
+		_, err = io.Copy(tw, reader)
+		common.PanicOnError(err)
+
+		if closer, ok := reader.(io.Closer); ok {
+			closer.Close()
+		}
+	}
+
 	err = tw.Close()
 	common.PanicOnError(err)
 }
@@ -2867,7 +3307,7 @@ func (api *API) composeResultsHandler(writer http.ResponseWriter, request *http.
 		return
 	}
 
-	compose, exists := api.store.GetCompose(uuid)
+	compose, exists := api.getCompose(request, uuid)
 	if !exists {
 		errors := responseError{
 			ID:  "UnknownUUID",
@@ -2958,7 +3398,7 @@ func (api *API) composeLogsHandler(writer http.ResponseWriter, request *http.Req
 		return
 	}
 
-	compose, exists := api.store.GetCompose(id)
+	compose, exists := api.getCompose(request, id)
 	if !exists {
 		errors := responseError{
 			ID:  "UnknownUUID",
@@ -3022,7 +3462,7 @@ func (api *API) composeLogHandler(writer http.ResponseWriter, request *http.Requ
 		return
 	}
 
-	compose, exists := api.store.GetCompose(id)
+	compose, exists := api.getCompose(request, id)
 	if !exists {
 		errors := responseError{
 			ID:  "UnknownUUID",
@@ -3061,7 +3501,7 @@ func (api *API) composeFinishedHandler(writer http.ResponseWriter, request *http
 	}{[]*ComposeEntry{}}
 
 	includeUploads := isRequestVersionAtLeast(params, 1)
-	for id, compose := range api.store.GetAllComposes() {
+	for id, compose := range api.getAllComposes(request) {
 		composeStatus := api.getComposeStatus(compose)
 		if composeStatus.State != ComposeFinished {
 			continue
@@ -3084,7 +3524,7 @@ func (api *API) composeFailedHandler(writer http.ResponseWriter, request *http.R
 	}{[]*ComposeEntry{}}
 
 	includeUploads := isRequestVersionAtLeast(params, 1)
-	for id, compose := range api.store.GetAllComposes() {
+	for id, compose := range api.getAllComposes(request) {
 		composeStatus := api.getComposeStatus(compose)
 		if composeStatus.State != ComposeFailed {
 			continue
@@ -3097,13 +3537,14 @@ func (api *API) composeFailedHandler(writer http.ResponseWriter, request *http.R
 	common.PanicOnError(err)
 }
 
-// fetchPackageList returns the package list or the selected distribution
-func (api *API) fetchPackageList(distroName string) (rpmmd.PackageList, error) {
+// fetchPackageList returns the package list or the selected distribution,
+// using the sources which can be used by `owner`
+func (api *API) fetchPackageList(distroName, owner string) (rpmmd.PackageList, error) {
 	d := api.getDistro(distroName)
 	if d == nil {
 		return nil, fmt.Errorf("GetDistro - unknown distribution: %s", distroName)
 	}
-	repos, err := api.allRepositories(distroName)
+	repos, err := api.allRepositories(distroName, owner)
 	if err != nil {
 		return nil, err
 	}
@@ -3116,36 +3557,38 @@ func (api *API) fetchPackageList(distroName string) (rpmmd.PackageList, error) {
 // The difference from allRepositories() is that this method may return additional repositories,
 // which are needed to build the specific image type. The allRepositories() can't do this, because
 // it is used in places where image types are not considered.
-func (api *API) allRepositoriesByImageType(imageType distro.ImageType) ([]rpmmd.RepoConfig, error) {
+// Only sources which can be used by `owner` are included.
+func (api *API) allRepositoriesByImageType(imageType distro.ImageType, owner string) ([]rpmmd.RepoConfig, error) {
 	imageTypeRepos, err := api.repoRegistry.ReposByImageType(imageType)
 	if err != nil {
 		return nil, err
 	}
 
 	repos := append([]rpmmd.RepoConfig{}, imageTypeRepos...)
-	for id, source := range api.store.GetAllDistroSources(imageType.Arch().Distro().Name()) {
+	for id, source := range api.getDistroSources(imageType.Arch().Distro().Name(), owner) {
 		repos = append(repos, source.RepoConfig(id))
 	}
 
 	return repos, nil
 }
 
-// Returns all configured repositories (base + sources) as rpmmd.RepoConfig
-func (api *API) allRepositories(distroName string) ([]rpmmd.RepoConfig, error) {
+// Returns all configured repositories (base + sources which can be used by
+// `owner`) as rpmmd.RepoConfig
+func (api *API) allRepositories(distroName, owner string) ([]rpmmd.RepoConfig, error) {
 	archRepos, err := api.repoRegistry.ReposByArchName(distroName, api.arch.Name(), false)
 	if err != nil {
 		return nil, err
 	}
 
 	repos := append([]rpmmd.RepoConfig{}, archRepos...)
-	for id, source := range api.store.GetAllDistroSources(distroName) {
+	for id, source := range api.getDistroSources(distroName, owner) {
 		repos = append(repos, source.RepoConfig(id))
 	}
 
 	return repos, nil
 }
 
-func (api *API) depsolveBlueprint(bp blueprint.Blueprint) ([]rpmmd.PackageSpec, error) {
+func (api *API) depsolveBlueprint(bp blueprint.Blueprint, owner string) ([]rpmmd.PackageSpec, error) {
 	// Depsolve using the host distro if none has been specified
 	if bp.Distro == "" {
 		bp.Distro = api.hostDistroName
@@ -3155,12 +3598,13 @@ func (api *API) depsolveBlueprint(bp blueprint.Blueprint) ([]rpmmd.PackageSpec,
 	if d == nil {
 		return nil, fmt.Errorf("GetDistro - unknown distribution: %s", bp.Distro)
 	}
-	repos, err := api.allRepositories(bp.Distro)
+	repos, err := api.allRepositories(bp.Distro, owner)
 	if err != nil {
 		return nil, err
 	}
 
-	packages, _, err := api.rpmmd.Depsolve(rpmmd.PackageSet{Include: bp.GetPackages()}, repos, d.ModulePlatformID(), api.arch.Name(), d.Releasever())
+	packageSet := rpmmd.PackageSet{Include: bp.GetPackages(), EnabledModules: bp.GetEnabledModules()}
+	packages, _, err := api.rpmmd.Depsolve(packageSet, repos, d.ModulePlatformID(), api.arch.Name(), d.Releasever())
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,136 @@
+package weldr
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/julienschmidt/httprouter"
+	"github.com/sirupsen/logrus"
+
+	"github.com/osbuild/osbuild-composer/internal/common"
+	"github.com/osbuild/osbuild-composer/internal/store"
+)
+
+// composeLogEvent contains lines of the osbuild log of a running compose
+type composeLogEvent struct {
+	ID    uuid.UUID `json:"id"`
+	Lines []string  `json:"lines"`
+}
+
+// composeEventsHandler streams the events of the composes the user can
+// access as server-sent events. "status" events contain the compose's entry,
+// as in /compose/status. They're sent for all waiting and running composes
+// when the stream starts, and whenever the state of a compose changes. "log"
+// events contain the lines osbuild logged while building a compose. The
+// stream can be limited to a single compose with the `uuid` query parameter.
+func (api *API) composeEventsHandler(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	if !verifyRequestVersion(writer, params, 1) {
+		return
+	}
+
+	filter := uuid.Nil
+	if uuidString := request.URL.Query().Get("uuid"); uuidString != "" {
+		id, err := uuid.Parse(uuidString)
+		if err != nil {
+			errors := responseError{
+				ID:  "UnknownUUID",
+				Msg: fmt.Sprintf("%s is not a valid build uuid", uuidString),
+			}
+			statusResponseError(writer, http.StatusBadRequest, errors)
+			return
+		}
+		if _, exists := api.getCompose(request, id); !exists {
+			errors := responseError{
+				ID:  "UnknownUUID",
+				Msg: fmt.Sprintf("Compose %s doesn't exist", uuidString),
+			}
+			statusResponseError(writer, http.StatusBadRequest, errors)
+			return
+		}
+		filter = id
+	}
+
+	// subscribe before looking at the composes to not miss any changes
+	events, logs, unsubscribe := api.workers.SubscribeJobEvents()
+	defer unsubscribe()
+
+	stream, err := common.NewEventStream(writer)
+	if err != nil {
+		errors := responseError{
+			ID:  "InternalServerError",
+			Msg: err.Error(),
+		}
+		statusResponseError(writer, http.StatusInternalServerError, errors)
+		return
+	}
+
+	states := make(map[uuid.UUID]ComposeState)
+	for id, compose := range api.getAllComposes(request) {
+		if filter != uuid.Nil && id != filter {
+			continue
+		}
+
+		status := api.getComposeStatus(compose)
+		states[id] = status.State
+		if filter == uuid.Nil && status.State != ComposeWaiting && status.State != ComposeRunning {
+			continue
+		}
+
+		err = stream.Send("status", composeToComposeEntry(id, compose, status, true))
+		if err == nil && len(logs[compose.ImageBuild.JobID]) > 0 {
+			err = stream.Send("log", composeLogEvent{id, logs[compose.ImageBuild.JobID]})
+		}
+		if err != nil {
+			return
+		}
+	}
+
+	keepAlive := time.NewTicker(common.EventStreamKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+
+			id, compose, found := api.composeOfJob(request, event.JobID)
+			if !found || (filter != uuid.Nil && id != filter) {
+				continue
+			}
+
+			if len(event.Lines) > 0 {
+				err = stream.Send("log", composeLogEvent{id, event.Lines})
+				break
+			}
+
+			status := api.getComposeStatus(compose)
+			if state, known := states[id]; known && state == status.State {
+				continue
+			}
+			states[id] = status.State
+			err = stream.Send("status", composeToComposeEntry(id, compose, status, true))
+		case <-request.Context().Done():
+			return
+		case <-keepAlive.C:
+			err = stream.KeepAlive()
+		}
+		if err != nil {
+			logrus.Debugf("Stopped streaming compose events: %v", err)
+			return
+		}
+	}
+}
+
+// composeOfJob returns the compose whose image is built by the job with id
+// `jobID`, if the user of the request may access it.
+func (api *API) composeOfJob(request *http.Request, jobID uuid.UUID) (uuid.UUID, store.Compose, bool) {
+	for id, compose := range api.getAllComposes(request) {
+		if compose.ImageBuild.JobID == jobID {
+			return id, compose, true
+		}
+	}
+	return uuid.Nil, store.Compose{}, false
+}
@@ -0,0 +1,101 @@
+package weldr
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/osbuild-composer/internal/common"
+	"github.com/osbuild/osbuild-composer/internal/distro/test_distro"
+	rpmmd_mock "github.com/osbuild/osbuild-composer/internal/mocks/rpmmd"
+	"github.com/osbuild/osbuild-composer/internal/worker"
+)
+
+// nextComposeEvent reads the next server-sent event from `reader` and
+// decodes its data into `data`. It returns the event's type.
+func nextComposeEvent(t *testing.T, reader *bufio.Reader, data interface{}) string {
+	var event string
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimSuffix(line, "\n")
+
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), data))
+		case line == "" && event != "":
+			return event
+		}
+	}
+}
+
+func openComposeEvents(t *testing.T, server *httptest.Server, query string) (*http.Response, *bufio.Reader) {
+	response, err := http.Get(server.URL + "/api/v1/compose/events" + query)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	require.Equal(t, "text/event-stream", response.Header.Get("Content-Type"))
+	return response, bufio.NewReader(response.Body)
+}
+
+func TestComposeEvents(t *testing.T) {
+	api, _ := createWeldrAPI(t.TempDir(), rpmmd_mock.NoComposesFixture)
+	server := httptest.NewServer(api)
+	defer server.Close()
+
+	response, events := openComposeEvents(t, server, "")
+	defer response.Body.Close()
+
+	resp := sendAsUser(api, "", "POST", "/api/v1/compose", fmt.Sprintf(`{"blueprint_name": "test","compose_type":"%s","branch":"master"}`, test_distro.TestImageTypeName))
+	require.Equal(t, http.StatusOK, resp.Code)
+	var reply struct {
+		BuildID uuid.UUID `json:"build_id"`
+	}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &reply))
+
+	_, token, _, _, _, err := api.workers.RequestJob(context.Background(), test_distro.TestArchName, []string{"osbuild"}, []string{""})
+	require.NoError(t, err)
+
+	var entry ComposeEntry
+	require.Equal(t, "status", nextComposeEvent(t, events, &entry))
+	require.Equal(t, reply.BuildID, entry.ID)
+	require.Equal(t, common.IBRunning, entry.QueueStatus)
+
+	require.NoError(t, api.workers.AppendJobLog(token, []string{"building", "still building"}))
+	var log composeLogEvent
+	require.Equal(t, "log", nextComposeEvent(t, events, &log))
+	require.Equal(t, composeLogEvent{reply.BuildID, []string{"building", "still building"}}, log)
+
+	// streams of a single compose start with its status and log
+	filtered, filteredEvents := openComposeEvents(t, server, "?uuid="+reply.BuildID.String())
+	defer filtered.Body.Close()
+	require.Equal(t, "status", nextComposeEvent(t, filteredEvents, &entry))
+	require.Equal(t, common.IBRunning, entry.QueueStatus)
+	require.Equal(t, "log", nextComposeEvent(t, filteredEvents, &log))
+	require.Equal(t, []string{"building", "still building"}, log.Lines)
+
+	result, err := json.Marshal(&worker.OSBuildJobResult{Success: true})
+	require.NoError(t, err)
+	require.NoError(t, api.workers.FinishJob(token, result))
+	for _, reader := range []*bufio.Reader{events, filteredEvents} {
+		require.Equal(t, "status", nextComposeEvent(t, reader, &entry))
+		require.Equal(t, reply.BuildID, entry.ID)
+		require.Equal(t, common.IBFinished, entry.QueueStatus)
+	}
+
+	resp = sendAsUser(api, "", "GET", "/api/v1/compose/events?uuid=not-a-uuid", "")
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+	resp = sendAsUser(api, "", "GET", "/api/v1/compose/events?uuid="+uuid.New().String(), "")
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+	resp = sendAsUser(api, "", "GET", "/api/v0/compose/events", "")
+	require.Equal(t, http.StatusNotFound, resp.Code)
+}
@@ -0,0 +1,100 @@
+package weldr
+
+import (
+	"fmt"
+
+	"github.com/osbuild/osbuild-composer/internal/blueprint"
+	"github.com/osbuild/osbuild-composer/internal/rpmmd"
+)
+
+// Sources of the packages in a package set.
+const (
+	packageSourceBlueprint = "blueprint"
+	packageSourceImageType = "image_type"
+)
+
+// PackageExplanationV0 is why a package is part of one of the package sets of
+// an image type, as returned by /blueprints/depsolve?explain=1.
+type PackageExplanationV0 struct {
+	Name    string `json:"name"`
+	Epoch   uint   `json:"epoch"`
+	Version string `json:"version"`
+	Release string `json:"release"`
+	Arch    string `json:"arch"`
+	Reason  string `json:"reason,omitempty"`
+	// Chain is the shortest chain of requirements from an explicitly
+	// requested package to this one.
+	Chain       []string `json:"chain"`
+	RequestedBy []string `json:"requested_by"`
+	// Source is "blueprint" when the first package of the chain was
+	// requested by the blueprint, and "image_type" when it is one of the
+	// image type's default packages.
+	Source string `json:"source,omitempty"`
+}
+
+// explainPackageSets returns why each package of `packageSets`, which must
+// have been depsolved with explanations, is part of it.
+func explainPackageSets(bp blueprint.Blueprint, packageSets map[string][]rpmmd.PackageSpec) map[string][]PackageExplanationV0 {
+	bpPackages := make(map[string]bool)
+	for _, spec := range bp.GetPackages() {
+		bpPackages[spec] = true
+	}
+
+	explanations := make(map[string][]PackageExplanationV0, len(packageSets))
+	for name, specs := range packageSets {
+		explained := rpmmd.ExplainPackages(specs)
+		entries := make([]PackageExplanationV0, len(specs))
+		for i, spec := range specs {
+			entries[i] = PackageExplanationV0{
+				Name:        spec.Name,
+				Epoch:       spec.Epoch,
+				Version:     spec.Version,
+				Release:     spec.Release,
+				Arch:        spec.Arch,
+				Reason:      explained[i].Reason,
+				Chain:       explained[i].Chain,
+				RequestedBy: explained[i].RequestedBy,
+			}
+			if entries[i].Chain == nil {
+				entries[i].Chain = []string{}
+			}
+			if entries[i].RequestedBy == nil {
+				entries[i].RequestedBy = []string{}
+			}
+
+			for _, requested := range entries[i].RequestedBy {
+				if bpPackages[requested] {
+					entries[i].Source = packageSourceBlueprint
+					break
+				}
+			}
+			if entries[i].Source == "" && len(entries[i].Chain) > 0 {
+				entries[i].Source = packageSourceImageType
+			}
+		}
+		explanations[name] = entries
+	}
+
+	return explanations
+}
+
+// explainBlueprint depsolves the package sets of the compose type
+// `composeType` for `bp` with the sources `owner` can use and explains them.
+func (api *API) explainBlueprint(bp blueprint.Blueprint, composeType, owner string) (map[string][]PackageExplanationV0, error) {
+	distroName := bp.Distro
+	if distroName == "" {
+		distroName = api.hostDistroName
+	}
+
+	imageType, err := api.getImageType(distroName, composeType)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get compose type %q: %v", composeType, err)
+	}
+
+	packageSets, _, err := api.depsolveBlueprintForImageType(bp, imageType, true, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	return explainPackageSets(bp, packageSets), nil
+}
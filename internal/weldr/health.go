@@ -0,0 +1,93 @@
+package weldr
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+
+	"github.com/osbuild/osbuild-composer/internal/common"
+	"github.com/osbuild/osbuild-composer/internal/rpmmd"
+)
+
+// SourceHealthV1 is the result of checking a source in the
+// /projects/source/health response.
+type SourceHealthV1 struct {
+	Name              string              `json:"name"`
+	Healthy           bool                `json:"healthy"`
+	Checks            []SourceHealthCheck `json:"checks"`
+	MetadataTimestamp float64             `json:"metadata_timestamp,omitempty"`
+}
+
+type SourceHealthCheck struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+// SourceHealthResponseV1 is the response to /projects/source/health
+type SourceHealthResponseV1 struct {
+	Sources map[string]SourceHealthV1 `json:"sources"`
+	Errors  []responseError           `json:"errors"`
+}
+
+// sourceHealthHandler probes the sources given as a comma-separated list of
+// ids, or all of them for "*", and reports problems with each of them.
+func (api *API) sourceHealthHandler(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	if !verifyRequestVersion(writer, params, 1) {
+		return
+	}
+
+	sources, errors := api.getSourceConfigs(request, params)
+
+	// SourceConfig doesn't carry the GPG keys of system repositories, so
+	// use their original configuration.
+	systemRepos := make(map[string]rpmmd.RepoConfig)
+	repos, err := api.repoRegistry.ReposByArch(api.arch, false)
+	if err == nil {
+		for _, repo := range repos {
+			systemRepos[repo.Name] = repo
+		}
+	}
+
+	var ids []string
+	var configs []rpmmd.RepoConfig
+	for id, source := range sources {
+		repo, ok := systemRepos[id]
+		if !source.System || !ok {
+			repo = source.RepoConfig(id)
+		}
+		ids = append(ids, id)
+		configs = append(configs, repo)
+	}
+
+	reply := SourceHealthResponseV1{
+		Sources: make(map[string]SourceHealthV1, len(ids)),
+		Errors:  errors,
+	}
+
+	// sources are configured by the administrators of the host, so they
+	// may use local GPG keys
+	results := api.rpmmd.CheckRepositories(configs, api.arch.Name(), api.arch.Distro().Releasever(), true)
+	for i, health := range results {
+		entry := SourceHealthV1{
+			Name:    sources[ids[i]].Name,
+			Healthy: health.Healthy,
+			Checks:  make([]SourceHealthCheck, 0, len(health.Checks)),
+		}
+		for _, check := range health.Checks {
+			entry.Checks = append(entry.Checks, SourceHealthCheck{
+				Name:    check.Name,
+				Status:  string(check.Status),
+				Message: check.Message,
+			})
+		}
+		if !health.MetadataTimestamp.IsZero() {
+			entry.MetadataTimestamp = float64(health.MetadataTimestamp.UnixNano()) / 1000000000
+		}
+		reply.Sources[ids[i]] = entry
+	}
+
+	err = json.NewEncoder(writer).Encode(reply)
+	common.PanicOnError(err)
+}
@@ -3,6 +3,8 @@
 package weldr
 
 import (
+	"net/url"
+
 	"github.com/google/uuid"
 
 	"github.com/osbuild/osbuild-composer/internal/blueprint"
@@ -120,7 +122,7 @@ type SourceConfig interface {
 }
 
 // NewSourceConfigV0 converts a store.SourceConfig to a SourceConfigV0
-// The store does not support proxy and gpgkey_urls
+// The store does not support gpgkey_urls
 func NewSourceConfigV0(s store.SourceConfig) SourceConfigV0 {
 	var sc SourceConfigV0
 
@@ -130,6 +132,7 @@ func NewSourceConfigV0(s store.SourceConfig) SourceConfigV0 {
 	sc.CheckGPG = s.CheckGPG
 	sc.CheckSSL = s.CheckSSL
 	sc.System = s.System
+	sc.Proxy = redactProxy(s.Proxy)
 
 	return sc
 }
@@ -162,13 +165,14 @@ func (s SourceConfigV0) GetType() string {
 }
 
 // SourceConfig returns a SourceConfig struct populated with the supported variables
-// The store does not support proxy and gpgkey_urls
+// The store does not support gpgkey_urls
 func (s SourceConfigV0) SourceConfig() (ssc store.SourceConfig) {
 	ssc.Name = s.Name
 	ssc.Type = s.Type
 	ssc.URL = s.URL
 	ssc.CheckGPG = s.CheckGPG
 	ssc.CheckSSL = s.CheckSSL
+	ssc.Proxy = s.Proxy
 
 	return ssc
 }
@@ -180,7 +184,8 @@ type SourceInfoResponseV0 struct {
 }
 
 // NewSourceConfigV1 converts a store.SourceConfig to a SourceConfigV1
-// The store does not support proxy and gpgkey_urls
+// The store does not support gpgkey_urls
+// The password of the proxy is never returned
 func NewSourceConfigV1(id string, s store.SourceConfig) SourceConfigV1 {
 	var sc SourceConfigV1
 
@@ -193,6 +198,7 @@ func NewSourceConfigV1(id string, s store.SourceConfig) SourceConfigV1 {
 	sc.System = s.System
 	sc.Distros = s.Distros
 	sc.RHSM = s.RHSM
+	sc.Proxy = redactProxy(s.Proxy)
 
 	return sc
 }
@@ -228,7 +234,7 @@ func (s SourceConfigV1) GetType() string {
 }
 
 // SourceConfig returns a SourceConfig struct populated with the supported variables
-// The store does not support proxy and gpgkey_urls
+// The store does not support gpgkey_urls
 func (s SourceConfigV1) SourceConfig() (ssc store.SourceConfig) {
 	ssc.Name = s.Name
 	ssc.Type = s.Type
@@ -237,10 +243,20 @@ func (s SourceConfigV1) SourceConfig() (ssc store.SourceConfig) {
 	ssc.CheckSSL = s.CheckSSL
 	ssc.Distros = s.Distros
 	ssc.RHSM = s.RHSM
+	ssc.Proxy = s.Proxy
 
 	return ssc
 }
 
+// redactProxy hides the password in a proxy url
+func redactProxy(proxy string) string {
+	u, err := url.Parse(proxy)
+	if err != nil || u.User == nil {
+		return proxy
+	}
+	return u.Redacted()
+}
+
 // SourceInfoResponseV1
 type SourceInfoResponseV1 struct {
 	Sources map[string]SourceConfigV1 `json:"sources"`
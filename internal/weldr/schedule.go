@@ -0,0 +1,390 @@
+package weldr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/julienschmidt/httprouter"
+
+	"github.com/osbuild/osbuild-composer/internal/common"
+	"github.com/osbuild/osbuild-composer/internal/cron"
+	"github.com/osbuild/osbuild-composer/internal/ostree"
+	"github.com/osbuild/osbuild-composer/internal/store"
+	"github.com/osbuild/osbuild-composer/internal/target"
+)
+
+// ScheduleEntry is the representation of a schedule in the
+// /compose/schedules/* responses.
+type ScheduleEntry struct {
+	ID          uuid.UUID              `json:"id"`
+	Name        string                 `json:"name"`
+	Cron        string                 `json:"cron"`
+	Blueprint   string                 `json:"blueprint"`
+	ComposeType string                 `json:"compose_type"`
+	Size        uint64                 `json:"size"`
+	Upload      *uploadResponse        `json:"upload,omitempty"`
+	Created     float64                `json:"created"`
+	LastRun     float64                `json:"last_run,omitempty"`
+	NextRun     float64                `json:"next_run,omitempty"`
+	History     []ScheduleHistoryEntry `json:"history,omitempty"`
+}
+
+// ScheduleHistoryEntry is a compose which was triggered by a schedule
+type ScheduleHistoryEntry struct {
+	Triggered float64   `json:"triggered"`
+	ComposeID uuid.UUID `json:"compose_id,omitempty"`
+	Error     string    `json:"error,omitempty"`
+}
+
+func scheduleToScheduleEntry(schedule store.Schedule, includeHistory bool) ScheduleEntry {
+	entry := ScheduleEntry{
+		ID:          schedule.ID,
+		Name:        schedule.Name,
+		Cron:        schedule.Cron,
+		Blueprint:   schedule.Blueprint,
+		ComposeType: schedule.ImageType,
+		Size:        schedule.Size,
+		Created:     float64(schedule.Created.UnixNano()) / 1000000000,
+	}
+
+	if schedule.Upload != nil {
+		uploads := targetsToUploadResponses([]*target.Target{schedule.Upload}, ComposeWaiting)
+		if len(uploads) == 1 {
+			entry.Upload = &uploads[0]
+		}
+	}
+
+	if !schedule.LastRun.IsZero() {
+		entry.LastRun = float64(schedule.LastRun.UnixNano()) / 1000000000
+	}
+
+	if next := nextScheduledRun(schedule); !next.IsZero() {
+		entry.NextRun = float64(next.UnixNano()) / 1000000000
+	}
+
+	if includeHistory {
+		for _, h := range schedule.History {
+			entry.History = append(entry.History, ScheduleHistoryEntry{
+				Triggered: float64(h.Triggered.UnixNano()) / 1000000000,
+				ComposeID: h.ComposeID,
+				Error:     h.Error,
+			})
+		}
+	}
+
+	return entry
+}
+
+// nextScheduledRun returns the time at which the schedule should trigger the
+// next compose, or the zero time if it never will.
+func nextScheduledRun(schedule store.Schedule) time.Time {
+	expr, err := cron.Parse(schedule.Cron)
+	if err != nil {
+		return time.Time{}
+	}
+
+	last := schedule.LastRun
+	if last.IsZero() {
+		last = schedule.Created
+	}
+
+	return expr.Next(last)
+}
+
+// WatchSchedules starts the composes of all schedules that are due. It
+// checks once per minute until ctx is canceled, so it should be started as a
+// goroutine.
+func (api *API) WatchSchedules(ctx context.Context) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			api.runSchedules(now)
+		}
+	}
+}
+
+// runSchedules triggers a compose for each schedule which has a run due at
+// `now`. Runs which were missed (e.g., because composer was not running) are
+// coalesced into a single compose.
+func (api *API) runSchedules(now time.Time) {
+	for id, schedule := range api.store.GetAllSchedules() {
+		next := nextScheduledRun(schedule)
+		if next.IsZero() || next.After(now) {
+			continue
+		}
+
+		entry := store.ScheduledCompose{
+			Triggered: now,
+		}
+
+		composeID, err := api.startScheduledCompose(schedule)
+		if err != nil {
+			log.Printf("Error starting compose for schedule %s: %v", id, err)
+			entry.Error = err.Error()
+		} else {
+			entry.ComposeID = composeID
+		}
+
+		err = api.store.PushScheduledCompose(id, entry)
+		if err != nil {
+			// the schedule was deleted in the meantime
+			log.Printf("Error recording compose for schedule %s: %v", id, err)
+		}
+	}
+}
+
+// startScheduledCompose starts a compose of the latest committed version of
+// the schedule's blueprint, in the same way a /compose request does.
+func (api *API) startScheduledCompose(schedule store.Schedule) (uuid.UUID, error) {
+	bp := api.store.GetBlueprintCommitted(schedule.Blueprint)
+	if bp == nil || !api.canAccess(schedule.Owner, api.store.GetBlueprintOwner(schedule.Blueprint)) {
+		return uuid.Nil, fmt.Errorf("Unknown blueprint name: %s", schedule.Blueprint)
+	}
+
+	distroName := bp.Distro
+	if distroName == "" {
+		distroName = api.hostDistroName
+	}
+	if api.getDistro(distroName) == nil {
+		return uuid.Nil, fmt.Errorf("Unknown distribution: %s", distroName)
+	}
+
+	imageType, err := api.getImageType(distroName, schedule.ImageType)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("Failed to get compose type %q: %v", schedule.ImageType, err)
+	}
+
+	ostreeParams, err := ostree.ResolveParams(ostree.RequestParams{}, imageType.OSTreeRef())
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	var targets []*target.Target
+	if schedule.Upload != nil {
+		t := *schedule.Upload
+		t.Uuid = uuid.New()
+		t.Created = time.Now()
+		t.Status = common.IBWaiting
+		targets = append(targets, &t)
+	}
+
+	composeID := uuid.New()
+	if _, cerr := api.startCompose(composeID, bp, imageType, schedule.Size, ostreeParams, targets, nil, "", schedule.Owner, nil); cerr != nil {
+		return uuid.Nil, fmt.Errorf("%s: %s", cerr.ID, cerr.Msg)
+	}
+
+	return composeID, nil
+}
+
+func (api *API) schedulesListHandler(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	if !verifyRequestVersion(writer, params, 1) {
+		return
+	}
+
+	schedules := []ScheduleEntry{}
+	for _, schedule := range api.getAllSchedules(request) {
+		schedules = append(schedules, scheduleToScheduleEntry(schedule, false))
+	}
+	sort.Slice(schedules, func(i, j int) bool {
+		return schedules[i].ID.String() < schedules[j].ID.String()
+	})
+
+	reply := struct {
+		Schedules []ScheduleEntry `json:"schedules"`
+	}{schedules}
+
+	err := json.NewEncoder(writer).Encode(reply)
+	common.PanicOnError(err)
+}
+
+func (api *API) schedulesInfoHandler(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	if !verifyRequestVersion(writer, params, 1) {
+		return
+	}
+
+	uuidString := params.ByName("uuid")
+	id, err := uuid.Parse(uuidString)
+	if err != nil {
+		errors := responseError{
+			ID:  "UnknownUUID",
+			Msg: fmt.Sprintf("%s is not a valid schedule uuid", uuidString),
+		}
+		statusResponseError(writer, http.StatusBadRequest, errors)
+		return
+	}
+
+	schedule, exists := api.getSchedule(request, id)
+	if !exists {
+		errors := responseError{
+			ID:  "UnknownUUID",
+			Msg: fmt.Sprintf("Schedule %s doesn't exist", uuidString),
+		}
+		statusResponseError(writer, http.StatusBadRequest, errors)
+		return
+	}
+
+	err = json.NewEncoder(writer).Encode(scheduleToScheduleEntry(schedule, true))
+	common.PanicOnError(err)
+}
+
+func (api *API) schedulesNewHandler(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	if !verifyRequestVersion(writer, params, 1) {
+		return
+	}
+
+	type ScheduleRequest struct {
+		Name          string         `json:"name"`
+		Cron          string         `json:"cron"`
+		BlueprintName string         `json:"blueprint_name"`
+		ComposeType   string         `json:"compose_type"`
+		Size          uint64         `json:"size"`
+		Upload        *uploadRequest `json:"upload"`
+	}
+	type ScheduleReply struct {
+		ID     uuid.UUID `json:"id"`
+		Status bool      `json:"status"`
+	}
+
+	contentType := request.Header["Content-Type"]
+	if len(contentType) != 1 || contentType[0] != "application/json" {
+		errors := responseError{
+			ID:  "MissingPost",
+			Msg: "schedule must be json",
+		}
+		statusResponseError(writer, http.StatusBadRequest, errors)
+		return
+	}
+
+	var sr ScheduleRequest
+	err := json.NewDecoder(request.Body).Decode(&sr)
+	if err != nil {
+		errors := responseError{
+			ID:  "ScheduleError",
+			Msg: fmt.Sprintf("invalid schedule: %v", err),
+		}
+		statusResponseError(writer, http.StatusBadRequest, errors)
+		return
+	}
+
+	if _, err := cron.Parse(sr.Cron); err != nil {
+		errors := responseError{
+			ID:  "ScheduleError",
+			Msg: err.Error(),
+		}
+		statusResponseError(writer, http.StatusBadRequest, errors)
+		return
+	}
+
+	if !verifyStringsWithRegex(writer, []string{sr.BlueprintName}, ValidBlueprintName) {
+		return
+	}
+
+	bp := api.getBlueprintCommitted(request, sr.BlueprintName)
+	if bp == nil {
+		errors := responseError{
+			ID:  "UnknownBlueprint",
+			Msg: fmt.Sprintf("Unknown blueprint name: %s", sr.BlueprintName),
+		}
+		statusResponseError(writer, http.StatusBadRequest, errors)
+		return
+	}
+
+	// The blueprint may change before the schedule triggers, but validate
+	// the compose type against its current distribution anyway, to catch
+	// typos early.
+	distroName := bp.Distro
+	if distroName == "" {
+		distroName = api.hostDistroName
+	}
+	imageType, err := api.getImageType(distroName, sr.ComposeType)
+	if err != nil {
+		errors := responseError{
+			ID:  "ComposeError",
+			Msg: fmt.Sprintf("Failed to get compose type %q: %v", sr.ComposeType, err),
+		}
+		statusResponseError(writer, http.StatusBadRequest, errors)
+		return
+	}
+
+	schedule := store.Schedule{
+		ID:        uuid.New(),
+		Name:      sr.Name,
+		Cron:      sr.Cron,
+		Blueprint: sr.BlueprintName,
+		ImageType: sr.ComposeType,
+		Size:      sr.Size,
+		Created:   time.Now(),
+		Owner:     api.owner(request),
+	}
+	if sr.Upload != nil {
+		err = checkUploadRequest(*sr.Upload, imageType)
+		if err != nil {
+			errors := responseError{
+				ID:  "UploadError",
+				Msg: err.Error(),
+			}
+			statusResponseError(writer, http.StatusBadRequest, errors)
+			return
+		}
+		schedule.Upload = uploadRequestToTarget(*sr.Upload, imageType)
+	}
+
+	err = api.store.PushSchedule(schedule)
+	if err != nil {
+		errors := responseError{
+			ID:  "ScheduleError",
+			Msg: err.Error(),
+		}
+		statusResponseError(writer, http.StatusInternalServerError, errors)
+		return
+	}
+
+	err = json.NewEncoder(writer).Encode(ScheduleReply{
+		ID:     schedule.ID,
+		Status: true,
+	})
+	common.PanicOnError(err)
+}
+
+func (api *API) schedulesDeleteHandler(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	if !verifyRequestVersion(writer, params, 1) {
+		return
+	}
+
+	uuidString := params.ByName("uuid")
+	id, err := uuid.Parse(uuidString)
+	if err != nil {
+		errors := responseError{
+			ID:  "UnknownUUID",
+			Msg: fmt.Sprintf("%s is not a valid schedule uuid", uuidString),
+		}
+		statusResponseError(writer, http.StatusBadRequest, errors)
+		return
+	}
+
+	_, exists := api.getSchedule(request, id)
+	if exists {
+		err = api.store.DeleteSchedule(id)
+	}
+	if !exists || err != nil {
+		errors := responseError{
+			ID:  "UnknownUUID",
+			Msg: fmt.Sprintf("Schedule %s doesn't exist", uuidString),
+		}
+		statusResponseError(writer, http.StatusBadRequest, errors)
+		return
+	}
+
+	statusResponseOK(writer)
+}
@@ -0,0 +1,247 @@
+package weldr
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/julienschmidt/httprouter"
+
+	"github.com/osbuild/osbuild-composer/internal/common"
+	"github.com/osbuild/osbuild-composer/internal/store"
+)
+
+// SnapshotEntry is the representation of a repository snapshot in the
+// /projects/snapshots/* responses.
+type SnapshotEntry struct {
+	Name      string            `json:"name"`
+	Distro    string            `json:"distro"`
+	Checksums map[string]string `json:"checksums"`
+	Created   float64           `json:"created"`
+}
+
+func snapshotToSnapshotEntry(snapshot store.RepoSnapshot) SnapshotEntry {
+	return SnapshotEntry{
+		Name:      snapshot.Name,
+		Distro:    snapshot.Distro,
+		Checksums: snapshot.Checksums,
+		Created:   float64(snapshot.Created.UnixNano()) / 1000000000,
+	}
+}
+
+// snapshotPin is a repository snapshot a compose is pinned to.
+type snapshotPin struct {
+	snapshot store.RepoSnapshot
+	// warnOnly lets the compose proceed with a warning when the
+	// repositories changed since the snapshot was taken, instead of
+	// failing it.
+	warnOnly bool
+}
+
+// newSnapshotPin looks up the snapshot `name` for a compose of `distroName`.
+// `policy` is the snapshot_policy of the compose request.
+func (api *API) newSnapshotPin(name, policy, distroName string) (*snapshotPin, error) {
+	var warnOnly bool
+	switch policy {
+	case "", "fail":
+	case "warn":
+		warnOnly = true
+	default:
+		return nil, fmt.Errorf("invalid snapshot policy %q, must be \"fail\" or \"warn\"", policy)
+	}
+
+	snapshot, exists := api.store.GetSnapshot(name)
+	if !exists {
+		return nil, fmt.Errorf("unknown snapshot: %s", name)
+	}
+	if snapshot.Distro != distroName {
+		return nil, fmt.Errorf("snapshot %s was taken for %s, not %s", name, snapshot.Distro, distroName)
+	}
+
+	return &snapshotPin{snapshot, warnOnly}, nil
+}
+
+// snapshotDistroChecksums returns the current metadata checksums of all
+// repositories of a distribution, including the ones which are only used by
+// some image types, and the sources which can be used by `owner`.
+func (api *API) snapshotDistroChecksums(distroName, owner string) (map[string]string, error) {
+	d := api.getDistro(distroName)
+	if d == nil {
+		return nil, fmt.Errorf("Unknown distribution: %s", distroName)
+	}
+
+	repos, err := api.repoRegistry.ReposByArchName(distroName, api.arch.Name(), true)
+	if err != nil {
+		return nil, err
+	}
+	for id, source := range api.getDistroSources(distroName, owner) {
+		repos = append(repos, source.RepoConfig(id))
+	}
+
+	_, checksums, err := api.rpmmd.FetchMetadata(repos, d.ModulePlatformID(), api.arch.Name(), d.Releasever())
+	if err != nil {
+		return nil, err
+	}
+	return checksums, nil
+}
+
+func (api *API) snapshotsListHandler(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	if !verifyRequestVersion(writer, params, 1) {
+		return
+	}
+
+	snapshots := []SnapshotEntry{}
+	for _, snapshot := range api.store.GetAllSnapshots() {
+		snapshots = append(snapshots, snapshotToSnapshotEntry(snapshot))
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Name < snapshots[j].Name
+	})
+
+	reply := struct {
+		Snapshots []SnapshotEntry `json:"snapshots"`
+	}{snapshots}
+
+	err := json.NewEncoder(writer).Encode(reply)
+	common.PanicOnError(err)
+}
+
+func (api *API) snapshotsInfoHandler(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	if !verifyRequestVersion(writer, params, 1) {
+		return
+	}
+
+	name := params.ByName("name")
+	snapshot, exists := api.store.GetSnapshot(name)
+	if !exists {
+		errors := responseError{
+			ID:  "UnknownSnapshot",
+			Msg: fmt.Sprintf("%s is not a valid snapshot", name),
+		}
+		statusResponseError(writer, http.StatusBadRequest, errors)
+		return
+	}
+
+	err := json.NewEncoder(writer).Encode(snapshotToSnapshotEntry(snapshot))
+	common.PanicOnError(err)
+}
+
+// snapshotsNewHandler records a new snapshot, either of the current state of
+// the repositories of a distribution, or of the repositories a compose was
+// depsolved against.
+func (api *API) snapshotsNewHandler(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	if !verifyRequestVersion(writer, params, 1) {
+		return
+	}
+
+	type SnapshotRequest struct {
+		Name      string     `json:"name"`
+		Distro    string     `json:"distro"`
+		ComposeID *uuid.UUID `json:"compose_id"`
+	}
+
+	contentType := request.Header["Content-Type"]
+	if len(contentType) != 1 || contentType[0] != "application/json" {
+		errors := responseError{
+			ID:  "MissingPost",
+			Msg: "snapshot must be json",
+		}
+		statusResponseError(writer, http.StatusBadRequest, errors)
+		return
+	}
+
+	var sr SnapshotRequest
+	err := json.NewDecoder(request.Body).Decode(&sr)
+	if err != nil {
+		errors := responseError{
+			ID:  "SnapshotError",
+			Msg: fmt.Sprintf("invalid snapshot: %v", err),
+		}
+		statusResponseError(writer, http.StatusBadRequest, errors)
+		return
+	}
+
+	if !verifyStringsWithRegex(writer, []string{sr.Name}, ValidBlueprintName) {
+		return
+	}
+
+	snapshot := store.RepoSnapshot{
+		Name:    sr.Name,
+		Created: time.Now(),
+	}
+
+	if sr.ComposeID != nil {
+		compose, exists := api.getCompose(request, *sr.ComposeID)
+		if !exists {
+			errors := responseError{
+				ID:  "UnknownUUID",
+				Msg: fmt.Sprintf("%s is not a valid build uuid", sr.ComposeID),
+			}
+			statusResponseError(writer, http.StatusBadRequest, errors)
+			return
+		}
+		if len(compose.RepoChecksums) == 0 {
+			errors := responseError{
+				ID:  "SnapshotError",
+				Msg: fmt.Sprintf("compose %s has no recorded repository checksums", sr.ComposeID),
+			}
+			statusResponseError(writer, http.StatusBadRequest, errors)
+			return
+		}
+		snapshot.Distro = compose.ImageBuild.ImageType.Arch().Distro().Name()
+		snapshot.Checksums = compose.RepoChecksums
+	} else {
+		snapshot.Distro = sr.Distro
+		if snapshot.Distro == "" {
+			snapshot.Distro = api.hostDistroName
+		}
+		snapshot.Checksums, err = api.snapshotDistroChecksums(snapshot.Distro, api.owner(request))
+		if err != nil {
+			errors := responseError{
+				ID:  "SnapshotError",
+				Msg: err.Error(),
+			}
+			statusResponseError(writer, http.StatusBadRequest, errors)
+			return
+		}
+	}
+
+	err = api.store.PushSnapshot(snapshot)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if _, ok := err.(*store.SnapshotExistsError); ok {
+			status = http.StatusBadRequest
+		}
+		errors := responseError{
+			ID:  "SnapshotError",
+			Msg: err.Error(),
+		}
+		statusResponseError(writer, status, errors)
+		return
+	}
+
+	err = json.NewEncoder(writer).Encode(snapshotToSnapshotEntry(snapshot))
+	common.PanicOnError(err)
+}
+
+func (api *API) snapshotsDeleteHandler(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	if !verifyRequestVersion(writer, params, 1) {
+		return
+	}
+
+	name := params.ByName("name")
+	err := api.store.DeleteSnapshot(name)
+	if err != nil {
+		errors := responseError{
+			ID:  "UnknownSnapshot",
+			Msg: fmt.Sprintf("%s is not a valid snapshot", name),
+		}
+		statusResponseError(writer, http.StatusBadRequest, errors)
+		return
+	}
+
+	statusResponseOK(writer)
+}
@@ -3,6 +3,7 @@ package weldr
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/osbuild/osbuild-composer/internal/common"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/osbuild/osbuild-composer/internal/target"
+	"github.com/osbuild/osbuild-composer/internal/worker"
 )
 
 type uploadResponse struct {
@@ -68,6 +70,14 @@ type ociUploadSettings struct {
 
 func (ociUploadSettings) isUploadSettings() {}
 
+type ostreeUploadSettings struct {
+	// Repository is the name of an OSTree repository configured on the
+	// worker
+	Repository string `json:"repository"`
+}
+
+func (ostreeUploadSettings) isUploadSettings() {}
+
 type uploadRequest struct {
 	Provider  string         `json:"provider"`
 	ImageName string         `json:"image_name"`
@@ -97,6 +107,8 @@ func (u *uploadRequest) UnmarshalJSON(data []byte) error {
 		settings = new(vmwareUploadSettings)
 	case "oci":
 		settings = new(ociUploadSettings)
+	case "ostree":
+		settings = new(ostreeUploadSettings)
 	default:
 		return errors.New("unexpected provider name")
 	}
@@ -167,12 +179,27 @@ func targetsToUploadResponses(targets []*target.Target, state ComposeState) []up
 				// Username and Password are intentionally not included.
 			}
 			uploads = append(uploads, upload)
+		case *target.OSTreeTargetOptions:
+			upload.ProviderName = "ostree"
+			upload.Settings = &ostreeUploadSettings{
+				Repository: options.Repository,
+			}
+			uploads = append(uploads, upload)
 		}
 	}
 
 	return uploads
 }
 
+// checkUploadRequest returns an error if images of `imageType` can't be
+// uploaded as requested by `u`.
+func checkUploadRequest(u uploadRequest, imageType distro.ImageType) error {
+	if _, ok := u.Settings.(*ostreeUploadSettings); ok && !worker.ProducesOSTreeCommit(imageType) {
+		return fmt.Errorf("image type %s does not produce an ostree commit that can be published to a repository", imageType.Name())
+	}
+	return nil
+}
+
 func uploadRequestToTarget(u uploadRequest, imageType distro.ImageType) *target.Target {
 	var t target.Target
 
@@ -225,6 +252,12 @@ func uploadRequestToTarget(u uploadRequest, imageType distro.ImageType) *target.
 			Namespace:   options.Namespace,
 			Compartment: options.Compartment,
 		}
+	case *ostreeUploadSettings:
+		t.Name = "org.osbuild.ostree"
+		t.Options = &target.OSTreeTargetOptions{
+			Filename:   imageType.Filename(),
+			Repository: options.Repository,
+		}
 	}
 
 	return &t
@@ -0,0 +1,328 @@
+package weldr
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net"
+	"net/http"
+	"os/user"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"golang.org/x/sys/unix"
+
+	"github.com/osbuild/osbuild-composer/internal/blueprint"
+	"github.com/osbuild/osbuild-composer/internal/store"
+)
+
+// UsersConfig configures how clients of the API are identified and whether
+// they are restricted to the objects they own.
+type UsersConfig struct {
+	// MultiUser restricts users to the blueprints, composes, sources and
+	// schedules they own, and those which don't have an owner. Clients on
+	// the unix socket are identified by the peer credentials of their
+	// connection.
+	MultiUser bool
+
+	// Admins are the names of the users who can access all objects. root
+	// is always an admin.
+	Admins []string
+
+	// Tokens maps bearer tokens to the names of the users they identify.
+	// Requests received on connections which are not unix sockets must
+	// carry one of these tokens.
+	Tokens map[string]string
+}
+
+type contextKey int
+
+const (
+	// userContextKey is the key of the name of the user who sent a request
+	userContextKey contextKey = iota
+	// tokenContextKey marks requests which need to be authenticated by a token
+	tokenContextKey
+)
+
+// SetUsersConfig changes how clients of the API are identified. It must be
+// called before the API serves any requests.
+func (api *API) SetUsersConfig(config UsersConfig) {
+	api.multiUser = config.MultiUser
+
+	api.admins = map[string]bool{"root": true}
+	for _, name := range config.Admins {
+		api.admins[name] = true
+	}
+
+	api.tokens = make(map[string]string, len(config.Tokens))
+	for token, name := range config.Tokens {
+		api.tokens[token] = name
+	}
+}
+
+// connContext is used as the ConnContext of the http.Server. It attaches the
+// user of unix socket connections to their context, and marks all other
+// connections as needing authentication by a token.
+func (api *API) connContext(ctx context.Context, conn net.Conn) context.Context {
+	unixConn, ok := conn.(*net.UnixConn)
+	if !ok {
+		return context.WithValue(ctx, tokenContextKey, true)
+	}
+
+	name, err := peerUserName(unixConn)
+	if err != nil {
+		api.logger.Printf("cannot read peer credentials: %v", err)
+		return ctx
+	}
+	return context.WithValue(ctx, userContextKey, name)
+}
+
+// peerUserName returns the name of the user on the other end of a unix socket
+// connection, or their uid if they don't have a name.
+func peerUserName(conn *net.UnixConn) (string, error) {
+	raw, err := conn.SyscallConn()
+	if err != nil {
+		return "", err
+	}
+
+	var cred *unix.Ucred
+	var credErr error
+	err = raw.Control(func(fd uintptr) {
+		cred, credErr = unix.GetsockoptUcred(int(fd), unix.SOL_SOCKET, unix.SO_PEERCRED)
+	})
+	if err != nil {
+		return "", err
+	}
+	if credErr != nil {
+		return "", credErr
+	}
+
+	uid := strconv.FormatUint(uint64(cred.Uid), 10)
+	u, err := user.LookupId(uid)
+	if err != nil {
+		return uid, nil
+	}
+	return u.Username, nil
+}
+
+// authenticate identifies the user of a request which was received on a
+// connection that needs a token. It writes an error and returns false if the
+// request may not continue.
+func (api *API) authenticate(writer http.ResponseWriter, request *http.Request) (*http.Request, bool) {
+	if needsToken, _ := request.Context().Value(tokenContextKey).(bool); needsToken {
+		name, ok := api.tokenUserName(request.Header.Get("Authorization"))
+		if !ok {
+			writer.Header().Set("WWW-Authenticate", "Bearer")
+			errors := responseError{
+				ID:  "Unauthorized",
+				Msg: "A valid token is required",
+			}
+			statusResponseError(writer, http.StatusUnauthorized, errors)
+			return nil, false
+		}
+		request = request.WithContext(context.WithValue(request.Context(), userContextKey, name))
+	}
+
+	if api.multiUser && requestUserName(request) == "" {
+		errors := responseError{
+			ID:  "Unauthorized",
+			Msg: "Cannot determine the user of the request",
+		}
+		statusResponseError(writer, http.StatusUnauthorized, errors)
+		return nil, false
+	}
+
+	return request, true
+}
+
+// tokenUserName returns the user identified by the bearer token in the value
+// of an Authorization header.
+func (api *API) tokenUserName(header string) (string, bool) {
+	token := strings.TrimPrefix(header, "Bearer ")
+	if token == header || token == "" {
+		return "", false
+	}
+
+	// compare all tokens in constant time, so that they cannot be guessed
+	var name string
+	for t, n := range api.tokens {
+		if subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1 {
+			name = n
+		}
+	}
+	return name, name != ""
+}
+
+// requestUserName returns the name of the user who sent the request, or an
+// empty string if it is unknown.
+func requestUserName(request *http.Request) string {
+	name, _ := request.Context().Value(userContextKey).(string)
+	return name
+}
+
+// owner returns the name of the user who owns the objects created by the
+// request. It is empty unless the API runs in multi-user mode.
+func (api *API) owner(request *http.Request) string {
+	if !api.multiUser {
+		return ""
+	}
+	return requestUserName(request)
+}
+
+// canAccess returns whether the user with the given name may see and change
+// objects owned by owner. Objects without an owner are shared by all users.
+func (api *API) canAccess(name, owner string) bool {
+	return !api.multiUser || owner == "" || owner == name || api.admins[name]
+}
+
+// canUse returns whether objects owned by owner may be used for the builds of
+// the user with the given name. Unlike canAccess(), this doesn't make an
+// exception for admins, so that their composes don't pick up the sources of
+// all other users.
+func (api *API) canUse(name, owner string) bool {
+	return !api.multiUser || owner == "" || owner == name
+}
+
+// getCompose returns the compose with the given id, if the user of the request
+// may access it.
+func (api *API) getCompose(request *http.Request, id uuid.UUID) (store.Compose, bool) {
+	compose, exists := api.store.GetCompose(id)
+	if !exists || !api.canAccess(requestUserName(request), compose.Owner) {
+		return store.Compose{}, false
+	}
+	return compose, true
+}
+
+// getAllComposes returns all composes the user of the request may access.
+func (api *API) getAllComposes(request *http.Request) map[uuid.UUID]store.Compose {
+	composes := api.store.GetAllComposes()
+	name := requestUserName(request)
+	for id, compose := range composes {
+		if !api.canAccess(name, compose.Owner) {
+			delete(composes, id)
+		}
+	}
+	return composes
+}
+
+// canAccessBlueprint returns whether the user of the request may see and
+// change the blueprint with the given name.
+func (api *API) canAccessBlueprint(request *http.Request, name string) bool {
+	return api.canAccess(requestUserName(request), api.store.GetBlueprintOwner(name))
+}
+
+// getBlueprint returns the blueprint with the given name, if the user of the
+// request may access it. See store.GetBlueprint().
+func (api *API) getBlueprint(request *http.Request, name string) (*blueprint.Blueprint, bool) {
+	if !api.canAccessBlueprint(request, name) {
+		return nil, false
+	}
+	return api.store.GetBlueprint(name)
+}
+
+// getBlueprintCommitted returns the committed version of the blueprint with
+// the given name, if the user of the request may access it.
+func (api *API) getBlueprintCommitted(request *http.Request, name string) *blueprint.Blueprint {
+	if !api.canAccessBlueprint(request, name) {
+		return nil
+	}
+	return api.store.GetBlueprintCommitted(name)
+}
+
+// listBlueprints returns the names of all blueprints the user of the request
+// may access.
+func (api *API) listBlueprints(request *http.Request) []string {
+	names := []string{}
+	for _, name := range api.store.ListBlueprints() {
+		if api.canAccessBlueprint(request, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// claimBlueprint makes the user of the request the owner of the blueprint
+// with the given name, if it is new. It writes an error and returns false if
+// the blueprint belongs to another user. Admins may change all blueprints
+// without taking them over.
+func (api *API) claimBlueprint(writer http.ResponseWriter, request *http.Request, name string) bool {
+	owner := api.owner(request)
+	err := api.store.ClaimBlueprint(name, owner)
+	if err != nil {
+		if _, isOwnerError := err.(*store.OwnerError); isOwnerError && api.admins[owner] {
+			return true
+		}
+		ownerError(writer, "blueprint", name)
+		return false
+	}
+	return true
+}
+
+// getSource returns the source with the given id, if the user of the request
+// may access it.
+func (api *API) getSource(request *http.Request, id string) *store.SourceConfig {
+	source := api.store.GetSource(id)
+	if source == nil || !api.canAccess(requestUserName(request), source.Owner) {
+		return nil
+	}
+	return source
+}
+
+// getAllSourcesByID returns all sources the user of the request may access,
+// using the repo id as the key.
+func (api *API) getAllSourcesByID(request *http.Request) map[string]store.SourceConfig {
+	sources := api.store.GetAllSourcesByID()
+	name := requestUserName(request)
+	for id, source := range sources {
+		if !api.canAccess(name, source.Owner) {
+			delete(sources, id)
+		}
+	}
+	return sources
+}
+
+// getDistroSources returns the sources which can be used for the builds of
+// the user with the given name, using the repo id as the key. See
+// store.GetAllDistroSources().
+func (api *API) getDistroSources(distroName, userName string) map[string]store.SourceConfig {
+	sources := api.store.GetAllDistroSources(distroName)
+	for id, source := range sources {
+		if !api.canUse(userName, source.Owner) {
+			delete(sources, id)
+		}
+	}
+	return sources
+}
+
+// getSchedule returns the schedule with the given id, if the user of the
+// request may access it.
+func (api *API) getSchedule(request *http.Request, id uuid.UUID) (store.Schedule, bool) {
+	schedule, exists := api.store.GetSchedule(id)
+	if !exists || !api.canAccess(requestUserName(request), schedule.Owner) {
+		return store.Schedule{}, false
+	}
+	return schedule, true
+}
+
+// getAllSchedules returns all schedules the user of the request may access.
+func (api *API) getAllSchedules(request *http.Request) map[uuid.UUID]store.Schedule {
+	schedules := api.store.GetAllSchedules()
+	name := requestUserName(request)
+	for id, schedule := range schedules {
+		if !api.canAccess(name, schedule.Owner) {
+			delete(schedules, id)
+		}
+	}
+	return schedules
+}
+
+// ownerError writes the error for requests which try to replace an object
+// owned by another user.
+func ownerError(writer http.ResponseWriter, kind, name string) {
+	errors := responseError{
+		ID:  "PermissionDenied",
+		Msg: fmt.Sprintf("%s %s is owned by another user", kind, name),
+	}
+	statusResponseError(writer, http.StatusForbidden, errors)
+}
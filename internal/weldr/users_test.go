@@ -0,0 +1,207 @@
+package weldr
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	rpmmd_mock "github.com/osbuild/osbuild-composer/internal/mocks/rpmmd"
+)
+
+// sendAsUser sends a request to the API as if it was received from the user
+// with the given name on the unix socket.
+func sendAsUser(api *API, name, method, path, body string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, path, strings.NewReader(body))
+	if method == "POST" {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	if name != "" {
+		request = request.WithContext(context.WithValue(request.Context(), userContextKey, name))
+	}
+	recorder := httptest.NewRecorder()
+	api.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func listBlueprintNames(t *testing.T, api *API, name string) []string {
+	resp := sendAsUser(api, name, "GET", "/api/v0/blueprints/list", "")
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	var reply struct {
+		Blueprints []string `json:"blueprints"`
+	}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &reply))
+	return reply.Blueprints
+}
+
+func listSourceNames(t *testing.T, api *API, name string) []string {
+	resp := sendAsUser(api, name, "GET", "/api/v0/projects/source/list", "")
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	var reply struct {
+		Sources []string `json:"sources"`
+	}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &reply))
+	return reply.Sources
+}
+
+func TestMultiUserBlueprints(t *testing.T) {
+	api, _ := createWeldrAPI(t.TempDir(), rpmmd_mock.BaseFixture)
+	api.SetUsersConfig(UsersConfig{MultiUser: true, Admins: []string{"admin"}})
+
+	bp := `{"name":"alice-bp","description":"Test","packages":[{"name":"httpd","version":"2.4.*"}],"version":"0.0.0"}`
+	resp := sendAsUser(api, "alice", "POST", "/api/v0/blueprints/new", bp)
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	require.Contains(t, listBlueprintNames(t, api, "alice"), "alice-bp")
+	require.NotContains(t, listBlueprintNames(t, api, "bob"), "alice-bp")
+	require.Contains(t, listBlueprintNames(t, api, "admin"), "alice-bp")
+
+	// bob can neither see, replace nor delete alice's blueprint
+	resp = sendAsUser(api, "bob", "GET", "/api/v0/blueprints/info/alice-bp", "")
+	require.Contains(t, resp.Body.String(), "UnknownBlueprint")
+
+	resp = sendAsUser(api, "bob", "POST", "/api/v0/blueprints/new", bp)
+	require.Equal(t, http.StatusForbidden, resp.Code)
+	require.Contains(t, resp.Body.String(), "PermissionDenied")
+
+	resp = sendAsUser(api, "bob", "POST", "/api/v0/blueprints/workspace", bp)
+	require.Equal(t, http.StatusForbidden, resp.Code)
+
+	resp = sendAsUser(api, "bob", "DELETE", "/api/v0/blueprints/delete/alice-bp", "")
+	require.NotEqual(t, http.StatusOK, resp.Code)
+	require.Contains(t, listBlueprintNames(t, api, "alice"), "alice-bp")
+
+	// admins can change it without taking it over
+	resp = sendAsUser(api, "admin", "POST", "/api/v0/blueprints/new", bp)
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.NotContains(t, listBlueprintNames(t, api, "bob"), "alice-bp")
+
+	resp = sendAsUser(api, "alice", "DELETE", "/api/v0/blueprints/delete/alice-bp", "")
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.NotContains(t, listBlueprintNames(t, api, "alice"), "alice-bp")
+
+	// once it is gone, the name can be claimed by another user
+	resp = sendAsUser(api, "bob", "POST", "/api/v0/blueprints/new", bp)
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.NotContains(t, listBlueprintNames(t, api, "alice"), "alice-bp")
+}
+
+func TestMultiUserSources(t *testing.T) {
+	api, _ := createWeldrAPI(t.TempDir(), rpmmd_mock.BaseFixture)
+	api.SetUsersConfig(UsersConfig{MultiUser: true})
+
+	source := `{"id": "alice-repo", "name": "alice-repo", "url": "file:///repo", "type": "yum-baseurl", "check_ssl": false, "check_gpg": false}`
+	resp := sendAsUser(api, "alice", "POST", "/api/v1/projects/source/new", source)
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	require.Contains(t, listSourceNames(t, api, "alice"), "alice-repo")
+	require.NotContains(t, listSourceNames(t, api, "bob"), "alice-repo")
+
+	resp = sendAsUser(api, "bob", "POST", "/api/v1/projects/source/new", source)
+	require.Equal(t, http.StatusForbidden, resp.Code)
+
+	resp = sendAsUser(api, "bob", "DELETE", "/api/v1/projects/source/delete/alice-repo", "")
+	require.NotEqual(t, http.StatusOK, resp.Code)
+	require.Contains(t, listSourceNames(t, api, "alice"), "alice-repo")
+
+	// deleting one of bob's sources never deletes one of alice's, even if
+	// its id is the name of alice's source
+	resp = sendAsUser(api, "alice", "POST", "/api/v1/projects/source/new", `{"id": "alice-shared", "name": "shared", "url": "file:///repo", "type": "yum-baseurl", "check_ssl": false, "check_gpg": false}`)
+	require.Equal(t, http.StatusOK, resp.Code)
+	resp = sendAsUser(api, "bob", "POST", "/api/v1/projects/source/new", `{"id": "shared", "name": "bob-repo", "url": "file:///repo", "type": "yum-baseurl", "check_ssl": false, "check_gpg": false}`)
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	resp = sendAsUser(api, "bob", "DELETE", "/api/v0/projects/source/delete/shared", "")
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.NotContains(t, listSourceNames(t, api, "bob"), "bob-repo")
+	require.Contains(t, listSourceNames(t, api, "alice"), "shared")
+}
+
+func TestMultiUserUnknownUser(t *testing.T) {
+	api, _ := createWeldrAPI(t.TempDir(), rpmmd_mock.BaseFixture)
+
+	resp := sendAsUser(api, "", "GET", "/api/v0/blueprints/list", "")
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	api.SetUsersConfig(UsersConfig{MultiUser: true})
+	resp = sendAsUser(api, "", "GET", "/api/v0/blueprints/list", "")
+	require.Equal(t, http.StatusUnauthorized, resp.Code)
+}
+
+func TestTokens(t *testing.T) {
+	api, _ := createWeldrAPI(t.TempDir(), rpmmd_mock.BaseFixture)
+	api.SetUsersConfig(UsersConfig{
+		MultiUser: true,
+		Tokens:    map[string]string{"secret": "alice"},
+	})
+
+	listener, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	go func() {
+		_ = api.Serve(listener)
+	}()
+
+	url := "http://" + listener.Addr().String() + "/api/v0/blueprints/list"
+	for _, c := range []struct {
+		Authorization  string
+		ExpectedStatus int
+	}{
+		{"", http.StatusUnauthorized},
+		{"Bearer", http.StatusUnauthorized},
+		{"Bearer wrong", http.StatusUnauthorized},
+		{"secret", http.StatusUnauthorized},
+		{"Bearer secret", http.StatusOK},
+	} {
+		request, err := http.NewRequest("GET", url, nil)
+		require.NoError(t, err)
+		if c.Authorization != "" {
+			request.Header.Set("Authorization", c.Authorization)
+		}
+		resp, err := http.DefaultClient.Do(request)
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equalf(t, c.ExpectedStatus, resp.StatusCode, "Authorization: %q", c.Authorization)
+	}
+}
+
+func TestPeerCredentials(t *testing.T) {
+	current, err := user.Current()
+	require.NoError(t, err)
+
+	api, _ := createWeldrAPI(t.TempDir(), rpmmd_mock.BaseFixture)
+	api.SetUsersConfig(UsersConfig{MultiUser: true})
+
+	socket := filepath.Join(t.TempDir(), "api.socket")
+	listener, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+	defer listener.Close()
+	go func() {
+		_ = api.Serve(listener)
+	}()
+
+	client := http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, "unix", socket)
+			},
+		},
+	}
+
+	bp := `{"name":"peer-bp","description":"Test","packages":[],"version":"0.0.0"}`
+	resp, err := client.Post("http://localhost/api/v0/blueprints/new", "application/json", strings.NewReader(bp))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	require.Equal(t, current.Username, api.store.GetBlueprintOwner("peer-bp"))
+}
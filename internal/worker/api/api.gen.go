@@ -62,6 +62,9 @@ type RequestJobResponse struct {
 	// Embedded struct due to allOf(#/components/schemas/ObjectReference)
 	ObjectReference `yaml:",inline"`
 	// Embedded fields due to inline allOf schema
+	// Whether the job's log lines can be reported with updates.
+	// Older servers finish the job on any update.
+	AcceptsLog       *bool              `json:"accepts_log,omitempty"`
 	Args             *json.RawMessage   `json:"args,omitempty"`
 	ArtifactLocation string             `json:"artifact_location"`
 	DynamicArgs      *[]json.RawMessage `json:"dynamic_args,omitempty"`
@@ -77,9 +80,12 @@ type StatusResponse struct {
 	Status string `json:"status"`
 }
 
-// UpdateJobRequest defines model for UpdateJobRequest.
+// Finishes the job with the given result. Updates which only contain
+// log lines report them without finishing the job.
 type UpdateJobRequest struct {
-	Result json.RawMessage `json:"result"`
+	// Lines of the job's log since the last update
+	Log    *[]string        `json:"log,omitempty"`
+	Result *json.RawMessage `json:"result,omitempty"`
 }
 
 // UpdateJobResponse defines model for UpdateJobResponse.
@@ -269,26 +275,29 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/9xYX2/bNhD/KgS3hw2QLadpXwTsoemGIh26DMmKFciC4EydLSYSqZAnO4ah7z6QlP9J",
-	"ip0A8UPzJFk83p/f/Xh39JILXZRaoSLLkyW3IsMC/OsfxmjjXiDPLyY8uV7ynw1OeMJ/ijeb4mZHfDG+",
-	"Q0GXOEGDSiCvoyUvjS7RkESvUOgU3ZMWJfKEWzJSTXkd8QKthalfS9EKI0uSWvGEn4G4n4NJmbMHJMcy",
-	"l7Rgc0kZm2tzj8ay/6rR6FT8xmanpxHDhwpyywyC1YpHXVPOH3Dab2Xa60uztbvk1x4qaTDlyXUIZi3e",
-	"UrwJ6Wbtg/b48PqmjvhnpC96fIm21Mriq2IMSmCO27GNtc4RVDeClWi/j21bSdtU5h3tgfAJZO+lSg/j",
-	"6tHzolGw0PUu4pf4UKENGPq3rndgRNbrhvvgJSRhYZ8U4QkHY2DRcTDsj4KBQ869foLBTP3zcTDVg8b2",
-	"ndVqeAnzrw3paucdyQkIus21gHCaegJNFwoKKW5XSteQHNC+C1DE9xoJHw7l3a9uaeoLoZ+oVwRU2WNg",
-	"bb3mw743cv3ufStTINxHVYO2yukg7C2jza4+Bm6Z3IDyIiicMakmuluS/8mkZdIyUOzj3+dsos26EpNm",
-	"JsTIQKUsA5XmyO702A5dKZaUOzcvrs4qmafsk3PDomED9q9XwCM+Q2ODmZOmWCsoJU/46XA0HPGIl0CZ",
-	"xyxG151svJRp7X5Pkbq+fkbnCZPKkqt1TE8YZcj8VmZLFHIiMWXjBfNVZ13Cz9OwOXRAZ9VAgYTGelLt",
-	"Gjn/fUcvd8DxxHvKI66gcEF7/ZvskakwanqtcxsfoSg9Oien3a5V37i9IZM++HejUeinilD5uKEscxlO",
-	"SXzX9K+N+n2pDzHWPuPvv38/it4PR9FbR9yiqIykhU/LGYJBw5PrGweYrYoCzKJhQUj5duLc9thx059H",
-	"bXvo0xxYy8CReMg89dckYeNci3vLKkUyDyL+XMxA5jDOcdhh1KYxNGRAS2c6XbwaNt22GGBqkefkKAab",
-	"SuMN7uL4ySAQpu5Evxu9fzXjvUVr1/Jf2qdlDlt5iRiZBYMpSMV/NM634/Ms3jD9clV9XdQbhsdL0veo",
-	"tutkp9StSHmkKtMaeHtCufiT/5AVaKfMmEopqaYB/k7f6OkLPjF7W0NPLyiBwmy7m8V11z9SdekMMr3F",
-	"ZXQMe2+YNiFKBrvcaR/deDUM23jpqOPPcllRHwtyDekXPf7Y7ODP4aF/vISG0evR+Xlc1YKQBpYMQrEL",
-	"elvlU6R8c8RxiXbz7YobgTbrofnpYn/RiDwHp0adH5eZVMz57qb+AvxV48MxRtH2If+m8LFEQZg2g5wW",
-	"ojKOX90S7AbxvT47jDYXu957w5V00zgLUs09xrB5JkXGDFJllGUWzUyKlVDf7eFqtXK0Ctm6+b7F8tjA",
-	"20z7ZtZ/B/sKUrFfSqPTSrhPv7IgyyNemZwnPCMqbRLHUMqhY4fN5ISGQhfuSywLmOJg7K6laAbhOhvP",
-	"Tvw/Ai1mEExdkd6j3hJM8YVGgpaXiG0t3NT/BwAA//+IToO0xRUAAA==",
+	"H4sIAAAAAAAC/9xYW2/bthf/KgT/f2AboFhO074I2EPTbUW7dR6SFS3QBMExdSwxoUiVPEpqGP7uA0n5",
+	"JilxAsQP7ZNl8vBcf+dCLrgwVW00anI8W3AnSqwgfP5urbH+A5SazHj2ZcH/b3HGM/6/dHMobU+kk+k1",
+	"CjrDGVrUAvkyWfDamhotSQwMhcnR/9K8Rp5xR1bqgi8TXqFzUIS9HJ2wsiZpNM/4KYibO7A58/KA5FQq",
+	"SXN2J6lkd8beoHXsohmPT8Sv7PbkJGH4tQHlmEVwRvOkL8rrA577lcwHdWmP9rfC3tdGWsx59iUasybv",
+	"MN6YdLnWwQT/8OXlMuFvkd6b6Rm62miHz+pj0AIVbts2NUYh6L4FK9JhHbuysq6oMig64MJ7PHsjdb7f",
+	"r8F7gTSJEvraJfwMvzboog/DV187sKIcVMMvBApJWLl7SXjGwVqY9xSM55MoYJ9yzx9gEAJrclfKFP18",
+	"+VQilWgZlciuzfQnx5QpmJIaHROg2RSZxdpYwjwmUVPnQOhGF3qicrTMob31STWTWrpyxYcZzUDPW+rR",
+	"xVZmrcHlHVIEDb8dFeao3b52Ro/O4O5Dmw6BjOQMBF0pIyDqPRCCfK6hkuJqxXQdrD3cd0OX8AeFxIV9",
+	"iAy7W5yGTBhOoXMCatwhUOAC5/26t3TD6n0M4dxNol08/RFggG4NhAAa/6eQt6iZRdcoGrHIybG7UoqS",
+	"Ga3mTBhNIPWF3iAwQs8frwIj01ALNKmLlYiIrl1rB7H+V+BpZh2wO6kFhjUFjlrI8uTR2Z7waNRerC0H",
+	"Un/Lo5uYPynSnq3UM9O3999SOiYdA81e//OOzYxdt0AyzMYQMtA5K0HnKvjEjXjCSZLyak7OTxupcvbG",
+	"q+HQsiP2KTDgCfdJH8Uct11SQy15xk9G49HYBwSoDO5L0VpjXbqQ+dL/L3AAN2/Ra8KkduSbzCpK4Shz",
+	"NQo5k5iz6ZyFcr/une/yeDiOHl6qhQoJrQs5syvk3W87fH2M/bLXlCdcQ+WNDvw3GUG2waQdcrza+A2q",
+	"Onjn+KQ/Liwv/dkYyWD8i/GYh0FGE+pgN9S1krEIpNft4LBh/1Doo43LEPGXnz8fhO+rg/BdJtyhaKyk",
+	"eQjLKYJFy7Mvl95hrqkqsPMWBTHk24Hzx1OPzdCzzVDZaeuRYxAqAgvQX4OETZURN441mqSKJCEvbkEq",
+	"mCoc9RC16cgtGNDRqcnnz+ab/jwS3dQBz/FBBEYRsXTs+vGNRSDMfUa/GL98NuGDRWtX8t8mtgvYikvC",
+	"yM4ZFCA1/94w37UvoHiD9LNV9fVWbxCeLsjcoN6uk71StwLlgapM56YxYMrkT/5dVqCdMmMbrf0QEdzf",
+	"6xsDfSEE5sHWMNALaiBR9qO47voHqi69OW2wuIwPIe8Hhk20ksEudrqpm65mfZcuPHRCLtcNDaFAGcjf",
+	"m+nr9gR/DA7Dz1NgmDwfnB+HVSMI6ciRRah2nd5leR8ofzjg+ED7+XaFjQib9dB8f7GftCSP8VPLLozL",
+	"TGrmdfdTfwXhqvHqEKNoN8k/avxWo/DPBXGQM0I01uOrX4L9IP6gzt5Hm3vr4L3hXPppnEWq9h5j21ul",
+	"RWqsduGJQooV0dDt4Xy1c7AK2bnY/4jlsXVvXI2vQgN3sA8gNfu5tiZvhF/6pX1B4glvrOIZL4lql6Up",
+	"1HLk0eFKOaORMJVfSWUFBR5N/bUU7VG8zqa3x+HBo4MMgsIX6QfYO4ICnygkcnkK2dbG5fK/AQBrSit6",
+	"PhcAAA==",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
@@ -42,6 +42,7 @@ type Job interface {
 	DynamicArgs(i int, args interface{}) error
 	NDynamicArgs() int
 	Update(result interface{}) error
+	Log(lines []string) error
 	Canceled() (bool, error)
 	UploadArtifact(name string, reader io.Reader) error
 }
@@ -53,6 +54,7 @@ type job struct {
 	id               uuid.UUID
 	location         string
 	artifactLocation string
+	acceptsLog       bool
 	jobType          string
 	args             json.RawMessage
 	dynamicArgs      []json.RawMessage
@@ -233,6 +235,7 @@ func (c *Client) RequestJob(types []string, arch string) (Job, error) {
 		dynamicArgs:      dynamicArgs,
 		location:         location.String(),
 		artifactLocation: artifactLocation.String(),
+		acceptsLog:       jr.AcceptsLog != nil && *jr.AcceptsLog,
 	}, nil
 }
 
@@ -265,10 +268,25 @@ func (j *job) DynamicArgs(i int, args interface{}) error {
 }
 
 func (j *job) Update(result interface{}) error {
-	var buf bytes.Buffer
-	err := json.NewEncoder(&buf).Encode(updateJobRequest{
+	return j.update(updateJobRequest{
 		Result: result,
 	})
+}
+
+// Log reports lines of the job's log to composer, without finishing the job.
+// The lines are dropped if composer doesn't accept them.
+func (j *job) Log(lines []string) error {
+	if !j.acceptsLog {
+		return nil
+	}
+	return j.update(updateJobRequest{
+		Log: lines,
+	})
+}
+
+func (j *job) update(request updateJobRequest) error {
+	var buf bytes.Buffer
+	err := json.NewEncoder(&buf).Encode(request)
 	if err != nil {
 		panic(err)
 	}
@@ -19,11 +19,16 @@ const (
 	ErrorKojiFinalize         ClientErrorCode = 16
 	ErrorInvalidConfig        ClientErrorCode = 17
 	ErrorOldResultCompatible  ClientErrorCode = 18
+	ErrorDeduplication        ClientErrorCode = 19
 
 	ErrorDNFDepsolveError ClientErrorCode = 20
 	ErrorDNFMarkingError  ClientErrorCode = 21
 	ErrorDNFOtherError    ClientErrorCode = 22
 	ErrorRPMMDError       ClientErrorCode = 23
+
+	ErrorOSTreeCommit ClientErrorCode = 24
+
+	ErrorImageCleanup ClientErrorCode = 25
 )
 
 type ClientErrorCode int
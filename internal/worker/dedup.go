@@ -0,0 +1,282 @@
+package worker
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/osbuild/osbuild-composer/internal/blueprint"
+	"github.com/osbuild/osbuild-composer/internal/distro"
+	"github.com/osbuild/osbuild-composer/internal/jobqueue"
+	"github.com/osbuild/osbuild-composer/internal/rpmmd"
+	"github.com/osbuild/osbuild-composer/internal/target"
+	"github.com/osbuild/osbuild-composer/internal/worker/clienterrors"
+)
+
+// ContentHash returns a hash identifying the content of an image: the image
+// type and its distribution and architecture, the depsolved package sets, the
+// blueprint customizations, and the image options. Two composes with the same
+// content hash produce equivalent images.
+//
+// Packages are identified by their NEVRA and checksum only, so that the hash
+// doesn't change when the same package is fetched from a different mirror.
+func ContentHash(imageType distro.ImageType, packageSpecs map[string][]rpmmd.PackageSpec, customizations *blueprint.Customizations, options distro.ImageOptions) (string, error) {
+	type pkg struct {
+		NEVRA    string `json:"nevra"`
+		Checksum string `json:"checksum"`
+	}
+
+	packageSets := make(map[string][]pkg)
+	for name, specs := range packageSpecs {
+		pkgs := make([]pkg, 0, len(specs))
+		for _, spec := range specs {
+			pkgs = append(pkgs, pkg{
+				NEVRA:    fmt.Sprintf("%s-%d:%s-%s.%s", spec.Name, spec.Epoch, spec.Version, spec.Release, spec.Arch),
+				Checksum: spec.Checksum,
+			})
+		}
+		sort.Slice(pkgs, func(i, j int) bool {
+			return pkgs[i].NEVRA < pkgs[j].NEVRA
+		})
+		packageSets[name] = pkgs
+	}
+
+	// how the parent commit is pulled doesn't change the image
+	options.OSTree.RHSM = false
+
+	// encoding/json sorts map keys, which makes this deterministic
+	content, err := json.Marshal(struct {
+		Distro         string                    `json:"distro"`
+		Arch           string                    `json:"arch"`
+		ImageType      string                    `json:"image_type"`
+		PackageSets    map[string][]pkg          `json:"package_sets"`
+		Customizations *blueprint.Customizations `json:"customizations"`
+		Options        distro.ImageOptions       `json:"options"`
+	}{
+		Distro:         imageType.Arch().Distro().Name(),
+		Arch:           imageType.Arch().Name(),
+		ImageType:      imageType.Name(),
+		PackageSets:    packageSets,
+		Customizations: customizations,
+		Options:        options,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(content)), nil
+}
+
+// dedupKey returns the key of an osbuild job for deduplication, by which
+// it's found in the job queue. Only jobs in the same channel (i.e., of the
+// same tenant) and with the same targets are considered duplicates of each
+// other. The targets must match in all their options, except for the name of
+// the image they upload, which is generated for each compose.
+func dedupKey(channel, contentHash string, job *OSBuildJob) (string, error) {
+	type dedupTarget struct {
+		Name    string               `json:"name"`
+		Options target.TargetOptions `json:"options"`
+	}
+
+	targets := make([]dedupTarget, 0, len(job.Targets))
+	for _, t := range job.Targets {
+		options := t.Options
+		switch o := t.Options.(type) {
+		case *target.AWSTargetOptions:
+			c := *o
+			c.Key = ""
+			options = &c
+		case *target.AWSS3TargetOptions:
+			c := *o
+			c.Key = ""
+			options = &c
+		case *target.GCPTargetOptions:
+			c := *o
+			c.Object = ""
+			options = &c
+		}
+		targets = append(targets, dedupTarget{Name: t.Name, Options: options})
+	}
+
+	rawTargets, err := json.Marshal(targets)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("dedup:%s/%s/%x", channel, contentHash, sha256.Sum256(rawTargets)), nil
+}
+
+// osbuildJobContentHash returns the content hash of an osbuild job. It is
+// either part of the job's arguments or, when the manifest was generated by a
+// dependency, of that dependency's result.
+func (s *Server) osbuildJobContentHash(job *OSBuildJob, deps []uuid.UUID) string {
+	if job.ContentHash != "" {
+		return job.ContentHash
+	}
+
+	if len(deps) == 0 {
+		return ""
+	}
+
+	var manifestResult ManifestJobByIDResult
+	_, _, err := s.ManifestJobStatus(deps[0], &manifestResult)
+	if err != nil {
+		return ""
+	}
+	return manifestResult.ContentHash
+}
+
+// findDedupCandidate returns the most recent osbuild job other than `id`,
+// which has the deduplication key `key`, finished successfully, and whose
+// artifacts still exist. The candidates are looked up by their key in the job
+// queue, so that they survive restarts and are shared between all composer
+// instances using the same queue.
+func (s *Server) findDedupCandidate(key string, id uuid.UUID) (uuid.UUID, *OSBuildJobResult, error) {
+	ids, err := s.jobs.JobsByKey(key)
+	if err != nil {
+		return uuid.Nil, nil, err
+	}
+
+	for _, candidateID := range ids {
+		if candidateID == id {
+			continue
+		}
+
+		var result OSBuildJobResult
+		status, _, err := s.OSBuildJobStatus(candidateID, &result)
+		if err != nil || status.Finished.IsZero() || status.Canceled || !result.Success || result.JobError != nil {
+			continue
+		}
+		if s.config.ArtifactsDir != "" {
+			_, err = os.Stat(path.Join(s.config.ArtifactsDir, candidateID.String()))
+			if err != nil {
+				// the artifacts were deleted
+				continue
+			}
+		}
+
+		return candidateID, &result, nil
+	}
+
+	return uuid.Nil, nil, nil
+}
+
+// DeduplicateOSBuildJob finishes the pending osbuild job `id` with the result
+// of a previous job with the same content hash, instead of building the
+// image again. Artifacts of the previous job are linked into the new job's
+// artifact directory and its target results are reused.
+//
+// Returns true if the job was deduplicated, and false if deduplication is
+// disabled, no previous job exists, or the job was already picked up by a
+// worker. Either way, the job's result can be reused by later jobs: only jobs
+// which were passed to this function are considered. The job must be pending, that is, all its dependencies must have
+// finished.
+func (s *Server) DeduplicateOSBuildJob(ctx context.Context, id uuid.UUID) (bool, error) {
+	if !s.config.DeduplicateComposes {
+		return false, nil
+	}
+
+	var job OSBuildJob
+	jobType, rawArgs, deps, channel, err := s.jobs.Job(id)
+	if err != nil {
+		return false, err
+	}
+	if !strings.HasPrefix(jobType, "osbuild:") {
+		return false, fmt.Errorf("expected osbuild:*, found %q job instead for job '%s'", jobType, id)
+	}
+	if err := json.Unmarshal(rawArgs, &job); err != nil {
+		return false, fmt.Errorf("error unmarshaling arguments for job '%s': %v", id, err)
+	}
+
+	contentHash := s.osbuildJobContentHash(&job, deps)
+	if contentHash == "" {
+		return false, nil
+	}
+
+	key, err := dedupKey(channel, contentHash, &job)
+	if err != nil {
+		return false, err
+	}
+	// later jobs with the same content can reuse this one's result
+	err = s.jobs.SetJobKey(id, key)
+	if err != nil {
+		return false, err
+	}
+	previousID, previousResult, err := s.findDedupCandidate(key, id)
+	if err != nil {
+		return false, err
+	}
+	if previousResult == nil {
+		return false, nil
+	}
+	result := *previousResult
+
+	_, token, _, _, _, err := s.RequestJobById(ctx, "", id)
+	if err == jobqueue.ErrNotPending {
+		// a worker was faster, let it build the image
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	if s.config.ArtifactsDir != "" {
+		err = linkArtifacts(path.Join(s.config.ArtifactsDir, previousID.String()), path.Join(s.config.ArtifactsDir, "tmp", token.String()))
+		if err != nil {
+			logrus.Errorf("Error linking artifacts of job %s for deduplicated job %s: %v", previousID, id, err)
+			result = OSBuildJobResult{
+				JobResult: JobResult{
+					JobError: clienterrors.WorkerClientError(clienterrors.ErrorDeduplication, "Error reusing the artifacts of a previous build"),
+				},
+			}
+		}
+	}
+
+	// refer to the job which built the image, the previous one might only
+	// have reused it as well
+	if result.JobError == nil && result.DeduplicatedFrom == nil {
+		result.DeduplicatedFrom = &previousID
+	}
+
+	rawResult, err := json.Marshal(result)
+	if err != nil {
+		return false, err
+	}
+
+	err = s.FinishJob(token, rawResult)
+	if err != nil {
+		return false, err
+	}
+
+	return result.JobError == nil, nil
+}
+
+// linkArtifacts hard links all artifacts from directory `from` to directory
+// `to`, which must exist.
+func linkArtifacts(from, to string) error {
+	files, err := ioutil.ReadDir(from)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		if !f.Mode().IsRegular() {
+			return errors.New("unexpected non-regular artifact: " + f.Name())
+		}
+		err = os.Link(path.Join(from, f.Name()), path.Join(to, f.Name()))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
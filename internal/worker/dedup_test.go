@@ -0,0 +1,242 @@
+package worker_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/osbuild-composer/internal/blueprint"
+	"github.com/osbuild/osbuild-composer/internal/distro"
+	"github.com/osbuild/osbuild-composer/internal/distro/test_distro"
+	"github.com/osbuild/osbuild-composer/internal/jobqueue/fsjobqueue"
+	"github.com/osbuild/osbuild-composer/internal/osbuild2"
+	"github.com/osbuild/osbuild-composer/internal/rpmmd"
+	"github.com/osbuild/osbuild-composer/internal/target"
+	"github.com/osbuild/osbuild-composer/internal/worker"
+)
+
+func TestContentHash(t *testing.T) {
+	arch, err := test_distro.New().GetArch(test_distro.TestArchName)
+	require.NoError(t, err)
+	imageType, err := arch.GetImageType(test_distro.TestImageTypeName)
+	require.NoError(t, err)
+
+	pkgs := map[string][]rpmmd.PackageSpec{
+		"packages": {
+			{Name: "bash", Version: "5.1", Release: "1", Arch: "x86_64", Checksum: "sha256:aaa", RemoteLocation: "https://mirror1/bash.rpm"},
+			{Name: "kernel", Version: "5.15", Release: "1", Arch: "x86_64", Checksum: "sha256:bbb", RemoteLocation: "https://mirror1/kernel.rpm"},
+		},
+	}
+	options := distro.ImageOptions{Size: 1024}
+
+	hash, err := worker.ContentHash(imageType, pkgs, nil, options)
+	require.NoError(t, err)
+
+	// package order and mirrors don't matter
+	reordered := map[string][]rpmmd.PackageSpec{
+		"packages": {
+			{Name: "kernel", Version: "5.15", Release: "1", Arch: "x86_64", Checksum: "sha256:bbb", RemoteLocation: "https://mirror2/kernel.rpm"},
+			{Name: "bash", Version: "5.1", Release: "1", Arch: "x86_64", Checksum: "sha256:aaa", RemoteLocation: "https://mirror2/bash.rpm"},
+		},
+	}
+	other, err := worker.ContentHash(imageType, reordered, nil, options)
+	require.NoError(t, err)
+	require.Equal(t, hash, other)
+
+	// a package update does
+	updated := map[string][]rpmmd.PackageSpec{
+		"packages": {
+			{Name: "bash", Version: "5.1", Release: "2", Arch: "x86_64", Checksum: "sha256:ccc"},
+			{Name: "kernel", Version: "5.15", Release: "1", Arch: "x86_64", Checksum: "sha256:bbb"},
+		},
+	}
+	other, err = worker.ContentHash(imageType, updated, nil, options)
+	require.NoError(t, err)
+	require.NotEqual(t, hash, other)
+
+	// so do customizations and image options
+	hostname := "my-host"
+	other, err = worker.ContentHash(imageType, pkgs, &blueprint.Customizations{Hostname: &hostname}, options)
+	require.NoError(t, err)
+	require.NotEqual(t, hash, other)
+
+	other, err = worker.ContentHash(imageType, pkgs, nil, distro.ImageOptions{Size: 2048})
+	require.NoError(t, err)
+	require.NotEqual(t, hash, other)
+}
+
+func TestDeduplicateOSBuildJob(t *testing.T) {
+	tempdir, err := ioutil.TempDir("", "worker-tests-")
+	require.NoError(t, err)
+	defer os.RemoveAll(tempdir)
+
+	q, err := fsjobqueue.New(tempdir)
+	require.NoError(t, err)
+	artifactsDir, err := ioutil.TempDir("", "worker-tests-artifacts-")
+	require.NoError(t, err)
+	defer os.RemoveAll(artifactsDir)
+	server := worker.NewServer(nil, q, worker.Config{
+		ArtifactsDir:        artifactsDir,
+		DeduplicateComposes: true,
+	})
+
+	arch, err := test_distro.New().GetArch(test_distro.TestArchName)
+	require.NoError(t, err)
+
+	enqueue := func(contentHash, channel string) uuid.UUID {
+		id, err := server.EnqueueOSBuild(arch.Name(), &worker.OSBuildJob{ContentHash: contentHash}, channel)
+		require.NoError(t, err)
+		return id
+	}
+
+	// nothing to deduplicate against yet
+	first := enqueue("sha256:1", "")
+	deduplicated, err := server.DeduplicateOSBuildJob(context.Background(), first)
+	require.NoError(t, err)
+	require.False(t, deduplicated)
+
+	// build it
+	_, token, _, _, _, err := server.RequestJob(context.Background(), arch.Name(), []string{"osbuild"}, []string{""})
+	require.NoError(t, err)
+	err = ioutil.WriteFile(path.Join(artifactsDir, "tmp", token.String(), "disk.img"), []byte("image"), 0600)
+	require.NoError(t, err)
+	result, err := json.Marshal(worker.OSBuildJobResult{
+		Success:       true,
+		OSBuildOutput: &osbuild2.Result{Success: true},
+	})
+	require.NoError(t, err)
+	require.NoError(t, server.FinishJob(token, result))
+
+	second := enqueue("sha256:1", "")
+	deduplicated, err = server.DeduplicateOSBuildJob(context.Background(), second)
+	require.NoError(t, err)
+	require.True(t, deduplicated)
+
+	var secondResult worker.OSBuildJobResult
+	status, _, err := server.OSBuildJobStatus(second, &secondResult)
+	require.NoError(t, err)
+	require.False(t, status.Finished.IsZero())
+	require.True(t, secondResult.Success)
+	require.Equal(t, &first, secondResult.DeduplicatedFrom)
+
+	reader, size, err := server.JobArtifact(second, "disk.img")
+	require.NoError(t, err)
+	require.Equal(t, int64(5), size)
+	content, err := ioutil.ReadAll(reader)
+	require.NoError(t, err)
+	require.Equal(t, "image", string(content))
+
+	// previous builds are found after a restart
+	restarted := worker.NewServer(nil, q, worker.Config{
+		ArtifactsDir:        artifactsDir,
+		DeduplicateComposes: true,
+	})
+	afterRestart, err := restarted.EnqueueOSBuild(arch.Name(), &worker.OSBuildJob{ContentHash: "sha256:1"}, "")
+	require.NoError(t, err)
+	deduplicated, err = restarted.DeduplicateOSBuildJob(context.Background(), afterRestart)
+	require.NoError(t, err)
+	require.True(t, deduplicated)
+
+	// it reuses the result of `second`, but refers to the job which built
+	// the image
+	var afterRestartResult worker.OSBuildJobResult
+	_, _, err = server.OSBuildJobStatus(afterRestart, &afterRestartResult)
+	require.NoError(t, err)
+	require.Equal(t, &first, afterRestartResult.DeduplicatedFrom)
+
+	// different content
+	third := enqueue("sha256:2", "")
+	deduplicated, err = server.DeduplicateOSBuildJob(context.Background(), third)
+	require.NoError(t, err)
+	require.False(t, deduplicated)
+
+	// same content, but different tenant
+	fourth := enqueue("sha256:1", "org-other")
+	deduplicated, err = server.DeduplicateOSBuildJob(context.Background(), fourth)
+	require.NoError(t, err)
+	require.False(t, deduplicated)
+
+	// the artifacts of the original builds are gone
+	require.NoError(t, server.DeleteArtifacts(first))
+	require.NoError(t, server.DeleteArtifacts(second))
+	require.NoError(t, server.DeleteArtifacts(afterRestart))
+	fifth := enqueue("sha256:1", "")
+	deduplicated, err = server.DeduplicateOSBuildJob(context.Background(), fifth)
+	require.NoError(t, err)
+	require.False(t, deduplicated)
+}
+
+func TestDeduplicateOSBuildJobDisabled(t *testing.T) {
+	tempdir, err := ioutil.TempDir("", "worker-tests-")
+	require.NoError(t, err)
+	defer os.RemoveAll(tempdir)
+
+	server := newTestServer(t, tempdir, 0, "/api/worker/v1")
+
+	arch, err := test_distro.New().GetArch(test_distro.TestArchName)
+	require.NoError(t, err)
+
+	id, err := server.EnqueueOSBuild(arch.Name(), &worker.OSBuildJob{ContentHash: "sha256:1"}, "")
+	require.NoError(t, err)
+
+	deduplicated, err := server.DeduplicateOSBuildJob(context.Background(), id)
+	require.NoError(t, err)
+	require.False(t, deduplicated)
+}
+
+func TestDeduplicateOSBuildJobTargets(t *testing.T) {
+	q, err := fsjobqueue.New(t.TempDir())
+	require.NoError(t, err)
+	server := worker.NewServer(nil, q, worker.Config{
+		ArtifactsDir:        t.TempDir(),
+		DeduplicateComposes: true,
+	})
+
+	arch, err := test_distro.New().GetArch(test_distro.TestArchName)
+	require.NoError(t, err)
+
+	enqueue := func(imageName, key, region string) uuid.UUID {
+		awsTarget := target.NewAWSTarget(&target.AWSTargetOptions{Region: region, Key: key})
+		awsTarget.ImageName = imageName
+		id, err := server.EnqueueOSBuild(arch.Name(), &worker.OSBuildJob{
+			ContentHash: "sha256:1",
+			Targets:     []*target.Target{awsTarget},
+		}, "")
+		require.NoError(t, err)
+		return id
+	}
+
+	first := enqueue("image-1", "key-1", "eu-central-1")
+	deduplicated, err := server.DeduplicateOSBuildJob(context.Background(), first)
+	require.NoError(t, err)
+	require.False(t, deduplicated)
+	_, token, _, _, _, err := server.RequestJob(context.Background(), arch.Name(), []string{"osbuild"}, []string{""})
+	require.NoError(t, err)
+	result, err := json.Marshal(worker.OSBuildJobResult{
+		Success:       true,
+		OSBuildOutput: &osbuild2.Result{Success: true},
+		TargetResults: []*target.TargetResult{
+			target.NewAWSTargetResult(&target.AWSTargetResultOptions{Ami: "ami-1", Region: "eu-central-1"}),
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, server.FinishJob(token, result))
+
+	// the image is uploaded to a different region
+	other := enqueue("image-2", "key-2", "us-east-1")
+	deduplicated, err = server.DeduplicateOSBuildJob(context.Background(), other)
+	require.NoError(t, err)
+	require.False(t, deduplicated)
+
+	// only the names of the uploaded image differ
+	same := enqueue("image-3", "key-3", "eu-central-1")
+	deduplicated, err = server.DeduplicateOSBuildJob(context.Background(), same)
+	require.NoError(t, err)
+	require.True(t, deduplicated)
+}
@@ -0,0 +1,317 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/osbuild/osbuild-composer/internal/jobqueue"
+	"github.com/osbuild/osbuild-composer/internal/notification"
+)
+
+// The number of lines of a running job's log which are kept for subscribers
+// who start listening after the job started.
+const maxJobLogLines = 1000
+
+// The number of events which are buffered for each subscriber. Subscribers
+// who fall further behind are dropped, so that they can't block the server.
+const jobEventsBuffer = 256
+
+// JobEvent is sent to subscribers of job events whenever a job started,
+// finished, or was canceled, and whenever a running job logged lines.
+type JobEvent struct {
+	JobID uuid.UUID
+	// Lines the job logged. Empty for changes of the job's status.
+	Lines []string
+}
+
+// ComposeEvent is a status transition of a compose or lines which one of
+// its jobs logged.
+type ComposeEvent struct {
+	// Status is only set for status transitions
+	Status notification.Status
+	JobID  uuid.UUID
+	Lines  []string
+}
+
+// jobEventMessage is how job events are passed between the composer
+// instances which share a job queue. See jobqueue.EventBroadcaster.
+type jobEventMessage struct {
+	JobID uuid.UUID `json:"job_id"`
+	Lines []string  `json:"lines,omitempty"`
+	// Done is set when the job finished or was canceled, to drop the
+	// lines kept for it.
+	Done bool `json:"done,omitempty"`
+}
+
+type jobEvents struct {
+	mu          sync.Mutex
+	subscribers map[chan JobEvent]struct{}
+	logs        map[uuid.UUID][]string
+}
+
+func newJobEvents() *jobEvents {
+	return &jobEvents{
+		subscribers: make(map[chan JobEvent]struct{}),
+		logs:        make(map[uuid.UUID][]string),
+	}
+}
+
+func (e *jobEvents) publish(event JobEvent) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if len(event.Lines) > 0 {
+		lines := append(e.logs[event.JobID], event.Lines...)
+		if len(lines) > maxJobLogLines {
+			lines = append([]string{}, lines[len(lines)-maxJobLogLines:]...)
+		}
+		e.logs[event.JobID] = lines
+	}
+
+	for ch := range e.subscribers {
+		select {
+		case ch <- event:
+		default:
+			logrus.Warn("dropping subscriber of job events which fell behind")
+			delete(e.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// forgetLog drops the log lines kept for a job which isn't running anymore.
+func (e *jobEvents) forgetLog(id uuid.UUID) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.logs, id)
+}
+
+func (e *jobEvents) receive(message jobEventMessage) {
+	e.publish(JobEvent{JobID: message.JobID, Lines: message.Lines})
+	if message.Done {
+		e.forgetLog(message.JobID)
+	}
+}
+
+func (e *jobEvents) subscribe() (chan JobEvent, map[uuid.UUID][]string, func()) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	ch := make(chan JobEvent, jobEventsBuffer)
+	e.subscribers[ch] = struct{}{}
+
+	logs := make(map[uuid.UUID][]string, len(e.logs))
+	for id, lines := range e.logs {
+		logs[id] = lines
+	}
+
+	unsubscribe := func() {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		if _, ok := e.subscribers[ch]; ok {
+			delete(e.subscribers, ch)
+			close(ch)
+		}
+	}
+
+	return ch, logs, unsubscribe
+}
+
+// SubscribeJobEvents returns a channel which receives the events of all
+// jobs, and the lines which running jobs logged so far. The channel is closed
+// when the returned function is called, or when the subscriber falls too far
+// behind.
+func (s *Server) SubscribeJobEvents() (<-chan JobEvent, map[uuid.UUID][]string, func()) {
+	return s.events.subscribe()
+}
+
+// AppendJobLog reports lines which the running job with `token` logged to
+// the subscribers of job events.
+func (s *Server) AppendJobLog(token uuid.UUID, lines []string) error {
+	jobId, err := s.jobs.IdFromToken(token)
+	if err != nil {
+		switch err {
+		case jobqueue.ErrNotExist:
+			return ErrInvalidToken
+		default:
+			return err
+		}
+	}
+
+	if len(lines) > 0 {
+		s.publishJobEvent(jobEventMessage{JobID: jobId, Lines: lines})
+	}
+	return nil
+}
+
+// publishJobEvent passes an event to the subscribers of all composer
+// instances which share the job queue, or only to those of this instance if
+// the job queue can't broadcast events.
+func (s *Server) publishJobEvent(message jobEventMessage) {
+	broadcaster, ok := s.jobs.(jobqueue.EventBroadcaster)
+	if !ok {
+		s.events.receive(message)
+		return
+	}
+
+	for _, m := range splitJobEventMessage(message) {
+		payload, err := json.Marshal(m)
+		if err != nil {
+			logrus.Errorf("error marshaling event of job %s: %v", m.JobID, err)
+			return
+		}
+		err = broadcaster.BroadcastEvent(payload)
+		if err != nil {
+			logrus.Errorf("error broadcasting event of job %s: %v", m.JobID, err)
+			return
+		}
+	}
+}
+
+// listenForJobEvents passes the job events of all composer instances to the
+// subscribers of this one. Events sent while the connection to the job queue
+// is broken are lost.
+func (s *Server) listenForJobEvents(broadcaster jobqueue.EventBroadcaster) {
+	for {
+		err := broadcaster.ListenForEvents(context.Background(), func(payload []byte) {
+			var message jobEventMessage
+			err := json.Unmarshal(payload, &message)
+			if err != nil {
+				logrus.Errorf("error unmarshaling job event: %v", err)
+				return
+			}
+			s.events.receive(message)
+		})
+		logrus.Errorf("error listening for job events, retrying: %v", err)
+		time.Sleep(10 * time.Second)
+	}
+}
+
+// splitJobEventMessage splits the lines of a message into as many messages
+// as needed to keep each of them smaller than jobqueue.MaxEventSize. Lines
+// which don't fit into a message on their own are cut off. Only the last
+// message is marked as done.
+func splitJobEventMessage(message jobEventMessage) []jobEventMessage {
+	// room for the id, the done flag, and the syntax around them
+	const overhead = 128
+	const maxSize = jobqueue.MaxEventSize - overhead
+
+	var messages []jobEventMessage
+	current := jobEventMessage{JobID: message.JobID}
+	size := 0
+	for _, line := range message.Lines {
+		encoded, _ := json.Marshal(line)
+		if len(encoded)+1 > maxSize {
+			// each byte is escaped to at most six
+			line = line[:maxSize/6]
+			encoded, _ = json.Marshal(line)
+		}
+		if len(current.Lines) > 0 && size+len(encoded)+1 > maxSize {
+			messages = append(messages, current)
+			current = jobEventMessage{JobID: message.JobID}
+			size = 0
+		}
+		current.Lines = append(current.Lines, line)
+		size += len(encoded) + 1
+	}
+	current.Done = message.Done
+	return append(messages, current)
+}
+
+// WatchCompose streams the events of the compose whose status is decided by
+// `rootJob`. The first event is the compose's current status, followed by
+// the lines its running jobs logged so far. The channel is closed after the
+// compose finished, when `ctx` is done, or when the receiver falls too far
+// behind.
+func (s *Server) WatchCompose(ctx context.Context, rootJob uuid.UUID) (<-chan ComposeEvent, error) {
+	jobs, err := s.jobAndDependencies(rootJob)
+	if err != nil {
+		return nil, err
+	}
+	composeJobs := make(map[uuid.UUID]bool, len(jobs))
+	for _, id := range jobs {
+		composeJobs[id] = true
+	}
+
+	// subscribe before getting the status to not miss any transitions
+	events, logs, unsubscribe := s.events.subscribe()
+	status, err := s.ComposeStatus(rootJob)
+	if err != nil {
+		unsubscribe()
+		return nil, err
+	}
+
+	out := make(chan ComposeEvent)
+	go func() {
+		defer close(out)
+		defer unsubscribe()
+
+		send := func(event ComposeEvent) bool {
+			select {
+			case out <- event:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		if !send(ComposeEvent{Status: status}) {
+			return
+		}
+		for _, id := range jobs {
+			if len(logs[id]) > 0 && !send(ComposeEvent{JobID: id, Lines: logs[id]}) {
+				return
+			}
+		}
+
+		for status != notification.StatusSuccess && status != notification.StatusFailure {
+			var event JobEvent
+			var ok bool
+			select {
+			case event, ok = <-events:
+				if !ok {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+
+			if !composeJobs[event.JobID] {
+				continue
+			}
+
+			if len(event.Lines) > 0 {
+				if !send(ComposeEvent{JobID: event.JobID, Lines: event.Lines}) {
+					return
+				}
+				continue
+			}
+
+			newStatus, err := s.ComposeStatus(rootJob)
+			if err != nil {
+				logrus.Errorf("error getting status of compose %s: %v", rootJob, err)
+				return
+			}
+			if newStatus != status {
+				status = newStatus
+				if !send(ComposeEvent{Status: status}) {
+					return
+				}
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+// jobChanged tells subscribers of job events and subscribed composes that a
+// job started, or that it is `done` because it finished or was canceled.
+func (s *Server) jobChanged(id uuid.UUID, done bool) {
+	s.publishJobEvent(jobEventMessage{JobID: id, Done: done})
+	s.notifyComposesOfJob(id)
+}
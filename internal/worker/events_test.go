@@ -0,0 +1,223 @@
+package worker_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/osbuild-composer/internal/distro/test_distro"
+	"github.com/osbuild/osbuild-composer/internal/jobqueue"
+	"github.com/osbuild/osbuild-composer/internal/jobqueue/fsjobqueue"
+	"github.com/osbuild/osbuild-composer/internal/notification"
+	"github.com/osbuild/osbuild-composer/internal/test"
+	"github.com/osbuild/osbuild-composer/internal/worker"
+)
+
+func nextComposeEvent(t *testing.T, events <-chan worker.ComposeEvent) worker.ComposeEvent {
+	select {
+	case event, ok := <-events:
+		require.True(t, ok, "stream of compose events ended")
+		return event
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "no compose event was sent")
+	}
+	return worker.ComposeEvent{}
+}
+
+func TestWatchCompose(t *testing.T) {
+	server := newTestServer(t, t.TempDir(), time.Duration(0), "/api/worker/v1")
+	handler := server.Handler()
+
+	jobID, err := server.EnqueueOSBuild(test_distro.TestArchName, &worker.OSBuildJob{}, "")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := server.WatchCompose(ctx, jobID)
+	require.NoError(t, err)
+	require.Equal(t, worker.ComposeEvent{Status: notification.StatusPending}, nextComposeEvent(t, events))
+
+	_, token, _, _, _, err := server.RequestJob(context.Background(), test_distro.TestArchName, []string{"osbuild"}, []string{""})
+	require.NoError(t, err)
+	require.Equal(t, worker.ComposeEvent{Status: notification.StatusBuilding}, nextComposeEvent(t, events))
+
+	// updates with only log lines don't finish the job
+	path := fmt.Sprintf("/api/worker/v1/jobs/%s", token)
+	test.TestRoute(t, handler, false, "PATCH", path, `{"log":["first line","second line"]}`, http.StatusOK,
+		fmt.Sprintf(`{"href":"%s","id":"%s","kind":"UpdateJobResponse"}`, path, token))
+	require.Equal(t, worker.ComposeEvent{JobID: jobID, Lines: []string{"first line", "second line"}}, nextComposeEvent(t, events))
+
+	// late subscribers get the lines logged so far
+	late, err := server.WatchCompose(ctx, jobID)
+	require.NoError(t, err)
+	require.Equal(t, worker.ComposeEvent{Status: notification.StatusBuilding}, nextComposeEvent(t, late))
+	require.Equal(t, worker.ComposeEvent{JobID: jobID, Lines: []string{"first line", "second line"}}, nextComposeEvent(t, late))
+
+	result, err := json.Marshal(&worker.OSBuildJobResult{Success: true})
+	require.NoError(t, err)
+	test.TestRoute(t, handler, false, "PATCH", path, fmt.Sprintf(`{"result":%s}`, result), http.StatusOK,
+		fmt.Sprintf(`{"href":"%s","id":"%s","kind":"UpdateJobResponse"}`, path, token))
+	require.Equal(t, worker.ComposeEvent{Status: notification.StatusSuccess}, nextComposeEvent(t, events))
+	require.Equal(t, worker.ComposeEvent{Status: notification.StatusSuccess}, nextComposeEvent(t, late))
+
+	// the streams end after the compose finished
+	_, ok := <-events
+	require.False(t, ok)
+	_, ok = <-late
+	require.False(t, ok)
+
+	// the log of the finished job is dropped
+	events, err = server.WatchCompose(ctx, jobID)
+	require.NoError(t, err)
+	require.Equal(t, worker.ComposeEvent{Status: notification.StatusSuccess}, nextComposeEvent(t, events))
+	_, ok = <-events
+	require.False(t, ok)
+
+	// log lines of jobs which aren't running are rejected
+	test.TestRoute(t, handler, false, "PATCH", path, `{"log":["too late"]}`, http.StatusNotFound,
+		`{"kind":"Error","id":"5","code":"IMAGE-BUILDER-WORKER-5","message":"Token not found"}`, "operation_id", "reason", "href")
+}
+
+func TestWatchComposeCanceled(t *testing.T) {
+	server := newTestServer(t, t.TempDir(), time.Duration(0), "/api/worker/v1")
+
+	jobID, err := server.EnqueueOSBuild(test_distro.TestArchName, &worker.OSBuildJob{}, "")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := server.WatchCompose(ctx, jobID)
+	require.NoError(t, err)
+	require.Equal(t, notification.StatusPending, nextComposeEvent(t, events).Status)
+
+	// canceling the context ends the stream
+	cancel()
+	require.Eventually(t, func() bool {
+		_, ok := <-events
+		return !ok
+	}, 5*time.Second, 10*time.Millisecond)
+
+	events, err = server.WatchCompose(context.Background(), jobID)
+	require.NoError(t, err)
+	require.Equal(t, notification.StatusPending, nextComposeEvent(t, events).Status)
+	require.NoError(t, server.Cancel(jobID))
+	require.Equal(t, notification.StatusFailure, nextComposeEvent(t, events).Status)
+
+	_, err = server.WatchCompose(context.Background(), uuid.New())
+	require.Error(t, err)
+}
+
+func TestSubscribeJobEvents(t *testing.T) {
+	server := newTestServer(t, t.TempDir(), time.Duration(0), "/api/worker/v1")
+
+	jobID, err := server.EnqueueOSBuild(test_distro.TestArchName, &worker.OSBuildJob{}, "")
+	require.NoError(t, err)
+	_, token, _, _, _, err := server.RequestJob(context.Background(), test_distro.TestArchName, []string{"osbuild"}, []string{""})
+	require.NoError(t, err)
+	require.NoError(t, server.AppendJobLog(token, []string{"building"}))
+	require.Equal(t, worker.ErrInvalidToken, server.AppendJobLog(uuid.New(), []string{"nope"}))
+
+	events, logs, unsubscribe := server.SubscribeJobEvents()
+	require.Equal(t, map[uuid.UUID][]string{jobID: {"building"}}, logs)
+
+	require.NoError(t, server.AppendJobLog(token, []string{"still building"}))
+	require.Equal(t, worker.JobEvent{JobID: jobID, Lines: []string{"still building"}}, <-events)
+	require.NoError(t, server.FinishJob(token, json.RawMessage("{}")))
+	require.Equal(t, worker.JobEvent{JobID: jobID}, <-events)
+
+	unsubscribe()
+	_, ok := <-events
+	require.False(t, ok)
+	unsubscribe()
+}
+
+// sharedQueue is a job queue which broadcasts events to all servers using
+// it, like a database shared by several composer instances.
+type sharedQueue struct {
+	jobqueue.JobQueue
+
+	mu        sync.Mutex
+	listeners []chan []byte
+}
+
+func (q *sharedQueue) BroadcastEvent(payload []byte) error {
+	if len(payload) >= jobqueue.MaxEventSize {
+		return fmt.Errorf("event is too large: %d bytes", len(payload))
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, listener := range q.listeners {
+		listener <- payload
+	}
+	return nil
+}
+
+func (q *sharedQueue) ListenForEvents(ctx context.Context, handler func(payload []byte)) error {
+	listener := make(chan []byte, 100)
+	q.mu.Lock()
+	q.listeners = append(q.listeners, listener)
+	q.mu.Unlock()
+
+	for {
+		select {
+		case payload := <-listener:
+			handler(payload)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (q *sharedQueue) listening() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.listeners)
+}
+
+func TestSharedJobEvents(t *testing.T) {
+	fsQueue, err := fsjobqueue.New(t.TempDir())
+	require.NoError(t, err)
+	q := &sharedQueue{JobQueue: fsQueue}
+	first := worker.NewServer(nil, q, worker.Config{})
+	second := worker.NewServer(nil, q, worker.Config{})
+	require.Eventually(t, func() bool { return q.listening() == 2 }, 5*time.Second, 10*time.Millisecond)
+
+	jobID, err := first.EnqueueOSBuild(test_distro.TestArchName, &worker.OSBuildJob{}, "")
+	require.NoError(t, err)
+	events, _, unsubscribe := second.SubscribeJobEvents()
+	defer unsubscribe()
+
+	// the worker talks to the first instance, the client to the second
+	_, token, _, _, _, err := first.RequestJob(context.Background(), test_distro.TestArchName, []string{"osbuild"}, []string{""})
+	require.NoError(t, err)
+	require.Equal(t, worker.JobEvent{JobID: jobID}, <-events)
+
+	// lines which don't fit into a single event are split up and cut off
+	half := strings.Repeat("x", jobqueue.MaxEventSize/2)
+	long := strings.Repeat("y", jobqueue.MaxEventSize)
+	require.NoError(t, first.AppendJobLog(token, []string{half, half, long}))
+	event := <-events
+	require.Equal(t, []string{half}, event.Lines)
+	event = <-events
+	require.Len(t, event.Lines, 2)
+	require.Equal(t, half, event.Lines[0])
+	require.Less(t, len(event.Lines[1]), len(long))
+	require.True(t, strings.HasPrefix(long, event.Lines[1]))
+
+	_, logs, unsubscribeLate := second.SubscribeJobEvents()
+	unsubscribeLate()
+	require.Len(t, logs[jobID], 3)
+
+	require.NoError(t, first.FinishJob(token, json.RawMessage("{}")))
+	require.Equal(t, worker.JobEvent{JobID: jobID}, <-events)
+	_, logs, unsubscribeLate = second.SubscribeJobEvents()
+	unsubscribeLate()
+	require.Empty(t, logs)
+}
@@ -3,6 +3,9 @@ package worker
 import (
 	"encoding/json"
 
+	"github.com/google/uuid"
+
+	"github.com/osbuild/osbuild-composer/internal/blueprint"
 	"github.com/osbuild/osbuild-composer/internal/distro"
 	osbuild "github.com/osbuild/osbuild-composer/internal/osbuild2"
 	"github.com/osbuild/osbuild-composer/internal/rpmmd"
@@ -21,6 +24,28 @@ type OSBuildJob struct {
 	StreamOptimized bool             `json:"stream_optimized,omitempty"`
 	Exports         []string         `json:"export_stages,omitempty"`
 	PipelineNames   *PipelineNames   `json:"pipeline_names,omitempty"`
+	// ContentHash identifies the content of the image, see ContentHash().
+	// Only set when the manifest is part of the job. Otherwise, it is
+	// part of the result of the manifest job.
+	ContentHash string `json:"content_hash,omitempty"`
+	// Distro and PackageSpecs describe the image's content for its software
+	// bill of materials. PackageSpecs are the depsolved packages of the
+	// image's payload. Like ContentHash, they are part of the manifest job's
+	// result when the manifest isn't part of the job.
+	Distro       string              `json:"distro,omitempty"`
+	PackageSpecs []rpmmd.PackageSpec `json:"package_specs,omitempty"`
+	// Proxy is the proxy the sources download packages through. It is
+	// passed to osbuild in its environment.
+	Proxy string `json:"proxy,omitempty"`
+	// OSTreeCommit is set when the exported OSTree commit should be signed
+	// or get a static delta after osbuild built it.
+	OSTreeCommit *OSTreeCommitOptions `json:"ostree_commit,omitempty"`
+	// ImageType is the image type of the Cloud API request the job was
+	// enqueued for. Together with Distro, it is used to search composes.
+	ImageType string `json:"image_type,omitempty"`
+	// ImageSize is the size of the image's disk in bytes, which counts
+	// towards the image size quota of the tenant who requested it.
+	ImageSize uint64 `json:"image_size,omitempty"`
 }
 
 type JobResult struct {
@@ -34,6 +59,12 @@ type OSBuildJobResult struct {
 	TargetErrors  []string               `json:"target_errors,omitempty"`
 	UploadStatus  string                 `json:"upload_status"`
 	PipelineNames *PipelineNames         `json:"pipeline_names,omitempty"`
+	// DeduplicatedFrom is set when the image wasn't built, but the
+	// result of a previous job with the same content was reused. It is
+	// the job which built and uploaded the image, also when the previous
+	// job was deduplicated itself, so that all jobs sharing the images
+	// refer to the same one.
+	DeduplicatedFrom *uuid.UUID `json:"deduplicated_from,omitempty"`
 	JobResult
 }
 
@@ -59,6 +90,13 @@ type OSBuildKojiJob struct {
 	KojiServer    string          `json:"koji_server"`
 	KojiDirectory string          `json:"koji_directory"`
 	KojiFilename  string          `json:"koji_filename"`
+	// Distro, ImageType and ImageSize, see OSBuildJob
+	Distro    string `json:"distro,omitempty"`
+	ImageType string `json:"image_type,omitempty"`
+	ImageSize uint64 `json:"image_size,omitempty"`
+	// Proxy and OSTreeCommit, see OSBuildJob
+	Proxy        string               `json:"proxy,omitempty"`
+	OSTreeCommit *OSTreeCommitOptions `json:"ostree_commit,omitempty"`
 }
 
 type OSBuildKojiJobResult struct {
@@ -68,10 +106,35 @@ type OSBuildKojiJobResult struct {
 	PipelineNames *PipelineNames  `json:"pipeline_names,omitempty"`
 	ImageHash     string          `json:"image_hash"`
 	ImageSize     uint64          `json:"image_size"`
-	KojiError     string          `json:"koji_error"`
+	// Distro and ImageType of the image, copied from the job, so that the
+	// koji-finalize job can describe the image
+	Distro    string `json:"distro,omitempty"`
+	ImageType string `json:"image_type,omitempty"`
+	// ExtraOutputs are the files which were uploaded along with the image,
+	// like its manifest or osbuild's log
+	ExtraOutputs []KojiExtraOutput `json:"extra_outputs,omitempty"`
+	KojiError    string            `json:"koji_error"`
 	JobResult
 }
 
+// Kinds of files which osbuild-koji jobs upload in addition to the image
+const (
+	KojiOutputManifest = "manifest"
+	KojiOutputSBOM     = "sbom"
+	KojiOutputLog      = "log"
+	KojiOutputPackages = "packages"
+)
+
+// KojiExtraOutput is a file which was uploaded to the koji directory of the
+// build in addition to an image, and is imported as an output of the build.
+type KojiExtraOutput struct {
+	// Kind is one of the KojiOutput* constants
+	Kind     string `json:"kind"`
+	Filename string `json:"filename"`
+	Size     uint64 `json:"size"`
+	MD5      string `json:"md5"`
+}
+
 type KojiFinalizeJob struct {
 	Server        string   `json:"server"`
 	Name          string   `json:"name"`
@@ -81,10 +144,48 @@ type KojiFinalizeJob struct {
 	KojiDirectory string   `json:"koji_directory"`
 	TaskID        uint64   `json:"task_id"` /* https://pagure.io/koji/issue/215 */
 	StartTime     uint64   `json:"start_time"`
+	// Attempt counts how often the compose was retried before this run
+	Attempt int `json:"attempt,omitempty"`
 }
 
+// KojiFinalizeAttempts is how often a koji compose whose images couldn't be
+// uploaded or imported is run, before its koji build is failed.
+const KojiFinalizeAttempts = 3
+
 type KojiFinalizeJobResult struct {
 	KojiError string `json:"koji_error"`
+	// Failure is the step at which a failed koji build failed
+	Failure KojiFailure `json:"failure,omitempty"`
+	// Retryable is set when the koji build was left open, so that the
+	// compose can be retried with Server.RetryKojiCompose()
+	Retryable bool `json:"retryable,omitempty"`
+	JobResult
+}
+
+// KojiFailure tells whether the images of a failed koji build have to be
+// built again, or whether only uploading or importing them failed.
+type KojiFailure string
+
+const (
+	// The build couldn't be initialized or an image couldn't be built.
+	// The koji build is failed.
+	KojiFailureBuild KojiFailure = "build"
+	// The images were built, but uploading one of them to koji failed.
+	// The koji build is left open, unless this was the last attempt.
+	KojiFailureUpload KojiFailure = "upload"
+	// The images were built and uploaded, but importing them into koji
+	// failed. The koji build is left open, unless this was the last
+	// attempt.
+	KojiFailureImport KojiFailure = "import"
+)
+
+// ImageCleanupJob removes the images which were uploaded for a compose when
+// the compose is deleted.
+type ImageCleanupJob struct {
+	TargetResults []*target.TargetResult `json:"target_results"`
+}
+
+type ImageCleanupJobResult struct {
 	JobResult
 }
 
@@ -114,6 +215,9 @@ type DepsolveJob struct {
 	Arch             string                        `json:"arch"`
 	Releasever       string                        `json:"releasever"`
 	PackageSetsRepos map[string][]rpmmd.RepoConfig `json:"package_sets_repositories,omitempty"`
+	// Explain makes the resulting package specs record why each package
+	// was pulled in
+	Explain bool `json:"explain,omitempty"`
 }
 
 type ErrorType string
@@ -130,11 +234,42 @@ type DepsolveJobResult struct {
 	JobResult
 }
 
-type ManifestJobByID struct{}
+// UpdateInfoJob fetches the update advisories published in the updateinfo
+// metadata of the given repositories.
+type UpdateInfoJob struct {
+	Repos      []rpmmd.RepoConfig `json:"repos"`
+	Arch       string             `json:"arch"`
+	Releasever string             `json:"releasever"`
+}
+
+type UpdateInfoJobResult struct {
+	Advisories []rpmmd.Advisory `json:"advisories"`
+	JobResult
+}
+
+// RepositoryHealthJob probes the given repositories and reports problems
+// with each of them.
+type RepositoryHealthJob struct {
+	Repos      []rpmmd.RepoConfig `json:"repos"`
+	Arch       string             `json:"arch"`
+	Releasever string             `json:"releasever"`
+}
+
+type RepositoryHealthJobResult struct {
+	Health []rpmmd.RepoHealth `json:"health"`
+	JobResult
+}
+
+type ManifestJobByID struct {
+	// Blueprint the manifest is generated from, for reference
+	Blueprint *blueprint.Blueprint `json:"blueprint,omitempty"`
+}
 
 type ManifestJobByIDResult struct {
-	Manifest distro.Manifest `json:"data,omitempty"`
-	Error    string          `json:"error"`
+	Manifest     distro.Manifest     `json:"data,omitempty"`
+	ContentHash  string              `json:"content_hash,omitempty"`
+	PackageSpecs []rpmmd.PackageSpec `json:"package_specs,omitempty"`
+	Error        string              `json:"error"`
 	JobResult
 }
 
@@ -143,7 +278,8 @@ type ManifestJobByIDResult struct {
 //
 
 type updateJobRequest struct {
-	Result interface{} `json:"result"`
+	Result interface{} `json:"result,omitempty"`
+	Log    []string    `json:"log,omitempty"`
 }
 
 func (j *OSBuildJob) UnmarshalJSON(data []byte) error {
@@ -0,0 +1,202 @@
+package worker
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/osbuild/osbuild-composer/internal/notification"
+)
+
+var ErrNotificationsDisabled = errors.New("notifications are not enabled")
+
+// NotificationsEnabled returns true if composes can be subscribed to
+// notifications.
+func (s *Server) NotificationsEnabled() bool {
+	return s.config.Notifier != nil
+}
+
+// SubscribeCompose subscribes the compose with id `composeID` to
+// notifications about its status, which are sent to `webhooks` and the
+// global webhooks. The status of the compose is decided by `rootJob` and the
+// jobs it depends on. The first notification, usually "pending", is sent
+// right away.
+func (s *Server) SubscribeCompose(composeID, rootJob uuid.UUID, webhooks []notification.Webhook) error {
+	if s.config.Notifier == nil {
+		if len(webhooks) > 0 {
+			return ErrNotificationsDisabled
+		}
+		return nil
+	}
+
+	for _, w := range webhooks {
+		if err := w.Validate(); err != nil {
+			return err
+		}
+	}
+	if len(webhooks) == 0 && len(s.config.Notifier.Webhooks()) == 0 {
+		return nil
+	}
+
+	jobs, err := s.jobAndDependencies(rootJob)
+	if err != nil {
+		return err
+	}
+	channel, err := s.JobChannel(rootJob)
+	if err != nil {
+		return err
+	}
+
+	subscription := notification.Subscription{
+		ComposeID: composeID,
+		Channel:   channel,
+		RootJob:   rootJob,
+		Jobs:      jobs,
+		Webhooks:  webhooks,
+	}
+	err = s.config.Notifier.Subscribe(subscription)
+	if err != nil {
+		return err
+	}
+
+	s.notifyCompose(&subscription)
+	return nil
+}
+
+// UnsubscribeCompose removes the subscription of a compose, if there is one,
+// together with its deliveries. `channel` is the channel the compose was
+// enqueued on. Events which haven't been sent yet are dropped.
+func (s *Server) UnsubscribeCompose(composeID uuid.UUID, channel string) error {
+	if s.config.Notifier == nil {
+		return nil
+	}
+
+	_, err := s.composeSubscription(composeID, channel)
+	if err == notification.ErrNotSubscribed {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	return s.config.Notifier.Unsubscribe(composeID)
+}
+
+// ComposeDeliveries returns the log of the notifications sent about a
+// compose, which was enqueued on `channel`. It returns
+// notification.ErrNotSubscribed if the compose isn't subscribed.
+func (s *Server) ComposeDeliveries(composeID uuid.UUID, channel string) ([]notification.Delivery, error) {
+	if s.config.Notifier == nil {
+		return nil, notification.ErrNotSubscribed
+	}
+
+	_, err := s.composeSubscription(composeID, channel)
+	if err != nil {
+		return nil, err
+	}
+	deliveries, err := s.config.Notifier.Deliveries(composeID)
+	if err != nil {
+		return nil, err
+	}
+	if deliveries == nil {
+		deliveries = []notification.Delivery{}
+	}
+	return deliveries, nil
+}
+
+// composeSubscription returns the subscription of a compose which was
+// enqueued on `channel`. Subscriptions of composes on other channels are
+// treated as if they didn't exist.
+func (s *Server) composeSubscription(composeID uuid.UUID, channel string) (*notification.Subscription, error) {
+	subscription, err := s.config.Notifier.Subscription(composeID)
+	if err != nil {
+		return nil, err
+	}
+	if subscription.Channel != channel {
+		return nil, notification.ErrNotSubscribed
+	}
+	return subscription, nil
+}
+
+// notifyComposesOfJob notifies all subscribed composes which `job` is part
+// of about their status. See jobChanged().
+func (s *Server) notifyComposesOfJob(job uuid.UUID) {
+	if s.config.Notifier == nil {
+		return
+	}
+
+	subscriptions, err := s.config.Notifier.SubscriptionsOfJob(job)
+	if err != nil {
+		logrus.Errorf("error listing subscriptions for job %s: %v", job, err)
+		return
+	}
+	for i := range subscriptions {
+		s.notifyCompose(&subscriptions[i])
+	}
+}
+
+// notifyCompose adds an event about the status of a subscribed compose,
+// unless its status hasn't changed since the last event. The notifier sends
+// it in the background.
+func (s *Server) notifyCompose(subscription *notification.Subscription) {
+	status, err := s.ComposeStatus(subscription.RootJob)
+	if err != nil {
+		logrus.Errorf("error getting status of compose %s for notifications: %v", subscription.ComposeID, err)
+		return
+	}
+
+	err = s.config.Notifier.Notify(subscription, status)
+	if err != nil && err != notification.ErrNotSubscribed {
+		logrus.Errorf("error notifying about compose %s: %v", subscription.ComposeID, err)
+	}
+}
+
+// ComposeStatus returns the status of the compose whose status is decided
+// by `rootJob`, as it is reported in notifications and event streams.
+// Workers report failed dependencies in the result of the root job, so it
+// always finishes. Koji composes are uploading while their koji-finalize job
+// imports the builds. Other composes upload their images while they're
+// building, so they're never uploading.
+func (s *Server) ComposeStatus(rootJob uuid.UUID) (notification.Status, error) {
+	jobType, rawResult, _, started, finished, canceled, deps, err := s.jobs.JobStatus(rootJob)
+	if err != nil {
+		return "", err
+	}
+
+	if canceled {
+		return notification.StatusFailure, nil
+	}
+
+	if !finished.IsZero() {
+		var result struct {
+			JobResult
+			Success *bool `json:"success"`
+		}
+		err := json.Unmarshal(rawResult, &result)
+		if err != nil || result.JobError != nil || (result.Success != nil && !*result.Success) {
+			return notification.StatusFailure, nil
+		}
+		return notification.StatusSuccess, nil
+	}
+
+	if !started.IsZero() {
+		if jobType == "koji-finalize" {
+			return notification.StatusUploading, nil
+		}
+		return notification.StatusBuilding, nil
+	}
+
+	// koji composes are building while any of their builds is running
+	for _, dep := range deps {
+		depType, _, _, depStarted, _, _, _, err := s.jobs.JobStatus(dep)
+		if err != nil {
+			return "", err
+		}
+		if strings.HasPrefix(depType, "osbuild-koji:") && !depStarted.IsZero() {
+			return notification.StatusBuilding, nil
+		}
+	}
+
+	return notification.StatusPending, nil
+}
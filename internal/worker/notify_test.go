@@ -0,0 +1,207 @@
+package worker_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/osbuild-composer/internal/distro/test_distro"
+	"github.com/osbuild/osbuild-composer/internal/jobqueue/fsjobqueue"
+	"github.com/osbuild/osbuild-composer/internal/notification"
+	"github.com/osbuild/osbuild-composer/internal/notification/fsstore"
+	"github.com/osbuild/osbuild-composer/internal/worker"
+)
+
+// newNotifier returns a notifier which keeps its state in `dir`.
+func newNotifier(t *testing.T, dir string) *notification.Notifier {
+	store, err := fsstore.New(dir)
+	require.NoError(t, err)
+	notifier, err := notification.New(notification.Config{
+		Client:       &http.Client{},
+		PollInterval: 10 * time.Millisecond,
+	}, store)
+	require.NoError(t, err)
+	t.Cleanup(notifier.Close)
+	return notifier
+}
+
+// webhookEvents starts a webhook which records the statuses it receives for
+// each compose.
+func webhookEvents(t *testing.T) (*httptest.Server, func(uuid.UUID) []notification.Status) {
+	var mu sync.Mutex
+	statuses := make(map[uuid.UUID][]notification.Status)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event notification.Event
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&event))
+
+		mu.Lock()
+		defer mu.Unlock()
+		statuses[event.ComposeID] = append(statuses[event.ComposeID], event.Status)
+	}))
+
+	return server, func(composeID uuid.UUID) []notification.Status {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]notification.Status{}, statuses[composeID]...)
+	}
+}
+
+func requireStatuses(t *testing.T, events func(uuid.UUID) []notification.Status, composeID uuid.UUID, expected ...notification.Status) {
+	require.Eventually(t, func() bool {
+		return len(events(composeID)) >= len(expected)
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, expected, events(composeID))
+}
+
+func TestComposeNotifications(t *testing.T) {
+	webhook, events := webhookEvents(t)
+	defer webhook.Close()
+
+	q, err := fsjobqueue.New(t.TempDir())
+	require.NoError(t, err)
+	server := worker.NewServer(nil, q, worker.Config{Notifier: newNotifier(t, t.TempDir())})
+
+	webhooks := []notification.Webhook{{URL: webhook.URL}}
+
+	// a successful compose, with an id that differs from its job's
+	jobID, err := server.EnqueueOSBuild(test_distro.TestArchName, &worker.OSBuildJob{}, "")
+	require.NoError(t, err)
+	composeID := uuid.New()
+	require.NoError(t, server.SubscribeCompose(composeID, jobID, webhooks))
+	requireStatuses(t, events, composeID, notification.StatusPending)
+
+	_, token, _, _, _, err := server.RequestJob(context.Background(), test_distro.TestArchName, []string{"osbuild"}, []string{""})
+	require.NoError(t, err)
+	requireStatuses(t, events, composeID, notification.StatusPending, notification.StatusBuilding)
+
+	result, err := json.Marshal(&worker.OSBuildJobResult{Success: true})
+	require.NoError(t, err)
+	require.NoError(t, server.FinishJob(token, result))
+	requireStatuses(t, events, composeID, notification.StatusPending, notification.StatusBuilding, notification.StatusSuccess)
+
+	require.Eventually(t, func() bool {
+		deliveries, err := server.ComposeDeliveries(composeID, "")
+		require.NoError(t, err)
+		return len(deliveries) == 3
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// a failed compose
+	jobID, err = server.EnqueueOSBuild(test_distro.TestArchName, &worker.OSBuildJob{}, "")
+	require.NoError(t, err)
+	require.NoError(t, server.SubscribeCompose(jobID, jobID, webhooks))
+	_, token, _, _, _, err = server.RequestJob(context.Background(), test_distro.TestArchName, []string{"osbuild"}, []string{""})
+	require.NoError(t, err)
+	result, err = json.Marshal(&worker.OSBuildJobResult{Success: false})
+	require.NoError(t, err)
+	require.NoError(t, server.FinishJob(token, result))
+	requireStatuses(t, events, jobID, notification.StatusPending, notification.StatusBuilding, notification.StatusFailure)
+
+	// a canceled compose
+	jobID, err = server.EnqueueOSBuild(test_distro.TestArchName, &worker.OSBuildJob{}, "")
+	require.NoError(t, err)
+	require.NoError(t, server.SubscribeCompose(jobID, jobID, webhooks))
+	require.NoError(t, server.Cancel(jobID))
+	requireStatuses(t, events, jobID, notification.StatusPending, notification.StatusFailure)
+
+	require.NoError(t, server.UnsubscribeCompose(jobID, ""))
+	_, err = server.ComposeDeliveries(jobID, "")
+	require.Equal(t, notification.ErrNotSubscribed, err)
+}
+
+func TestComposeNotificationsRestart(t *testing.T) {
+	webhook, events := webhookEvents(t)
+	defer webhook.Close()
+
+	q, err := fsjobqueue.New(t.TempDir())
+	require.NoError(t, err)
+	dir := t.TempDir()
+	notifier := newNotifier(t, dir)
+	server := worker.NewServer(nil, q, worker.Config{Notifier: notifier})
+
+	jobID, err := server.EnqueueOSBuild(test_distro.TestArchName, &worker.OSBuildJob{}, "org-42")
+	require.NoError(t, err)
+	require.NoError(t, server.SubscribeCompose(jobID, jobID, []notification.Webhook{{URL: webhook.URL}}))
+	require.Equal(t, notification.ErrAlreadySubscribed, server.SubscribeCompose(jobID, jobID, []notification.Webhook{{URL: webhook.URL}}))
+	requireStatuses(t, events, jobID, notification.StatusPending)
+	require.Eventually(t, func() bool {
+		deliveries, err := server.ComposeDeliveries(jobID, "org-42")
+		require.NoError(t, err)
+		return len(deliveries) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// other tenants don't see the subscription
+	_, err = server.ComposeDeliveries(jobID, "org-123")
+	require.Equal(t, notification.ErrNotSubscribed, err)
+	require.NoError(t, server.UnsubscribeCompose(jobID, "org-123"))
+
+	// subscriptions and deliveries are kept in the notification store, so
+	// that another instance continues where the first one left off
+	notifier.Close()
+	server = worker.NewServer(nil, q, worker.Config{Notifier: newNotifier(t, dir)})
+
+	deliveries, err := server.ComposeDeliveries(jobID, "org-42")
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	require.Equal(t, notification.StatusPending, deliveries[0].Status)
+
+	_, _, _, _, _, err = server.RequestJob(context.Background(), test_distro.TestArchName, []string{"osbuild"}, []string{"org-42"})
+	require.NoError(t, err)
+	requireStatuses(t, events, jobID, notification.StatusPending, notification.StatusBuilding)
+	require.Eventually(t, func() bool {
+		deliveries, err = server.ComposeDeliveries(jobID, "org-42")
+		require.NoError(t, err)
+		return len(deliveries) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// unsubscribing removes the deliveries as well
+	require.NoError(t, server.UnsubscribeCompose(jobID, "org-42"))
+	_, err = server.ComposeDeliveries(jobID, "org-42")
+	require.Equal(t, notification.ErrNotSubscribed, err)
+}
+
+func TestKojiComposeNotifications(t *testing.T) {
+	webhook, events := webhookEvents(t)
+	defer webhook.Close()
+
+	q, err := fsjobqueue.New(t.TempDir())
+	require.NoError(t, err)
+	server := worker.NewServer(nil, q, worker.Config{Notifier: newNotifier(t, t.TempDir())})
+
+	initID, err := server.EnqueueKojiInit(&worker.KojiInitJob{}, "")
+	require.NoError(t, err)
+	buildID, err := server.EnqueueOSBuildKoji(test_distro.TestArchName, &worker.OSBuildKojiJob{}, initID, "")
+	require.NoError(t, err)
+	finalizeID, err := server.EnqueueKojiFinalize(&worker.KojiFinalizeJob{}, initID, []uuid.UUID{buildID}, "")
+	require.NoError(t, err)
+	require.NoError(t, server.SubscribeCompose(finalizeID, finalizeID, []notification.Webhook{{URL: webhook.URL}}))
+
+	for _, jobType := range []string{"koji-init", "osbuild-koji", "koji-finalize"} {
+		_, token, _, _, _, err := server.RequestJob(context.Background(), test_distro.TestArchName, []string{jobType}, []string{""})
+		require.NoError(t, err)
+		require.NoError(t, server.FinishJob(token, json.RawMessage("{}")))
+	}
+
+	requireStatuses(t, events, finalizeID,
+		notification.StatusPending,
+		notification.StatusBuilding,
+		notification.StatusUploading,
+		notification.StatusSuccess)
+}
+
+func TestNotificationsDisabled(t *testing.T) {
+	server := newTestServer(t, t.TempDir(), time.Duration(0), "/api/worker/v1")
+	require.False(t, server.NotificationsEnabled())
+
+	jobID, err := server.EnqueueOSBuild(test_distro.TestArchName, &worker.OSBuildJob{}, "")
+	require.NoError(t, err)
+	require.NoError(t, server.SubscribeCompose(jobID, jobID, nil))
+	require.Equal(t, worker.ErrNotificationsDisabled, server.SubscribeCompose(jobID, jobID, []notification.Webhook{{URL: "https://example.com"}}))
+}
@@ -0,0 +1,63 @@
+package worker
+
+import (
+	"fmt"
+
+	"github.com/osbuild/osbuild-composer/internal/distro"
+	"github.com/osbuild/osbuild-composer/internal/ostree"
+)
+
+// ostreeCommitArchive is the filename of the image types which export an
+// OSTree commit as a tarball of its repository.
+const ostreeCommitArchive = "commit.tar"
+
+// OSTreeCommitOptions tell the worker how to post-process the OSTree commit
+// archive exported by an osbuild job: whether to sign the commit with the
+// worker's key and whether to generate a static delta from its parent.
+type OSTreeCommitOptions struct {
+	// Filename of the archive in the export
+	Filename string `json:"filename"`
+	Ref      string `json:"ref"`
+	// Parent is the commit the static delta is generated from, and URL
+	// the repository it is pulled from. If GPGKey is set, the parent must
+	// be signed with it. If RHSM is set, the worker pulls it with the
+	// consumer certificate of its host.
+	Parent      string `json:"parent,omitempty"`
+	URL         string `json:"url,omitempty"`
+	GPGKey      string `json:"gpg_key,omitempty"`
+	RHSM        bool   `json:"rhsm,omitempty"`
+	Sign        bool   `json:"sign,omitempty"`
+	StaticDelta bool   `json:"static_delta,omitempty"`
+}
+
+// NewOSTreeCommitOptions returns the post-processing options for an image of
+// `imageType` built with the resolved `params`, or nil when they don't ask for
+// any. Signing and static deltas are only supported for image types which
+// export a commit archive.
+func NewOSTreeCommitOptions(imageType distro.ImageType, params ostree.RequestParams) (*OSTreeCommitOptions, error) {
+	if !params.Sign && !params.StaticDelta {
+		return nil, nil
+	}
+
+	if !ProducesOSTreeCommit(imageType) {
+		return nil, fmt.Errorf("image type %s does not produce an ostree commit that can be signed or have a static delta", imageType.Name())
+	}
+
+	return &OSTreeCommitOptions{
+		Filename:    imageType.Filename(),
+		Ref:         params.Ref,
+		Parent:      params.Parent,
+		URL:         params.URL,
+		GPGKey:      params.GPGKey,
+		RHSM:        params.RHSM,
+		Sign:        params.Sign,
+		StaticDelta: params.StaticDelta,
+	}, nil
+}
+
+// ProducesOSTreeCommit returns whether images of `imageType` are an archive of
+// an OSTree repository containing the commit, which the worker can
+// post-process and publish.
+func ProducesOSTreeCommit(imageType distro.ImageType) bool {
+	return imageType.Filename() == ostreeCommitArchive
+}
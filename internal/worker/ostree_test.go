@@ -0,0 +1,46 @@
+package worker_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/osbuild-composer/internal/distro/rhel86"
+	"github.com/osbuild/osbuild-composer/internal/ostree"
+	"github.com/osbuild/osbuild-composer/internal/worker"
+)
+
+func TestNewOSTreeCommitOptions(t *testing.T) {
+	arch, err := rhel86.New().GetArch("x86_64")
+	require.NoError(t, err)
+	commit, err := arch.GetImageType("edge-commit")
+	require.NoError(t, err)
+	installer, err := arch.GetImageType("edge-installer")
+	require.NoError(t, err)
+
+	params := ostree.RequestParams{
+		URL:         "https://example.com/repo",
+		Ref:         "rhel/8/x86_64/edge",
+		Parent:      "02604b2da6e954bd34b8b82a835e5a77d2b60ffa",
+		Sign:        true,
+		StaticDelta: true,
+	}
+
+	options, err := worker.NewOSTreeCommitOptions(commit, params)
+	require.NoError(t, err)
+	require.Equal(t, &worker.OSTreeCommitOptions{
+		Filename:    "commit.tar",
+		Ref:         "rhel/8/x86_64/edge",
+		Parent:      "02604b2da6e954bd34b8b82a835e5a77d2b60ffa",
+		URL:         "https://example.com/repo",
+		Sign:        true,
+		StaticDelta: true,
+	}, options)
+
+	_, err = worker.NewOSTreeCommitOptions(installer, params)
+	require.Error(t, err)
+
+	options, err = worker.NewOSTreeCommitOptions(installer, ostree.RequestParams{Ref: "rhel/8/x86_64/edge"})
+	require.NoError(t, err)
+	require.Nil(t, options)
+}
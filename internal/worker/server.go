@@ -13,6 +13,7 @@ import (
 	"path"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -23,6 +24,7 @@ import (
 	"github.com/osbuild/osbuild-composer/internal/auth"
 	"github.com/osbuild/osbuild-composer/internal/common"
 	"github.com/osbuild/osbuild-composer/internal/jobqueue"
+	"github.com/osbuild/osbuild-composer/internal/notification"
 	"github.com/osbuild/osbuild-composer/internal/prometheus"
 	"github.com/osbuild/osbuild-composer/internal/worker/api"
 	"github.com/osbuild/osbuild-composer/internal/worker/clienterrors"
@@ -32,6 +34,19 @@ type Server struct {
 	jobs   jobqueue.JobQueue
 	logger *log.Logger
 	config Config
+
+	events *jobEvents
+
+	// the channels locked by this process, see LockChannel()
+	channelLocksMu sync.Mutex
+	channelLocks   map[string]*channelLock
+}
+
+// channelLock is a mutex which can be waited for with a context. `users`
+// counts the goroutines holding or waiting for it.
+type channelLock struct {
+	held  chan struct{}
+	users int
 }
 
 type JobStatus struct {
@@ -44,6 +59,8 @@ type JobStatus struct {
 var ErrInvalidToken = errors.New("token does not exist")
 var ErrJobNotRunning = errors.New("job isn't running")
 var ErrInvalidJobType = errors.New("job has invalid type")
+var ErrJobNotFinished = errors.New("job hasn't finished")
+var ErrComposeNotRetryable = errors.New("compose can't be retried")
 
 type Config struct {
 	ArtifactsDir         string
@@ -51,6 +68,10 @@ type Config struct {
 	BasePath             string
 	JWTEnabled           bool
 	TenantProviderFields []string
+	DeduplicateComposes  bool
+	// Notifier sends webhooks about the status of subscribed composes.
+	// Composes can't be subscribed if it is nil. See notify.go.
+	Notifier *notification.Notifier
 }
 
 func NewServer(logger *log.Logger, jobs jobqueue.JobQueue, config Config) *Server {
@@ -58,11 +79,17 @@ func NewServer(logger *log.Logger, jobs jobqueue.JobQueue, config Config) *Serve
 		jobs:   jobs,
 		logger: logger,
 		config: config,
+		events: newJobEvents(),
+
+		channelLocks: make(map[string]*channelLock),
 	}
 
 	api.BasePath = config.BasePath
 
 	go s.WatchHeartbeats()
+	if broadcaster, ok := jobs.(jobqueue.EventBroadcaster); ok {
+		go s.listenForJobEvents(broadcaster)
+	}
 	return s
 }
 
@@ -128,10 +155,22 @@ func (s *Server) EnqueueDepsolve(job *DepsolveJob, channel string) (uuid.UUID, e
 	return s.enqueue("depsolve", job, nil, channel)
 }
 
+func (s *Server) EnqueueUpdateInfo(job *UpdateInfoJob, channel string) (uuid.UUID, error) {
+	return s.enqueue("updateinfo", job, nil, channel)
+}
+
+func (s *Server) EnqueueRepositoryHealth(job *RepositoryHealthJob, channel string) (uuid.UUID, error) {
+	return s.enqueue("repository-health", job, nil, channel)
+}
+
 func (s *Server) EnqueueManifestJobByID(job *ManifestJobByID, parent uuid.UUID, channel string) (uuid.UUID, error) {
 	return s.enqueue("manifest-id-only", job, []uuid.UUID{parent}, channel)
 }
 
+func (s *Server) EnqueueImageCleanup(job *ImageCleanupJob, channel string) (uuid.UUID, error) {
+	return s.enqueue("image-cleanup", job, nil, channel)
+}
+
 func (s *Server) enqueue(jobType string, job interface{}, dependencies []uuid.UUID, channel string) (uuid.UUID, error) {
 	prometheus.EnqueueJobMetrics(jobType)
 	return s.jobs.Enqueue(jobType, job, dependencies, channel)
@@ -243,6 +282,32 @@ func (s *Server) DepsolveJobStatus(id uuid.UUID, result *DepsolveJobResult) (*Jo
 	return status, deps, nil
 }
 
+func (s *Server) UpdateInfoJobStatus(id uuid.UUID, result *UpdateInfoJobResult) (*JobStatus, []uuid.UUID, error) {
+	jobType, status, deps, err := s.jobStatus(id, result)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if jobType != "updateinfo" {
+		return nil, nil, fmt.Errorf("expected \"updateinfo\", found %q job instead", jobType)
+	}
+
+	return status, deps, nil
+}
+
+func (s *Server) RepositoryHealthJobStatus(id uuid.UUID, result *RepositoryHealthJobResult) (*JobStatus, []uuid.UUID, error) {
+	jobType, status, deps, err := s.jobStatus(id, result)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if jobType != "repository-health" {
+		return nil, nil, fmt.Errorf("expected \"repository-health\", found %q job instead", jobType)
+	}
+
+	return status, deps, nil
+}
+
 func (s *Server) ManifestByIdJobStatus(id uuid.UUID, result *ManifestJobByIDResult) (*JobStatus, []uuid.UUID, error) {
 	jobType, status, deps, err := s.jobStatus(id, result)
 	if err != nil {
@@ -266,8 +331,8 @@ func (s *Server) ManifestJobStatus(id uuid.UUID, result *ManifestJobByIDResult)
 		return nil, nil, err
 	}
 
-	if jobType != "manifest-job-by-id" {
-		return nil, nil, fmt.Errorf("expected \"manifest-job-by-id\", found %q job instead", jobType)
+	if jobType != "manifest-id-only" {
+		return nil, nil, fmt.Errorf("expected \"manifest-id-only\", found %q job instead", jobType)
 	}
 
 	return status, deps, nil
@@ -312,6 +377,42 @@ func (s *Server) OSBuildJob(id uuid.UUID, job *OSBuildJob) error {
 	return nil
 }
 
+// ManifestJobByID returns the parameters of a ManifestJobByID
+func (s *Server) ManifestJobByID(id uuid.UUID, job *ManifestJobByID) error {
+	jobType, rawArgs, _, _, err := s.jobs.Job(id)
+	if err != nil {
+		return err
+	}
+
+	if jobType != "manifest-id-only" {
+		return fmt.Errorf("expected \"manifest-id-only\", found %q job instead for job '%s'", jobType, id)
+	}
+
+	if err := json.Unmarshal(rawArgs, job); err != nil {
+		return fmt.Errorf("error unmarshaling arguments for job '%s': %v", id, err)
+	}
+
+	return nil
+}
+
+// DepsolveJob returns the parameters of a DepsolveJob
+func (s *Server) DepsolveJob(id uuid.UUID, job *DepsolveJob) error {
+	jobType, rawArgs, _, _, err := s.jobs.Job(id)
+	if err != nil {
+		return err
+	}
+
+	if jobType != "depsolve" {
+		return fmt.Errorf("expected \"depsolve\", found %q job instead for job '%s'", jobType, id)
+	}
+
+	if err := json.Unmarshal(rawArgs, job); err != nil {
+		return fmt.Errorf("error unmarshaling arguments for job '%s': %v", id, err)
+	}
+
+	return nil
+}
+
 // OSBuildKojiJob returns the parameters of an OSBuildKojiJob
 func (s *Server) OSBuildKojiJob(id uuid.UUID, job *OSBuildKojiJob) error {
 	jobType, rawArgs, _, _, err := s.jobs.Job(id)
@@ -330,6 +431,24 @@ func (s *Server) OSBuildKojiJob(id uuid.UUID, job *OSBuildKojiJob) error {
 	return nil
 }
 
+// KojiFinalizeJob returns the parameters of a KojiFinalizeJob
+func (s *Server) KojiFinalizeJob(id uuid.UUID, job *KojiFinalizeJob) error {
+	jobType, rawArgs, _, _, err := s.jobs.Job(id)
+	if err != nil {
+		return err
+	}
+
+	if jobType != "koji-finalize" {
+		return fmt.Errorf("expected \"koji-finalize\", found %q job instead for job '%s'", jobType, id)
+	}
+
+	if err := json.Unmarshal(rawArgs, job); err != nil {
+		return fmt.Errorf("error unmarshaling arguments for job '%s': %v", id, err)
+	}
+
+	return nil
+}
+
 // JobType returns the type of the job
 func (s *Server) JobType(id uuid.UUID) (string, error) {
 	jobType, _, _, _, err := s.jobs.Job(id)
@@ -338,6 +457,99 @@ func (s *Server) JobType(id uuid.UUID) (string, error) {
 	return strings.Split(jobType, ":")[0], err
 }
 
+// JobStatus returns the status of the job with the given id, regardless of
+// its type.
+func (s *Server) JobStatus(id uuid.UUID) (*JobStatus, error) {
+	_, status, _, err := s.jobStatus(id, nil)
+	return status, err
+}
+
+// WaitForJob blocks until the job with the given id finished or was canceled,
+// or until ctx is done. The job queue is polled, so that this also works when
+// another composer instance receives the job's result.
+func (s *Server) WaitForJob(ctx context.Context, id uuid.UUID) (*JobStatus, error) {
+	for {
+		status, err := s.JobStatus(id)
+		if err != nil {
+			return nil, err
+		}
+		if !status.Finished.IsZero() || status.Canceled {
+			return status, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
+
+// JobsByChannel returns the ids of the jobs of the given types which were
+// enqueued on `channel`, most recently queued first. See
+// jobqueue.JobsByChannel().
+func (s *Server) JobsByChannel(channel string, jobTypes []string, queuedAfter, queuedBefore time.Time) ([]uuid.UUID, error) {
+	return s.jobs.JobsByChannel(channel, jobTypes, queuedAfter, queuedBefore)
+}
+
+// UnfinishedJobsByChannel returns the ids of the jobs of the given types
+// which were enqueued on `channel` and have neither finished nor been
+// canceled, most recently queued first.
+func (s *Server) UnfinishedJobsByChannel(channel string, jobTypes []string) ([]uuid.UUID, error) {
+	return s.jobs.UnfinishedJobsByChannel(channel, jobTypes)
+}
+
+// LockChannel blocks until no one else holds the lock of `channel` and takes
+// it, or until `ctx` is canceled. It serializes changes to the channel which
+// depend on its state, such as checking a tenant's quotas and enqueuing a
+// compose. The lock is shared with other composer instances if the job queue
+// supports it. The returned function releases the lock.
+func (s *Server) LockChannel(ctx context.Context, channel string) (func(), error) {
+	s.channelLocksMu.Lock()
+	lock, ok := s.channelLocks[channel]
+	if !ok {
+		lock = &channelLock{held: make(chan struct{}, 1)}
+		s.channelLocks[channel] = lock
+	}
+	lock.users++
+	s.channelLocksMu.Unlock()
+
+	release := func() {
+		s.channelLocksMu.Lock()
+		lock.users--
+		if lock.users == 0 {
+			delete(s.channelLocks, channel)
+		}
+		s.channelLocksMu.Unlock()
+	}
+
+	// take the lock of this process first, so that only one connection
+	// per channel waits for the job queue's lock
+	select {
+	case lock.held <- struct{}{}:
+	case <-ctx.Done():
+		release()
+		return nil, ctx.Err()
+	}
+
+	unlockQueue := func() {}
+	if locker, ok := s.jobs.(jobqueue.ChannelLocker); ok {
+		var err error
+		unlockQueue, err = locker.LockChannel(ctx, channel)
+		if err != nil {
+			<-lock.held
+			release()
+			return nil, err
+		}
+	}
+
+	return func() {
+		unlockQueue()
+		<-lock.held
+		release()
+	}, nil
+}
+
 func (s *Server) Cancel(id uuid.UUID) error {
 	jobType, status, _, err := s.jobStatus(id, nil)
 	if err != nil {
@@ -345,7 +557,157 @@ func (s *Server) Cancel(id uuid.UUID) error {
 	} else {
 		prometheus.CancelJobMetrics(status.Started, jobType)
 	}
-	return s.jobs.CancelJob(id)
+	err = s.jobs.CancelJob(id)
+	if err != nil {
+		return err
+	}
+	s.jobChanged(id, true)
+	return nil
+}
+
+// RetryKojiCompose runs the koji-finalize job with the given id again, after
+// the osbuild-koji jobs whose uploads failed. They keep the result of their
+// koji-init job, so that the images are uploaded to and imported into the
+// same koji build, which was left open. Uploads continue where the previous
+// attempt stopped, if the rebuilt image is the same. It returns
+// ErrComposeNotRetryable unless the compose failed in a way that can be
+// retried, and had attempts left.
+func (s *Server) RetryKojiCompose(id uuid.UUID) error {
+	var result KojiFinalizeJobResult
+	_, deps, err := s.KojiFinalizeJobStatus(id, &result)
+	if err != nil {
+		return err
+	}
+	if !result.Retryable {
+		return ErrComposeNotRetryable
+	}
+
+	var job KojiFinalizeJob
+	err = s.KojiFinalizeJob(id, &job)
+	if err != nil {
+		return err
+	}
+
+	// the first dependency is the koji-init job
+	for _, dep := range deps[1:] {
+		var buildResult OSBuildKojiJobResult
+		_, _, err = s.OSBuildKojiJobStatus(dep, &buildResult)
+		if err != nil {
+			return err
+		}
+		if buildResult.JobError == nil {
+			continue
+		}
+		err = s.requeue(dep, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	job.Attempt++
+	return s.requeue(id, &job)
+}
+
+func (s *Server) requeue(id uuid.UUID, args interface{}) error {
+	err := s.jobs.RequeueJob(id, args)
+	if err == jobqueue.ErrNotFinished || err == jobqueue.ErrCanceled {
+		// another request retried the compose already
+		return ErrComposeNotRetryable
+	} else if err != nil {
+		return err
+	}
+	s.jobChanged(id, false)
+	return nil
+}
+
+// JobChannel returns the channel the job was enqueued on.
+func (s *Server) JobChannel(id uuid.UUID) (string, error) {
+	_, _, _, channel, err := s.jobs.Job(id)
+	return channel, err
+}
+
+// CancelIncludingDependencies cancels the job with the given id and all the
+// jobs it depends on, recursively. Jobs which have finished or were canceled
+// already are skipped.
+func (s *Server) CancelIncludingDependencies(id uuid.UUID) error {
+	ids, err := s.jobAndDependencies(id)
+	if err != nil {
+		return err
+	}
+
+	for _, jobId := range ids {
+		_, status, _, err := s.jobStatus(jobId, nil)
+		if err != nil {
+			return err
+		}
+		if !status.Finished.IsZero() || status.Canceled {
+			continue
+		}
+
+		err = s.Cancel(jobId)
+		// the job might have finished in the meantime
+		if err != nil && err != jobqueue.ErrNotRunning {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteJobIncludingDependencies deletes the job with the given id, all the
+// jobs it depends on, recursively, and their artifacts. Returns
+// ErrJobNotFinished if any of these jobs has neither finished nor been
+// canceled.
+func (s *Server) DeleteJobIncludingDependencies(id uuid.UUID) error {
+	ids, err := s.jobAndDependencies(id)
+	if err != nil {
+		return err
+	}
+
+	finished := []uuid.UUID{}
+	for _, jobId := range ids {
+		_, status, _, err := s.jobStatus(jobId, nil)
+		if err != nil {
+			return err
+		}
+		if status.Finished.IsZero() && !status.Canceled {
+			return ErrJobNotFinished
+		}
+		if !status.Finished.IsZero() {
+			finished = append(finished, jobId)
+		}
+	}
+
+	if s.config.ArtifactsDir != "" {
+		for _, jobId := range finished {
+			err = s.DeleteArtifacts(jobId)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return s.jobs.DeleteJobIncludingDependencies(id)
+}
+
+// jobAndDependencies returns the ids of the job with the given id and of all
+// the jobs it depends on, recursively.
+func (s *Server) jobAndDependencies(id uuid.UUID) ([]uuid.UUID, error) {
+	ids := []uuid.UUID{id}
+	seen := map[uuid.UUID]bool{id: true}
+	for i := 0; i < len(ids); i++ {
+		_, _, deps, _, err := s.jobs.Job(ids[i])
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range deps {
+			if !seen[d] {
+				seen[d] = true
+				ids = append(ids, d)
+			}
+		}
+	}
+	return ids, nil
 }
 
 // Provides access to artifacts of a job. Returns an io.Reader for the artifact
@@ -444,6 +806,7 @@ func (s *Server) requestJob(ctx context.Context, arch string, jobTypes []string,
 	} else {
 		prometheus.DequeueJobMetrics(status.Queued, status.Started, jobType)
 	}
+	s.jobChanged(jobId, false)
 
 	for _, depID := range depIDs {
 		// TODO: include type of arguments
@@ -496,6 +859,7 @@ func (s *Server) FinishJob(token uuid.UUID, result json.RawMessage) error {
 		statusCode := clienterrors.GetStatusCode(jobResult.JobError)
 		prometheus.FinishJobMetrics(status.Started, status.Finished, status.Canceled, jobType, statusCode)
 	}
+	s.jobChanged(jobId, true)
 
 	// Move artifacts from the temporary location to the final job
 	// location. Log any errors, but do not treat them as fatal. The job is
@@ -601,6 +965,7 @@ func (h *apiHandlers) RequestJob(ctx echo.Context) error {
 		},
 		Location:         fmt.Sprintf("%s/jobs/%v", api.BasePath, jobToken),
 		ArtifactLocation: fmt.Sprintf("%s/jobs/%v/artifacts/", api.BasePath, jobToken),
+		AcceptsLog:       common.BoolToPtr(true),
 		Type:             jobType,
 		Args:             respArgs,
 		DynamicArgs:      respDynArgs,
@@ -664,15 +1029,34 @@ func (h *apiHandlers) UpdateJob(ctx echo.Context, idstr string) error {
 		return err
 	}
 
-	err = h.server.FinishJob(token, body.Result)
-	if err != nil {
-		switch err {
-		case ErrInvalidToken:
-			return api.HTTPError(api.ErrorJobNotFound)
-		case ErrJobNotRunning:
-			return api.HTTPError(api.ErrorJobNotRunning)
-		default:
-			return api.HTTPErrorWithInternal(api.ErrorFinishingJob, err)
+	if body.Log != nil {
+		err = h.server.AppendJobLog(token, *body.Log)
+		if err != nil {
+			switch err {
+			case ErrInvalidToken:
+				return api.HTTPError(api.ErrorJobNotFound)
+			default:
+				return api.HTTPErrorWithInternal(api.ErrorAppendingJobLog, err)
+			}
+		}
+	}
+
+	// updates which only contain log lines don't finish the job
+	if body.Log == nil || body.Result != nil {
+		var result json.RawMessage
+		if body.Result != nil {
+			result = *body.Result
+		}
+		err = h.server.FinishJob(token, result)
+		if err != nil {
+			switch err {
+			case ErrInvalidToken:
+				return api.HTTPError(api.ErrorJobNotFound)
+			case ErrJobNotRunning:
+				return api.HTTPError(api.ErrorJobNotRunning)
+			default:
+				return api.HTTPErrorWithInternal(api.ErrorFinishingJob, err)
+			}
 		}
 	}
 
@@ -139,7 +139,7 @@ func TestCreate(t *testing.T) {
 
 	test.TestRoute(t, handler, false, "POST", "/api/worker/v1/jobs",
 		fmt.Sprintf(`{"types":["osbuild"],"arch":"%s"}`, test_distro.TestArchName), http.StatusCreated,
-		`{"kind":"RequestJob","href":"/api/worker/v1/jobs","type":"osbuild","args":{"manifest":{"pipeline":{},"sources":{}}}}`, "id", "location", "artifact_location")
+		`{"kind":"RequestJob","href":"/api/worker/v1/jobs","type":"osbuild","accepts_log":true,"args":{"manifest":{"pipeline":{},"sources":{}}}}`, "id", "location", "artifact_location")
 }
 
 func TestCancel(t *testing.T) {
@@ -183,6 +183,50 @@ func TestCancel(t *testing.T) {
 		fmt.Sprintf(`{"canceled":true,"href":"/api/worker/v1/jobs/%s","id":"%s","kind":"JobStatus"}`, token, token))
 }
 
+func TestCancelAndDeleteIncludingDependencies(t *testing.T) {
+	tempdir, err := ioutil.TempDir("", "worker-tests-")
+	require.NoError(t, err)
+	defer os.RemoveAll(tempdir)
+
+	server := newTestServer(t, tempdir, time.Duration(0), "/api/worker/v1")
+
+	depsolveID, err := server.EnqueueDepsolve(&worker.DepsolveJob{}, "")
+	require.NoError(t, err)
+	manifestID, err := server.EnqueueManifestJobByID(&worker.ManifestJobByID{}, depsolveID, "")
+	require.NoError(t, err)
+	osbuildID, err := server.EnqueueOSBuildAsDependency(test_distro.TestArchName, &worker.OSBuildJob{}, manifestID, "")
+	require.NoError(t, err)
+
+	_, token, _, _, _, err := server.RequestJob(context.Background(), test_distro.TestArchName, []string{"depsolve"}, []string{""})
+	require.NoError(t, err)
+	require.NoError(t, server.FinishJob(token, nil))
+
+	// jobs must have finished or been canceled before they can be deleted
+	require.Equal(t, worker.ErrJobNotFinished, server.DeleteJobIncludingDependencies(osbuildID))
+
+	require.NoError(t, server.CancelIncludingDependencies(osbuildID))
+
+	var depsolveResult worker.DepsolveJobResult
+	status, _, err := server.DepsolveJobStatus(depsolveID, &depsolveResult)
+	require.NoError(t, err)
+	require.False(t, status.Canceled)
+	status, _, err = server.ManifestJobStatus(manifestID, &worker.ManifestJobByIDResult{})
+	require.NoError(t, err)
+	require.True(t, status.Canceled)
+	status, _, err = server.OSBuildJobStatus(osbuildID, &worker.OSBuildJobResult{})
+	require.NoError(t, err)
+	require.True(t, status.Canceled)
+
+	// canceling again doesn't fail
+	require.NoError(t, server.CancelIncludingDependencies(osbuildID))
+
+	require.NoError(t, server.DeleteJobIncludingDependencies(osbuildID))
+	for _, id := range []uuid.UUID{depsolveID, manifestID, osbuildID} {
+		_, err := server.JobType(id)
+		require.ErrorIs(t, err, jobqueue.ErrNotExist)
+	}
+}
+
 func TestUpdate(t *testing.T) {
 	tempdir, err := ioutil.TempDir("", "worker-tests-")
 	require.NoError(t, err)
@@ -397,7 +441,10 @@ func TestOAuth(t *testing.T) {
 	require.NoError(t, err)
 	r := strings.NewReader("artifact contents")
 	require.NoError(t, job.UploadArtifact("some-artifact", r))
+	// reporting log lines doesn't finish the job
+	require.NoError(t, job.Log([]string{"a line of the log"}))
 	c, err := job.Canceled()
+	require.NoError(t, err)
 	require.False(t, c)
 }
 
@@ -1030,3 +1077,41 @@ func TestMixedOSBuildKojiJobErrors(t *testing.T) {
 	require.NoError(err)
 	require.Equal(newJobResult, newJobResultRead)
 }
+
+func TestLockChannel(t *testing.T) {
+	server := newTestServer(t, t.TempDir(), 0, "/api/worker/v1")
+
+	unlock, err := server.LockChannel(context.Background(), "org-42")
+	require.NoError(t, err)
+
+	// other channels aren't affected
+	unlockOther, err := server.LockChannel(context.Background(), "org-123")
+	require.NoError(t, err)
+	unlockOther()
+
+	// the channel can't be locked twice
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = server.LockChannel(ctx, "org-42")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	locked := make(chan func())
+	go func() {
+		unlock, err := server.LockChannel(context.Background(), "org-42")
+		require.NoError(t, err)
+		locked <- unlock
+	}()
+	select {
+	case <-locked:
+		t.Fatal("channel was locked twice")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	unlock()
+	select {
+	case unlock = <-locked:
+		unlock()
+	case <-time.After(time.Second):
+		t.Fatal("channel wasn't unlocked")
+	}
+}
@@ -70,6 +70,7 @@ BuildRequires:  golang(github.com/vmware/govmomi)
 BuildRequires:  golang(github.com/oracle/oci-go-sdk/v54)
 BuildRequires:  golang(cloud.google.com/go)
 BuildRequires:  golang(gopkg.in/ini.v1)
+BuildRequires:  golang(gopkg.in/yaml.v3)
 %endif
 
 Requires: %{name}-core = %{version}-%{release}
@@ -125,6 +126,7 @@ export LDFLAGS="${LDFLAGS} -X 'github.com/osbuild/osbuild-composer/internal/comm
 
 %gobuild -o _bin/osbuild-composer %{goipath}/cmd/osbuild-composer
 %gobuild -o _bin/osbuild-worker %{goipath}/cmd/osbuild-worker
+%gobuild -o _bin/composer-cloud %{goipath}/cmd/composer-cloud
 
 make man
 
@@ -162,6 +164,9 @@ install -m 0755 -vp _bin/osbuild-composer                          %{buildroot}%
 install -m 0755 -vp _bin/osbuild-worker                            %{buildroot}%{_libexecdir}/osbuild-composer/
 install -m 0755 -vp dnf-json                                       %{buildroot}%{_libexecdir}/osbuild-composer/
 
+install -m 0755 -vd                                                %{buildroot}%{_bindir}
+install -m 0755 -vp _bin/composer-cloud                            %{buildroot}%{_bindir}/
+
 # Only include repositories for the distribution and release
 install -m 0755 -vd                                                %{buildroot}%{_datadir}/osbuild-composer/repositories
 # CentOS also defines rhel so we check for centos first
@@ -375,6 +380,16 @@ The dnf-json binary used by osbuild-composer and the workers.
 %{_unitdir}/osbuild-dnf-json.service
 %{_unitdir}/osbuild-dnf-json.socket
 
+%package -n composer-cloud
+Summary: A command line client for the cloud API of osbuild-composer
+
+%description -n composer-cloud
+Submits compose requests to the cloud API of osbuild-composer, waits for
+them to finish and prints the results of uploading the images.
+
+%files -n composer-cloud
+%{_bindir}/composer-cloud
+
 %if %{with tests} || 0%{?rhel}
 
 %package tests
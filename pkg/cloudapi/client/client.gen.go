@@ -0,0 +1,3979 @@
+// Package client provides primitives to interact with the openapi HTTP API.
+//
+// Code generated by github.com/deepmap/oapi-codegen version v1.8.2 DO NOT EDIT.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/deepmap/oapi-codegen/pkg/runtime"
+)
+
+const (
+	BearerScopes = "Bearer.Scopes"
+)
+
+// Defines values for ComposeNotificationStatus.
+const (
+	ComposeNotificationStatusBuilding ComposeNotificationStatus = "building"
+
+	ComposeNotificationStatusFailure ComposeNotificationStatus = "failure"
+
+	ComposeNotificationStatusPending ComposeNotificationStatus = "pending"
+
+	ComposeNotificationStatusSuccess ComposeNotificationStatus = "success"
+
+	ComposeNotificationStatusUploading ComposeNotificationStatus = "uploading"
+)
+
+// Defines values for ComposeStatusValue.
+const (
+	ComposeStatusValueFailure ComposeStatusValue = "failure"
+
+	ComposeStatusValuePending ComposeStatusValue = "pending"
+
+	ComposeStatusValueSuccess ComposeStatusValue = "success"
+)
+
+// Defines values for ImageStatusValue.
+const (
+	ImageStatusValueBuilding ImageStatusValue = "building"
+
+	ImageStatusValueFailure ImageStatusValue = "failure"
+
+	ImageStatusValuePending ImageStatusValue = "pending"
+
+	ImageStatusValueRegistering ImageStatusValue = "registering"
+
+	ImageStatusValueSuccess ImageStatusValue = "success"
+
+	ImageStatusValueUploading ImageStatusValue = "uploading"
+)
+
+// Defines values for ImageTypes.
+const (
+	ImageTypesAws ImageTypes = "aws"
+
+	ImageTypesAwsHaRhui ImageTypes = "aws-ha-rhui"
+
+	ImageTypesAwsRhui ImageTypes = "aws-rhui"
+
+	ImageTypesAwsSapRhui ImageTypes = "aws-sap-rhui"
+
+	ImageTypesAzure ImageTypes = "azure"
+
+	ImageTypesAzureRhui ImageTypes = "azure-rhui"
+
+	ImageTypesEdgeCommit ImageTypes = "edge-commit"
+
+	ImageTypesEdgeContainer ImageTypes = "edge-container"
+
+	ImageTypesEdgeInstaller ImageTypes = "edge-installer"
+
+	ImageTypesGcp ImageTypes = "gcp"
+
+	ImageTypesGuestImage ImageTypes = "guest-image"
+
+	ImageTypesImageInstaller ImageTypes = "image-installer"
+
+	ImageTypesVsphere ImageTypes = "vsphere"
+)
+
+// Defines values for KojiStatusFailure.
+const (
+	KojiStatusFailureBuild KojiStatusFailure = "build"
+
+	KojiStatusFailureImport KojiStatusFailure = "import"
+
+	KojiStatusFailureUpload KojiStatusFailure = "upload"
+)
+
+// Defines values for PackageExplanationSource.
+const (
+	PackageExplanationSourceImageType PackageExplanationSource = "image_type"
+
+	PackageExplanationSourceRequest PackageExplanationSource = "request"
+)
+
+// Defines values for RepositoryHealthCheckName.
+const (
+	RepositoryHealthCheckNameGpgKey RepositoryHealthCheckName = "gpg_key"
+
+	RepositoryHealthCheckNameMetadataAge RepositoryHealthCheckName = "metadata_age"
+
+	RepositoryHealthCheckNameReachable RepositoryHealthCheckName = "reachable"
+
+	RepositoryHealthCheckNameRepomdSignature RepositoryHealthCheckName = "repomd_signature"
+)
+
+// Defines values for RepositoryHealthCheckStatus.
+const (
+	RepositoryHealthCheckStatusError RepositoryHealthCheckStatus = "error"
+
+	RepositoryHealthCheckStatusOk RepositoryHealthCheckStatus = "ok"
+
+	RepositoryHealthCheckStatusSkipped RepositoryHealthCheckStatus = "skipped"
+
+	RepositoryHealthCheckStatusWarning RepositoryHealthCheckStatus = "warning"
+)
+
+// Defines values for StageChangeChange.
+const (
+	StageChangeChangeAdded StageChangeChange = "added"
+
+	StageChangeChangeChanged StageChangeChange = "changed"
+
+	StageChangeChangeRemoved StageChangeChange = "removed"
+)
+
+// Defines values for UploadStatusValue.
+const (
+	UploadStatusValueFailure UploadStatusValue = "failure"
+
+	UploadStatusValuePending UploadStatusValue = "pending"
+
+	UploadStatusValueRunning UploadStatusValue = "running"
+
+	UploadStatusValueSuccess UploadStatusValue = "success"
+)
+
+// Defines values for UploadTypes.
+const (
+	UploadTypesAws UploadTypes = "aws"
+
+	UploadTypesAwsS3 UploadTypes = "aws.s3"
+
+	UploadTypesAzure UploadTypes = "azure"
+
+	UploadTypesGcp UploadTypes = "gcp"
+)
+
+// AWSEC2UploadOptions defines model for AWSEC2UploadOptions.
+type AWSEC2UploadOptions struct {
+	Region            string   `json:"region"`
+	ShareWithAccounts []string `json:"share_with_accounts"`
+	SnapshotName      *string  `json:"snapshot_name,omitempty"`
+}
+
+// AWSEC2UploadStatus defines model for AWSEC2UploadStatus.
+type AWSEC2UploadStatus struct {
+	Ami    string `json:"ami"`
+	Region string `json:"region"`
+}
+
+// AWSS3UploadOptions defines model for AWSS3UploadOptions.
+type AWSS3UploadOptions struct {
+	Region string `json:"region"`
+}
+
+// AWSS3UploadStatus defines model for AWSS3UploadStatus.
+type AWSS3UploadStatus struct {
+	Url string `json:"url"`
+}
+
+// Advisory defines model for Advisory.
+type Advisory struct {
+	Cves   *[]string `json:"cves,omitempty"`
+	Id     string    `json:"id"`
+	Issued *string   `json:"issued,omitempty"`
+
+	// Packages containing the fix
+	Packages []AdvisoryPackage `json:"packages"`
+	Severity *string           `json:"severity,omitempty"`
+	Title    string            `json:"title"`
+
+	// security, bugfix, enhancement, or newpackage
+	Type string `json:"type"`
+}
+
+// AdvisoryPackage defines model for AdvisoryPackage.
+type AdvisoryPackage struct {
+	Arch    string `json:"arch"`
+	Epoch   int    `json:"epoch"`
+	Name    string `json:"name"`
+	Release string `json:"release"`
+	Version string `json:"version"`
+}
+
+// AzureUploadOptions defines model for AzureUploadOptions.
+type AzureUploadOptions struct {
+	// Name of the uploaded image. It must be unique in the given resource group.
+	// If name is omitted from the request, a random one based on a UUID is
+	// generated.
+	ImageName *string `json:"image_name,omitempty"`
+
+	// Location where the image should be uploaded and registered.
+	// How to list all locations:
+	// https://docs.microsoft.com/en-us/cli/azure/account?view=azure-cli-latest#az_account_list_locations'
+	Location string `json:"location"`
+
+	// Name of the resource group where the image should be uploaded.
+	ResourceGroup string `json:"resource_group"`
+
+	// ID of subscription where the image should be uploaded.
+	SubscriptionId string `json:"subscription_id"`
+
+	// ID of the tenant where the image should be uploaded.
+	// How to find it in the Azure Portal:
+	// https://docs.microsoft.com/en-us/azure/active-directory/fundamentals/active-directory-how-to-find-tenant
+	TenantId string `json:"tenant_id"`
+}
+
+// AzureUploadStatus defines model for AzureUploadStatus.
+type AzureUploadStatus struct {
+	ImageName string `json:"image_name"`
+}
+
+// ComposeAdvisories defines model for ComposeAdvisories.
+type ComposeAdvisories struct {
+	// Embedded struct due to allOf(#/components/schemas/ObjectReference)
+	ObjectReference `yaml:",inline"`
+	// Embedded fields due to inline allOf schema
+	// Advisories whose fixes are included in the compose
+	Fixed []Advisory `json:"fixed"`
+
+	// Advisories for which the compose contains outdated packages
+	Unpatched []Advisory `json:"unpatched"`
+
+	// CVEs referenced by the unpatched advisories
+	UnpatchedCves []string `json:"unpatched_cves"`
+}
+
+// ComposeDiff defines model for ComposeDiff.
+type ComposeDiff struct {
+	// Embedded struct due to allOf(#/components/schemas/ObjectReference)
+	ObjectReference `yaml:",inline"`
+	// Embedded fields due to inline allOf schema
+	// Changes of the blueprint and its customizations
+	Blueprint []FieldChange `json:"blueprint"`
+
+	// ID of the compose compared to
+	OtherId  string      `json:"other_id"`
+	Packages PackageDiff `json:"packages"`
+
+	// Changes of the partition table, without partition UUIDs
+	PartitionTable []FieldChange `json:"partition_table"`
+
+	// Stages of the manifest which were added, removed, or changed
+	Stages []StageChange `json:"stages"`
+}
+
+// ComposeEvent defines model for ComposeEvent.
+type ComposeEvent struct {
+	// The job which logged the lines
+	JobId *string `json:"job_id,omitempty"`
+
+	// Lines of the job's osbuild log
+	Lines  *[]string                  `json:"lines,omitempty"`
+	Status *ComposeNotificationStatus `json:"status,omitempty"`
+}
+
+// ComposeId defines model for ComposeId.
+type ComposeId struct {
+	// Embedded struct due to allOf(#/components/schemas/ObjectReference)
+	ObjectReference `yaml:",inline"`
+	// Embedded fields due to inline allOf schema
+	Id string `json:"id"`
+}
+
+// ComposeList defines model for ComposeList.
+type ComposeList struct {
+	// Embedded struct due to allOf(#/components/schemas/List)
+	List `yaml:",inline"`
+	// Embedded fields due to inline allOf schema
+	Items []ComposeStatus `json:"items"`
+}
+
+// ComposeLogs defines model for ComposeLogs.
+type ComposeLogs struct {
+	// Embedded struct due to allOf(#/components/schemas/ObjectReference)
+	ObjectReference `yaml:",inline"`
+	// Embedded fields due to inline allOf schema
+	ImageBuilds []interface{} `json:"image_builds"`
+	Koji        *KojiLogs     `json:"koji,omitempty"`
+}
+
+// ComposeManifests defines model for ComposeManifests.
+type ComposeManifests struct {
+	// Embedded struct due to allOf(#/components/schemas/ObjectReference)
+	ObjectReference `yaml:",inline"`
+	// Embedded fields due to inline allOf schema
+	Manifests []interface{} `json:"manifests"`
+}
+
+// ComposeMetadata defines model for ComposeMetadata.
+type ComposeMetadata struct {
+	// Embedded struct due to allOf(#/components/schemas/ObjectReference)
+	ObjectReference `yaml:",inline"`
+	// Embedded fields due to inline allOf schema
+	// ID (hash) of the built commit
+	OstreeCommit *string `json:"ostree_commit,omitempty"`
+
+	// Package list including NEVRA
+	Packages *[]PackageMetadata `json:"packages,omitempty"`
+}
+
+// ComposeNotificationStatus defines model for ComposeNotificationStatus.
+type ComposeNotificationStatus string
+
+// ComposeNotifications defines model for ComposeNotifications.
+type ComposeNotifications struct {
+	// Embedded struct due to allOf(#/components/schemas/ObjectReference)
+	ObjectReference `yaml:",inline"`
+	// Embedded fields due to inline allOf schema
+	Deliveries []NotificationDelivery `json:"deliveries"`
+}
+
+// ComposeRequest defines model for ComposeRequest.
+type ComposeRequest struct {
+	Customizations *Customizations `json:"customizations,omitempty"`
+	Distribution   string          `json:"distribution"`
+	ImageRequest   *ImageRequest   `json:"image_request,omitempty"`
+	ImageRequests  *[]ImageRequest `json:"image_requests,omitempty"`
+	Koji           *Koji           `json:"koji,omitempty"`
+
+	// URLs to POST to whenever the status of the compose changes. The
+	// body is a JSON object with the fields id, compose_id, status and
+	// time. Requests to webhooks with a secret are signed with it: the
+	// X-Composer-Signature header contains "sha256=" followed by the
+	// hex-encoded HMAC-SHA256 of the body.
+	Webhooks *[]Webhook `json:"webhooks,omitempty"`
+}
+
+// ComposeStatus defines model for ComposeStatus.
+type ComposeStatus struct {
+	// Embedded struct due to allOf(#/components/schemas/ObjectReference)
+	ObjectReference `yaml:",inline"`
+	// Embedded fields due to inline allOf schema
+	// Set when the image was not built, because a previous compose
+	// with identical content (packages, customizations and image
+	// type) was found. Its image and upload results were reused.
+	DeduplicatedFrom *string            `json:"deduplicated_from,omitempty"`
+	ImageStatus      ImageStatus        `json:"image_status"`
+	ImageStatuses    *[]ImageStatus     `json:"image_statuses,omitempty"`
+	KojiStatus       *KojiStatus        `json:"koji_status,omitempty"`
+	Status           ComposeStatusValue `json:"status"`
+}
+
+// ComposeStatusError defines model for ComposeStatusError.
+type ComposeStatusError struct {
+	Details *interface{} `json:"details,omitempty"`
+	Id      int          `json:"id"`
+	Reason  string       `json:"reason"`
+}
+
+// ComposeStatusValue defines model for ComposeStatusValue.
+type ComposeStatusValue string
+
+// Customizations defines model for Customizations.
+type Customizations struct {
+	// Module streams to enable when depsolving the packages of the image.
+	// The streams are also recorded as enabled in the image.
+	EnabledModules *[]Module     `json:"enabled_modules,omitempty"`
+	Filesystem     *[]Filesystem `json:"filesystem,omitempty"`
+	Firewall       *Firewall     `json:"firewall,omitempty"`
+	Groups         *[]Group      `json:"groups,omitempty"`
+	Hostname       *string       `json:"hostname,omitempty"`
+	Kernel         *Kernel       `json:"kernel,omitempty"`
+	Locale         *Locale       `json:"locale,omitempty"`
+	Packages       *[]string     `json:"packages,omitempty"`
+
+	// Extra repositories for packages specified in customizations. These
+	// repositories will only be used to depsolve and retrieve packages
+	// for the OS itself (they will not be available for the build root or
+	// any other part of the build process). The package_sets field for these
+	// repositories is ignored.
+	PayloadRepositories *[]Repository `json:"payload_repositories,omitempty"`
+
+	// Services to enable or disable. For the firewall, these are the names
+	// of firewalld services, otherwise of systemd units.
+	Services     *Services     `json:"services,omitempty"`
+	Subscription *Subscription `json:"subscription,omitempty"`
+	Timezone     *Timezone     `json:"timezone,omitempty"`
+	Users        *[]User       `json:"users,omitempty"`
+}
+
+// Error defines model for Error.
+type Error struct {
+	// Embedded struct due to allOf(#/components/schemas/ObjectReference)
+	ObjectReference `yaml:",inline"`
+	// Embedded fields due to inline allOf schema
+	Code string `json:"code"`
+
+	// Additional information about the error, e.g. the exceeded quota
+	Details     *interface{} `json:"details,omitempty"`
+	OperationId string       `json:"operation_id"`
+	Reason      string       `json:"reason"`
+}
+
+// ErrorList defines model for ErrorList.
+type ErrorList struct {
+	// Embedded struct due to allOf(#/components/schemas/List)
+	List `yaml:",inline"`
+	// Embedded fields due to inline allOf schema
+	Items []Error `json:"items"`
+}
+
+// FieldChange defines model for FieldChange.
+type FieldChange struct {
+	// Value in the compose compared from, missing if the value was added
+	From *interface{} `json:"from,omitempty"`
+
+	// Location of the value, or empty when the values differ as a whole
+	Path string `json:"path"`
+
+	// Value in the compose compared to, missing if the value was removed
+	To *interface{} `json:"to,omitempty"`
+}
+
+// Filesystem defines model for Filesystem.
+type Filesystem struct {
+	MinSize    int    `json:"min_size"`
+	Mountpoint string `json:"mountpoint"`
+}
+
+// Firewall defines model for Firewall.
+type Firewall struct {
+	Ports *[]string `json:"ports,omitempty"`
+
+	// Services to enable or disable. For the firewall, these are the names
+	// of firewalld services, otherwise of systemd units.
+	Services *Services `json:"services,omitempty"`
+}
+
+// GCPUploadOptions defines model for GCPUploadOptions.
+type GCPUploadOptions struct {
+	// Name of an existing STANDARD Storage class Bucket.
+	Bucket string `json:"bucket"`
+
+	// The name to use for the imported and shared Compute Engine image.
+	// The image name must be unique within the GCP project, which is used
+	// for the OS image upload and import. If not specified a random
+	// 'composer-api-<uuid>' string is used as the image name.
+	ImageName *string `json:"image_name,omitempty"`
+
+	// The GCP region where the OS image will be imported to and shared from.
+	// The value must be a valid GCP location. See https://cloud.google.com/storage/docs/locations.
+	// If not specified, the multi-region location closest to the source
+	// (source Storage Bucket location) is chosen automatically.
+	Region string `json:"region"`
+
+	// List of valid Google accounts to share the imported Compute Engine image with.
+	// Each string must contain a specifier of the account type. Valid formats are:
+	//   - 'user:{emailid}': An email address that represents a specific
+	//     Google account. For example, 'alice@example.com'.
+	//   - 'serviceAccount:{emailid}': An email address that represents a
+	//     service account. For example, 'my-other-app@appspot.gserviceaccount.com'.
+	//   - 'group:{emailid}': An email address that represents a Google group.
+	//     For example, 'admins@example.com'.
+	//   - 'domain:{domain}': The G Suite domain (primary) that represents all
+	//     the users of that domain. For example, 'google.com' or 'example.com'.
+	// If not specified, the imported Compute Engine image is not shared with any
+	// account.
+	ShareWithAccounts *[]string `json:"share_with_accounts,omitempty"`
+}
+
+// GCPUploadStatus defines model for GCPUploadStatus.
+type GCPUploadStatus struct {
+	ImageName string `json:"image_name"`
+	ProjectId string `json:"project_id"`
+}
+
+// Group defines model for Group.
+type Group struct {
+	Gid  *int   `json:"gid,omitempty"`
+	Name string `json:"name"`
+}
+
+// ImageRequest defines model for ImageRequest.
+type ImageRequest struct {
+	Architecture  string         `json:"architecture"`
+	ImageType     ImageTypes     `json:"image_type"`
+	Ostree        *OSTree        `json:"ostree,omitempty"`
+	Repositories  []Repository   `json:"repositories"`
+	UploadOptions *UploadOptions `json:"upload_options,omitempty"`
+}
+
+// ImageStatus defines model for ImageStatus.
+type ImageStatus struct {
+	Error        *ComposeStatusError `json:"error,omitempty"`
+	Status       ImageStatusValue    `json:"status"`
+	UploadStatus *UploadStatus       `json:"upload_status,omitempty"`
+}
+
+// ImageStatusValue defines model for ImageStatusValue.
+type ImageStatusValue string
+
+// ImageTypes defines model for ImageTypes.
+type ImageTypes string
+
+// Kernel defines model for Kernel.
+type Kernel struct {
+	// Arguments appended to the kernel command line
+	Append *string `json:"append,omitempty"`
+
+	// Name of the kernel package to install instead of the default one
+	Name *string `json:"name,omitempty"`
+}
+
+// Koji defines model for Koji.
+type Koji struct {
+	Name    string `json:"name"`
+	Release string `json:"release"`
+	Server  string `json:"server"`
+	TaskId  int    `json:"task_id"`
+	Version string `json:"version"`
+}
+
+// KojiLogs defines model for KojiLogs.
+type KojiLogs struct {
+	Import interface{} `json:"import"`
+	Init   interface{} `json:"init"`
+}
+
+// KojiStatus defines model for KojiStatus.
+type KojiStatus struct {
+	BuildId *int `json:"build_id,omitempty"`
+
+	// The step at which a failed koji build failed. When only uploading
+	// or importing the images failed, the koji build is left open until
+	// the compose was retried a few times.
+	Failure *KojiStatusFailure `json:"failure,omitempty"`
+
+	// Whether the koji build was left open, so that the compose can be
+	// retried with /composes/{id}/retry.
+	Retryable *bool `json:"retryable,omitempty"`
+}
+
+// The step at which a failed koji build failed. When only uploading
+// or importing the images failed, the koji build is left open until
+// the compose was retried a few times.
+type KojiStatusFailure string
+
+// List defines model for List.
+type List struct {
+	Kind  string `json:"kind"`
+	Page  int    `json:"page"`
+	Size  int    `json:"size"`
+	Total int    `json:"total"`
+}
+
+// Locale defines model for Locale.
+type Locale struct {
+	Keyboard  *string   `json:"keyboard,omitempty"`
+	Languages *[]string `json:"languages,omitempty"`
+}
+
+// Module defines model for Module.
+type Module struct {
+	Name   string `json:"name"`
+	Stream string `json:"stream"`
+}
+
+// NotificationDelivery defines model for NotificationDelivery.
+type NotificationDelivery struct {
+	Attempts    int                       `json:"attempts"`
+	Delivered   bool                      `json:"delivered"`
+	EventId     string                    `json:"event_id"`
+	LastAttempt time.Time                 `json:"last_attempt"`
+	Status      ComposeNotificationStatus `json:"status"`
+	Url         string                    `json:"url"`
+}
+
+// OSTree defines model for OSTree.
+type OSTree struct {
+	// ASCII-armored public GPG key the parent commit must be signed with.
+	GpgKey *string `json:"gpg_key,omitempty"`
+	Parent *string `json:"parent,omitempty"`
+	Ref    *string `json:"ref,omitempty"`
+
+	// Pull the parent commit from url with the subscription manager
+	// (candlepin) identity of the worker's host: its consumer
+	// certificate is used as client certificate. Composer doesn't access
+	// the repository itself, so this requires a url and the checksum of
+	// the parent commit as parent. Other credentials, like custom CA or
+	// client certificates and tokens, are not supported.
+	Rhsm *bool `json:"rhsm,omitempty"`
+
+	// Sign the commit with the signing key of the worker
+	Sign *bool `json:"sign,omitempty"`
+
+	// Generate a static delta from the parent commit to the new commit
+	// and include it in the commit archive. Requires a url.
+	StaticDelta *bool   `json:"static_delta,omitempty"`
+	Url         *string `json:"url,omitempty"`
+}
+
+// ObjectReference defines model for ObjectReference.
+type ObjectReference struct {
+	Href string `json:"href"`
+	Id   string `json:"id"`
+	Kind string `json:"kind"`
+}
+
+// PackageDiff defines model for PackageDiff.
+type PackageDiff struct {
+	Added   []PackageVersion       `json:"added"`
+	Changed []PackageVersionChange `json:"changed"`
+	Removed []PackageVersion       `json:"removed"`
+}
+
+// PackageExplainRequest defines model for PackageExplainRequest.
+type PackageExplainRequest struct {
+	Architecture   string     `json:"architecture"`
+	Distribution   string     `json:"distribution"`
+	EnabledModules *[]Module  `json:"enabled_modules,omitempty"`
+	ImageType      ImageTypes `json:"image_type"`
+
+	// Packages to add to the image, like in the customizations of a compose
+	Packages     *[]string    `json:"packages,omitempty"`
+	Repositories []Repository `json:"repositories"`
+}
+
+// PackageExplanation defines model for PackageExplanation.
+type PackageExplanation struct {
+	Arch string `json:"arch"`
+
+	// The shortest chain of requirements from a requested package to
+	// this one, each package requiring the next one
+	Chain []string `json:"chain"`
+	Epoch int      `json:"epoch"`
+	Name  string   `json:"name"`
+
+	// Why dnf installs the package, "user" for requested packages
+	Reason  *string `json:"reason,omitempty"`
+	Release string  `json:"release"`
+
+	// The package specs that requested the first package of the chain
+	RequestedBy []string `json:"requested_by"`
+
+	// Whether the first package of the chain was requested in the
+	// request or is one of the image type's default packages
+	Source  *PackageExplanationSource `json:"source,omitempty"`
+	Version string                    `json:"version"`
+}
+
+// Whether the first package of the chain was requested in the
+// request or is one of the image type's default packages
+type PackageExplanationSource string
+
+// PackageExplanationList defines model for PackageExplanationList.
+type PackageExplanationList struct {
+	PackageSets []PackageSetExplanation `json:"package_sets"`
+}
+
+// PackageMetadata defines model for PackageMetadata.
+type PackageMetadata struct {
+	Arch      string  `json:"arch"`
+	Epoch     *string `json:"epoch,omitempty"`
+	Name      string  `json:"name"`
+	Release   string  `json:"release"`
+	Sigmd5    string  `json:"sigmd5"`
+	Signature *string `json:"signature,omitempty"`
+	Type      string  `json:"type"`
+	Version   string  `json:"version"`
+}
+
+// PackageSetExplanation defines model for PackageSetExplanation.
+type PackageSetExplanation struct {
+	Name     string               `json:"name"`
+	Packages []PackageExplanation `json:"packages"`
+}
+
+// PackageVersion defines model for PackageVersion.
+type PackageVersion struct {
+	Arch string `json:"arch"`
+	Name string `json:"name"`
+
+	// Version in the form [epoch:]version-release
+	Version string `json:"version"`
+}
+
+// PackageVersionChange defines model for PackageVersionChange.
+type PackageVersionChange struct {
+	Arch        string `json:"arch"`
+	FromVersion string `json:"from_version"`
+	Name        string `json:"name"`
+	ToVersion   string `json:"to_version"`
+}
+
+// Repository defines model for Repository.
+type Repository struct {
+	Baseurl    *string `json:"baseurl,omitempty"`
+	CheckGpg   *bool   `json:"check_gpg,omitempty"`
+	GpgKey     *string `json:"gpg_key,omitempty"`
+	IgnoreSsl  *bool   `json:"ignore_ssl,omitempty"`
+	Metalink   *string `json:"metalink,omitempty"`
+	Mirrorlist *string `json:"mirrorlist,omitempty"`
+
+	// Naming package sets for a repository assigns it to a specific part
+	// (pipeline) of the build process.
+	PackageSets *[]string `json:"package_sets,omitempty"`
+
+	// Proxy to access the repository through. It must not contain
+	// credentials. All repositories of a compose which use a proxy must
+	// use the same one.
+	Proxy *string `json:"proxy,omitempty"`
+	Rhsm  bool    `json:"rhsm"`
+}
+
+// RepositoryHealth defines model for RepositoryHealth.
+type RepositoryHealth struct {
+	Checks []RepositoryHealthCheck `json:"checks"`
+
+	// False when any of the checks failed, warnings are ignored
+	Healthy bool `json:"healthy"`
+
+	// When the newest metadata of the repository was generated
+	MetadataTimestamp *time.Time `json:"metadata_timestamp,omitempty"`
+
+	// The baseurl, mirrorlist, or metalink of the repository
+	Url string `json:"url"`
+}
+
+// RepositoryHealthCheck defines model for RepositoryHealthCheck.
+type RepositoryHealthCheck struct {
+	Message *string                     `json:"message,omitempty"`
+	Name    RepositoryHealthCheckName   `json:"name"`
+	Status  RepositoryHealthCheckStatus `json:"status"`
+}
+
+// RepositoryHealthCheckName defines model for RepositoryHealthCheck.Name.
+type RepositoryHealthCheckName string
+
+// RepositoryHealthCheckStatus defines model for RepositoryHealthCheck.Status.
+type RepositoryHealthCheckStatus string
+
+// RepositoryHealthList defines model for RepositoryHealthList.
+type RepositoryHealthList struct {
+	Items []RepositoryHealth `json:"items"`
+}
+
+// RepositoryHealthRequest defines model for RepositoryHealthRequest.
+type RepositoryHealthRequest struct {
+	// Architecture the repositories are used for, to expand $basearch
+	Architecture string `json:"architecture"`
+
+	// Distribution the repositories are used for, to expand $releasever
+	Distribution string       `json:"distribution"`
+	Repositories []Repository `json:"repositories"`
+}
+
+// Services to enable or disable. For the firewall, these are the names
+// of firewalld services, otherwise of systemd units.
+type Services struct {
+	Disabled *[]string `json:"disabled,omitempty"`
+	Enabled  *[]string `json:"enabled,omitempty"`
+}
+
+// StageChange defines model for StageChange.
+type StageChange struct {
+	Change      StageChangeChange       `json:"change"`
+	FromOptions *map[string]interface{} `json:"from_options,omitempty"`
+
+	// The stage is the n-th stage of its type in the pipeline
+	Index     int                     `json:"index"`
+	Pipeline  string                  `json:"pipeline"`
+	ToOptions *map[string]interface{} `json:"to_options,omitempty"`
+	Type      string                  `json:"type"`
+}
+
+// StageChangeChange defines model for StageChange.Change.
+type StageChangeChange string
+
+// Subscription defines model for Subscription.
+type Subscription struct {
+	ActivationKey string `json:"activation_key"`
+	BaseUrl       string `json:"base_url"`
+	Insights      bool   `json:"insights"`
+	Organization  string `json:"organization"`
+	ServerUrl     string `json:"server_url"`
+}
+
+// Timezone defines model for Timezone.
+type Timezone struct {
+	Ntpservers *[]string `json:"ntpservers,omitempty"`
+	Timezone   *string   `json:"timezone,omitempty"`
+}
+
+// UploadOptions defines model for UploadOptions.
+type UploadOptions interface{}
+
+// UploadStatus defines model for UploadStatus.
+type UploadStatus struct {
+	Options interface{}       `json:"options"`
+	Status  UploadStatusValue `json:"status"`
+	Type    UploadTypes       `json:"type"`
+}
+
+// UploadStatusValue defines model for UploadStatusValue.
+type UploadStatusValue string
+
+// UploadTypes defines model for UploadTypes.
+type UploadTypes string
+
+// User defines model for User.
+type User struct {
+	// Embedded struct due to allOf(#/components/schemas/ObjectReference)
+	ObjectReference `yaml:",inline"`
+	// Embedded fields due to inline allOf schema
+	Groups *[]string `json:"groups,omitempty"`
+	Key    *string   `json:"key,omitempty"`
+	Name   string    `json:"name"`
+}
+
+// Webhook defines model for Webhook.
+type Webhook struct {
+	// Key of the HMAC signature of the requests
+	Secret *string `json:"secret,omitempty"`
+
+	// Events are only delivered to public addresses, not to loopback,
+	// link-local or private ones
+	Url string `json:"url"`
+}
+
+// Page defines model for page.
+type Page string
+
+// Size defines model for size.
+type Size string
+
+// PostComposeJSONBody defines parameters for PostCompose.
+type PostComposeJSONBody ComposeRequest
+
+// GetComposeListParams defines parameters for GetComposeList.
+type GetComposeListParams struct {
+	// Page index
+	Page *Page `json:"page,omitempty"`
+
+	// Number of items in each page
+	Size *Size `json:"size,omitempty"`
+
+	// Only list composes with this status
+	Status *ComposeStatusValue `json:"status,omitempty"`
+
+	// Only list composes which build an image of this type
+	ImageType *ImageTypes `json:"image_type,omitempty"`
+
+	// Only list composes of this distribution
+	Distribution *string `json:"distribution,omitempty"`
+
+	// Only list composes created at or after this time
+	CreatedAfter *time.Time `json:"created_after,omitempty"`
+
+	// Only list composes created before this time
+	CreatedBefore *time.Time `json:"created_before,omitempty"`
+}
+
+// DeleteComposeParams defines parameters for DeleteCompose.
+type DeleteComposeParams struct {
+	// Also remove the images uploaded to AWS EC2 or GCP
+	DeleteImages *bool `json:"delete_images,omitempty"`
+}
+
+// GetComposeSBOMParams defines parameters for GetComposeSBOM.
+type GetComposeSBOMParams struct {
+	// Format of the software bill of materials
+	Format *GetComposeSBOMParamsFormat `json:"format,omitempty"`
+}
+
+// GetComposeSBOMParamsFormat defines parameters for GetComposeSBOM.
+type GetComposeSBOMParamsFormat string
+
+// GetErrorListParams defines parameters for GetErrorList.
+type GetErrorListParams struct {
+	// Page index
+	Page *Page `json:"page,omitempty"`
+
+	// Number of items in each page
+	Size *Size `json:"size,omitempty"`
+}
+
+// PostPackagesExplainJSONBody defines parameters for PostPackagesExplain.
+type PostPackagesExplainJSONBody PackageExplainRequest
+
+// PostRepositoriesHealthJSONBody defines parameters for PostRepositoriesHealth.
+type PostRepositoriesHealthJSONBody RepositoryHealthRequest
+
+// PostComposeJSONRequestBody defines body for PostCompose for application/json ContentType.
+type PostComposeJSONRequestBody PostComposeJSONBody
+
+// PostPackagesExplainJSONRequestBody defines body for PostPackagesExplain for application/json ContentType.
+type PostPackagesExplainJSONRequestBody PostPackagesExplainJSONBody
+
+// PostRepositoriesHealthJSONRequestBody defines body for PostRepositoriesHealth for application/json ContentType.
+type PostRepositoriesHealthJSONRequestBody PostRepositoriesHealthJSONBody
+
+// RequestEditorFn  is the function signature for the RequestEditor callback function
+type RequestEditorFn func(ctx context.Context, req *http.Request) error
+
+// Doer performs HTTP requests.
+//
+// The standard http.Client implements this interface.
+type HttpRequestDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Client which conforms to the OpenAPI3 specification for this service.
+type Client struct {
+	// The endpoint of the server conforming to this interface, with scheme,
+	// https://api.deepmap.com for example. This can contain a path relative
+	// to the server, such as https://api.deepmap.com/dev-test, and all the
+	// paths in the swagger spec will be appended to the server.
+	Server string
+
+	// Doer for performing requests, typically a *http.Client with any
+	// customized settings, such as certificate chains.
+	Client HttpRequestDoer
+
+	// A list of callbacks for modifying requests which are generated before sending over
+	// the network.
+	RequestEditors []RequestEditorFn
+}
+
+// ClientOption allows setting custom parameters during construction
+type ClientOption func(*Client) error
+
+// Creates a new Client, with reasonable defaults
+func NewClient(server string, opts ...ClientOption) (*Client, error) {
+	// create a client with sane default values
+	client := Client{
+		Server: server,
+	}
+	// mutate client and add all optional params
+	for _, o := range opts {
+		if err := o(&client); err != nil {
+			return nil, err
+		}
+	}
+	// ensure the server URL always has a trailing slash
+	if !strings.HasSuffix(client.Server, "/") {
+		client.Server += "/"
+	}
+	// create httpClient, if not already present
+	if client.Client == nil {
+		client.Client = &http.Client{}
+	}
+	return &client, nil
+}
+
+// WithHTTPClient allows overriding the default Doer, which is
+// automatically created using http.Client. This is useful for tests.
+func WithHTTPClient(doer HttpRequestDoer) ClientOption {
+	return func(c *Client) error {
+		c.Client = doer
+		return nil
+	}
+}
+
+// WithRequestEditorFn allows setting up a callback function, which will be
+// called right before sending the request. This can be used to mutate the request.
+func WithRequestEditorFn(fn RequestEditorFn) ClientOption {
+	return func(c *Client) error {
+		c.RequestEditors = append(c.RequestEditors, fn)
+		return nil
+	}
+}
+
+// The interface specification for the client above.
+type ClientInterface interface {
+	// PostCompose request with any body
+	PostComposeWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	PostCompose(ctx context.Context, body PostComposeJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetComposeList request
+	GetComposeList(ctx context.Context, params *GetComposeListParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// DeleteCompose request
+	DeleteCompose(ctx context.Context, id string, params *DeleteComposeParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetComposeStatus request
+	GetComposeStatus(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetComposeAdvisories request
+	GetComposeAdvisories(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// PostComposeCancel request
+	PostComposeCancel(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetComposeDiff request
+	GetComposeDiff(ctx context.Context, id string, otherId string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetComposeEvents request
+	GetComposeEvents(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetComposeLogs request
+	GetComposeLogs(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetComposeManifests request
+	GetComposeManifests(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetComposeMetadata request
+	GetComposeMetadata(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetComposeNotifications request
+	GetComposeNotifications(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// PostComposeRetry request
+	PostComposeRetry(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetComposeSBOM request
+	GetComposeSBOM(ctx context.Context, id string, params *GetComposeSBOMParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetErrorList request
+	GetErrorList(ctx context.Context, params *GetErrorListParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetError request
+	GetError(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetOpenapi request
+	GetOpenapi(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// PostPackagesExplain request with any body
+	PostPackagesExplainWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	PostPackagesExplain(ctx context.Context, body PostPackagesExplainJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// PostRepositoriesHealth request with any body
+	PostRepositoriesHealthWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	PostRepositoriesHealth(ctx context.Context, body PostRepositoriesHealthJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+}
+
+func (c *Client) PostComposeWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostComposeRequestWithBody(c.Server, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostCompose(ctx context.Context, body PostComposeJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostComposeRequest(c.Server, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetComposeList(ctx context.Context, params *GetComposeListParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetComposeListRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) DeleteCompose(ctx context.Context, id string, params *DeleteComposeParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewDeleteComposeRequest(c.Server, id, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetComposeStatus(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetComposeStatusRequest(c.Server, id)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetComposeAdvisories(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetComposeAdvisoriesRequest(c.Server, id)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostComposeCancel(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostComposeCancelRequest(c.Server, id)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetComposeDiff(ctx context.Context, id string, otherId string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetComposeDiffRequest(c.Server, id, otherId)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetComposeEvents(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetComposeEventsRequest(c.Server, id)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetComposeLogs(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetComposeLogsRequest(c.Server, id)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetComposeManifests(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetComposeManifestsRequest(c.Server, id)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetComposeMetadata(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetComposeMetadataRequest(c.Server, id)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetComposeNotifications(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetComposeNotificationsRequest(c.Server, id)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostComposeRetry(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostComposeRetryRequest(c.Server, id)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetComposeSBOM(ctx context.Context, id string, params *GetComposeSBOMParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetComposeSBOMRequest(c.Server, id, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetErrorList(ctx context.Context, params *GetErrorListParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetErrorListRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetError(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetErrorRequest(c.Server, id)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetOpenapi(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetOpenapiRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostPackagesExplainWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostPackagesExplainRequestWithBody(c.Server, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostPackagesExplain(ctx context.Context, body PostPackagesExplainJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostPackagesExplainRequest(c.Server, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostRepositoriesHealthWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostRepositoriesHealthRequestWithBody(c.Server, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostRepositoriesHealth(ctx context.Context, body PostRepositoriesHealthJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostRepositoriesHealthRequest(c.Server, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+// NewPostComposeRequest calls the generic PostCompose builder with application/json body
+func NewPostComposeRequest(server string, body PostComposeJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewPostComposeRequestWithBody(server, "application/json", bodyReader)
+}
+
+// NewPostComposeRequestWithBody generates requests for PostCompose with any type of body
+func NewPostComposeRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/compose")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewGetComposeListRequest generates requests for GetComposeList
+func NewGetComposeListRequest(server string, params *GetComposeListParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/composes")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	queryValues := queryURL.Query()
+
+	if params.Page != nil {
+
+		if queryFrag, err := runtime.StyleParamWithLocation("form", true, "page", runtime.ParamLocationQuery, *params.Page); err != nil {
+			return nil, err
+		} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+			return nil, err
+		} else {
+			for k, v := range parsed {
+				for _, v2 := range v {
+					queryValues.Add(k, v2)
+				}
+			}
+		}
+
+	}
+
+	if params.Size != nil {
+
+		if queryFrag, err := runtime.StyleParamWithLocation("form", true, "size", runtime.ParamLocationQuery, *params.Size); err != nil {
+			return nil, err
+		} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+			return nil, err
+		} else {
+			for k, v := range parsed {
+				for _, v2 := range v {
+					queryValues.Add(k, v2)
+				}
+			}
+		}
+
+	}
+
+	if params.Status != nil {
+
+		if queryFrag, err := runtime.StyleParamWithLocation("form", true, "status", runtime.ParamLocationQuery, *params.Status); err != nil {
+			return nil, err
+		} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+			return nil, err
+		} else {
+			for k, v := range parsed {
+				for _, v2 := range v {
+					queryValues.Add(k, v2)
+				}
+			}
+		}
+
+	}
+
+	if params.ImageType != nil {
+
+		if queryFrag, err := runtime.StyleParamWithLocation("form", true, "image_type", runtime.ParamLocationQuery, *params.ImageType); err != nil {
+			return nil, err
+		} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+			return nil, err
+		} else {
+			for k, v := range parsed {
+				for _, v2 := range v {
+					queryValues.Add(k, v2)
+				}
+			}
+		}
+
+	}
+
+	if params.Distribution != nil {
+
+		if queryFrag, err := runtime.StyleParamWithLocation("form", true, "distribution", runtime.ParamLocationQuery, *params.Distribution); err != nil {
+			return nil, err
+		} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+			return nil, err
+		} else {
+			for k, v := range parsed {
+				for _, v2 := range v {
+					queryValues.Add(k, v2)
+				}
+			}
+		}
+
+	}
+
+	if params.CreatedAfter != nil {
+
+		if queryFrag, err := runtime.StyleParamWithLocation("form", true, "created_after", runtime.ParamLocationQuery, *params.CreatedAfter); err != nil {
+			return nil, err
+		} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+			return nil, err
+		} else {
+			for k, v := range parsed {
+				for _, v2 := range v {
+					queryValues.Add(k, v2)
+				}
+			}
+		}
+
+	}
+
+	if params.CreatedBefore != nil {
+
+		if queryFrag, err := runtime.StyleParamWithLocation("form", true, "created_before", runtime.ParamLocationQuery, *params.CreatedBefore); err != nil {
+			return nil, err
+		} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+			return nil, err
+		} else {
+			for k, v := range parsed {
+				for _, v2 := range v {
+					queryValues.Add(k, v2)
+				}
+			}
+		}
+
+	}
+
+	queryURL.RawQuery = queryValues.Encode()
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewDeleteComposeRequest generates requests for DeleteCompose
+func NewDeleteComposeRequest(server string, id string, params *DeleteComposeParams) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/composes/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	queryValues := queryURL.Query()
+
+	if params.DeleteImages != nil {
+
+		if queryFrag, err := runtime.StyleParamWithLocation("form", true, "delete_images", runtime.ParamLocationQuery, *params.DeleteImages); err != nil {
+			return nil, err
+		} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+			return nil, err
+		} else {
+			for k, v := range parsed {
+				for _, v2 := range v {
+					queryValues.Add(k, v2)
+				}
+			}
+		}
+
+	}
+
+	queryURL.RawQuery = queryValues.Encode()
+
+	req, err := http.NewRequest("DELETE", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetComposeStatusRequest generates requests for GetComposeStatus
+func NewGetComposeStatusRequest(server string, id string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/composes/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetComposeAdvisoriesRequest generates requests for GetComposeAdvisories
+func NewGetComposeAdvisoriesRequest(server string, id string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/composes/%s/advisories", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewPostComposeCancelRequest generates requests for PostComposeCancel
+func NewPostComposeCancelRequest(server string, id string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/composes/%s/cancel", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetComposeDiffRequest generates requests for GetComposeDiff
+func NewGetComposeDiffRequest(server string, id string, otherId string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var pathParam1 string
+
+	pathParam1, err = runtime.StyleParamWithLocation("simple", false, "other_id", runtime.ParamLocationPath, otherId)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/composes/%s/diff/%s", pathParam0, pathParam1)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetComposeEventsRequest generates requests for GetComposeEvents
+func NewGetComposeEventsRequest(server string, id string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/composes/%s/events", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetComposeLogsRequest generates requests for GetComposeLogs
+func NewGetComposeLogsRequest(server string, id string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/composes/%s/logs", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetComposeManifestsRequest generates requests for GetComposeManifests
+func NewGetComposeManifestsRequest(server string, id string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/composes/%s/manifests", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetComposeMetadataRequest generates requests for GetComposeMetadata
+func NewGetComposeMetadataRequest(server string, id string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/composes/%s/metadata", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetComposeNotificationsRequest generates requests for GetComposeNotifications
+func NewGetComposeNotificationsRequest(server string, id string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/composes/%s/notifications", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewPostComposeRetryRequest generates requests for PostComposeRetry
+func NewPostComposeRetryRequest(server string, id string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/composes/%s/retry", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetComposeSBOMRequest generates requests for GetComposeSBOM
+func NewGetComposeSBOMRequest(server string, id string, params *GetComposeSBOMParams) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/composes/%s/sbom", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	queryValues := queryURL.Query()
+
+	if params.Format != nil {
+
+		if queryFrag, err := runtime.StyleParamWithLocation("form", true, "format", runtime.ParamLocationQuery, *params.Format); err != nil {
+			return nil, err
+		} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+			return nil, err
+		} else {
+			for k, v := range parsed {
+				for _, v2 := range v {
+					queryValues.Add(k, v2)
+				}
+			}
+		}
+
+	}
+
+	queryURL.RawQuery = queryValues.Encode()
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetErrorListRequest generates requests for GetErrorList
+func NewGetErrorListRequest(server string, params *GetErrorListParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/errors")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	queryValues := queryURL.Query()
+
+	if params.Page != nil {
+
+		if queryFrag, err := runtime.StyleParamWithLocation("form", true, "page", runtime.ParamLocationQuery, *params.Page); err != nil {
+			return nil, err
+		} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+			return nil, err
+		} else {
+			for k, v := range parsed {
+				for _, v2 := range v {
+					queryValues.Add(k, v2)
+				}
+			}
+		}
+
+	}
+
+	if params.Size != nil {
+
+		if queryFrag, err := runtime.StyleParamWithLocation("form", true, "size", runtime.ParamLocationQuery, *params.Size); err != nil {
+			return nil, err
+		} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+			return nil, err
+		} else {
+			for k, v := range parsed {
+				for _, v2 := range v {
+					queryValues.Add(k, v2)
+				}
+			}
+		}
+
+	}
+
+	queryURL.RawQuery = queryValues.Encode()
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetErrorRequest generates requests for GetError
+func NewGetErrorRequest(server string, id string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/errors/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetOpenapiRequest generates requests for GetOpenapi
+func NewGetOpenapiRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/openapi")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewPostPackagesExplainRequest calls the generic PostPackagesExplain builder with application/json body
+func NewPostPackagesExplainRequest(server string, body PostPackagesExplainJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewPostPackagesExplainRequestWithBody(server, "application/json", bodyReader)
+}
+
+// NewPostPackagesExplainRequestWithBody generates requests for PostPackagesExplain with any type of body
+func NewPostPackagesExplainRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/packages/explain")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewPostRepositoriesHealthRequest calls the generic PostRepositoriesHealth builder with application/json body
+func NewPostRepositoriesHealthRequest(server string, body PostRepositoriesHealthJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewPostRepositoriesHealthRequestWithBody(server, "application/json", bodyReader)
+}
+
+// NewPostRepositoriesHealthRequestWithBody generates requests for PostRepositoriesHealth with any type of body
+func NewPostRepositoriesHealthRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/repositories/health")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+func (c *Client) applyEditors(ctx context.Context, req *http.Request, additionalEditors []RequestEditorFn) error {
+	for _, r := range c.RequestEditors {
+		if err := r(ctx, req); err != nil {
+			return err
+		}
+	}
+	for _, r := range additionalEditors {
+		if err := r(ctx, req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ClientWithResponses builds on ClientInterface to offer response payloads
+type ClientWithResponses struct {
+	ClientInterface
+}
+
+// NewClientWithResponses creates a new ClientWithResponses, which wraps
+// Client with return type handling
+func NewClientWithResponses(server string, opts ...ClientOption) (*ClientWithResponses, error) {
+	client, err := NewClient(server, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &ClientWithResponses{client}, nil
+}
+
+// WithBaseURL overrides the baseURL.
+func WithBaseURL(baseURL string) ClientOption {
+	return func(c *Client) error {
+		newBaseURL, err := url.Parse(baseURL)
+		if err != nil {
+			return err
+		}
+		c.Server = newBaseURL.String()
+		return nil
+	}
+}
+
+// ClientWithResponsesInterface is the interface specification for the client with responses above.
+type ClientWithResponsesInterface interface {
+	// PostCompose request with any body
+	PostComposeWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostComposeResponse, error)
+
+	PostComposeWithResponse(ctx context.Context, body PostComposeJSONRequestBody, reqEditors ...RequestEditorFn) (*PostComposeResponse, error)
+
+	// GetComposeList request
+	GetComposeListWithResponse(ctx context.Context, params *GetComposeListParams, reqEditors ...RequestEditorFn) (*GetComposeListResponse, error)
+
+	// DeleteCompose request
+	DeleteComposeWithResponse(ctx context.Context, id string, params *DeleteComposeParams, reqEditors ...RequestEditorFn) (*DeleteComposeResponse, error)
+
+	// GetComposeStatus request
+	GetComposeStatusWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*GetComposeStatusResponse, error)
+
+	// GetComposeAdvisories request
+	GetComposeAdvisoriesWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*GetComposeAdvisoriesResponse, error)
+
+	// PostComposeCancel request
+	PostComposeCancelWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*PostComposeCancelResponse, error)
+
+	// GetComposeDiff request
+	GetComposeDiffWithResponse(ctx context.Context, id string, otherId string, reqEditors ...RequestEditorFn) (*GetComposeDiffResponse, error)
+
+	// GetComposeEvents request
+	GetComposeEventsWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*GetComposeEventsResponse, error)
+
+	// GetComposeLogs request
+	GetComposeLogsWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*GetComposeLogsResponse, error)
+
+	// GetComposeManifests request
+	GetComposeManifestsWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*GetComposeManifestsResponse, error)
+
+	// GetComposeMetadata request
+	GetComposeMetadataWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*GetComposeMetadataResponse, error)
+
+	// GetComposeNotifications request
+	GetComposeNotificationsWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*GetComposeNotificationsResponse, error)
+
+	// PostComposeRetry request
+	PostComposeRetryWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*PostComposeRetryResponse, error)
+
+	// GetComposeSBOM request
+	GetComposeSBOMWithResponse(ctx context.Context, id string, params *GetComposeSBOMParams, reqEditors ...RequestEditorFn) (*GetComposeSBOMResponse, error)
+
+	// GetErrorList request
+	GetErrorListWithResponse(ctx context.Context, params *GetErrorListParams, reqEditors ...RequestEditorFn) (*GetErrorListResponse, error)
+
+	// GetError request
+	GetErrorWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*GetErrorResponse, error)
+
+	// GetOpenapi request
+	GetOpenapiWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetOpenapiResponse, error)
+
+	// PostPackagesExplain request with any body
+	PostPackagesExplainWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostPackagesExplainResponse, error)
+
+	PostPackagesExplainWithResponse(ctx context.Context, body PostPackagesExplainJSONRequestBody, reqEditors ...RequestEditorFn) (*PostPackagesExplainResponse, error)
+
+	// PostRepositoriesHealth request with any body
+	PostRepositoriesHealthWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostRepositoriesHealthResponse, error)
+
+	PostRepositoriesHealthWithResponse(ctx context.Context, body PostRepositoriesHealthJSONRequestBody, reqEditors ...RequestEditorFn) (*PostRepositoriesHealthResponse, error)
+}
+
+type PostComposeResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON201      *ComposeId
+	JSON400      *Error
+	JSON401      *Error
+	JSON403      *Error
+	JSON404      *Error
+	JSON429      *Error
+	JSON500      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r PostComposeResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r PostComposeResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetComposeListResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *ComposeList
+	JSON400      *Error
+	JSON401      *Error
+	JSON403      *Error
+	JSON500      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetComposeListResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetComposeListResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type DeleteComposeResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON400      *Error
+	JSON401      *Error
+	JSON403      *Error
+	JSON404      *Error
+	JSON409      *Error
+	JSON500      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r DeleteComposeResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r DeleteComposeResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetComposeStatusResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *ComposeStatus
+	JSON400      *Error
+	JSON401      *Error
+	JSON403      *Error
+	JSON404      *Error
+	JSON500      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetComposeStatusResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetComposeStatusResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetComposeAdvisoriesResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *ComposeAdvisories
+	JSON400      *Error
+	JSON401      *Error
+	JSON403      *Error
+	JSON404      *Error
+	JSON500      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetComposeAdvisoriesResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetComposeAdvisoriesResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type PostComposeCancelResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *ComposeStatus
+	JSON400      *Error
+	JSON401      *Error
+	JSON403      *Error
+	JSON404      *Error
+	JSON500      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r PostComposeCancelResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r PostComposeCancelResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetComposeDiffResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *ComposeDiff
+	JSON400      *Error
+	JSON401      *Error
+	JSON403      *Error
+	JSON404      *Error
+	JSON500      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetComposeDiffResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetComposeDiffResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetComposeEventsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON400      *Error
+	JSON401      *Error
+	JSON403      *Error
+	JSON404      *Error
+	JSON500      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetComposeEventsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetComposeEventsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetComposeLogsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *ComposeLogs
+}
+
+// Status returns HTTPResponse.Status
+func (r GetComposeLogsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetComposeLogsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetComposeManifestsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *ComposeManifests
+}
+
+// Status returns HTTPResponse.Status
+func (r GetComposeManifestsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetComposeManifestsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetComposeMetadataResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *ComposeMetadata
+	JSON400      *Error
+	JSON401      *Error
+	JSON403      *Error
+	JSON404      *Error
+	JSON500      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetComposeMetadataResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetComposeMetadataResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetComposeNotificationsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *ComposeNotifications
+	JSON400      *Error
+	JSON401      *Error
+	JSON403      *Error
+	JSON404      *Error
+	JSON500      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetComposeNotificationsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetComposeNotificationsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type PostComposeRetryResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *ComposeStatus
+	JSON400      *Error
+	JSON401      *Error
+	JSON403      *Error
+	JSON404      *Error
+	JSON409      *Error
+	JSON500      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r PostComposeRetryResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r PostComposeRetryResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetComposeSBOMResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *map[string]interface{}
+	JSON400      *Error
+	JSON401      *Error
+	JSON403      *Error
+	JSON404      *Error
+	JSON500      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetComposeSBOMResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetComposeSBOMResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetErrorListResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *ErrorList
+	JSON401      *Error
+	JSON403      *Error
+	JSON404      *Error
+	JSON500      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetErrorListResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetErrorListResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetErrorResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *Error
+	JSON401      *Error
+	JSON403      *Error
+	JSON404      *Error
+	JSON500      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetErrorResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetErrorResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetOpenapiResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON500      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetOpenapiResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetOpenapiResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type PostPackagesExplainResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *PackageExplanationList
+	JSON400      *Error
+	JSON401      *Error
+	JSON403      *Error
+	JSON500      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r PostPackagesExplainResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r PostPackagesExplainResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type PostRepositoriesHealthResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *RepositoryHealthList
+	JSON400      *Error
+	JSON401      *Error
+	JSON403      *Error
+	JSON500      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r PostRepositoriesHealthResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r PostRepositoriesHealthResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+// PostComposeWithBodyWithResponse request with arbitrary body returning *PostComposeResponse
+func (c *ClientWithResponses) PostComposeWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostComposeResponse, error) {
+	rsp, err := c.PostComposeWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostComposeResponse(rsp)
+}
+
+func (c *ClientWithResponses) PostComposeWithResponse(ctx context.Context, body PostComposeJSONRequestBody, reqEditors ...RequestEditorFn) (*PostComposeResponse, error) {
+	rsp, err := c.PostCompose(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostComposeResponse(rsp)
+}
+
+// GetComposeListWithResponse request returning *GetComposeListResponse
+func (c *ClientWithResponses) GetComposeListWithResponse(ctx context.Context, params *GetComposeListParams, reqEditors ...RequestEditorFn) (*GetComposeListResponse, error) {
+	rsp, err := c.GetComposeList(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetComposeListResponse(rsp)
+}
+
+// DeleteComposeWithResponse request returning *DeleteComposeResponse
+func (c *ClientWithResponses) DeleteComposeWithResponse(ctx context.Context, id string, params *DeleteComposeParams, reqEditors ...RequestEditorFn) (*DeleteComposeResponse, error) {
+	rsp, err := c.DeleteCompose(ctx, id, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDeleteComposeResponse(rsp)
+}
+
+// GetComposeStatusWithResponse request returning *GetComposeStatusResponse
+func (c *ClientWithResponses) GetComposeStatusWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*GetComposeStatusResponse, error) {
+	rsp, err := c.GetComposeStatus(ctx, id, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetComposeStatusResponse(rsp)
+}
+
+// GetComposeAdvisoriesWithResponse request returning *GetComposeAdvisoriesResponse
+func (c *ClientWithResponses) GetComposeAdvisoriesWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*GetComposeAdvisoriesResponse, error) {
+	rsp, err := c.GetComposeAdvisories(ctx, id, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetComposeAdvisoriesResponse(rsp)
+}
+
+// PostComposeCancelWithResponse request returning *PostComposeCancelResponse
+func (c *ClientWithResponses) PostComposeCancelWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*PostComposeCancelResponse, error) {
+	rsp, err := c.PostComposeCancel(ctx, id, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostComposeCancelResponse(rsp)
+}
+
+// GetComposeDiffWithResponse request returning *GetComposeDiffResponse
+func (c *ClientWithResponses) GetComposeDiffWithResponse(ctx context.Context, id string, otherId string, reqEditors ...RequestEditorFn) (*GetComposeDiffResponse, error) {
+	rsp, err := c.GetComposeDiff(ctx, id, otherId, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetComposeDiffResponse(rsp)
+}
+
+// GetComposeEventsWithResponse request returning *GetComposeEventsResponse
+func (c *ClientWithResponses) GetComposeEventsWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*GetComposeEventsResponse, error) {
+	rsp, err := c.GetComposeEvents(ctx, id, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetComposeEventsResponse(rsp)
+}
+
+// GetComposeLogsWithResponse request returning *GetComposeLogsResponse
+func (c *ClientWithResponses) GetComposeLogsWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*GetComposeLogsResponse, error) {
+	rsp, err := c.GetComposeLogs(ctx, id, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetComposeLogsResponse(rsp)
+}
+
+// GetComposeManifestsWithResponse request returning *GetComposeManifestsResponse
+func (c *ClientWithResponses) GetComposeManifestsWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*GetComposeManifestsResponse, error) {
+	rsp, err := c.GetComposeManifests(ctx, id, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetComposeManifestsResponse(rsp)
+}
+
+// GetComposeMetadataWithResponse request returning *GetComposeMetadataResponse
+func (c *ClientWithResponses) GetComposeMetadataWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*GetComposeMetadataResponse, error) {
+	rsp, err := c.GetComposeMetadata(ctx, id, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetComposeMetadataResponse(rsp)
+}
+
+// GetComposeNotificationsWithResponse request returning *GetComposeNotificationsResponse
+func (c *ClientWithResponses) GetComposeNotificationsWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*GetComposeNotificationsResponse, error) {
+	rsp, err := c.GetComposeNotifications(ctx, id, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetComposeNotificationsResponse(rsp)
+}
+
+// PostComposeRetryWithResponse request returning *PostComposeRetryResponse
+func (c *ClientWithResponses) PostComposeRetryWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*PostComposeRetryResponse, error) {
+	rsp, err := c.PostComposeRetry(ctx, id, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostComposeRetryResponse(rsp)
+}
+
+// GetComposeSBOMWithResponse request returning *GetComposeSBOMResponse
+func (c *ClientWithResponses) GetComposeSBOMWithResponse(ctx context.Context, id string, params *GetComposeSBOMParams, reqEditors ...RequestEditorFn) (*GetComposeSBOMResponse, error) {
+	rsp, err := c.GetComposeSBOM(ctx, id, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetComposeSBOMResponse(rsp)
+}
+
+// GetErrorListWithResponse request returning *GetErrorListResponse
+func (c *ClientWithResponses) GetErrorListWithResponse(ctx context.Context, params *GetErrorListParams, reqEditors ...RequestEditorFn) (*GetErrorListResponse, error) {
+	rsp, err := c.GetErrorList(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetErrorListResponse(rsp)
+}
+
+// GetErrorWithResponse request returning *GetErrorResponse
+func (c *ClientWithResponses) GetErrorWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*GetErrorResponse, error) {
+	rsp, err := c.GetError(ctx, id, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetErrorResponse(rsp)
+}
+
+// GetOpenapiWithResponse request returning *GetOpenapiResponse
+func (c *ClientWithResponses) GetOpenapiWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetOpenapiResponse, error) {
+	rsp, err := c.GetOpenapi(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetOpenapiResponse(rsp)
+}
+
+// PostPackagesExplainWithBodyWithResponse request with arbitrary body returning *PostPackagesExplainResponse
+func (c *ClientWithResponses) PostPackagesExplainWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostPackagesExplainResponse, error) {
+	rsp, err := c.PostPackagesExplainWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostPackagesExplainResponse(rsp)
+}
+
+func (c *ClientWithResponses) PostPackagesExplainWithResponse(ctx context.Context, body PostPackagesExplainJSONRequestBody, reqEditors ...RequestEditorFn) (*PostPackagesExplainResponse, error) {
+	rsp, err := c.PostPackagesExplain(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostPackagesExplainResponse(rsp)
+}
+
+// PostRepositoriesHealthWithBodyWithResponse request with arbitrary body returning *PostRepositoriesHealthResponse
+func (c *ClientWithResponses) PostRepositoriesHealthWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostRepositoriesHealthResponse, error) {
+	rsp, err := c.PostRepositoriesHealthWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostRepositoriesHealthResponse(rsp)
+}
+
+func (c *ClientWithResponses) PostRepositoriesHealthWithResponse(ctx context.Context, body PostRepositoriesHealthJSONRequestBody, reqEditors ...RequestEditorFn) (*PostRepositoriesHealthResponse, error) {
+	rsp, err := c.PostRepositoriesHealth(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostRepositoriesHealthResponse(rsp)
+}
+
+// ParsePostComposeResponse parses an HTTP response from a PostComposeWithResponse call
+func ParsePostComposeResponse(rsp *http.Response) (*PostComposeResponse, error) {
+	bodyBytes, err := ioutil.ReadAll(rsp.Body)
+	defer rsp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &PostComposeResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 201:
+		var dest ComposeId
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON201 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 429:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON429 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetComposeListResponse parses an HTTP response from a GetComposeListWithResponse call
+func ParseGetComposeListResponse(rsp *http.Response) (*GetComposeListResponse, error) {
+	bodyBytes, err := ioutil.ReadAll(rsp.Body)
+	defer rsp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetComposeListResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest ComposeList
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseDeleteComposeResponse parses an HTTP response from a DeleteComposeWithResponse call
+func ParseDeleteComposeResponse(rsp *http.Response) (*DeleteComposeResponse, error) {
+	bodyBytes, err := ioutil.ReadAll(rsp.Body)
+	defer rsp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &DeleteComposeResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 409:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON409 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetComposeStatusResponse parses an HTTP response from a GetComposeStatusWithResponse call
+func ParseGetComposeStatusResponse(rsp *http.Response) (*GetComposeStatusResponse, error) {
+	bodyBytes, err := ioutil.ReadAll(rsp.Body)
+	defer rsp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetComposeStatusResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest ComposeStatus
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetComposeAdvisoriesResponse parses an HTTP response from a GetComposeAdvisoriesWithResponse call
+func ParseGetComposeAdvisoriesResponse(rsp *http.Response) (*GetComposeAdvisoriesResponse, error) {
+	bodyBytes, err := ioutil.ReadAll(rsp.Body)
+	defer rsp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetComposeAdvisoriesResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest ComposeAdvisories
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParsePostComposeCancelResponse parses an HTTP response from a PostComposeCancelWithResponse call
+func ParsePostComposeCancelResponse(rsp *http.Response) (*PostComposeCancelResponse, error) {
+	bodyBytes, err := ioutil.ReadAll(rsp.Body)
+	defer rsp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &PostComposeCancelResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest ComposeStatus
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetComposeDiffResponse parses an HTTP response from a GetComposeDiffWithResponse call
+func ParseGetComposeDiffResponse(rsp *http.Response) (*GetComposeDiffResponse, error) {
+	bodyBytes, err := ioutil.ReadAll(rsp.Body)
+	defer rsp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetComposeDiffResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest ComposeDiff
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetComposeEventsResponse parses an HTTP response from a GetComposeEventsWithResponse call
+func ParseGetComposeEventsResponse(rsp *http.Response) (*GetComposeEventsResponse, error) {
+	bodyBytes, err := ioutil.ReadAll(rsp.Body)
+	defer rsp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetComposeEventsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetComposeLogsResponse parses an HTTP response from a GetComposeLogsWithResponse call
+func ParseGetComposeLogsResponse(rsp *http.Response) (*GetComposeLogsResponse, error) {
+	bodyBytes, err := ioutil.ReadAll(rsp.Body)
+	defer rsp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetComposeLogsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest ComposeLogs
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetComposeManifestsResponse parses an HTTP response from a GetComposeManifestsWithResponse call
+func ParseGetComposeManifestsResponse(rsp *http.Response) (*GetComposeManifestsResponse, error) {
+	bodyBytes, err := ioutil.ReadAll(rsp.Body)
+	defer rsp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetComposeManifestsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest ComposeManifests
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetComposeMetadataResponse parses an HTTP response from a GetComposeMetadataWithResponse call
+func ParseGetComposeMetadataResponse(rsp *http.Response) (*GetComposeMetadataResponse, error) {
+	bodyBytes, err := ioutil.ReadAll(rsp.Body)
+	defer rsp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetComposeMetadataResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest ComposeMetadata
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetComposeNotificationsResponse parses an HTTP response from a GetComposeNotificationsWithResponse call
+func ParseGetComposeNotificationsResponse(rsp *http.Response) (*GetComposeNotificationsResponse, error) {
+	bodyBytes, err := ioutil.ReadAll(rsp.Body)
+	defer rsp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetComposeNotificationsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest ComposeNotifications
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParsePostComposeRetryResponse parses an HTTP response from a PostComposeRetryWithResponse call
+func ParsePostComposeRetryResponse(rsp *http.Response) (*PostComposeRetryResponse, error) {
+	bodyBytes, err := ioutil.ReadAll(rsp.Body)
+	defer rsp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &PostComposeRetryResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest ComposeStatus
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 409:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON409 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetComposeSBOMResponse parses an HTTP response from a GetComposeSBOMWithResponse call
+func ParseGetComposeSBOMResponse(rsp *http.Response) (*GetComposeSBOMResponse, error) {
+	bodyBytes, err := ioutil.ReadAll(rsp.Body)
+	defer rsp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetComposeSBOMResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest map[string]interface{}
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetErrorListResponse parses an HTTP response from a GetErrorListWithResponse call
+func ParseGetErrorListResponse(rsp *http.Response) (*GetErrorListResponse, error) {
+	bodyBytes, err := ioutil.ReadAll(rsp.Body)
+	defer rsp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetErrorListResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest ErrorList
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetErrorResponse parses an HTTP response from a GetErrorWithResponse call
+func ParseGetErrorResponse(rsp *http.Response) (*GetErrorResponse, error) {
+	bodyBytes, err := ioutil.ReadAll(rsp.Body)
+	defer rsp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetErrorResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetOpenapiResponse parses an HTTP response from a GetOpenapiWithResponse call
+func ParseGetOpenapiResponse(rsp *http.Response) (*GetOpenapiResponse, error) {
+	bodyBytes, err := ioutil.ReadAll(rsp.Body)
+	defer rsp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetOpenapiResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParsePostPackagesExplainResponse parses an HTTP response from a PostPackagesExplainWithResponse call
+func ParsePostPackagesExplainResponse(rsp *http.Response) (*PostPackagesExplainResponse, error) {
+	bodyBytes, err := ioutil.ReadAll(rsp.Body)
+	defer rsp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &PostPackagesExplainResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest PackageExplanationList
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParsePostRepositoriesHealthResponse parses an HTTP response from a PostRepositoriesHealthWithResponse call
+func ParsePostRepositoriesHealthResponse(rsp *http.Response) (*PostRepositoriesHealthResponse, error) {
+	bodyBytes, err := ioutil.ReadAll(rsp.Body)
+	defer rsp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &PostRepositoriesHealthResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest RepositoryHealthList
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
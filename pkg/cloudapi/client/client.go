@@ -0,0 +1,216 @@
+//go:generate go run github.com/deepmap/oapi-codegen/cmd/oapi-codegen -package=client -generate types,client -o client.gen.go ../../../internal/cloudapi/v2/openapi.v2.yml
+
+// Package client is a client for osbuild-composer's cloud API (v2).
+//
+// The types and the request functions are generated from the same OpenAPI
+// specification as the server. This file adds authentication with OAuth
+// offline tokens and helpers for waiting on composes.
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The client id used to refresh access tokens when none is set explicitly.
+const DefaultOAuthClientID = "rhsm-api"
+
+type bearerToken struct {
+	AccessToken     string `json:"access_token"`
+	ValidForSeconds int    `json:"expires_in"`
+}
+
+// tokenRefresher exchanges an offline token for access tokens and caches
+// them until 80% of their lifetime passed.
+type tokenRefresher struct {
+	client       *Client
+	offlineToken string
+	oAuthURL     string
+	clientID     string
+
+	mu          sync.Mutex
+	token       *bearerToken
+	lastRefresh time.Time
+}
+
+func (t *tokenRefresher) accessToken(ctx context.Context) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.token != nil && time.Since(t.lastRefresh).Seconds() < float64(t.token.ValidForSeconds)*0.8 {
+		return t.token.AccessToken, nil
+	}
+
+	data := url.Values{}
+	data.Set("grant_type", "refresh_token")
+	data.Set("client_id", t.clientID)
+	data.Set("refresh_token", t.offlineToken)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.oAuthURL, strings.NewReader(data.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	now := time.Now()
+	resp, err := t.client.Client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error refreshing access token: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("error refreshing access token: %s", resp.Status)
+	}
+
+	var token bearerToken
+	err = json.NewDecoder(resp.Body).Decode(&token)
+	if err != nil {
+		return "", fmt.Errorf("error decoding access token: %v", err)
+	}
+	if token.AccessToken == "" {
+		return "", fmt.Errorf("the OAuth server did not return an access token")
+	}
+
+	t.token = &token
+	t.lastRefresh = now
+	return token.AccessToken, nil
+}
+
+// WithOfflineToken authenticates all requests with access tokens which are
+// obtained from the OAuth server at oAuthURL using offlineToken. This is the
+// same scheme the worker uses with its `authentication` configuration. An
+// empty clientID selects DefaultOAuthClientID.
+//
+// The token requests are sent with the client's HTTP client, so that they
+// use the same TLS configuration as the requests to the API.
+func WithOfflineToken(offlineToken, oAuthURL, clientID string) ClientOption {
+	return func(c *Client) error {
+		if offlineToken == "" || oAuthURL == "" {
+			return fmt.Errorf("an offline token requires an OAuth URL and vice versa")
+		}
+		if clientID == "" {
+			clientID = DefaultOAuthClientID
+		}
+
+		t := &tokenRefresher{
+			client:       c,
+			offlineToken: offlineToken,
+			oAuthURL:     oAuthURL,
+			clientID:     clientID,
+		}
+		c.RequestEditors = append(c.RequestEditors, func(ctx context.Context, req *http.Request) error {
+			token, err := t.accessToken(ctx)
+			if err != nil {
+				return err
+			}
+			req.Header.Set("Authorization", "Bearer "+token)
+			return nil
+		})
+		return nil
+	}
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Reason)
+}
+
+// responseError returns the error returned by the API, or a generic one
+// built from the status when the body isn't a cloud API error.
+func responseError(resp *http.Response, body []byte) error {
+	var apiError Error
+	if json.Unmarshal(body, &apiError) == nil && apiError.Code != "" {
+		return &apiError
+	}
+	if resp == nil {
+		return fmt.Errorf("no response")
+	}
+	return fmt.Errorf("unexpected response: %s", resp.Status)
+}
+
+// isClientError returns true for 4xx status codes, except for
+// 429 Too Many Requests, which is worth retrying.
+func isClientError(statusCode int) bool {
+	return statusCode >= 400 && statusCode < 500 && statusCode != http.StatusTooManyRequests
+}
+
+// Compose submits a compose request and returns the id of the new compose.
+func (c *ClientWithResponses) Compose(ctx context.Context, request ComposeRequest) (string, error) {
+	resp, err := c.PostComposeWithResponse(ctx, PostComposeJSONRequestBody(request))
+	if err != nil {
+		return "", err
+	}
+	if resp.JSON201 == nil {
+		return "", responseError(resp.HTTPResponse, resp.Body)
+	}
+	return resp.JSON201.Id, nil
+}
+
+// ComposeStatus returns the status of the compose with the given id.
+func (c *ClientWithResponses) ComposeStatus(ctx context.Context, id string) (*ComposeStatus, error) {
+	resp, err := c.GetComposeStatusWithResponse(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if resp.JSON200 == nil {
+		return nil, responseError(resp.HTTPResponse, resp.Body)
+	}
+	return resp.JSON200, nil
+}
+
+// WaitForCompose polls the status of the compose with the given id every
+// interval until it isn't pending anymore, and returns the final status.
+// Failed requests and responses other than client errors (4xx) are
+// transient and retried at the next interval, client errors are returned
+// right away.
+func (c *ClientWithResponses) WaitForCompose(ctx context.Context, id string, interval time.Duration) (*ComposeStatus, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		resp, err := c.GetComposeStatusWithResponse(ctx, id)
+		if err == nil && resp.JSON200 != nil {
+			if resp.JSON200.Status != ComposeStatusValuePending {
+				return resp.JSON200, nil
+			}
+		} else if err == nil && isClientError(resp.StatusCode()) {
+			return nil, responseError(resp.HTTPResponse, resp.Body)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// ComposeLogs returns the logs of the compose with the given id.
+func (c *ClientWithResponses) ComposeLogs(ctx context.Context, id string) (*ComposeLogs, error) {
+	resp, err := c.GetComposeLogsWithResponse(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if resp.JSON200 == nil {
+		return nil, responseError(resp.HTTPResponse, resp.Body)
+	}
+	return resp.JSON200, nil
+}
+
+// ComposeManifests returns the manifests of the compose with the given id.
+func (c *ClientWithResponses) ComposeManifests(ctx context.Context, id string) (*ComposeManifests, error) {
+	resp, err := c.GetComposeManifestsWithResponse(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if resp.JSON200 == nil {
+		return nil, responseError(resp.HTTPResponse, resp.Body)
+	}
+	return resp.JSON200, nil
+}
@@ -0,0 +1,101 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newOAuthServer returns an OAuth server which hands out access tokens for
+// the offline token "offline", valid for expiresIn seconds.
+func newOAuthServer(t *testing.T, expiresIn int) (*httptest.Server, *int) {
+	var mu sync.Mutex
+	refreshes := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		require.Equal(t, "refresh_token", r.PostForm.Get("grant_type"))
+		require.Equal(t, DefaultOAuthClientID, r.PostForm.Get("client_id"))
+		if r.PostForm.Get("refresh_token") != "offline" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		mu.Lock()
+		refreshes++
+		token := fmt.Sprintf("access-%d", refreshes)
+		mu.Unlock()
+
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": token,
+			"expires_in":   expiresIn,
+		}))
+	}))
+	t.Cleanup(server.Close)
+	return server, &refreshes
+}
+
+// newAPIServer returns a server which answers compose status requests with
+// the Authorization header it received as the compose's id.
+func newAPIServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(ComposeStatus{
+			ObjectReference: ObjectReference{Id: r.Header.Get("Authorization"), Kind: "ComposeStatus"},
+			Status:          ComposeStatusValueSuccess,
+		}))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestOfflineToken(t *testing.T) {
+	oauth, refreshes := newOAuthServer(t, 300)
+	api := newAPIServer(t)
+
+	c, err := NewClientWithResponses(api.URL, WithOfflineToken("offline", oauth.URL, ""))
+	require.NoError(t, err)
+
+	// the access token is reused while it's valid
+	for i := 0; i < 3; i++ {
+		status, err := c.ComposeStatus(context.Background(), "id")
+		require.NoError(t, err)
+		require.Equal(t, "Bearer access-1", status.Id)
+	}
+	require.Equal(t, 1, *refreshes)
+}
+
+func TestOfflineTokenRefresh(t *testing.T) {
+	oauth, refreshes := newOAuthServer(t, 0)
+	api := newAPIServer(t)
+
+	c, err := NewClientWithResponses(api.URL, WithOfflineToken("offline", oauth.URL, ""))
+	require.NoError(t, err)
+
+	// expired access tokens are refreshed
+	for i := 1; i <= 3; i++ {
+		status, err := c.ComposeStatus(context.Background(), "id")
+		require.NoError(t, err)
+		require.Equal(t, fmt.Sprintf("Bearer access-%d", i), status.Id)
+	}
+	require.Equal(t, 3, *refreshes)
+}
+
+func TestOfflineTokenInvalid(t *testing.T) {
+	oauth, _ := newOAuthServer(t, 300)
+	api := newAPIServer(t)
+
+	c, err := NewClientWithResponses(api.URL, WithOfflineToken("invalid", oauth.URL, ""))
+	require.NoError(t, err)
+	_, err = c.ComposeStatus(context.Background(), "id")
+	require.Error(t, err)
+
+	_, err = NewClientWithResponses(api.URL, WithOfflineToken("offline", "", ""))
+	require.Error(t, err)
+}
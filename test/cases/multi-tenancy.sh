@@ -70,6 +70,11 @@ jwt_keys_urls = ["https://localhost:8082/certs"]
 jwt_ca_file = "/etc/osbuild-composer/ca-crt.pem"
 jwt_acl_file = ""
 jwt_tenant_provider_fields = ["rh-org-id"]
+[koji.jwt_roles]
+claims = ["groups"]
+viewer = ["viewers"]
+builder = ["builders"]
+admin = ["admins"]
 [koji.aws_config]
 bucket = "${AWS_BUCKET}"
 [worker]
@@ -154,8 +159,13 @@ EOF
 
 function access_token {
   local refresh_token="$1"
+  local groups=()
+  for group in "${@:2}"; do
+    groups+=(--data "groups=$group")
+  done
   curl --request POST \
     --data "refresh_token=$refresh_token" \
+    "${groups[@]}" \
     --header "Content-Type: application/x-www-form-urlencoded" \
     --silent \
     --show-error \
@@ -171,7 +181,7 @@ function send_compose {
     --show-error \
     --fail \
     --header 'Content-Type: application/json' \
-    --header "Authorization: Bearer $(access_token "$refresh_token")" \
+    --header "Authorization: Bearer $(access_token "$refresh_token" builders)" \
     --request POST \
     --data @"$request_file" \
     http://localhost:443/api/image-builder-composer/v2/compose | jq -r '.id'
@@ -184,10 +194,38 @@ function compose_status {
     --silent \
     --show-error \
     --fail \
-    --header "Authorization: Bearer $(access_token "$refresh_token")" \
+    --header "Authorization: Bearer $(access_token "$refresh_token" viewers)" \
     "http://localhost:443/api/image-builder-composer/v2/composes/$compose" | jq -r '.status'
 }
 
+# Prints the HTTP status code of a request to the cloud API, made with a token
+# in the given group.
+function http_status {
+  local method="$1"
+  local path="$2"
+  local group="$3"
+  curl \
+    --silent \
+    --show-error \
+    --output /dev/null \
+    --write-out '%{http_code}' \
+    --header 'Content-Type: application/json' \
+    --header "Authorization: Bearer $(access_token 42 "$group")" \
+    --request "$method" \
+    --data '{}' \
+    "http://localhost:443/api/image-builder-composer/v2$path"
+}
+
+function assert_http_status {
+  local expected="$4"
+  local actual
+  actual=$(http_status "$1" "$2" "$3")
+  if [[ "$actual" != "$expected" ]]; then
+    echo "$1 $2 as $3: expected HTTP status $expected, got $actual"
+    exit 1
+  fi
+}
+
 function assert_status {
   local compose="$1"
   local refresh_token="$2"
@@ -228,6 +266,14 @@ EOF
   sudo systemctl restart osbuild-remote-worker@localhost:8700
 }
 
+greenprint "Checking role-based access control"
+assert_http_status POST /compose nobody 403
+assert_http_status POST /compose viewers 403
+assert_http_status POST /compose builders 400
+assert_http_status GET /errors viewers 403
+assert_http_status GET /errors builders 403
+assert_http_status GET /errors admins 200
+
 ORG=42
 greenprint "Sending 1st compose, koji, org id = $ORG"
 koji --server=http://localhost:8080/kojihub --user kojiadmin --password kojipass --authtype=password make-task image